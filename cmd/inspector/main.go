@@ -23,6 +23,7 @@ import (
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
 	"storj.io/private/process"
+	"storj.io/storj/pkg/inspectorpb"
 	"storj.io/storj/private/prompt"
 	_ "storj.io/storj/private/version" // This attaches version information during release builds.
 	"storj.io/uplink/private/eestream"
@@ -82,6 +83,12 @@ var (
 		Args:  cobra.MinimumNArgs(4),
 		RunE:  SegmentHealth,
 	}
+	repairHistoryCmd = &cobra.Command{
+		Use:   "repair-history <project-id> <segment-index> <bucket> <encrypted-path>",
+		Short: "List the failed repair attempts of a segment in the repair queue",
+		Args:  cobra.MinimumNArgs(4),
+		RunE:  SegmentRepairHistory,
+	}
 	paymentsCmd = &cobra.Command{
 		Use:   "payments",
 		Short: "commands for payments",
@@ -116,12 +123,13 @@ var (
 
 // Inspector gives access to overlay.
 type Inspector struct {
-	conn           *rpc.Conn
-	identity       *identity.FullIdentity
-	overlayclient  pb.DRPCOverlayInspectorClient
-	irrdbclient    pb.DRPCIrreparableInspectorClient
-	healthclient   pb.DRPCHealthInspectorClient
-	paymentsClient pb.DRPCPaymentsClient
+	conn                *rpc.Conn
+	identity            *identity.FullIdentity
+	overlayclient       pb.DRPCOverlayInspectorClient
+	irrdbclient         pb.DRPCIrreparableInspectorClient
+	healthclient        pb.DRPCHealthInspectorClient
+	repairHistoryClient inspectorpb.DRPCRepairHistoryInspectorClient
+	paymentsClient      pb.DRPCPaymentsClient
 }
 
 // NewInspector creates a new inspector client for access to overlay.
@@ -142,12 +150,13 @@ func NewInspector(address, path string) (*Inspector, error) {
 	}
 
 	return &Inspector{
-		conn:           conn,
-		identity:       id,
-		overlayclient:  pb.NewDRPCOverlayInspectorClient(conn),
-		irrdbclient:    pb.NewDRPCIrreparableInspectorClient(conn),
-		healthclient:   pb.NewDRPCHealthInspectorClient(conn),
-		paymentsClient: pb.NewDRPCPaymentsClient(conn),
+		conn:                conn,
+		identity:            id,
+		overlayclient:       pb.NewDRPCOverlayInspectorClient(conn),
+		irrdbclient:         pb.NewDRPCIrreparableInspectorClient(conn),
+		healthclient:        pb.NewDRPCHealthInspectorClient(conn),
+		repairHistoryClient: inspectorpb.NewDRPCRepairHistoryInspectorClient(conn),
+		paymentsClient:      pb.NewDRPCPaymentsClient(conn),
 	}, nil
 }
 
@@ -294,6 +303,36 @@ func SegmentHealth(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
+// SegmentRepairHistory lists the failed repair attempts of a segment
+func SegmentRepairHistory(cmd *cobra.Command, args []string) (err error) {
+	ctx := context.Background()
+
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrArgs.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, i.Close()) }()
+
+	segmentIndex, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	resp, err := i.repairHistoryClient.SegmentRepairHistory(ctx, &inspectorpb.SegmentRepairHistoryRequest{
+		ProjectId:     []byte(args[0]),
+		SegmentIndex:  segmentIndex,
+		Bucket:        []byte(args[2]),
+		EncryptedPath: []byte(args[3]),
+	})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(resp.Attempts)
+}
+
 func csvOutput() (*os.File, error) {
 	if CSVPath == "stdout" {
 		return os.Stdout, nil
@@ -596,6 +635,7 @@ func init() {
 
 	healthCmd.AddCommand(objectHealthCmd)
 	healthCmd.AddCommand(segmentHealthCmd)
+	healthCmd.AddCommand(repairHistoryCmd)
 
 	paymentsCmd.AddCommand(prepareInvoiceRecordsCmd)
 	paymentsCmd.AddCommand(createInvoiceItemsCmd)
@@ -0,0 +1,165 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/fpath"
+	"storj.io/private/cfgstruct"
+	"storj.io/private/process"
+	"storj.io/storj/multinode"
+	"storj.io/storj/multinode/multinodedb"
+	"storj.io/storj/multinode/nodes"
+	_ "storj.io/storj/private/version" // This attaches version information during release builds.
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "multinode",
+		Short: "Dashboard aggregating many storage nodes",
+	}
+	runCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Run the multinode dashboard",
+		RunE:  cmdRun,
+	}
+	setupCmd = &cobra.Command{
+		Use:         "setup",
+		Short:       "Create config files",
+		RunE:        cmdSetup,
+		Annotations: map[string]string{"type": "setup"},
+	}
+	addNodeCmd = &cobra.Command{
+		Use:   "add-node",
+		Short: "Register a storage node by its console api address",
+		RunE:  cmdAddNode,
+	}
+
+	runCfg   multinode.Config
+	setupCfg multinode.Config
+
+	addNodeCfg struct {
+		Database string `help:"path to the multinode database" default:"$CONFDIR/multinode.db"`
+		Nodes    nodes.Config
+
+		Name      string `help:"name of the node, defaults to its ID" default:""`
+		Address   string `help:"address of the console api of the node, as host:port or an http(s) URL" default:""`
+		AuthToken string `help:"api key of the console api of the node, issued with storagenode issue-apikey" default:""`
+	}
+
+	confDir string
+)
+
+func init() {
+	defaultConfDir := fpath.ApplicationDir("storj", "multinode")
+	cfgstruct.SetupFlag(zap.L(), rootCmd, &confDir, "config-dir", defaultConfDir, "main directory for multinode configuration")
+	defaults := cfgstruct.DefaultsFlag(rootCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(setupCmd)
+	rootCmd.AddCommand(addNodeCmd)
+	process.Bind(runCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir))
+	process.Bind(setupCmd, &setupCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.SetupMode())
+	process.Bind(addNodeCmd, &addNodeCfg, defaults, cfgstruct.ConfDir(confDir))
+}
+
+func cmdRun(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	db, err := multinodedb.New(log.Named("db"), runCfg.Database)
+	if err != nil {
+		return errs.New("Error starting master database on multinode: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	err = db.MigrateToLatest(ctx)
+	if err != nil {
+		return errs.New("Error creating tables for master database on multinode: %+v", err)
+	}
+
+	peer, err := multinode.New(log, db, runCfg)
+	if err != nil {
+		return err
+	}
+
+	runError := peer.Run(ctx)
+	closeError := peer.Close()
+	return errs.Combine(runError, closeError)
+}
+
+func cmdSetup(cmd *cobra.Command, args []string) (err error) {
+	setupDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return err
+	}
+
+	valid, _ := fpath.IsValidSetupDir(setupDir)
+	if !valid {
+		return fmt.Errorf("multinode configuration already exists (%v)", setupDir)
+	}
+
+	err = os.MkdirAll(setupDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// the console api isn't served without an auth token
+	authToken := setupCfg.Console.AuthToken
+	if authToken == "" {
+		var token [32]byte
+		if _, err := rand.Read(token[:]); err != nil {
+			return err
+		}
+		authToken = hex.EncodeToString(token[:])
+	}
+
+	return process.SaveConfig(cmd, filepath.Join(setupDir, "config.yaml"),
+		process.SaveConfigWithOverride("console.auth-token", authToken))
+}
+
+func cmdAddNode(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	if addNodeCfg.Address == "" {
+		return errs.New("--address is required")
+	}
+
+	db, err := multinodedb.New(log.Named("db"), addNodeCfg.Database)
+	if err != nil {
+		return errs.New("Error starting master database on multinode: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	err = db.MigrateToLatest(ctx)
+	if err != nil {
+		return errs.New("Error creating tables for master database on multinode: %+v", err)
+	}
+
+	service := nodes.NewService(log.Named("nodes:service"), db.Nodes(), addNodeCfg.Nodes)
+	node, err := service.Add(ctx, addNodeCfg.Name, addNodeCfg.Address, addNodeCfg.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Registered node %s (%s) at %s.\n", node.Name, node.ID, node.Address)
+	return nil
+}
+
+func main() {
+	process.Exec(rootCmd)
+}
@@ -116,6 +116,12 @@ func (obsvr *observer) processSegment(ctx context.Context, path metainfo.ScopedP
 
 	obsvr.lastProjectID = path.ProjectIDString
 
+	if metainfo.IsVersionedSegment(path.Segment) {
+		// the segments of archived object versions are only moved together
+		// with their last segment, so they aren't checked for zombies
+		return nil
+	}
+
 	isLastSegment := path.Segment == "l"
 	object := findOrCreate(path.BucketName, path.EncryptedObjectPath, obsvr.objects)
 	if isLastSegment {
@@ -0,0 +1,108 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/uuid"
+	"storj.io/private/process"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/apikeys"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+// apiKeyFlags defines the flags of the api key commands.
+type apiKeyFlags struct {
+	storagenode.Config
+
+	Scope apikeys.Scope `help:"scope of the issued api key, read-only or admin" default:"read-only"`
+}
+
+// cmdIssueAPIKey issues a console api key and prints its secret.
+func cmdIssueAPIKey(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+
+	return withAPIKeys(ctx, func(service *apikeys.Service) error {
+		key, secret, err := service.Issue(ctx, args[0], apiKeyCfg.Scope)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Issued %s api key %q with ID %s.\n", key.Scope, key.Name, key.ID)
+		fmt.Println("The key can't be displayed again, store it now:")
+		fmt.Println(secret)
+		return nil
+	})
+}
+
+// cmdListAPIKeys prints the issued console api keys.
+func cmdListAPIKeys(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+
+	return withAPIKeys(ctx, func(service *apikeys.Service) (err error) {
+		keys, err := service.List(ctx)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			fmt.Println("No api keys issued.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		defer func() { err = errs.Combine(err, w.Flush()) }()
+
+		fmt.Fprint(w, "ID\tName\tScope\tCreated At\n")
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", key.ID, key.Name, key.Scope, key.CreatedAt)
+		}
+		return nil
+	})
+}
+
+// cmdRevokeAPIKey revokes a console api key.
+func cmdRevokeAPIKey(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+
+	id, err := uuid.FromString(args[0])
+	if err != nil {
+		return errs.New("invalid api key ID %q: %v", args[0], err)
+	}
+
+	return withAPIKeys(ctx, func(service *apikeys.Service) error {
+		if err := service.Revoke(ctx, id); err != nil {
+			return err
+		}
+		fmt.Printf("Revoked api key %s.\n", id)
+		return nil
+	})
+}
+
+// withAPIKeys opens the storage node database and calls fn with the api keys service.
+func withAPIKeys(ctx context.Context, fn func(service *apikeys.Service) error) (err error) {
+	log := zap.L()
+
+	db, err := storagenodedb.New(log.Named("db"), apiKeyCfg.DatabaseConfig())
+	if err != nil {
+		return errs.New("Error starting master database on storage node: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	err = db.MigrateToLatest(ctx)
+	if err != nil {
+		return errs.New("Error creating tables for master database on storage node: %v", err)
+	}
+
+	return fn(apikeys.NewService(log.Named("apikeys"), db.APIKeys()))
+}
@@ -0,0 +1,395 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/identity"
+	"storj.io/common/storj"
+	"storj.io/private/process"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+// restoreFlags defines the flags of the restore command.
+type restoreFlags struct {
+	storagenode.Config
+
+	Force bool `help:"overwrite the existing databases, identity and config" default:"false"`
+}
+
+// Names of the entries of a backup archive.
+const (
+	backupManifestName  = "manifest.json"
+	backupDatabasesDir  = "databases"
+	backupIdentityCert  = "identity/identity.cert"
+	backupIdentityKey   = "identity/identity.key"
+	backupConfigName    = "config.yaml"
+	backupDatabaseExt   = ".db"
+	backupPiecesDirName = "pieces"
+)
+
+// backupManifest describes the content of a backup archive.
+type backupManifest struct {
+	CreatedAt time.Time      `json:"createdAt"`
+	NodeID    storj.NodeID   `json:"nodeId"`
+	Versions  map[string]int `json:"versions"`
+}
+
+// cmdBackup writes a snapshot of the databases together with the identity and
+// the config into an archive. The node may be running while the backup is taken.
+func cmdBackup(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	ident, err := diagCfg.Identity.Load()
+	if err != nil {
+		return errs.New("Error loading identity: %v", err)
+	}
+
+	stagingDir, err := ioutil.TempDir("", "storagenode-backup")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, os.RemoveAll(stagingDir)) }()
+
+	// The pieces aren't part of the backup, so keep the piece store of the
+	// running node untouched.
+	dbConfig := diagCfg.DatabaseConfig()
+	dbConfig.Backend = ""
+	dbConfig.Pieces = filepath.Join(stagingDir, backupPiecesDirName)
+	dbConfig.ExtraPieces = nil
+
+	db, err := storagenodedb.New(log.Named("db"), dbConfig)
+	if err != nil {
+		return errs.New("Error starting master database on storage node: %v", err)
+	}
+	defer func() { err = errs.Combine(err, db.Close()) }()
+
+	manifest := backupManifest{
+		CreatedAt: time.Now().UTC(),
+		NodeID:    ident.ID,
+	}
+	manifest.Versions, err = db.Versions(ctx)
+	if err != nil {
+		return err
+	}
+
+	databasesDir := filepath.Join(stagingDir, backupDatabasesDir)
+	if err := db.Backup(ctx, databasesDir); err != nil {
+		return err
+	}
+
+	err = writeBackupArchive(args[0], manifest, databasesDir)
+	if err != nil {
+		return errs.New("Error writing backup archive: %v", err)
+	}
+
+	fmt.Printf("Backed up %d databases of node %s to %s.\n", len(manifest.Versions), manifest.NodeID, args[0])
+	return nil
+}
+
+// writeBackupArchive writes the manifest, the database snapshots in
+// databasesDir, the identity and the config into a new archive at archivePath.
+func writeBackupArchive(archivePath string, manifest backupManifest, databasesDir string) (err error) {
+	manifestData, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(archivePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, os.Remove(archivePath))
+		}
+	}()
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	gw := gzip.NewWriter(file)
+	defer func() { err = errs.Combine(err, gw.Close()) }()
+
+	tw := tar.NewWriter(gw)
+	defer func() { err = errs.Combine(err, tw.Close()) }()
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     backupManifestName,
+		Mode:     0600,
+		Size:     int64(len(manifestData)),
+		ModTime:  manifest.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return err
+	}
+
+	for dbName := range manifest.Versions {
+		name := dbName + backupDatabaseExt
+		err := addFileToArchive(tw, path.Join(backupDatabasesDir, name), filepath.Join(databasesDir, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := addFileToArchive(tw, backupIdentityCert, diagCfg.Identity.CertPath); err != nil {
+		return err
+	}
+	if err := addFileToArchive(tw, backupIdentityKey, diagCfg.Identity.KeyPath); err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(confDir, backupConfigName)
+	if _, err := os.Stat(configPath); err == nil {
+		if err := addFileToArchive(tw, backupConfigName, configPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addFileToArchive adds the file at filePath to the archive as name.
+func addFileToArchive(tw *tar.Writer, name, filePath string) (err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// cmdRestore restores the databases, the identity and the config from a
+// backup archive. The databases are checked before anything is overwritten,
+// the node must not be running.
+func cmdRestore(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	stagingDir, err := ioutil.TempDir("", "storagenode-restore")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, os.RemoveAll(stagingDir)) }()
+
+	manifest, err := extractBackupArchive(args[0], stagingDir)
+	if err != nil {
+		return errs.New("Error reading backup archive: %v", err)
+	}
+
+	ident, err := identity.Config{
+		CertPath: filepath.Join(stagingDir, filepath.FromSlash(backupIdentityCert)),
+		KeyPath:  filepath.Join(stagingDir, filepath.FromSlash(backupIdentityKey)),
+	}.Load()
+	if err != nil {
+		return errs.New("Error loading identity from backup archive: %v", err)
+	}
+	if ident.ID != manifest.NodeID {
+		return errs.New("identity %s of the backup archive doesn't belong to node %s", ident.ID, manifest.NodeID)
+	}
+
+	databasesDir := filepath.Join(stagingDir, backupDatabasesDir)
+	if err := checkRestoredDatabases(ctx, log, databasesDir, filepath.Join(stagingDir, backupPiecesDirName)); err != nil {
+		return err
+	}
+
+	// map the restored files to their destinations
+	targets := map[string]string{
+		filepath.Join(stagingDir, filepath.FromSlash(backupIdentityCert)): restoreCfg.Identity.CertPath,
+		filepath.Join(stagingDir, filepath.FromSlash(backupIdentityKey)):  restoreCfg.Identity.KeyPath,
+	}
+	configPath := filepath.Join(stagingDir, backupConfigName)
+	if _, err := os.Stat(configPath); err == nil {
+		targets[configPath] = filepath.Join(confDir, backupConfigName)
+	}
+
+	dbDir := filepath.Dir(restoreCfg.DatabaseConfig().Info2)
+	databases, err := filepath.Glob(filepath.Join(databasesDir, "*"+backupDatabaseExt))
+	if err != nil {
+		return err
+	}
+	for _, database := range databases {
+		targets[database] = filepath.Join(dbDir, filepath.Base(database))
+	}
+
+	if !restoreCfg.Force {
+		for _, target := range targets {
+			if _, err := os.Stat(target); err == nil {
+				return errs.New("%s already exists, use --force to overwrite it", target)
+			}
+		}
+	}
+
+	for source, target := range targets {
+		// a stale write-ahead log would be applied to the restored database
+		if strings.HasSuffix(target, backupDatabaseExt) {
+			for _, suffix := range []string{"-wal", "-shm"} {
+				if err := os.Remove(target + suffix); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
+
+		if err := copyFile(source, target); err != nil {
+			return errs.New("Error restoring %s: %v", target, err)
+		}
+	}
+
+	fmt.Printf("Restored %d databases of node %s backed up at %v.\n", len(databases), manifest.NodeID, manifest.CreatedAt)
+	return nil
+}
+
+// extractBackupArchive extracts the archive at archivePath into dir and
+// returns its manifest.
+func extractBackupArchive(archivePath, dir string) (_ backupManifest, err error) {
+	var manifest backupManifest
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return manifest, err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return manifest, err
+	}
+	defer func() { err = errs.Combine(err, gr.Close()) }()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			return manifest, errs.New("unexpected entry %q", header.Name)
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return manifest, errs.New("invalid entry %q", header.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return manifest, err
+		}
+		if err := writeFile(target, tr); err != nil {
+			return manifest, err
+		}
+	}
+
+	manifestData, err := ioutil.ReadFile(filepath.Join(dir, backupManifestName))
+	if err != nil {
+		return manifest, errs.New("missing manifest: %v", err)
+	}
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return manifest, errs.New("invalid manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+// checkRestoredDatabases checks that the restored databases in dir can be
+// used by this release, migrates them and runs the pre-flight check of the
+// node on them.
+func checkRestoredDatabases(ctx context.Context, log *zap.Logger, dir, piecesDir string) (err error) {
+	dbConfig := restoreCfg.DatabaseConfig()
+	dbConfig.Backend = ""
+	dbConfig.Storage = dir
+	dbConfig.Info = filepath.Join(dir, "piecestore.db")
+	dbConfig.Info2 = filepath.Join(dir, "info.db")
+	dbConfig.Pieces = piecesDir
+	dbConfig.ExtraPieces = nil
+
+	db, err := storagenodedb.New(log.Named("db"), dbConfig)
+	if err != nil {
+		return errs.New("Error opening restored databases: %v", err)
+	}
+	defer func() { err = errs.Combine(err, db.Close()) }()
+
+	if err := db.CheckVersions(ctx); err != nil {
+		return err
+	}
+	if err := db.MigrateToLatest(ctx); err != nil {
+		return errs.New("Error migrating restored databases: %v", err)
+	}
+	if err := db.Preflight(ctx); err != nil {
+		return errs.New("Error during preflight check of restored databases: %v", err)
+	}
+	return nil
+}
+
+// copyFile copies the file at source to target, replacing target atomically.
+func copyFile(source, target string) (err error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+
+	temp := target + ".restore"
+	if err := writeFile(temp, file); err != nil {
+		return errs.Combine(err, os.Remove(temp))
+	}
+	return os.Rename(temp, target)
+}
+
+// writeFile writes the content of r into a new file at filePath.
+func writeFile(filePath string, r io.Reader) (err error) {
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	_, err = io.Copy(file, r)
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
@@ -82,10 +82,58 @@ var (
 		RunE:        cmdGracefulExitStatus,
 		Annotations: map[string]string{"type": "helper"},
 	}
+	scrubCmd = &cobra.Command{
+		Use:         "scrub",
+		Short:       "Verify the hashes of all the stored pieces",
+		RunE:        cmdScrub,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	convertToPackstoreCmd = &cobra.Command{
+		Use:         "convert-to-packstore",
+		Short:       "Move all the stored pieces into pack files",
+		RunE:        cmdConvertToPackstore,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	issueAPIKeyCmd = &cobra.Command{
+		Use:         "issue-apikey <name>",
+		Short:       "Issue an api key for the remote access to the console api",
+		Args:        cobra.ExactArgs(1),
+		RunE:        cmdIssueAPIKey,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	listAPIKeysCmd = &cobra.Command{
+		Use:         "list-apikeys",
+		Short:       "List the issued console api keys",
+		RunE:        cmdListAPIKeys,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	revokeAPIKeyCmd = &cobra.Command{
+		Use:         "revoke-apikey <id>",
+		Short:       "Revoke a console api key",
+		Args:        cobra.ExactArgs(1),
+		RunE:        cmdRevokeAPIKey,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	backupCmd = &cobra.Command{
+		Use:         "backup <archive>",
+		Short:       "Back up the databases, the identity and the config into an archive",
+		Args:        cobra.ExactArgs(1),
+		RunE:        cmdBackup,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	restoreCmd = &cobra.Command{
+		Use:         "restore <archive>",
+		Short:       "Restore the databases, the identity and the config from an archive",
+		Args:        cobra.ExactArgs(1),
+		RunE:        cmdRestore,
+		Annotations: map[string]string{"type": "helper"},
+	}
 
 	runCfg       StorageNodeFlags
 	setupCfg     StorageNodeFlags
 	diagCfg      storagenode.Config
+	apiKeyCfg    apiKeyFlags
+	restoreCfg   restoreFlags
 	dashboardCfg struct {
 		Address string `default:"127.0.0.1:7778" help:"address for dashboard service"`
 	}
@@ -115,6 +163,13 @@ func init() {
 	rootCmd.AddCommand(dashboardCmd)
 	rootCmd.AddCommand(gracefulExitInitCmd)
 	rootCmd.AddCommand(gracefulExitStatusCmd)
+	rootCmd.AddCommand(scrubCmd)
+	rootCmd.AddCommand(convertToPackstoreCmd)
+	rootCmd.AddCommand(issueAPIKeyCmd)
+	rootCmd.AddCommand(listAPIKeysCmd)
+	rootCmd.AddCommand(revokeAPIKeyCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
 	process.Bind(runCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(setupCmd, &setupCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir), cfgstruct.SetupMode())
 	process.Bind(configCmd, &setupCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir), cfgstruct.SetupMode())
@@ -122,6 +177,13 @@ func init() {
 	process.Bind(dashboardCmd, &dashboardCfg, defaults, cfgstruct.ConfDir(defaultDiagDir))
 	process.Bind(gracefulExitInitCmd, &diagCfg, defaults, cfgstruct.ConfDir(defaultDiagDir))
 	process.Bind(gracefulExitStatusCmd, &diagCfg, defaults, cfgstruct.ConfDir(defaultDiagDir))
+	process.Bind(scrubCmd, &diagCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(convertToPackstoreCmd, &diagCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(issueAPIKeyCmd, &apiKeyCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(listAPIKeysCmd, &apiKeyCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(revokeAPIKeyCmd, &apiKeyCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(backupCmd, &diagCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(restoreCmd, &restoreCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 }
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
@@ -0,0 +1,73 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/private/process"
+	"storj.io/storj/storage/packstore"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+// cmdConvertToPackstore moves all the pieces of a filestore node into pack
+// files in place. The node must not be running while the pieces are moved.
+func cmdConvertToPackstore(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	storageDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return err
+	}
+
+	// check if the directory exists
+	_, err = os.Stat(storageDir)
+	if err != nil {
+		fmt.Println("storage node directory doesn't exist", storageDir)
+		return err
+	}
+
+	// the pieces are read from the filestore, regardless of the configured backend
+	dbConfig := diagCfg.DatabaseConfig()
+	dbConfig.Backend = "filestore"
+
+	db, err := storagenodedb.New(log.Named("db"), dbConfig)
+	if err != nil {
+		return errs.New("Error starting master database on storage node: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	err = db.MigrateToLatest(ctx)
+	if err != nil {
+		return errs.New("Error creating tables for master database on storage node: %v", err)
+	}
+
+	packs, err := packstore.New(log.Named("packstore"), dbConfig.Pieces, db.PackIndex(), diagCfg.Packstore)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errs.Combine(err, packs.Close())
+	}()
+
+	stats, err := packstore.Convert(ctx, log.Named("packstore:convert"), db.Pieces(), packs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Converted %d pieces (%v) of %d satellites, %d of them are in the trash.\n",
+		stats.Blobs, memory.Size(stats.Bytes), stats.Namespaces, stats.Trashed)
+	fmt.Println("Set storage.backend to packstore, and remove storage.extra-paths if set, before starting the node.")
+	return nil
+}
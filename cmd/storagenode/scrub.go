@@ -0,0 +1,86 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/private/process"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+// cmdScrub verifies the hashes of all the stored pieces once and prints the
+// pieces which are corrupt.
+func cmdScrub(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	storageDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return err
+	}
+
+	// check if the directory exists
+	_, err = os.Stat(storageDir)
+	if err != nil {
+		fmt.Println("storage node directory doesn't exist", storageDir)
+		return err
+	}
+
+	db, err := storagenodedb.New(log.Named("db"), diagCfg.DatabaseConfig())
+	if err != nil {
+		return errs.New("Error starting master database on storage node: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	err = db.MigrateToLatest(ctx)
+	if err != nil {
+		return errs.New("Error creating tables for master database on storage node: %v", err)
+	}
+
+	store := pieces.NewStore(log.Named("pieces"),
+		db.Pieces(),
+		db.V0PieceInfo(),
+		db.PieceExpirationDB(),
+		db.PieceSpaceUsedDB(),
+		diagCfg.Pieces,
+	)
+	scrubber := pieces.NewScrubber(log.Named("pieces:scrubber"), store, db.CorruptPieces(), diagCfg.Scrubber)
+
+	stats, err := scrubber.Scrub(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checked %d pieces (%v), skipped %d V0 pieces, found %d corrupt pieces.\n",
+		stats.Checked, memory.Size(stats.Bytes), stats.Skipped, stats.Corrupt)
+
+	corrupt, err := db.CorruptPieces().List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(corrupt) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	defer func() { err = errs.Combine(err, w.Flush()) }()
+
+	fmt.Fprint(w, "\nSatellite\tPiece\tDetected At\tReason\n")
+	for _, piece := range corrupt {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%s\n", piece.SatelliteID, piece.PieceID, piece.DetectedAt, piece.Reason)
+	}
+	return nil
+}
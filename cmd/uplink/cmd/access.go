@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
 
 	"storj.io/private/cfgstruct"
 	"storj.io/private/process"
@@ -16,6 +17,7 @@ import (
 var (
 	inspectCfg AccessConfig
 	listCfg    AccessConfig
+	revokeCfg  AccessConfig
 )
 
 func init() {
@@ -40,12 +42,21 @@ func init() {
 		Args:  cobra.NoArgs,
 	}
 
+	revokeCmd := &cobra.Command{
+		Use:   "revoke ACCESS",
+		Short: "Revokes an access which was shared from the current access.",
+		RunE:  accessRevoke,
+		Args:  cobra.ExactArgs(1),
+	}
+
 	RootCmd.AddCommand(accessCmd)
 	accessCmd.AddCommand(inspectCmd)
 	accessCmd.AddCommand(listCmd)
+	accessCmd.AddCommand(revokeCmd)
 
 	process.Bind(inspectCmd, &inspectCfg, defaults, cfgstruct.ConfDir(getConfDir()))
 	process.Bind(listCmd, &listCfg, defaults, cfgstruct.ConfDir(getConfDir()))
+	process.Bind(revokeCmd, &revokeCfg, defaults, cfgstruct.ConfDir(getConfDir()))
 }
 
 func accessList(cmd *cobra.Command, args []string) (err error) {
@@ -96,3 +107,49 @@ func accessInspect(cmd *cobra.Command, args []string) (err error) {
 	fmt.Println("Encryption Access:", serializedEncAccess)
 	return nil
 }
+
+// accessRevoke revokes the access given as argument, which must be the
+// current access or be shared from it. The current access and the other
+// accesses shared from it keep working.
+func accessRevoke(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := withTelemetry(cmd)
+
+	access, err := revokeCfg.GetAccess()
+	if err != nil {
+		return err
+	}
+
+	revoked, err := revokeCfg.GetNamedAccess(args[0])
+	if err != nil {
+		return err
+	}
+	if revoked == nil {
+		if revoked, err = libuplink.ParseScope(args[0]); err != nil {
+			return err
+		}
+	}
+
+	if revoked.SatelliteAddr != access.SatelliteAddr {
+		return fmt.Errorf("access to revoke is for satellite %s, not %s", revoked.SatelliteAddr, access.SatelliteAddr)
+	}
+
+	up, err := libuplink.NewUplink(ctx, &libuplink.Config{})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, up.Close()) }()
+
+	project, err := up.OpenProject(ctx, access.SatelliteAddr, access.APIKey)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, project.Close()) }()
+
+	err = project.RevokeAPIKey(ctx, revoked.APIKey)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Revoked access for satellite", revoked.SatelliteAddr)
+	return nil
+}
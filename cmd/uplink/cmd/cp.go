@@ -22,9 +22,16 @@ import (
 )
 
 var (
-	progress *bool
-	expires  *string
-	metadata *string
+	progress    *bool
+	expires     *string
+	metadata    *string
+	cpRecursive *bool
+	cpTransfers *int
+	cpInclude   *[]string
+	cpExclude   *[]string
+
+	cpResumable   *bool
+	cpParallelism *int
 )
 
 func init() {
@@ -38,8 +45,48 @@ func init() {
 	progress = cpCmd.Flags().Bool("progress", true, "if true, show progress")
 	expires = cpCmd.Flags().String("expires", "", "optional expiration date of an object. Please use format (yyyy-mm-ddThh:mm:ssZhh:mm)")
 	metadata = cpCmd.Flags().String("metadata", "", "optional metadata for the object. Please use a single level JSON object of string to string only")
+	cpRecursive = cpCmd.Flags().Bool("recursive", false, "if true, copy a local directory to a Storj prefix or a Storj prefix to a local directory")
+	cpTransfers = cpCmd.Flags().Int("transfers", 4, "number of files transferred in parallel with --recursive")
+	cpInclude = cpCmd.Flags().StringArray("include", nil, "only copy the paths matching the glob pattern with --recursive, patterns without a slash match the file name (can be repeated)")
+	cpExclude = cpCmd.Flags().StringArray("exclude", nil, "don't copy the paths matching the glob pattern with --recursive, patterns without a slash match the file name (can be repeated)")
 
-	setBasicFlags(cpCmd.Flags(), "progress", "expires", "metadata")
+	cpResumable = cpCmd.Flags().Bool("resumable", false, "if true, transfer the segments of a file in parallel and resume an interrupted transfer of the same file")
+	cpParallelism = cpCmd.Flags().Int("parallelism", 1, "number of segments uploaded or downloaded in parallel, more than one implies --resumable")
+
+	setBasicFlags(cpCmd.Flags(), "progress", "expires", "metadata", "recursive", "transfers", "resumable", "parallelism")
+}
+
+// parseExpiration parses the expiration date of the uploaded objects.
+func parseExpiration(expires string) (expiration time.Time, err error) {
+	if expires == "" {
+		return time.Time{}, nil
+	}
+
+	expiration, err = time.Parse(time.RFC3339, expires)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if expiration.Before(time.Now()) {
+		return time.Time{}, fmt.Errorf("invalid expiration date: (%s) has already passed", expires)
+	}
+	return expiration, nil
+}
+
+// parseMetadata parses the custom metadata of the uploaded objects.
+func parseMetadata(metadata string) (customMetadata uplink.CustomMetadata, err error) {
+	if metadata == "" {
+		return nil, nil
+	}
+
+	err = json.Unmarshal([]byte(metadata), &customMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := customMetadata.Verify(); err != nil {
+		return nil, err
+	}
+	return customMetadata, nil
 }
 
 // upload transfers src from local machine to s3 compatible object dst
@@ -52,15 +99,9 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 		return fmt.Errorf("destination must be Storj URL: %s", dst)
 	}
 
-	var expiration time.Time
-	if *expires != "" {
-		expiration, err = time.Parse(time.RFC3339, *expires)
-		if err != nil {
-			return err
-		}
-		if expiration.Before(time.Now()) {
-			return fmt.Errorf("invalid expiration date: (%s) has already passed", *expires)
-		}
+	expiration, err := parseExpiration(*expires)
+	if err != nil {
+		return err
 	}
 
 	// if object name not specified, default to filename
@@ -88,6 +129,13 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 		return fmt.Errorf("source cannot be a directory: %s", src)
 	}
 
+	if *cpResumable || *cpParallelism > 1 {
+		if file == os.Stdin {
+			return fmt.Errorf("resumable uploads can't read from stdin: %s", src)
+		}
+		return uploadResumable(ctx, file, fileInfo, dst, expiration, *cpParallelism, showProgress)
+	}
+
 	project, err := cfg.getProject(ctx, false)
 	if err != nil {
 		return err
@@ -102,16 +150,9 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 		bar.Start()
 	}
 
-	var customMetadata uplink.CustomMetadata
-	if *metadata != "" {
-		err := json.Unmarshal([]byte(*metadata), &customMetadata)
-		if err != nil {
-			return err
-		}
-
-		if err := customMetadata.Verify(); err != nil {
-			return err
-		}
+	customMetadata, err := parseMetadata(*metadata)
+	if err != nil {
+		return err
 	}
 
 	upload, err := project.UploadObject(ctx, dst.Bucket(), dst.Path(), &uplink.UploadOptions{
@@ -161,6 +202,16 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 		return fmt.Errorf("destination must be local path: %s", dst)
 	}
 
+	if *cpResumable || *cpParallelism > 1 {
+		if fileInfo, err := os.Stat(dst.Path()); err == nil && fileInfo.IsDir() {
+			dst = dst.Join(src.Base())
+		}
+		if dst.Base() == "-" {
+			return fmt.Errorf("resumable downloads can't write to stdout: %s", dst)
+		}
+		return downloadParallel(ctx, src, dst, *cpParallelism, showProgress)
+	}
+
 	project, err := cfg.getProject(ctx, false)
 	if err != nil {
 		return err
@@ -228,66 +279,43 @@ func copyObject(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err erro
 		return fmt.Errorf("destination must be Storj URL: %s", dst)
 	}
 
-	project, err := cfg.getProject(ctx, false)
-	if err != nil {
-		return err
-	}
-	defer closeProject(project)
-
-	download, err := project.DownloadObject(ctx, src.Bucket(), src.Path(), nil)
-	if err != nil {
-		return err
-	}
-	defer func() { err = errs.Combine(err, download.Close()) }()
-
-	downloadInfo := download.Info()
-
-	var bar *progressbar.ProgressBar
-	var reader io.Reader
-	if *progress {
-		bar = progressbar.New64(downloadInfo.System.ContentLength)
-		reader = bar.NewProxyReader(download)
-		bar.Start()
-	} else {
-		reader = download
-	}
-
 	// if destination object name not specified, default to source object name
 	if strings.HasSuffix(dst.Path(), "/") {
 		dst = dst.Join(src.Base())
 	}
 
-	upload, err := project.UploadObject(ctx, dst.Bucket(), dst.Path(), &uplink.UploadOptions{
-		Expires: downloadInfo.System.Expires,
-	})
-
-	_, err = io.Copy(upload, reader)
+	// the object is copied by the satellite, hence nothing is downloaded
+	err = relocateObject(ctx, src, dst, false)
 	if err != nil {
-		abortErr := upload.Abort()
-		return errs.Combine(err, abortErr)
+		return convertError(err, src)
 	}
 
-	err = upload.SetCustomMetadata(ctx, downloadInfo.Custom)
-	if err != nil {
-		abortErr := upload.Abort()
-		return errs.Combine(err, abortErr)
-	}
+	fmt.Printf("%s copied to %s\n", src.String(), dst.String())
+
+	return nil
+}
 
-	err = upload.Commit()
+// copyRecursive copies every file of the tree at src to the tree at dst.
+func copyRecursive(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err error) {
+	expiration, err := parseExpiration(*expires)
 	if err != nil {
 		return err
 	}
 
-	if bar != nil {
-		bar.Finish()
-	}
+	customMetadata, err := parseMetadata(*metadata)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("%s copied to %s\n", src.String(), dst.String())
-
-	return nil
+	return transferTree(ctx, src, dst, treeOptions{
+		Transfers: *cpTransfers,
+		Always:    true,
+		Progress:  *progress,
+		Include:   *cpInclude,
+		Exclude:   *cpExclude,
+		Metadata:  customMetadata,
+		Expires:   expiration,
+	})
 }
 
 // copyMain is the function executed when cpCmd is called.
@@ -316,6 +344,10 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 		return errors.New("at least one of the source or the destination must be a Storj URL")
 	}
 
+	if *cpRecursive {
+		return copyRecursive(ctx, src, dst)
+	}
+
 	// if uploading
 	if src.IsLocal() {
 		return upload(ctx, src, dst, *progress)
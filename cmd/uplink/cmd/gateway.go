@@ -0,0 +1,145 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/cmd/uplink/gateway"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+var (
+	gatewayAddress  *string
+	gatewayRegion   *string
+	gatewayStateDir *string
+)
+
+func init() {
+	gatewayCmd := &cobra.Command{
+		Use:   "gateway",
+		Short: "Set of commands to serve an S3-compatible gateway.",
+	}
+	RootCmd.AddCommand(gatewayCmd)
+
+	runCmd := addCmd(&cobra.Command{
+		Use:   "run",
+		Short: "Serves the S3 API for the accesses, with the access names as access key IDs",
+		RunE:  gatewayRun,
+		Args:  cobra.NoArgs,
+	}, gatewayCmd)
+
+	gatewayAddress = runCmd.Flags().String("address", "127.0.0.1:7777", "address to serve the S3 API on")
+	gatewayRegion = runCmd.Flags().String("region", "us-east-1", "region of the signatures, any region is accepted when empty")
+	gatewayStateDir = runCmd.Flags().String("state-dir", "", "directory of the state of the multipart uploads, the gateway directory of the configuration directory by default")
+
+	setBasicFlags(runCmd.Flags(), "address", "region")
+}
+
+// gatewayRun is the function executed when the gateway run command is called.
+func gatewayRun(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := withTelemetry(cmd)
+
+	credentials, err := gatewayCredentials()
+	if err != nil {
+		return err
+	}
+
+	stateDir := *gatewayStateDir
+	if stateDir == "" {
+		stateDir = filepath.Join(confDir, "gateway")
+	}
+
+	uplinkCfg := &libuplink.Config{}
+	uplinkCfg.Volatile.DialTimeout = cfg.Client.DialTimeout
+	uplinkCfg.Volatile.PBKDFConcurrency = cfg.PBKDFConcurrency
+
+	up, err := libuplink.NewUplink(ctx, uplinkCfg)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, up.Close()) }()
+
+	s3 := gateway.New(zap.L().Named("gateway"), up, credentials, gateway.Config{
+		Region:   *gatewayRegion,
+		StateDir: stateDir,
+	})
+	defer func() { err = errs.Combine(err, s3.Close()) }()
+
+	listener, err := net.Listen("tcp", *gatewayAddress)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	fmt.Printf("Serving the S3 API on http://%s\n", listener.Addr())
+	fmt.Println("=========== CREDENTIALS: access key ID / secret key ========================")
+	for _, credential := range credentials {
+		fmt.Println(credential.AccessKeyID, "/", credential.SecretKey)
+	}
+
+	server := &http.Server{Handler: s3}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	err = server.Serve(listener)
+	if errs.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return Error.Wrap(err)
+}
+
+// gatewayCredentials returns the credentials of the stored accesses. The
+// current access is named default when it's not a stored access.
+func gatewayCredentials() (credentials []gateway.Credential, err error) {
+	accessCfg := cfg.AccessConfig.normalize()
+
+	names := make([]string, 0, len(accessCfg.Accesses))
+	for name := range accessCfg.Accesses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		access, err := accessCfg.GetNamedAccess(name)
+		if err != nil {
+			return nil, err
+		}
+		credential, err := gateway.NewCredential(name, access)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+
+	if _, ok := accessCfg.Accesses[accessCfg.Access]; !ok {
+		if _, ok := accessCfg.Accesses["default"]; ok {
+			return credentials, nil
+		}
+		access, err := accessCfg.GetAccess()
+		if err != nil {
+			if len(credentials) > 0 {
+				return credentials, nil
+			}
+			return nil, err
+		}
+		credential, err := gateway.NewCredential("default", access)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, nil
+}
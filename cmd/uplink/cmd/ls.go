@@ -12,12 +12,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"storj.io/common/fpath"
+	libuplink "storj.io/storj/lib/uplink"
 	"storj.io/uplink"
 )
 
 var (
 	lsRecursiveFlag *bool
 	lsEncryptedFlag *bool
+	lsVersionsFlag  *bool
 )
 
 func init() {
@@ -29,8 +31,9 @@ func init() {
 	}, RootCmd)
 	lsRecursiveFlag = lsCmd.Flags().Bool("recursive", false, "if true, list recursively")
 	lsEncryptedFlag = lsCmd.Flags().Bool("encrypted", false, "if true, show paths as base64-encoded encrypted paths")
+	lsVersionsFlag = lsCmd.Flags().Bool("versions", false, "if true, list the archived versions and the delete markers of the objects too")
 
-	setBasicFlags(lsCmd.Flags(), "recursive", "encrypted")
+	setBasicFlags(lsCmd.Flags(), "recursive", "encrypted", "versions")
 }
 
 func list(cmd *cobra.Command, args []string) error {
@@ -53,6 +56,11 @@ func list(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("no bucket specified, use format sj://bucket/")
 		}
 
+		if *lsVersionsFlag {
+			err = listVersions(ctx, src)
+			return convertError(err, src)
+		}
+
 		err = listFiles(ctx, project, src.Bucket(), src.Path(), false)
 		return convertError(err, src)
 	}
@@ -118,6 +126,44 @@ func listFiles(ctx context.Context, project *uplink.Project, bucket, prefix stri
 	return nil
 }
 
+// listVersions lists the objects of src with their archived versions. The
+// versions can be restored with the restore command.
+func listVersions(ctx context.Context, src fpath.FPath) error {
+	prefix := src.Path()
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return withLibBucket(ctx, src, func(bucket *libuplink.Bucket) error {
+		opts := libuplink.ObjectVersionListOptions{
+			Prefix:    prefix,
+			Recursive: *lsRecursiveFlag,
+		}
+		for {
+			list, err := bucket.ListObjectVersions(ctx, &opts)
+			if err != nil {
+				return err
+			}
+
+			for _, item := range list.Items {
+				switch {
+				case item.IsPrefix:
+					fmt.Println("PRE", item.Path)
+				case item.IsDeleteMarker:
+					fmt.Printf("%v %v %12v %v %v\n", "DEL", formatTime(item.Created), "", item.Path, item.Version)
+				default:
+					fmt.Printf("%v %v %12v %v %v\n", "OBJ", formatTime(item.Created), item.Size, item.Path, item.Version)
+				}
+			}
+
+			if !list.More {
+				return nil
+			}
+			opts = opts.NextPage(list)
+		}
+	})
+}
+
 func formatTime(t time.Time) string {
 	return t.Local().Format("2006-01-02 15:04:05")
 }
@@ -0,0 +1,106 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/fpath"
+	"storj.io/common/memory"
+	"storj.io/storj/cmd/uplink/mount"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+var (
+	mountAttrTTL    *time.Duration
+	mountCacheDir   *string
+	mountBlockSize  = 1 * memory.MiB
+	mountCacheSize  = 1 * memory.GiB
+	mountReadOnly   *bool
+	mountAllowOther *bool
+)
+
+func init() {
+	mountCmd := addCmd(&cobra.Command{
+		Use:   "mount sj://BUCKET[/PREFIX] MOUNTPOINT",
+		Short: "Mounts a bucket as a filesystem",
+		RunE:  mountMain,
+		Args:  cobra.ExactArgs(2),
+	}, RootCmd)
+
+	mountAttrTTL = mountCmd.Flags().Duration("attr-ttl", 10*time.Second, "how long the directory listings and the attributes of the files are cached, 0 disables the cache")
+	mountCacheDir = mountCmd.Flags().String("cache-dir", "", "local directory for the downloaded blocks and the written files, a temporary directory by default")
+	mountCmd.Flags().Var(&mountBlockSize, "block-size", "size of the blocks downloaded when files are read")
+	mountCmd.Flags().Var(&mountCacheSize, "cache-size", "total size of the downloaded blocks kept in the cache directory")
+	mountReadOnly = mountCmd.Flags().Bool("read-only", false, "if true, mount the bucket read-only")
+	mountAllowOther = mountCmd.Flags().Bool("allow-other", false, "if true, allow other users to access the filesystem")
+
+	setBasicFlags(mountCmd.Flags(), "attr-ttl", "cache-dir", "read-only")
+}
+
+// mountMain is the function executed when mountCmd is called.
+func mountMain(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := withTelemetry(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+	if src.IsLocal() {
+		return fmt.Errorf("source must be Storj URL: %s", src)
+	}
+	if src.Bucket() == "" {
+		return fmt.Errorf("no bucket specified, use format sj://bucket/")
+	}
+	mountpoint := args[1]
+
+	cacheDir := *mountCacheDir
+	if cacheDir == "" {
+		cacheDir, err = ioutil.TempDir("", "uplink-mount")
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, mount.RemoveCache(cacheDir)) }()
+	}
+
+	options := []fuse.MountOption{
+		fuse.FSName("sj://" + src.Bucket()),
+		fuse.Subtype("uplink"),
+	}
+	if *mountReadOnly {
+		options = append(options, fuse.ReadOnly())
+	}
+	if *mountAllowOther {
+		options = append(options, fuse.AllowOther())
+	}
+
+	return withLibBucket(ctx, src, func(bucket *libuplink.Bucket) error {
+		fsys, err := mount.New(zap.L().Named("mount"), bucket, mount.Config{
+			Bucket:    src.Bucket(),
+			Prefix:    src.Path(),
+			CacheDir:  cacheDir,
+			BlockSize: mountBlockSize,
+			CacheSize: mountCacheSize,
+			AttrTTL:   *mountAttrTTL,
+			UID:       uint32(os.Getuid()),
+			GID:       uint32(os.Getgid()),
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Mounting %s at %s, interrupt to unmount\n", src, mountpoint)
+		return mount.Mount(ctx, fsys, mountpoint, options...)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/fpath"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+func init() {
+	addCmd(&cobra.Command{
+		Use:   "mv SOURCE DESTINATION",
+		Short: "Moves a Storj object to another location in Storj",
+		RunE:  moveMain,
+		Args:  cobra.ExactArgs(2),
+	}, RootCmd)
+}
+
+// moveMain is the function executed when mvCmd is called.
+func moveMain(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := withTelemetry(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	dst, err := fpath.New(args[1])
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() {
+		return fmt.Errorf("source must be Storj URL: %s", src)
+	}
+
+	if dst.IsLocal() {
+		return fmt.Errorf("destination must be Storj URL: %s", dst)
+	}
+
+	// if destination object name not specified, default to source object name
+	if dst.Path() == "" || strings.HasSuffix(dst.Path(), "/") {
+		dst = dst.Join(src.Base())
+	}
+
+	err = relocateObject(ctx, src, dst, true)
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	fmt.Printf("%s moved to %s\n", src.String(), dst.String())
+
+	return nil
+}
+
+// relocateObject copies or moves the remote object src to the remote object
+// dst without downloading it.
+func relocateObject(ctx context.Context, src fpath.FPath, dst fpath.FPath, move bool) (err error) {
+	scope, err := cfg.GetAccess()
+	if err != nil {
+		return err
+	}
+
+	uplinkCfg := &libuplink.Config{}
+	uplinkCfg.Volatile.DialTimeout = cfg.Client.DialTimeout
+	uplinkCfg.Volatile.PBKDFConcurrency = cfg.PBKDFConcurrency
+
+	up, err := libuplink.NewUplink(ctx, uplinkCfg)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, up.Close()) }()
+
+	project, err := up.OpenProject(ctx, scope.SatelliteAddr, scope.APIKey)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, project.Close()) }()
+
+	bucket, err := project.OpenBucket(ctx, src.Bucket(), scope.EncryptionAccess)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	if move {
+		return bucket.MoveObject(ctx, src.Path(), dst.Bucket(), dst.Path())
+	}
+	return bucket.CopyObject(ctx, src.Path(), dst.Bucket(), dst.Path())
+}
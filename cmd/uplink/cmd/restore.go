@@ -0,0 +1,56 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/common/fpath"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+var (
+	restoreVersionFlag *int
+)
+
+func init() {
+	restoreCmd := addCmd(&cobra.Command{
+		Use:   "restore sj://BUCKET/KEY",
+		Short: "Restore an archived version of an object of a bucket with versioning",
+		RunE:  restoreObjectVersion,
+		Args:  cobra.ExactArgs(1),
+	}, RootCmd)
+	restoreVersionFlag = restoreCmd.Flags().Int("version", 0, "the version to restore, as listed by ls --versions")
+	setBasicFlags(restoreCmd.Flags(), "version")
+}
+
+// restoreObjectVersion is the function executed when restoreCmd is called.
+func restoreObjectVersion(cmd *cobra.Command, args []string) error {
+	ctx, _ := withTelemetry(cmd)
+
+	dst, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	if dst.IsLocal() {
+		return fmt.Errorf("no bucket specified, use format sj://bucket/")
+	}
+
+	if *restoreVersionFlag <= 0 {
+		return fmt.Errorf("no version specified, use --version with a version listed by ls --versions")
+	}
+
+	err = withLibBucket(ctx, dst, func(bucket *libuplink.Bucket) error {
+		return bucket.RestoreObjectVersion(ctx, dst.Path(), int32(*restoreVersionFlag))
+	})
+	if err != nil {
+		return convertError(err, dst)
+	}
+
+	fmt.Printf("Restored version %d of %s\n", *restoreVersionFlag, dst)
+	return nil
+}
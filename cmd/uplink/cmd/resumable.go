@@ -0,0 +1,170 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	progressbar "github.com/cheggaaa/pb/v3"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/fpath"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+// uploadResumable uploads file to dst with a parallel upload of its segments.
+// The progress is persisted in a state file in the configuration directory,
+// so running the same upload again resumes it.
+func uploadResumable(ctx context.Context, file *os.File, fileInfo os.FileInfo, dst fpath.FPath, expiration time.Time, parallelism int, showProgress bool) (err error) {
+	customMetadata, err := parseMetadata(*metadata)
+	if err != nil {
+		return err
+	}
+
+	statePath, err := transferStatePath("uploads", file.Name(), dst)
+	if err != nil {
+		return err
+	}
+
+	var bar *progressbar.ProgressBar
+	if showProgress {
+		bar = progressbar.New64(fileInfo.Size())
+		bar.Start()
+	}
+
+	opts := &libuplink.ResumableUploadOptions{
+		StatePath:      statePath,
+		Parallelism:    parallelism,
+		SourceModified: fileInfo.ModTime(),
+	}
+	opts.Expires = expiration
+	opts.Metadata = customMetadata
+	if bar != nil {
+		opts.Progress = func(bytes int64) { bar.Add64(bytes) }
+	}
+
+	err = withLibBucket(ctx, dst, func(bucket *libuplink.Bucket) error {
+		return bucket.UploadObjectResumable(ctx, dst.Path(), file, fileInfo.Size(), opts)
+	})
+
+	if bar != nil {
+		bar.Finish()
+	}
+	if err != nil {
+		return fmt.Errorf("upload interrupted, run the same command again to resume it: %w", err)
+	}
+
+	fmt.Printf("Created %s\n", dst.String())
+	return nil
+}
+
+// downloadParallel downloads src to dst with a parallel download of its
+// segments. The progress is persisted in a state file in the configuration
+// directory, so running the same download again resumes it.
+func downloadParallel(ctx context.Context, src fpath.FPath, dst fpath.FPath, parallelism int, showProgress bool) (err error) {
+	statePath, err := transferStatePath("downloads", dst.Path(), src)
+	if err != nil {
+		return err
+	}
+
+	// the file isn't truncated, to keep the data of an interrupted download
+	file, err := os.OpenFile(dst.Path(), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	err = withLibBucket(ctx, src, func(bucket *libuplink.Bucket) (err error) {
+		object, err := bucket.OpenObject(ctx, src.Path())
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, object.Close()) }()
+
+		var bar *progressbar.ProgressBar
+		opts := &libuplink.ParallelDownloadOptions{
+			Parallelism: parallelism,
+			StatePath:   statePath,
+		}
+		if showProgress {
+			bar = progressbar.New64(object.Meta.Size)
+			bar.Start()
+			opts.Progress = func(bytes int64) { bar.Add64(bytes) }
+		}
+
+		err = object.DownloadParallel(ctx, file, opts)
+		if bar != nil {
+			bar.Finish()
+		}
+		if err != nil {
+			return fmt.Errorf("download interrupted, run the same command again to resume it: %w", err)
+		}
+
+		// a previous file at dst may have been larger
+		return file.Truncate(object.Meta.Size)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Downloaded %s to %s\n", src.String(), dst.String())
+	return nil
+}
+
+// withLibBucket opens the bucket of path with the library which supports the
+// resumable transfers and calls fn with it.
+func withLibBucket(ctx context.Context, path fpath.FPath, fn func(bucket *libuplink.Bucket) error) (err error) {
+	scope, err := cfg.GetAccess()
+	if err != nil {
+		return err
+	}
+
+	uplinkCfg := &libuplink.Config{}
+	uplinkCfg.Volatile.DialTimeout = cfg.Client.DialTimeout
+	uplinkCfg.Volatile.PBKDFConcurrency = cfg.PBKDFConcurrency
+
+	up, err := libuplink.NewUplink(ctx, uplinkCfg)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, up.Close()) }()
+
+	project, err := up.OpenProject(ctx, scope.SatelliteAddr, scope.APIKey)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, project.Close()) }()
+
+	bucket, err := project.OpenBucket(ctx, path.Bucket(), scope.EncryptionAccess)
+	if err != nil {
+		return convertError(err, path)
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	return fn(bucket)
+}
+
+// transferStatePath returns the path of the state file, in the directory
+// named kind of the configuration directory, of the transfer between the
+// local file at path and remote.
+func transferStatePath(kind, path string, remote fpath.FPath) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(confDir, kind)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(absPath + "\n" + remote.String()))
+	return filepath.Join(dir, hex.EncodeToString(hash[:])+".json"), nil
+}
@@ -0,0 +1,551 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	progressbar "github.com/cheggaaa/pb/v3"
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/fpath"
+	"storj.io/common/memory"
+	"storj.io/uplink"
+)
+
+// mtimeMetadataKey is the custom metadata key which keeps the modification
+// time of an uploaded file, so that trees can be compared without
+// downloading them.
+const mtimeMetadataKey = "mtime"
+
+var (
+	syncDelete    *bool
+	syncDryRun    *bool
+	syncProgress  *bool
+	syncTransfers *int
+	syncMetadata  *string
+	syncInclude   *[]string
+	syncExclude   *[]string
+)
+
+func init() {
+	syncCmd := addCmd(&cobra.Command{
+		Use:   "sync SOURCE DESTINATION",
+		Short: "Synchronizes a local directory with a Storj prefix or a Storj prefix with a local directory",
+		RunE:  syncMain,
+		Args:  cobra.ExactArgs(2),
+	}, RootCmd)
+
+	syncDelete = syncCmd.Flags().Bool("delete", false, "if true, delete the files in the destination which don't exist in the source")
+	syncDryRun = syncCmd.Flags().Bool("dry-run", false, "if true, only print what would be transferred and deleted")
+	syncProgress = syncCmd.Flags().Bool("progress", true, "if true, show progress")
+	syncTransfers = syncCmd.Flags().Int("transfers", 4, "number of files transferred in parallel")
+	syncMetadata = syncCmd.Flags().String("metadata", "", "optional metadata for the uploaded objects. Please use a single level JSON object of string to string only")
+	syncInclude = syncCmd.Flags().StringArray("include", nil, "only sync the paths matching the glob pattern, patterns without a slash match the file name (can be repeated)")
+	syncExclude = syncCmd.Flags().StringArray("exclude", nil, "don't sync the paths matching the glob pattern, patterns without a slash match the file name (can be repeated)")
+
+	setBasicFlags(syncCmd.Flags(), "delete", "dry-run", "progress", "transfers", "include", "exclude")
+}
+
+// treeOptions configures a transfer of a whole tree.
+type treeOptions struct {
+	// Transfers is the number of files transferred in parallel.
+	Transfers int
+	// Always transfers every file, instead of only the changed ones.
+	Always bool
+	// Delete removes the destination files which don't exist in the source.
+	Delete bool
+	// DryRun only prints the planned transfers and deletions.
+	DryRun bool
+	// Progress shows a progress bar aggregated across all the files.
+	Progress bool
+
+	Include []string
+	Exclude []string
+
+	// Metadata is the custom metadata of the uploaded objects, next to the
+	// modification time.
+	Metadata uplink.CustomMetadata
+	// Expires is the expiration of the uploaded objects.
+	Expires time.Time
+}
+
+// treeEntry is a file of a local directory or an object below a Storj prefix.
+type treeEntry struct {
+	// Key is the slash separated path relative to the root of the tree.
+	Key     string
+	Size    int64
+	ModTime time.Time
+	// Metadata is the custom metadata of an object, without the
+	// modification time.
+	Metadata uplink.CustomMetadata
+}
+
+// treePlan lists the transfers and the deletions needed to update a tree.
+type treePlan struct {
+	// Transfers are the source entries to copy to the destination.
+	Transfers []treeEntry
+	// Deletes are the destination entries to remove.
+	Deletes []treeEntry
+	// Bytes is the total size of the transfers.
+	Bytes int64
+}
+
+// syncMain is the function executed when syncCmd is called.
+func syncMain(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := withTelemetry(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	dst, err := fpath.New(args[1])
+	if err != nil {
+		return err
+	}
+
+	customMetadata, err := parseMetadata(*syncMetadata)
+	if err != nil {
+		return err
+	}
+
+	return transferTree(ctx, src, dst, treeOptions{
+		Transfers: *syncTransfers,
+		Delete:    *syncDelete,
+		DryRun:    *syncDryRun,
+		Progress:  *syncProgress,
+		Include:   *syncInclude,
+		Exclude:   *syncExclude,
+		Metadata:  customMetadata,
+	})
+}
+
+// transferTree copies the files of the tree at src, which have changed, to
+// the tree at dst. One of them must be local and the other a Storj prefix.
+func transferTree(ctx context.Context, src, dst fpath.FPath, opts treeOptions) (err error) {
+	if src.IsLocal() == dst.IsLocal() {
+		return errors.New("one of the source or the destination must be a local directory and the other a Storj URL")
+	}
+	if opts.Transfers <= 0 {
+		return fmt.Errorf("invalid number of transfers: %d", opts.Transfers)
+	}
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+
+	project, err := cfg.getProject(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer closeProject(project)
+
+	uploading := src.IsLocal()
+
+	var srcTree, dstTree map[string]treeEntry
+	if uploading {
+		srcTree, err = listLocalTree(src.Path(), false)
+		if err == nil {
+			dstTree, err = listRemoteTree(ctx, project, dst)
+		}
+	} else {
+		srcTree, err = listRemoteTree(ctx, project, src)
+		if err == nil {
+			dstTree, err = listLocalTree(dst.Path(), true)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	plan := planTree(filterTree(srcTree, opts.Include, opts.Exclude), filterTree(dstTree, opts.Include, opts.Exclude), uploading, opts)
+
+	if opts.DryRun {
+		for _, entry := range plan.Transfers {
+			fmt.Printf("(dry run) copy %s to %s\n", joinTree(src, entry.Key), joinTree(dst, entry.Key))
+		}
+		for _, entry := range plan.Deletes {
+			fmt.Printf("(dry run) delete %s\n", joinTree(dst, entry.Key))
+		}
+		fmt.Printf("(dry run) %d files (%v) to copy, %d files to delete\n", len(plan.Transfers), memory.Size(plan.Bytes), len(plan.Deletes))
+		return nil
+	}
+
+	var bar *progressbar.ProgressBar
+	if opts.Progress {
+		bar = progressbar.New64(plan.Bytes)
+		bar.Start()
+	}
+
+	transferred, err := runTransfers(ctx, plan.Transfers, opts.Transfers, func(entry treeEntry) error {
+		if uploading {
+			return uploadTreeFile(ctx, project, src, dst, entry, bar, opts)
+		}
+		return downloadTreeFile(ctx, project, src, dst, entry, bar)
+	})
+	if bar != nil {
+		bar.Finish()
+	}
+	fmt.Printf("Copied %d of %d files\n", transferred, len(plan.Transfers))
+	if err != nil {
+		if len(plan.Deletes) > 0 {
+			fmt.Println("Skipped deleting files because of the failed transfers")
+		}
+		return err
+	}
+
+	var group errs.Group
+	deleted := 0
+	for _, entry := range plan.Deletes {
+		if uploading {
+			_, err = project.DeleteObject(ctx, dst.Bucket(), remoteTreeKey(dst, entry.Key))
+		} else {
+			err = os.Remove(localTreePath(dst, entry.Key))
+		}
+		if err != nil {
+			group.Add(fmt.Errorf("delete %s: %w", joinTree(dst, entry.Key), err))
+			continue
+		}
+		deleted++
+	}
+	if len(plan.Deletes) > 0 {
+		fmt.Printf("Deleted %d of %d files\n", deleted, len(plan.Deletes))
+	}
+
+	return group.Err()
+}
+
+// runTransfers calls transfer for the entries with the specified number of
+// workers and returns how many of them succeeded. A failed transfer doesn't
+// stop the other ones.
+func runTransfers(ctx context.Context, entries []treeEntry, workers int, transfer func(treeEntry) error) (transferred int, err error) {
+	queue := make(chan treeEntry)
+
+	var mu sync.Mutex
+	var group errs.Group
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range queue {
+				err := transfer(entry)
+
+				mu.Lock()
+				if err != nil {
+					group.Add(fmt.Errorf("%s: %w", entry.Key, err))
+				} else {
+					transferred++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- entry
+	}
+	close(queue)
+	wg.Wait()
+
+	group.Add(ctx.Err())
+	return transferred, group.Err()
+}
+
+// planTree compares the source and the destination trees and returns the
+// transfers and the deletions needed to make the destination match the
+// source.
+func planTree(srcTree, dstTree map[string]treeEntry, uploading bool, opts treeOptions) treePlan {
+	var plan treePlan
+	for key, srcEntry := range srcTree {
+		dstEntry, ok := dstTree[key]
+		if ok && !opts.Always && !entryChanged(srcEntry, dstEntry, uploading, opts.Metadata) {
+			continue
+		}
+		plan.Transfers = append(plan.Transfers, srcEntry)
+		plan.Bytes += srcEntry.Size
+	}
+
+	if opts.Delete {
+		for key, dstEntry := range dstTree {
+			if _, ok := srcTree[key]; !ok {
+				plan.Deletes = append(plan.Deletes, dstEntry)
+			}
+		}
+	}
+
+	sort.Slice(plan.Transfers, func(i, k int) bool { return plan.Transfers[i].Key < plan.Transfers[k].Key })
+	sort.Slice(plan.Deletes, func(i, k int) bool { return plan.Deletes[i].Key < plan.Deletes[k].Key })
+	return plan
+}
+
+// entryChanged returns whether the destination entry differs from the source
+// entry. The modification times are compared with a second precision, since
+// not every file system keeps more. Only objects have custom metadata, hence
+// it's only compared on uploads.
+func entryChanged(srcEntry, dstEntry treeEntry, uploading bool, metadata uplink.CustomMetadata) bool {
+	if srcEntry.Size != dstEntry.Size {
+		return true
+	}
+	if srcEntry.ModTime.IsZero() || dstEntry.ModTime.IsZero() {
+		return true
+	}
+	if !srcEntry.ModTime.Truncate(time.Second).Equal(dstEntry.ModTime.Truncate(time.Second)) {
+		return true
+	}
+	if uploading && !equalMetadata(metadata, dstEntry.Metadata) {
+		return true
+	}
+	return false
+}
+
+// equalMetadata returns whether both custom metadata have the same entries.
+func equalMetadata(a, b uplink.CustomMetadata) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// filterTree returns the entries of the tree matching any of the include
+// patterns, if there are any, and none of the exclude patterns.
+func filterTree(tree map[string]treeEntry, include, exclude []string) map[string]treeEntry {
+	if len(include) == 0 && len(exclude) == 0 {
+		return tree
+	}
+
+	filtered := make(map[string]treeEntry, len(tree))
+	for key, entry := range tree {
+		if len(include) > 0 && !matchesAny(include, key) {
+			continue
+		}
+		if matchesAny(exclude, key) {
+			continue
+		}
+		filtered[key] = entry
+	}
+	return filtered
+}
+
+// matchesAny returns whether the key matches any of the glob patterns.
+// Patterns without a slash are matched against the last element of the key.
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		name := key
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(key)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// listLocalTree returns the regular files below the root directory. A missing
+// root is an empty tree when allowMissing is set.
+func listLocalTree(root string, allowMissing bool) (map[string]treeEntry, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		if os.IsNotExist(err) && allowMissing {
+			return map[string]treeEntry{}, nil
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	tree := make(map[string]treeEntry)
+	err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		tree[key] = treeEntry{
+			Key:     key,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		return nil
+	})
+	return tree, err
+}
+
+// listRemoteTree returns the objects below the prefix of root. The
+// modification time of the objects which don't keep one in their custom
+// metadata is their creation time.
+func listRemoteTree(ctx context.Context, project *uplink.Project, root fpath.FPath) (map[string]treeEntry, error) {
+	prefix := remotePrefix(root)
+
+	tree := make(map[string]treeEntry)
+	objects := project.ListObjects(ctx, root.Bucket(), &uplink.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+		System:    true,
+		Custom:    true,
+	})
+	for objects.Next() {
+		object := objects.Item()
+		if object.IsPrefix {
+			continue
+		}
+
+		entry := treeEntry{
+			Key:      strings.TrimPrefix(object.Key, prefix),
+			Size:     object.System.ContentLength,
+			ModTime:  object.System.Created,
+			Metadata: uplink.CustomMetadata{},
+		}
+		for key, value := range object.Custom {
+			if key != mtimeMetadataKey {
+				entry.Metadata[key] = value
+				continue
+			}
+			if modTime, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				entry.ModTime = modTime
+			}
+		}
+		tree[entry.Key] = entry
+	}
+	if err := objects.Err(); err != nil {
+		return nil, convertError(err, root)
+	}
+	return tree, nil
+}
+
+// uploadTreeFile uploads the local file of the entry, keeping its
+// modification time in the custom metadata.
+func uploadTreeFile(ctx context.Context, project *uplink.Project, src, dst fpath.FPath, entry treeEntry, bar *progressbar.ProgressBar, opts treeOptions) (err error) {
+	file, err := os.Open(localTreePath(src, entry.Key))
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	customMetadata := uplink.CustomMetadata{}
+	for key, value := range opts.Metadata {
+		customMetadata[key] = value
+	}
+	customMetadata[mtimeMetadataKey] = entry.ModTime.UTC().Format(time.RFC3339Nano)
+
+	upload, err := project.UploadObject(ctx, dst.Bucket(), remoteTreeKey(dst, entry.Key), &uplink.UploadOptions{
+		Expires: opts.Expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = upload.SetCustomMetadata(ctx, customMetadata)
+	if err != nil {
+		return errs.Combine(err, upload.Abort())
+	}
+
+	reader := io.Reader(file)
+	if bar != nil {
+		reader = bar.NewProxyReader(reader)
+	}
+
+	_, err = io.Copy(upload, reader)
+	if err != nil {
+		return errs.Combine(err, upload.Abort())
+	}
+
+	return upload.Commit()
+}
+
+// downloadTreeFile downloads the object of the entry and sets the
+// modification time of the local file to the one of the object.
+func downloadTreeFile(ctx context.Context, project *uplink.Project, src, dst fpath.FPath, entry treeEntry, bar *progressbar.ProgressBar) (err error) {
+	clean := path.Clean(entry.Key)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("object key escapes the destination directory")
+	}
+	localPath := localTreePath(dst, clean)
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return err
+	}
+
+	download, err := project.DownloadObject(ctx, src.Bucket(), remoteTreeKey(src, entry.Key), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, download.Close()) }()
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+
+	reader := io.Reader(download)
+	if bar != nil {
+		reader = bar.NewProxyReader(reader)
+	}
+
+	_, err = io.Copy(file, reader)
+	err = errs.Combine(err, file.Close())
+	if err != nil {
+		return err
+	}
+
+	return os.Chtimes(localPath, entry.ModTime, entry.ModTime)
+}
+
+// remotePrefix returns the listing prefix of a Storj URL.
+func remotePrefix(root fpath.FPath) string {
+	prefix := root.Path()
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// remoteTreeKey returns the object key of the tree key below root.
+func remoteTreeKey(root fpath.FPath, key string) string {
+	return remotePrefix(root) + key
+}
+
+// localTreePath returns the local path of the tree key below root.
+func localTreePath(root fpath.FPath, key string) string {
+	return filepath.Join(root.Path(), filepath.FromSlash(key))
+}
+
+// joinTree returns the printable location of the tree key below root.
+func joinTree(root fpath.FPath, key string) string {
+	if root.IsLocal() {
+		return localTreePath(root, key)
+	}
+	return "sj://" + root.Bucket() + "/" + remoteTreeKey(root, key)
+}
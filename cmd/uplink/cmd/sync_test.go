@@ -0,0 +1,103 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+)
+
+func TestSync(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		run := func(args ...string) string {
+			args = append([]string{"--config-dir", ctx.Dir("uplink")}, args...)
+			output, err := exec.Command(uplinkExe, args...).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+			return string(output)
+		}
+
+		run("import", planet.Uplinks[0].GetConfig(planet.Satellites[0]).Access)
+
+		bucketName := testrand.BucketName()
+		run("mb", "sj://"+bucketName)
+		remote := "sj://" + bucketName + "/backup"
+
+		source := ctx.Dir("source")
+		files := map[string][]byte{
+			"a.txt":         testrand.BytesInt(10 * memory.KiB.Int()),
+			"dir/b.txt":     testrand.BytesInt(100),
+			"dir/sub/c.log": testrand.BytesInt(5 * memory.KiB.Int()),
+		}
+		for name, data := range files {
+			path := filepath.Join(source, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, ioutil.WriteFile(path, data, 0644))
+		}
+
+		// everything is uploaded, except the excluded files
+		output := run("sync", "--progress=false", "--exclude", "*.log", source, remote)
+		require.Contains(t, output, "Copied 2 of 2 files")
+
+		// unchanged files aren't uploaded again
+		output = run("sync", "--dry-run", "--exclude", "*.log", source, remote)
+		require.Contains(t, output, "0 files (0 B) to copy, 0 files to delete")
+
+		// changed files are uploaded and removed files are deleted
+		files["a.txt"] = testrand.BytesInt(20 * memory.KiB.Int())
+		require.NoError(t, ioutil.WriteFile(filepath.Join(source, "a.txt"), files["a.txt"], 0644))
+		require.NoError(t, os.Remove(filepath.Join(source, "dir", "b.txt")))
+		delete(files, "dir/b.txt")
+
+		output = run("sync", "--progress=false", "--delete", "--transfers", "2", source, remote)
+		require.Contains(t, output, "Copied 2 of 2 files")
+		require.Contains(t, output, "Deleted 1 of 1 files")
+
+		// the tree is downloaded with the modification times of the source
+		destination := ctx.Dir("destination")
+		output = run("sync", "--progress=false", remote, destination)
+		require.Contains(t, output, "Copied 2 of 2 files")
+
+		for name, data := range files {
+			path := filepath.FromSlash(name)
+			downloaded, err := ioutil.ReadFile(filepath.Join(destination, path))
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+
+			sourceInfo, err := os.Stat(filepath.Join(source, path))
+			require.NoError(t, err)
+			downloadedInfo, err := os.Stat(filepath.Join(destination, path))
+			require.NoError(t, err)
+			require.Equal(t, sourceInfo.ModTime().Unix(), downloadedInfo.ModTime().Unix())
+		}
+
+		output = run("sync", "--dry-run", remote, destination)
+		require.Contains(t, output, "0 files (0 B) to copy, 0 files to delete")
+
+		// cp --recursive copies the matching files, even when unchanged
+		copied := ctx.Dir("copied")
+		output = run("cp", "--progress=false", "--recursive", "--include", "*.log", remote, copied)
+		require.Contains(t, output, "Copied 1 of 1 files")
+
+		downloaded, err := ioutil.ReadFile(filepath.Join(copied, "dir", "sub", "c.log"))
+		require.NoError(t, err)
+		require.Equal(t, files["dir/sub/c.log"], downloaded)
+	})
+}
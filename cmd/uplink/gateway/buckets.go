@@ -0,0 +1,339 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gateway
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+const (
+	// maxKeys is the maximum number of keys of a listing response.
+	maxKeys = 1000
+
+	// timeFormat is the format of the times of the XML responses.
+	timeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
+	Owner   owner    `xml:"Owner"`
+	Buckets []bucket `xml:"Buckets>Bucket"`
+}
+
+type bucket struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+// listBuckets serves the ListBuckets operation.
+func (gateway *Gateway) listBuckets(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	project, err := gateway.project(ctx, req.credential)
+	if err != nil {
+		return err
+	}
+
+	result := listAllMyBucketsResult{
+		Owner:   owner{ID: req.credential.AccessKeyID, DisplayName: req.credential.AccessKeyID},
+		Buckets: []bucket{},
+	}
+
+	opts := libuplink.BucketListOptions{Direction: storj.After}
+	for {
+		list, err := project.ListBuckets(ctx, &opts)
+		if err != nil {
+			return err
+		}
+		for _, item := range list.Items {
+			result.Buckets = append(result.Buckets, bucket{
+				Name:         item.Name,
+				CreationDate: item.Created.UTC().Format(timeFormat),
+			})
+		}
+		if !list.More || len(list.Items) == 0 {
+			break
+		}
+		opts = opts.NextPage(list)
+	}
+
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+type locationConstraint struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+	Location string   `xml:",chardata"`
+}
+
+// getBucketLocation serves the GetBucketLocation operation. The location is
+// the region of the gateway.
+func (gateway *Gateway) getBucketLocation(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := gateway.checkBucket(ctx, req); err != nil {
+		return err
+	}
+
+	writeXML(w, http.StatusOK, locationConstraint{Location: gateway.config.Region})
+	return nil
+}
+
+// headBucket serves the HeadBucket operation.
+func (gateway *Gateway) headBucket(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := gateway.checkBucket(ctx, req); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// checkBucket checks that the bucket of req exists.
+func (gateway *Gateway) checkBucket(ctx context.Context, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	project, err := gateway.project(ctx, req.credential)
+	if err != nil {
+		return err
+	}
+	_, _, err = project.GetBucketInfo(ctx, req.bucket)
+	return err
+}
+
+type listBucketResult struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+
+	Name         string `xml:"Name"`
+	Prefix       string `xml:"Prefix"`
+	Delimiter    string `xml:"Delimiter,omitempty"`
+	MaxKeys      int    `xml:"MaxKeys"`
+	EncodingType string `xml:"EncodingType,omitempty"`
+	IsTruncated  bool   `xml:"IsTruncated"`
+
+	// ListObjects
+	Marker     *string `xml:"Marker"`
+	NextMarker string  `xml:"NextMarker,omitempty"`
+
+	// ListObjectsV2
+	KeyCount              *int   `xml:"KeyCount"`
+	ContinuationToken     string `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string `xml:"NextContinuationToken,omitempty"`
+	StartAfter            string `xml:"StartAfter,omitempty"`
+
+	Contents       []objectEntry  `xml:"Contents"`
+	CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type objectEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// listing is a page of a listing.
+type listing struct {
+	objects     []objectEntry
+	prefixes    []commonPrefix
+	truncated   bool
+	lastKey     string
+	encodeKeyFn func(string) string
+}
+
+// listObjectsV2 serves the ListObjectsV2 operation.
+func (gateway *Gateway) listObjectsV2(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	query := req.URL.Query()
+
+	after := query.Get("start-after")
+	if token := query.Get("continuation-token"); token != "" {
+		after = token
+	}
+
+	result, list, err := gateway.list(ctx, req, after)
+	if err != nil {
+		return err
+	}
+
+	keyCount := len(list.objects) + len(list.prefixes)
+	result.KeyCount = &keyCount
+	result.ContinuationToken = query.Get("continuation-token")
+	result.StartAfter = list.encodeKeyFn(query.Get("start-after"))
+	if list.truncated {
+		result.NextContinuationToken = list.lastKey
+	}
+
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// listObjects serves the ListObjects operation.
+func (gateway *Gateway) listObjects(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	marker := req.URL.Query().Get("marker")
+
+	result, list, err := gateway.list(ctx, req, marker)
+	if err != nil {
+		return err
+	}
+
+	result.Marker = &marker
+	if list.truncated {
+		result.NextMarker = list.encodeKeyFn(list.lastKey)
+	}
+
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// list lists the objects of the bucket of req after the key after.
+//
+// The paths are encrypted, so the objects are listed in the order of their
+// encrypted paths. The prefixes are listed up to their last slash and the
+// rest of the prefix filters the listed objects, so the keys of a page may
+// not be sorted and after must be a key returned by a previous listing.
+func (gateway *Gateway) list(ctx context.Context, req *request, after string) (_ *listBucketResult, _ *listing, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	query := req.URL.Query()
+
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+	if delimiter != "" && delimiter != "/" {
+		return nil, nil, errNotImplemented.withMessage("Only the '/' delimiter is supported.")
+	}
+
+	limit := maxKeys
+	if value := query.Get("max-keys"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, nil, errInvalidArgument.withMessage("Argument max-keys must be an integer between 0 and 2147483647.")
+		}
+		if limit > maxKeys {
+			limit = maxKeys
+		}
+	}
+
+	list := &listing{encodeKeyFn: func(key string) string { return key }}
+	switch encoding := query.Get("encoding-type"); encoding {
+	case "":
+	case "url":
+		list.encodeKeyFn = func(key string) string {
+			return strings.Replace(url.QueryEscape(key), "+", "%20", -1)
+		}
+	default:
+		return nil, nil, errInvalidArgument.withMessage("Invalid Encoding Method specified in Request.")
+	}
+
+	result := &listBucketResult{
+		Name:         req.bucket,
+		Prefix:       list.encodeKeyFn(prefix),
+		Delimiter:    list.encodeKeyFn(delimiter),
+		MaxKeys:      limit,
+		EncodingType: query.Get("encoding-type"),
+	}
+
+	dirPrefix := prefix[:strings.LastIndexByte(prefix, '/')+1]
+	namePrefix := prefix[len(dirPrefix):]
+
+	if after != "" && !strings.HasPrefix(after, dirPrefix) {
+		return nil, nil, errInvalidArgument.withMessage("The continuation token doesn't match the prefix.")
+	}
+
+	if limit > 0 {
+		bucket, err := gateway.openBucket(ctx, req)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+		opts := libuplink.ListOptions{
+			Prefix:    dirPrefix,
+			Cursor:    strings.TrimPrefix(after, dirPrefix),
+			Direction: storj.After,
+			Recursive: delimiter == "",
+			Limit:     limit,
+		}
+
+	pages:
+		for {
+			page, err := bucket.ListObjects(ctx, &opts)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, item := range page.Items {
+				if len(list.objects)+len(list.prefixes) == limit {
+					list.truncated = true
+					break pages
+				}
+				opts.Cursor = item.Path
+				list.lastKey = dirPrefix + item.Path
+
+				if !strings.HasPrefix(item.Path, namePrefix) {
+					continue
+				}
+
+				if item.IsPrefix {
+					list.prefixes = append(list.prefixes, commonPrefix{
+						Prefix: list.encodeKeyFn(dirPrefix + item.Path),
+					})
+					continue
+				}
+				list.objects = append(list.objects, objectEntry{
+					Key:          list.encodeKeyFn(dirPrefix + item.Path),
+					LastModified: item.Modified.UTC().Format(timeFormat),
+					ETag:         etag(item.Metadata, item.Path, item.Modified),
+					Size:         item.Size,
+					StorageClass: "STANDARD",
+				})
+			}
+
+			if !page.More || len(page.Items) == 0 {
+				break
+			}
+		}
+	}
+
+	result.IsTruncated = list.truncated
+	result.Contents = list.objects
+	result.CommonPrefixes = list.prefixes
+	return result, list, nil
+}
+
+// etag returns the ETag of an object. The ETag of an object uploaded by the
+// gateway is the ETag computed at the upload, the ETag of the other objects
+// is derived from their path and modification time.
+func etag(metadata map[string]string, path string, modified time.Time) string {
+	if value, ok := metadata[etagKey]; ok {
+		return strconv.Quote(value)
+	}
+	return strconv.Quote(md5Hex([]byte(path+"\x00"+modified.UTC().Format(time.RFC3339Nano))) + "-1")
+}
@@ -0,0 +1,13 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package gateway implements a subset of the S3 REST API on top of a project.
+//
+// The requests are authenticated with AWS Signature Version 4, in the
+// Authorization header or in the query of a presigned URL. Every credential
+// maps to an access, whose secret key is derived from the access, so that the
+// signatures are verified locally. Only path-style requests are supported.
+//
+// Objects are uploaded as segmented uploads: every part of a multipart upload
+// is stored as a segment of the object.
+package gateway
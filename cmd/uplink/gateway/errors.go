@@ -0,0 +1,117 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gateway
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+)
+
+// apiError is an error returned to the client as an S3 error response.
+type apiError struct {
+	Code    string
+	Status  int
+	Message string
+}
+
+// Error returns the message of the error.
+func (err *apiError) Error() string {
+	return err.Code + ": " + err.Message
+}
+
+// withMessage returns a copy of the error with message.
+func (err *apiError) withMessage(message string) *apiError {
+	return &apiError{Code: err.Code, Status: err.Status, Message: message}
+}
+
+var (
+	errAccessDenied               = &apiError{"AccessDenied", http.StatusForbidden, "Access Denied."}
+	errInvalidAccessKeyID         = &apiError{"InvalidAccessKeyId", http.StatusForbidden, "The access key ID you provided does not exist in our records."}
+	errSignatureDoesNotMatch      = &apiError{"SignatureDoesNotMatch", http.StatusForbidden, "The request signature we calculated does not match the signature you provided."}
+	errRequestTimeTooSkewed       = &apiError{"RequestTimeTooSkewed", http.StatusForbidden, "The difference between the request time and the server's time is too large."}
+	errAuthorizationMalformed     = &apiError{"AuthorizationHeaderMalformed", http.StatusBadRequest, "The authorization header is malformed."}
+	errAuthorizationQueryMissing  = &apiError{"AuthorizationQueryParametersError", http.StatusBadRequest, "The authorization query parameters are malformed."}
+	errMissingSecurityHeader      = &apiError{"MissingSecurityHeader", http.StatusBadRequest, "Your request is missing a required header."}
+	errContentSHA256Mismatch      = &apiError{"XAmzContentSHA256Mismatch", http.StatusBadRequest, "The provided 'x-amz-content-sha256' header does not match what was computed."}
+	errIncompleteBody             = &apiError{"IncompleteBody", http.StatusBadRequest, "You did not provide the number of bytes specified by the Content-Length HTTP header."}
+	errMissingContentLength       = &apiError{"MissingContentLength", http.StatusLengthRequired, "You must provide the Content-Length HTTP header."}
+	errInvalidArgument            = &apiError{"InvalidArgument", http.StatusBadRequest, "Invalid Argument."}
+	errInvalidRange               = &apiError{"InvalidRange", http.StatusRequestedRangeNotSatisfiable, "The requested range is not satisfiable."}
+	errMalformedXML               = &apiError{"MalformedXML", http.StatusBadRequest, "The XML you provided was not well-formed or did not validate against our published schema."}
+	errInvalidPart                = &apiError{"InvalidPart", http.StatusBadRequest, "One or more of the specified parts could not be found."}
+	errInvalidPartOrder           = &apiError{"InvalidPartOrder", http.StatusBadRequest, "The list of parts was not in ascending order."}
+	errNoSuchBucket               = &apiError{"NoSuchBucket", http.StatusNotFound, "The specified bucket does not exist."}
+	errNoSuchKey                  = &apiError{"NoSuchKey", http.StatusNotFound, "The specified key does not exist."}
+	errNoSuchUpload               = &apiError{"NoSuchUpload", http.StatusNotFound, "The specified multipart upload does not exist."}
+	errNotImplemented             = &apiError{"NotImplemented", http.StatusNotImplemented, "A header or query you provided implies functionality that is not implemented."}
+	errMethodNotAllowed           = &apiError{"MethodNotAllowed", http.StatusMethodNotAllowed, "The specified method is not allowed against this resource."}
+	errInternalError              = &apiError{"InternalError", http.StatusInternalServerError, "We encountered an internal error. Please try again."}
+	errEntityTooLarge             = &apiError{"EntityTooLarge", http.StatusBadRequest, "Your proposed upload exceeds the maximum allowed object size."}
+	errInvalidPartNumberOrVersion = &apiError{"InvalidArgument", http.StatusBadRequest, "Part number must be an integer between 1 and 10000, inclusive."}
+)
+
+// errorResponse is the body of an S3 error response.
+type errorResponse struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource"`
+}
+
+// convertError converts the errors of the project and of the buckets to S3
+// errors.
+func convertError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case storj.ErrObjectNotFound.Has(err):
+		return errNoSuchKey
+	case storj.ErrBucketNotFound.Has(err):
+		return errNoSuchBucket
+	case errs2.IsRPC(err, rpcstatus.PermissionDenied), errs2.IsRPC(err, rpcstatus.Unauthenticated):
+		return errAccessDenied
+	default:
+		return err
+	}
+}
+
+// writeError writes err as an S3 error response.
+func (gateway *Gateway) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	apiErr, ok := convertError(err).(*apiError)
+	if !ok {
+		gateway.log.Error("request failed", zap.String("method", r.Method), zap.String("path", r.URL.Path), zap.Error(err))
+		apiErr = errInternalError
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(apiErr.Status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	writeXMLBody(w, errorResponse{
+		Code:     apiErr.Code,
+		Message:  apiErr.Message,
+		Resource: r.URL.Path,
+	})
+}
+
+// writeXML writes v as an XML response with status.
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	writeXMLBody(w, v)
+}
+
+// writeXMLBody writes v as the XML body of a response. The status is
+// already sent, so the errors can't be reported anymore.
+func writeXMLBody(w http.ResponseWriter, v interface{}) {
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(v)
+}
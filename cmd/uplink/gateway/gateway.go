@@ -0,0 +1,306 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the error class of the gateway.
+	Error = errs.Class("gateway")
+)
+
+// secretKeyLength is the length of the derived secret keys.
+const secretKeyLength = 40
+
+// Credential maps an access key ID to an access.
+type Credential struct {
+	// AccessKeyID is the access key ID used by the clients, the name of the
+	// access.
+	AccessKeyID string
+	// SecretKey is the secret key used by the clients to sign the requests.
+	SecretKey string
+	// Access is the access of the requests signed with the credential.
+	Access *libuplink.Scope
+}
+
+// NewCredential returns the credential of the access named name. The secret
+// key is derived from the serialized access, so it stays the same as long as
+// the access does.
+func NewCredential(name string, access *libuplink.Scope) (Credential, error) {
+	serialized, err := access.Serialize()
+	if err != nil {
+		return Credential{}, Error.Wrap(err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(serialized))
+	_, _ = mac.Write([]byte("uplink gateway secret key"))
+
+	return Credential{
+		AccessKeyID: name,
+		SecretKey:   hex.EncodeToString(mac.Sum(nil))[:secretKeyLength],
+		Access:      access,
+	}, nil
+}
+
+// Config is the configuration of the gateway.
+type Config struct {
+	// Region is the region expected in the signatures. Any region is
+	// accepted when empty.
+	Region string
+	// StateDir is the directory where the state of the multipart uploads is
+	// kept.
+	StateDir string
+}
+
+// Gateway serves the S3 requests.
+type Gateway struct {
+	log         *zap.Logger
+	uplink      *libuplink.Uplink
+	config      Config
+	credentials map[string]Credential
+
+	mu       sync.Mutex
+	projects map[string]*libuplink.Project
+
+	// uploadsMu protects the state files of the multipart uploads.
+	uploadsMu sync.Mutex
+}
+
+// New returns a gateway serving the requests signed with credentials.
+func New(log *zap.Logger, uplink *libuplink.Uplink, credentials []Credential, config Config) *Gateway {
+	gateway := &Gateway{
+		log:         log,
+		uplink:      uplink,
+		config:      config,
+		credentials: make(map[string]Credential, len(credentials)),
+		projects:    make(map[string]*libuplink.Project),
+	}
+	for _, credential := range credentials {
+		gateway.credentials[credential.AccessKeyID] = credential
+	}
+	return gateway
+}
+
+// request is an authenticated request.
+type request struct {
+	*http.Request
+
+	credential Credential
+	bucket     string
+	key        string
+
+	// body is the verified body of the request and size its size, or -1
+	// when unknown.
+	body io.Reader
+	size int64
+}
+
+// ServeHTTP serves an S3 request.
+func (gateway *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	req, err := gateway.authenticate(r)
+	if err != nil {
+		gateway.writeError(w, r, err)
+		return
+	}
+
+	err = gateway.route(ctx, w, req)
+	if err != nil {
+		gateway.writeError(w, r, err)
+	}
+}
+
+// authenticate verifies the signature of r.
+func (gateway *Gateway) authenticate(r *http.Request) (_ *request, err error) {
+	sig, err := parseSignature(r, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	credential, ok := gateway.credentials[sig.accessKey]
+	if !ok {
+		return nil, errInvalidAccessKeyID
+	}
+	if gateway.config.Region != "" && sig.region != gateway.config.Region {
+		return nil, errAuthorizationMalformed.withMessage("The authorization region '" + sig.region + "' is wrong; expecting '" + gateway.config.Region + "'.")
+	}
+	if err := sig.verify(r, credential.SecretKey); err != nil {
+		return nil, err
+	}
+
+	body, err := sig.payloadReader(r, credential.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	size := r.ContentLength
+	if decoded := r.Header.Get("X-Amz-Decoded-Content-Length"); decoded != "" {
+		size, err = parseSize(decoded)
+		if err != nil {
+			return nil, errInvalidArgument.withMessage("The x-amz-decoded-content-length header is invalid.")
+		}
+	} else if sig.payloadHash == streamingPayload {
+		size = -1
+	}
+
+	bucket, key := splitPath(r.URL.Path)
+	return &request{
+		Request:    r,
+		credential: credential,
+		bucket:     bucket,
+		key:        key,
+		body:       body,
+		size:       size,
+	}, nil
+}
+
+// splitPath splits the path of a path-style request into the bucket and the
+// key of the object.
+func splitPath(path string) (bucket, key string) {
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return path, ""
+}
+
+// route serves req with the handler of its operation.
+func (gateway *Gateway) route(ctx context.Context, w http.ResponseWriter, req *request) error {
+	query := req.URL.Query()
+
+	switch {
+	case req.bucket == "":
+		if req.Method != http.MethodGet {
+			return errMethodNotAllowed
+		}
+		return gateway.listBuckets(ctx, w, req)
+
+	case req.key == "":
+		switch {
+		case req.Method == http.MethodGet && has(query, "location"):
+			return gateway.getBucketLocation(ctx, w, req)
+		case req.Method == http.MethodGet && hasAny(query, "acl", "cors", "lifecycle", "policy", "tagging", "uploads", "versioning", "versions", "website"):
+			return errNotImplemented
+		case req.Method == http.MethodGet && query.Get("list-type") == "2":
+			return gateway.listObjectsV2(ctx, w, req)
+		case req.Method == http.MethodGet:
+			return gateway.listObjects(ctx, w, req)
+		case req.Method == http.MethodHead:
+			return gateway.headBucket(ctx, w, req)
+		case req.Method == http.MethodPut, req.Method == http.MethodDelete, req.Method == http.MethodPost:
+			return errNotImplemented
+		}
+		return errMethodNotAllowed
+
+	default:
+		switch {
+		case hasAny(query, "acl", "tagging", "retention", "legal-hold", "torrent"):
+			return errNotImplemented
+		case req.Method == http.MethodGet && has(query, "uploadId"):
+			return errNotImplemented
+		case req.Method == http.MethodGet:
+			return gateway.getObject(ctx, w, req)
+		case req.Method == http.MethodHead:
+			return gateway.headObject(ctx, w, req)
+		case req.Method == http.MethodPut && req.Header.Get("X-Amz-Copy-Source") != "":
+			return errNotImplemented
+		case req.Method == http.MethodPut && has(query, "uploadId"):
+			return gateway.uploadPart(ctx, w, req)
+		case req.Method == http.MethodPut:
+			return gateway.putObject(ctx, w, req)
+		case req.Method == http.MethodPost && has(query, "uploads"):
+			return gateway.createMultipartUpload(ctx, w, req)
+		case req.Method == http.MethodPost && has(query, "uploadId"):
+			return gateway.completeMultipartUpload(ctx, w, req)
+		case req.Method == http.MethodDelete && has(query, "uploadId"):
+			return gateway.abortMultipartUpload(ctx, w, req)
+		case req.Method == http.MethodDelete:
+			return gateway.deleteObject(ctx, w, req)
+		case req.Method == http.MethodPost:
+			return errNotImplemented
+		}
+		return errMethodNotAllowed
+	}
+}
+
+// has returns whether the query has the parameter name, even without a value.
+func has(query map[string][]string, name string) bool {
+	_, ok := query[name]
+	return ok
+}
+
+// hasAny returns whether the query has any of the parameters names.
+func hasAny(query map[string][]string, names ...string) bool {
+	for _, name := range names {
+		if has(query, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// project returns the project of the access of credential. The projects are
+// opened once and kept open until the gateway is closed.
+func (gateway *Gateway) project(ctx context.Context, credential Credential) (_ *libuplink.Project, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+
+	if project, ok := gateway.projects[credential.AccessKeyID]; ok {
+		return project, nil
+	}
+
+	project, err := gateway.uplink.OpenProject(ctx, credential.Access.SatelliteAddr, credential.Access.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	gateway.projects[credential.AccessKeyID] = project
+	return project, nil
+}
+
+// openBucket opens the bucket of req.
+func (gateway *Gateway) openBucket(ctx context.Context, req *request) (_ *libuplink.Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	project, err := gateway.project(ctx, req.credential)
+	if err != nil {
+		return nil, err
+	}
+	return project.OpenBucket(ctx, req.bucket, req.credential.Access.EncryptionAccess)
+}
+
+// Close closes the projects opened by the gateway.
+func (gateway *Gateway) Close() error {
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+
+	var group errs.Group
+	for name, project := range gateway.projects {
+		group.Add(project.Close())
+		delete(gateway.projects, name)
+	}
+	return group.Err()
+}
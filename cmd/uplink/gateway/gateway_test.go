@@ -0,0 +1,274 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gateway
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+)
+
+func TestGateway(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+
+		require.NoError(t, client.CreateBucket(ctx, satellite, "bucket"))
+
+		access, err := client.GetConfig(satellite).GetAccess()
+		require.NoError(t, err)
+		credential, err := NewCredential("test", access)
+		require.NoError(t, err)
+
+		up, err := client.NewLibuplink(ctx)
+		require.NoError(t, err)
+		defer ctx.Check(up.Close)
+
+		gateway := New(zaptest.NewLogger(t), up, []Credential{credential}, Config{
+			Region:   "us-east-1",
+			StateDir: ctx.Dir("gateway"),
+		})
+		defer ctx.Check(gateway.Close)
+
+		server := httptest.NewServer(gateway)
+		defer server.Close()
+
+		do := func(method, path string, body []byte, header http.Header) (*http.Response, []byte) {
+			r, err := http.NewRequest(method, server.URL+path, bytes.NewReader(body))
+			require.NoError(t, err)
+			for name, values := range header {
+				r.Header[name] = values
+			}
+			sign(r, credential, body)
+
+			response, err := http.DefaultClient.Do(r)
+			require.NoError(t, err)
+			data, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			require.NoError(t, response.Body.Close())
+			return response, data
+		}
+
+		{ // the buckets are listed
+			response, body := do(http.MethodGet, "/", nil, nil)
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+			require.Contains(t, string(body), "<Name>bucket</Name>")
+		}
+
+		expectedData := testrand.BytesInt(10 * memory.KiB.Int())
+
+		{ // an object is uploaded and downloaded
+			response, body := do(http.MethodPut, "/bucket/dir/object", expectedData, http.Header{
+				"Content-Type":   {"text/plain"},
+				"X-Amz-Meta-Key": {"value"},
+			})
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+			require.Equal(t, strconv.Quote(md5Hex(expectedData)), response.Header.Get("ETag"))
+
+			response, body = do(http.MethodGet, "/bucket/dir/object", nil, nil)
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+			require.Equal(t, expectedData, body)
+			require.Equal(t, "text/plain", response.Header.Get("Content-Type"))
+			require.Equal(t, "value", response.Header.Get("X-Amz-Meta-Key"))
+			require.Equal(t, strconv.Quote(md5Hex(expectedData)), response.Header.Get("ETag"))
+
+			response, body = do(http.MethodGet, "/bucket/dir/object", nil, http.Header{"Range": {"bytes=100-199"}})
+			require.Equal(t, http.StatusPartialContent, response.StatusCode, string(body))
+			require.Equal(t, expectedData[100:200], body)
+			require.Equal(t, fmt.Sprintf("bytes 100-199/%d", len(expectedData)), response.Header.Get("Content-Range"))
+
+			response, _ = do(http.MethodHead, "/bucket/dir/object", nil, nil)
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			require.Equal(t, strconv.Itoa(len(expectedData)), response.Header.Get("Content-Length"))
+		}
+
+		{ // the objects are listed
+			response, body := do(http.MethodGet, "/bucket?list-type=2&delimiter=%2F", nil, nil)
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+
+			var result listBucketResult
+			require.NoError(t, xml.Unmarshal(body, &result))
+			require.Empty(t, result.Contents)
+			require.Equal(t, []commonPrefix{{Prefix: "dir/"}}, result.CommonPrefixes)
+
+			response, body = do(http.MethodGet, "/bucket?list-type=2&prefix=dir%2Fobj", nil, nil)
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+
+			result = listBucketResult{}
+			require.NoError(t, xml.Unmarshal(body, &result))
+			require.Len(t, result.Contents, 1)
+			require.Equal(t, "dir/object", result.Contents[0].Key)
+			require.EqualValues(t, len(expectedData), result.Contents[0].Size)
+		}
+
+		{ // an object is uploaded in parts
+			response, body := do(http.MethodPost, "/bucket/multipart?uploads", nil, http.Header{"Content-Type": {"text/plain"}})
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+
+			var initiated initiateMultipartUploadResult
+			require.NoError(t, xml.Unmarshal(body, &initiated))
+			uploadID := initiated.UploadID
+
+			partSize := 4 * memory.KiB.Int()
+			var complete strings.Builder
+			complete.WriteString("<CompleteMultipartUpload>")
+			for i := 0; i*partSize < len(expectedData); i++ {
+				end := (i + 1) * partSize
+				if end > len(expectedData) {
+					end = len(expectedData)
+				}
+
+				response, body := do(http.MethodPut, fmt.Sprintf("/bucket/multipart?partNumber=%d&uploadId=%s", i+1, uploadID), expectedData[i*partSize:end], nil)
+				require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+				fmt.Fprintf(&complete, "<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", i+1, response.Header.Get("ETag"))
+			}
+			complete.WriteString("</CompleteMultipartUpload>")
+
+			response, body = do(http.MethodPost, "/bucket/multipart?uploadId="+uploadID, []byte(complete.String()), nil)
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+
+			response, body = do(http.MethodGet, "/bucket/multipart", nil, nil)
+			require.Equal(t, http.StatusOK, response.StatusCode, string(body))
+			require.Equal(t, expectedData, body)
+			require.Equal(t, "text/plain", response.Header.Get("Content-Type"))
+			require.True(t, strings.HasSuffix(response.Header.Get("ETag"), `-3"`))
+
+			// the upload doesn't exist anymore
+			response, body = do(http.MethodDelete, "/bucket/multipart?uploadId="+uploadID, nil, nil)
+			require.Equal(t, http.StatusNotFound, response.StatusCode, string(body))
+		}
+
+		{ // a presigned URL downloads the object
+			r, err := http.NewRequest(http.MethodGet, server.URL+"/bucket/dir/object", nil)
+			require.NoError(t, err)
+			presign(r, credential, time.Hour)
+
+			response, err := http.DefaultClient.Do(r)
+			require.NoError(t, err)
+			data, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			require.NoError(t, response.Body.Close())
+			require.Equal(t, http.StatusOK, response.StatusCode, string(data))
+			require.Equal(t, expectedData, data)
+		}
+
+		{ // the requests signed with a wrong secret key are denied
+			r, err := http.NewRequest(http.MethodGet, server.URL+"/bucket/dir/object", nil)
+			require.NoError(t, err)
+			sign(r, Credential{AccessKeyID: credential.AccessKeyID, SecretKey: "wrong"}, nil)
+
+			response, err := http.DefaultClient.Do(r)
+			require.NoError(t, err)
+			require.NoError(t, response.Body.Close())
+			require.Equal(t, http.StatusForbidden, response.StatusCode)
+		}
+
+		{ // the objects are deleted
+			response, body := do(http.MethodDelete, "/bucket/dir/object", nil, nil)
+			require.Equal(t, http.StatusNoContent, response.StatusCode, string(body))
+
+			response, body = do(http.MethodGet, "/bucket/dir/object", nil, nil)
+			require.Equal(t, http.StatusNotFound, response.StatusCode, string(body))
+			require.Contains(t, string(body), "NoSuchKey")
+
+			// deleting a missing object succeeds
+			response, body = do(http.MethodDelete, "/bucket/dir/object", nil, nil)
+			require.Equal(t, http.StatusNoContent, response.StatusCode, string(body))
+		}
+	})
+}
+
+func TestGatewayAuthentication(t *testing.T) {
+	credential := Credential{AccessKeyID: "test", SecretKey: "secret"}
+	gateway := New(zaptest.NewLogger(t), nil, []Credential{credential}, Config{Region: "us-east-1"})
+
+	for _, tt := range []struct {
+		name       string
+		credential *Credential
+		method     string
+		status     int
+		code       string
+	}{
+		{name: "unsigned", credential: nil, method: http.MethodGet, status: http.StatusForbidden, code: "AccessDenied"},
+		{name: "unknown access key", credential: &Credential{AccessKeyID: "unknown", SecretKey: "secret"}, method: http.MethodGet, status: http.StatusForbidden, code: "InvalidAccessKeyId"},
+		{name: "wrong secret key", credential: &Credential{AccessKeyID: "test", SecretKey: "wrong"}, method: http.MethodGet, status: http.StatusForbidden, code: "SignatureDoesNotMatch"},
+		{name: "unsupported method", credential: &credential, method: http.MethodPost, status: http.StatusMethodNotAllowed, code: "MethodNotAllowed"},
+	} {
+		r := httptest.NewRequest(tt.method, "http://localhost:7777/", nil)
+		if tt.credential != nil {
+			sign(r, *tt.credential, nil)
+		}
+
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, r)
+		require.Equal(t, tt.status, w.Code, tt.name)
+
+		var response errorResponse
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &response), tt.name)
+		require.Equal(t, tt.code, response.Code, tt.name)
+	}
+}
+
+// sign signs r with credential in the Authorization header.
+func sign(r *http.Request, credential Credential, body []byte) {
+	sig := &signature{
+		accessKey:     credential.AccessKeyID,
+		date:          time.Now().UTC(),
+		region:        "us-east-1",
+		signedHeaders: []string{"host", "x-amz-content-sha256", "x-amz-date"},
+		payloadHash:   sha256Hex(body),
+	}
+
+	if r.Host == "" {
+		r.Host = r.URL.Host
+	}
+	r.Header.Set("X-Amz-Date", sig.date.Format(amzDateFormat))
+	r.Header.Set("X-Amz-Content-Sha256", sig.payloadHash)
+	sig.signature = sig.compute(r, credential.SecretKey)
+	r.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		signV4Algorithm, sig.accessKey, sig.scope(), strings.Join(sig.signedHeaders, ";"), sig.signature))
+}
+
+// presign presigns the URL of r with credential.
+func presign(r *http.Request, credential Credential, expires time.Duration) {
+	sig := &signature{
+		accessKey:     credential.AccessKeyID,
+		date:          time.Now().UTC(),
+		region:        "us-east-1",
+		signedHeaders: []string{"host"},
+		payloadHash:   unsignedPayload,
+		presigned:     true,
+	}
+
+	if r.Host == "" {
+		r.Host = r.URL.Host
+	}
+	query := r.URL.Query()
+	query.Set("X-Amz-Algorithm", signV4Algorithm)
+	query.Set("X-Amz-Credential", sig.accessKey+"/"+sig.scope())
+	query.Set("X-Amz-Date", sig.date.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	r.URL.RawQuery = query.Encode()
+
+	query.Set("X-Amz-Signature", sig.compute(r, credential.SecretKey))
+	r.URL.RawQuery = query.Encode()
+}
@@ -0,0 +1,359 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gateway
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+// maxPartNumber is the maximum part number of a multipart upload.
+const maxPartNumber = 10000
+
+// multipartUpload is the state of a multipart upload, kept in the state
+// directory until the upload is completed or aborted.
+type multipartUpload struct {
+	Bucket      string            `json:"bucket"`
+	Key         string            `json:"key"`
+	AccessKey   string            `json:"accessKey"`
+	StreamID    storj.StreamID    `json:"streamID"`
+	ContentType string            `json:"contentType"`
+	Metadata    map[string]string `json:"metadata"`
+	Initiated   time.Time         `json:"initiated"`
+
+	// Parts are the uploaded parts by part number.
+	Parts map[int]uploadedPart `json:"parts"`
+}
+
+// uploadedPart is an uploaded part of a multipart upload.
+type uploadedPart struct {
+	libuplink.MultipartPart
+	ETag string `json:"etag"`
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUploadResult"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+// createMultipartUpload serves the CreateMultipartUpload operation.
+func (gateway *Gateway) createMultipartUpload(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucket, err := gateway.openBucket(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	streamID, err := bucket.BeginMultipartUpload(ctx, req.key, nil)
+	if err != nil {
+		return err
+	}
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return Error.Wrap(err)
+	}
+	uploadID := hex.EncodeToString(id[:])
+
+	opts := uploadOptions(req)
+	upload := &multipartUpload{
+		Bucket:      req.bucket,
+		Key:         req.key,
+		AccessKey:   req.credential.AccessKeyID,
+		StreamID:    streamID,
+		ContentType: opts.ContentType,
+		Metadata:    opts.Metadata,
+		Initiated:   time.Now(),
+		Parts:       map[int]uploadedPart{},
+	}
+
+	gateway.uploadsMu.Lock()
+	err = gateway.saveUpload(uploadID, upload)
+	gateway.uploadsMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{
+		Bucket:   req.bucket,
+		Key:      req.key,
+		UploadID: uploadID,
+	})
+	return nil
+}
+
+// uploadPart serves the UploadPart operation. The part is uploaded as the
+// segment of the object at the index of the part number.
+func (gateway *Gateway) uploadPart(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	query := req.URL.Query()
+	partNumber, err := strconv.Atoi(query.Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > maxPartNumber {
+		return errInvalidPartNumberOrVersion
+	}
+	if req.size < 0 {
+		return errMissingContentLength
+	}
+
+	uploadID := query.Get("uploadId")
+	gateway.uploadsMu.Lock()
+	upload, err := gateway.loadUpload(uploadID, req)
+	gateway.uploadsMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	bucket, err := gateway.openBucket(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	if req.size > bucket.Volatile.SegmentsSize.Int64() {
+		return errEntityTooLarge.withMessage("The part size can't exceed the segment size " + bucket.Volatile.SegmentsSize.String() + ".")
+	}
+
+	hash := md5.New()
+	body := &bodyReader{r: io.TeeReader(req.body, hash)}
+
+	part, err := bucket.UploadPart(ctx, req.key, upload.StreamID, int64(partNumber-1), body, req.size)
+	if err != nil {
+		return body.wrapError(err)
+	}
+	if err := drain(body); err != nil {
+		return err
+	}
+	etag := hex.EncodeToString(hash.Sum(nil))
+
+	gateway.uploadsMu.Lock()
+	defer gateway.uploadsMu.Unlock()
+
+	// the upload is loaded again, the other parts may have been uploaded
+	// concurrently
+	upload, err = gateway.loadUpload(uploadID, req)
+	if err != nil {
+		return err
+	}
+	upload.Parts[partNumber] = uploadedPart{MultipartPart: part, ETag: etag}
+	if err := gateway.saveUpload(uploadID, upload); err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", strconv.Quote(etag))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// completeMultipartUpload serves the CompleteMultipartUpload operation. The
+// object is committed with the listed parts, which must have the same size
+// except the last.
+func (gateway *Gateway) completeMultipartUpload(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var complete completeMultipartUpload
+	if err := xml.NewDecoder(req.body).Decode(&complete); err != nil {
+		return errMalformedXML
+	}
+	if err := drain(req.body); err != nil {
+		return err
+	}
+	if len(complete.Parts) == 0 {
+		return errMalformedXML.withMessage("You must specify at least one part.")
+	}
+
+	uploadID := req.URL.Query().Get("uploadId")
+	gateway.uploadsMu.Lock()
+	upload, err := gateway.loadUpload(uploadID, req)
+	gateway.uploadsMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	hash := md5.New()
+	var parts []libuplink.MultipartPart
+	for i, completed := range complete.Parts {
+		if i > 0 && completed.PartNumber <= complete.Parts[i-1].PartNumber {
+			return errInvalidPartOrder
+		}
+		part, ok := upload.Parts[completed.PartNumber]
+		if !ok || strings.Trim(completed.ETag, `"`) != part.ETag {
+			return errInvalidPart
+		}
+		if part.Index != int64(i) {
+			return errInvalidPart.withMessage("The parts must be numbered consecutively from 1.")
+		}
+		last := i == len(complete.Parts)-1
+		if i > 0 && (part.Size > parts[0].Size || !last && part.Size != parts[0].Size) {
+			return errInvalidPart.withMessage("The parts, except the last, must have the same size.")
+		}
+
+		sum, err := hex.DecodeString(part.ETag)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		_, _ = hash.Write(sum)
+		parts = append(parts, part.MultipartPart)
+	}
+
+	etag := hex.EncodeToString(hash.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+
+	metadata := make(map[string]string, len(upload.Metadata)+1)
+	for key, value := range upload.Metadata {
+		metadata[key] = value
+	}
+	metadata[etagKey] = etag
+
+	bucket, err := gateway.openBucket(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	err = bucket.CommitMultipartUpload(ctx, req.key, upload.StreamID, parts, &libuplink.UploadOptions{
+		ContentType: upload.ContentType,
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	gateway.uploadsMu.Lock()
+	err = gateway.removeUpload(uploadID)
+	gateway.uploadsMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Location: "/" + req.bucket + "/" + req.key,
+		Bucket:   req.bucket,
+		Key:      req.key,
+		ETag:     strconv.Quote(etag),
+	})
+	return nil
+}
+
+// abortMultipartUpload serves the AbortMultipartUpload operation. Only the
+// state of the upload is removed, the uploaded parts are uncommitted segments
+// deleted by the satellite.
+func (gateway *Gateway) abortMultipartUpload(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	uploadID := req.URL.Query().Get("uploadId")
+
+	gateway.uploadsMu.Lock()
+	defer gateway.uploadsMu.Unlock()
+
+	if _, err := gateway.loadUpload(uploadID, req); err != nil {
+		return err
+	}
+	if err := gateway.removeUpload(uploadID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// uploadPath returns the path of the state file of the upload uploadID. The
+// ID is hashed, so that it's never interpreted as a path.
+func (gateway *Gateway) uploadPath(uploadID string) string {
+	hash := sha256.Sum256([]byte(uploadID))
+	return filepath.Join(gateway.config.StateDir, hex.EncodeToString(hash[:])+".json")
+}
+
+// loadUpload loads the state of the upload uploadID of the object of req.
+// The uploads of the other objects or of the other accesses don't exist for
+// req. It must be called with uploadsMu held.
+func (gateway *Gateway) loadUpload(uploadID string, req *request) (*multipartUpload, error) {
+	data, err := ioutil.ReadFile(gateway.uploadPath(uploadID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errNoSuchUpload
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	upload := &multipartUpload{}
+	if err := json.Unmarshal(data, upload); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if upload.Bucket != req.bucket || upload.Key != req.key || upload.AccessKey != req.credential.AccessKeyID {
+		return nil, errNoSuchUpload
+	}
+	if upload.Parts == nil {
+		upload.Parts = map[int]uploadedPart{}
+	}
+	return upload, nil
+}
+
+// saveUpload saves the state of the upload uploadID. It must be called with
+// uploadsMu held.
+func (gateway *Gateway) saveUpload(uploadID string, upload *multipartUpload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if err := os.MkdirAll(gateway.config.StateDir, 0700); err != nil {
+		return Error.Wrap(err)
+	}
+
+	// the state is written to a temporary file first, so that it's never
+	// partially written
+	path := gateway.uploadPath(uploadID)
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(os.Rename(path+".tmp", path))
+}
+
+// removeUpload removes the state of the upload uploadID. It must be called
+// with uploadsMu held.
+func (gateway *Gateway) removeUpload(uploadID string) error {
+	err := os.Remove(gateway.uploadPath(uploadID))
+	if err != nil && !os.IsNotExist(err) {
+		return Error.Wrap(err)
+	}
+	return nil
+}
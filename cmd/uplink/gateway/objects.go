@@ -0,0 +1,354 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+const (
+	// etagKey is the metadata key of the ETag of the objects uploaded by the
+	// gateway.
+	etagKey = "s3:etag"
+	// contentTypeKey is the metadata key of the content type of an object.
+	contentTypeKey = "content-type"
+	// metadataHeaderPrefix is the prefix of the headers of the user metadata.
+	metadataHeaderPrefix = "X-Amz-Meta-"
+
+	defaultContentType = "binary/octet-stream"
+)
+
+// getObject serves the GetObject operation.
+func (gateway *Gateway) getObject(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucket, err := gateway.openBucket(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	object, err := bucket.OpenObject(ctx, req.key)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, object.Close()) }()
+
+	offset, length, err := parseRange(req.Header.Get("Range"), object.Meta.Size)
+	if err != nil {
+		return err
+	}
+
+	reader, err := object.DownloadRange(ctx, offset, length)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	writeObjectHeaders(w, object)
+	status := http.StatusOK
+	if req.Header.Get("Range") != "" {
+		w.Header().Set("Content-Range", "bytes "+strconv.FormatInt(offset, 10)+"-"+strconv.FormatInt(offset+length-1, 10)+"/"+strconv.FormatInt(object.Meta.Size, 10))
+		status = http.StatusPartialContent
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+	w.WriteHeader(status)
+
+	// the status is already sent, the errors can only be logged
+	if _, err := io.Copy(w, reader); err != nil {
+		gateway.log.Debug("download failed", zap.String("bucket", req.bucket), zap.String("key", req.key), zap.Error(err))
+	}
+	return nil
+}
+
+// headObject serves the HeadObject operation.
+func (gateway *Gateway) headObject(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucket, err := gateway.openBucket(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	object, err := bucket.OpenObject(ctx, req.key)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, object.Close()) }()
+
+	writeObjectHeaders(w, object)
+	w.Header().Set("Content-Length", strconv.FormatInt(object.Meta.Size, 10))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// writeObjectHeaders writes the headers of the metadata of object.
+func writeObjectHeaders(w http.ResponseWriter, object *libuplink.Object) {
+	header := w.Header()
+
+	contentType := object.Meta.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("ETag", etag(object.Meta.Metadata, object.Meta.Path, object.Meta.Modified))
+	header.Set("Last-Modified", object.Meta.Modified.UTC().Format(http.TimeFormat))
+	header.Set("Accept-Ranges", "bytes")
+
+	for key, value := range object.Meta.Metadata {
+		if key == etagKey || key == contentTypeKey {
+			continue
+		}
+		header.Set(metadataHeaderPrefix+key, value)
+	}
+}
+
+// parseRange parses the Range header of a request of an object of size
+// bytes. Only a single range is supported.
+func parseRange(value string, size int64) (offset, length int64, err error) {
+	if value == "" {
+		return 0, size, nil
+	}
+
+	invalid := errInvalidArgument.withMessage("The Range header is invalid.")
+	if !strings.HasPrefix(value, "bytes=") {
+		return 0, 0, invalid
+	}
+	value = strings.TrimPrefix(value, "bytes=")
+	if strings.Contains(value, ",") {
+		return 0, 0, errNotImplemented.withMessage("Multiple ranges are not supported.")
+	}
+
+	i := strings.IndexByte(value, '-')
+	if i < 0 {
+		return 0, 0, invalid
+	}
+	first, last := value[:i], value[i+1:]
+
+	switch {
+	case first == "" && last == "":
+		return 0, 0, invalid
+
+	case first == "":
+		// the last bytes of the object
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix < 0 {
+			return 0, 0, invalid
+		}
+		if suffix == 0 {
+			return 0, 0, errInvalidRange
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, suffix, nil
+
+	default:
+		start, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 {
+			return 0, 0, invalid
+		}
+		if start >= size {
+			return 0, 0, errInvalidRange
+		}
+
+		end := size - 1
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return 0, 0, invalid
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+		return start, end - start + 1, nil
+	}
+}
+
+// putObject serves the PutObject operation. The body is uploaded as a
+// multipart upload with parts of the segment size of the bucket, so that the
+// object is only committed once the whole body is verified.
+func (gateway *Gateway) putObject(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if req.size < 0 {
+		return errMissingContentLength
+	}
+
+	bucket, err := gateway.openBucket(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	hash := md5.New()
+	body := &bodyReader{r: io.TeeReader(req.body, hash)}
+	opts := uploadOptions(req)
+
+	if req.size == 0 {
+		if err := drain(body); err != nil {
+			return err
+		}
+		opts.Metadata[etagKey] = hex.EncodeToString(hash.Sum(nil))
+		if err := bucket.UploadObject(ctx, req.key, bytes.NewReader(nil), opts); err != nil {
+			return err
+		}
+		w.Header().Set("ETag", strconv.Quote(opts.Metadata[etagKey]))
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	streamID, err := bucket.BeginMultipartUpload(ctx, req.key, nil)
+	if err != nil {
+		return err
+	}
+
+	partSize := bucket.Volatile.SegmentsSize.Int64()
+	var parts []libuplink.MultipartPart
+	for offset := int64(0); offset < req.size; offset += partSize {
+		size := partSize
+		if remaining := req.size - offset; remaining < size {
+			size = remaining
+		}
+
+		part, err := bucket.UploadPart(ctx, req.key, streamID, int64(len(parts)), io.LimitReader(body, size), size)
+		if err != nil {
+			return body.wrapError(err)
+		}
+		parts = append(parts, part)
+	}
+
+	// reading the end of the body verifies its hash
+	if err := drain(body); err != nil {
+		return err
+	}
+
+	opts.Metadata[etagKey] = hex.EncodeToString(hash.Sum(nil))
+	if err := bucket.CommitMultipartUpload(ctx, req.key, streamID, parts, opts); err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", strconv.Quote(opts.Metadata[etagKey]))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// deleteObject serves the DeleteObject operation. Deleting an object that
+// doesn't exist succeeds, as with S3.
+func (gateway *Gateway) deleteObject(ctx context.Context, w http.ResponseWriter, req *request) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucket, err := gateway.openBucket(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, bucket.Close()) }()
+
+	if err := bucket.DeleteObject(ctx, req.key); err != nil && !storj.ErrObjectNotFound.Has(err) {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// uploadOptions returns the options of the upload of the object of req, with
+// the content type and the user metadata of its headers.
+func uploadOptions(req *request) *libuplink.UploadOptions {
+	opts := &libuplink.UploadOptions{
+		ContentType: req.Header.Get("Content-Type"),
+		Metadata:    metadataFromHeaders(req.Header),
+	}
+	if opts.Metadata == nil {
+		opts.Metadata = map[string]string{}
+	}
+	return opts
+}
+
+// metadataFromHeaders returns the user metadata of header, with lowercase
+// keys.
+func metadataFromHeaders(header http.Header) map[string]string {
+	metadata := map[string]string{}
+	for name, values := range header {
+		if !strings.HasPrefix(name, metadataHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+		metadata[strings.ToLower(strings.TrimPrefix(name, metadataHeaderPrefix))] = values[0]
+	}
+	return metadata
+}
+
+// drain reads the rest of body, which verifies its hash.
+func drain(body io.Reader) error {
+	n, err := io.Copy(ioutil.Discard, body)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return errInvalidArgument.withMessage("The body is larger than the Content-Length.")
+	}
+	return nil
+}
+
+// bodyReader records the error of the body of a request, so that it's
+// returned instead of the error of the upload that read the body.
+type bodyReader struct {
+	r   io.Reader
+	err error
+}
+
+// Read reads from r and records the errors, a body shorter than expected
+// being incomplete.
+func (body *bodyReader) Read(p []byte) (n int, err error) {
+	n, err = body.r.Read(p)
+	switch {
+	case err == nil:
+	case errs.Is(err, io.EOF), errs.Is(err, io.ErrUnexpectedEOF):
+		body.err = errIncompleteBody
+	default:
+		body.err = err
+	}
+	return n, err
+}
+
+// wrapError returns the error of the body, if any, or err.
+func (body *bodyReader) wrapError(err error) error {
+	if body.err != nil {
+		return body.err
+	}
+	return err
+}
+
+// parseSize parses a size in bytes.
+func parseSize(value string) (int64, error) {
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if size < 0 {
+		return 0, errs.New("negative size %d", size)
+	}
+	return size, nil
+}
+
+func md5Hex(data []byte) string {
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
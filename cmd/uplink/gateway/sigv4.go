@@ -0,0 +1,449 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signV4Algorithm      = "AWS4-HMAC-SHA256"
+	signV4ChunkAlgorithm = "AWS4-HMAC-SHA256-PAYLOAD"
+	signV4Request        = "aws4_request"
+	signV4Service        = "s3"
+
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	streamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	emptySHA256      = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	amzDateFormat   = "20060102T150405Z"
+	scopeDateFormat = "20060102"
+
+	// maxClockSkew is the maximum difference between the time of a signed
+	// request and the time of the gateway.
+	maxClockSkew = 15 * time.Minute
+	// maxPresignExpires is the maximum validity of a presigned URL.
+	maxPresignExpires = 7 * 24 * time.Hour
+	// maxChunkSize is the maximum size of a chunk of a streaming upload.
+	maxChunkSize = 16 << 20
+)
+
+// signature is the signature of a request.
+type signature struct {
+	accessKey     string
+	date          time.Time
+	region        string
+	signedHeaders []string
+	signature     string
+	payloadHash   string
+	presigned     bool
+}
+
+// scope returns the credential scope of the signature.
+func (sig *signature) scope() string {
+	return strings.Join([]string{sig.date.Format(scopeDateFormat), sig.region, signV4Service, signV4Request}, "/")
+}
+
+// parseSignature parses the signature of r, from the Authorization header or
+// from the query of a presigned URL.
+func parseSignature(r *http.Request, now time.Time) (*signature, error) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return parseAuthorization(r, auth, now)
+	}
+	if r.URL.Query().Get("X-Amz-Algorithm") != "" {
+		return parsePresigned(r, now)
+	}
+	return nil, errAccessDenied
+}
+
+// parseAuthorization parses the signature in the Authorization header.
+func parseAuthorization(r *http.Request, auth string, now time.Time) (*signature, error) {
+	if !strings.HasPrefix(auth, signV4Algorithm+" ") {
+		return nil, errAuthorizationMalformed.withMessage("Only the " + signV4Algorithm + " algorithm is supported.")
+	}
+
+	fields := map[string]string{}
+	for _, field := range strings.Split(strings.TrimPrefix(auth, signV4Algorithm+" "), ",") {
+		field = strings.TrimSpace(field)
+		i := strings.IndexByte(field, '=')
+		if i < 0 {
+			return nil, errAuthorizationMalformed
+		}
+		fields[field[:i]] = field[i+1:]
+	}
+
+	sig := &signature{
+		signature:   fields["Signature"],
+		payloadHash: r.Header.Get("X-Amz-Content-Sha256"),
+	}
+	if sig.payloadHash == "" {
+		return nil, errMissingSecurityHeader.withMessage("Missing required header for this request: x-amz-content-sha256.")
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if amzDate == "" {
+		amzDate = r.Header.Get("Date")
+	}
+	date, err := time.Parse(amzDateFormat, amzDate)
+	if err != nil {
+		date, err = http.ParseTime(amzDate)
+		if err != nil {
+			return nil, errAccessDenied.withMessage("AWS authentication requires a valid Date or x-amz-date header.")
+		}
+	}
+	sig.date = date.UTC()
+
+	if sig.date.Before(now.Add(-maxClockSkew)) || sig.date.After(now.Add(maxClockSkew)) {
+		return nil, errRequestTimeTooSkewed
+	}
+
+	if err := sig.parseCredential(fields["Credential"], errAuthorizationMalformed); err != nil {
+		return nil, err
+	}
+	if err := sig.parseSignedHeaders(fields["SignedHeaders"], errAuthorizationMalformed); err != nil {
+		return nil, err
+	}
+	if sig.signature == "" {
+		return nil, errAuthorizationMalformed
+	}
+	return sig, nil
+}
+
+// parsePresigned parses the signature in the query of a presigned URL.
+func parsePresigned(r *http.Request, now time.Time) (*signature, error) {
+	query := r.URL.Query()
+
+	if query.Get("X-Amz-Algorithm") != signV4Algorithm {
+		return nil, errAuthorizationQueryMissing.withMessage("X-Amz-Algorithm only supports \"" + signV4Algorithm + "\".")
+	}
+
+	sig := &signature{
+		signature:   query.Get("X-Amz-Signature"),
+		payloadHash: unsignedPayload,
+		presigned:   true,
+	}
+	if hash := query.Get("X-Amz-Content-Sha256"); hash != "" {
+		sig.payloadHash = hash
+	}
+
+	date, err := time.Parse(amzDateFormat, query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, errAuthorizationQueryMissing.withMessage("X-Amz-Date must be in the ISO8601 Long Format \"yyyyMMdd'T'HHmmss'Z'\".")
+	}
+	sig.date = date.UTC()
+
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires < 0 {
+		return nil, errAuthorizationQueryMissing.withMessage("X-Amz-Expires should be a number.")
+	}
+	if time.Duration(expires)*time.Second > maxPresignExpires {
+		return nil, errAuthorizationQueryMissing.withMessage("X-Amz-Expires must be less than a week (in seconds) that is 604800.")
+	}
+
+	if sig.date.After(now.Add(maxClockSkew)) {
+		return nil, errAccessDenied.withMessage("Request is not valid yet.")
+	}
+	if now.After(sig.date.Add(time.Duration(expires) * time.Second)) {
+		return nil, errAccessDenied.withMessage("Request has expired.")
+	}
+
+	if err := sig.parseCredential(query.Get("X-Amz-Credential"), errAuthorizationQueryMissing); err != nil {
+		return nil, err
+	}
+	if err := sig.parseSignedHeaders(query.Get("X-Amz-SignedHeaders"), errAuthorizationQueryMissing); err != nil {
+		return nil, err
+	}
+	if sig.signature == "" {
+		return nil, errAuthorizationQueryMissing
+	}
+	return sig, nil
+}
+
+// parseCredential parses the access key and the scope of the signature.
+func (sig *signature) parseCredential(credential string, malformed *apiError) error {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[0] == "" {
+		return malformed.withMessage("The credential is malformed.")
+	}
+	if parts[1] != sig.date.Format(scopeDateFormat) {
+		return malformed.withMessage("The credential date doesn't match the request date.")
+	}
+	if parts[3] != signV4Service || parts[4] != signV4Request {
+		return malformed.withMessage("The credential scope is malformed.")
+	}
+	sig.accessKey = parts[0]
+	sig.region = parts[2]
+	return nil
+}
+
+// parseSignedHeaders parses the names of the signed headers, which must
+// include the host.
+func (sig *signature) parseSignedHeaders(signedHeaders string, malformed *apiError) error {
+	if signedHeaders == "" {
+		return malformed.withMessage("The signed headers are missing.")
+	}
+	sig.signedHeaders = strings.Split(signedHeaders, ";")
+	for _, name := range sig.signedHeaders {
+		if name == "host" {
+			return nil
+		}
+	}
+	return malformed.withMessage("The host header must be signed.")
+}
+
+// verify verifies that the signature of r was computed with secretKey.
+func (sig *signature) verify(r *http.Request, secretKey string) error {
+	expected := sig.compute(r, secretKey)
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return errSignatureDoesNotMatch
+	}
+	return nil
+}
+
+// compute computes the signature of r with secretKey.
+func (sig *signature) compute(r *http.Request, secretKey string) string {
+	canonical := canonicalRequest(r, sig.signedHeaders, sig.payloadHash, sig.presigned)
+	stringToSign := strings.Join([]string{
+		signV4Algorithm,
+		sig.date.Format(amzDateFormat),
+		sig.scope(),
+		sha256Hex([]byte(canonical)),
+	}, "\n")
+	return hex.EncodeToString(hmacSHA256(sig.signingKey(secretKey), []byte(stringToSign)))
+}
+
+// signingKey derives the signing key of the scope of the signature.
+func (sig *signature) signingKey(secretKey string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), []byte(sig.date.Format(scopeDateFormat)))
+	key = hmacSHA256(key, []byte(sig.region))
+	key = hmacSHA256(key, []byte(signV4Service))
+	return hmacSHA256(key, []byte(signV4Request))
+}
+
+// canonicalRequest returns the canonical request of r.
+func canonicalRequest(r *http.Request, signedHeaders []string, payloadHash string, presigned bool) string {
+	var headers strings.Builder
+	for _, name := range signedHeaders {
+		headers.WriteString(name)
+		headers.WriteByte(':')
+		headers.WriteString(canonicalHeaderValue(r, name))
+		headers.WriteByte('\n')
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		uriEncode(r.URL.Path, false),
+		canonicalQuery(r.URL.Query(), presigned),
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+// canonicalHeaderValue returns the canonical value of the header name of r.
+func canonicalHeaderValue(r *http.Request, name string) string {
+	var values []string
+	switch name {
+	case "host":
+		values = []string{r.Host}
+	case "content-length":
+		values = r.Header.Values("Content-Length")
+		if len(values) == 0 && r.ContentLength >= 0 {
+			values = []string{strconv.FormatInt(r.ContentLength, 10)}
+		}
+	case "transfer-encoding":
+		values = r.TransferEncoding
+	default:
+		values = r.Header.Values(name)
+	}
+
+	for i, value := range values {
+		values[i] = strings.Join(strings.Fields(value), " ")
+	}
+	return strings.Join(values, ",")
+}
+
+// canonicalQuery returns the canonical query string. The signature is
+// excluded from the query of a presigned URL.
+func canonicalQuery(query url.Values, presigned bool) string {
+	var pairs []string
+	for key, values := range query {
+		if presigned && key == "X-Amz-Signature" {
+			continue
+		}
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes s as specified for the signatures: every byte, except the
+// unreserved characters and the slashes when encodeSlash isn't set, is
+// percent-encoded.
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			encoded.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			encoded.WriteByte('%')
+			encoded.WriteByte(hexDigits[c>>4])
+			encoded.WriteByte(hexDigits[c&15])
+		}
+	}
+	return encoded.String()
+}
+
+// payloadReader returns the reader of the payload of r, which verifies the
+// payload against the signature.
+func (sig *signature) payloadReader(r *http.Request, secretKey string) (io.Reader, error) {
+	switch sig.payloadHash {
+	case unsignedPayload:
+		return r.Body, nil
+	case streamingPayload:
+		if sig.presigned {
+			return nil, errInvalidArgument.withMessage("Streaming uploads can't be presigned.")
+		}
+		return &chunkedReader{
+			r:             bufio.NewReader(r.Body),
+			signingKey:    sig.signingKey(secretKey),
+			date:          sig.date.Format(amzDateFormat),
+			scope:         sig.scope(),
+			prevSignature: sig.signature,
+		}, nil
+	default:
+		expected, err := hex.DecodeString(sig.payloadHash)
+		if err != nil || len(expected) != sha256.Size {
+			return nil, errInvalidArgument.withMessage("The x-amz-content-sha256 header is invalid.")
+		}
+		return &hashingReader{r: r.Body, hash: sha256.New(), expected: expected}, nil
+	}
+}
+
+// hashingReader verifies the SHA-256 hash of the data read from r when it
+// reaches the end.
+type hashingReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	expected []byte
+}
+
+// Read reads from r and verifies the hash at the end.
+func (reader *hashingReader) Read(p []byte) (n int, err error) {
+	n, err = reader.r.Read(p)
+	_, _ = reader.hash.Write(p[:n])
+	if err == io.EOF && !bytes.Equal(reader.hash.Sum(nil), reader.expected) {
+		return n, errContentSHA256Mismatch
+	}
+	return n, err
+}
+
+// chunkedReader decodes the aws-chunked encoding of a streaming upload and
+// verifies the signature of every chunk.
+type chunkedReader struct {
+	r             *bufio.Reader
+	signingKey    []byte
+	date          string
+	scope         string
+	prevSignature string
+
+	chunk []byte
+	done  bool
+	err   error
+}
+
+// Read reads the data of the verified chunks.
+func (reader *chunkedReader) Read(p []byte) (n int, err error) {
+	for len(reader.chunk) == 0 {
+		if reader.err != nil {
+			return 0, reader.err
+		}
+		if reader.done {
+			return 0, io.EOF
+		}
+		reader.err = reader.readChunk()
+	}
+
+	n = copy(p, reader.chunk)
+	reader.chunk = reader.chunk[n:]
+	return n, nil
+}
+
+// readChunk reads and verifies the next chunk.
+func (reader *chunkedReader) readChunk() error {
+	header, err := reader.r.ReadString('\n')
+	if err != nil {
+		return errIncompleteBody
+	}
+	header = strings.TrimSuffix(strings.TrimSuffix(header, "\n"), "\r")
+
+	i := strings.Index(header, ";chunk-signature=")
+	if i < 0 {
+		return errInvalidArgument.withMessage("The chunk header is malformed.")
+	}
+	size, err := strconv.ParseInt(header[:i], 16, 64)
+	if err != nil || size < 0 || size > maxChunkSize {
+		return errInvalidArgument.withMessage("The chunk size is invalid.")
+	}
+	chunkSignature := header[i+len(";chunk-signature="):]
+
+	chunk := make([]byte, size+2)
+	if _, err := io.ReadFull(reader.r, chunk); err != nil {
+		return errIncompleteBody
+	}
+	if !bytes.HasSuffix(chunk, []byte("\r\n")) {
+		return errInvalidArgument.withMessage("The chunk is not terminated.")
+	}
+	chunk = chunk[:size]
+
+	stringToSign := strings.Join([]string{
+		signV4ChunkAlgorithm,
+		reader.date,
+		reader.scope,
+		reader.prevSignature,
+		emptySHA256,
+		sha256Hex(chunk),
+	}, "\n")
+	expected := hex.EncodeToString(hmacSHA256(reader.signingKey, []byte(stringToSign)))
+	if !hmac.Equal([]byte(expected), []byte(chunkSignature)) {
+		return errSignatureDoesNotMatch
+	}
+
+	reader.prevSignature = chunkSignature
+	reader.chunk = chunk
+	reader.done = size == 0
+	return nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
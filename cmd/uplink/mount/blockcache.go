@@ -0,0 +1,209 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+package mount
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+)
+
+// blockKey identifies a block of a version of an object.
+type blockKey struct {
+	path     storj.Path
+	modified int64
+	index    int64
+}
+
+// block is a block stored in the cache directory.
+type block struct {
+	key  blockKey
+	size int64
+}
+
+// blockCache keeps the downloaded blocks of the objects in files of a local
+// directory, evicting the least recently used blocks when their total size
+// exceeds the capacity.
+type blockCache struct {
+	dir       string
+	blockSize int64
+	capacity  int64
+
+	mu     sync.Mutex
+	used   int64
+	order  *list.List
+	blocks map[blockKey]*list.Element
+}
+
+// newBlockCache creates a cache of blocks of blockSize in dir.
+func newBlockCache(dir string, blockSize, capacity int64) *blockCache {
+	return &blockCache{
+		dir:       dir,
+		blockSize: blockSize,
+		capacity:  capacity,
+		order:     list.New(),
+		blocks:    make(map[blockKey]*list.Element),
+	}
+}
+
+// readAt reads the data of the object at path, of size bytes and modified at
+// modified, at offset into p. The blocks which aren't cached are downloaded
+// from bucket.
+func (cache *blockCache) readAt(ctx context.Context, bucket Bucket, path storj.Path, size int64, modified time.Time, p []byte, offset int64) (n int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if offset >= size {
+		return 0, nil
+	}
+	if remaining := size - offset; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	for n < len(p) {
+		index := (offset + int64(n)) / cache.blockSize
+		data, err := cache.get(ctx, bucket, blockKey{
+			path:     path,
+			modified: modified.UnixNano(),
+			index:    index,
+		}, size)
+		if err != nil {
+			return n, err
+		}
+
+		start := offset + int64(n) - index*cache.blockSize
+		if start >= int64(len(data)) {
+			return n, Error.New("block %d of %q is shorter than expected", index, path)
+		}
+		n += copy(p[n:], data[start:])
+	}
+	return n, nil
+}
+
+// get returns the data of the block at key, downloading it when it isn't
+// cached.
+func (cache *blockCache) get(ctx context.Context, bucket Bucket, key blockKey, objectSize int64) (data []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if data, ok := cache.load(key); ok {
+		return data, nil
+	}
+
+	offset := key.index * cache.blockSize
+	length := objectSize - offset
+	if length > cache.blockSize {
+		length = cache.blockSize
+	}
+
+	reader, err := bucket.DownloadRange(ctx, key.path, offset, length)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	data = make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	cache.store(key, data)
+	return data, nil
+}
+
+// load reads the block at key from the cache directory.
+func (cache *blockCache) load(key blockKey) ([]byte, bool) {
+	cache.mu.Lock()
+	element, ok := cache.blocks[key]
+	if ok {
+		cache.order.MoveToFront(element)
+	}
+	cache.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(cache.blockPath(key))
+	if err != nil {
+		cache.remove(key)
+		return nil, false
+	}
+	return data, true
+}
+
+// store writes the block at key into the cache directory. The block isn't
+// cached when it can't be written, the cache is only an optimization.
+func (cache *blockCache) store(key blockKey, data []byte) {
+	size := int64(len(data))
+	if size > cache.capacity {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if _, ok := cache.blocks[key]; ok {
+		return
+	}
+
+	for cache.used+size > cache.capacity {
+		cache.evict(cache.order.Back())
+	}
+
+	if err := ioutil.WriteFile(cache.blockPath(key), data, 0600); err != nil {
+		return
+	}
+
+	cache.blocks[key] = cache.order.PushFront(&block{key: key, size: size})
+	cache.used += size
+}
+
+// remove drops the block at key.
+func (cache *blockCache) remove(key blockKey) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if element, ok := cache.blocks[key]; ok {
+		cache.evict(element)
+	}
+}
+
+// invalidate drops the blocks of every version of the object at path.
+func (cache *blockCache) invalidate(path storj.Path) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for key, element := range cache.blocks {
+		if key.path == path {
+			cache.evict(element)
+		}
+	}
+}
+
+// evict drops the block of element. It must be called with the lock held.
+func (cache *blockCache) evict(element *list.Element) {
+	b := cache.order.Remove(element).(*block)
+	delete(cache.blocks, b.key)
+	cache.used -= b.size
+	_ = os.Remove(cache.blockPath(b.key))
+}
+
+// blockPath returns the path of the file of the block at key.
+func (cache *blockCache) blockPath(key blockKey) string {
+	hash := sha256.Sum256([]byte(key.path + "\x00" + strconv.FormatInt(key.modified, 10)))
+	return filepath.Join(cache.dir, hex.EncodeToString(hash[:])+"-"+strconv.FormatInt(key.index, 10))
+}
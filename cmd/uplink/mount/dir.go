@@ -0,0 +1,316 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+package mount
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+// Dir is a directory of the filesystem, the objects of the bucket with the
+// same prefix.
+type Dir struct {
+	fsys   *FS
+	prefix storj.Path
+}
+
+var (
+	_ fs.Node                = (*Dir)(nil)
+	_ fs.NodeRequestLookuper = (*Dir)(nil)
+	_ fs.HandleReadDirAller  = (*Dir)(nil)
+	_ fs.NodeCreater         = (*Dir)(nil)
+	_ fs.NodeMkdirer         = (*Dir)(nil)
+	_ fs.NodeRemover         = (*Dir)(nil)
+	_ fs.NodeRenamer         = (*Dir)(nil)
+)
+
+// Attr returns the attributes of the directory.
+func (dir *Dir) Attr(ctx context.Context, attr *fuse.Attr) error {
+	attr.Valid = dir.fsys.attrValid()
+	attr.Mode = os.ModeDir | 0755
+	attr.Uid = dir.fsys.config.UID
+	attr.Gid = dir.fsys.config.GID
+	return nil
+}
+
+// Lookup returns the file or the directory called req.Name in the directory.
+func (dir *Dir) Lookup(ctx context.Context, req *fuse.LookupRequest, resp *fuse.LookupResponse) (_ fs.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	resp.EntryValid = dir.fsys.attrValid()
+
+	entries, err := dir.fsys.list(ctx, dir.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := entries[req.Name]
+	if !ok {
+		return nil, fuse.ENOENT
+	}
+	if e.dir {
+		return dir.child(req.Name), nil
+	}
+
+	file := dir.fsys.openFile(dir.prefix+req.Name, e)
+	file.refresh(e)
+	return file, nil
+}
+
+// ReadDirAll returns the files and the directories in the directory.
+func (dir *Dir) ReadDirAll(ctx context.Context) (_ []fuse.Dirent, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entries, err := dir.fsys.list(ctx, dir.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	dirents := make([]fuse.Dirent, 0, len(entries))
+	for name, e := range entries {
+		dirent := fuse.Dirent{Name: name, Type: fuse.DT_File}
+		if e.dir {
+			dirent.Type = fuse.DT_Dir
+		}
+		dirents = append(dirents, dirent)
+	}
+	sort.Slice(dirents, func(i, k int) bool {
+		return dirents[i].Name < dirents[k].Name
+	})
+	return dirents, nil
+}
+
+// Create creates an empty file called req.Name in the directory and opens
+// it. The file is uploaded when it's closed.
+func (dir *Dir) Create(ctx context.Context, req *fuse.CreateRequest, resp *fuse.CreateResponse) (_ fs.Node, _ fs.Handle, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	resp.EntryValid = dir.fsys.attrValid()
+
+	file := dir.fsys.openFile(dir.prefix+req.Name, entry{modified: time.Now()})
+	handle, err := file.create()
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, handle, nil
+}
+
+// Mkdir creates the directory called req.Name in the directory. The
+// directory only exists locally until an object is stored in it.
+func (dir *Dir) Mkdir(ctx context.Context, req *fuse.MkdirRequest) (_ fs.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entries, err := dir.fsys.list(ctx, dir.prefix)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := entries[req.Name]; ok {
+		return nil, fuse.EEXIST
+	}
+
+	child := dir.child(req.Name)
+	dir.fsys.addDir(child.prefix)
+	return child, nil
+}
+
+// Remove deletes the file or the empty directory called req.Name in the
+// directory.
+func (dir *Dir) Remove(ctx context.Context, req *fuse.RemoveRequest) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	fsys := dir.fsys
+
+	if req.Dir {
+		prefix := dir.child(req.Name).prefix
+		entries, err := fsys.list(ctx, prefix)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return fuse.Errno(syscall.ENOTEMPTY)
+		}
+		fsys.removeDirs(prefix)
+		fsys.invalidate(prefix)
+		return nil
+	}
+
+	path := dir.prefix + req.Name
+
+	remote := true
+	if file, ok := fsys.trackedFile(path); ok {
+		remote = file.discard()
+	}
+	fsys.forgetFile(path)
+
+	err = fsys.bucket.DeleteObject(ctx, path)
+	if err != nil && (remote || !storj.ErrObjectNotFound.Has(err)) {
+		return convertError(err)
+	}
+
+	fsys.blocks.invalidate(path)
+	fsys.invalidate(path)
+	dir.keep()
+	return nil
+}
+
+// Rename moves the file or the directory called req.OldName in the
+// directory to req.NewName in newDir.
+func (dir *Dir) Rename(ctx context.Context, req *fuse.RenameRequest, newDir fs.Node) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	fsys := dir.fsys
+
+	target, ok := newDir.(*Dir)
+	if !ok {
+		return fuse.EIO
+	}
+
+	entries, err := fsys.list(ctx, dir.prefix)
+	if err != nil {
+		return err
+	}
+	e, ok := entries[req.OldName]
+	if !ok {
+		return fuse.ENOENT
+	}
+
+	if e.dir {
+		err = fsys.renameDir(ctx, dir.child(req.OldName).prefix, target.child(req.NewName).prefix)
+	} else {
+		err = fsys.renameFileObject(ctx, dir.prefix+req.OldName, target.prefix+req.NewName)
+	}
+	if err != nil {
+		return err
+	}
+
+	dir.keep()
+	return nil
+}
+
+// child returns the directory called name in the directory.
+func (dir *Dir) child(name string) *Dir {
+	return &Dir{fsys: dir.fsys, prefix: dir.prefix + name + "/"}
+}
+
+// keep keeps the directory, which may not contain any object anymore, until
+// it's removed.
+func (dir *Dir) keep() {
+	if dir.prefix != dir.fsys.config.Prefix {
+		dir.fsys.addDir(dir.prefix)
+	}
+}
+
+// renameFileObject moves the file at oldPath to newPath. The object at
+// oldPath is only deleted once the file is stored at newPath.
+func (fsys *FS) renameFileObject(ctx context.Context, oldPath, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	file, tracked := fsys.trackedFile(oldPath)
+	dirty, remote := false, true
+	if tracked {
+		dirty, remote = file.state()
+	}
+
+	if !dirty {
+		if err := fsys.moveObject(ctx, oldPath, newPath); err != nil {
+			return convertError(err)
+		}
+		fsys.renameFile(oldPath, newPath)
+	} else {
+		// the data which isn't uploaded yet is uploaded at the new path
+		fsys.renameFile(oldPath, newPath)
+		if err := file.sync(ctx); err != nil {
+			fsys.renameFile(newPath, oldPath)
+			return convertError(err)
+		}
+		if remote {
+			err := fsys.bucket.DeleteObject(ctx, oldPath)
+			if err != nil && !storj.ErrObjectNotFound.Has(err) {
+				return convertError(err)
+			}
+		}
+	}
+
+	fsys.blocks.invalidate(oldPath)
+	fsys.blocks.invalidate(newPath)
+	fsys.invalidate(oldPath)
+	fsys.invalidate(newPath)
+	return nil
+}
+
+// renameDir moves every object of the directory at oldPrefix to newPrefix.
+func (fsys *FS) renameDir(ctx context.Context, oldPrefix, newPrefix storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if strings.HasPrefix(newPrefix, oldPrefix) {
+		return fuse.Errno(syscall.EINVAL)
+	}
+
+	fsys.mu.Lock()
+	var files []*File
+	for path, file := range fsys.files {
+		if strings.HasPrefix(path, oldPrefix) {
+			files = append(files, file)
+		}
+	}
+	fsys.mu.Unlock()
+
+	// the written files are uploaded first, so they are moved with the others
+	for _, file := range files {
+		if err := file.sync(ctx); err != nil {
+			return err
+		}
+	}
+
+	opts := &libuplink.ListOptions{
+		Prefix:    oldPrefix,
+		Direction: storj.After,
+		Recursive: true,
+	}
+	var paths []storj.Path
+	for {
+		list, err := fsys.bucket.ListObjects(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, object := range list.Items {
+			paths = append(paths, object.Path)
+		}
+		if !list.More || len(list.Items) == 0 {
+			break
+		}
+		opts.Cursor = list.Items[len(list.Items)-1].Path
+	}
+
+	var group errs.Group
+	for _, path := range paths {
+		oldPath, newPath := oldPrefix+path, newPrefix+path
+		if err := fsys.moveObject(ctx, oldPath, newPath); err != nil {
+			group.Add(err)
+			continue
+		}
+		fsys.renameFile(oldPath, newPath)
+		fsys.blocks.invalidate(oldPath)
+		fsys.invalidate(oldPath)
+		fsys.invalidate(newPath)
+	}
+	fsys.renameDirs(oldPrefix, newPrefix)
+
+	fsys.invalidate(oldPrefix)
+	fsys.invalidate(newPrefix)
+	return group.Err()
+}
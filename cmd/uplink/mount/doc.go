@@ -0,0 +1,12 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+// Package mount implements a FUSE filesystem serving the objects of a bucket.
+//
+// Directories are the prefixes of the object paths, listed with the prefix
+// and delimiter listing of the bucket. Reads download the blocks of an
+// object on demand into a cache on the local disk. Writes go to a local file
+// and are uploaded when the file is closed.
+package mount
@@ -0,0 +1,392 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+package mount
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+)
+
+// File is a file of the filesystem, an object of the bucket.
+//
+// The data of a file opened for writing is kept in a local file, which is
+// uploaded when the file is closed.
+type File struct {
+	fsys *FS
+
+	mu       sync.Mutex
+	path     storj.Path
+	size     int64
+	modified time.Time
+	// remote is whether the object of the file is stored in the bucket.
+	remote bool
+	// handles is the number of handles of the open file.
+	handles int
+	// local is the local copy of the data, while the file is open for
+	// writing or has data which isn't uploaded yet.
+	local *os.File
+	// dirty is whether local has data which isn't uploaded yet.
+	dirty bool
+}
+
+var (
+	_ fs.Node          = (*File)(nil)
+	_ fs.NodeOpener    = (*File)(nil)
+	_ fs.NodeSetattrer = (*File)(nil)
+	_ fs.NodeFsyncer   = (*File)(nil)
+	_ fs.NodeForgetter = (*File)(nil)
+)
+
+// Attr returns the attributes of the file.
+func (file *File) Attr(ctx context.Context, attr *fuse.Attr) error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	attr.Valid = file.fsys.attrValid()
+	attr.Mode = 0644
+	attr.Size = uint64(file.size)
+	attr.Blocks = (attr.Size + 511) / 512
+	attr.Mtime = file.modified
+	attr.Ctime = file.modified
+	attr.Uid = file.fsys.config.UID
+	attr.Gid = file.fsys.config.GID
+	return nil
+}
+
+// Open opens the file. The data of a file opened for writing is downloaded
+// into a local file, unless it's truncated.
+func (file *File) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenResponse) (_ fs.Handle, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	if !req.Flags.IsReadOnly() {
+		truncate := req.Flags&fuse.OpenTruncate != 0
+		if err := file.openLocal(ctx, !truncate); err != nil {
+			return nil, err
+		}
+		if truncate {
+			if err := file.truncate(0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	file.handles++
+	return &handle{file: file}, nil
+}
+
+// Setattr changes the size of the file. The other attributes can't be
+// changed and are ignored.
+func (file *File) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse.SetattrResponse) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if req.Valid.Size() {
+		err := func() error {
+			file.mu.Lock()
+			defer file.mu.Unlock()
+
+			if err := file.openLocal(ctx, req.Size > 0); err != nil {
+				return err
+			}
+			if err := file.truncate(int64(req.Size)); err != nil {
+				return err
+			}
+
+			// a file truncated without being open isn't closed afterwards
+			if file.handles == 0 {
+				if err := file.upload(ctx); err != nil {
+					return err
+				}
+				file.closeLocal()
+			}
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return file.Attr(ctx, &resp.Attr)
+}
+
+// Fsync uploads the data written into the file.
+func (file *File) Fsync(ctx context.Context, req *fuse.FsyncRequest) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return file.sync(ctx)
+}
+
+// Forget stops tracking the file, once the kernel doesn't reference it.
+func (file *File) Forget() {
+	file.fsys.releaseFile(file)
+}
+
+// create makes the file an empty file and opens it.
+func (file *File) create() (*handle, error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	if err := file.openLocal(context.Background(), false); err != nil {
+		return nil, err
+	}
+	if err := file.truncate(0); err != nil {
+		return nil, err
+	}
+
+	file.handles++
+	return &handle{file: file}, nil
+}
+
+// refresh updates the attributes of the file from a directory listing,
+// unless its data is written locally.
+func (file *File) refresh(e entry) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	if file.local != nil {
+		return
+	}
+	file.size = e.size
+	file.modified = e.modified
+	file.remote = true
+}
+
+// localEntry returns the directory listing entry of the file, when its data
+// is written locally.
+func (file *File) localEntry() (entry, bool) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	if file.local == nil {
+		return entry{}, false
+	}
+	return entry{size: file.size, modified: file.modified}, true
+}
+
+// state returns whether the file has data which isn't uploaded yet, and
+// whether the object of the file is stored in the bucket.
+func (file *File) state() (dirty, remote bool) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	return file.dirty, file.remote
+}
+
+// discard drops the data of the file which isn't uploaded yet and returns
+// whether the object of the file is stored in the bucket.
+func (file *File) discard() bool {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	file.dirty = false
+	file.closeLocal()
+	return file.remote
+}
+
+// sync uploads the data written into the file.
+func (file *File) sync(ctx context.Context) error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	return file.upload(ctx)
+}
+
+// readAt reads the data of the file at offset into p.
+func (file *File) readAt(ctx context.Context, p []byte, offset int64) (n int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	file.mu.Lock()
+	if file.local != nil {
+		defer file.mu.Unlock()
+
+		n, err = file.local.ReadAt(p, offset)
+		if errs.Is(err, io.EOF) {
+			err = nil
+		}
+		return n, Error.Wrap(err)
+	}
+	path, size, modified := file.path, file.size, file.modified
+	file.mu.Unlock()
+
+	return file.fsys.blocks.readAt(ctx, file.fsys.bucket, path, size, modified, p, offset)
+}
+
+// writeAt writes p into the local copy of the file at offset.
+func (file *File) writeAt(p []byte, offset int64) (n int, err error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	if file.local == nil {
+		return 0, fuse.EPERM
+	}
+
+	n, err = file.local.WriteAt(p, offset)
+	if end := offset + int64(n); end > file.size {
+		file.size = end
+	}
+	file.modified = time.Now()
+	file.dirty = true
+	return n, Error.Wrap(err)
+}
+
+// release closes a handle of the file. The data written into the file is
+// uploaded, if it wasn't uploaded yet, when the last handle is closed.
+func (file *File) release(ctx context.Context) (err error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	file.handles--
+	if file.handles > 0 {
+		return nil
+	}
+
+	if err := file.upload(ctx); err != nil {
+		return err
+	}
+	file.closeLocal()
+	return nil
+}
+
+// openLocal creates the local copy of the file, with the data of the object
+// when download is set. It must be called with the lock held.
+func (file *File) openLocal(ctx context.Context, download bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if file.local != nil {
+		return nil
+	}
+
+	local, err := ioutil.TempFile(file.fsys.writeDir, "write")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, local.Close(), os.Remove(local.Name()))
+		}
+	}()
+
+	if download && file.remote && file.size > 0 {
+		reader, err := file.fsys.bucket.DownloadRange(ctx, file.path, 0, file.size)
+		if err != nil {
+			return convertError(err)
+		}
+		_, err = io.Copy(local, reader)
+		if err := errs.Combine(err, reader.Close()); err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	file.local = local
+	return nil
+}
+
+// closeLocal removes the local copy of the file. It must be called with the
+// lock held.
+func (file *File) closeLocal() {
+	if file.local == nil {
+		return
+	}
+
+	err := errs.Combine(file.local.Close(), os.Remove(file.local.Name()))
+	if err != nil {
+		file.fsys.log.Warn("unable to remove local copy", zap.String("path", file.path), zap.Error(err))
+	}
+	file.local = nil
+}
+
+// truncate changes the size of the local copy of the file. It must be called
+// with the lock held.
+func (file *File) truncate(size int64) error {
+	if err := file.local.Truncate(size); err != nil {
+		return Error.Wrap(err)
+	}
+	file.size = size
+	file.modified = time.Now()
+	file.dirty = true
+	return nil
+}
+
+// upload uploads the local copy of the file, when it has data which isn't
+// uploaded yet. It must be called with the lock held.
+func (file *File) upload(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !file.dirty {
+		return nil
+	}
+
+	writer, err := file.fsys.bucket.NewWriter(ctx, file.path, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, io.NewSectionReader(file.local, 0, file.size)); err != nil {
+		return errs.Combine(err, writer.Close())
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	file.dirty = false
+	file.remote = true
+	file.fsys.blocks.invalidate(file.path)
+	file.fsys.invalidate(file.path)
+	return nil
+}
+
+// handle is an open file.
+type handle struct {
+	file *File
+}
+
+var (
+	_ fs.HandleReader   = (*handle)(nil)
+	_ fs.HandleWriter   = (*handle)(nil)
+	_ fs.HandleFlusher  = (*handle)(nil)
+	_ fs.HandleReleaser = (*handle)(nil)
+)
+
+// Read reads req.Size bytes of the file at req.Offset.
+func (h *handle) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadResponse) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	data := make([]byte, req.Size)
+	n, err := h.file.readAt(ctx, data, req.Offset)
+	resp.Data = data[:n]
+	return err
+}
+
+// Write writes req.Data into the file at req.Offset.
+func (h *handle) Write(ctx context.Context, req *fuse.WriteRequest, resp *fuse.WriteResponse) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	resp.Size, err = h.file.writeAt(req.Data, req.Offset)
+	return err
+}
+
+// Flush uploads the data written into the file, as it's closed.
+func (h *handle) Flush(ctx context.Context, req *fuse.FlushRequest) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return h.file.sync(ctx)
+}
+
+// Release closes the handle.
+func (h *handle) Release(ctx context.Context, req *fuse.ReleaseRequest) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return h.file.release(ctx)
+}
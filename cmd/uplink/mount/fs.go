@@ -0,0 +1,512 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+package mount
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/memory"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default error class for the mount package.
+	Error = errs.Class("mount")
+)
+
+// listingCapacity is the number of directory listings kept in the cache.
+const listingCapacity = 1024
+
+// Bucket is the part of a bucket used by the filesystem.
+type Bucket interface {
+	ListObjects(ctx context.Context, opts *libuplink.ListOptions) (storj.ObjectList, error)
+	DownloadRange(ctx context.Context, path storj.Path, start, limit int64) (io.ReadCloser, error)
+	NewWriter(ctx context.Context, path storj.Path, opts *libuplink.UploadOptions) (io.WriteCloser, error)
+	DeleteObject(ctx context.Context, path storj.Path) error
+}
+
+// Mover is implemented by a Bucket which can move objects without
+// downloading and uploading them again.
+type Mover interface {
+	MoveObject(ctx context.Context, path storj.Path, newBucket string, newPath storj.Path) error
+}
+
+// Config is the configuration of the filesystem.
+type Config struct {
+	// Bucket is the name of the mounted bucket.
+	Bucket string
+	// Prefix is the path, ending with a slash, of the directory of the
+	// bucket at the root of the filesystem.
+	Prefix storj.Path
+	// CacheDir is the local directory where the downloaded blocks and the
+	// written files are stored.
+	CacheDir string
+	// BlockSize is the size of the blocks downloaded on reads.
+	BlockSize memory.Size
+	// CacheSize is the total size of the cached blocks.
+	CacheSize memory.Size
+	// AttrTTL is how long the directory listings, and the attributes of the
+	// files and directories in them, are cached. A non-positive value
+	// disables the cache.
+	AttrTTL time.Duration
+	// UID and GID own the files and the directories.
+	UID uint32
+	GID uint32
+}
+
+// FS is a FUSE filesystem serving the objects of a bucket.
+type FS struct {
+	log    *zap.Logger
+	bucket Bucket
+	config Config
+
+	listings *listingCache
+	blocks   *blockCache
+	writeDir string
+
+	mu sync.Mutex
+	// files are the files which are open or have been written and not
+	// uploaded yet, by path.
+	files map[storj.Path]*File
+	// dirs are the directories created with mkdir, which don't contain any
+	// object yet, by prefix.
+	dirs map[storj.Path]struct{}
+}
+
+var _ fs.FS = (*FS)(nil)
+
+// New creates a filesystem serving the objects of bucket. The cached blocks
+// are removed, but the written files left in the cache directory, which may
+// not have been uploaded, must be moved away by the user before.
+func New(log *zap.Logger, bucket Bucket, config Config) (*FS, error) {
+	if config.CacheDir == "" {
+		return nil, Error.New("cache directory is required")
+	}
+	if config.BlockSize <= 0 {
+		return nil, Error.New("block size must be positive")
+	}
+	if config.Prefix != "" && !strings.HasSuffix(config.Prefix, "/") {
+		config.Prefix += "/"
+	}
+
+	blockDir := filepath.Join(config.CacheDir, "blocks")
+	writeDir := filepath.Join(config.CacheDir, "writes")
+	written, err := ioutil.ReadDir(writeDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, Error.Wrap(err)
+	}
+	if len(written) > 0 {
+		return nil, Error.New("%q contains %d written files which may not have been uploaded, move them away before mounting", writeDir, len(written))
+	}
+
+	if err := os.RemoveAll(blockDir); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for _, dir := range []string{blockDir, writeDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	return &FS{
+		log:      log,
+		bucket:   bucket,
+		config:   config,
+		listings: newListingCache(config.AttrTTL),
+		blocks:   newBlockCache(blockDir, config.BlockSize.Int64(), config.CacheSize.Int64()),
+		writeDir: writeDir,
+		files:    make(map[storj.Path]*File),
+		dirs:     make(map[storj.Path]struct{}),
+	}, nil
+}
+
+// RemoveCache removes the cache directory, unless it contains written files
+// which may not have been uploaded.
+func RemoveCache(cacheDir string) error {
+	if err := os.RemoveAll(filepath.Join(cacheDir, "blocks")); err != nil {
+		return Error.Wrap(err)
+	}
+	written, err := ioutil.ReadDir(filepath.Join(cacheDir, "writes"))
+	if err != nil && !os.IsNotExist(err) {
+		return Error.Wrap(err)
+	}
+	if len(written) > 0 {
+		return Error.New("%q contains %d written files which may not have been uploaded", cacheDir, len(written))
+	}
+	return Error.Wrap(os.RemoveAll(cacheDir))
+}
+
+// Root returns the root directory of the filesystem.
+func (fsys *FS) Root() (fs.Node, error) {
+	return &Dir{fsys: fsys, prefix: fsys.config.Prefix}, nil
+}
+
+// Mount mounts the filesystem at mountpoint and serves it until ctx is
+// canceled or it is unmounted.
+func Mount(ctx context.Context, fsys *FS, mountpoint string, options ...fuse.MountOption) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	conn, err := fuse.Mount(mountpoint, options...)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	served := make(chan error, 1)
+	go func() {
+		served <- fs.Serve(conn, fsys)
+	}()
+
+	<-conn.Ready
+	if conn.MountError != nil {
+		return Error.Wrap(conn.MountError)
+	}
+
+	select {
+	case err := <-served:
+		return Error.Wrap(err)
+	case <-ctx.Done():
+	}
+
+	if err := fuse.Unmount(mountpoint); err != nil {
+		fsys.log.Warn("unable to unmount", zap.String("mountpoint", mountpoint), zap.Error(err))
+	}
+	return Error.Wrap(<-served)
+}
+
+// entry is a file or a directory in a directory listing.
+type entry struct {
+	dir      bool
+	size     int64
+	modified time.Time
+}
+
+// list returns the entries of the directory at prefix, by name.
+func (fsys *FS) list(ctx context.Context, prefix storj.Path) (entries map[string]entry, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entries, err = fsys.listings.get(prefix, func() (map[string]entry, error) {
+		return fsys.listObjects(ctx, prefix)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the cached listing is shared, the local changes are merged into a copy
+	merged := make(map[string]entry, len(entries))
+	for name, e := range entries {
+		merged[name] = e
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	for dir := range fsys.dirs {
+		if name, ok := childName(prefix, dir); ok {
+			merged[name] = entry{dir: true}
+		}
+	}
+	for path, file := range fsys.files {
+		name, ok := childName(prefix, path)
+		if !ok {
+			continue
+		}
+		if e, ok := file.localEntry(); ok {
+			merged[name] = e
+		}
+	}
+	return merged, nil
+}
+
+// listObjects lists the objects and the prefixes directly in the directory
+// at prefix.
+func (fsys *FS) listObjects(ctx context.Context, prefix storj.Path) (entries map[string]entry, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entries = make(map[string]entry)
+
+	opts := &libuplink.ListOptions{
+		Prefix:    prefix,
+		Direction: storj.After,
+	}
+	for {
+		list, err := fsys.bucket.ListObjects(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range list.Items {
+			name := strings.TrimSuffix(object.Path, "/")
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			if object.IsPrefix {
+				entries[name] = entry{dir: true}
+				continue
+			}
+			// a prefix and an object with the same name are shown as the directory
+			if e, ok := entries[name]; ok && e.dir {
+				continue
+			}
+			entries[name] = entry{
+				size:     object.Size,
+				modified: object.Modified,
+			}
+		}
+
+		if !list.More || len(list.Items) == 0 {
+			return entries, nil
+		}
+		opts.Cursor = list.Items[len(list.Items)-1].Path
+	}
+}
+
+// invalidate drops the cached listings of the directories containing path,
+// and of the directory at path if it's a directory.
+func (fsys *FS) invalidate(path storj.Path) {
+	if strings.HasSuffix(path, "/") {
+		fsys.listings.delete(path)
+	}
+	for path != "" {
+		path = parentPrefix(path)
+		fsys.listings.delete(path)
+	}
+}
+
+// openFile returns the file at path tracked by the filesystem, creating it
+// with e when it isn't tracked yet.
+func (fsys *FS) openFile(path storj.Path, e entry) *File {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if file, ok := fsys.files[path]; ok {
+		return file
+	}
+	file := &File{
+		fsys:     fsys,
+		path:     path,
+		size:     e.size,
+		modified: e.modified,
+	}
+	fsys.files[path] = file
+	return file
+}
+
+// trackedFile returns the file at path, if it is tracked by the filesystem.
+func (fsys *FS) trackedFile(path storj.Path) (*File, bool) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	file, ok := fsys.files[path]
+	return file, ok
+}
+
+// releaseFile stops tracking file, if it is not open nor has data to upload.
+func (fsys *FS) releaseFile(file *File) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	if file.handles == 0 && file.local == nil && fsys.files[file.path] == file {
+		delete(fsys.files, file.path)
+	}
+}
+
+// forgetFile stops tracking the file at path.
+func (fsys *FS) forgetFile(path storj.Path) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	delete(fsys.files, path)
+}
+
+// renameFile tracks the file at oldPath as newPath. The data of the file it
+// replaces at newPath, which may still be open, is discarded, so that it
+// doesn't overwrite the renamed object when it's released.
+func (fsys *FS) renameFile(oldPath, newPath storj.Path) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if replaced, ok := fsys.files[newPath]; ok {
+		replaced.discard()
+		delete(fsys.files, newPath)
+	}
+	if file, ok := fsys.files[oldPath]; ok {
+		delete(fsys.files, oldPath)
+		file.mu.Lock()
+		file.path = newPath
+		file.mu.Unlock()
+		fsys.files[newPath] = file
+	}
+}
+
+// addDir records the empty directory at prefix, created with mkdir.
+func (fsys *FS) addDir(prefix storj.Path) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	fsys.dirs[prefix] = struct{}{}
+}
+
+// removeDirs forgets the directories created with mkdir at prefix and in it.
+func (fsys *FS) removeDirs(prefix storj.Path) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	for dir := range fsys.dirs {
+		if strings.HasPrefix(dir, prefix) {
+			delete(fsys.dirs, dir)
+		}
+	}
+}
+
+// renameDirs moves the directories created with mkdir at oldPrefix and in it
+// to newPrefix.
+func (fsys *FS) renameDirs(oldPrefix, newPrefix storj.Path) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	for dir := range fsys.dirs {
+		if strings.HasPrefix(dir, oldPrefix) {
+			delete(fsys.dirs, dir)
+			fsys.dirs[newPrefix+strings.TrimPrefix(dir, oldPrefix)] = struct{}{}
+		}
+	}
+}
+
+// moveObject moves the object at oldPath to newPath, on the satellite when
+// the bucket supports it, or by downloading and uploading it again.
+func (fsys *FS) moveObject(ctx context.Context, oldPath, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if mover, ok := fsys.bucket.(Mover); ok {
+		err := mover.MoveObject(ctx, oldPath, fsys.config.Bucket, newPath)
+		if !errs2.IsRPC(err, rpcstatus.Unimplemented) {
+			return err
+		}
+		fsys.log.Debug("moving objects is not supported by the satellite, copying instead", zap.Error(err))
+	}
+
+	reader, err := fsys.bucket.DownloadRange(ctx, oldPath, 0, -1)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	opts, err := fsys.uploadOptions(ctx, oldPath)
+	if err != nil {
+		return err
+	}
+	writer, err := fsys.bucket.NewWriter(ctx, newPath, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, reader); err != nil {
+		return errs.Combine(err, writer.Close())
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return fsys.bucket.DeleteObject(ctx, oldPath)
+}
+
+// uploadOptions returns the options which upload an object with the content
+// type, the metadata and the expiration of the object at path.
+func (fsys *FS) uploadOptions(ctx context.Context, path storj.Path) (_ *libuplink.UploadOptions, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	opts := &libuplink.ListOptions{
+		Prefix:    parentPrefix(path),
+		Direction: storj.After,
+	}
+	name := strings.TrimPrefix(path, opts.Prefix)
+	for {
+		list, err := fsys.bucket.ListObjects(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range list.Items {
+			if object.IsPrefix || object.Path != name {
+				continue
+			}
+			return &libuplink.UploadOptions{
+				ContentType: object.ContentType,
+				Metadata:    object.Metadata,
+				Expires:     object.Expires,
+			}, nil
+		}
+
+		if !list.More || len(list.Items) == 0 {
+			return nil, storj.ErrObjectNotFound.New("%q", path)
+		}
+		opts.Cursor = list.Items[len(list.Items)-1].Path
+	}
+}
+
+// attrValid returns how long the kernel may cache attributes and entries.
+func (fsys *FS) attrValid() time.Duration {
+	if fsys.config.AttrTTL < 0 {
+		return 0
+	}
+	return fsys.config.AttrTTL
+}
+
+// childName returns the name of path in the directory at prefix, if path is
+// directly in it.
+func childName(prefix, path storj.Path) (string, bool) {
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(path, prefix), "/")
+	if name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+// parentPrefix returns the prefix of the directory containing path.
+func parentPrefix(path storj.Path) storj.Path {
+	path = strings.TrimSuffix(path, "/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return ""
+	}
+	return path[:i+1]
+}
+
+// convertError converts the errors of the bucket to FUSE errors.
+func convertError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case storj.ErrObjectNotFound.Has(err):
+		return fuse.ENOENT
+	default:
+		return err
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+package mount
+
+import (
+	"time"
+
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/cache"
+)
+
+// listingCache caches the directory listings, and with them the attributes
+// of the files and directories, for a limited time.
+type listingCache struct {
+	lru *cache.ExpiringLRU
+}
+
+// newListingCache creates a cache keeping the listings for ttl. A
+// non-positive ttl disables the cache.
+func newListingCache(ttl time.Duration) *listingCache {
+	capacity := listingCapacity
+	if ttl <= 0 {
+		capacity = 0
+	}
+	return &listingCache{
+		lru: cache.New(cache.Options{
+			Expiration: ttl,
+			Capacity:   capacity,
+		}),
+	}
+}
+
+// get returns the cached listing of the directory at prefix, calling list
+// when it isn't cached or has expired.
+func (listings *listingCache) get(prefix storj.Path, list func() (map[string]entry, error)) (map[string]entry, error) {
+	value, err := listings.lru.Get(prefix, func() (interface{}, error) {
+		return list()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]entry), nil
+}
+
+// delete drops the cached listing of the directory at prefix.
+func (listings *listingCache) delete(prefix storj.Path) {
+	listings.lru.Delete(prefix)
+}
@@ -0,0 +1,605 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin freebsd
+
+package mount_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/cmd/uplink/mount"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+func TestReadDirAndRead(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	bucket := newFakeBucket()
+	data := testrand.BytesInt(100)
+	bucket.put("a.txt", data)
+	bucket.put("dir/b.txt", []byte("b"))
+	bucket.put("dir/sub/c.txt", []byte("c"))
+	bucket.put("dir/d.txt", []byte("d"))
+
+	root := newRoot(ctx, t, bucket, mount.Config{BlockSize: 16, CacheSize: 1 * memory.KiB})
+
+	require.Equal(t, []fuse.Dirent{
+		{Name: "a.txt", Type: fuse.DT_File},
+		{Name: "dir", Type: fuse.DT_Dir},
+	}, readDir(ctx, t, root))
+
+	dir := lookup(ctx, t, root, "dir").(*mount.Dir)
+	require.Equal(t, []fuse.Dirent{
+		{Name: "b.txt", Type: fuse.DT_File},
+		{Name: "d.txt", Type: fuse.DT_File},
+		{Name: "sub", Type: fuse.DT_Dir},
+	}, readDir(ctx, t, dir))
+
+	_, err := dir.Lookup(ctx, &fuse.LookupRequest{Name: "missing"}, &fuse.LookupResponse{})
+	require.Equal(t, fuse.ENOENT, err)
+
+	file := lookup(ctx, t, root, "a.txt").(*mount.File)
+	var attr fuse.Attr
+	require.NoError(t, file.Attr(ctx, &attr))
+	require.EqualValues(t, len(data), attr.Size)
+
+	// only the blocks of the read ranges are downloaded
+	downloads := bucket.downloadCount()
+	handle := open(ctx, t, file, fuse.OpenReadOnly)
+	require.Equal(t, data[10:50], read(ctx, t, handle, 10, 40))
+	require.Equal(t, data[90:], read(ctx, t, handle, 90, 40))
+	require.Empty(t, read(ctx, t, handle, 200, 10))
+	require.Equal(t, downloads+6, bucket.downloadCount())
+
+	// the downloaded blocks are read from the cache
+	require.Equal(t, data[0:64], read(ctx, t, handle, 0, 64))
+	require.Equal(t, data[16:48], read(ctx, t, handle, 16, 32))
+	require.Equal(t, downloads+6, bucket.downloadCount())
+
+	require.NoError(t, handle.(fs.HandleReleaser).Release(ctx, &fuse.ReleaseRequest{}))
+}
+
+func TestWriteOnClose(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	bucket := newFakeBucket()
+	bucket.put("dir/existing.txt", []byte("hello world"))
+
+	root := newRoot(ctx, t, bucket, mount.Config{})
+
+	// a new file is uploaded when it's closed
+	node, handle, err := root.Create(ctx, &fuse.CreateRequest{Name: "new.txt"}, &fuse.CreateResponse{})
+	require.NoError(t, err)
+	write(ctx, t, handle, 0, []byte("new data"))
+	require.Equal(t, []byte("new data"), read(ctx, t, handle, 0, 100))
+
+	_, ok := bucket.get("new.txt")
+	require.False(t, ok)
+	require.Equal(t, []fuse.Dirent{
+		{Name: "dir", Type: fuse.DT_Dir},
+		{Name: "new.txt", Type: fuse.DT_File},
+	}, readDir(ctx, t, root))
+
+	require.NoError(t, handle.(fs.HandleFlusher).Flush(ctx, &fuse.FlushRequest{}))
+	require.NoError(t, handle.(fs.HandleReleaser).Release(ctx, &fuse.ReleaseRequest{}))
+
+	stored, ok := bucket.get("new.txt")
+	require.True(t, ok)
+	require.Equal(t, []byte("new data"), stored)
+
+	var attr fuse.Attr
+	require.NoError(t, node.Attr(ctx, &attr))
+	require.EqualValues(t, len("new data"), attr.Size)
+
+	// an existing file is downloaded and written at an offset
+	dir := lookup(ctx, t, root, "dir")
+	file := lookup(ctx, t, dir, "existing.txt").(*mount.File)
+	handle = open(ctx, t, file, fuse.OpenReadWrite)
+	write(ctx, t, handle, 6, []byte("storj!"))
+	require.NoError(t, handle.(fs.HandleReleaser).Release(ctx, &fuse.ReleaseRequest{}))
+
+	stored, ok = bucket.get("dir/existing.txt")
+	require.True(t, ok)
+	require.Equal(t, []byte("hello storj!"), stored)
+
+	// a truncated file is uploaded without being opened
+	require.NoError(t, file.Setattr(ctx, &fuse.SetattrRequest{Valid: fuse.SetattrSize, Size: 5}, &fuse.SetattrResponse{}))
+	stored, ok = bucket.get("dir/existing.txt")
+	require.True(t, ok)
+	require.Equal(t, []byte("hello"), stored)
+
+	handle = open(ctx, t, file, fuse.OpenReadOnly)
+	require.Equal(t, []byte("hello"), read(ctx, t, handle, 0, 100))
+	require.NoError(t, handle.(fs.HandleReleaser).Release(ctx, &fuse.ReleaseRequest{}))
+}
+
+func TestAttrTTL(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	bucket := newFakeBucket()
+	bucket.put("a.txt", []byte("a"))
+
+	cached := newRoot(ctx, t, bucket, mount.Config{AttrTTL: time.Hour})
+	uncached := newRoot(ctx, t, bucket, mount.Config{})
+
+	require.Len(t, readDir(ctx, t, cached), 1)
+	require.Len(t, readDir(ctx, t, uncached), 1)
+
+	bucket.put("b.txt", []byte("b"))
+
+	// the listing is cached until it expires or is changed through the filesystem
+	require.Len(t, readDir(ctx, t, cached), 1)
+	require.Len(t, readDir(ctx, t, uncached), 2)
+
+	_, handle, err := cached.Create(ctx, &fuse.CreateRequest{Name: "c.txt"}, &fuse.CreateResponse{})
+	require.NoError(t, err)
+	require.NoError(t, handle.(fs.HandleReleaser).Release(ctx, &fuse.ReleaseRequest{}))
+	require.Len(t, readDir(ctx, t, cached), 3)
+}
+
+func TestRemoveAndRename(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		bucket mount.Bucket
+	}{
+		{"copy", newFakeBucket()},
+		{"move", &fakeMoverBucket{fakeBucket: newFakeBucket()}},
+		{"unimplemented move", &fakeMoverBucket{fakeBucket: newFakeBucket(), unimplemented: true}},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testcontext.New(t)
+			defer ctx.Cleanup()
+
+			var bucket *fakeBucket
+			switch b := tt.bucket.(type) {
+			case *fakeBucket:
+				bucket = b
+			case *fakeMoverBucket:
+				bucket = b.fakeBucket
+			}
+			bucket.put("a.txt", []byte("a"))
+			bucket.put("dir/b.txt", []byte("b"))
+			bucket.put("dir/sub/c.txt", []byte("c"))
+
+			root := newRoot(ctx, t, tt.bucket, mount.Config{AttrTTL: time.Hour})
+
+			// files are renamed
+			require.NoError(t, root.Rename(ctx, &fuse.RenameRequest{OldName: "a.txt", NewName: "renamed.txt"}, root))
+			require.Equal(t, []string{"dir/b.txt", "dir/sub/c.txt", "renamed.txt"}, bucket.paths())
+
+			// directories are renamed with their objects
+			newDir, err := root.Mkdir(ctx, &fuse.MkdirRequest{Name: "new"})
+			require.NoError(t, err)
+			require.NoError(t, root.Rename(ctx, &fuse.RenameRequest{OldName: "dir", NewName: "moved"}, newDir))
+			require.Equal(t, []string{"new/moved/b.txt", "new/moved/sub/c.txt", "renamed.txt"}, bucket.paths())
+
+			// directories are only removed when empty
+			err = root.Remove(ctx, &fuse.RemoveRequest{Name: "new", Dir: true})
+			require.Equal(t, fuse.Errno(syscall.ENOTEMPTY), err)
+
+			moved := lookup(ctx, t, lookup(ctx, t, root, "new"), "moved").(*mount.Dir)
+			sub := lookup(ctx, t, moved, "sub").(*mount.Dir)
+			require.NoError(t, sub.Remove(ctx, &fuse.RemoveRequest{Name: "c.txt"}))
+			require.Equal(t, []string{"new/moved/b.txt", "renamed.txt"}, bucket.paths())
+
+			// the directory of a removed file is kept until it's removed
+			require.Equal(t, []fuse.Dirent{
+				{Name: "b.txt", Type: fuse.DT_File},
+				{Name: "sub", Type: fuse.DT_Dir},
+			}, readDir(ctx, t, moved))
+			require.NoError(t, moved.Remove(ctx, &fuse.RemoveRequest{Name: "sub", Dir: true}))
+			require.Equal(t, []fuse.Dirent{
+				{Name: "b.txt", Type: fuse.DT_File},
+			}, readDir(ctx, t, moved))
+
+			err = root.Remove(ctx, &fuse.RemoveRequest{Name: "missing.txt"})
+			require.Equal(t, fuse.ENOENT, err)
+		})
+	}
+}
+
+func TestRenameKeepsMetadata(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		bucket mount.Bucket
+	}{
+		{"copy", newFakeBucket()},
+		{"move", &fakeMoverBucket{fakeBucket: newFakeBucket()}},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testcontext.New(t)
+			defer ctx.Cleanup()
+
+			var bucket *fakeBucket
+			switch b := tt.bucket.(type) {
+			case *fakeBucket:
+				bucket = b
+			case *fakeMoverBucket:
+				bucket = b.fakeBucket
+			}
+			metadata := map[string]string{"key": "value"}
+			// the object is on the second page of the listing
+			bucket.put("dir/a1.txt", []byte("a1"))
+			bucket.put("dir/a2.txt", []byte("a2"))
+			bucket.putWithMetadata("dir/b.txt", []byte("b"), "text/plain", metadata)
+
+			root := newRoot(ctx, t, tt.bucket, mount.Config{AttrTTL: time.Hour})
+			dir := lookup(ctx, t, root, "dir").(*mount.Dir)
+
+			require.NoError(t, dir.Rename(ctx, &fuse.RenameRequest{OldName: "b.txt", NewName: "renamed.txt"}, root))
+			require.Equal(t, []string{"dir/a1.txt", "dir/a2.txt", "renamed.txt"}, bucket.paths())
+
+			contentType, renamedMetadata := bucket.getMetadata("renamed.txt")
+			require.Equal(t, "text/plain", contentType)
+			require.Equal(t, metadata, renamedMetadata)
+		})
+	}
+}
+
+func TestRenameWrittenFile(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	bucket := newFakeBucket()
+	bucket.put("a.txt", []byte("a"))
+
+	root := newRoot(ctx, t, bucket, mount.Config{AttrTTL: time.Hour})
+
+	file := lookup(ctx, t, root, "a.txt").(*mount.File)
+	handle := open(ctx, t, file, fuse.OpenReadWrite)
+	write(ctx, t, handle, 1, []byte("bc"))
+
+	// the object isn't deleted when the written file can't be uploaded
+	bucket.setFailWrites(true)
+	err := root.Rename(ctx, &fuse.RenameRequest{OldName: "a.txt", NewName: "renamed.txt"}, root)
+	require.Error(t, err)
+	require.Equal(t, []string{"a.txt"}, bucket.paths())
+
+	// the written file is uploaded at the new path before the object is deleted
+	bucket.setFailWrites(false)
+	require.NoError(t, root.Rename(ctx, &fuse.RenameRequest{OldName: "a.txt", NewName: "renamed.txt"}, root))
+	require.Equal(t, []string{"renamed.txt"}, bucket.paths())
+	stored, ok := bucket.get("renamed.txt")
+	require.True(t, ok)
+	require.Equal(t, []byte("abc"), stored)
+
+	require.NoError(t, handle.(fs.HandleReleaser).Release(ctx, &fuse.ReleaseRequest{}))
+	require.Equal(t, []string{"renamed.txt"}, bucket.paths())
+}
+
+func TestRenameOntoWrittenFile(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	bucket := newFakeBucket()
+	bucket.put("a.txt", []byte("a"))
+	bucket.put("b.txt", []byte("b"))
+
+	root := newRoot(ctx, t, bucket, mount.Config{AttrTTL: time.Hour})
+
+	target := lookup(ctx, t, root, "b.txt").(*mount.File)
+	handle := open(ctx, t, target, fuse.OpenReadWrite)
+	write(ctx, t, handle, 1, []byte("stale"))
+
+	require.NoError(t, root.Rename(ctx, &fuse.RenameRequest{OldName: "a.txt", NewName: "b.txt"}, root))
+	require.Equal(t, []string{"b.txt"}, bucket.paths())
+
+	// the replaced file doesn't overwrite the renamed object when it's released
+	require.NoError(t, handle.(fs.HandleReleaser).Release(ctx, &fuse.ReleaseRequest{}))
+	stored, ok := bucket.get("b.txt")
+	require.True(t, ok)
+	require.Equal(t, []byte("a"), stored)
+}
+
+func TestNewKeepsWrittenFiles(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	cacheDir := ctx.Dir("cache")
+	written := filepath.Join(cacheDir, "writes", "write123")
+	require.NoError(t, os.MkdirAll(filepath.Dir(written), 0700))
+	require.NoError(t, ioutil.WriteFile(written, []byte("data"), 0600))
+
+	_, err := mount.New(zaptest.NewLogger(t), newFakeBucket(), mount.Config{
+		Bucket:    "testbucket",
+		CacheDir:  cacheDir,
+		BlockSize: 1 * memory.KiB,
+		CacheSize: 1 * memory.MiB,
+	})
+	require.Error(t, err)
+
+	// neither is the cache directory removed
+	require.Error(t, mount.RemoveCache(cacheDir))
+
+	data, err := ioutil.ReadFile(written)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+
+	require.NoError(t, os.Remove(written))
+	require.NoError(t, mount.RemoveCache(cacheDir))
+	_, err = os.Stat(cacheDir)
+	require.True(t, os.IsNotExist(err))
+}
+
+// newRoot creates a filesystem of bucket and returns its root directory.
+func newRoot(ctx *testcontext.Context, t *testing.T, bucket mount.Bucket, config mount.Config) *mount.Dir {
+	config.Bucket = "testbucket"
+	config.CacheDir = ctx.Dir("cache", t.Name())
+	if config.BlockSize == 0 {
+		config.BlockSize = 1 * memory.KiB
+		config.CacheSize = 1 * memory.MiB
+	}
+
+	fsys, err := mount.New(zaptest.NewLogger(t), bucket, config)
+	require.NoError(t, err)
+
+	root, err := fsys.Root()
+	require.NoError(t, err)
+	return root.(*mount.Dir)
+}
+
+func lookup(ctx context.Context, t *testing.T, dir fs.Node, name string) fs.Node {
+	node, err := dir.(fs.NodeRequestLookuper).Lookup(ctx, &fuse.LookupRequest{Name: name}, &fuse.LookupResponse{})
+	require.NoError(t, err)
+	return node
+}
+
+func readDir(ctx context.Context, t *testing.T, dir fs.Node) []fuse.Dirent {
+	dirents, err := dir.(fs.HandleReadDirAller).ReadDirAll(ctx)
+	require.NoError(t, err)
+	return dirents
+}
+
+func open(ctx context.Context, t *testing.T, file *mount.File, flags fuse.OpenFlags) fs.Handle {
+	handle, err := file.Open(ctx, &fuse.OpenRequest{Flags: flags}, &fuse.OpenResponse{})
+	require.NoError(t, err)
+	return handle
+}
+
+func read(ctx context.Context, t *testing.T, handle fs.Handle, offset int64, size int) []byte {
+	resp := &fuse.ReadResponse{}
+	err := handle.(fs.HandleReader).Read(ctx, &fuse.ReadRequest{Offset: offset, Size: size}, resp)
+	require.NoError(t, err)
+	return resp.Data
+}
+
+func write(ctx context.Context, t *testing.T, handle fs.Handle, offset int64, data []byte) {
+	resp := &fuse.WriteResponse{}
+	err := handle.(fs.HandleWriter).Write(ctx, &fuse.WriteRequest{Offset: offset, Data: data}, resp)
+	require.NoError(t, err)
+	require.Equal(t, len(data), resp.Size)
+}
+
+// fakeBucket is an in-memory bucket.
+type fakeBucket struct {
+	mu         sync.Mutex
+	objects    map[storj.Path]fakeObject
+	downloads  int
+	failWrites bool
+}
+
+type fakeObject struct {
+	data        []byte
+	modified    time.Time
+	contentType string
+	metadata    map[string]string
+}
+
+func newFakeBucket() *fakeBucket {
+	return &fakeBucket{objects: make(map[storj.Path]fakeObject)}
+}
+
+func (bucket *fakeBucket) put(path storj.Path, data []byte) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.objects[path] = fakeObject{data: data, modified: time.Now()}
+}
+
+func (bucket *fakeBucket) putWithMetadata(path storj.Path, data []byte, contentType string, metadata map[string]string) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.objects[path] = fakeObject{data: data, modified: time.Now(), contentType: contentType, metadata: metadata}
+}
+
+func (bucket *fakeBucket) setFailWrites(fail bool) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.failWrites = fail
+}
+
+func (bucket *fakeBucket) get(path storj.Path) ([]byte, bool) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	object, ok := bucket.objects[path]
+	return object.data, ok
+}
+
+func (bucket *fakeBucket) getMetadata(path storj.Path) (contentType string, metadata map[string]string) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	object := bucket.objects[path]
+	return object.contentType, object.metadata
+}
+
+func (bucket *fakeBucket) paths() []string {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	var paths []string
+	for path := range bucket.objects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (bucket *fakeBucket) downloadCount() int {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	return bucket.downloads
+}
+
+// ListObjects lists the objects, two at a time to exercise the paging.
+func (bucket *fakeBucket) ListObjects(ctx context.Context, opts *libuplink.ListOptions) (storj.ObjectList, error) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	items := map[storj.Path]storj.Object{}
+	for path, object := range bucket.objects {
+		if !strings.HasPrefix(path, opts.Prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(path, opts.Prefix)
+		if i := strings.IndexByte(name, '/'); i >= 0 && !opts.Recursive {
+			items[name[:i+1]] = storj.Object{Path: name[:i+1], IsPrefix: true}
+			continue
+		}
+		items[name] = storj.Object{
+			Path:        name,
+			Metadata:    object.metadata,
+			ContentType: object.contentType,
+			Modified:    object.modified,
+			Stream:      storj.Stream{Size: int64(len(object.data))},
+		}
+	}
+
+	var names []string
+	for name := range items {
+		if name > opts.Cursor {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	list := storj.ObjectList{Prefix: opts.Prefix}
+	if len(names) > 2 {
+		names, list.More = names[:2], true
+	}
+	for _, name := range names {
+		list.Items = append(list.Items, items[name])
+	}
+	return list, nil
+}
+
+func (bucket *fakeBucket) DownloadRange(ctx context.Context, path storj.Path, start, limit int64) (io.ReadCloser, error) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	object, ok := bucket.objects[path]
+	if !ok {
+		return nil, storj.ErrObjectNotFound.New("%q", path)
+	}
+	bucket.downloads++
+
+	data := object.data[start:]
+	if limit >= 0 && limit < int64(len(data)) {
+		data = data[:limit]
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (bucket *fakeBucket) NewWriter(ctx context.Context, path storj.Path, opts *libuplink.UploadOptions) (io.WriteCloser, error) {
+	if opts == nil {
+		opts = &libuplink.UploadOptions{}
+	}
+	return &fakeWriter{bucket: bucket, path: path, opts: opts}, nil
+}
+
+func (bucket *fakeBucket) DeleteObject(ctx context.Context, path storj.Path) error {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	if _, ok := bucket.objects[path]; !ok {
+		return storj.ErrObjectNotFound.New("%q", path)
+	}
+	delete(bucket.objects, path)
+	return nil
+}
+
+// fakeWriter stores the object when it's closed.
+type fakeWriter struct {
+	bucket *fakeBucket
+	path   storj.Path
+	opts   *libuplink.UploadOptions
+	buffer bytes.Buffer
+}
+
+func (writer *fakeWriter) Write(p []byte) (int, error) {
+	return writer.buffer.Write(p)
+}
+
+func (writer *fakeWriter) Close() error {
+	writer.bucket.mu.Lock()
+	defer writer.bucket.mu.Unlock()
+
+	if writer.bucket.failWrites {
+		return errors.New("upload failed")
+	}
+	writer.bucket.objects[writer.path] = fakeObject{
+		data:        writer.buffer.Bytes(),
+		modified:    time.Now(),
+		contentType: writer.opts.ContentType,
+		metadata:    writer.opts.Metadata,
+	}
+	return nil
+}
+
+// fakeMoverBucket is an in-memory bucket which moves objects.
+type fakeMoverBucket struct {
+	*fakeBucket
+	unimplemented bool
+}
+
+func (bucket *fakeMoverBucket) MoveObject(ctx context.Context, path storj.Path, newBucket string, newPath storj.Path) error {
+	if bucket.unimplemented {
+		return rpcstatus.Error(rpcstatus.Unimplemented, "not implemented")
+	}
+	if newBucket != "testbucket" {
+		return storj.ErrBucketNotFound.New("%q", newBucket)
+	}
+
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	object, ok := bucket.objects[path]
+	if !ok {
+		return storj.ErrObjectNotFound.New("%q", path)
+	}
+	delete(bucket.objects, path)
+	bucket.objects[newPath] = object
+	return nil
+}
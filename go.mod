@@ -3,6 +3,7 @@ module storj.io/storj
 go 1.13
 
 require (
+	bazil.org/fuse v0.0.0-20200117225306-7b5117fecadc
 	github.com/alessio/shellescape v0.0.0-20190409004728-b115ca0f9053
 	github.com/alicebob/miniredis/v2 v2.11.1
 	github.com/btcsuite/btcutil v1.0.1
@@ -10,6 +11,7 @@ require (
 	github.com/cheggaaa/pb/v3 v3.0.1
 	github.com/fatih/color v1.7.0
 	github.com/go-redis/redis v6.14.1+incompatible
+	github.com/gogo/protobuf v1.2.1
 	github.com/golang-migrate/migrate/v4 v4.7.0
 	github.com/google/go-cmp v0.4.0
 	github.com/gorilla/mux v1.7.1
@@ -20,6 +22,7 @@ require (
 	github.com/minio/sha256-simd v0.1.1 // indirect
 	github.com/nsf/jsondiff v0.0.0-20160203110537-7de28ed2b6e3
 	github.com/nsf/termbox-go v0.0.0-20190121233118-02980233997d
+	github.com/oschwald/maxminddb-golang v1.6.0
 	github.com/shopspring/decimal v0.0.0-20200105231215-408a2507e114
 	github.com/spacemonkeygo/monkit/v3 v3.0.6
 	github.com/spf13/cast v1.3.0
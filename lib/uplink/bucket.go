@@ -10,8 +10,10 @@ import (
 
 	"github.com/zeebo/errs"
 
+	"storj.io/common/encryption"
 	"storj.io/common/storj"
 	"storj.io/uplink/private/metainfo/kvmetainfo"
+	"storj.io/uplink/private/storage/segments"
 	"storj.io/uplink/private/storage/streams"
 	"storj.io/uplink/private/stream"
 )
@@ -23,8 +25,11 @@ type Bucket struct {
 	Created time.Time
 
 	bucket   storj.Bucket
+	project  *Project
+	encStore *encryption.Store
 	metainfo *kvmetainfo.DB
 	streams  streams.Store
+	segments segments.Store
 }
 
 // TODO: move the object related OpenObject to object.go
@@ -136,7 +141,33 @@ func (b *Bucket) NewWriter(ctx context.Context, path storj.Path, opts *UploadOpt
 	if opts == nil {
 		opts = &UploadOptions{}
 	}
+	b.setUploadDefaults(opts)
 
+	createInfo := kvmetainfo.CreateObject{
+		ContentType:          opts.ContentType,
+		Metadata:             opts.Metadata,
+		Expires:              opts.Expires,
+		RedundancyScheme:     opts.Volatile.RedundancyScheme,
+		EncryptionParameters: opts.Volatile.EncryptionParameters,
+	}
+
+	obj, err := b.metainfo.CreateObject(ctx, b.bucket, path, &createInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	mutableStream, err := obj.CreateStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := stream.NewUpload(ctx, mutableStream, b.streams)
+	return upload, nil
+}
+
+// setUploadDefaults sets the redundancy scheme and the encryption parameters
+// of opts which aren't set to the defaults of the bucket.
+func (b *Bucket) setUploadDefaults(opts *UploadOptions) {
 	if opts.Volatile.RedundancyScheme.Algorithm == 0 {
 		opts.Volatile.RedundancyScheme.Algorithm = b.Volatile.RedundancyScheme.Algorithm
 	}
@@ -161,26 +192,6 @@ func (b *Bucket) NewWriter(ctx context.Context, path storj.Path, opts *UploadOpt
 	if opts.Volatile.EncryptionParameters.BlockSize == 0 {
 		opts.Volatile.EncryptionParameters.BlockSize = b.EncryptionParameters.BlockSize
 	}
-	createInfo := kvmetainfo.CreateObject{
-		ContentType:          opts.ContentType,
-		Metadata:             opts.Metadata,
-		Expires:              opts.Expires,
-		RedundancyScheme:     opts.Volatile.RedundancyScheme,
-		EncryptionParameters: opts.Volatile.EncryptionParameters,
-	}
-
-	obj, err := b.metainfo.CreateObject(ctx, b.bucket, path, &createInfo)
-	if err != nil {
-		return nil, err
-	}
-
-	mutableStream, err := obj.CreateStream(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	upload := stream.NewUpload(ctx, mutableStream, b.streams)
-	return upload, nil
 }
 
 // NewReader creates a new reader that downloads the object data.
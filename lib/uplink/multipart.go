@@ -0,0 +1,184 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"storj.io/common/encryption"
+	"storj.io/common/paths"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/uplink/private/eestream"
+	"storj.io/uplink/private/metainfo"
+)
+
+// MultipartPart is an uploaded part of a multipart upload.
+type MultipartPart struct {
+	// Index is the index of the part, and of its segment, in the object.
+	Index int64 `json:"index"`
+	// Size is the size of the part.
+	Size int64 `json:"size"`
+	// EncryptedKey and KeyNonce are the content key of the part, encrypted
+	// with the key derived for the path of the object. The key of the last
+	// part is needed to commit the object.
+	EncryptedKey storj.EncryptedPrivateKey `json:"encryptedKey"`
+	KeyNonce     storj.Nonce               `json:"keyNonce"`
+}
+
+// BeginMultipartUpload begins an upload of the object at path in parts, if
+// authorized. Every part is stored as a segment of the object, so the parts
+// can be uploaded concurrently and in any order with UploadPart. The object
+// is stored once the upload is committed with CommitMultipartUpload.
+//
+// The returned stream ID identifies the upload. Only the expiration of opts
+// is used, the other options are given when the upload is committed.
+func (b *Bucket) BeginMultipartUpload(ctx context.Context, path storj.Path, opts *UploadOptions) (_ storj.StreamID, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+
+	encPath, err := encryption.EncryptPathWithStoreCipher(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	response, err := b.project.metainfo.BeginObject(ctx, metainfo.BeginObjectParams{
+		Bucket:        []byte(b.Name),
+		EncryptedPath: []byte(encPath.Raw()),
+		ExpiresAt:     opts.Expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.StreamID, nil
+}
+
+// UploadPart uploads size bytes of data as the part at index of the multipart
+// upload of the object at path identified by streamID. A part uploaded again
+// replaces the previous upload of the part, whose pieces the satellite
+// deletes.
+//
+// All parts of an object, except the last, must have the same size, which
+// can't exceed the maximum segment size of the satellite.
+func (b *Bucket) UploadPart(ctx context.Context, path storj.Path, streamID storj.StreamID, index int64, data io.Reader, size int64) (_ MultipartPart, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if index < 0 {
+		return MultipartPart{}, Error.New("invalid part index %d", index)
+	}
+	if size < 0 {
+		return MultipartPart{}, Error.New("invalid part size %d", size)
+	}
+
+	opts := &UploadOptions{}
+	b.setUploadDefaults(opts)
+	encryptionParameters := opts.Volatile.EncryptionParameters
+
+	satStreamID := &pb.SatStreamID{}
+	if err := pb.Unmarshal(streamID, satStreamID); err != nil {
+		return MultipartPart{}, Error.New("invalid stream id: %v", err)
+	}
+	rs, err := eestream.NewRedundancyStrategyFromProto(satStreamID.Redundancy)
+	if err != nil {
+		return MultipartPart{}, Error.Wrap(err)
+	}
+
+	derivedKey, err := encryption.DeriveContentKey(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return MultipartPart{}, Error.Wrap(err)
+	}
+	maxEncryptedSegmentSize, err := encryption.CalcEncryptedSize(size, encryptionParameters)
+	if err != nil {
+		return MultipartPart{}, Error.Wrap(err)
+	}
+
+	key, err := randomSegmentKey(encryptionParameters.CipherSuite, derivedKey)
+	if err != nil {
+		return MultipartPart{}, err
+	}
+
+	err = b.uploadSegment(ctx, segmentUpload{
+		streamID:                streamID,
+		rs:                      rs,
+		encryption:              encryptionParameters,
+		expires:                 satStreamID.ExpirationDate,
+		maxEncryptedSegmentSize: maxEncryptedSegmentSize,
+		index:                   index,
+		key:                     key,
+		data:                    data,
+		size:                    size,
+	})
+	if err != nil {
+		return MultipartPart{}, err
+	}
+
+	return MultipartPart{
+		Index:        index,
+		Size:         size,
+		EncryptedKey: key.encryptedKey,
+		KeyNonce:     key.keyNonce,
+	}, nil
+}
+
+// CommitMultipartUpload commits the object at path of the multipart upload
+// identified by streamID, made of parts. The parts must have consecutive
+// indexes from zero and, except the last, the same size. The content type
+// and the metadata of opts are stored with the object.
+func (b *Bucket) CommitMultipartUpload(ctx context.Context, path storj.Path, streamID storj.StreamID, parts []MultipartPart, opts *UploadOptions) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(parts) == 0 {
+		return Error.New("no parts uploaded")
+	}
+
+	parts = append([]MultipartPart(nil), parts...)
+	sort.Slice(parts, func(i, k int) bool {
+		return parts[i].Index < parts[k].Index
+	})
+
+	partSize := parts[0].Size
+	last := parts[len(parts)-1]
+	for i, part := range parts {
+		if part.Index != int64(i) {
+			return Error.New("part %d is missing", i)
+		}
+		if i < len(parts)-1 && part.Size != partSize {
+			return Error.New("part %d has size %d, expected %d", part.Index, part.Size, partSize)
+		}
+	}
+	if len(parts) > 1 && last.Size > partSize {
+		return Error.New("last part has size %d, larger than %d", last.Size, partSize)
+	}
+
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	commitOpts := &UploadOptions{
+		ContentType: opts.ContentType,
+		Metadata:    opts.Metadata,
+	}
+	b.setUploadDefaults(commitOpts)
+	cipher := commitOpts.Volatile.EncryptionParameters.CipherSuite
+
+	derivedKey, err := encryption.DeriveContentKey(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	contentKey, err := encryption.DecryptKey(last.EncryptedKey, cipher, derivedKey, &last.KeyNonce)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	lastKey := segmentKey{
+		contentKey:   *contentKey,
+		encryptedKey: last.EncryptedKey,
+		keyNonce:     last.KeyNonce,
+	}
+	return b.commitStream(ctx, streamID, commitOpts, int64(len(parts)), partSize, last.Size, lastKey)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/lib/uplink"
+	"storj.io/storj/private/testplanet"
+)
+
+func TestBucketMultipartUpload(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+
+		require.NoError(t, client.CreateBucket(ctx, satellite, "bucket"))
+
+		project, bucket, err := client.GetProjectAndBucket(ctx, satellite, "bucket", client.GetConfig(satellite))
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+		defer ctx.Check(bucket.Close)
+
+		partSize := 10 * memory.KiB.Int()
+		expectedData := testrand.BytesInt(2*partSize + 3*memory.KiB.Int())
+
+		streamID, err := bucket.BeginMultipartUpload(ctx, "multipart", nil)
+		require.NoError(t, err)
+
+		// the parts are uploaded out of order
+		var parts []uplink.MultipartPart
+		for _, index := range []int{2, 0, 1} {
+			start, end := index*partSize, (index+1)*partSize
+			if end > len(expectedData) {
+				end = len(expectedData)
+			}
+			part, err := bucket.UploadPart(ctx, "multipart", streamID, int64(index), bytes.NewReader(expectedData[start:end]), int64(end-start))
+			require.NoError(t, err)
+			require.EqualValues(t, index, part.Index)
+			parts = append(parts, part)
+		}
+
+		// all parts are required
+		err = bucket.CommitMultipartUpload(ctx, "multipart", streamID, parts[1:], nil)
+		require.Error(t, err)
+
+		err = bucket.CommitMultipartUpload(ctx, "multipart", streamID, parts, &uplink.UploadOptions{
+			ContentType: "text/plain",
+			Metadata:    map[string]string{"key": "value"},
+		})
+		require.NoError(t, err)
+
+		object, err := bucket.OpenObject(ctx, "multipart")
+		require.NoError(t, err)
+		defer ctx.Check(object.Close)
+
+		require.Equal(t, int64(len(expectedData)), object.Meta.Size)
+		require.Equal(t, "text/plain", object.Meta.ContentType)
+		require.Equal(t, "value", object.Meta.Metadata["key"])
+
+		reader, err := object.DownloadRange(ctx, 0, -1)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, expectedData, data)
+
+		// a range across parts is downloaded
+		reader, err = bucket.DownloadRange(ctx, "multipart", int64(partSize-100), 200)
+		require.NoError(t, err)
+		data, err = ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, expectedData[partSize-100:partSize+100], data)
+	})
+}
+
+func TestBucketMultipartUploadPartAgain(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+
+		require.NoError(t, client.CreateBucket(ctx, satellite, "bucket"))
+
+		project, bucket, err := client.GetProjectAndBucket(ctx, satellite, "bucket", client.GetConfig(satellite))
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+		defer ctx.Check(bucket.Close)
+
+		usedSpace := func() (total int64) {
+			for _, node := range planet.StorageNodes {
+				piecesTotal, _, err := node.Storage2.Store.SpaceUsedForPieces(ctx)
+				require.NoError(t, err)
+				total += piecesTotal
+			}
+			return total
+		}
+
+		streamID, err := bucket.BeginMultipartUpload(ctx, "multipart", nil)
+		require.NoError(t, err)
+
+		data := testrand.BytesInt(10 * memory.KiB.Int())
+		_, err = bucket.UploadPart(ctx, "multipart", streamID, 0, bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		usedAfterUpload := usedSpace()
+
+		// the pieces of the replaced part are deleted
+		expectedData := testrand.BytesInt(len(data))
+		part, err := bucket.UploadPart(ctx, "multipart", streamID, 0, bytes.NewReader(expectedData), int64(len(expectedData)))
+		require.NoError(t, err)
+		planet.WaitForStorageNodeDeleters(ctx)
+		require.Less(t, usedSpace(), 2*usedAfterUpload)
+
+		err = bucket.CommitMultipartUpload(ctx, "multipart", streamID, []uplink.MultipartPart{part}, nil)
+		require.NoError(t, err)
+
+		reader, err := bucket.Download(ctx, "multipart")
+		require.NoError(t, err)
+		downloaded, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, expectedData, downloaded)
+	})
+}
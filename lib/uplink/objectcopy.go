@@ -0,0 +1,154 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"crypto/rand"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/encryption"
+	"storj.io/common/errs2"
+	"storj.io/common/paths"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+// CopyObject copies the object at path to newPath in the bucket named
+// newBucket, if authorized. The object isn't downloaded and uploaded again,
+// the copy shares the stored data of the object instead.
+//
+// An object already stored at newPath is replaced.
+func (b *Bucket) CopyObject(ctx context.Context, path storj.Path, newBucket string, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return b.copyObject(ctx, path, newBucket, newPath, false)
+}
+
+// MoveObject moves the object at path to newPath in the bucket named
+// newBucket, if authorized. The object isn't downloaded and uploaded again.
+//
+// An object already stored at newPath is replaced.
+func (b *Bucket) MoveObject(ctx context.Context, path storj.Path, newBucket string, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return b.copyObject(ctx, path, newBucket, newPath, true)
+}
+
+// copyObject copies or moves the object at path to newPath.
+//
+// The satellite can't decrypt the paths nor the keys of the object, hence
+// they are encrypted for the new path here.
+func (b *Bucket) copyObject(ctx context.Context, path storj.Path, newBucket string, newPath storj.Path, move bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	encPath, err := encryption.EncryptPathWithStoreCipher(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	newEncPath, err := encryption.EncryptPathWithStoreCipher(newBucket, paths.NewUnencrypted(newPath), b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	conn, err := b.project.dialer.DialAddressInsecureBestEffort(ctx, b.project.satelliteAddr)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	client := metainfopb.NewDRPCObjectCopyClient(conn)
+	header := b.requestHeader()
+
+	begin, err := client.BeginCopyObject(ctx, &metainfopb.ObjectBeginCopyRequest{
+		Header:        header,
+		Bucket:        []byte(b.Name),
+		EncryptedPath: []byte(encPath.Raw()),
+	})
+	if err != nil {
+		return convertCopyError(err)
+	}
+
+	derivedKey, err := encryption.DeriveContentKey(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	newDerivedKey, err := encryption.DeriveContentKey(newBucket, paths.NewUnencrypted(newPath), b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	newKeys, err := reencryptSegmentKeys(begin, derivedKey, newDerivedKey)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if move {
+		_, err = client.FinishMoveObject(ctx, &metainfopb.ObjectFinishMoveRequest{
+			Header:           header,
+			Bucket:           []byte(b.Name),
+			EncryptedPath:    []byte(encPath.Raw()),
+			NewBucket:        []byte(newBucket),
+			NewEncryptedPath: []byte(newEncPath.Raw()),
+			NewSegmentKeys:   newKeys,
+		})
+	} else {
+		_, err = client.FinishCopyObject(ctx, &metainfopb.ObjectFinishCopyRequest{
+			Header:           header,
+			Bucket:           []byte(b.Name),
+			EncryptedPath:    []byte(encPath.Raw()),
+			NewBucket:        []byte(newBucket),
+			NewEncryptedPath: []byte(newEncPath.Raw()),
+			NewSegmentKeys:   newKeys,
+		})
+	}
+	return convertCopyError(err)
+}
+
+// reencryptSegmentKeys decrypts the segment keys with the key derived from
+// the old path and encrypts them with the key derived from the new path.
+func reencryptSegmentKeys(begin *metainfopb.ObjectBeginCopyResponse, derivedKey, newDerivedKey *storj.Key) (newKeys []*metainfopb.SegmentKey, err error) {
+	cipher := storj.CipherSuite(begin.EncryptionParameters.GetCipherSuite())
+
+	for _, key := range begin.SegmentKeys {
+		keyNonce, err := storj.NonceFromBytes(key.EncryptedKeyNonce)
+		if err != nil {
+			return nil, err
+		}
+
+		contentKey, err := encryption.DecryptKey(key.EncryptedKey, cipher, derivedKey, &keyNonce)
+		if err != nil {
+			return nil, err
+		}
+
+		var newKeyNonce storj.Nonce
+		if _, err := rand.Read(newKeyNonce[:]); err != nil {
+			return nil, err
+		}
+
+		newEncryptedKey, err := encryption.EncryptKey(contentKey, cipher, newDerivedKey, &newKeyNonce)
+		if err != nil {
+			return nil, err
+		}
+
+		newKeys = append(newKeys, &metainfopb.SegmentKey{
+			Position:          key.Position,
+			EncryptedKeyNonce: newKeyNonce[:],
+			EncryptedKey:      newEncryptedKey,
+		})
+	}
+	return newKeys, nil
+}
+
+// convertCopyError converts the errors of the object copy RPCs.
+func convertCopyError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errs2.IsRPC(err, rpcstatus.NotFound):
+		return storj.ErrObjectNotFound.Wrap(err)
+	default:
+		return Error.Wrap(err)
+	}
+}
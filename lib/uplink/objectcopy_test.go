@@ -0,0 +1,58 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+)
+
+func TestBucketCopyAndMoveObject(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+
+		expectedData := testrand.Bytes(10 * memory.KiB)
+		require.NoError(t, client.Upload(ctx, satellite, "bucket", "original", expectedData))
+		require.NoError(t, client.CreateBucket(ctx, satellite, "other-bucket"))
+
+		project, bucket, err := client.GetProjectAndBucket(ctx, satellite, "bucket", client.GetConfig(satellite))
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+		defer ctx.Check(bucket.Close)
+
+		err = bucket.CopyObject(ctx, "original", "bucket", "copy")
+		require.NoError(t, err)
+
+		// the copy shares the pieces, hence they must survive the deletion
+		// of the original object
+		require.NoError(t, client.DeleteObject(ctx, satellite, "bucket", "original"))
+
+		data, err := client.Download(ctx, satellite, "bucket", "copy")
+		require.NoError(t, err)
+		require.Equal(t, expectedData, data)
+
+		err = bucket.MoveObject(ctx, "copy", "other-bucket", "moved")
+		require.NoError(t, err)
+
+		data, err = client.Download(ctx, satellite, "other-bucket", "moved")
+		require.NoError(t, err)
+		require.Equal(t, expectedData, data)
+
+		_, err = client.Download(ctx, satellite, "bucket", "copy")
+		require.Error(t, err)
+
+		err = bucket.CopyObject(ctx, "missing", "bucket", "copy")
+		require.True(t, storj.ErrObjectNotFound.Has(err))
+	})
+}
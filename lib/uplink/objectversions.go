@@ -0,0 +1,264 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/encryption"
+	"storj.io/common/errs2"
+	"storj.io/common/paths"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/metainfopb"
+	"storj.io/uplink/private/storage/streams"
+)
+
+// ObjectVersion is an object, an archived version of an object or a delete
+// marker of a bucket with versioning.
+type ObjectVersion struct {
+	Path string
+	// Version is 0 for the current object and the number of the archived
+	// version otherwise. Newer versions have higher numbers.
+	Version int32
+	// IsPrefix is set for the prefixes of a non-recursive listing.
+	IsPrefix bool
+	// IsDeleteMarker is set for the versions which were created by deleting
+	// the object. They don't have any data nor metadata.
+	IsDeleteMarker bool
+
+	ContentType string
+	Metadata    map[string]string
+	Created     time.Time
+	Expires     time.Time
+	Size        int64
+}
+
+// ObjectVersionListOptions controls options for the ListObjectVersions()
+// call.
+type ObjectVersionListOptions struct {
+	Prefix string
+	// Cursor and CursorVersion are the path and the version of the item
+	// after which the listing starts.
+	Cursor        string
+	CursorVersion int32
+	Recursive     bool
+	Limit         int
+}
+
+// ObjectVersionList is a page of object versions.
+type ObjectVersionList struct {
+	Bucket string
+	Prefix string
+	More   bool
+	Items  []ObjectVersion
+}
+
+// NextPage returns the options to list the page after list.
+func (opts ObjectVersionListOptions) NextPage(list ObjectVersionList) ObjectVersionListOptions {
+	if !list.More || len(list.Items) == 0 {
+		return ObjectVersionListOptions{}
+	}
+
+	last := list.Items[len(list.Items)-1]
+	return ObjectVersionListOptions{
+		Prefix:        opts.Prefix,
+		Cursor:        last.Path,
+		CursorVersion: last.Version,
+		Recursive:     opts.Recursive,
+		Limit:         opts.Limit,
+	}
+}
+
+// ListObjectVersions lists the objects of the bucket together with their
+// archived versions and delete markers, if authorized.
+//
+// The current object is listed first with version 0, followed by its archived
+// versions from the newest to the oldest one. An archived version is
+// downloaded by restoring it with RestoreObjectVersion.
+func (b *Bucket) ListObjectVersions(ctx context.Context, opts *ObjectVersionListOptions) (list ObjectVersionList, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if opts == nil {
+		opts = &ObjectVersionListOptions{}
+	}
+
+	prefix := streams.ParsePath(storj.JoinPaths(b.Name, opts.Prefix))
+	prefixKey, err := encryption.DerivePathKey(prefix.Bucket(), streams.PathForKey(prefix.UnencryptedPath().Raw()), b.encStore)
+	if err != nil {
+		return ObjectVersionList{}, Error.Wrap(err)
+	}
+
+	encPrefix, err := encryption.EncryptPathWithStoreCipher(prefix.Bucket(), prefix.UnencryptedPath(), b.encStore)
+	if err != nil {
+		return ObjectVersionList{}, Error.Wrap(err)
+	}
+
+	// listing `bob/` has to use the prefix `enc("bob")` rather than
+	// `enc("bob")/enc("")`
+	if strings.HasSuffix(prefix.UnencryptedPath().Raw(), "/") {
+		lastSlashIdx := strings.LastIndex(encPrefix.Raw(), "/")
+		encPrefix = paths.NewEncrypted(encPrefix.Raw()[:lastSlashIdx])
+	}
+
+	_, _, base := b.encStore.LookupEncrypted(prefix.Bucket(), encPrefix)
+	pathCipher := base.PathCipher
+	if b.encStore.EncryptionBypass {
+		pathCipher = storj.EncNullBase64URL
+	}
+
+	var cursor []byte
+	if opts.Cursor != "" {
+		encCursor, err := encryption.EncryptPathRaw(opts.Cursor, pathCipher, prefixKey)
+		if err != nil {
+			return ObjectVersionList{}, Error.Wrap(err)
+		}
+		cursor = []byte(encCursor)
+		if opts.CursorVersion > 0 {
+			// the satellite expects the version after a NUL byte, which
+			// encrypted paths never contain
+			cursor = append(cursor, 0)
+			cursor = strconv.AppendInt(cursor, int64(opts.CursorVersion), 10)
+		}
+	}
+
+	conn, err := b.project.dialer.DialAddressInsecureBestEffort(ctx, b.project.satelliteAddr)
+	if err != nil {
+		return ObjectVersionList{}, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	response, err := metainfopb.NewDRPCObjectVersionsClient(conn).ListObjectVersions(ctx, &pb.ObjectListRequest{
+		Header:          b.requestHeader(),
+		Bucket:          []byte(b.Name),
+		EncryptedPrefix: []byte(encPrefix.Raw()),
+		EncryptedCursor: cursor,
+		Limit:           int32(opts.Limit),
+		Recursive:       opts.Recursive,
+	})
+	if err != nil {
+		return ObjectVersionList{}, convertVersionsError(err)
+	}
+
+	list = ObjectVersionList{
+		Bucket: b.Name,
+		Prefix: opts.Prefix,
+		More:   response.More,
+		Items:  make([]ObjectVersion, 0, len(response.Items)),
+	}
+
+	for _, item := range response.Items {
+		itemPath, err := encryption.DecryptPathRaw(string(item.EncryptedPath), pathCipher, prefixKey)
+		if err != nil {
+			return ObjectVersionList{}, Error.Wrap(err)
+		}
+
+		version := ObjectVersion{
+			Path:           itemPath,
+			Version:        item.Version,
+			IsPrefix:       !opts.Recursive && strings.HasSuffix(string(item.EncryptedPath), "/"),
+			IsDeleteMarker: item.Status == pb.Object_DELETING,
+			Created:        item.CreatedAt,
+			Expires:        item.ExpiresAt,
+		}
+
+		if !version.IsPrefix && len(item.EncryptedMetadata) > 0 {
+			fullPath := prefix.UnencryptedPath().Raw()
+			if len(fullPath) > 0 && fullPath[len(fullPath)-1] != '/' {
+				fullPath += "/"
+			}
+			fullPath += itemPath
+
+			path := streams.CreatePath(prefix.Bucket(), paths.NewUnencrypted(fullPath))
+			stream, streamMeta, err := streams.TypedDecryptStreamInfo(ctx, item.EncryptedMetadata, path, b.encStore)
+			if err != nil {
+				return ObjectVersionList{}, Error.Wrap(err)
+			}
+			if err := version.setStreamInfo(stream, streamMeta); err != nil {
+				return ObjectVersionList{}, Error.Wrap(err)
+			}
+		}
+
+		list.Items = append(list.Items, version)
+	}
+
+	return list, nil
+}
+
+// setStreamInfo sets the size and the metadata of the version from the
+// decrypted stream info.
+func (version *ObjectVersion) setStreamInfo(stream *pb.StreamInfo, streamMeta pb.StreamMeta) error {
+	if stream == nil {
+		return nil
+	}
+
+	serializableMeta := pb.SerializableMeta{}
+	if err := pb.Unmarshal(stream.Metadata, &serializableMeta); err != nil {
+		return err
+	}
+
+	segmentCount := streamMeta.NumberOfSegments
+	if segmentCount == 0 {
+		segmentCount = stream.DeprecatedNumberOfSegments
+	}
+
+	version.ContentType = serializableMeta.ContentType
+	version.Metadata = serializableMeta.UserDefined
+	version.Size = (segmentCount-1)*stream.SegmentsSize + stream.LastSegmentSize
+	return nil
+}
+
+// RestoreObjectVersion makes the archived version of the object at path the
+// current object again, if authorized.
+//
+// The current object is archived as a new version when versioning is enabled
+// on the bucket and it's replaced otherwise.
+func (b *Bucket) RestoreObjectVersion(ctx context.Context, path storj.Path, version int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	encPath, err := encryption.EncryptPathWithStoreCipher(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	conn, err := b.project.dialer.DialAddressInsecureBestEffort(ctx, b.project.satelliteAddr)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	_, err = metainfopb.NewDRPCObjectVersionsClient(conn).RestoreObjectVersion(ctx, &metainfopb.ObjectRestoreVersionRequest{
+		Header:        b.requestHeader(),
+		Bucket:        []byte(b.Name),
+		EncryptedPath: []byte(encPath.Raw()),
+		Version:       version,
+	})
+	return convertVersionsError(err)
+}
+
+// requestHeader returns the header of the requests of the RPCs which aren't
+// part of the metainfo client.
+func (b *Bucket) requestHeader() *pb.RequestHeader {
+	return &pb.RequestHeader{
+		ApiKey:    b.project.apiKey.key.SerializeRaw(),
+		UserAgent: []byte(b.project.uplinkCfg.Volatile.UserAgent),
+	}
+}
+
+// convertVersionsError converts the errors of the object versions RPCs.
+func convertVersionsError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errs2.IsRPC(err, rpcstatus.NotFound):
+		return storj.ErrObjectNotFound.Wrap(err)
+	default:
+		return Error.Wrap(err)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/lib/uplink"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestBucketListAndRestoreObjectVersions(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+		projectID := client.Projects[0].ID
+
+		require.NoError(t, client.CreateBucket(ctx, satellite, "versioned"))
+		require.NoError(t, satellite.Metainfo.Service.UpdateBucketVersioning(ctx, []byte("versioned"), projectID, metainfo.VersioningEnabled))
+
+		first, second := testrand.Bytes(10*memory.KiB), testrand.Bytes(5*memory.KiB)
+		require.NoError(t, client.Upload(ctx, satellite, "versioned", "dir/object", first))
+		require.NoError(t, client.Upload(ctx, satellite, "versioned", "dir/object", second))
+		require.NoError(t, client.Upload(ctx, satellite, "versioned", "deleted", first))
+		require.NoError(t, client.DeleteObject(ctx, satellite, "versioned", "deleted"))
+
+		project, bucket, err := client.GetProjectAndBucket(ctx, satellite, "versioned", client.GetConfig(satellite))
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+		defer ctx.Check(bucket.Close)
+
+		list, err := bucket.ListObjectVersions(ctx, &uplink.ObjectVersionListOptions{Prefix: "dir/"})
+		require.NoError(t, err)
+		require.False(t, list.More)
+		require.Len(t, list.Items, 2)
+		require.Equal(t, "object", list.Items[0].Path)
+		require.EqualValues(t, 0, list.Items[0].Version)
+		require.EqualValues(t, len(second), list.Items[0].Size)
+		require.Equal(t, "object", list.Items[1].Path)
+		require.EqualValues(t, 1, list.Items[1].Version)
+		require.EqualValues(t, len(first), list.Items[1].Size)
+
+		// the pages continue after an archived version
+		opts := uplink.ObjectVersionListOptions{Recursive: true, Limit: 1}
+		versions := map[string][]uplink.ObjectVersion{}
+		for {
+			list, err := bucket.ListObjectVersions(ctx, &opts)
+			require.NoError(t, err)
+			for _, item := range list.Items {
+				versions[item.Path] = append(versions[item.Path], item)
+			}
+			if !list.More {
+				break
+			}
+			opts = opts.NextPage(list)
+		}
+		require.Len(t, versions["dir/object"], 2)
+		require.Len(t, versions["deleted"], 2)
+		require.True(t, versions["deleted"][0].IsDeleteMarker)
+		require.EqualValues(t, 2, versions["deleted"][0].Version)
+		require.False(t, versions["deleted"][1].IsDeleteMarker)
+		require.EqualValues(t, len(first), versions["deleted"][1].Size)
+
+		// restoring the archived version archives the current object
+		require.NoError(t, bucket.RestoreObjectVersion(ctx, "dir/object", 1))
+
+		data, err := client.Download(ctx, satellite, "versioned", "dir/object")
+		require.NoError(t, err)
+		require.Equal(t, first, data)
+
+		list, err = bucket.ListObjectVersions(ctx, &uplink.ObjectVersionListOptions{Prefix: "dir/"})
+		require.NoError(t, err)
+		require.Len(t, list.Items, 2)
+		require.EqualValues(t, len(second), list.Items[1].Size)
+
+		err = bucket.RestoreObjectVersion(ctx, "dir/object", 10)
+		require.True(t, storj.ErrObjectNotFound.Has(err))
+	})
+}
@@ -0,0 +1,307 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/uplink/private/metainfo/kvmetainfo"
+	"storj.io/uplink/private/stream"
+)
+
+// ParallelDownloadOptions controls a parallel download of an object.
+type ParallelDownloadOptions struct {
+	// Parallelism is the number of segments downloaded concurrently. Every
+	// segment is streamed into the destination, so the memory used grows
+	// with the parallelism and not with the size of the object.
+	Parallelism int
+	// StatePath, if set, is the local file where the downloaded ranges and
+	// the SHA-256 hashes of their data are recorded. A download of the same
+	// object recorded there is resumed: the recorded ranges are read back
+	// from the destination, when it is an io.ReaderAt, and are only
+	// downloaded again when their data doesn't match. The file is removed
+	// when the download completes.
+	StatePath string
+	// Progress, if set, is called with the size of every downloaded range,
+	// including the ranges verified when the download is resumed. It may be
+	// called concurrently.
+	Progress func(bytes int64)
+}
+
+// DownloadState is the progress of a parallel download, as persisted in the
+// state file.
+type DownloadState struct {
+	Bucket    string     `json:"bucket"`
+	Path      storj.Path `json:"path"`
+	Size      int64      `json:"size"`
+	Modified  time.Time  `json:"modified"`
+	RangeSize int64      `json:"rangeSize"`
+	// Completed maps the index of every downloaded range to the hex encoded
+	// SHA-256 hash of its data.
+	Completed map[int64]string `json:"completed"`
+}
+
+// parallelDownload downloads the ranges of an object, one segment each, in
+// parallel.
+type parallelDownload struct {
+	object *Object
+	stream kvmetainfo.ReadOnlyStream
+	w      io.WriterAt
+	opts   *ParallelDownloadOptions
+
+	rangeSize  int64
+	rangeCount int64
+
+	mu    sync.Mutex
+	state DownloadState
+}
+
+// DownloadParallel downloads the object at path into w, if authorized.
+// See Object.DownloadParallel.
+func (b *Bucket) DownloadParallel(ctx context.Context, path storj.Path, w io.WriterAt, opts *ParallelDownloadOptions) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	object, err := b.OpenObject(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, object.Close()) }()
+
+	return object.DownloadParallel(ctx, w, opts)
+}
+
+// DownloadParallel downloads the object data into w, downloading
+// opts.Parallelism segments concurrently. Every segment is written at its
+// offset in the object.
+func (o *Object) DownloadParallel(ctx context.Context, w io.WriterAt, opts *ParallelDownloadOptions) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if opts == nil {
+		opts = &ParallelDownloadOptions{}
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = 1
+	}
+
+	rangeSize := o.Meta.Volatile.SegmentsSize
+	if rangeSize <= 0 {
+		rangeSize = o.Meta.Size
+	}
+	var rangeCount int64
+	if rangeSize > 0 {
+		rangeCount = (o.Meta.Size + rangeSize - 1) / rangeSize
+	}
+
+	segmentStream, err := o.metainfoDB.GetObjectStream(ctx, o.bucket, o.object)
+	if err != nil {
+		return err
+	}
+
+	download := &parallelDownload{
+		object:     o,
+		stream:     segmentStream,
+		w:          w,
+		opts:       opts,
+		rangeSize:  rangeSize,
+		rangeCount: rangeCount,
+	}
+
+	if err := download.load(); err != nil {
+		return err
+	}
+	if err := download.run(ctx); err != nil {
+		return err
+	}
+
+	if opts.StatePath != "" {
+		if err := os.Remove(opts.StatePath); err != nil && !os.IsNotExist(err) {
+			return Error.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// ReadDownloadState reads the progress of a parallel download from the state
+// file at statePath.
+func ReadDownloadState(statePath string) (_ *DownloadState, err error) {
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &DownloadState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, Error.New("invalid download state %q: %v", statePath, err)
+	}
+	return state, nil
+}
+
+// load loads the ranges recorded in the state file, which are still present
+// in the destination.
+func (download *parallelDownload) load() error {
+	meta := download.object.Meta
+	download.state = DownloadState{
+		Bucket:    meta.Bucket,
+		Path:      meta.Path,
+		Size:      meta.Size,
+		Modified:  meta.Modified,
+		RangeSize: download.rangeSize,
+		Completed: make(map[int64]string),
+	}
+
+	if download.opts.StatePath == "" {
+		return nil
+	}
+
+	state, err := ReadDownloadState(download.opts.StatePath)
+	if err != nil {
+		if !os.IsNotExist(err) && !Error.Has(err) {
+			return Error.Wrap(err)
+		}
+		return nil
+	}
+
+	if state.Bucket != meta.Bucket || state.Path != meta.Path || state.Size != meta.Size ||
+		!state.Modified.Equal(meta.Modified) || state.RangeSize != download.rangeSize {
+		return nil
+	}
+
+	// the recorded ranges can only be trusted when their data is verified
+	reader, ok := download.w.(io.ReaderAt)
+	if !ok {
+		return nil
+	}
+
+	for index, hash := range state.Completed {
+		if index < 0 || index >= download.rangeCount {
+			continue
+		}
+
+		offset, length := download.rangeAt(index)
+		h := sha256.New()
+		_, err := io.Copy(h, io.NewSectionReader(reader, offset, length))
+		if err != nil || hex.EncodeToString(h.Sum(nil)) != hash {
+			continue
+		}
+
+		download.state.Completed[index] = hash
+		download.progress(length)
+	}
+	return nil
+}
+
+// run downloads the ranges which aren't completed yet.
+func (download *parallelDownload) run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var downloadErr error
+
+	limiter := sync2.NewLimiter(download.opts.Parallelism)
+	for index := int64(0); index < download.rangeCount; index++ {
+		if _, ok := download.state.Completed[index]; ok {
+			continue
+		}
+
+		index := index
+		started := limiter.Go(ctx, func() {
+			err := download.downloadRange(ctx, index)
+			if err != nil {
+				mu.Lock()
+				if downloadErr == nil {
+					downloadErr = err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		})
+		if !started {
+			break
+		}
+	}
+	limiter.Wait()
+
+	if downloadErr != nil {
+		return downloadErr
+	}
+	return ctx.Err()
+}
+
+// downloadRange downloads the range at index into the destination and
+// records it as completed.
+func (download *parallelDownload) downloadRange(ctx context.Context, index int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	offset, length := download.rangeAt(index)
+
+	reader := stream.NewDownloadRange(ctx, download.stream, download.object.streams, offset, length)
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(&offsetWriter{w: download.w, offset: offset}, h), reader)
+	if err != nil {
+		return err
+	}
+	if n != length {
+		return Error.New("range %d: downloaded %d bytes, expected %d", index, n, length)
+	}
+
+	if download.opts.StatePath != "" {
+		download.mu.Lock()
+		download.state.Completed[index] = hex.EncodeToString(h.Sum(nil))
+		err = writeStateFile(download.opts.StatePath, download.state)
+		download.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	download.progress(length)
+	return nil
+}
+
+// rangeAt returns the offset and the length of the range at index.
+func (download *parallelDownload) rangeAt(index int64) (offset, length int64) {
+	offset = index * download.rangeSize
+	length = download.object.Meta.Size - offset
+	if length > download.rangeSize {
+		length = download.rangeSize
+	}
+	return offset, length
+}
+
+// progress reports length bytes as downloaded.
+func (download *parallelDownload) progress(length int64) {
+	if download.opts.Progress != nil {
+		download.opts.Progress(length)
+	}
+}
+
+// offsetWriter writes sequentially into an io.WriterAt from an offset.
+type offsetWriter struct {
+	w      io.WriterAt
+	offset int64
+}
+
+// Write writes p at the current offset and advances it.
+func (ow *offsetWriter) Write(p []byte) (n int, err error) {
+	n, err = ow.w.WriteAt(p, ow.offset)
+	ow.offset += int64(n)
+	return n, err
+}
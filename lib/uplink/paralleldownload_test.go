@@ -0,0 +1,118 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/lib/uplink"
+	"storj.io/storj/private/testplanet"
+)
+
+func TestBucketDownloadParallel(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+
+		config := client.GetConfig(satellite)
+		config.Client.SegmentSize = 10 * memory.KiB
+
+		expectedData := testrand.Bytes(55 * memory.KiB)
+		require.NoError(t, client.UploadWithClientConfig(ctx, satellite, config, "bucket", "large", expectedData))
+
+		project, bucket, err := client.GetProjectAndBucket(ctx, satellite, "bucket", config)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+		defer ctx.Check(bucket.Close)
+
+		dir := ctx.Dir("download")
+		statePath := filepath.Join(dir, "download.json")
+		path := filepath.Join(dir, "large")
+
+		download := func() (downloaded int64) {
+			file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+			require.NoError(t, err)
+			defer ctx.Check(file.Close)
+
+			err = bucket.DownloadParallel(ctx, "large", file, &uplink.ParallelDownloadOptions{
+				Parallelism: 3,
+				StatePath:   statePath,
+				Progress: func(bytes int64) {
+					atomic.AddInt64(&downloaded, bytes)
+				},
+			})
+			require.NoError(t, err)
+			return downloaded
+		}
+
+		require.EqualValues(t, len(expectedData), download())
+
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expectedData, data)
+
+		_, err = uplink.ReadDownloadState(statePath)
+		require.True(t, os.IsNotExist(err))
+
+		// simulate an interrupted download, where the second range was
+		// recorded but its data was lost
+		object, err := bucket.OpenObject(ctx, "large")
+		require.NoError(t, err)
+		defer ctx.Check(object.Close)
+
+		file, err := os.OpenFile(path, os.O_RDWR, 0644)
+		require.NoError(t, err)
+		_, err = file.WriteAt(make([]byte, memory.KiB), int64(15*memory.KiB))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		state := &uplink.DownloadState{
+			Bucket:    "bucket",
+			Path:      "large",
+			Size:      object.Meta.Size,
+			Modified:  object.Meta.Modified,
+			RangeSize: object.Meta.Volatile.SegmentsSize,
+			Completed: map[int64]string{},
+		}
+		for index := int64(0); index < 3; index++ {
+			start := index * state.RangeSize
+			state.Completed[index] = sha256Hex(expectedData[start : start+state.RangeSize])
+		}
+		require.NoError(t, writeJSON(statePath, state))
+
+		// the verified ranges are reported without being downloaded again
+		require.EqualValues(t, len(expectedData), download())
+
+		data, err = ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expectedData, data)
+	})
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
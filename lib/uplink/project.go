@@ -20,10 +20,12 @@ import (
 
 // Project represents a specific project access session.
 type Project struct {
-	uplinkCfg *Config
-	dialer    rpc.Dialer
-	metainfo  *metainfo.Client
-	project   *kvmetainfo.Project
+	uplinkCfg     *Config
+	dialer        rpc.Dialer
+	satelliteAddr string
+	apiKey        APIKey
+	metainfo      *metainfo.Client
+	project       *kvmetainfo.Project
 }
 
 // BucketConfig holds information about a bucket's configuration. This is
@@ -195,8 +197,11 @@ func (p *Project) OpenBucket(ctx context.Context, bucketName string, access *Enc
 		Name:         bucketInfo.Name,
 		Created:      bucketInfo.Created,
 		bucket:       bucketInfo,
+		project:      p,
+		encStore:     access.store,
 		metainfo:     kvmetainfo.New(p.project, p.metainfo, streamStore, segmentStore, access.store),
 		streams:      streamStore,
+		segments:     segmentStore,
 	}, nil
 }
 
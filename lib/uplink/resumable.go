@@ -0,0 +1,469 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/common/encryption"
+	"storj.io/common/errs2"
+	"storj.io/common/paths"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/uplink/private/eestream"
+	"storj.io/uplink/private/metainfo"
+)
+
+// listSegmentsLimit is the number of segment indexes checked with a single
+// ListSegments request when an upload is resumed, the satellite doesn't check
+// more of them for an uncommitted object.
+const listSegmentsLimit = 100
+
+// ResumableUploadOptions controls a parallel upload which can be resumed after
+// it was interrupted.
+type ResumableUploadOptions struct {
+	UploadOptions
+
+	// StatePath is the local file where the progress of the upload is
+	// persisted. An interrupted upload of the same data recorded there is
+	// resumed. The file is removed when the upload is committed.
+	StatePath string
+	// Parallelism is the number of segments uploaded concurrently.
+	Parallelism int
+	// SourceModified identifies the version of the uploaded data. A recorded
+	// upload of data with another modification time isn't resumed.
+	SourceModified time.Time
+	// Progress, if set, is called with the size of every uploaded segment,
+	// including the segments uploaded before the upload was resumed. It may
+	// be called concurrently.
+	Progress func(bytes int64)
+}
+
+// UploadState is the progress of a resumable upload, as persisted in the state
+// file.
+type UploadState struct {
+	Bucket         string         `json:"bucket"`
+	Path           storj.Path     `json:"path"`
+	Size           int64          `json:"size"`
+	SourceModified time.Time      `json:"sourceModified"`
+	SegmentSize    int64          `json:"segmentSize"`
+	Expires        time.Time      `json:"expires"`
+	StreamID       storj.StreamID `json:"streamId"`
+	// Committed contains the indexes of the committed segments, except the
+	// last segment, which is only uploaded together with the object commit.
+	Committed []int32 `json:"committed"`
+}
+
+// resumableUpload uploads the segments of an object in parallel and records
+// each committed segment in the state file.
+type resumableUpload struct {
+	bucket *Bucket
+	path   storj.Path
+	data   io.ReaderAt
+	size   int64
+	opts   *ResumableUploadOptions
+
+	encPath                 paths.Encrypted
+	derivedKey              *storj.Key
+	segmentSize             int64
+	segmentCount            int64
+	maxEncryptedSegmentSize int64
+	rs                      eestream.RedundancyStrategy
+
+	mu    sync.Mutex
+	state UploadState
+}
+
+// UploadObjectResumable uploads size bytes of data as a new object, if
+// authorized. The segments of the object are uploaded in parallel and the
+// progress is persisted in opts.StatePath, so that an interrupted upload is
+// resumed by calling UploadObjectResumable again with the same state path.
+//
+// The satellite only accepts segments for an upload during its max commit
+// interval; an older interrupted upload is started from the beginning.
+func (b *Bucket) UploadObjectResumable(ctx context.Context, path storj.Path, data io.ReaderAt, size int64, opts *ResumableUploadOptions) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if opts == nil || opts.StatePath == "" {
+		return Error.New("upload state path is required")
+	}
+	if size < 0 {
+		return Error.New("invalid size %d", size)
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = 1
+	}
+	b.setUploadDefaults(&opts.UploadOptions)
+
+	upload, err := b.newResumableUpload(path, data, size, opts)
+	if err != nil {
+		return err
+	}
+
+	resumed, err := upload.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = upload.run(ctx)
+	if err != nil && resumed && uploadExpired(err) {
+		// the satellite doesn't accept the interrupted upload anymore
+		if err := upload.begin(ctx); err != nil {
+			return err
+		}
+		err = upload.run(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(opts.StatePath); err != nil && !os.IsNotExist(err) {
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
+// ReadUploadState reads the progress of a resumable upload from the state
+// file at statePath.
+func ReadUploadState(statePath string) (_ *UploadState, err error) {
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &UploadState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, Error.New("invalid upload state %q: %v", statePath, err)
+	}
+	return state, nil
+}
+
+func (b *Bucket) newResumableUpload(path storj.Path, data io.ReaderAt, size int64, opts *ResumableUploadOptions) (*resumableUpload, error) {
+	encPath, err := encryption.EncryptPathWithStoreCipher(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	derivedKey, err := encryption.DeriveContentKey(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	segmentSize := b.Volatile.SegmentsSize.Int64()
+	if segmentSize <= 0 {
+		return nil, Error.New("invalid segment size %d", segmentSize)
+	}
+	maxEncryptedSegmentSize, err := encryption.CalcEncryptedSize(segmentSize, opts.Volatile.EncryptionParameters)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	// an empty object is stored as a single empty segment
+	segmentCount := (size + segmentSize - 1) / segmentSize
+	if segmentCount == 0 {
+		segmentCount = 1
+	}
+
+	return &resumableUpload{
+		bucket:                  b,
+		path:                    path,
+		data:                    data,
+		size:                    size,
+		opts:                    opts,
+		encPath:                 encPath,
+		derivedKey:              derivedKey,
+		segmentSize:             segmentSize,
+		segmentCount:            segmentCount,
+		maxEncryptedSegmentSize: maxEncryptedSegmentSize,
+	}, nil
+}
+
+// load resumes the upload recorded in the state file or begins a new upload
+// when there is no matching upload to resume.
+func (upload *resumableUpload) load(ctx context.Context) (resumed bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	state, err := ReadUploadState(upload.opts.StatePath)
+	if err != nil {
+		if !os.IsNotExist(err) && !Error.Has(err) {
+			return false, Error.Wrap(err)
+		}
+		return false, upload.begin(ctx)
+	}
+
+	if state.Bucket != upload.bucket.Name || state.Path != upload.path ||
+		state.Size != upload.size || !state.SourceModified.Equal(upload.opts.SourceModified) ||
+		state.SegmentSize != upload.segmentSize || !state.Expires.Equal(upload.opts.Expires) {
+		return false, upload.begin(ctx)
+	}
+
+	satStreamID := &pb.SatStreamID{}
+	if err := pb.Unmarshal(state.StreamID, satStreamID); err != nil {
+		return false, upload.begin(ctx)
+	}
+	rs, err := eestream.NewRedundancyStrategyFromProto(satStreamID.Redundancy)
+	if err != nil {
+		return false, upload.begin(ctx)
+	}
+
+	upload.state = *state
+	upload.rs = rs
+
+	// the satellite knows which segments were committed, the state file may
+	// miss the last ones
+	committed, pending, err := upload.listCommitted(ctx)
+	if err != nil {
+		if uploadExpired(err) {
+			return false, upload.begin(ctx)
+		}
+		return false, err
+	}
+	if !pending {
+		return false, upload.begin(ctx)
+	}
+
+	upload.state.Committed = committed
+	for _, index := range committed {
+		upload.progress(index)
+	}
+	return true, upload.save()
+}
+
+// begin begins a new upload and records it in the state file.
+func (upload *resumableUpload) begin(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	response, err := upload.bucket.project.metainfo.BeginObject(ctx, metainfo.BeginObjectParams{
+		Bucket:        []byte(upload.bucket.Name),
+		EncryptedPath: []byte(upload.encPath.Raw()),
+		ExpiresAt:     upload.opts.Expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	upload.rs = response.RedundancyStrategy
+	upload.state = UploadState{
+		Bucket:         upload.bucket.Name,
+		Path:           upload.path,
+		Size:           upload.size,
+		SourceModified: upload.opts.SourceModified,
+		SegmentSize:    upload.segmentSize,
+		Expires:        upload.opts.Expires,
+		StreamID:       response.StreamID,
+		Committed:      []int32{},
+	}
+	return upload.save()
+}
+
+// listCommitted lists the committed segments of the upload. pending is false
+// when the stream doesn't belong to an uncommitted object anymore.
+func (upload *resumableUpload) listCommitted(ctx context.Context) (committed []int32, pending bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	committed = []int32{}
+	for cursor := int64(0); cursor < upload.segmentCount-1; cursor += listSegmentsLimit {
+		items, _, err := upload.bucket.project.metainfo.ListSegments(ctx, metainfo.ListSegmentsParams{
+			StreamID:       upload.state.StreamID,
+			CursorPosition: storj.SegmentPosition{Index: int32(cursor)},
+			Limit:          listSegmentsLimit,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, item := range items {
+			index := int64(item.Position.Index)
+			if index < 0 || index >= upload.segmentCount-1 {
+				// the last segment is only listed for a committed object
+				return nil, false, nil
+			}
+			committed = append(committed, item.Position.Index)
+		}
+	}
+	return committed, true, nil
+}
+
+// run uploads the segments which aren't committed yet and commits the object.
+func (upload *resumableUpload) run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	committed := make(map[int64]bool, len(upload.state.Committed))
+	for _, index := range upload.state.Committed {
+		committed[int64(index)] = true
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var uploadErr error
+
+	limiter := sync2.NewLimiter(upload.opts.Parallelism)
+	for index := int64(0); index < upload.segmentCount-1; index++ {
+		if committed[index] {
+			continue
+		}
+
+		index := index
+		started := limiter.Go(ctx, func() {
+			err := upload.uploadPendingSegment(ctx, index)
+			if err != nil {
+				mu.Lock()
+				if uploadErr == nil {
+					uploadErr = err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		})
+		if !started {
+			break
+		}
+	}
+	limiter.Wait()
+
+	if uploadErr != nil {
+		return uploadErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// the key of the last segment encrypts the stream info, so the last
+	// segment is uploaded only once all other segments are committed
+	lastIndex := upload.segmentCount - 1
+	key, err := upload.uploadSegment(ctx, lastIndex)
+	if err != nil {
+		return err
+	}
+
+	err = upload.commitObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	upload.progress(int32(lastIndex))
+	return nil
+}
+
+// uploadPendingSegment uploads the segment at index and records it as
+// committed.
+func (upload *resumableUpload) uploadPendingSegment(ctx context.Context, index int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if _, err := upload.uploadSegment(ctx, index); err != nil {
+		return err
+	}
+
+	upload.mu.Lock()
+	upload.state.Committed = append(upload.state.Committed, int32(index))
+	sort.Slice(upload.state.Committed, func(i, k int) bool {
+		return upload.state.Committed[i] < upload.state.Committed[k]
+	})
+	err = upload.save()
+	upload.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	upload.progress(int32(index))
+	return nil
+}
+
+// uploadSegment uploads the segment at index with a random content key and
+// commits it.
+func (upload *resumableUpload) uploadSegment(ctx context.Context, index int64) (key segmentKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	encryptionParameters := upload.opts.Volatile.EncryptionParameters
+
+	key, err = randomSegmentKey(encryptionParameters.CipherSuite, upload.derivedKey)
+	if err != nil {
+		return key, err
+	}
+
+	offset, length := upload.segmentRange(index)
+	err = upload.bucket.uploadSegment(ctx, segmentUpload{
+		streamID:                upload.state.StreamID,
+		rs:                      upload.rs,
+		encryption:              encryptionParameters,
+		expires:                 upload.opts.Expires,
+		maxEncryptedSegmentSize: upload.maxEncryptedSegmentSize,
+		index:                   index,
+		key:                     key,
+		data:                    io.NewSectionReader(upload.data, offset, length),
+		size:                    length,
+	})
+	return key, err
+}
+
+// commitObject commits the object with the stream info encrypted with the
+// key of the last segment.
+func (upload *resumableUpload) commitObject(ctx context.Context, lastKey segmentKey) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, lastSegmentSize := upload.segmentRange(upload.segmentCount - 1)
+	return upload.bucket.commitStream(ctx, upload.state.StreamID, &upload.opts.UploadOptions,
+		upload.segmentCount, upload.segmentSize, lastSegmentSize, lastKey)
+}
+
+// segmentRange returns the offset and the length of the segment at index.
+func (upload *resumableUpload) segmentRange(index int64) (offset, length int64) {
+	offset = index * upload.segmentSize
+	length = upload.size - offset
+	if length > upload.segmentSize {
+		length = upload.segmentSize
+	}
+	return offset, length
+}
+
+// progress reports the segment at index as uploaded.
+func (upload *resumableUpload) progress(index int32) {
+	if upload.opts.Progress != nil {
+		_, length := upload.segmentRange(int64(index))
+		upload.opts.Progress(length)
+	}
+}
+
+// save writes the upload state to the state file.
+func (upload *resumableUpload) save() error {
+	return writeStateFile(upload.opts.StatePath, upload.state)
+}
+
+// writeStateFile writes state as JSON to the file at path. The state is
+// written to a temporary file first, so an interruption never leaves a partial
+// state.
+func writeStateFile(path string, state interface{}) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := ioutil.WriteFile(tempPath, data, 0600); err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(os.Rename(tempPath, path))
+}
+
+// uploadExpired returns whether err is returned by the satellite because the
+// stream of the upload is too old to be written to.
+func uploadExpired(err error) bool {
+	if errs2.IsRPC(err, rpcstatus.FailedPrecondition) {
+		return strings.Contains(err.Error(), "max object commit interval")
+	}
+	if errs2.IsRPC(err, rpcstatus.InvalidArgument) {
+		return strings.Contains(err.Error(), "stream ID expired") || strings.Contains(err.Error(), "segment ID expired")
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/lib/uplink"
+	"storj.io/storj/private/testplanet"
+)
+
+// failingReaderAt fails the reads at or after offset failAt.
+type failingReaderAt struct {
+	data   []byte
+	failAt int64
+}
+
+func (r *failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off+int64(len(p)) > r.failAt {
+		return 0, errors.New("interrupted")
+	}
+	return bytes.NewReader(r.data).ReadAt(p, off)
+}
+
+func TestBucketUploadObjectResumable(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+
+		require.NoError(t, client.CreateBucket(ctx, satellite, "bucket"))
+
+		config := client.GetConfig(satellite)
+		config.Client.SegmentSize = 10 * memory.KiB
+
+		project, bucket, err := client.GetProjectAndBucket(ctx, satellite, "bucket", config)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+		defer ctx.Check(bucket.Close)
+
+		expectedData := testrand.Bytes(55 * memory.KiB)
+		statePath := filepath.Join(ctx.Dir("state"), "upload.json")
+		modified := time.Now()
+
+		// the upload is interrupted while reading the fourth segment
+		err = bucket.UploadObjectResumable(ctx, "large", &failingReaderAt{
+			data:   expectedData,
+			failAt: int64(35 * memory.KiB),
+		}, int64(len(expectedData)), &uplink.ResumableUploadOptions{
+			StatePath:      statePath,
+			SourceModified: modified,
+		})
+		require.Error(t, err)
+
+		state, err := uplink.ReadUploadState(statePath)
+		require.NoError(t, err)
+		require.Equal(t, []int32{0, 1, 2}, state.Committed)
+
+		// the resumed upload only uploads the remaining segments
+		var uploaded int64
+		err = bucket.UploadObjectResumable(ctx, "large", bytes.NewReader(expectedData), int64(len(expectedData)), &uplink.ResumableUploadOptions{
+			UploadOptions: uplink.UploadOptions{
+				Metadata: map[string]string{"key": "value"},
+			},
+			StatePath:      statePath,
+			Parallelism:    2,
+			SourceModified: modified,
+			Progress: func(bytes int64) {
+				atomic.AddInt64(&uploaded, bytes)
+			},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, len(expectedData), uploaded)
+
+		_, err = uplink.ReadUploadState(statePath)
+		require.Error(t, err)
+
+		data, err := client.Download(ctx, satellite, "bucket", "large")
+		require.NoError(t, err)
+		require.Equal(t, expectedData, data)
+
+		object, err := bucket.OpenObject(ctx, "large")
+		require.NoError(t, err)
+		require.Equal(t, "value", object.Meta.Metadata["key"])
+		require.EqualValues(t, len(expectedData), object.Meta.Size)
+
+		// empty objects are uploaded as well
+		err = bucket.UploadObjectResumable(ctx, "empty", bytes.NewReader(nil), 0, &uplink.ResumableUploadOptions{
+			StatePath: statePath,
+		})
+		require.NoError(t, err)
+
+		data, err = client.Download(ctx, satellite, "bucket", "empty")
+		require.NoError(t, err)
+		require.Empty(t, data)
+	})
+}
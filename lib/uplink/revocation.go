@@ -0,0 +1,38 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pb"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+// RevokeAPIKey revokes the API key, which must be the API key of the project
+// or be restricted from it, e.g. the API key of an access shared from the
+// access of the project.
+//
+// The other API keys restricted from the API key of the project keep working.
+func (p *Project) RevokeAPIKey(ctx context.Context, key APIKey) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	conn, err := p.dialer.DialAddressInsecureBestEffort(ctx, p.satelliteAddr)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	client := metainfopb.NewDRPCAPIKeyRevocationClient(conn)
+	_, err = client.RevokeAPIKey(ctx, &metainfopb.RevokeAPIKeyRequest{
+		Header: &pb.RequestHeader{
+			ApiKey:    p.apiKey.serializeRaw(),
+			UserAgent: []byte(p.uplinkCfg.Volatile.UserAgent),
+		},
+		ApiKey: key.serializeRaw(),
+	})
+	return Error.Wrap(err)
+}
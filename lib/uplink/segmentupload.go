@@ -0,0 +1,229 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"storj.io/common/encryption"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/uplink/private/eestream"
+	"storj.io/uplink/private/metainfo"
+)
+
+// contentTypeKey is the metadata key of the content type of an object.
+const contentTypeKey = "content-type"
+
+// segmentKey contains the key of a segment's content.
+type segmentKey struct {
+	contentKey   storj.Key
+	encryptedKey storj.EncryptedPrivateKey
+	keyNonce     storj.Nonce
+}
+
+// newSegmentKey returns the segment key with contentKey, encrypted with
+// derivedKey and a random nonce.
+func newSegmentKey(contentKey storj.Key, cipher storj.CipherSuite, derivedKey *storj.Key) (key segmentKey, err error) {
+	key.contentKey = contentKey
+
+	// generate random nonce for encrypting the content key
+	if _, err := rand.Read(key.keyNonce[:]); err != nil {
+		return key, Error.Wrap(err)
+	}
+	key.encryptedKey, err = encryption.EncryptKey(&key.contentKey, cipher, derivedKey, &key.keyNonce)
+	if err != nil {
+		return key, Error.Wrap(err)
+	}
+	return key, nil
+}
+
+// randomSegmentKey returns a segment key with a random content key.
+func randomSegmentKey(cipher storj.CipherSuite, derivedKey *storj.Key) (segmentKey, error) {
+	var contentKey storj.Key
+	if _, err := rand.Read(contentKey[:]); err != nil {
+		return segmentKey{}, Error.Wrap(err)
+	}
+	return newSegmentKey(contentKey, cipher, derivedKey)
+}
+
+// segmentUpload is a segment of a stream, uploaded directly through the
+// metainfo and segments clients rather than the streams store.
+type segmentUpload struct {
+	streamID                storj.StreamID
+	rs                      eestream.RedundancyStrategy
+	encryption              storj.EncryptionParameters
+	expires                 time.Time
+	maxEncryptedSegmentSize int64
+	index                   int64
+	key                     segmentKey
+	data                    io.Reader
+	size                    int64
+}
+
+// uploadSegment encrypts and uploads the size bytes of segment.data as the
+// segment at segment.index of the stream and commits it.
+func (b *Bucket) uploadSegment(ctx context.Context, segment segmentUpload) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cipher := segment.encryption.CipherSuite
+	client := b.project.metainfo
+
+	// the content nonce depends on the index, like the nonces of the
+	// segments uploaded sequentially, to avoid reusing the zero nonce of the
+	// stream info encryption
+	contentNonce := storj.Nonce{}
+	if _, err := encryption.Increment(&contentNonce, segment.index+1); err != nil {
+		return Error.Wrap(err)
+	}
+
+	segmentEncryption := storj.SegmentEncryption{}
+	if cipher != storj.EncNull {
+		segmentEncryption = storj.SegmentEncryption{
+			EncryptedKey:      segment.key.encryptedKey,
+			EncryptedKeyNonce: segment.key.keyNonce,
+		}
+	}
+
+	position := storj.SegmentPosition{Index: int32(segment.index)}
+
+	if segment.size <= int64(b.project.uplinkCfg.Volatile.MaxInlineSize.Int()) {
+		data := make([]byte, segment.size)
+		if _, err := io.ReadFull(segment.data, data); err != nil {
+			return Error.Wrap(err)
+		}
+		cipherData, err := encryption.Encrypt(data, cipher, &segment.key.contentKey, &contentNonce)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		return client.MakeInlineSegment(ctx, metainfo.MakeInlineSegmentParams{
+			StreamID:            segment.streamID,
+			Position:            position,
+			Encryption:          segmentEncryption,
+			EncryptedInlineData: cipherData,
+		})
+	}
+
+	encrypter, err := encryption.NewEncrypter(cipher, &segment.key.contentKey, &contentNonce, int(segment.encryption.BlockSize))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	data := &countingReader{r: io.LimitReader(segment.data, segment.size)}
+	paddedReader := encryption.PadReader(ioutil.NopCloser(data), encrypter.InBlockSize())
+	transformedReader := encryption.TransformReader(paddedReader, encrypter, 0)
+
+	segmentID, limits, piecePrivateKey, err := client.BeginSegment(ctx, metainfo.BeginSegmentParams{
+		StreamID:      segment.streamID,
+		Position:      position,
+		MaxOrderLimit: segment.maxEncryptedSegmentSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	uploadResults, size, err := b.segments.Put(ctx, transformedReader, segment.expires, limits, piecePrivateKey, segment.rs)
+	if err != nil {
+		return err
+	}
+	if data.n != segment.size {
+		return Error.New("segment %d: read %d bytes, expected %d", segment.index, data.n, segment.size)
+	}
+
+	return client.CommitSegment(ctx, metainfo.CommitSegmentParams{
+		SegmentID:         segmentID,
+		SizeEncryptedData: size,
+		Encryption:        segmentEncryption,
+		UploadResult:      uploadResults,
+	})
+}
+
+// commitStream commits the object of the stream with the stream info
+// encrypted with the key of the last segment.
+func (b *Bucket) commitStream(ctx context.Context, streamID storj.StreamID, opts *UploadOptions, segmentCount, segmentSize, lastSegmentSize int64, lastKey segmentKey) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	encryptionParameters := opts.Volatile.EncryptionParameters
+
+	metadata, err := uploadMetadata(opts)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	streamInfo, err := pb.Marshal(&pb.StreamInfo{
+		DeprecatedNumberOfSegments: segmentCount,
+		SegmentsSize:               segmentSize,
+		LastSegmentSize:            lastSegmentSize,
+		Metadata:                   metadata,
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	// encrypt metadata with the content encryption key and zero nonce
+	encryptedStreamInfo, err := encryption.Encrypt(streamInfo, encryptionParameters.CipherSuite, &lastKey.contentKey, &storj.Nonce{})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	streamMeta := pb.StreamMeta{
+		NumberOfSegments:    segmentCount,
+		EncryptedStreamInfo: encryptedStreamInfo,
+		EncryptionType:      int32(encryptionParameters.CipherSuite),
+		EncryptionBlockSize: encryptionParameters.BlockSize,
+	}
+	if encryptionParameters.CipherSuite != storj.EncNull {
+		streamMeta.LastSegmentMeta = &pb.SegmentMeta{
+			EncryptedKey: lastKey.encryptedKey,
+			KeyNonce:     lastKey.keyNonce[:],
+		}
+	}
+
+	objectMetadata, err := pb.Marshal(&streamMeta)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return b.project.metainfo.CommitObject(ctx, metainfo.CommitObjectParams{
+		StreamID:          streamID,
+		EncryptedMetadata: objectMetadata,
+	})
+}
+
+// uploadMetadata returns the serialized metadata of an object uploaded with
+// opts, which includes the content type.
+func uploadMetadata(opts *UploadOptions) ([]byte, error) {
+	userDefined := make(map[string]string, len(opts.Metadata)+1)
+	for key, value := range opts.Metadata {
+		userDefined[key] = value
+	}
+	if contentType := opts.ContentType; contentType != "" {
+		if _, found := userDefined[contentTypeKey]; !found {
+			userDefined[contentTypeKey] = contentType
+		}
+	}
+	if len(userDefined) == 0 {
+		return []byte{}, nil
+	}
+	return pb.Marshal(&pb.SerializableMeta{
+		UserDefined: userDefined,
+	})
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read reads from r and counts the read bytes.
+func (reader *countingReader) Read(p []byte) (n int, err error) {
+	n, err = reader.r.Read(p)
+	reader.n += int64(n)
+	return n, err
+}
@@ -195,10 +195,12 @@ func (u *Uplink) OpenProject(ctx context.Context, satelliteAddr string, apiKey A
 	}
 
 	return &Project{
-		uplinkCfg: u.cfg,
-		dialer:    u.dialer,
-		metainfo:  m,
-		project:   project,
+		uplinkCfg:     u.cfg,
+		dialer:        u.dialer,
+		satelliteAddr: satelliteAddr,
+		apiKey:        apiKey,
+		metainfo:      m,
+		project:       project,
 	}, nil
 }
 
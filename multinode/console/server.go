@@ -0,0 +1,300 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/errs2"
+	"storj.io/common/storj"
+	"storj.io/storj/multinode/nodes"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is multinode console web error type.
+	Error = errs.Class("multinode console web error")
+)
+
+const (
+	contentType = "Content-Type"
+
+	applicationJSON = "application/json"
+)
+
+// Config contains configuration for multinode console web server.
+type Config struct {
+	Address   string `help:"server address of the multinode api" default:"127.0.0.1:15002"`
+	AuthToken string `help:"token which the api requests must send as a bearer token in the Authorization header, the api isn't served without it" default:""`
+}
+
+// Server represents the multinode console web server, which serves the
+// aggregated dashboard data of the registered nodes.
+//
+// architecture: Endpoint
+type Server struct {
+	log       *zap.Logger
+	authToken string
+
+	nodes    *nodes.Service
+	listener net.Listener
+
+	server http.Server
+}
+
+// NewServer creates new instance of multinode console web server. Every api
+// request must send the auth token of the config.
+func NewServer(log *zap.Logger, config Config, nodes *nodes.Service, listener net.Listener) (*Server, error) {
+	if config.AuthToken == "" {
+		return nil, Error.New("the auth token of the console api must be set")
+	}
+
+	server := Server{
+		log:       log,
+		authToken: config.AuthToken,
+		nodes:     nodes,
+		listener:  listener,
+	}
+
+	router := mux.NewRouter()
+	apiRouter := router.PathPrefix("/api").Subrouter()
+	apiRouter.StrictSlash(true)
+	apiRouter.Use(server.authMiddleware)
+	apiRouter.HandleFunc("/totals", server.Totals).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/nodes", server.ListNodes).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/nodes", server.AddNode).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/nodes/{id}", server.GetNode).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/nodes/{id}", server.RemoveNode).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/nodes/{id}/history", server.NodeHistory).Methods(http.MethodGet)
+
+	server.server = http.Server{
+		Handler: router,
+	}
+
+	return &server, nil
+}
+
+// Run starts the server that hosts the api endpoints.
+func (server *Server) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	var group errgroup.Group
+	group.Go(func() error {
+		<-ctx.Done()
+		return server.server.Shutdown(context.Background())
+	})
+	group.Go(func() error {
+		defer cancel()
+		err := server.server.Serve(server.listener)
+		if errs2.IsCanceled(err) || errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		return err
+	})
+
+	return group.Wait()
+}
+
+// Close closes server and underlying listener.
+func (server *Server) Close() error {
+	return server.server.Close()
+}
+
+// Totals returns the aggregated dashboard data of all the nodes.
+func (server *Server) Totals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	totals, err := server.nodes.Totals(ctx)
+	if err != nil {
+		server.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	server.serveJSON(w, totals)
+}
+
+// ListNodes returns the registered nodes with the status of their last poll.
+func (server *Server) ListNodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	list, err := server.nodes.List(ctx)
+	if err != nil {
+		server.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if list == nil {
+		list = []nodes.Node{}
+	}
+
+	server.serveJSON(w, list)
+}
+
+// AddNode registers a node by its console api address and auth token.
+func (server *Server) AddNode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	var request struct {
+		Name      string `json:"name"`
+		Address   string `json:"address"`
+		AuthToken string `json:"authToken"`
+	}
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+	if request.Address == "" {
+		server.serveJSONError(w, http.StatusBadRequest, Error.New("address is required"))
+		return
+	}
+
+	node, err := server.nodes.Add(ctx, request.Name, request.Address, request.AuthToken)
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.serveJSON(w, node)
+}
+
+// GetNode returns the node with its latest snapshot.
+func (server *Server) GetNode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	id, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+
+	details, err := server.nodes.Get(ctx, id)
+	if err != nil {
+		server.serveJSONError(w, statusCode(err), err)
+		return
+	}
+
+	server.serveJSON(w, details)
+}
+
+// RemoveNode unregisters the node.
+func (server *Server) RemoveNode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	id, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+
+	if err = server.nodes.Remove(ctx, id); err != nil {
+		server.serveJSONError(w, statusCode(err), err)
+		return
+	}
+}
+
+// NodeHistory returns the snapshots of the node taken since the time given by
+// the since query parameter in RFC 3339 format, or in the last 30 days.
+func (server *Server) NodeHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	id, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -30)
+	if value := r.URL.Query().Get("since"); value != "" {
+		since, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			server.serveJSONError(w, http.StatusBadRequest, Error.Wrap(err))
+			return
+		}
+	}
+
+	snapshots, err := server.nodes.History(ctx, id, since)
+	if err != nil {
+		server.serveJSONError(w, statusCode(err), err)
+		return
+	}
+	if snapshots == nil {
+		snapshots = []nodes.Snapshot{}
+	}
+
+	server.serveJSON(w, snapshots)
+}
+
+// authMiddleware checks that the request has the auth token.
+func (server *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(server.authToken)) != 1 {
+			server.serveJSONError(w, http.StatusUnauthorized, Error.New("invalid auth token"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusCode returns the http status code for the error.
+func statusCode(err error) int {
+	if nodes.ErrNodeNotFound.Has(err) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// serveJSON writes the value as JSON to response output stream.
+func (server *Server) serveJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set(contentType, applicationJSON)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		server.log.Error("failed to encode json response", zap.Error(Error.Wrap(err)))
+		return
+	}
+}
+
+// serveJSONError writes JSON error to response output stream.
+func (server *Server) serveJSONError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set(contentType, applicationJSON)
+	w.WriteHeader(status)
+
+	var response struct {
+		Error string `json:"error"`
+	}
+
+	response.Error = err.Error()
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		server.log.Error("failed to write json error response", zap.Error(Error.Wrap(err)))
+		return
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewServerRequiresAuthToken(t *testing.T) {
+	_, err := NewServer(zaptest.NewLogger(t), Config{}, nil, nil)
+	require.Error(t, err)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	server, err := NewServer(zaptest.NewLogger(t), Config{AuthToken: "secret"}, nil, nil)
+	require.NoError(t, err)
+
+	handler := server.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range []struct {
+		authorization string
+		status        int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Bearer ", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/nodes", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, tt.status, rec.Code, tt.authorization)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinodedb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3" // used indirectly.
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/multinode"
+	"storj.io/storj/multinode/nodes"
+	"storj.io/storj/private/dbutil"
+	"storj.io/storj/private/migrate"
+	"storj.io/storj/private/tagsql"
+)
+
+// VersionTable is the table that stores the version info of the database.
+const VersionTable = "versions"
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default multinodedb errs class.
+	Error = errs.Class("multinodedb error")
+)
+
+var _ multinode.DB = (*DB)(nil)
+
+// DB is the SQLite database of the multinode dashboard.
+//
+// architecture: Master Database
+type DB struct {
+	log *zap.Logger
+	db  tagsql.DB
+
+	nodesDB *nodesDB
+}
+
+// New opens the SQLite database at the path, creating its directory when needed.
+func New(log *zap.Logger, path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	sqlDB, err := tagsql.Open("sqlite3", "file:"+path+"?_journal=WAL&_busy_timeout=10000")
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	dbutil.Configure(sqlDB, "multinode", mon)
+
+	return &DB{
+		log:     log,
+		db:      sqlDB,
+		nodesDB: &nodesDB{db: sqlDB},
+	}, nil
+}
+
+// Nodes returns the database of the registered nodes.
+func (db *DB) Nodes() nodes.DB { return db.nodesDB }
+
+// MigrateToLatest creates any necessary tables.
+func (db *DB) MigrateToLatest(ctx context.Context) error {
+	return db.Migration().Run(ctx, db.log.Named("migration"))
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return Error.Wrap(db.db.Close())
+}
+
+// Migration returns the migration of the database.
+func (db *DB) Migration() *migrate.Migration {
+	return &migrate.Migration{
+		Table: VersionTable,
+		Steps: []*migrate.Step{
+			{
+				DB:          db.db,
+				Description: "Initial setup",
+				Version:     0,
+				Action: migrate.SQL{
+					`CREATE TABLE nodes (
+						id           BLOB NOT NULL,
+						name         TEXT NOT NULL,
+						address      TEXT NOT NULL,
+						auth_token   TEXT NOT NULL,
+						created_at   TIMESTAMP NOT NULL,
+						last_contact TIMESTAMP,
+						last_error   TEXT NOT NULL DEFAULT '',
+						PRIMARY KEY (id)
+					)`,
+					`CREATE TABLE snapshots (
+						node_id    BLOB NOT NULL,
+						created_at TIMESTAMP NOT NULL,
+						data       BLOB NOT NULL,
+						PRIMARY KEY (node_id, created_at)
+					)`,
+					`CREATE INDEX idx_snapshots_created_at ON snapshots(created_at)`,
+				},
+			},
+		},
+	}
+}
+
+// withTx runs the callback in a transaction, which is committed unless the callback fails.
+func withTx(ctx context.Context, db tagsql.DB, cb func(tx tagsql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, tx.Rollback())
+			return
+		}
+
+		err = tx.Commit()
+	}()
+	return cb(tx)
+}
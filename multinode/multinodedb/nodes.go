@@ -0,0 +1,245 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinodedb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/storj/multinode/nodes"
+	"storj.io/storj/private/tagsql"
+)
+
+// ensures that nodesDB implements nodes.DB interface.
+var _ nodes.DB = (*nodesDB)(nil)
+
+// ErrNodes represents errors from the nodes database.
+var ErrNodes = errs.Class("nodes db error")
+
+// nodesDB stores the registered nodes and their snapshots.
+//
+// architecture: Database
+type nodesDB struct {
+	db tagsql.DB
+}
+
+const nodeColumns = `id, name, address, auth_token, created_at, last_contact, last_error`
+
+// Add registers the node.
+func (db *nodesDB) Add(ctx context.Context, node nodes.Node) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var lastContact *time.Time
+	if !node.LastContact.IsZero() {
+		utc := node.LastContact.UTC()
+		lastContact = &utc
+	}
+
+	err = withTx(ctx, db.db, func(tx tagsql.Tx) error {
+		var count int
+		err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM nodes WHERE id = ?`, node.ID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errs.New("node %s is already registered", node.ID)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO nodes(`+nodeColumns+`)
+				VALUES (?,?,?,?,?,?,?)
+		`, node.ID, node.Name, node.Address, node.AuthToken, node.CreatedAt.UTC(), lastContact, node.LastError)
+		return err
+	})
+	return ErrNodes.Wrap(err)
+}
+
+// Remove unregisters the node and removes its snapshots.
+func (db *nodesDB) Remove(ctx context.Context, id storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = withTx(ctx, db.db, func(tx tagsql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM nodes WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return nodes.ErrNodeNotFound.New("%s", id)
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM snapshots WHERE node_id = ?`, id)
+		return err
+	})
+	if nodes.ErrNodeNotFound.Has(err) {
+		return err
+	}
+	return ErrNodes.Wrap(err)
+}
+
+// Get returns the node.
+func (db *nodesDB) Get(ctx context.Context, id storj.NodeID) (_ nodes.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	row := db.db.QueryRowContext(ctx, `
+		SELECT `+nodeColumns+`
+			FROM nodes
+			WHERE id = ?
+	`, id)
+
+	node, err := scanNode(row)
+	if errs.Is(err, sql.ErrNoRows) {
+		return nodes.Node{}, nodes.ErrNodeNotFound.New("%s", id)
+	}
+	return node, ErrNodes.Wrap(err)
+}
+
+// List returns all the registered nodes ordered by name.
+func (db *nodesDB) List(ctx context.Context) (_ []nodes.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, `
+		SELECT `+nodeColumns+`
+			FROM nodes
+			ORDER BY name, id
+	`)
+	if err != nil {
+		return nil, ErrNodes.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var list []nodes.Node
+	for rows.Next() {
+		node, err := scanNode(rows)
+		if err != nil {
+			return nil, ErrNodes.Wrap(err)
+		}
+		list = append(list, node)
+	}
+	return list, ErrNodes.Wrap(rows.Err())
+}
+
+// UpdateStatus records the result of the last poll of the node.
+func (db *nodesDB) UpdateStatus(ctx context.Context, id storj.NodeID, lastContact time.Time, lastError string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var contact *time.Time
+	if !lastContact.IsZero() {
+		utc := lastContact.UTC()
+		contact = &utc
+	}
+
+	_, err = db.db.ExecContext(ctx, `
+		UPDATE nodes
+			SET last_contact = ?, last_error = ?
+			WHERE id = ?
+	`, contact, lastError, id)
+	return ErrNodes.Wrap(err)
+}
+
+// AddSnapshot stores a snapshot of the node dashboard.
+func (db *nodesDB) AddSnapshot(ctx context.Context, snapshot nodes.Snapshot) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return ErrNodes.Wrap(err)
+	}
+
+	_, err = db.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO snapshots(node_id, created_at, data)
+			VALUES (?,?,?)
+	`, snapshot.NodeID, snapshot.CreatedAt.UTC(), data)
+	return ErrNodes.Wrap(err)
+}
+
+// LatestSnapshot returns the most recent snapshot of the node dashboard.
+func (db *nodesDB) LatestSnapshot(ctx context.Context, id storj.NodeID) (_ nodes.Snapshot, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var data []byte
+	err = db.db.QueryRowContext(ctx, `
+		SELECT data
+			FROM snapshots
+			WHERE node_id = ?
+			ORDER BY created_at DESC
+			LIMIT 1
+	`, id).Scan(&data)
+	if errs.Is(err, sql.ErrNoRows) {
+		return nodes.Snapshot{}, nodes.ErrNoSnapshot.New("%s", id)
+	}
+	if err != nil {
+		return nodes.Snapshot{}, ErrNodes.Wrap(err)
+	}
+
+	var snapshot nodes.Snapshot
+	return snapshot, ErrNodes.Wrap(json.Unmarshal(data, &snapshot))
+}
+
+// ListSnapshots returns the snapshots of the node dashboard taken since the time, oldest first.
+func (db *nodesDB) ListSnapshots(ctx context.Context, id storj.NodeID, since time.Time) (_ []nodes.Snapshot, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, `
+		SELECT data
+			FROM snapshots
+			WHERE node_id = ? AND created_at >= ?
+			ORDER BY created_at
+	`, id, since.UTC())
+	if err != nil {
+		return nil, ErrNodes.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var snapshots []nodes.Snapshot
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, ErrNodes.Wrap(err)
+		}
+
+		var snapshot nodes.Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, ErrNodes.Wrap(err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, ErrNodes.Wrap(rows.Err())
+}
+
+// DeleteSnapshotsBefore removes the snapshots taken before the time.
+func (db *nodesDB) DeleteSnapshotsBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := db.db.ExecContext(ctx, `DELETE FROM snapshots WHERE created_at < ?`, before.UTC())
+	if err != nil {
+		return 0, ErrNodes.Wrap(err)
+	}
+	deleted, err := result.RowsAffected()
+	return deleted, ErrNodes.Wrap(err)
+}
+
+type nodeScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanNode(row nodeScanner) (node nodes.Node, err error) {
+	var lastContact sql.NullTime
+	err = row.Scan(&node.ID, &node.Name, &node.Address, &node.AuthToken, &node.CreatedAt, &lastContact, &node.LastError)
+	if err != nil {
+		return nodes.Node{}, err
+	}
+	if lastContact.Valid {
+		node.LastContact = lastContact.Time
+	}
+	return node, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinodedb_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/multinode/multinodedb"
+	"storj.io/storj/multinode/nodes"
+	"storj.io/storj/storagenode/console"
+)
+
+func TestNodesDB(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db, err := multinodedb.New(zaptest.NewLogger(t), filepath.Join(ctx.Dir("multinode"), "multinode.db"))
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+	require.NoError(t, db.MigrateToLatest(ctx))
+
+	nodesDB := db.Nodes()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	node := nodes.Node{
+		ID:        testrand.NodeID(),
+		Name:      "node-b",
+		Address:   "10.0.0.2:14002",
+		AuthToken: "secret",
+		CreatedAt: now,
+	}
+	require.NoError(t, nodesDB.Add(ctx, node))
+	require.Error(t, nodesDB.Add(ctx, node))
+
+	other := nodes.Node{ID: testrand.NodeID(), Name: "node-a", Address: "10.0.0.1:14002", CreatedAt: now, LastContact: now}
+	require.NoError(t, nodesDB.Add(ctx, other))
+
+	got, err := nodesDB.Get(ctx, node.ID)
+	require.NoError(t, err)
+	require.Equal(t, node.ID, got.ID)
+	require.Equal(t, "secret", got.AuthToken)
+	require.True(t, got.LastContact.IsZero())
+	require.False(t, got.Online())
+
+	_, err = nodesDB.Get(ctx, testrand.NodeID())
+	require.True(t, nodes.ErrNodeNotFound.Has(err))
+
+	list, err := nodesDB.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "node-a", list[0].Name)
+	require.Equal(t, "node-b", list[1].Name)
+
+	// status
+	require.NoError(t, nodesDB.UpdateStatus(ctx, node.ID, now, "connection refused"))
+	got, err = nodesDB.Get(ctx, node.ID)
+	require.NoError(t, err)
+	require.True(t, now.Equal(got.LastContact))
+	require.Equal(t, "connection refused", got.LastError)
+	require.False(t, got.Online())
+
+	require.NoError(t, nodesDB.UpdateStatus(ctx, node.ID, now, ""))
+	got, err = nodesDB.Get(ctx, node.ID)
+	require.NoError(t, err)
+	require.True(t, got.Online())
+
+	// snapshots
+	_, err = nodesDB.LatestSnapshot(ctx, node.ID)
+	require.True(t, nodes.ErrNoSnapshot.Has(err))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, nodesDB.AddSnapshot(ctx, nodes.Snapshot{
+			NodeID:    node.ID,
+			CreatedAt: now.Add(time.Duration(i-2) * time.Hour),
+			Dashboard: console.Dashboard{
+				NodeID:    node.ID,
+				DiskSpace: console.DiskSpaceInfo{Used: int64(i)},
+			},
+		}))
+	}
+
+	latest, err := nodesDB.LatestSnapshot(ctx, node.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, latest.Dashboard.DiskSpace.Used)
+	require.Equal(t, node.ID, latest.Dashboard.NodeID)
+
+	snapshots, err := nodesDB.ListSnapshots(ctx, node.ID, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	require.EqualValues(t, 1, snapshots[0].Dashboard.DiskSpace.Used)
+	require.EqualValues(t, 2, snapshots[1].Dashboard.DiskSpace.Used)
+
+	deleted, err := nodesDB.DeleteSnapshotsBefore(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, deleted)
+
+	// remove
+	require.NoError(t, nodesDB.Remove(ctx, node.ID))
+	require.True(t, nodes.ErrNodeNotFound.Has(nodesDB.Remove(ctx, node.ID)))
+
+	snapshots, err = nodesDB.ListSnapshots(ctx, node.ID, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+
+	list, err = nodesDB.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodes
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/sync2"
+)
+
+// Chore periodically polls the registered nodes and removes the old snapshots.
+//
+// architecture: Chore
+type Chore struct {
+	log     *zap.Logger
+	service *Service
+
+	Loop *sync2.Cycle
+}
+
+// NewChore creates a new nodes polling chore.
+func NewChore(log *zap.Logger, service *Service, config Config) *Chore {
+	return &Chore{
+		log:     log,
+		service: service,
+		Loop:    sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run starts the chore.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := chore.service.Poll(ctx); err != nil {
+			chore.log.Error("failed to poll nodes", zap.Error(err))
+		}
+		if err := chore.service.DeleteHistory(ctx); err != nil {
+			chore.log.Error("failed to delete old snapshots", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the chore.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storagenode/console"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/notifications"
+)
+
+// ErrClient is the error class for the requests to the storage node console api.
+var ErrClient = errs.Class("storage node console api")
+
+// Client reads the dashboard data from the console api of a storage node.
+type Client struct {
+	baseURL   string
+	authToken string
+	http      *http.Client
+}
+
+// NewClient creates a client for the storage node console api at the address,
+// which is either host:port or an http(s) URL.
+func NewClient(address, authToken string, timeout time.Duration) *Client {
+	baseURL := strings.TrimSuffix(address, "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+	return &Client{
+		baseURL:   baseURL,
+		authToken: authToken,
+		http:      &http.Client{Timeout: timeout},
+	}
+}
+
+// Dashboard returns the dashboard data of the node.
+func (client *Client) Dashboard(ctx context.Context) (_ console.Dashboard, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var dashboard console.Dashboard
+	err = client.get(ctx, "/api/sno/", &dashboard)
+	return dashboard, err
+}
+
+// Satellites returns the usage of the node consolidated across all the satellites.
+func (client *Client) Satellites(ctx context.Context) (_ console.Satellites, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var satellites console.Satellites
+	err = client.get(ctx, "/api/sno/satellites", &satellites)
+	return satellites, err
+}
+
+// PayStubs returns the paystubs of all the satellites for the months between start and end, formatted as YYYY-MM.
+func (client *Client) PayStubs(ctx context.Context, start, end string) (_ []heldamount.PayStub, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var payStubs []heldamount.PayStub
+	err = client.get(ctx, "/api/heldamount/paystubs/"+start+"/"+end, &payStubs)
+	return payStubs, err
+}
+
+// Notifications returns the latest notifications and the number of unread notifications.
+func (client *Client) Notifications(ctx context.Context, limit int) (_ []notifications.Notification, unread int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var result struct {
+		Page struct {
+			Notifications []notifications.Notification `json:"notifications"`
+		} `json:"page"`
+		UnreadCount int `json:"unreadCount"`
+	}
+	err = client.get(ctx, fmt.Sprintf("/api/notifications/list?limit=%d&page=1", limit), &result)
+	return result.Page.Notifications, result.UnreadCount, err
+}
+
+// get decodes the JSON response of the api endpoint into v.
+func (client *Client) get(ctx context.Context, path string, v interface{}) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.baseURL+path, nil)
+	if err != nil {
+		return ErrClient.Wrap(err)
+	}
+	if client.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+client.authToken)
+	}
+
+	resp, err := client.http.Do(req)
+	if err != nil {
+		return ErrClient.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, ErrClient.Wrap(resp.Body.Close())) }()
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&response) == nil && response.Error != "" {
+			return ErrClient.New("%s: %s: %s", path, resp.Status, response.Error)
+		}
+		return ErrClient.New("%s: %s", path, resp.Status)
+	}
+
+	return ErrClient.Wrap(json.NewDecoder(resp.Body).Decode(v))
+}
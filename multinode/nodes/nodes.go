@@ -0,0 +1,135 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodes
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/storj/storagenode/console"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/notifications"
+)
+
+var (
+	// Error is the default error class for the multinode nodes.
+	Error = errs.Class("multinode nodes error")
+
+	// ErrNodeNotFound is returned when the node isn't registered.
+	ErrNodeNotFound = errs.Class("node not found")
+
+	// ErrNoSnapshot is returned when the node hasn't been polled yet.
+	ErrNoSnapshot = errs.Class("no snapshot")
+)
+
+// DB stores the registered storage nodes and the snapshots of their dashboards.
+//
+// architecture: Database
+type DB interface {
+	// Add registers the node.
+	Add(ctx context.Context, node Node) error
+	// Remove unregisters the node and removes its snapshots.
+	Remove(ctx context.Context, id storj.NodeID) error
+	// Get returns the node.
+	Get(ctx context.Context, id storj.NodeID) (Node, error)
+	// List returns all the registered nodes ordered by name.
+	List(ctx context.Context) ([]Node, error)
+	// UpdateStatus records the result of the last poll of the node.
+	UpdateStatus(ctx context.Context, id storj.NodeID, lastContact time.Time, lastError string) error
+
+	// AddSnapshot stores a snapshot of the node dashboard.
+	AddSnapshot(ctx context.Context, snapshot Snapshot) error
+	// LatestSnapshot returns the most recent snapshot of the node dashboard.
+	LatestSnapshot(ctx context.Context, id storj.NodeID) (Snapshot, error)
+	// ListSnapshots returns the snapshots of the node dashboard taken since the time, oldest first.
+	ListSnapshots(ctx context.Context, id storj.NodeID, since time.Time) ([]Snapshot, error)
+	// DeleteSnapshotsBefore removes the snapshots taken before the time.
+	DeleteSnapshotsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Node is a storage node registered by its console api address.
+type Node struct {
+	ID        storj.NodeID `json:"id"`
+	Name      string       `json:"name"`
+	Address   string       `json:"address"`
+	AuthToken string       `json:"-"`
+	CreatedAt time.Time    `json:"createdAt"`
+
+	// LastContact is the time of the last successful poll.
+	LastContact time.Time `json:"lastContact"`
+	// LastError is the error of the last poll, empty when it succeeded.
+	LastError string `json:"lastError"`
+}
+
+// Online returns whether the last poll of the node succeeded.
+func (node Node) Online() bool {
+	return !node.LastContact.IsZero() && node.LastError == ""
+}
+
+// Snapshot is the dashboard data of a node at a point in time.
+type Snapshot struct {
+	NodeID    storj.NodeID `json:"nodeId"`
+	CreatedAt time.Time    `json:"createdAt"`
+
+	Dashboard           console.Dashboard            `json:"dashboard"`
+	Satellites          console.Satellites           `json:"satellites"`
+	PayStubs            []heldamount.PayStub         `json:"payStubs"`
+	Notifications       []notifications.Notification `json:"notifications"`
+	UnreadNotifications int                          `json:"unreadNotifications"`
+}
+
+// Totals are the aggregated latest snapshots of all the nodes.
+type Totals struct {
+	Nodes  int `json:"nodes"`
+	Online int `json:"online"`
+
+	DiskUsed      int64 `json:"diskUsed"`
+	DiskAvailable int64 `json:"diskAvailable"`
+	DiskTrash     int64 `json:"diskTrash"`
+
+	BandwidthUsed  int64   `json:"bandwidthUsed"`
+	EgressSummary  int64   `json:"egressSummary"`
+	IngressSummary int64   `json:"ingressSummary"`
+	StorageSummary float64 `json:"storageSummary"`
+
+	Held int64 `json:"held"`
+	Paid int64 `json:"paid"`
+
+	UnreadNotifications int `json:"unreadNotifications"`
+
+	// DisqualifiedSatellites and SuspendedSatellites count the node and satellite pairs.
+	DisqualifiedSatellites int `json:"disqualifiedSatellites"`
+	SuspendedSatellites    int `json:"suspendedSatellites"`
+}
+
+// Add adds the snapshot to the totals.
+func (totals *Totals) Add(snapshot Snapshot) {
+	totals.DiskUsed += snapshot.Dashboard.DiskSpace.Used
+	totals.DiskAvailable += snapshot.Dashboard.DiskSpace.Available
+	totals.DiskTrash += snapshot.Dashboard.DiskSpace.Trash
+
+	totals.BandwidthUsed += snapshot.Dashboard.Bandwidth.Used
+	totals.EgressSummary += snapshot.Satellites.EgressSummary
+	totals.IngressSummary += snapshot.Satellites.IngressSummary
+	totals.StorageSummary += snapshot.Satellites.StorageSummary
+
+	for _, payStub := range snapshot.PayStubs {
+		totals.Held += payStub.Held
+		totals.Paid += payStub.Paid
+	}
+
+	totals.UnreadNotifications += snapshot.UnreadNotifications
+
+	for _, satellite := range snapshot.Dashboard.Satellites {
+		if satellite.Disqualified != nil {
+			totals.DisqualifiedSatellites++
+		}
+		if satellite.Suspended != nil {
+			totals.SuspendedSatellites++
+		}
+	}
+}
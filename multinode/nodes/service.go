@@ -0,0 +1,260 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodes
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+)
+
+var mon = monkit.Package()
+
+// Config contains configurable values for polling the nodes.
+type Config struct {
+	Interval         time.Duration `help:"how often the nodes are polled" default:"5m0s"`
+	Timeout          time.Duration `help:"timeout of a request to the console api of a node" default:"30s"`
+	Concurrency      int           `help:"how many nodes are polled at the same time" default:"10"`
+	PayStubMonths    int           `help:"how many months of paystubs are fetched" default:"12"`
+	Notifications    int           `help:"how many of the latest notifications are fetched" default:"20"`
+	HistoryRetention time.Duration `help:"how long the snapshots of the nodes are kept" default:"2160h0m0s"`
+}
+
+// NodeDetails is a node with its latest snapshot.
+type NodeDetails struct {
+	Node
+	Snapshot *Snapshot `json:"snapshot"`
+}
+
+// Service registers storage nodes and polls their console api.
+//
+// architecture: Service
+type Service struct {
+	log    *zap.Logger
+	db     DB
+	config Config
+
+	nowFn func() time.Time
+}
+
+// NewService creates a new nodes service.
+func NewService(log *zap.Logger, db DB, config Config) *Service {
+	return &Service{
+		log:    log,
+		db:     db,
+		config: config,
+		nowFn:  time.Now,
+	}
+}
+
+// Add registers the node with the console api address and auth token. The
+// node is polled right away, to check the address and to learn its ID.
+func (service *Service) Add(ctx context.Context, name, address, authToken string) (_ Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	node := Node{
+		Name:      name,
+		Address:   address,
+		AuthToken: authToken,
+		CreatedAt: service.nowFn().UTC(),
+	}
+
+	snapshot, err := service.snapshot(ctx, node)
+	if err != nil {
+		return Node{}, Error.Wrap(err)
+	}
+	node.ID = snapshot.NodeID
+	if node.Name == "" {
+		node.Name = node.ID.String()
+	}
+	node.LastContact = snapshot.CreatedAt
+
+	if err := service.db.Add(ctx, node); err != nil {
+		return Node{}, Error.Wrap(err)
+	}
+	if err := service.db.AddSnapshot(ctx, snapshot); err != nil {
+		return Node{}, Error.Wrap(err)
+	}
+	return node, nil
+}
+
+// Remove unregisters the node.
+func (service *Service) Remove(ctx context.Context, id storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return Error.Wrap(service.db.Remove(ctx, id))
+}
+
+// List returns all the registered nodes.
+func (service *Service) List(ctx context.Context) (_ []Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodes, err := service.db.List(ctx)
+	return nodes, Error.Wrap(err)
+}
+
+// Get returns the node with its latest snapshot, which is nil when the node
+// hasn't been polled successfully.
+func (service *Service) Get(ctx context.Context, id storj.NodeID) (_ NodeDetails, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	node, err := service.db.Get(ctx, id)
+	if err != nil {
+		return NodeDetails{}, Error.Wrap(err)
+	}
+
+	details := NodeDetails{Node: node}
+	snapshot, err := service.db.LatestSnapshot(ctx, id)
+	switch {
+	case ErrNoSnapshot.Has(err):
+	case err != nil:
+		return NodeDetails{}, Error.Wrap(err)
+	default:
+		details.Snapshot = &snapshot
+	}
+	return details, nil
+}
+
+// History returns the snapshots of the node taken since the time.
+func (service *Service) History(ctx context.Context, id storj.NodeID, since time.Time) (_ []Snapshot, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if _, err := service.db.Get(ctx, id); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	snapshots, err := service.db.ListSnapshots(ctx, id, since)
+	return snapshots, Error.Wrap(err)
+}
+
+// Totals returns the aggregated latest snapshots of all the nodes.
+func (service *Service) Totals(ctx context.Context) (_ Totals, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodes, err := service.db.List(ctx)
+	if err != nil {
+		return Totals{}, Error.Wrap(err)
+	}
+
+	var totals Totals
+	for _, node := range nodes {
+		totals.Nodes++
+		if node.Online() {
+			totals.Online++
+		}
+
+		snapshot, err := service.db.LatestSnapshot(ctx, node.ID)
+		if ErrNoSnapshot.Has(err) {
+			continue
+		}
+		if err != nil {
+			return Totals{}, Error.Wrap(err)
+		}
+		totals.Add(snapshot)
+	}
+	return totals, nil
+}
+
+// Poll takes a snapshot of every registered node. Errors of the nodes are
+// recorded in their status instead of being returned.
+func (service *Service) Poll(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodes, err := service.db.List(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	limiter := sync2.NewLimiter(service.config.Concurrency)
+	for _, node := range nodes {
+		node := node
+		limiter.Go(ctx, func() {
+			service.poll(ctx, node)
+		})
+	}
+	limiter.Wait()
+
+	return ctx.Err()
+}
+
+// poll takes a snapshot of the node and records the result.
+func (service *Service) poll(ctx context.Context, node Node) {
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	snapshot, err := service.snapshot(ctx, node)
+	if err == nil && snapshot.NodeID != node.ID {
+		err = Error.New("node at %q is %s instead of %s", node.Address, snapshot.NodeID, node.ID)
+	}
+	if err == nil {
+		err = service.db.AddSnapshot(ctx, snapshot)
+	}
+
+	lastContact, lastError := node.LastContact, ""
+	if err != nil {
+		service.log.Warn("failed to poll node", zap.Stringer("Node ID", node.ID), zap.String("Name", node.Name), zap.Error(err))
+		lastError = err.Error()
+	} else {
+		lastContact = snapshot.CreatedAt
+	}
+
+	if err := service.db.UpdateStatus(ctx, node.ID, lastContact, lastError); err != nil {
+		service.log.Error("failed to update node status", zap.Stringer("Node ID", node.ID), zap.Error(err))
+	}
+}
+
+// snapshot fetches the dashboard data of the node.
+func (service *Service) snapshot(ctx context.Context, node Node) (_ Snapshot, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	client := NewClient(node.Address, node.AuthToken, service.config.Timeout)
+	now := service.nowFn().UTC()
+
+	snapshot := Snapshot{CreatedAt: now}
+
+	snapshot.Dashboard, err = client.Dashboard(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot.NodeID = snapshot.Dashboard.NodeID
+
+	snapshot.Satellites, err = client.Satellites(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	// paystubs are only available for the months which have ended
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	start := end.AddDate(0, 1-service.config.PayStubMonths, 0)
+	snapshot.PayStubs, err = client.PayStubs(ctx, start.Format("2006-01"), end.Format("2006-01"))
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot.Notifications, snapshot.UnreadNotifications, err = client.Notifications(ctx, service.config.Notifications)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// DeleteHistory removes the snapshots older than the history retention.
+func (service *Service) DeleteHistory(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	deleted, err := service.db.DeleteSnapshotsBefore(ctx, service.nowFn().Add(-service.config.HistoryRetention))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if deleted > 0 {
+		service.log.Debug("deleted old snapshots", zap.Int64("Count", deleted))
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/multinode/multinodedb"
+	"storj.io/storj/multinode/nodes"
+	"storj.io/storj/storagenode/console"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/notifications"
+)
+
+// fakeNode serves the storage node console api endpoints which are polled.
+type fakeNode struct {
+	id        storj.NodeID
+	authToken string
+	diskUsed  int64
+	payPeriod string
+}
+
+func (node *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+node.authToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid auth token"}`))
+		return
+	}
+
+	var response interface{}
+	switch {
+	case r.URL.Path == "/api/sno/":
+		satellites := []console.SatelliteInfo{{ID: testrand.NodeID()}, {ID: testrand.NodeID(), Suspended: &time.Time{}}}
+		response = console.Dashboard{
+			NodeID:     node.id,
+			Satellites: satellites,
+			DiskSpace:  console.DiskSpaceInfo{Used: node.diskUsed, Available: 1000, Trash: 10},
+			Bandwidth:  console.BandwidthInfo{Used: 50},
+		}
+	case r.URL.Path == "/api/sno/satellites":
+		response = console.Satellites{EgressSummary: 30, IngressSummary: 20, StorageSummary: 1.5}
+	case strings.HasPrefix(r.URL.Path, "/api/heldamount/paystubs/"):
+		node.payPeriod = strings.TrimPrefix(r.URL.Path, "/api/heldamount/paystubs/")
+		response = []heldamount.PayStub{{Held: 100, Paid: 400}, {Held: 50, Paid: 200}}
+	case r.URL.Path == "/api/notifications/list":
+		var list struct {
+			Page struct {
+				Notifications []notifications.Notification `json:"notifications"`
+			} `json:"page"`
+			UnreadCount int `json:"unreadCount"`
+		}
+		list.Page.Notifications = []notifications.Notification{{Title: "hello"}}
+		list.UnreadCount = 3
+		response = list
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func TestService(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db, err := multinodedb.New(zaptest.NewLogger(t), filepath.Join(ctx.Dir("multinode"), "multinode.db"))
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+	require.NoError(t, db.MigrateToLatest(ctx))
+
+	first := &fakeNode{id: testrand.NodeID(), authToken: "first", diskUsed: 100}
+	second := &fakeNode{id: testrand.NodeID(), authToken: "second", diskUsed: 200}
+	firstServer := httptest.NewServer(first)
+	defer firstServer.Close()
+	secondServer := httptest.NewServer(second)
+	defer secondServer.Close()
+
+	service := nodes.NewService(zaptest.NewLogger(t), db.Nodes(), nodes.Config{
+		Timeout:       10 * time.Second,
+		Concurrency:   2,
+		PayStubMonths: 3,
+		Notifications: 5,
+	})
+
+	// the token is checked when the node is added
+	_, err = service.Add(ctx, "first", firstServer.URL, "wrong")
+	require.Error(t, err)
+
+	node, err := service.Add(ctx, "first", firstServer.URL, "first")
+	require.NoError(t, err)
+	require.Equal(t, first.id, node.ID)
+	require.True(t, node.Online())
+	require.Regexp(t, `^\d{4}-\d{2}/\d{4}-\d{2}$`, first.payPeriod)
+
+	// host:port addresses are accepted too
+	node, err = service.Add(ctx, "", strings.TrimPrefix(secondServer.URL, "http://"), "second")
+	require.NoError(t, err)
+	require.Equal(t, second.id.String(), node.Name)
+
+	list, err := service.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	totals, err := service.Totals(ctx)
+	require.NoError(t, err)
+	require.Equal(t, nodes.Totals{
+		Nodes:               2,
+		Online:              2,
+		DiskUsed:            300,
+		DiskAvailable:       2000,
+		DiskTrash:           20,
+		BandwidthUsed:       100,
+		EgressSummary:       60,
+		IngressSummary:      40,
+		StorageSummary:      3,
+		Held:                300,
+		Paid:                1200,
+		UnreadNotifications: 6,
+		SuspendedSatellites: 2,
+	}, totals)
+
+	// a failing node is recorded, and its previous snapshot is kept
+	second.authToken = "changed"
+	first.diskUsed = 150
+	require.NoError(t, service.Poll(ctx))
+
+	details, err := service.Get(ctx, second.id)
+	require.NoError(t, err)
+	require.False(t, details.Online())
+	require.Contains(t, details.LastError, "invalid auth token")
+	require.NotNil(t, details.Snapshot)
+	require.EqualValues(t, 200, details.Snapshot.Dashboard.DiskSpace.Used)
+
+	details, err = service.Get(ctx, first.id)
+	require.NoError(t, err)
+	require.True(t, details.Online())
+	require.EqualValues(t, 150, details.Snapshot.Dashboard.DiskSpace.Used)
+	require.Len(t, details.Snapshot.Notifications, 1)
+
+	history, err := service.History(ctx, first.id, time.Time{})
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	require.EqualValues(t, 150, history[len(history)-1].Dashboard.DiskSpace.Used)
+
+	totals, err = service.Totals(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, totals.Online)
+	require.EqualValues(t, 350, totals.DiskUsed)
+
+	require.NoError(t, service.Remove(ctx, second.id))
+	_, err = service.Get(ctx, second.id)
+	require.True(t, nodes.ErrNodeNotFound.Has(err))
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinode
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/private/debug"
+	"storj.io/storj/multinode/console"
+	"storj.io/storj/multinode/nodes"
+	"storj.io/storj/private/lifecycle"
+)
+
+var (
+	mon = monkit.Package()
+)
+
+// DB is the master database for the multinode dashboard.
+//
+// architecture: Master Database
+type DB interface {
+	// MigrateToLatest initializes the database.
+	MigrateToLatest(ctx context.Context) error
+	// Close closes the database.
+	Close() error
+
+	// Nodes returns the database of the registered nodes.
+	Nodes() nodes.DB
+}
+
+// Config is all the configuration parameters for the multinode dashboard.
+type Config struct {
+	Database string `help:"path to the multinode database" default:"$CONFDIR/multinode.db"`
+
+	Debug   debug.Config
+	Nodes   nodes.Config
+	Console console.Config
+}
+
+// Peer is the multinode dashboard, which polls the console api of many
+// storage nodes and serves their aggregated data.
+//
+// architecture: Peer
+type Peer struct {
+	Log *zap.Logger
+	DB  DB
+
+	Servers  *lifecycle.Group
+	Services *lifecycle.Group
+
+	Debug struct {
+		Listener net.Listener
+		Server   *debug.Server
+	}
+
+	Nodes struct {
+		Service *nodes.Service
+		Chore   *nodes.Chore
+	}
+
+	Console struct {
+		Listener net.Listener
+		Endpoint *console.Server
+	}
+}
+
+// New creates a new multinode dashboard.
+func New(log *zap.Logger, db DB, config Config) (*Peer, error) {
+	peer := &Peer{
+		Log: log,
+		DB:  db,
+
+		Servers:  lifecycle.NewGroup(log.Named("servers")),
+		Services: lifecycle.NewGroup(log.Named("services")),
+	}
+
+	{ // setup debug
+		var err error
+		if config.Debug.Address != "" {
+			peer.Debug.Listener, err = net.Listen("tcp", config.Debug.Address)
+			if err != nil {
+				withoutStack := errors.New(err.Error())
+				peer.Log.Debug("failed to start debug endpoints", zap.Error(withoutStack))
+			}
+		}
+		debugConfig := config.Debug
+		debugConfig.ControlTitle = "Multinode"
+		peer.Debug.Server = debug.NewServer(log.Named("debug"), peer.Debug.Listener, monkit.Default, debugConfig)
+		peer.Servers.Add(lifecycle.Item{
+			Name:  "debug",
+			Run:   peer.Debug.Server.Run,
+			Close: peer.Debug.Server.Close,
+		})
+	}
+
+	{ // setup nodes
+		peer.Nodes.Service = nodes.NewService(
+			peer.Log.Named("nodes:service"),
+			peer.DB.Nodes(),
+			config.Nodes,
+		)
+
+		peer.Nodes.Chore = nodes.NewChore(
+			peer.Log.Named("nodes:chore"),
+			peer.Nodes.Service,
+			config.Nodes,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "nodes:chore",
+			Run:   peer.Nodes.Chore.Run,
+			Close: peer.Nodes.Chore.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Nodes Poll", peer.Nodes.Chore.Loop))
+	}
+
+	{ // setup console
+		var err error
+		peer.Console.Listener, err = net.Listen("tcp", config.Console.Address)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
+		peer.Console.Endpoint, err = console.NewServer(
+			peer.Log.Named("console:endpoint"),
+			config.Console,
+			peer.Nodes.Service,
+			peer.Console.Listener,
+		)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		peer.Servers.Add(lifecycle.Item{
+			Name:  "console:endpoint",
+			Run:   peer.Console.Endpoint.Run,
+			Close: peer.Console.Endpoint.Close,
+		})
+	}
+
+	return peer, nil
+}
+
+// Run runs the multinode dashboard until it's either closed or it errors.
+func (peer *Peer) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	group, ctx := errgroup.WithContext(ctx)
+
+	peer.Servers.Run(ctx, group)
+	peer.Services.Run(ctx, group)
+
+	return group.Wait()
+}
+
+// Close closes all the resources.
+func (peer *Peer) Close() error {
+	return errs.Combine(
+		peer.Servers.Close(),
+		peer.Services.Close(),
+	)
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package gracefulexitpb contains the messages and the DRPC descriptions of
+// the graceful exit RPCs which aren't part of storj.io/common/pb yet.
+package gracefulexitpb
+
+import (
+	"context"
+
+	proto "github.com/gogo/protobuf/proto"
+
+	"storj.io/drpc"
+)
+
+// CancelGracefulExitRequest cancels the graceful exit of the calling node.
+// The node is identified by its peer identity.
+type CancelGracefulExitRequest struct{}
+
+func (m *CancelGracefulExitRequest) Reset()         { *m = CancelGracefulExitRequest{} }
+func (m *CancelGracefulExitRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelGracefulExitRequest) ProtoMessage()    {}
+
+// CancelGracefulExitResponse is the response of CancelGracefulExit.
+type CancelGracefulExitResponse struct{}
+
+func (m *CancelGracefulExitResponse) Reset()         { *m = CancelGracefulExitResponse{} }
+func (m *CancelGracefulExitResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelGracefulExitResponse) ProtoMessage()    {}
+
+// DRPCGracefulExitCancelClient is the client of the graceful exit cancel RPC.
+type DRPCGracefulExitCancelClient interface {
+	DRPCConn() drpc.Conn
+
+	CancelGracefulExit(ctx context.Context, in *CancelGracefulExitRequest) (*CancelGracefulExitResponse, error)
+}
+
+type drpcGracefulExitCancelClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCGracefulExitCancelClient creates a new client of the graceful exit cancel RPC.
+func NewDRPCGracefulExitCancelClient(cc drpc.Conn) DRPCGracefulExitCancelClient {
+	return &drpcGracefulExitCancelClient{cc}
+}
+
+func (c *drpcGracefulExitCancelClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcGracefulExitCancelClient) CancelGracefulExit(ctx context.Context, in *CancelGracefulExitRequest) (*CancelGracefulExitResponse, error) {
+	out := new(CancelGracefulExitResponse)
+	err := c.cc.Invoke(ctx, "/gracefulexit.SatelliteGracefulExit/CancelGracefulExit", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DRPCGracefulExitCancelServer is the server of the graceful exit cancel RPC.
+type DRPCGracefulExitCancelServer interface {
+	CancelGracefulExit(context.Context, *CancelGracefulExitRequest) (*CancelGracefulExitResponse, error)
+}
+
+// DRPCGracefulExitCancelDescription describes the graceful exit cancel RPC,
+// which is served next to pb.DRPCSatelliteGracefulExitDescription.
+type DRPCGracefulExitCancelDescription struct{}
+
+// NumMethods returns the number of methods available.
+func (DRPCGracefulExitCancelDescription) NumMethods() int { return 1 }
+
+// Method returns the information about the nth method.
+func (DRPCGracefulExitCancelDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/gracefulexit.SatelliteGracefulExit/CancelGracefulExit",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGracefulExitCancelServer).
+					CancelGracefulExit(
+						ctx,
+						in1.(*CancelGracefulExitRequest),
+					)
+			}, DRPCGracefulExitCancelServer.CancelGracefulExit, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterGracefulExitCancel registers the graceful exit cancel RPC.
+func DRPCRegisterGracefulExitCancel(mux drpc.Mux, impl DRPCGracefulExitCancelServer) error {
+	return mux.Register(impl, DRPCGracefulExitCancelDescription{})
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexitpb
+
+import (
+	"context"
+
+	proto "github.com/gogo/protobuf/proto"
+
+	"storj.io/common/pb"
+	"storj.io/drpc"
+)
+
+// InitiateShrinkRequest asks the satellite to transfer TargetBytes of the
+// pieces of the calling node to other nodes, while the node stays active.
+type InitiateShrinkRequest struct {
+	TargetBytes int64 `protobuf:"varint,1,opt,name=target_bytes,json=targetBytes,proto3" json:"target_bytes,omitempty"`
+}
+
+func (m *InitiateShrinkRequest) Reset()         { *m = InitiateShrinkRequest{} }
+func (m *InitiateShrinkRequest) String() string { return proto.CompactTextString(m) }
+func (*InitiateShrinkRequest) ProtoMessage()    {}
+
+// InitiateShrinkResponse is the response of InitiateShrink.
+type InitiateShrinkResponse struct{}
+
+func (m *InitiateShrinkResponse) Reset()         { *m = InitiateShrinkResponse{} }
+func (m *InitiateShrinkResponse) String() string { return proto.CompactTextString(m) }
+func (*InitiateShrinkResponse) ProtoMessage()    {}
+
+// GetShrinkStatusRequest requests the status of the last shrink of the
+// calling node.
+type GetShrinkStatusRequest struct{}
+
+func (m *GetShrinkStatusRequest) Reset()         { *m = GetShrinkStatusRequest{} }
+func (m *GetShrinkStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetShrinkStatusRequest) ProtoMessage()    {}
+
+// GetShrinkStatusResponse is the status of the last shrink of a node.
+// QueuedBytes is only known once the pieces have been queued.
+type GetShrinkStatusResponse struct {
+	TargetBytes      int64 `protobuf:"varint,1,opt,name=target_bytes,json=targetBytes,proto3" json:"target_bytes,omitempty"`
+	QueuedBytes      int64 `protobuf:"varint,2,opt,name=queued_bytes,json=queuedBytes,proto3" json:"queued_bytes,omitempty"`
+	BytesTransferred int64 `protobuf:"varint,3,opt,name=bytes_transferred,json=bytesTransferred,proto3" json:"bytes_transferred,omitempty"`
+	Queued           bool  `protobuf:"varint,4,opt,name=queued,proto3" json:"queued,omitempty"`
+	Finished         bool  `protobuf:"varint,5,opt,name=finished,proto3" json:"finished,omitempty"`
+}
+
+func (m *GetShrinkStatusResponse) Reset()         { *m = GetShrinkStatusResponse{} }
+func (m *GetShrinkStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetShrinkStatusResponse) ProtoMessage()    {}
+
+// DRPCGracefulExitShrinkClient is the client of the graceful exit shrink RPCs.
+type DRPCGracefulExitShrinkClient interface {
+	DRPCConn() drpc.Conn
+
+	InitiateShrink(ctx context.Context, in *InitiateShrinkRequest) (*InitiateShrinkResponse, error)
+	GetShrinkStatus(ctx context.Context, in *GetShrinkStatusRequest) (*GetShrinkStatusResponse, error)
+	ProcessShrink(ctx context.Context) (pb.DRPCSatelliteGracefulExit_ProcessClient, error)
+}
+
+type drpcGracefulExitShrinkClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCGracefulExitShrinkClient creates a new client of the graceful exit shrink RPCs.
+func NewDRPCGracefulExitShrinkClient(cc drpc.Conn) DRPCGracefulExitShrinkClient {
+	return &drpcGracefulExitShrinkClient{cc}
+}
+
+func (c *drpcGracefulExitShrinkClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcGracefulExitShrinkClient) InitiateShrink(ctx context.Context, in *InitiateShrinkRequest) (*InitiateShrinkResponse, error) {
+	out := new(InitiateShrinkResponse)
+	err := c.cc.Invoke(ctx, "/gracefulexit.SatelliteGracefulExit/InitiateShrink", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGracefulExitShrinkClient) GetShrinkStatus(ctx context.Context, in *GetShrinkStatusRequest) (*GetShrinkStatusResponse, error) {
+	out := new(GetShrinkStatusResponse)
+	err := c.cc.Invoke(ctx, "/gracefulexit.SatelliteGracefulExit/GetShrinkStatus", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProcessShrink streams the pieces queued for the shrink of the calling node
+// like Process, but it never initiates a graceful exit.
+func (c *drpcGracefulExitShrinkClient) ProcessShrink(ctx context.Context) (pb.DRPCSatelliteGracefulExit_ProcessClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/gracefulexit.SatelliteGracefulExit/ProcessShrink")
+	if err != nil {
+		return nil, err
+	}
+	return &drpcProcessShrinkClient{stream}, nil
+}
+
+type drpcProcessShrinkClient struct {
+	drpc.Stream
+}
+
+func (x *drpcProcessShrinkClient) Send(m *pb.StorageNodeMessage) error {
+	return x.MsgSend(m)
+}
+
+func (x *drpcProcessShrinkClient) Recv() (*pb.SatelliteMessage, error) {
+	m := new(pb.SatelliteMessage)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DRPCGracefulExitShrinkServer is the server of the graceful exit shrink RPCs.
+type DRPCGracefulExitShrinkServer interface {
+	InitiateShrink(context.Context, *InitiateShrinkRequest) (*InitiateShrinkResponse, error)
+	GetShrinkStatus(context.Context, *GetShrinkStatusRequest) (*GetShrinkStatusResponse, error)
+	ProcessShrink(pb.DRPCSatelliteGracefulExit_ProcessStream) error
+}
+
+// DRPCGracefulExitShrinkDescription describes the graceful exit shrink RPCs,
+// which are served next to pb.DRPCSatelliteGracefulExitDescription.
+type DRPCGracefulExitShrinkDescription struct{}
+
+// NumMethods returns the number of methods available.
+func (DRPCGracefulExitShrinkDescription) NumMethods() int { return 3 }
+
+// Method returns the information about the nth method.
+func (DRPCGracefulExitShrinkDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/gracefulexit.SatelliteGracefulExit/InitiateShrink",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGracefulExitShrinkServer).
+					InitiateShrink(
+						ctx,
+						in1.(*InitiateShrinkRequest),
+					)
+			}, DRPCGracefulExitShrinkServer.InitiateShrink, true
+	case 1:
+		return "/gracefulexit.SatelliteGracefulExit/GetShrinkStatus",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGracefulExitShrinkServer).
+					GetShrinkStatus(
+						ctx,
+						in1.(*GetShrinkStatusRequest),
+					)
+			}, DRPCGracefulExitShrinkServer.GetShrinkStatus, true
+	case 2:
+		return "/gracefulexit.SatelliteGracefulExit/ProcessShrink",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCGracefulExitShrinkServer).
+					ProcessShrink(
+						&drpcProcessShrinkStream{in1.(drpc.Stream)},
+					)
+			}, DRPCGracefulExitShrinkServer.ProcessShrink, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterGracefulExitShrink registers the graceful exit shrink RPCs.
+func DRPCRegisterGracefulExitShrink(mux drpc.Mux, impl DRPCGracefulExitShrinkServer) error {
+	return mux.Register(impl, DRPCGracefulExitShrinkDescription{})
+}
+
+type drpcProcessShrinkStream struct {
+	drpc.Stream
+}
+
+func (x *drpcProcessShrinkStream) Send(m *pb.SatelliteMessage) error {
+	return x.MsgSend(m)
+}
+
+func (x *drpcProcessShrinkStream) Recv() (*pb.StorageNodeMessage, error) {
+	m := new(pb.StorageNodeMessage)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,29 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexitpb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/storj/pkg/gracefulexitpb"
+)
+
+func TestGetShrinkStatusResponseEncoding(t *testing.T) {
+	resp := &gracefulexitpb.GetShrinkStatusResponse{
+		TargetBytes:      1 << 30,
+		QueuedBytes:      1<<30 + 512,
+		BytesTransferred: 1 << 20,
+		Queued:           true,
+	}
+
+	data, err := pb.Marshal(resp)
+	require.NoError(t, err)
+
+	decoded := &gracefulexitpb.GetShrinkStatusResponse{}
+	require.NoError(t, pb.Unmarshal(data, decoded))
+	require.True(t, pb.Equal(resp, decoded))
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package inspectorpb contains the messages and the DRPC descriptions of the
+// satellite inspector RPCs which aren't part of storj.io/common/pb yet.
+package inspectorpb
+
+import (
+	"context"
+	"time"
+
+	proto "github.com/gogo/protobuf/proto"
+
+	"storj.io/drpc"
+)
+
+// SegmentRepairHistoryRequest requests the failed repair attempts of a
+// segment which is in the repair queue.
+type SegmentRepairHistoryRequest struct {
+	ProjectId     []byte `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	SegmentIndex  int64  `protobuf:"varint,2,opt,name=segment_index,json=segmentIndex,proto3" json:"segment_index,omitempty"`
+	Bucket        []byte `protobuf:"bytes,3,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath []byte `protobuf:"bytes,4,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+}
+
+func (m *SegmentRepairHistoryRequest) Reset()         { *m = SegmentRepairHistoryRequest{} }
+func (m *SegmentRepairHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*SegmentRepairHistoryRequest) ProtoMessage()    {}
+
+// RepairAttempt is a failed repair attempt of a segment.
+type RepairAttempt struct {
+	AttemptedAt   time.Time `protobuf:"bytes,1,opt,name=attempted_at,json=attemptedAt,proto3,stdtime" json:"attempted_at"`
+	FailureReason string    `protobuf:"bytes,2,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+}
+
+func (m *RepairAttempt) Reset()         { *m = RepairAttempt{} }
+func (m *RepairAttempt) String() string { return proto.CompactTextString(m) }
+func (*RepairAttempt) ProtoMessage()    {}
+
+// SegmentRepairHistoryResponse contains the failed repair attempts of the
+// segment, the most recent one first.
+type SegmentRepairHistoryResponse struct {
+	Attempts []*RepairAttempt `protobuf:"bytes,1,rep,name=attempts,proto3" json:"attempts,omitempty"`
+}
+
+func (m *SegmentRepairHistoryResponse) Reset()         { *m = SegmentRepairHistoryResponse{} }
+func (m *SegmentRepairHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*SegmentRepairHistoryResponse) ProtoMessage()    {}
+
+// DRPCRepairHistoryInspectorClient is the client of the repair history RPC.
+type DRPCRepairHistoryInspectorClient interface {
+	DRPCConn() drpc.Conn
+
+	SegmentRepairHistory(ctx context.Context, in *SegmentRepairHistoryRequest) (*SegmentRepairHistoryResponse, error)
+}
+
+type drpcRepairHistoryInspectorClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCRepairHistoryInspectorClient creates a new client of the repair
+// history RPC.
+func NewDRPCRepairHistoryInspectorClient(cc drpc.Conn) DRPCRepairHistoryInspectorClient {
+	return &drpcRepairHistoryInspectorClient{cc}
+}
+
+func (c *drpcRepairHistoryInspectorClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcRepairHistoryInspectorClient) SegmentRepairHistory(ctx context.Context, in *SegmentRepairHistoryRequest) (*SegmentRepairHistoryResponse, error) {
+	out := new(SegmentRepairHistoryResponse)
+	err := c.cc.Invoke(ctx, "/inspector.HealthInspector/SegmentRepairHistory", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DRPCRepairHistoryInspectorServer is the server of the repair history RPC.
+type DRPCRepairHistoryInspectorServer interface {
+	SegmentRepairHistory(context.Context, *SegmentRepairHistoryRequest) (*SegmentRepairHistoryResponse, error)
+}
+
+// DRPCRepairHistoryInspectorDescription describes the repair history RPC,
+// which is served next to pb.DRPCHealthInspectorDescription.
+type DRPCRepairHistoryInspectorDescription struct{}
+
+// NumMethods returns the number of methods available.
+func (DRPCRepairHistoryInspectorDescription) NumMethods() int { return 1 }
+
+// Method returns the information about the nth method.
+func (DRPCRepairHistoryInspectorDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/inspector.HealthInspector/SegmentRepairHistory",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCRepairHistoryInspectorServer).
+					SegmentRepairHistory(
+						ctx,
+						in1.(*SegmentRepairHistoryRequest),
+					)
+			}, DRPCRepairHistoryInspectorServer.SegmentRepairHistory, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterRepairHistoryInspector registers the repair history RPC.
+func DRPCRegisterRepairHistoryInspector(mux drpc.Mux, impl DRPCRepairHistoryInspectorServer) error {
+	return mux.Register(impl, DRPCRepairHistoryInspectorDescription{})
+}
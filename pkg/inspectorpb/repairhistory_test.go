@@ -0,0 +1,40 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package inspectorpb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/storj/pkg/inspectorpb"
+)
+
+func TestSegmentRepairHistoryResponseEncoding(t *testing.T) {
+	resp := &inspectorpb.SegmentRepairHistoryResponse{
+		Attempts: []*inspectorpb.RepairAttempt{
+			{
+				AttemptedAt:   time.Date(2020, 4, 2, 10, 30, 0, 0, time.UTC),
+				FailureReason: "segment cannot be repaired: only 28 healthy pieces, 29 required",
+			},
+			{
+				AttemptedAt:   time.Date(2020, 4, 2, 9, 0, 0, 0, time.UTC),
+				FailureReason: "context deadline exceeded",
+			},
+		},
+	}
+
+	data, err := pb.Marshal(resp)
+	require.NoError(t, err)
+
+	decoded := &inspectorpb.SegmentRepairHistoryResponse{}
+	require.NoError(t, pb.Unmarshal(data, decoded))
+	require.Len(t, decoded.Attempts, 2)
+	for i, attempt := range resp.Attempts {
+		require.True(t, attempt.AttemptedAt.Equal(decoded.Attempts[i].AttemptedAt))
+		require.Equal(t, attempt.FailureReason, decoded.Attempts[i].FailureReason)
+	}
+}
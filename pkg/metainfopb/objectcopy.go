@@ -0,0 +1,203 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package metainfopb contains the messages and the DRPC descriptions of the
+// metainfo RPCs which aren't part of storj.io/common/pb yet.
+//
+// The messages are plain protobuf structs, so they are encoded with the same
+// codec as the rest of the metainfo RPCs.
+package metainfopb
+
+import (
+	"context"
+
+	proto "github.com/gogo/protobuf/proto"
+
+	"storj.io/common/pb"
+	"storj.io/drpc"
+)
+
+// SegmentKey is the encrypted content key of a segment.
+//
+// The key is encrypted with a key derived from the encrypted path of the
+// object, hence it has to be re-encrypted by the client when the object is
+// copied or moved to another path.
+type SegmentKey struct {
+	Position          *pb.SegmentPosition `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	EncryptedKeyNonce []byte              `protobuf:"bytes,2,opt,name=encrypted_key_nonce,json=encryptedKeyNonce,proto3" json:"encrypted_key_nonce,omitempty"`
+	EncryptedKey      []byte              `protobuf:"bytes,3,opt,name=encrypted_key,json=encryptedKey,proto3" json:"encrypted_key,omitempty"`
+}
+
+func (m *SegmentKey) Reset()         { *m = SegmentKey{} }
+func (m *SegmentKey) String() string { return proto.CompactTextString(m) }
+func (*SegmentKey) ProtoMessage()    {}
+
+// ObjectBeginCopyRequest requests the segment keys of an object which is
+// going to be copied or moved.
+type ObjectBeginCopyRequest struct {
+	Header        *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3" json:"header,omitempty"`
+	Bucket        []byte            `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath []byte            `protobuf:"bytes,2,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+}
+
+func (m *ObjectBeginCopyRequest) Reset()         { *m = ObjectBeginCopyRequest{} }
+func (m *ObjectBeginCopyRequest) String() string { return proto.CompactTextString(m) }
+func (*ObjectBeginCopyRequest) ProtoMessage()    {}
+
+// ObjectBeginCopyResponse contains the segment keys of the object.
+type ObjectBeginCopyResponse struct {
+	EncryptionParameters *pb.EncryptionParameters `protobuf:"bytes,1,opt,name=encryption_parameters,json=encryptionParameters,proto3" json:"encryption_parameters,omitempty"`
+	SegmentKeys          []*SegmentKey            `protobuf:"bytes,2,rep,name=segment_keys,json=segmentKeys,proto3" json:"segment_keys,omitempty"`
+}
+
+func (m *ObjectBeginCopyResponse) Reset()         { *m = ObjectBeginCopyResponse{} }
+func (m *ObjectBeginCopyResponse) String() string { return proto.CompactTextString(m) }
+func (*ObjectBeginCopyResponse) ProtoMessage()    {}
+
+// ObjectFinishCopyRequest copies the object to a new path, with its segment
+// keys re-encrypted for the new path.
+type ObjectFinishCopyRequest struct {
+	Header           *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3" json:"header,omitempty"`
+	Bucket           []byte            `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath    []byte            `protobuf:"bytes,2,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+	NewBucket        []byte            `protobuf:"bytes,3,opt,name=new_bucket,json=newBucket,proto3" json:"new_bucket,omitempty"`
+	NewEncryptedPath []byte            `protobuf:"bytes,4,opt,name=new_encrypted_path,json=newEncryptedPath,proto3" json:"new_encrypted_path,omitempty"`
+	NewSegmentKeys   []*SegmentKey     `protobuf:"bytes,5,rep,name=new_segment_keys,json=newSegmentKeys,proto3" json:"new_segment_keys,omitempty"`
+}
+
+func (m *ObjectFinishCopyRequest) Reset()         { *m = ObjectFinishCopyRequest{} }
+func (m *ObjectFinishCopyRequest) String() string { return proto.CompactTextString(m) }
+func (*ObjectFinishCopyRequest) ProtoMessage()    {}
+
+// ObjectFinishCopyResponse is the response of FinishCopyObject.
+type ObjectFinishCopyResponse struct{}
+
+func (m *ObjectFinishCopyResponse) Reset()         { *m = ObjectFinishCopyResponse{} }
+func (m *ObjectFinishCopyResponse) String() string { return proto.CompactTextString(m) }
+func (*ObjectFinishCopyResponse) ProtoMessage()    {}
+
+// ObjectFinishMoveRequest moves the object to a new path, with its segment
+// keys re-encrypted for the new path.
+type ObjectFinishMoveRequest struct {
+	Header           *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3" json:"header,omitempty"`
+	Bucket           []byte            `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath    []byte            `protobuf:"bytes,2,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+	NewBucket        []byte            `protobuf:"bytes,3,opt,name=new_bucket,json=newBucket,proto3" json:"new_bucket,omitempty"`
+	NewEncryptedPath []byte            `protobuf:"bytes,4,opt,name=new_encrypted_path,json=newEncryptedPath,proto3" json:"new_encrypted_path,omitempty"`
+	NewSegmentKeys   []*SegmentKey     `protobuf:"bytes,5,rep,name=new_segment_keys,json=newSegmentKeys,proto3" json:"new_segment_keys,omitempty"`
+}
+
+func (m *ObjectFinishMoveRequest) Reset()         { *m = ObjectFinishMoveRequest{} }
+func (m *ObjectFinishMoveRequest) String() string { return proto.CompactTextString(m) }
+func (*ObjectFinishMoveRequest) ProtoMessage()    {}
+
+// ObjectFinishMoveResponse is the response of FinishMoveObject.
+type ObjectFinishMoveResponse struct{}
+
+func (m *ObjectFinishMoveResponse) Reset()         { *m = ObjectFinishMoveResponse{} }
+func (m *ObjectFinishMoveResponse) String() string { return proto.CompactTextString(m) }
+func (*ObjectFinishMoveResponse) ProtoMessage()    {}
+
+// DRPCObjectCopyClient is the client of the object copy RPCs.
+type DRPCObjectCopyClient interface {
+	DRPCConn() drpc.Conn
+
+	BeginCopyObject(ctx context.Context, in *ObjectBeginCopyRequest) (*ObjectBeginCopyResponse, error)
+	FinishCopyObject(ctx context.Context, in *ObjectFinishCopyRequest) (*ObjectFinishCopyResponse, error)
+	FinishMoveObject(ctx context.Context, in *ObjectFinishMoveRequest) (*ObjectFinishMoveResponse, error)
+}
+
+type drpcObjectCopyClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCObjectCopyClient creates a new client of the object copy RPCs.
+func NewDRPCObjectCopyClient(cc drpc.Conn) DRPCObjectCopyClient {
+	return &drpcObjectCopyClient{cc}
+}
+
+func (c *drpcObjectCopyClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcObjectCopyClient) BeginCopyObject(ctx context.Context, in *ObjectBeginCopyRequest) (*ObjectBeginCopyResponse, error) {
+	out := new(ObjectBeginCopyResponse)
+	err := c.cc.Invoke(ctx, "/metainfo.Metainfo/BeginCopyObject", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectCopyClient) FinishCopyObject(ctx context.Context, in *ObjectFinishCopyRequest) (*ObjectFinishCopyResponse, error) {
+	out := new(ObjectFinishCopyResponse)
+	err := c.cc.Invoke(ctx, "/metainfo.Metainfo/FinishCopyObject", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectCopyClient) FinishMoveObject(ctx context.Context, in *ObjectFinishMoveRequest) (*ObjectFinishMoveResponse, error) {
+	out := new(ObjectFinishMoveResponse)
+	err := c.cc.Invoke(ctx, "/metainfo.Metainfo/FinishMoveObject", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DRPCObjectCopyServer is the server of the object copy RPCs.
+//
+// Moving an object starts with BeginCopyObject too, because it needs the same
+// segment keys.
+type DRPCObjectCopyServer interface {
+	BeginCopyObject(context.Context, *ObjectBeginCopyRequest) (*ObjectBeginCopyResponse, error)
+	FinishCopyObject(context.Context, *ObjectFinishCopyRequest) (*ObjectFinishCopyResponse, error)
+	FinishMoveObject(context.Context, *ObjectFinishMoveRequest) (*ObjectFinishMoveResponse, error)
+}
+
+// DRPCObjectCopyDescription describes the object copy RPCs, which are served
+// next to pb.DRPCMetainfoDescription.
+type DRPCObjectCopyDescription struct{}
+
+// NumMethods returns the number of methods available.
+func (DRPCObjectCopyDescription) NumMethods() int { return 3 }
+
+// Method returns the information about the nth method.
+func (DRPCObjectCopyDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/metainfo.Metainfo/BeginCopyObject",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectCopyServer).
+					BeginCopyObject(
+						ctx,
+						in1.(*ObjectBeginCopyRequest),
+					)
+			}, DRPCObjectCopyServer.BeginCopyObject, true
+	case 1:
+		return "/metainfo.Metainfo/FinishCopyObject",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectCopyServer).
+					FinishCopyObject(
+						ctx,
+						in1.(*ObjectFinishCopyRequest),
+					)
+			}, DRPCObjectCopyServer.FinishCopyObject, true
+	case 2:
+		return "/metainfo.Metainfo/FinishMoveObject",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectCopyServer).
+					FinishMoveObject(
+						ctx,
+						in1.(*ObjectFinishMoveRequest),
+					)
+			}, DRPCObjectCopyServer.FinishMoveObject, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterObjectCopy registers the object copy RPCs.
+func DRPCRegisterObjectCopy(mux drpc.Mux, impl DRPCObjectCopyServer) error {
+	return mux.Register(impl, DRPCObjectCopyDescription{})
+}
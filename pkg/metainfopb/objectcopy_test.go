@@ -0,0 +1,43 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfopb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/common/testrand"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+func TestObjectFinishCopyRequestEncoding(t *testing.T) {
+	req := &metainfopb.ObjectFinishCopyRequest{
+		Header:           &pb.RequestHeader{ApiKey: testrand.BytesInt(32)},
+		Bucket:           []byte("bucket"),
+		EncryptedPath:    testrand.BytesInt(16),
+		NewBucket:        []byte("new-bucket"),
+		NewEncryptedPath: testrand.BytesInt(16),
+		NewSegmentKeys: []*metainfopb.SegmentKey{
+			{
+				Position:          &pb.SegmentPosition{Index: 0},
+				EncryptedKeyNonce: testrand.Nonce().Bytes(),
+				EncryptedKey:      testrand.BytesInt(48),
+			},
+			{
+				Position:          &pb.SegmentPosition{Index: -1},
+				EncryptedKeyNonce: testrand.Nonce().Bytes(),
+				EncryptedKey:      testrand.BytesInt(48),
+			},
+		},
+	}
+
+	data, err := pb.Marshal(req)
+	require.NoError(t, err)
+
+	decoded := &metainfopb.ObjectFinishCopyRequest{}
+	require.NoError(t, pb.Unmarshal(data, decoded))
+	require.True(t, pb.Equal(req, decoded))
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfopb
+
+import (
+	"context"
+
+	proto "github.com/gogo/protobuf/proto"
+
+	"storj.io/common/pb"
+	"storj.io/drpc"
+)
+
+// ObjectRestoreVersionRequest requests an archived object version to become
+// the current object again.
+type ObjectRestoreVersionRequest struct {
+	Header        *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3" json:"header,omitempty"`
+	Bucket        []byte            `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath []byte            `protobuf:"bytes,2,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+	Version       int32             `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *ObjectRestoreVersionRequest) Reset()         { *m = ObjectRestoreVersionRequest{} }
+func (m *ObjectRestoreVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*ObjectRestoreVersionRequest) ProtoMessage()    {}
+
+// ObjectRestoreVersionResponse is the response of RestoreObjectVersion.
+type ObjectRestoreVersionResponse struct{}
+
+func (m *ObjectRestoreVersionResponse) Reset()         { *m = ObjectRestoreVersionResponse{} }
+func (m *ObjectRestoreVersionResponse) String() string { return proto.CompactTextString(m) }
+func (*ObjectRestoreVersionResponse) ProtoMessage()    {}
+
+// DRPCObjectVersionsClient is the client of the object versions RPCs.
+type DRPCObjectVersionsClient interface {
+	DRPCConn() drpc.Conn
+
+	ListObjectVersions(ctx context.Context, in *pb.ObjectListRequest) (*pb.ObjectListResponse, error)
+	RestoreObjectVersion(ctx context.Context, in *ObjectRestoreVersionRequest) (*ObjectRestoreVersionResponse, error)
+}
+
+type drpcObjectVersionsClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCObjectVersionsClient creates a new client of the object versions
+// RPCs.
+func NewDRPCObjectVersionsClient(cc drpc.Conn) DRPCObjectVersionsClient {
+	return &drpcObjectVersionsClient{cc}
+}
+
+func (c *drpcObjectVersionsClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcObjectVersionsClient) ListObjectVersions(ctx context.Context, in *pb.ObjectListRequest) (*pb.ObjectListResponse, error) {
+	out := new(pb.ObjectListResponse)
+	err := c.cc.Invoke(ctx, "/metainfo.Metainfo/ListObjectVersions", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectVersionsClient) RestoreObjectVersion(ctx context.Context, in *ObjectRestoreVersionRequest) (*ObjectRestoreVersionResponse, error) {
+	out := new(ObjectRestoreVersionResponse)
+	err := c.cc.Invoke(ctx, "/metainfo.Metainfo/RestoreObjectVersion", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DRPCObjectVersionsServer is the server of the object versions RPCs.
+//
+// ListObjectVersions takes the same request as ListObjects. The cursor of the
+// request can also point to an archived version of an object.
+type DRPCObjectVersionsServer interface {
+	ListObjectVersions(context.Context, *pb.ObjectListRequest) (*pb.ObjectListResponse, error)
+	RestoreObjectVersion(context.Context, *ObjectRestoreVersionRequest) (*ObjectRestoreVersionResponse, error)
+}
+
+// DRPCObjectVersionsDescription describes the object versions RPCs, which are
+// served next to pb.DRPCMetainfoDescription.
+type DRPCObjectVersionsDescription struct{}
+
+// NumMethods returns the number of methods available.
+func (DRPCObjectVersionsDescription) NumMethods() int { return 2 }
+
+// Method returns the information about the nth method.
+func (DRPCObjectVersionsDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/metainfo.Metainfo/ListObjectVersions",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectVersionsServer).
+					ListObjectVersions(
+						ctx,
+						in1.(*pb.ObjectListRequest),
+					)
+			}, DRPCObjectVersionsServer.ListObjectVersions, true
+	case 1:
+		return "/metainfo.Metainfo/RestoreObjectVersion",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectVersionsServer).
+					RestoreObjectVersion(
+						ctx,
+						in1.(*ObjectRestoreVersionRequest),
+					)
+			}, DRPCObjectVersionsServer.RestoreObjectVersion, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterObjectVersions registers the object versions RPCs.
+func DRPCRegisterObjectVersions(mux drpc.Mux, impl DRPCObjectVersionsServer) error {
+	return mux.Register(impl, DRPCObjectVersionsDescription{})
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfopb
+
+import (
+	"context"
+
+	proto "github.com/gogo/protobuf/proto"
+
+	"storj.io/common/pb"
+	"storj.io/drpc"
+)
+
+// RevokeAPIKeyRequest requests the revocation of an API key which is
+// restricted from the API key of the request header.
+type RevokeAPIKeyRequest struct {
+	Header *pb.RequestHeader `protobuf:"bytes,15,opt,name=header,proto3" json:"header,omitempty"`
+	ApiKey []byte            `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+}
+
+func (m *RevokeAPIKeyRequest) Reset()         { *m = RevokeAPIKeyRequest{} }
+func (m *RevokeAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyRequest) ProtoMessage()    {}
+
+// RevokeAPIKeyResponse is the response of RevokeAPIKey.
+type RevokeAPIKeyResponse struct{}
+
+func (m *RevokeAPIKeyResponse) Reset()         { *m = RevokeAPIKeyResponse{} }
+func (m *RevokeAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyResponse) ProtoMessage()    {}
+
+// DRPCAPIKeyRevocationClient is the client of the API key revocation RPC.
+type DRPCAPIKeyRevocationClient interface {
+	DRPCConn() drpc.Conn
+
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+}
+
+type drpcAPIKeyRevocationClient struct {
+	cc drpc.Conn
+}
+
+// NewDRPCAPIKeyRevocationClient creates a new client of the API key
+// revocation RPC.
+func NewDRPCAPIKeyRevocationClient(cc drpc.Conn) DRPCAPIKeyRevocationClient {
+	return &drpcAPIKeyRevocationClient{cc}
+}
+
+func (c *drpcAPIKeyRevocationClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcAPIKeyRevocationClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error) {
+	out := new(RevokeAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/metainfo.Metainfo/RevokeAPIKey", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DRPCAPIKeyRevocationServer is the server of the API key revocation RPC.
+type DRPCAPIKeyRevocationServer interface {
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+}
+
+// DRPCAPIKeyRevocationDescription describes the API key revocation RPC,
+// which is served next to pb.DRPCMetainfoDescription.
+type DRPCAPIKeyRevocationDescription struct{}
+
+// NumMethods returns the number of methods available.
+func (DRPCAPIKeyRevocationDescription) NumMethods() int { return 1 }
+
+// Method returns the information about the nth method.
+func (DRPCAPIKeyRevocationDescription) Method(n int) (string, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/metainfo.Metainfo/RevokeAPIKey",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCAPIKeyRevocationServer).
+					RevokeAPIKey(
+						ctx,
+						in1.(*RevokeAPIKeyRequest),
+					)
+			}, DRPCAPIKeyRevocationServer.RevokeAPIKey, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+// DRPCRegisterAPIKeyRevocation registers the API key revocation RPC.
+func DRPCRegisterAPIKeyRevocation(mux drpc.Mux, impl DRPCAPIKeyRevocationServer) error {
+	return mux.Register(impl, DRPCAPIKeyRevocationDescription{})
+}
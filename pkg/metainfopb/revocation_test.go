@@ -0,0 +1,28 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfopb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/common/testrand"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+func TestRevokeAPIKeyRequestEncoding(t *testing.T) {
+	req := &metainfopb.RevokeAPIKeyRequest{
+		Header: &pb.RequestHeader{ApiKey: testrand.BytesInt(32)},
+		ApiKey: testrand.BytesInt(64),
+	}
+
+	data, err := pb.Marshal(req)
+	require.NoError(t, err)
+
+	decoded := &metainfopb.RevokeAPIKeyRequest{}
+	require.NoError(t, pb.Unmarshal(data, decoded))
+	require.True(t, pb.Equal(req, decoded))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"fmt"
+	"time"
 
 	"github.com/mattn/go-sqlite3"
 	"github.com/zeebo/errs"
@@ -21,8 +22,15 @@ var (
 
 	// ErrKeepTables is error class for MigrateTables
 	ErrKeepTables = errs.Class("keep tables")
+
+	// ErrBackup is error class for BackupDatabase
+	ErrBackup = errs.Class("backup")
 )
 
+// backupRetryInterval is how long BackupDatabase waits before retrying a
+// backup step which couldn't lock the source database.
+const backupRetryInterval = 10 * time.Millisecond
+
 // getSqlite3Conn attempts to get a *sqlite3.SQLiteConn from the connection.
 func getSqlite3Conn(conn interface{}) (*sqlite3.SQLiteConn, error) {
 	for {
@@ -41,7 +49,7 @@ func getSqlite3Conn(conn interface{}) (*sqlite3.SQLiteConn, error) {
 // All tables in destDB will be dropped other than those specified in
 // tablesToKeep.
 func MigrateTablesToDatabase(ctx context.Context, srcDB, destDB tagsql.DB, tablesToKeep ...string) error {
-	err := backupDBs(ctx, srcDB, destDB)
+	err := backupDBs(ctx, srcDB, destDB, backupConns)
 	if err != nil {
 		return ErrMigrateTables.Wrap(err)
 	}
@@ -50,7 +58,14 @@ func MigrateTablesToDatabase(ctx context.Context, srcDB, destDB tagsql.DB, table
 	return ErrMigrateTables.Wrap(KeepTables(ctx, destDB, tablesToKeep...))
 }
 
-func backupDBs(ctx context.Context, srcDB, destDB tagsql.DB) error {
+// BackupDatabase copies the whole srcDB into destDB using the sqlite3 online
+// backup API. The copy is a consistent snapshot of srcDB, even when srcDB is
+// being written to by other connections.
+func BackupDatabase(ctx context.Context, srcDB, destDB tagsql.DB) error {
+	return ErrBackup.Wrap(backupDBs(ctx, srcDB, destDB, copyConns))
+}
+
+func backupDBs(ctx context.Context, srcDB, destDB tagsql.DB, backup func(ctx context.Context, sourceDB, destDB *sqlite3.SQLiteConn) error) error {
 	// Retrieve the raw Sqlite3 driver connections for the src and dest so that
 	// we can execute the backup API for a corruption safe clone.
 	srcConn, err := srcDB.Conn(ctx)
@@ -85,7 +100,7 @@ func backupDBs(ctx context.Context, srcDB, destDB tagsql.DB) error {
 				return err
 			}
 
-			return ErrMigrateTables.Wrap(backupConns(ctx, srcSqliteConn, destSqliteConn))
+			return ErrMigrateTables.Wrap(backup(ctx, srcSqliteConn, destSqliteConn))
 		})
 		if err != nil {
 			return ErrMigrateTables.Wrap(err)
@@ -154,6 +169,33 @@ func backupConns(ctx context.Context, sourceDB *sqlite3.SQLiteConn, destDB *sqli
 	return nil
 }
 
+// copyConns copies the entire source database to the destination, retrying
+// while the source is locked by another connection.
+func copyConns(ctx context.Context, sourceDB *sqlite3.SQLiteConn, destDB *sqlite3.SQLiteConn) (err error) {
+	backup, err := destDB.Backup("main", sourceDB, "main")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, backup.Finish()) }()
+
+	for {
+		// Step -1 is used to copy the entire source database to the destination.
+		isDone, err := backup.Step(-1)
+		if err != nil {
+			return err
+		}
+		if isDone {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backupRetryInterval):
+		}
+	}
+}
+
 // KeepTables drops all the tables except the specified tables to keep.
 func KeepTables(ctx context.Context, db tagsql.DB, tablesToKeep ...string) (err error) {
 	err = dropTables(ctx, db, tablesToKeep...)
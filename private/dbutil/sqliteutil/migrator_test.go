@@ -85,6 +85,50 @@ func TestKeepTables(t *testing.T) {
 	require.Equal(t, snapshot.Data, data)
 }
 
+func TestBackupDatabase(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	srcDB := newMemDB(t)
+	defer ctx.Check(srcDB.Close)
+	destDB := newMemDB(t)
+	defer ctx.Check(destDB.Close)
+
+	query := `
+		CREATE TABLE bobby_jones(I Int);
+		INSERT INTO bobby_jones VALUES (1);
+		CREATE TABLE what(I Int);
+	`
+
+	execSQL(ctx, t, srcDB, query)
+
+	err := sqliteutil.BackupDatabase(ctx, srcDB, destDB)
+	require.NoError(t, err)
+
+	destSchema, err := sqliteutil.QuerySchema(ctx, destDB)
+	require.NoError(t, err)
+
+	destData, err := sqliteutil.QueryData(ctx, destDB, destSchema)
+	require.NoError(t, err)
+
+	snapshot, err := sqliteutil.LoadSnapshotFromSQL(ctx, query)
+	require.NoError(t, err)
+
+	require.Equal(t, snapshot.Schema, destSchema)
+	require.Equal(t, snapshot.Data, destData)
+
+	// an empty database is a valid backup source as well
+	emptyDB := newMemDB(t)
+	defer ctx.Check(emptyDB.Close)
+
+	err = sqliteutil.BackupDatabase(ctx, emptyDB, destDB)
+	require.NoError(t, err)
+
+	destSchema, err = sqliteutil.QuerySchema(ctx, destDB)
+	require.NoError(t, err)
+	require.Empty(t, destSchema.Tables)
+}
+
 func execSQL(ctx context.Context, t *testing.T, db tagsql.DB, query string, args ...interface{}) {
 	_, err := db.ExecContext(ctx, query, args...)
 	require.NoError(t, err)
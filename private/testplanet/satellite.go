@@ -36,6 +36,7 @@ import (
 	"storj.io/storj/satellite/accounting/tally"
 	"storj.io/storj/satellite/admin"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleweb"
 	"storj.io/storj/satellite/contact"
@@ -49,6 +50,7 @@ import (
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/metainfo/expireddeletion"
 	"storj.io/storj/satellite/metainfo/piecedeletion"
+	"storj.io/storj/satellite/metainfo/uncommitteddeletion"
 	"storj.io/storj/satellite/metrics"
 	"storj.io/storj/satellite/nodestats"
 	"storj.io/storj/satellite/orders"
@@ -131,6 +133,10 @@ type Satellite struct {
 		Chore *expireddeletion.Chore
 	}
 
+	UncommittedDeletion struct {
+		Chore *uncommitteddeletion.Chore
+	}
+
 	DBCleanup struct {
 		Chore *dbcleanup.Chore
 	}
@@ -178,6 +184,10 @@ type Satellite struct {
 		Chore *metrics.Chore
 	}
 
+	BucketEvents struct {
+		Chore *bucketevents.Chore
+	}
+
 	DowntimeTracking struct {
 		DetectionChore  *downtime.DetectionChore
 		EstimationChore *downtime.EstimationChore
@@ -345,12 +355,13 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 				UpdateStatsBatchSize: 100,
 			},
 			Metainfo: metainfo.Config{
-				DatabaseURL:          "", // not used
-				MinRemoteSegmentSize: 0,  // TODO: fix tests to work with 1024
-				MaxInlineSegmentSize: 4 * memory.KiB,
-				MaxSegmentSize:       64 * memory.MiB,
-				MaxCommitInterval:    1 * time.Hour,
-				Overlay:              true,
+				DatabaseURL:             "", // not used
+				MinRemoteSegmentSize:    0,  // TODO: fix tests to work with 1024
+				MaxInlineSegmentSize:    4 * memory.KiB,
+				MaxSegmentSize:          64 * memory.MiB,
+				MaxCommitInterval:       1 * time.Hour,
+				MaxObjectCommitInterval: 2 * time.Hour,
+				Overlay:                 true,
 				RS: metainfo.RSConfig{
 					MaxBufferMem:     memory.Size(256),
 					ErasureShareSize: memory.Size(256),
@@ -428,6 +439,10 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 				Interval: defaultInterval,
 				Enabled:  true,
 			},
+			UncommittedDeletion: uncommitteddeletion.Config{
+				Interval: defaultInterval,
+				Enabled:  true,
+			},
 			DBCleanup: dbcleanup.Config{
 				SerialsInterval: defaultInterval,
 			},
@@ -487,6 +502,17 @@ func (planet *Planet) newSatellites(count int, satelliteDatabases satellitedbtes
 			Metrics: metrics.Config{
 				ChoreInterval: defaultInterval,
 			},
+			BucketEvents: bucketevents.Config{
+				Interval:              defaultInterval,
+				BatchSize:             100,
+				Concurrency:           10,
+				EndpointConcurrency:   2,
+				Timeout:               10 * time.Second,
+				MaxAttempts:           3,
+				InitialBackoff:        time.Second,
+				MaxBackoff:            time.Minute,
+				AllowPrivateAddresses: true,
+			},
 			Downtime: downtime.Config{
 				DetectionInterval:          defaultInterval,
 				EstimationInterval:         defaultInterval,
@@ -613,6 +639,8 @@ func createNewSystem(log *zap.Logger, config satellite.Config, peer *satellite.C
 
 	system.ExpiredDeletion.Chore = peer.ExpiredDeletion.Chore
 
+	system.UncommittedDeletion.Chore = peer.UncommittedDeletion.Chore
+
 	system.DBCleanup.Chore = peer.DBCleanup.Chore
 
 	system.Accounting.Tally = peer.Accounting.Tally
@@ -629,6 +657,7 @@ func createNewSystem(log *zap.Logger, config satellite.Config, peer *satellite.C
 	system.GracefulExit.Endpoint = api.GracefulExit.Endpoint
 
 	system.Metrics.Chore = peer.Metrics.Chore
+	system.BucketEvents.Chore = peer.BucketEvents.Chore
 
 	system.DowntimeTracking.DetectionChore = peer.DowntimeTracking.DetectionChore
 	system.DowntimeTracking.EstimationChore = peer.DowntimeTracking.EstimationChore
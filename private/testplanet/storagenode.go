@@ -127,6 +127,7 @@ func (planet *Planet) newStorageNodes(count int, whitelistedSatellites storj.Nod
 			Console: consoleserver.Config{
 				Address:   "127.0.0.1:0",
 				StaticDir: filepath.Join(developmentRoot, "web/storagenode/"),
+				Metrics:   true,
 			},
 			Storage2: piecestore.Config{
 				CacheSyncInterval:       defaultInterval,
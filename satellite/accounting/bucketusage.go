@@ -4,8 +4,13 @@
 package accounting
 
 import (
+	"strings"
 	"time"
 
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
 	"storj.io/common/uuid"
 )
 
@@ -24,3 +29,31 @@ type BucketStorageTally struct {
 	RemoteBytes  int64
 	MetadataSize int64
 }
+
+// BucketLimits contains the optional usage limits of a bucket.
+//
+// A nil limit means that the bucket is only limited by its project limits.
+type BucketLimits struct {
+	Storage   *memory.Size
+	Bandwidth *memory.Size
+}
+
+// BucketKey returns the key of a bucket in the live accounting totals, which
+// is the same as the key of the bucket tallies.
+func BucketKey(projectID uuid.UUID, bucketName []byte) string {
+	return storj.JoinPaths(projectID.String(), string(bucketName))
+}
+
+// ParseBucketKey parses the project ID and the bucket name from a key created
+// by BucketKey.
+func ParseBucketKey(key string) (projectID uuid.UUID, bucketName []byte, err error) {
+	i := strings.IndexByte(key, '/')
+	if i < 0 {
+		return uuid.UUID{}, nil, errs.New("invalid bucket key %q", key)
+	}
+	projectID, err = uuid.FromString(key[:i])
+	if err != nil {
+		return uuid.UUID{}, nil, err
+	}
+	return projectID, []byte(key[i+1:]), nil
+}
@@ -185,14 +185,25 @@ type ProjectAccounting interface {
 	GetBucketUsageRollups(ctx context.Context, projectID uuid.UUID, since, before time.Time) ([]BucketUsageRollup, error)
 	// GetBucketTotals returns per bucket usage summary for specified period of time.
 	GetBucketTotals(ctx context.Context, projectID uuid.UUID, cursor BucketUsageCursor, since, before time.Time) (*BucketUsagePage, error)
+	// GetBucketAllocatedBandwidth returns the sum of GET bandwidth usage allocated for a bucket since from.
+	GetBucketAllocatedBandwidth(ctx context.Context, projectID uuid.UUID, bucketName []byte, from time.Time) (int64, error)
+	// GetBucketLimits returns the storage and bandwidth limits of a bucket.
+	GetBucketLimits(ctx context.Context, projectID uuid.UUID, bucketName []byte) (BucketLimits, error)
+	// UpdateBucketLimits updates the storage and bandwidth limits of a bucket.
+	UpdateBucketLimits(ctx context.Context, projectID uuid.UUID, bucketName []byte, limits BucketLimits) error
 }
 
-// Cache stores live information about project storage which has not yet been synced to ProjectAccounting.
+// Cache stores live information about project and bucket storage which has not yet been synced to ProjectAccounting.
+//
+// The bucket totals are keyed by BucketKey.
 //
 // architecture: Database
 type Cache interface {
 	GetProjectStorageUsage(ctx context.Context, projectID uuid.UUID) (totalUsed int64, err error)
 	AddProjectStorageUsage(ctx context.Context, projectID uuid.UUID, spaceUsed int64) error
 	GetAllProjectTotals(ctx context.Context) (map[uuid.UUID]int64, error)
+	GetBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte) (totalUsed int64, err error)
+	AddBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, spaceUsed int64) error
+	GetAllBucketTotals(ctx context.Context) (map[string]int64, error)
 	Close() error
 }
@@ -5,6 +5,7 @@ package live
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/zeebo/errs"
@@ -21,7 +22,8 @@ var (
 
 // Config contains configurable values for the live accounting service.
 type Config struct {
-	StorageBackend string `help:"what to use for storing real-time accounting data"`
+	StorageBackend string        `help:"what to use for storing real-time accounting data, one of redis://, memory: or the postgres:// or cockroach:// URL of the satellite database"`
+	FlushInterval  time.Duration `help:"how often the database backend writes the buffered usage increments" default:"1s"`
 }
 
 // NewCache creates a new accounting.Cache instance using the type specified backend in
@@ -37,7 +39,11 @@ func NewCache(log *zap.Logger, config Config) (accounting.Cache, error) {
 	switch backendType {
 	case "redis":
 		return newRedisLiveAccounting(log, config.StorageBackend)
+	case "postgres", "postgresql", "cockroach":
+		return newSQLLiveAccounting(log, config.StorageBackend, config.FlushInterval)
+	case "memory":
+		return newMemoryLiveAccounting(log), nil
 	default:
-		return nil, Error.New("unrecognized live accounting backend specifier %q. Currently only redis is supported", backendType)
+		return nil, Error.New("unrecognized live accounting backend specifier %q. Currently redis, postgres, cockroach and memory are supported", backendType)
 	}
 }
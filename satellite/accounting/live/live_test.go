@@ -4,31 +4,24 @@
 package live_test
 
 import (
-	"context"
-	"math/rand"
 	"testing"
+	"time"
 
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
-	"golang.org/x/sync/errgroup"
 
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
-	"storj.io/common/uuid"
-	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/private/dbutil"
+	"storj.io/storj/private/dbutil/pgtest"
+	"storj.io/storj/private/dbutil/tempdb"
 	"storj.io/storj/satellite/accounting/live"
+	"storj.io/storj/satellite/accounting/live/testsuite"
+	"storj.io/storj/satellite/satellitedb"
 	"storj.io/storj/storage/redis/redisserver"
 )
 
-func TestLiveAccountingCache(t *testing.T) {
-	tests := []struct {
-		backend string
-	}{
-		{
-			backend: "redis",
-		},
-	}
+func TestRedisCache(t *testing.T) {
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
 
@@ -36,163 +29,101 @@ func TestLiveAccountingCache(t *testing.T) {
 	require.NoError(t, err)
 	defer ctx.Check(redis.Close)
 
-	for _, tt := range tests {
-		var config live.Config
-		if tt.backend == "redis" {
-			config = live.Config{
-				StorageBackend: "redis://" + redis.Addr() + "?db=0",
-			}
-		}
-
-		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
-		require.NoError(t, err)
-
-		projectIDs, sum, err := populateCache(ctx, cache)
-		require.NoError(t, err)
-
-		// make sure all of the "projects" got all space updates and got right totals
-		for _, projID := range projectIDs {
-			spaceUsed, err := cache.GetProjectStorageUsage(ctx, projID)
-			require.NoError(t, err)
-			assert.Equalf(t, sum, spaceUsed, "projectID %v", projID)
-		}
-
-		negativeVal := int64(-100)
-		sum += negativeVal
-
-		for _, projID := range projectIDs {
-			err = cache.AddProjectStorageUsage(ctx, projID, negativeVal)
-			require.NoError(t, err)
+	cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), live.Config{
+		StorageBackend: "redis://" + redis.Addr() + "?db=0",
+	})
+	require.NoError(t, err)
+	defer ctx.Check(cache.Close)
 
-			spaceUsed, err := cache.GetProjectStorageUsage(ctx, projID)
-			require.NoError(t, err)
-			assert.EqualValues(t, sum, spaceUsed)
-		}
-	}
+	testsuite.RunTests(t, cache)
 }
 
-func TestRedisCacheConcurrency(t *testing.T) {
+func TestMemoryCache(t *testing.T) {
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
 
-	redis, err := redisserver.Mini()
-	require.NoError(t, err)
-	defer ctx.Check(redis.Close)
-
-	config := live.Config{
-		StorageBackend: "redis://" + redis.Addr() + "?db=0",
-	}
-	cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
+	cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), live.Config{
+		StorageBackend: "memory:",
+	})
 	require.NoError(t, err)
+	defer ctx.Check(cache.Close)
 
-	projectID := testrand.UUID()
+	testsuite.RunTests(t, cache)
+}
 
-	const (
-		numConcurrent = 100
-		spaceUsed     = 10
-	)
-	expectedSum := spaceUsed * numConcurrent
+func TestSQLCache(t *testing.T) {
+	pgtest.Run(t, func(ctx *testcontext.Context, t *testing.T, connstr string) {
+		db := openMigratedDB(ctx, t, connstr)
+		defer ctx.Check(db.Close)
 
-	var group errgroup.Group
-	for i := 0; i < numConcurrent; i++ {
-		group.Go(func() error {
-			return cache.AddProjectStorageUsage(ctx, projectID, spaceUsed)
+		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), live.Config{
+			StorageBackend: db.ConnStr,
+			FlushInterval:  time.Hour,
 		})
-	}
-	require.NoError(t, group.Wait())
-
-	total, err := cache.GetProjectStorageUsage(ctx, projectID)
-	require.NoError(t, err)
+		require.NoError(t, err)
+		defer ctx.Check(cache.Close)
 
-	require.EqualValues(t, expectedSum, total)
+		testsuite.RunTests(t, cache)
+	})
 }
 
-func populateCache(ctx context.Context, cache accounting.Cache) (projectIDs []uuid.UUID, sum int64, _ error) {
-	const (
-		valuesListSize  = 10
-		valueMultiplier = 4096
-		numProjects     = 100
-	)
-	// make a largish list of varying values
-	someValues := make([]int64, valuesListSize)
-	for i := range someValues {
-		someValues[i] = int64((i + 1) * valueMultiplier)
-		sum += someValues[i]
-	}
-
-	// make up some project IDs
-	projectIDs = make([]uuid.UUID, numProjects)
-	for i := range projectIDs {
-		projectIDs[i] = testrand.UUID()
-	}
-
-	// send lots of space used updates for all of these projects to the live
-	// accounting store.
-	errg, ctx := errgroup.WithContext(context.Background())
-	for _, projID := range projectIDs {
-		projID := projID
-		errg.Go(func() error {
-			// have each project sending the values in a different order
-			myValues := make([]int64, valuesListSize)
-			copy(myValues, someValues)
-			rand.Shuffle(valuesListSize, func(v1, v2 int) {
-				myValues[v1], myValues[v2] = myValues[v2], myValues[v1]
-			})
-
-			for _, val := range myValues {
-				if err := cache.AddProjectStorageUsage(ctx, projID, val); err != nil {
-					return err
-				}
-			}
-			return nil
-		})
-	}
+func TestSQLCacheFlush(t *testing.T) {
+	pgtest.Run(t, func(ctx *testcontext.Context, t *testing.T, connstr string) {
+		db := openMigratedDB(ctx, t, connstr)
+		defer ctx.Check(db.Close)
 
-	return projectIDs, sum, errg.Wait()
-}
+		config := live.Config{
+			StorageBackend: db.ConnStr,
+			FlushInterval:  time.Hour,
+		}
 
-func TestGetAllProjectTotals(t *testing.T) {
-	tests := []struct {
-		backend string
-	}{
-		{
-			backend: "redis",
-		},
-	}
-	ctx := testcontext.New(t)
-	defer ctx.Cleanup()
+		first, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
+		require.NoError(t, err)
+		defer ctx.Check(first.Close)
 
-	redis, err := redisserver.Mini()
-	require.NoError(t, err)
-	defer ctx.Check(redis.Close)
+		second, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
+		require.NoError(t, err)
+		defer ctx.Check(second.Close)
 
-	for _, tt := range tests {
-		var config live.Config
-		if tt.backend == "redis" {
-			config = live.Config{
-				StorageBackend: "redis://" + redis.Addr() + "?db=0",
-			}
-		}
+		projectID := testrand.UUID()
+		require.NoError(t, first.AddProjectStorageUsage(ctx, projectID, 10))
+		require.NoError(t, second.AddProjectStorageUsage(ctx, projectID, 20))
 
-		cache, err := live.NewCache(zaptest.NewLogger(t).Named("live-accounting"), config)
+		// the increments are buffered until the next flush
+		total, err := first.GetProjectStorageUsage(ctx, projectID)
 		require.NoError(t, err)
+		require.EqualValues(t, 10, total)
 
-		projectIDs := make([]uuid.UUID, 1000)
-		for i := range projectIDs {
-			projectIDs[i] = testrand.UUID()
-			err := cache.AddProjectStorageUsage(ctx, projectIDs[i], int64(i))
-			require.NoError(t, err)
-		}
+		// listing the totals flushes the buffered increments
+		totals, err := first.GetAllProjectTotals(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 10, totals[projectID])
 
-		projectTotals, err := cache.GetAllProjectTotals(ctx)
+		totals, err = second.GetAllProjectTotals(ctx)
 		require.NoError(t, err)
-		require.Len(t, projectTotals, len(projectIDs))
+		require.EqualValues(t, 30, totals[projectID])
 
-		// make sure each project ID and total was received
-		for _, projID := range projectIDs {
-			total, err := cache.GetProjectStorageUsage(ctx, projID)
-			require.NoError(t, err)
-			assert.Equal(t, total, projectTotals[projID])
-		}
-	}
+		total, err = first.GetProjectStorageUsage(ctx, projectID)
+		require.NoError(t, err)
+		require.EqualValues(t, 30, total)
+	})
+}
+
+// openMigratedDB creates a temporary database with the tables of the satellite
+// database, where the live accounting totals are stored.
+func openMigratedDB(ctx *testcontext.Context, t *testing.T, connstr string) *dbutil.TempDatabase {
+	db, err := tempdb.OpenUnique(ctx, connstr, "live-accounting")
+	require.NoError(t, err)
+
+	satelliteDB, err := satellitedb.New(zaptest.NewLogger(t).Named("db"), db.ConnStr, satellitedb.Options{})
+	require.NoError(t, err)
+	defer ctx.Check(satelliteDB.Close)
+	require.NoError(t, satelliteDB.TestingMigrateToLatest(ctx))
+
+	return db
+}
+
+func TestUnknownBackend(t *testing.T) {
+	_, err := live.NewCache(zaptest.NewLogger(t), live.Config{StorageBackend: "bolt://live.db"})
+	require.Error(t, err)
 }
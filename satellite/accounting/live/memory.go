@@ -0,0 +1,110 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package live
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
+)
+
+// memoryLiveAccounting keeps the live accounting totals in the memory of the
+// process. The totals aren't shared between processes nor kept across
+// restarts, hence it's only meant for development and single process
+// satellites.
+type memoryLiveAccounting struct {
+	log *zap.Logger
+
+	mu       sync.Mutex
+	projects map[uuid.UUID]int64
+	buckets  map[string]int64
+}
+
+func newMemoryLiveAccounting(log *zap.Logger) *memoryLiveAccounting {
+	return &memoryLiveAccounting{
+		log:      log,
+		projects: make(map[uuid.UUID]int64),
+		buckets:  make(map[string]int64),
+	}
+}
+
+// GetProjectStorageUsage gets inline and remote storage totals for a given
+// project, back to the time of the last accounting tally.
+func (cache *memoryLiveAccounting) GetProjectStorageUsage(ctx context.Context, projectID uuid.UUID) (totalUsed int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.projects[projectID], nil
+}
+
+// AddProjectStorageUsage lets the live accounting know that the given
+// project has just added spaceUsed bytes of storage (from the user's
+// perspective; i.e. segment size).
+func (cache *memoryLiveAccounting) AddProjectStorageUsage(ctx context.Context, projectID uuid.UUID, spaceUsed int64) (err error) {
+	defer mon.Task()(&ctx, projectID, spaceUsed)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.projects[projectID] += spaceUsed
+	return nil
+}
+
+// GetAllProjectTotals returns a map of project IDs and totals.
+func (cache *memoryLiveAccounting) GetAllProjectTotals(ctx context.Context) (_ map[uuid.UUID]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	projects := make(map[uuid.UUID]int64, len(cache.projects))
+	for projectID, total := range cache.projects {
+		projects[projectID] = total
+	}
+	return projects, nil
+}
+
+// GetBucketStorageUsage gets inline and remote storage totals for a given
+// bucket, back to the time of the last accounting tally.
+func (cache *memoryLiveAccounting) GetBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte) (totalUsed int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.buckets[accounting.BucketKey(projectID, bucketName)], nil
+}
+
+// AddBucketStorageUsage lets the live accounting know that the given
+// bucket has just added spaceUsed bytes of storage.
+func (cache *memoryLiveAccounting) AddBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, spaceUsed int64) (err error) {
+	defer mon.Task()(&ctx, projectID, spaceUsed)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.buckets[accounting.BucketKey(projectID, bucketName)] += spaceUsed
+	return nil
+}
+
+// GetAllBucketTotals returns a map of bucket keys and totals.
+func (cache *memoryLiveAccounting) GetAllBucketTotals(ctx context.Context) (_ map[string]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	buckets := make(map[string]int64, len(cache.buckets))
+	for key, total := range cache.buckets {
+		buckets[key] = total
+	}
+	return buckets, nil
+}
+
+// Close releases the resources of the cache.
+func (cache *memoryLiveAccounting) Close() error {
+	return nil
+}
@@ -6,10 +6,12 @@ package live
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 
 	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/redis"
 )
@@ -66,6 +68,10 @@ func (cache *redisLiveAccounting) GetAllProjectTotals(ctx context.Context) (_ ma
 			if item.Key == nil {
 				return Error.New("nil key")
 			}
+			if len(item.Key) != len(uuid.UUID{}) {
+				// bucket totals are stored next to the project totals
+				continue
+			}
 			id := new(uuid.UUID)
 			copy(id[:], item.Key[:])
 			intval, err := strconv.Atoi(string(item.Value))
@@ -79,6 +85,61 @@ func (cache *redisLiveAccounting) GetAllProjectTotals(ctx context.Context) (_ ma
 	return projects, err
 }
 
+// GetBucketStorageUsage gets inline and remote storage totals for a given
+// bucket, back to the time of the last accounting tally.
+func (cache *redisLiveAccounting) GetBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte) (totalUsed int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+	val, err := cache.client.Get(ctx, bucketKey(projectID, bucketName))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return 0, nil
+		}
+		return 0, Error.Wrap(err)
+	}
+	intval, err := strconv.Atoi(string(val))
+	return int64(intval), Error.Wrap(err)
+}
+
+// AddBucketStorageUsage lets the live accounting know that the given
+// bucket has just added spaceUsed bytes of storage.
+func (cache *redisLiveAccounting) AddBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, spaceUsed int64) (err error) {
+	defer mon.Task()(&ctx, projectID, spaceUsed)(&err)
+	return cache.client.IncrBy(ctx, bucketKey(projectID, bucketName), spaceUsed)
+}
+
+// GetAllBucketTotals iterates through the live accounting DB and returns a map of bucket keys and totals.
+func (cache *redisLiveAccounting) GetAllBucketTotals(ctx context.Context) (_ map[string]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	buckets := make(map[string]int64)
+
+	err = cache.client.Iterate(ctx, storage.IterateOptions{Recurse: true}, func(ctx context.Context, it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(ctx, &item) {
+			if len(item.Key) <= len(uuid.UUID{}) {
+				continue
+			}
+			var projectID uuid.UUID
+			copy(projectID[:], item.Key)
+			bucketName := strings.TrimPrefix(string(item.Key[len(projectID):]), "/")
+
+			intval, err := strconv.Atoi(string(item.Value))
+			if err != nil {
+				return Error.New("could not get total for bucket %s/%s", projectID.String(), bucketName)
+			}
+			buckets[accounting.BucketKey(projectID, []byte(bucketName))] = int64(intval)
+		}
+		return nil
+	})
+	return buckets, err
+}
+
+// bucketKey returns the key of the bucket totals, which starts with the
+// project ID, so that it sorts next to the project totals.
+func bucketKey(projectID uuid.UUID, bucketName []byte) []byte {
+	return append(append(projectID[:], '/'), bucketName...)
+}
+
 // Close the DB connection.
 func (cache *redisLiveAccounting) Close() error {
 	return cache.client.Close()
@@ -0,0 +1,265 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package live
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/sync2"
+	"storj.io/common/uuid"
+	"storj.io/storj/private/dbutil"
+	"storj.io/storj/private/dbutil/pgutil"
+	"storj.io/storj/private/tagsql"
+	"storj.io/storj/satellite/accounting"
+
+	_ "storj.io/storj/private/dbutil/cockroachutil" // registers the cockroach driver
+)
+
+// sqlLiveAccounting stores the live accounting totals in the
+// live_accounting_totals table of a Postgres or CockroachDB database, which is
+// created by the satellite database migration. Hence the database has to be
+// the satellite database or one migrated with it.
+//
+// The increments are buffered in memory and written in a single statement
+// every flush interval, so that uploads don't cause a database write each.
+// Hence the totals seen by other processes lag behind by up to the interval.
+type sqlLiveAccounting struct {
+	log *zap.Logger
+	db  tagsql.DB
+
+	// flushMu is held for writing while a flush moves the buffered
+	// increments to the database, so that reads don't miss the increments
+	// which are neither buffered nor written yet.
+	flushMu sync.RWMutex
+
+	mu      sync.Mutex
+	pending map[totalKey]int64
+
+	flushCycle *sync2.Cycle
+	group      errgroup.Group
+}
+
+// totalKey identifies a project total, when bucketName is empty, or a bucket
+// total.
+type totalKey struct {
+	projectID  uuid.UUID
+	bucketName string
+}
+
+func newSQLLiveAccounting(log *zap.Logger, dbURL string, flushInterval time.Duration) (_ *sqlLiveAccounting, err error) {
+	_, source, implementation, err := dbutil.SplitConnStr(dbURL)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var driver string
+	switch implementation {
+	case dbutil.Postgres:
+		driver = "postgres"
+	case dbutil.Cockroach:
+		driver = "cockroach"
+	default:
+		return nil, Error.New("unsupported db implementation: %s", dbURL)
+	}
+
+	db, err := tagsql.Open(driver, pgutil.CheckApplicationName(source))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	dbutil.Configure(db, "live-accounting", mon)
+
+	cache := &sqlLiveAccounting{
+		log:        log,
+		db:         db,
+		pending:    make(map[totalKey]int64),
+		flushCycle: sync2.NewCycle(flushInterval),
+	}
+	cache.flushCycle.Start(context.Background(), &cache.group, func(ctx context.Context) error {
+		if err := cache.flush(ctx); err != nil {
+			cache.log.Error("Writing live accounting totals failed.", zap.Error(err))
+		}
+		return nil
+	})
+	return cache, nil
+}
+
+// GetProjectStorageUsage gets inline and remote storage totals for a given
+// project, back to the time of the last accounting tally.
+func (cache *sqlLiveAccounting) GetProjectStorageUsage(ctx context.Context, projectID uuid.UUID) (totalUsed int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+	return cache.getTotal(ctx, totalKey{projectID: projectID})
+}
+
+// AddProjectStorageUsage lets the live accounting know that the given
+// project has just added spaceUsed bytes of storage (from the user's
+// perspective; i.e. segment size).
+func (cache *sqlLiveAccounting) AddProjectStorageUsage(ctx context.Context, projectID uuid.UUID, spaceUsed int64) (err error) {
+	defer mon.Task()(&ctx, projectID, spaceUsed)(&err)
+	cache.add(totalKey{projectID: projectID}, spaceUsed)
+	return nil
+}
+
+// GetAllProjectTotals returns a map of project IDs and totals.
+func (cache *sqlLiveAccounting) GetAllProjectTotals(ctx context.Context) (_ map[uuid.UUID]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	projects := make(map[uuid.UUID]int64)
+	err = cache.iterateTotals(ctx, func(key totalKey, total int64) {
+		if key.bucketName == "" {
+			projects[key.projectID] = total
+		}
+	})
+	return projects, err
+}
+
+// GetBucketStorageUsage gets inline and remote storage totals for a given
+// bucket, back to the time of the last accounting tally.
+func (cache *sqlLiveAccounting) GetBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte) (totalUsed int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+	return cache.getTotal(ctx, totalKey{projectID: projectID, bucketName: string(bucketName)})
+}
+
+// AddBucketStorageUsage lets the live accounting know that the given
+// bucket has just added spaceUsed bytes of storage.
+func (cache *sqlLiveAccounting) AddBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, spaceUsed int64) (err error) {
+	defer mon.Task()(&ctx, projectID, spaceUsed)(&err)
+	cache.add(totalKey{projectID: projectID, bucketName: string(bucketName)}, spaceUsed)
+	return nil
+}
+
+// GetAllBucketTotals returns a map of bucket keys and totals.
+func (cache *sqlLiveAccounting) GetAllBucketTotals(ctx context.Context) (_ map[string]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	buckets := make(map[string]int64)
+	err = cache.iterateTotals(ctx, func(key totalKey, total int64) {
+		if key.bucketName != "" {
+			buckets[accounting.BucketKey(key.projectID, []byte(key.bucketName))] = total
+		}
+	})
+	return buckets, err
+}
+
+// add buffers the increment of a total until the next flush.
+func (cache *sqlLiveAccounting) add(key totalKey, spaceUsed int64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.pending[key] += spaceUsed
+}
+
+// getTotal returns the stored total of key including the buffered increments.
+func (cache *sqlLiveAccounting) getTotal(ctx context.Context, key totalKey) (total int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.flushMu.RLock()
+	defer cache.flushMu.RUnlock()
+
+	err = cache.db.QueryRow(ctx, `
+		SELECT total FROM live_accounting_totals
+		WHERE project_id = $1 AND bucket_name = $2
+	`, key.projectID[:], []byte(key.bucketName)).Scan(&total)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, Error.Wrap(err)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return total + cache.pending[key], nil
+}
+
+// iterateTotals flushes the buffered increments and calls fn for every
+// stored total.
+func (cache *sqlLiveAccounting) iterateTotals(ctx context.Context, fn func(key totalKey, total int64)) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := cache.flush(ctx); err != nil {
+		return err
+	}
+
+	cache.flushMu.RLock()
+	defer cache.flushMu.RUnlock()
+
+	rows, err := cache.db.Query(ctx, `SELECT project_id, bucket_name, total FROM live_accounting_totals`)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(rows.Close())) }()
+
+	for rows.Next() {
+		var projectID, bucketName []byte
+		var total int64
+		if err := rows.Scan(&projectID, &bucketName, &total); err != nil {
+			return Error.Wrap(err)
+		}
+
+		id, err := uuid.FromBytes(projectID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		fn(totalKey{projectID: id, bucketName: string(bucketName)}, total)
+	}
+	return Error.Wrap(rows.Err())
+}
+
+// flush writes the buffered increments to the database. The increments are
+// kept for the next flush when the write fails.
+func (cache *sqlLiveAccounting) flush(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.flushMu.Lock()
+	defer cache.flushMu.Unlock()
+
+	cache.mu.Lock()
+	pending := cache.pending
+	cache.pending = make(map[totalKey]int64)
+	cache.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	projectIDs := make([][]byte, 0, len(pending))
+	bucketNames := make([][]byte, 0, len(pending))
+	totals := make([]int64, 0, len(pending))
+	for key, total := range pending {
+		projectID := key.projectID
+		projectIDs = append(projectIDs, projectID[:])
+		bucketNames = append(bucketNames, []byte(key.bucketName))
+		totals = append(totals, total)
+	}
+
+	_, err = cache.db.Exec(ctx, `
+		INSERT INTO live_accounting_totals (project_id, bucket_name, total)
+		SELECT unnest($1::bytea[]), unnest($2::bytea[]), unnest($3::int8[])
+		ON CONFLICT ( project_id, bucket_name )
+		DO UPDATE SET total = live_accounting_totals.total + EXCLUDED.total
+	`, pq.ByteaArray(projectIDs), pq.ByteaArray(bucketNames), pq.Array(totals))
+	if err != nil {
+		cache.mu.Lock()
+		for key, total := range pending {
+			cache.pending[key] += total
+		}
+		cache.mu.Unlock()
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
+// Close writes the buffered increments and closes the DB connection.
+func (cache *sqlLiveAccounting) Close() error {
+	cache.flushCycle.Close()
+	return errs.Combine(
+		cache.group.Wait(),
+		cache.flush(context.Background()),
+		cache.db.Close(),
+	)
+}
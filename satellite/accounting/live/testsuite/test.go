@@ -0,0 +1,189 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package testsuite contains the tests which every accounting.Cache
+// implementation must pass.
+package testsuite
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
+)
+
+// RunTests runs common accounting.Cache tests.
+func RunTests(t *testing.T, cache accounting.Cache) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	t.Run("ProjectStorageUsage", func(t *testing.T) { testProjectStorageUsage(t, ctx, cache) })
+	t.Run("Concurrency", func(t *testing.T) { testConcurrency(t, ctx, cache) })
+	t.Run("GetAllProjectTotals", func(t *testing.T) { testGetAllProjectTotals(t, ctx, cache) })
+	t.Run("BucketStorageUsage", func(t *testing.T) { testBucketStorageUsage(t, ctx, cache) })
+}
+
+func testProjectStorageUsage(t *testing.T, ctx *testcontext.Context, cache accounting.Cache) {
+	projectIDs, sum, err := populateCache(ctx, cache)
+	require.NoError(t, err)
+
+	// make sure all of the "projects" got all space updates and got right totals
+	for _, projID := range projectIDs {
+		spaceUsed, err := cache.GetProjectStorageUsage(ctx, projID)
+		require.NoError(t, err)
+		assert.Equalf(t, sum, spaceUsed, "projectID %v", projID)
+	}
+
+	negativeVal := int64(-100)
+	sum += negativeVal
+
+	for _, projID := range projectIDs {
+		err = cache.AddProjectStorageUsage(ctx, projID, negativeVal)
+		require.NoError(t, err)
+
+		spaceUsed, err := cache.GetProjectStorageUsage(ctx, projID)
+		require.NoError(t, err)
+		assert.EqualValues(t, sum, spaceUsed)
+	}
+
+	// unknown projects haven't used any storage
+	spaceUsed, err := cache.GetProjectStorageUsage(ctx, testrand.UUID())
+	require.NoError(t, err)
+	assert.Zero(t, spaceUsed)
+}
+
+func testConcurrency(t *testing.T, ctx *testcontext.Context, cache accounting.Cache) {
+	projectID := testrand.UUID()
+
+	const (
+		numConcurrent = 100
+		spaceUsed     = 10
+	)
+	expectedSum := spaceUsed * numConcurrent
+
+	var group errgroup.Group
+	for i := 0; i < numConcurrent; i++ {
+		group.Go(func() error {
+			return cache.AddProjectStorageUsage(ctx, projectID, spaceUsed)
+		})
+	}
+	require.NoError(t, group.Wait())
+
+	total, err := cache.GetProjectStorageUsage(ctx, projectID)
+	require.NoError(t, err)
+
+	require.EqualValues(t, expectedSum, total)
+}
+
+func testGetAllProjectTotals(t *testing.T, ctx *testcontext.Context, cache accounting.Cache) {
+	existingTotals, err := cache.GetAllProjectTotals(ctx)
+	require.NoError(t, err)
+
+	projectIDs := make([]uuid.UUID, 1000)
+	for i := range projectIDs {
+		projectIDs[i] = testrand.UUID()
+		err := cache.AddProjectStorageUsage(ctx, projectIDs[i], int64(i))
+		require.NoError(t, err)
+	}
+
+	projectTotals, err := cache.GetAllProjectTotals(ctx)
+	require.NoError(t, err)
+	require.Len(t, projectTotals, len(existingTotals)+len(projectIDs))
+
+	// make sure each project ID and total was received
+	for _, projID := range projectIDs {
+		total, err := cache.GetProjectStorageUsage(ctx, projID)
+		require.NoError(t, err)
+		assert.Equal(t, total, projectTotals[projID])
+	}
+}
+
+func testBucketStorageUsage(t *testing.T, ctx *testcontext.Context, cache accounting.Cache) {
+	existingProjectTotals, err := cache.GetAllProjectTotals(ctx)
+	require.NoError(t, err)
+	existingBucketTotals, err := cache.GetAllBucketTotals(ctx)
+	require.NoError(t, err)
+
+	projectID := testrand.UUID()
+	require.NoError(t, cache.AddProjectStorageUsage(ctx, projectID, 30))
+	require.NoError(t, cache.AddBucketStorageUsage(ctx, projectID, []byte("bucket-a"), 10))
+	require.NoError(t, cache.AddBucketStorageUsage(ctx, projectID, []byte("bucket-b"), 20))
+	require.NoError(t, cache.AddBucketStorageUsage(ctx, projectID, []byte("bucket-b"), -5))
+
+	total, err := cache.GetBucketStorageUsage(ctx, projectID, []byte("bucket-b"))
+	require.NoError(t, err)
+	require.EqualValues(t, 15, total)
+
+	total, err = cache.GetBucketStorageUsage(ctx, projectID, []byte("bucket-c"))
+	require.NoError(t, err)
+	require.Zero(t, total)
+
+	// the bucket totals don't change the project total
+	total, err = cache.GetProjectStorageUsage(ctx, projectID)
+	require.NoError(t, err)
+	require.EqualValues(t, 30, total)
+
+	bucketTotals, err := cache.GetAllBucketTotals(ctx)
+	require.NoError(t, err)
+	require.Len(t, bucketTotals, len(existingBucketTotals)+2)
+	require.EqualValues(t, 10, bucketTotals[accounting.BucketKey(projectID, []byte("bucket-a"))])
+	require.EqualValues(t, 15, bucketTotals[accounting.BucketKey(projectID, []byte("bucket-b"))])
+
+	// the bucket totals must not be mixed up with the project totals
+	projectTotals, err := cache.GetAllProjectTotals(ctx)
+	require.NoError(t, err)
+	require.Len(t, projectTotals, len(existingProjectTotals)+1)
+	require.EqualValues(t, 30, projectTotals[projectID])
+}
+
+func populateCache(ctx context.Context, cache accounting.Cache) (projectIDs []uuid.UUID, sum int64, _ error) {
+	const (
+		valuesListSize  = 10
+		valueMultiplier = 4096
+		numProjects     = 100
+	)
+	// make a largish list of varying values
+	someValues := make([]int64, valuesListSize)
+	for i := range someValues {
+		someValues[i] = int64((i + 1) * valueMultiplier)
+		sum += someValues[i]
+	}
+
+	// make up some project IDs
+	projectIDs = make([]uuid.UUID, numProjects)
+	for i := range projectIDs {
+		projectIDs[i] = testrand.UUID()
+	}
+
+	// send lots of space used updates for all of these projects to the live
+	// accounting store.
+	errg, ctx := errgroup.WithContext(ctx)
+	for _, projID := range projectIDs {
+		projID := projID
+		errg.Go(func() error {
+			// have each project sending the values in a different order
+			myValues := make([]int64, valuesListSize)
+			copy(myValues, someValues)
+			rand.Shuffle(valuesListSize, func(v1, v2 int) {
+				myValues[v1], myValues[v2] = myValues[v2], myValues[v1]
+			})
+
+			for _, val := range myValues {
+				if err := cache.AddProjectStorageUsage(ctx, projID, val); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return projectIDs, sum, errg.Wait()
+}
@@ -108,6 +108,44 @@ func (usage *Service) ExceedsStorageUsage(ctx context.Context, projectID uuid.UU
 	return false, limit, nil
 }
 
+// ExceedsBucketBandwidthUsage returns true if the bandwidth usage limit of the bucket
+// has been exceeded in the current month. Buckets without a bandwidth limit are never
+// exceeded, they are only limited by the project limit. The limits of the bucket are
+// passed by the caller, so that they can be cached.
+func (usage *Service) ExceedsBucketBandwidthUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, limits BucketLimits) (_ bool, limit memory.Size, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if limits.Bandwidth == nil {
+		return false, 0, nil
+	}
+
+	bandwidthGetTotal, err := usage.GetBucketBandwidthTotals(ctx, projectID, bucketName)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return bandwidthGetTotal >= limits.Bandwidth.Int64(), *limits.Bandwidth, nil
+}
+
+// ExceedsBucketStorageUsage returns true if the storage usage of the bucket is currently
+// over the bucket limit. Buckets without a storage limit are never exceeded, they are
+// only limited by the project limit. The limits of the bucket are passed by the caller,
+// so that they can be cached.
+func (usage *Service) ExceedsBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, limits BucketLimits) (_ bool, limit memory.Size, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if limits.Storage == nil {
+		return false, 0, nil
+	}
+
+	totalUsed, err := usage.GetBucketStorageTotals(ctx, projectID, bucketName)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return totalUsed >= limits.Storage.Int64(), *limits.Storage, nil
+}
+
 // GetProjectStorageTotals returns total amount of storage used by project.
 func (usage *Service) GetProjectStorageTotals(ctx context.Context, projectID uuid.UUID) (total int64, err error) {
 	defer mon.Task()(&ctx, projectID)(&err)
@@ -117,6 +155,15 @@ func (usage *Service) GetProjectStorageTotals(ctx context.Context, projectID uui
 	return total, ErrProjectUsage.Wrap(err)
 }
 
+// GetBucketStorageTotals returns total amount of storage used by bucket.
+func (usage *Service) GetBucketStorageTotals(ctx context.Context, projectID uuid.UUID, bucketName []byte) (total int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	total, err = usage.liveAccounting.GetBucketStorageUsage(ctx, projectID, bucketName)
+
+	return total, ErrProjectUsage.Wrap(err)
+}
+
 // GetProjectBandwidthTotals returns total amount of allocated bandwidth used for past 30 days.
 func (usage *Service) GetProjectBandwidthTotals(ctx context.Context, projectID uuid.UUID) (_ int64, err error) {
 	defer mon.Task()(&ctx, projectID)(&err)
@@ -129,6 +176,18 @@ func (usage *Service) GetProjectBandwidthTotals(ctx context.Context, projectID u
 	return total, ErrProjectUsage.Wrap(err)
 }
 
+// GetBucketBandwidthTotals returns total amount of allocated bandwidth used by bucket in the current month.
+func (usage *Service) GetBucketBandwidthTotals(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ int64, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	// from the beginning of the current month
+	year, month, _ := usage.nowFn().Date()
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+
+	total, err := usage.projectAccountingDB.GetBucketAllocatedBandwidth(ctx, projectID, bucketName, from)
+	return total, ErrProjectUsage.Wrap(err)
+}
+
 // GetProjectAllocatedBandwidth returns project allocated bandwidth for the specified year and month.
 func (usage *Service) GetProjectAllocatedBandwidth(ctx context.Context, projectID uuid.UUID, year int, month time.Month) (_ int64, err error) {
 	defer mon.Task()(&ctx, projectID)(&err)
@@ -174,6 +233,21 @@ func (usage *Service) UpdateProjectLimits(ctx context.Context, projectID uuid.UU
 	return ErrProjectUsage.Wrap(usage.projectAccountingDB.UpdateProjectUsageLimit(ctx, projectID, limit))
 }
 
+// GetBucketLimits returns the storage and bandwidth limits of the bucket.
+func (usage *Service) GetBucketLimits(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ BucketLimits, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	limits, err := usage.projectAccountingDB.GetBucketLimits(ctx, projectID, bucketName)
+	return limits, ErrProjectUsage.Wrap(err)
+}
+
+// UpdateBucketLimits sets new values for the storage and bandwidth limits of the bucket.
+func (usage *Service) UpdateBucketLimits(ctx context.Context, projectID uuid.UUID, bucketName []byte, limits BucketLimits) (err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+
+	return ErrProjectUsage.Wrap(usage.projectAccountingDB.UpdateBucketLimits(ctx, projectID, bucketName, limits))
+}
+
 // AddProjectStorageUsage lets the live accounting know that the given
 // project has just added spaceUsed bytes of storage (from the user's
 // perspective; i.e. segment size).
@@ -182,6 +256,13 @@ func (usage *Service) AddProjectStorageUsage(ctx context.Context, projectID uuid
 	return usage.liveAccounting.AddProjectStorageUsage(ctx, projectID, spaceUsed)
 }
 
+// AddBucketStorageUsage lets the live accounting know that the given
+// bucket has just added spaceUsed bytes of storage.
+func (usage *Service) AddBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucketName []byte, spaceUsed int64) (err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+	return usage.liveAccounting.AddBucketStorageUsage(ctx, projectID, bucketName, spaceUsed)
+}
+
 // SetNow allows tests to have the Service act as if the current time is whatever they want.
 func (usage *Service) SetNow(now func() time.Time) {
 	usage.nowFn = now
@@ -672,3 +672,68 @@ func TestProjectUsage_ResetLimitsFirstDayOfNextMonth(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestProjectUsage_BucketLimits(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		acctDB := satellite.DB.ProjectAccounting()
+
+		satellite.Orders.Chore.Loop.Pause()
+
+		projects, err := satellite.DB.Console().Projects().GetAll(ctx)
+		require.NoError(t, err)
+		project := projects[0]
+
+		data := testrand.Bytes(50 * memory.KiB)
+		err = planet.Uplinks[0].Upload(ctx, satellite, "limited", "test/path1", data)
+		require.NoError(t, err)
+		err = planet.Uplinks[0].Upload(ctx, satellite, "unlimited", "test/path1", data)
+		require.NoError(t, err)
+
+		storageLimit := 50 * memory.KiB
+		bandwidthLimit := 50 * memory.KiB
+		err = acctDB.UpdateBucketLimits(ctx, project.ID, []byte("limited"), accounting.BucketLimits{
+			Storage:   &storageLimit,
+			Bandwidth: &bandwidthLimit,
+		})
+		require.NoError(t, err)
+
+		limits, err := acctDB.GetBucketLimits(ctx, project.ID, []byte("limited"))
+		require.NoError(t, err)
+		require.Equal(t, storageLimit, *limits.Storage)
+		require.Equal(t, bandwidthLimit, *limits.Bandwidth)
+
+		// the limited bucket used its storage limit
+		err = planet.Uplinks[0].Upload(ctx, satellite, "limited", "test/path2", data)
+		require.Error(t, err)
+		require.True(t, errs2.IsRPC(err, rpcstatus.ResourceExhausted))
+
+		// the limit doesn't affect other buckets of the project
+		err = planet.Uplinks[0].Upload(ctx, satellite, "unlimited", "test/path2", data)
+		require.NoError(t, err)
+
+		_, err = planet.Uplinks[0].Download(ctx, satellite, "limited", "test/path1")
+		require.NoError(t, err)
+
+		satellite.Orders.Chore.Loop.TriggerWait()
+
+		// the limited bucket used its bandwidth limit
+		_, err = planet.Uplinks[0].Download(ctx, satellite, "limited", "test/path1")
+		require.Error(t, err)
+		require.True(t, errs2.IsRPC(err, rpcstatus.ResourceExhausted))
+
+		_, err = planet.Uplinks[0].Download(ctx, satellite, "unlimited", "test/path1")
+		require.NoError(t, err)
+
+		// removing the limits allows the bucket to be used again
+		err = acctDB.UpdateBucketLimits(ctx, project.ID, []byte("limited"), accounting.BucketLimits{})
+		require.NoError(t, err)
+
+		err = planet.Uplinks[0].Upload(ctx, satellite, "limited", "test/path2", data)
+		require.NoError(t, err)
+		_, err = planet.Uplinks[0].Download(ctx, satellite, "limited", "test/path1")
+		require.NoError(t, err)
+	})
+}
@@ -91,6 +91,10 @@ func (service *Service) Tally(ctx context.Context) (err error) {
 	if err != nil {
 		return Error.Wrap(err)
 	}
+	initialLiveBucketTotals, err := service.liveAccounting.GetAllBucketTotals(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
 	// Fetch when the last tally happened so we can roughly calculate the byte-hours.
 	lastTime, err := service.storagenodeAccountingDB.LastTimestamp(ctx, accounting.LastAtRestTally)
 	if err != nil {
@@ -156,6 +160,38 @@ func (service *Service) Tally(ctx context.Context) (err error) {
 				return Error.Wrap(err)
 			}
 		}
+
+		// update live accounting bucket totals the same way
+		latestLiveBucketTotals, err := service.liveAccounting.GetAllBucketTotals(ctx)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		tallyBucketTotals := make(map[string]int64, len(observer.Bucket))
+		for bucketKey, bucket := range observer.Bucket {
+			tallyBucketTotals[bucketKey] = bucket.InlineBytes + bucket.RemoteBytes
+		}
+		for bucketKey := range latestLiveBucketTotals {
+			if _, ok := tallyBucketTotals[bucketKey]; !ok {
+				tallyBucketTotals[bucketKey] = 0
+			}
+		}
+
+		for bucketKey, tallyTotal := range tallyBucketTotals {
+			delta := latestLiveBucketTotals[bucketKey] - initialLiveBucketTotals[bucketKey]
+			if delta < 0 {
+				delta = 0
+			}
+
+			projectID, bucketName, err := accounting.ParseBucketKey(bucketKey)
+			if err != nil {
+				return Error.Wrap(err)
+			}
+			err = service.liveAccounting.AddBucketStorageUsage(ctx, projectID, bucketName, -latestLiveBucketTotals[bucketKey]+tallyTotal+(delta/2))
+			if err != nil {
+				return Error.Wrap(err)
+			}
+		}
 	}
 
 	// report bucket metrics
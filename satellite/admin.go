@@ -114,7 +114,10 @@ func NewAdmin(log *zap.Logger, full *identity.FullIdentity, db DB,
 		adminConfig := config.Admin
 		adminConfig.AuthorizationToken = config.Console.AuthToken
 
-		peer.Admin.Server = admin.NewServer(log.Named("admin"), peer.Admin.Listener, peer.DB, adminConfig)
+		peer.Admin.Server, err = admin.NewServer(log.Named("admin"), peer.Admin.Listener, peer.DB, adminConfig)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Servers.Add(lifecycle.Item{
 			Name:  "admin",
 			Run:   peer.Admin.Server.Run,
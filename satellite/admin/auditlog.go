@@ -0,0 +1,97 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/overlay"
+)
+
+// AuditLog records the actions taken through the admin API.
+//
+// architecture: Database
+type AuditLog interface {
+	// WithTx runs fn in a transaction, so that the changes of an action are
+	// committed together with its record.
+	WithTx(ctx context.Context, fn func(ctx context.Context, tx AuditLogTx) error) error
+	// List returns up to limit actions taken on target, the most recent first.
+	// An empty target returns the actions taken on any target.
+	List(ctx context.Context, target string, limit int) ([]AuditLogEntry, error)
+}
+
+// AuditLogTx is a transaction of the audit log, in which the actions make
+// their changes.
+type AuditLogTx interface {
+	// Insert records an action.
+	Insert(ctx context.Context, entry AuditLogEntry) error
+	// Console returns the console database in the transaction.
+	Console() console.DB
+	// Nodes returns the node changes of the transaction.
+	Nodes() NodeChanges
+}
+
+// NodeChanges changes the status of the nodes.
+type NodeChanges interface {
+	// DisqualifyNode disqualifies a storage node.
+	DisqualifyNode(ctx context.Context, nodeID storj.NodeID) error
+	// ReinstateNode removes the disqualification of a storage node and resets
+	// its audit reputation.
+	ReinstateNode(ctx context.Context, nodeID storj.NodeID) error
+	// UnsuspendNode unsuspends a storage node.
+	UnsuspendNode(ctx context.Context, nodeID storj.NodeID) error
+	// UpdateExitStatus updates the graceful exit status of a storage node.
+	UpdateExitStatus(ctx context.Context, request *overlay.ExitStatusRequest) (*overlay.NodeDossier, error)
+}
+
+// AuditLogEntry is an action taken through the admin API.
+type AuditLogEntry struct {
+	ID        uuid.UUID `json:"id"`
+	Operator  string    `json:"operator"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// audited runs change in a transaction of the audit log, in which the action
+// taken on target is recorded with the operator authorized for the request.
+func (server *Server) audited(ctx context.Context, action, target, reason string, change func(ctx context.Context, tx AuditLogTx) error) error {
+	operator := operatorFromContext(ctx)
+
+	err := server.db.AdminAuditLog().WithTx(ctx, func(ctx context.Context, tx AuditLogTx) error {
+		if err := change(ctx, tx); err != nil {
+			return err
+		}
+
+		id, err := uuid.New()
+		if err != nil {
+			return err
+		}
+		return tx.Insert(ctx, AuditLogEntry{
+			ID:        id,
+			Operator:  operator,
+			Action:    action,
+			Target:    target,
+			Reason:    reason,
+			CreatedAt: time.Now(),
+		})
+	})
+	if err != nil {
+		server.log.Error("admin action failed",
+			zap.String("Operator", operator),
+			zap.String("Action", action),
+			zap.String("Target", target),
+			zap.String("Reason", reason),
+			zap.Error(err),
+		)
+	}
+	return err
+}
@@ -0,0 +1,304 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
+)
+
+func (server *Server) getBucketPlacement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketVars(w, r)
+	if !ok {
+		return
+	}
+
+	placement, err := server.db.Buckets().GetBucketPlacement(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket %q not found", bucket), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket placement: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Placement string   `json:"placement"`
+		Countries []string `json:"countries"`
+	}
+	output.Placement = placement.String()
+	output.Countries = placement.Countries()
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disapperaed
+}
+
+func (server *Server) putBucketPlacement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketVars(w, r)
+	if !ok {
+		return
+	}
+
+	var arguments struct {
+		Placement string `schema:"placement"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	placement, err := overlay.ParsePlacementConstraint(arguments.Placement)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid placement: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = server.db.Buckets().UpdateBucketPlacement(ctx, bucket, projectUUID, placement)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket %q not found", bucket), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket placement: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketVars(w, r)
+	if !ok {
+		return
+	}
+
+	versioning, err := server.db.Buckets().GetBucketVersioning(ctx, bucket, projectUUID)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket %q not found", bucket), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket versioning: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		Versioning string `json:"versioning"`
+	}
+	output.Versioning = versioning.String()
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disapperaed
+}
+
+func (server *Server) putBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketVars(w, r)
+	if !ok {
+		return
+	}
+
+	var arguments struct {
+		Versioning string `schema:"versioning"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	versioning, err := metainfo.ParseVersioning(arguments.Versioning)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid versioning: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = server.db.Buckets().UpdateBucketVersioning(ctx, bucket, projectUUID, versioning)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket %q not found", bucket), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket versioning: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) getBucketLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketVars(w, r)
+	if !ok {
+		return
+	}
+
+	limits, err := server.db.ProjectAccounting().GetBucketLimits(ctx, projectUUID, bucket)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket %q not found", bucket), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket limits: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	type Limit struct {
+		Amount memory.Size `json:"amount"`
+		Bytes  int64       `json:"bytes"`
+	}
+
+	var output struct {
+		Storage   *Limit `json:"storage"`
+		Bandwidth *Limit `json:"bandwidth"`
+	}
+	if limits.Storage != nil {
+		output.Storage = &Limit{Amount: *limits.Storage, Bytes: limits.Storage.Int64()}
+	}
+	if limits.Bandwidth != nil {
+		output.Bandwidth = &Limit{Amount: *limits.Bandwidth, Bytes: limits.Bandwidth.Int64()}
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disapperaed
+}
+
+func (server *Server) putBucketLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, bucket, ok := bucketVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limits, err := server.db.ProjectAccounting().GetBucketLimits(ctx, projectUUID, bucket)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket %q not found", bucket), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get bucket limits: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// only the limits present in the form are changed
+	if _, ok := r.Form["storage"]; ok {
+		limits.Storage, err = parseBucketLimit(r.Form.Get("storage"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid storage limit: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, ok := r.Form["bandwidth"]; ok {
+		limits.Bandwidth, err = parseBucketLimit(r.Form.Get("bandwidth"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid bandwidth limit: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = server.db.ProjectAccounting().UpdateBucketLimits(ctx, projectUUID, bucket, limits)
+	if storj.ErrBucketNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("bucket %q not found", bucket), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update bucket limits: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseBucketLimit parses a bucket limit, where "none" removes the limit.
+func parseBucketLimit(value string) (*memory.Size, error) {
+	if value == "none" {
+		return nil, nil
+	}
+
+	var limit memory.Size
+	if err := limit.Set(value); err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("negative limit: %v", limit)
+	}
+	return &limit, nil
+}
+
+// bucketVars parses the project and bucket from the request path.
+// When they are invalid, it writes the error response and returns ok = false.
+func bucketVars(w http.ResponseWriter, r *http.Request) (projectUUID uuid.UUID, bucket []byte, ok bool) {
+	vars := mux.Vars(r)
+	projectUUIDString, ok := vars["project"]
+	if !ok {
+		http.Error(w, "project-uuid missing", http.StatusBadRequest)
+		return uuid.UUID{}, nil, false
+	}
+
+	projectUUID, err := uuid.FromString(projectUUIDString)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid project-uuid: %v", err), http.StatusBadRequest)
+		return uuid.UUID{}, nil, false
+	}
+
+	bucketName, ok := vars["bucket"]
+	if !ok || bucketName == "" {
+		http.Error(w, "bucket name missing", http.StatusBadRequest)
+		return uuid.UUID{}, nil, false
+	}
+
+	return projectUUID, []byte(bucketName), true
+}
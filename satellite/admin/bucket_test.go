@@ -0,0 +1,162 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
+)
+
+func TestBucketPlacement(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		address := satellite.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, satellite, "placed"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/placed/placement"
+
+		assertGet(t, link, `{"placement":"any","countries":null}`)
+
+		req, err := http.NewRequest(http.MethodPut, link+"?placement=DE", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+
+		assertGet(t, link, `{"placement":"DE","countries":["DE"]}`)
+
+		placement, err := satellite.DB.Buckets().GetBucketPlacement(ctx, []byte("placed"), project.ID)
+		require.NoError(t, err)
+		require.Equal(t, overlay.DE, placement)
+
+		req, err = http.NewRequest(http.MethodPut, link+"?placement=mars", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+	})
+}
+
+func TestBucketVersioning(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		address := satellite.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, satellite, "versioned"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/versioned/versioning"
+
+		assertGet(t, link, `{"versioning":"unversioned"}`)
+
+		req, err := http.NewRequest(http.MethodPut, link+"?versioning=enabled", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+
+		assertGet(t, link, `{"versioning":"enabled"}`)
+
+		versioning, err := satellite.DB.Buckets().GetBucketVersioning(ctx, []byte("versioned"), project.ID)
+		require.NoError(t, err)
+		require.Equal(t, metainfo.VersioningEnabled, versioning)
+
+		req, err = http.NewRequest(http.MethodPut, link+"?versioning=sometimes", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "very-secret-token")
+
+		response, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.StatusCode)
+		require.NoError(t, response.Body.Close())
+	})
+}
+
+func TestBucketLimit(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		address := satellite.Admin.Admin.Listener.Addr()
+		project := planet.Uplinks[0].Projects[0]
+
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, satellite, "limited"))
+
+		link := "http://" + address.String() + "/api/project/" + project.ID.String() + "/bucket/limited/limit"
+
+		assertGet(t, link, `{"storage":null,"bandwidth":null}`)
+
+		put := func(query string, expectedStatus int) {
+			req, err := http.NewRequest(http.MethodPut, link+query, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "very-secret-token")
+
+			response, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, expectedStatus, response.StatusCode)
+			require.NoError(t, response.Body.Close())
+		}
+
+		put("?storage=1GB&bandwidth=2GB", http.StatusOK)
+		assertGet(t, link, `{"storage":{"amount":"1.0 GB","bytes":1000000000},"bandwidth":{"amount":"2.0 GB","bytes":2000000000}}`)
+
+		put("?bandwidth=none", http.StatusOK)
+		assertGet(t, link, `{"storage":{"amount":"1.0 GB","bytes":1000000000},"bandwidth":null}`)
+
+		limits, err := satellite.DB.ProjectAccounting().GetBucketLimits(ctx, project.ID, []byte("limited"))
+		require.NoError(t, err)
+		require.NotNil(t, limits.Storage)
+		require.Equal(t, memory.GB, *limits.Storage)
+		require.Nil(t, limits.Bandwidth)
+
+		put("?storage=-1GB", http.StatusBadRequest)
+		put("?storage=lots", http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,357 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/overlay"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 1000
+)
+
+func (server *Server) listNodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var arguments struct {
+		Search string `schema:"search"`
+		Cursor string `schema:"cursor"`
+		Limit  int    `schema:"limit"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var cursor storj.NodeID
+	if arguments.Cursor != "" {
+		cursor, err = storj.NodeIDFromString(arguments.Cursor)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid cursor: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := arguments.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	nodes, err := server.db.OverlayCache().SearchNodes(ctx, arguments.Search, cursor, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to search nodes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	type Node struct {
+		ID                 storj.NodeID `json:"id"`
+		Address            string       `json:"address"`
+		Email              string       `json:"email"`
+		Wallet             string       `json:"wallet"`
+		LastContactSuccess time.Time    `json:"lastContactSuccess"`
+		Disqualified       *time.Time   `json:"disqualified"`
+		Suspended          *time.Time   `json:"suspended"`
+		ExitInitiatedAt    *time.Time   `json:"exitInitiatedAt"`
+		ExitFinishedAt     *time.Time   `json:"exitFinishedAt"`
+	}
+
+	var output struct {
+		Nodes []Node `json:"nodes"`
+		// Next is the cursor of the next page, it's empty on the last page.
+		Next string `json:"next"`
+	}
+	output.Nodes = []Node{}
+	for _, node := range nodes {
+		output.Nodes = append(output.Nodes, Node(node))
+	}
+	if len(nodes) == limit {
+		output.Next = nodes[len(nodes)-1].ID.String()
+	}
+
+	server.writeJSON(w, output)
+}
+
+func (server *Server) getNode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	nodeID, ok := nodeVars(w, r)
+	if !ok {
+		return
+	}
+
+	node, err := server.db.OverlayCache().Get(ctx, nodeID)
+	if overlay.ErrNodeNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("node %q not found", nodeID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get node: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	type Reputation struct {
+		AuditSuccessCount           int64     `json:"auditSuccessCount"`
+		AuditCount                  int64     `json:"auditCount"`
+		UptimeSuccessCount          int64     `json:"uptimeSuccessCount"`
+		UptimeCount                 int64     `json:"uptimeCount"`
+		AuditReputationAlpha        float64   `json:"auditReputationAlpha"`
+		AuditReputationBeta         float64   `json:"auditReputationBeta"`
+		UnknownAuditReputationAlpha float64   `json:"unknownAuditReputationAlpha"`
+		UnknownAuditReputationBeta  float64   `json:"unknownAuditReputationBeta"`
+		LastContactSuccess          time.Time `json:"lastContactSuccess"`
+		LastContactFailure          time.Time `json:"lastContactFailure"`
+	}
+	type ExitStatus struct {
+		InitiatedAt     *time.Time `json:"initiatedAt"`
+		LoopCompletedAt *time.Time `json:"loopCompletedAt"`
+		FinishedAt      *time.Time `json:"finishedAt"`
+		Success         bool       `json:"success"`
+	}
+
+	var output struct {
+		ID           storj.NodeID `json:"id"`
+		Address      string       `json:"address"`
+		LastNet      string       `json:"lastNet"`
+		LastIPPort   string       `json:"lastIPPort"`
+		CountryCode  string       `json:"countryCode"`
+		Type         string       `json:"type"`
+		Email        string       `json:"email"`
+		Wallet       string       `json:"wallet"`
+		FreeDisk     int64        `json:"freeDisk"`
+		Version      string       `json:"version"`
+		Reputation   Reputation   `json:"reputation"`
+		Contained    bool         `json:"contained"`
+		Disqualified *time.Time   `json:"disqualified"`
+		Suspended    *time.Time   `json:"suspended"`
+		PieceCount   int64        `json:"pieceCount"`
+		ExitStatus   ExitStatus   `json:"exitStatus"`
+		CreatedAt    time.Time    `json:"createdAt"`
+	}
+
+	output.ID = node.Id
+	output.Address = node.Address.GetAddress()
+	output.LastNet = node.LastNet
+	output.LastIPPort = node.LastIPPort
+	output.CountryCode = node.CountryCode
+	output.Type = node.Type.String()
+	output.Email = node.Operator.Email
+	output.Wallet = node.Operator.Wallet
+	output.FreeDisk = node.Capacity.FreeDisk
+	output.Version = node.Version.Version
+	output.Reputation = Reputation{
+		AuditSuccessCount:           node.Reputation.AuditSuccessCount,
+		AuditCount:                  node.Reputation.AuditCount,
+		UptimeSuccessCount:          node.Reputation.UptimeSuccessCount,
+		UptimeCount:                 node.Reputation.UptimeCount,
+		AuditReputationAlpha:        node.Reputation.AuditReputationAlpha,
+		AuditReputationBeta:         node.Reputation.AuditReputationBeta,
+		UnknownAuditReputationAlpha: node.Reputation.UnknownAuditReputationAlpha,
+		UnknownAuditReputationBeta:  node.Reputation.UnknownAuditReputationBeta,
+		LastContactSuccess:          node.Reputation.LastContactSuccess,
+		LastContactFailure:          node.Reputation.LastContactFailure,
+	}
+	output.Contained = node.Contained
+	output.Disqualified = node.Disqualified
+	output.Suspended = node.Suspended
+	output.PieceCount = node.PieceCount
+	output.ExitStatus = ExitStatus{
+		InitiatedAt:     node.ExitStatus.ExitInitiatedAt,
+		LoopCompletedAt: node.ExitStatus.ExitLoopCompletedAt,
+		FinishedAt:      node.ExitStatus.ExitFinishedAt,
+		Success:         node.ExitStatus.ExitSuccess,
+	}
+	output.CreatedAt = node.CreatedAt
+
+	server.writeJSON(w, output)
+}
+
+func (server *Server) disqualifyNode(w http.ResponseWriter, r *http.Request) {
+	server.nodeAction(w, r, "node-disqualify", func(ctx context.Context, nodes NodeChanges, node *overlay.NodeDossier) error {
+		return nodes.DisqualifyNode(ctx, node.Id)
+	})
+}
+
+func (server *Server) reinstateNode(w http.ResponseWriter, r *http.Request) {
+	server.nodeAction(w, r, "node-reinstate", func(ctx context.Context, nodes NodeChanges, node *overlay.NodeDossier) error {
+		return nodes.ReinstateNode(ctx, node.Id)
+	})
+}
+
+func (server *Server) unsuspendNode(w http.ResponseWriter, r *http.Request) {
+	server.nodeAction(w, r, "node-unsuspend", func(ctx context.Context, nodes NodeChanges, node *overlay.NodeDossier) error {
+		return nodes.UnsuspendNode(ctx, node.Id)
+	})
+}
+
+func (server *Server) putNodeExitStatus(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	status := r.Form.Get("status")
+	switch status {
+	case "initiated", "loop-completed", "succeeded", "failed":
+	default:
+		http.Error(w, fmt.Sprintf("invalid exit status %q: expected initiated, loop-completed, succeeded or failed", status), http.StatusBadRequest)
+		return
+	}
+
+	server.nodeAction(w, r, "node-exit-"+status, func(ctx context.Context, nodes NodeChanges, node *overlay.NodeDossier) error {
+		now := time.Now().UTC()
+		request := &overlay.ExitStatusRequest{NodeID: node.Id}
+
+		// the earlier steps of the graceful exit are completed now, unless
+		// they are completed already
+		if node.ExitStatus.ExitInitiatedAt == nil {
+			request.ExitInitiatedAt = now
+		}
+		switch status {
+		case "loop-completed":
+			request.ExitLoopCompletedAt = now
+		case "succeeded", "failed":
+			if node.ExitStatus.ExitLoopCompletedAt == nil {
+				request.ExitLoopCompletedAt = now
+			}
+			request.ExitFinishedAt = now
+			request.ExitSuccess = status == "succeeded"
+		}
+
+		_, err := nodes.UpdateExitStatus(ctx, request)
+		return err
+	})
+}
+
+func (server *Server) listAuditLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var arguments struct {
+		Target string `schema:"target"`
+		Limit  int    `schema:"limit"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	limit := arguments.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	entries, err := server.db.AdminAuditLog().List(ctx, arguments.Target, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list audit log: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []AuditLogEntry{}
+	}
+
+	server.writeJSON(w, entries)
+}
+
+// nodeAction runs an action on the node of the request path and records it
+// in the audit log in the same transaction, with the authorized operator and
+// the reason from the request form.
+func (server *Server) nodeAction(w http.ResponseWriter, r *http.Request, action string, run func(ctx context.Context, nodes NodeChanges, node *overlay.NodeDossier) error) {
+	ctx := r.Context()
+
+	nodeID, ok := nodeVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	reason := r.Form.Get("reason")
+	if reason == "" {
+		http.Error(w, "reason missing", http.StatusBadRequest)
+		return
+	}
+
+	node, err := server.db.OverlayCache().Get(ctx, nodeID)
+	if overlay.ErrNodeNotFound.Has(err) {
+		http.Error(w, fmt.Sprintf("node %q not found", nodeID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get node: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	err = server.audited(ctx, action, nodeID.String(), reason, func(ctx context.Context, tx AuditLogTx) error {
+		return run(ctx, tx.Nodes(), node)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to %s: %v", action, err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeJSON writes output as the JSON response.
+func (server *Server) writeJSON(w http.ResponseWriter, output interface{}) {
+	data, err := json.Marshal(output)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("json encoding failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disapperaed
+}
+
+// nodeVars parses the node ID from the request path.
+// When it's invalid, it writes the error response and returns ok = false.
+func nodeVars(w http.ResponseWriter, r *http.Request) (nodeID storj.NodeID, ok bool) {
+	nodeIDString, ok := mux.Vars(r)["node"]
+	if !ok {
+		http.Error(w, "node-id missing", http.StatusBadRequest)
+		return storj.NodeID{}, false
+	}
+
+	nodeID, err := storj.NodeIDFromString(nodeIDString)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid node-id: %v", err), http.StatusBadRequest)
+		return storj.NodeID{}, false
+	}
+
+	return nodeID, true
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/admin"
+	"storj.io/storj/satellite/overlay"
+)
+
+func TestNodeManagement(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 2,
+		UplinkCount:      0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+				config.Admin.OperatorTokens = "alice:alice-token,bob:bob-token"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		address := "http://" + satellite.Admin.Admin.Listener.Addr().String()
+		node := planet.StorageNodes[0]
+
+		// listing
+		var list struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+			Next string `json:"next"`
+		}
+		getJSON(t, address+"/api/nodes?search="+node.ID().String(), &list)
+		require.Len(t, list.Nodes, 1)
+		require.Equal(t, node.ID().String(), list.Nodes[0].ID)
+		require.Empty(t, list.Next)
+
+		getJSON(t, address+"/api/nodes?limit=1", &list)
+		require.Len(t, list.Nodes, 1)
+		require.Equal(t, list.Nodes[0].ID, list.Next)
+
+		getJSON(t, address+"/api/nodes?limit=1&cursor="+list.Next, &list)
+		require.Len(t, list.Nodes, 1)
+		require.NotEqual(t, list.Next, list.Nodes[0].ID)
+
+		// actions require the reason
+		link := address + "/api/node/" + node.ID().String()
+		status := postAs(t, link+"/disqualify", "alice-token", nil)
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status = postAs(t, link+"/disqualify", "wrong-token", url.Values{"reason": {"lost its data"}})
+		require.Equal(t, http.StatusForbidden, status)
+
+		// the operator form field doesn't change the recorded operator
+		status = postAs(t, link+"/disqualify", "alice-token", url.Values{"operator": {"mallory"}, "reason": {"lost its data"}})
+		require.Equal(t, http.StatusOK, status)
+
+		dossier, err := satellite.Overlay.DB.Get(ctx, node.ID())
+		require.NoError(t, err)
+		require.NotNil(t, dossier.Disqualified)
+
+		var details struct {
+			Disqualified *string `json:"disqualified"`
+			Reputation   struct {
+				AuditReputationAlpha float64 `json:"auditReputationAlpha"`
+			} `json:"reputation"`
+		}
+		getJSON(t, link, &details)
+		require.NotNil(t, details.Disqualified)
+		require.Equal(t, dossier.Reputation.AuditReputationAlpha, details.Reputation.AuditReputationAlpha)
+
+		// the reinstatement resets the reputation lowered by the failed audits,
+		// so that the next failed audit doesn't disqualify the node again
+		_, err = satellite.Overlay.DB.UpdateStats(ctx, &overlay.UpdateRequest{
+			NodeID:       node.ID(),
+			AuditOutcome: overlay.AuditFailure,
+			AuditLambda:  1,
+			AuditWeight:  1,
+			AuditDQ:      0,
+		})
+		require.NoError(t, err)
+
+		status = postAs(t, link+"/reinstate", "bob-token", url.Values{"reason": {"data was restored"}})
+		require.Equal(t, http.StatusOK, status)
+
+		dossier, err = satellite.Overlay.DB.Get(ctx, node.ID())
+		require.NoError(t, err)
+		require.Nil(t, dossier.Disqualified)
+		require.EqualValues(t, 1, dossier.Reputation.AuditReputationAlpha)
+		require.EqualValues(t, 0, dossier.Reputation.AuditReputationBeta)
+
+		status = postAs(t, link+"/exit", "bob-token", url.Values{"reason": {"exit got stuck"}, "status": {"sometimes"}})
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status = postAs(t, link+"/exit", "bob-token", url.Values{"reason": {"exit got stuck"}, "status": {"succeeded"}})
+		require.Equal(t, http.StatusOK, status)
+
+		exitStatus, err := satellite.Overlay.DB.GetExitStatus(ctx, node.ID())
+		require.NoError(t, err)
+		require.NotNil(t, exitStatus.ExitInitiatedAt)
+		require.NotNil(t, exitStatus.ExitLoopCompletedAt)
+		require.NotNil(t, exitStatus.ExitFinishedAt)
+		require.True(t, exitStatus.ExitSuccess)
+
+		status = post(t, address+"/api/node/"+planet.StorageNodes[1].ID().String()+"/unsuspend", url.Values{"reason": {"false alarm"}})
+		require.Equal(t, http.StatusOK, status)
+
+		// audit log
+		var entries []admin.AuditLogEntry
+		getJSON(t, address+"/api/auditlog?target="+node.ID().String(), &entries)
+		require.Len(t, entries, 3)
+		require.Equal(t, "node-exit-succeeded", entries[0].Action)
+		require.Equal(t, "node-reinstate", entries[1].Action)
+		require.Equal(t, "bob", entries[1].Operator)
+		require.Equal(t, "node-disqualify", entries[2].Action)
+		require.Equal(t, "alice", entries[2].Operator)
+		require.Equal(t, "lost its data", entries[2].Reason)
+
+		getJSON(t, address+"/api/auditlog", &entries)
+		require.Len(t, entries, 4)
+		require.Equal(t, "node-unsuspend", entries[0].Action)
+		require.Equal(t, "admin", entries[0].Operator)
+	})
+}
+
+func getJSON(t *testing.T, link string, output interface{}) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "very-secret-token")
+
+	response, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+
+	require.Equal(t, http.StatusOK, response.StatusCode, string(data))
+	require.NoError(t, json.Unmarshal(data, output))
+}
+
+func post(t *testing.T, link string, form url.Values) int {
+	t.Helper()
+	return postAs(t, link, "very-secret-token", form)
+}
+
+func postAs(t *testing.T, link, token string, form url.Values) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, link+"?"+form.Encode(), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", token)
+
+	response, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+
+	return response.StatusCode
+}
@@ -4,6 +4,7 @@
 package admin
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -13,7 +14,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
 
+	"storj.io/common/macaroon"
 	"storj.io/common/memory"
+	"storj.io/common/storj"
 	"storj.io/common/uuid"
 )
 
@@ -197,3 +200,146 @@ func (server *Server) putProjectLimit(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+func (server *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, ok := projectVars(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := server.db.Console().Projects().Get(ctx, projectUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("project %q not found", projectUUID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get project: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	buckets, err := server.db.Buckets().ListBuckets(ctx, projectUUID, storj.BucketListOptions{
+		Direction: storj.Forward,
+		Limit:     1,
+	}, macaroon.AllowedBuckets{All: true})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list buckets: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if len(buckets.Items) > 0 {
+		http.Error(w, "buckets still exist", http.StatusConflict)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// the API keys and the members of the project are deleted with it in the
+	// same transaction, a bucket created in the meantime fails the deletion
+	err = server.audited(ctx, "project-delete", projectUUID.String(), r.Form.Get("reason"), func(ctx context.Context, tx AuditLogTx) error {
+		return tx.Console().Projects().Delete(ctx, projectUUID)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete project: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) putProjectOwner(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projectUUID, ok := projectVars(w, r)
+	if !ok {
+		return
+	}
+
+	var arguments struct {
+		Email  string `schema:"email"`
+		Reason string `schema:"reason"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+	if arguments.Email == "" {
+		http.Error(w, "email missing", http.StatusBadRequest)
+		return
+	}
+
+	_, err = server.db.Console().Projects().Get(ctx, projectUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("project %q not found", projectUUID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get project: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	user, err := server.db.Console().Users().GetByEmail(ctx, arguments.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("user with email %q not found", arguments.Email), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get user %q: %v", arguments.Email, err), http.StatusInternalServerError)
+		return
+	}
+
+	err = server.audited(ctx, "project-owner", projectUUID.String(), arguments.Reason, func(ctx context.Context, tx AuditLogTx) error {
+		memberships, err := tx.Console().ProjectMembers().GetByMemberID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		isMember := false
+		for _, membership := range memberships {
+			if membership.ProjectID == projectUUID {
+				isMember = true
+				break
+			}
+		}
+
+		if !isMember {
+			_, err = tx.Console().ProjectMembers().Insert(ctx, user.ID, projectUUID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Console().Projects().UpdateOwner(ctx, projectUUID, user.ID)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update owner: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// projectVars parses the project ID from the request path.
+// When it's invalid, it writes the error response and returns ok = false.
+func projectVars(w http.ResponseWriter, r *http.Request) (projectUUID uuid.UUID, ok bool) {
+	projectUUIDString, ok := mux.Vars(r)["project"]
+	if !ok {
+		http.Error(w, "project-uuid missing", http.StatusBadRequest)
+		return uuid.UUID{}, false
+	}
+
+	projectUUID, err := uuid.FromString(projectUUIDString)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid project-uuid: %v", err), http.StatusBadRequest)
+		return uuid.UUID{}, false
+	}
+
+	return projectUUID, true
+}
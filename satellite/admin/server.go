@@ -10,6 +10,7 @@ import (
 	"errors"
 	"net"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -18,21 +19,34 @@ import (
 	"storj.io/common/errs2"
 	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
 )
 
 // Config defines configuration for debug server.
 type Config struct {
-	Address string `help:"admin peer http listening address" releaseDefault:"" devDefault:""`
+	Address        string `help:"admin peer http listening address" releaseDefault:"" devDefault:""`
+	OperatorTokens string `help:"comma separated name:token pairs of the operators, whose name is recorded in the audit log for the actions authorized with their token" default:""`
 
 	AuthorizationToken string `internal:"true"`
 }
 
+// sharedOperator is the operator recorded in the audit log for the actions
+// authorized with the shared authorization token.
+const sharedOperator = "admin"
+
 // DB is databases needed for the admin server.
 type DB interface {
 	// ProjectAccounting returns database for storing information about project data use
 	ProjectAccounting() accounting.ProjectAccounting
 	// Console returns database for satellite console
 	Console() console.DB
+	// Buckets returns database for buckets metainfo
+	Buckets() metainfo.BucketsDB
+	// OverlayCache returns database for caching overlay information
+	OverlayCache() overlay.DB
+	// AdminAuditLog returns the log of the actions taken through the admin API
+	AdminAuditLog() AuditLog
 }
 
 // Server provides endpoints for debugging.
@@ -47,7 +61,15 @@ type Server struct {
 }
 
 // NewServer returns a new debug.Server.
-func NewServer(log *zap.Logger, listener net.Listener, db DB, config Config) *Server {
+func NewServer(log *zap.Logger, listener net.Listener, db DB, config Config) (*Server, error) {
+	operators, err := parseOperatorTokens(config.OperatorTokens)
+	if err != nil {
+		return nil, err
+	}
+	if config.AuthorizationToken != "" {
+		operators[config.AuthorizationToken] = sharedOperator
+	}
+
 	server := &Server{
 		log: log,
 	}
@@ -56,42 +78,108 @@ func NewServer(log *zap.Logger, listener net.Listener, db DB, config Config) *Se
 	server.listener = listener
 	server.mux = mux.NewRouter()
 	server.server.Handler = &protectedServer{
-		allowedAuthorization: config.AuthorizationToken,
-		next:                 server.mux,
+		operators: operators,
+		next:      server.mux,
 	}
 
 	// When adding new options, also update README.md
+	server.mux.HandleFunc("/api/user", server.createUser).Methods("POST")
 	server.mux.HandleFunc("/api/user/{useremail}", server.userInfo).Methods("GET")
+	server.mux.HandleFunc("/api/user/{useremail}", server.updateUser).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/user/{useremail}/freeze", server.freezeUser).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/user/{useremail}/unfreeze", server.unfreezeUser).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}", server.deleteProject).Methods("DELETE")
+	server.mux.HandleFunc("/api/project/{project}/owner", server.putProjectOwner).Methods("PUT", "POST")
 	server.mux.HandleFunc("/api/project/{project}/limit", server.getProjectLimit).Methods("GET")
 	server.mux.HandleFunc("/api/project/{project}/limit", server.putProjectLimit).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/placement", server.getBucketPlacement).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/placement", server.putBucketPlacement).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/versioning", server.getBucketVersioning).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/versioning", server.putBucketVersioning).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/limit", server.getBucketLimit).Methods("GET")
+	server.mux.HandleFunc("/api/project/{project}/bucket/{bucket}/limit", server.putBucketLimit).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/nodes", server.listNodes).Methods("GET")
+	server.mux.HandleFunc("/api/node/{node}", server.getNode).Methods("GET")
+	server.mux.HandleFunc("/api/node/{node}/disqualify", server.disqualifyNode).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/node/{node}/reinstate", server.reinstateNode).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/node/{node}/unsuspend", server.unsuspendNode).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/node/{node}/exit", server.putNodeExitStatus).Methods("PUT", "POST")
+	server.mux.HandleFunc("/api/auditlog", server.listAuditLog).Methods("GET")
+
+	return server, nil
+}
 
-	return server
+// parseOperatorTokens parses the operator tokens in the format
+// "name:token,name:token" to a map of the tokens to the names.
+func parseOperatorTokens(s string) (map[string]string, error) {
+	operators := make(map[string]string)
+	for i, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// the errors don't include the entries, which contain the tokens
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, Error.New("invalid operator token %d: expected name:token", i+1)
+		}
+		if _, ok := operators[parts[1]]; ok {
+			return nil, Error.New("duplicate operator token of %q", parts[0])
+		}
+		operators[parts[1]] = parts[0]
+	}
+	return operators, nil
 }
 
 type protectedServer struct {
-	allowedAuthorization string
+	// operators maps the authorization tokens to the names of the operators.
+	operators map[string]string
 
 	next http.Handler
 }
 
 func (server *protectedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if server.allowedAuthorization == "" {
+	if len(server.operators) == 0 {
 		http.Error(w, "Authorization not enabled.", http.StatusForbidden)
 		return
 	}
 
-	equality := subtle.ConstantTimeCompare(
-		[]byte(r.Header.Get("Authorization")),
-		[]byte(server.allowedAuthorization),
-	)
-	if equality != 1 {
+	operator, ok := server.authorize(r.Header.Get("Authorization"))
+	if !ok {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
 	r.Header.Set("Cache-Control", "must-revalidate")
 
-	server.next.ServeHTTP(w, r)
+	server.next.ServeHTTP(w, r.WithContext(withOperator(r.Context(), operator)))
+}
+
+// authorize returns the name of the operator with the authorization token.
+// Every token is compared, so that the time doesn't depend on which one
+// matches.
+func (server *protectedServer) authorize(authorization string) (operator string, ok bool) {
+	for token, name := range server.operators {
+		if subtle.ConstantTimeCompare([]byte(authorization), []byte(token)) == 1 {
+			operator, ok = name, true
+		}
+	}
+	return operator, ok
+}
+
+type operatorKey struct{}
+
+// withOperator returns a context with the name of the authorized operator.
+func withOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// operatorFromContext returns the name of the operator authorized for the
+// request.
+func operatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
 }
 
 // Run starts the debug endpoint.
@@ -9,10 +9,12 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
 
 	"storj.io/common/testcontext"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/admin"
 )
 
 func TestBasic(t *testing.T) {
@@ -63,3 +65,13 @@ func TestBasic(t *testing.T) {
 		})
 	})
 }
+
+func TestInvalidOperatorTokens(t *testing.T) {
+	for _, tokens := range []string{"alice", "alice:", ":token", "alice:token,bob:token"} {
+		_, err := admin.NewServer(zaptest.NewLogger(t), nil, nil, admin.Config{OperatorTokens: tokens})
+		require.Error(t, err, tokens)
+	}
+
+	_, err := admin.NewServer(zaptest.NewLogger(t), nil, nil, admin.Config{OperatorTokens: "alice:alice-token, bob:bob-token"})
+	require.NoError(t, err)
+}
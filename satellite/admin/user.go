@@ -0,0 +1,239 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"golang.org/x/crypto/bcrypt"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/console"
+)
+
+func (server *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var arguments struct {
+		Email     string `schema:"email"`
+		FullName  string `schema:"fullName"`
+		ShortName string `schema:"shortName"`
+		Password  string `schema:"password"`
+		Reason    string `schema:"reason"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	request := console.CreateUser{
+		FullName:  arguments.FullName,
+		ShortName: arguments.ShortName,
+		Email:     arguments.Email,
+		Password:  arguments.Password,
+	}
+	if err := request.IsValid(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid user: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	_, err = server.db.Console().Users().GetByEmail(ctx, request.Email)
+	if err == nil {
+		http.Error(w, fmt.Sprintf("user with email %q already exists", request.Email), http.StatusConflict)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("failed to check user %q: %v", request.Email, err), http.StatusInternalServerError)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to hash password: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	userID, err := uuid.New()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to generate user id: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var user *console.User
+	err = server.audited(ctx, "user-create", userID.String(), arguments.Reason, func(ctx context.Context, tx AuditLogTx) error {
+		users := tx.Console().Users()
+
+		inserted, err := users.Insert(ctx, &console.User{
+			ID:           userID,
+			FullName:     request.FullName,
+			ShortName:    request.ShortName,
+			Email:        request.Email,
+			PasswordHash: hash,
+		})
+		if err != nil {
+			return err
+		}
+
+		// users are inserted as inactive, the admin created ones don't need
+		// to confirm their email
+		inserted.Status = console.Active
+		if err := users.Update(ctx, inserted); err != nil {
+			return err
+		}
+
+		user = inserted
+		return nil
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create user: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var output struct {
+		ID       uuid.UUID `json:"id"`
+		FullName string    `json:"fullName"`
+		Email    string    `json:"email"`
+	}
+	output.ID = user.ID
+	output.FullName = user.FullName
+	output.Email = user.Email
+
+	server.writeJSON(w, output)
+}
+
+func (server *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := server.userVars(w, r)
+	if !ok {
+		return
+	}
+
+	var arguments struct {
+		Email     *string `schema:"email"`
+		FullName  *string `schema:"fullName"`
+		ShortName *string `schema:"shortName"`
+		Reason    string  `schema:"reason"`
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	decoder := schema.NewDecoder()
+	err := decoder.Decode(&arguments, r.Form)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid arguments: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if arguments.FullName != nil {
+		if err := console.ValidateFullName(*arguments.FullName); err != nil {
+			http.Error(w, fmt.Sprintf("invalid full name: %v", err), http.StatusBadRequest)
+			return
+		}
+		user.FullName = *arguments.FullName
+	}
+
+	if arguments.ShortName != nil {
+		user.ShortName = *arguments.ShortName
+	}
+
+	if arguments.Email != nil && *arguments.Email != user.Email {
+		if _, err := mail.ParseAddress(*arguments.Email); err != nil {
+			http.Error(w, fmt.Sprintf("invalid email: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		_, err := server.db.Console().Users().GetByEmail(ctx, *arguments.Email)
+		if err == nil {
+			http.Error(w, fmt.Sprintf("user with email %q already exists", *arguments.Email), http.StatusConflict)
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, fmt.Sprintf("failed to check user %q: %v", *arguments.Email, err), http.StatusInternalServerError)
+			return
+		}
+
+		user.Email = *arguments.Email
+	}
+
+	err = server.audited(ctx, "user-update", user.ID.String(), arguments.Reason, func(ctx context.Context, tx AuditLogTx) error {
+		return tx.Console().Users().Update(ctx, user)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update user: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) freezeUser(w http.ResponseWriter, r *http.Request) {
+	server.setUserStatus(w, r, "user-freeze", console.Frozen)
+}
+
+func (server *Server) unfreezeUser(w http.ResponseWriter, r *http.Request) {
+	server.setUserStatus(w, r, "user-unfreeze", console.Active)
+}
+
+func (server *Server) setUserStatus(w http.ResponseWriter, r *http.Request, action string, status console.UserStatus) {
+	ctx := r.Context()
+
+	user, ok := server.userVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	user.Status = status
+	err := server.audited(ctx, action, user.ID.String(), r.Form.Get("reason"), func(ctx context.Context, tx AuditLogTx) error {
+		return tx.Console().Users().Update(ctx, user)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update user status: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// userVars gets the user whose email is in the request path.
+// When it's missing or it doesn't exist, it writes the error response and
+// returns ok = false.
+func (server *Server) userVars(w http.ResponseWriter, r *http.Request) (user *console.User, ok bool) {
+	userEmail, ok := mux.Vars(r)["useremail"]
+	if !ok {
+		http.Error(w, "user-email missing", http.StatusBadRequest)
+		return nil, false
+	}
+
+	user, err := server.db.Console().Users().GetByEmail(r.Context(), userEmail)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("user with email %q not found", userEmail), http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get user %q: %v", userEmail, err), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return user, true
+}
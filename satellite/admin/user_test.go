@@ -0,0 +1,156 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/admin"
+	"storj.io/storj/satellite/console"
+)
+
+func TestUserManagement(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+				config.Metainfo.OwnerStatus.CacheCapacity = 0
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := "http://" + sat.Admin.Admin.Listener.Addr().String()
+		uplink := planet.Uplinks[0]
+		project := uplink.Projects[0]
+		users := sat.DB.Console().Users()
+
+		// create
+		status := post(t, address+"/api/user", url.Values{
+			"email":    {"bob@example.test"},
+			"fullName": {"Bob"},
+			"password": {"123a123"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		bob, err := users.GetByEmail(ctx, "bob@example.test")
+		require.NoError(t, err)
+		require.Equal(t, console.Active, bob.Status)
+
+		status = post(t, address+"/api/user", url.Values{
+			"email":    {"bob@example.test"},
+			"fullName": {"Bob"},
+			"password": {"123a123"},
+		})
+		require.Equal(t, http.StatusConflict, status)
+
+		// update
+		status = post(t, address+"/api/user/bob@example.test", url.Values{
+			"email":     {"robert@example.test"},
+			"shortName": {"Rob"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		bob, err = users.Get(ctx, bob.ID)
+		require.NoError(t, err)
+		require.Equal(t, "robert@example.test", bob.Email)
+		require.Equal(t, "Bob", bob.FullName)
+		require.Equal(t, "Rob", bob.ShortName)
+
+		status = post(t, address+"/api/user/robert@example.test", url.Values{
+			"email": {project.Owner.Email},
+		})
+		require.Equal(t, http.StatusConflict, status)
+
+		// freeze
+		require.NoError(t, uplink.CreateBucket(ctx, sat, "before"))
+
+		status = post(t, address+"/api/user/"+project.Owner.Email+"/freeze", url.Values{"reason": {"unpaid invoices"}})
+		require.Equal(t, http.StatusOK, status)
+
+		owner, err := users.Get(ctx, project.Owner.ID)
+		require.NoError(t, err)
+		require.Equal(t, console.Frozen, owner.Status)
+		require.Error(t, uplink.CreateBucket(ctx, sat, "frozen"))
+
+		status = post(t, address+"/api/user/"+project.Owner.Email+"/unfreeze", nil)
+		require.Equal(t, http.StatusOK, status)
+		require.NoError(t, uplink.CreateBucket(ctx, sat, "unfrozen"))
+
+		// transfer ownership
+		status = post(t, address+"/api/project/"+project.ID.String()+"/owner", url.Values{
+			"email": {"robert@example.test"},
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		updated, err := sat.DB.Console().Projects().Get(ctx, project.ID)
+		require.NoError(t, err)
+		require.Equal(t, bob.ID, updated.OwnerID)
+
+		memberships, err := sat.DB.Console().ProjectMembers().GetByMemberID(ctx, bob.ID)
+		require.NoError(t, err)
+		require.Len(t, memberships, 1)
+		require.Equal(t, project.ID, memberships[0].ProjectID)
+
+		// delete project
+		projectLink := address + "/api/project/" + project.ID.String()
+		require.Equal(t, http.StatusConflict, deleteRequest(t, projectLink))
+
+		require.NoError(t, uplink.DeleteBucket(ctx, sat, "before"))
+		require.NoError(t, uplink.DeleteBucket(ctx, sat, "unfrozen"))
+		require.Equal(t, http.StatusOK, deleteRequest(t, projectLink))
+
+		keys, err := sat.DB.Console().APIKeys().GetPagedByProjectID(ctx, project.ID, console.APIKeyCursor{Limit: 50, Page: 1})
+		require.NoError(t, err)
+		require.Empty(t, keys.APIKeys)
+
+		_, err = sat.DB.Console().Projects().Get(ctx, project.ID)
+		require.Error(t, err)
+
+		require.Equal(t, http.StatusNotFound, deleteRequest(t, projectLink))
+
+		// the actions are recorded in the audit log
+		var entries []admin.AuditLogEntry
+		getJSON(t, address+"/api/auditlog?target="+bob.ID.String(), &entries)
+		require.Len(t, entries, 2)
+		require.Equal(t, "user-update", entries[0].Action)
+		require.Equal(t, "user-create", entries[1].Action)
+
+		getJSON(t, address+"/api/auditlog?target="+project.Owner.ID.String(), &entries)
+		require.Len(t, entries, 2)
+		require.Equal(t, "user-unfreeze", entries[0].Action)
+		require.Equal(t, "user-freeze", entries[1].Action)
+		require.Equal(t, "unpaid invoices", entries[1].Reason)
+		require.Equal(t, "admin", entries[1].Operator)
+
+		getJSON(t, address+"/api/auditlog?target="+project.ID.String(), &entries)
+		require.Len(t, entries, 2)
+		require.Equal(t, "project-delete", entries[0].Action)
+		require.Equal(t, "project-owner", entries[1].Action)
+	})
+}
+
+func deleteRequest(t *testing.T, link string) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodDelete, link, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "very-secret-token")
+
+	response, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+
+	return response.StatusCode
+}
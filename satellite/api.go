@@ -25,12 +25,16 @@ import (
 	"storj.io/common/storj"
 	"storj.io/private/debug"
 	"storj.io/private/version"
+	"storj.io/storj/pkg/gracefulexitpb"
+	"storj.io/storj/pkg/inspectorpb"
+	"storj.io/storj/pkg/metainfopb"
 	"storj.io/storj/pkg/server"
 	"storj.io/storj/private/lifecycle"
 	"storj.io/storj/private/post"
 	"storj.io/storj/private/post/oauth2"
 	"storj.io/storj/private/version/checker"
 	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleauth"
 	"storj.io/storj/satellite/console/consoleweb"
@@ -249,7 +253,10 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 	{ // setup overlay
 		peer.Overlay.DB = peer.DB.OverlayCache()
 
-		peer.Overlay.Service = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		peer.Overlay.Service, err = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Services.Add(lifecycle.Item{
 			Name:  "overlay",
 			Close: peer.Overlay.Service.Close,
@@ -410,11 +417,15 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Orders.Service,
 			peer.Overlay.Service,
 			peer.DB.Attribution(),
+			peer.DB.PieceReferences(),
 			peer.Marketing.PartnersService,
 			peer.DB.PeerIdentities(),
 			peer.DB.Console().APIKeys(),
+			peer.DB.Console().APIKeyRevocations(),
+			peer.DB.BucketEvents(),
 			peer.Accounting.ProjectUsage,
 			peer.DB.Console().Projects(),
+			peer.DB.Console().Users(),
 			signing.SignerFromFullIdentity(peer.Identity),
 			config.Metainfo,
 		)
@@ -425,6 +436,15 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 		if err := pb.DRPCRegisterMetainfo(peer.Server.DRPC(), peer.Metainfo.Endpoint2); err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+		if err := metainfopb.DRPCRegisterObjectVersions(peer.Server.DRPC(), peer.Metainfo.Endpoint2); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		if err := metainfopb.DRPCRegisterObjectCopy(peer.Server.DRPC(), peer.Metainfo.Endpoint2); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		if err := metainfopb.DRPCRegisterAPIKeyRevocation(peer.Server.DRPC(), peer.Metainfo.Endpoint2); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 
 		peer.Services.Add(lifecycle.Item{
 			Name:  "metainfo:endpoint",
@@ -444,10 +464,14 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Log.Named("inspector"),
 			peer.Overlay.Service,
 			peer.Metainfo.Service,
+			peer.DB.RepairQueue(),
 		)
 		if err := pb.DRPCRegisterHealthInspector(peer.Server.PrivateDRPC(), peer.Inspector.Endpoint); err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+		if err := inspectorpb.DRPCRegisterRepairHistoryInspector(peer.Server.PrivateDRPC(), peer.Inspector.Endpoint); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 	}
 
 	{ // setup mailservice
@@ -595,6 +619,7 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.DB.Rewards(),
 			peer.Marketing.PartnersService,
 			peer.Payments.Accounts,
+			bucketevents.NewAddressPolicy(config.BucketEvents, config.Admin.Address),
 			consoleConfig.Config,
 			config.Payments.MinCoinPayment,
 		)
@@ -662,6 +687,12 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			if err := pb.DRPCRegisterSatelliteGracefulExit(peer.Server.DRPC(), peer.GracefulExit.Endpoint); err != nil {
 				return nil, errs.Combine(err, peer.Close())
 			}
+			if err := gracefulexitpb.DRPCRegisterGracefulExitCancel(peer.Server.DRPC(), peer.GracefulExit.Endpoint); err != nil {
+				return nil, errs.Combine(err, peer.Close())
+			}
+			if err := gracefulexitpb.DRPCRegisterGracefulExitShrink(peer.Server.DRPC(), peer.GracefulExit.Endpoint); err != nil {
+				return nil, errs.Combine(err, peer.Close())
+			}
 		} else {
 			peer.Log.Named("gracefulexit").Info("disabled")
 		}
@@ -0,0 +1,151 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketevents
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"syscall"
+)
+
+// privateNetworks are the networks which aren't reachable from the internet,
+// besides the loopback, link-local and unspecified addresses.
+var privateNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"fc00::/7",
+)
+
+// AddressPolicy decides which addresses the bucket events may be delivered
+// to. The addresses are checked after resolving the host of the webhook URL,
+// so that a public host name can't point to an internal address.
+type AddressPolicy struct {
+	// AllowPrivate allows the loopback, link-local and private network
+	// addresses, it's meant for testing only.
+	AllowPrivate bool
+	// DeniedPorts are denied on every address, they're the ports of the
+	// internal servers of the satellite.
+	DeniedPorts []int
+}
+
+// NewAddressPolicy returns the policy of config, denying the ports of the
+// internal addresses of the satellite, such as the admin address.
+func NewAddressPolicy(config Config, internalAddresses ...string) AddressPolicy {
+	policy := AddressPolicy{AllowPrivate: config.AllowPrivateAddresses}
+	for _, address := range internalAddresses {
+		_, portString, err := net.SplitHostPort(address)
+		if err != nil {
+			continue
+		}
+		port, err := strconv.Atoi(portString)
+		if err != nil || port == 0 {
+			continue
+		}
+		policy.DeniedPorts = append(policy.DeniedPorts, port)
+	}
+	return policy
+}
+
+// Check returns an error when the events must not be delivered to ip and port.
+func (policy AddressPolicy) Check(ip net.IP, port int) error {
+	for _, denied := range policy.DeniedPorts {
+		if port == denied {
+			return Error.New("port %d is not allowed", port)
+		}
+	}
+	if policy.AllowPrivate {
+		return nil
+	}
+
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return Error.New("address %s is not public", ip)
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return Error.New("address %s is not public", ip)
+		}
+	}
+	return nil
+}
+
+// CheckURL resolves the host of the webhook URL and returns an error when
+// any of its addresses must not receive the events.
+func (policy AddressPolicy) CheckURL(ctx context.Context, webhookURL *url.URL) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	port, err := urlPort(webhookURL)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, webhookURL.Hostname())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if len(addresses) == 0 {
+		return Error.New("host %q has no addresses", webhookURL.Hostname())
+	}
+	for _, address := range addresses {
+		if err := policy.Check(address.IP, port); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// control checks the resolved address before a connection is made to it.
+func (policy AddressPolicy) control(network, address string, _ syscall.RawConn) error {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return Error.New("invalid address %q", address)
+	}
+	port, err := strconv.Atoi(portString)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return policy.Check(ip, port)
+}
+
+// urlPort returns the port of the webhook URL, the default port of its scheme
+// when it doesn't have one.
+func urlPort(webhookURL *url.URL) (int, error) {
+	if portString := webhookURL.Port(); portString != "" {
+		port, err := strconv.Atoi(portString)
+		if err != nil {
+			return 0, Error.Wrap(err)
+		}
+		return port, nil
+	}
+	switch webhookURL.Scheme {
+	case "http":
+		return 80, nil
+	case "https":
+		return 443, nil
+	default:
+		return 0, Error.New("unsupported scheme %q", webhookURL.Scheme)
+	}
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketevents_test
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/satellite/bucketevents"
+)
+
+func TestAddressPolicy(t *testing.T) {
+	policy := bucketevents.NewAddressPolicy(bucketevents.Config{}, "127.0.0.1:10005", "", "invalid")
+	require.Equal(t, []int{10005}, policy.DeniedPorts)
+
+	for _, tt := range []struct {
+		ip      string
+		port    int
+		allowed bool
+	}{
+		{"8.8.8.8", 443, true},
+		{"2001:4860:4860::8888", 80, true},
+		{"8.8.8.8", 10005, false},
+		{"127.0.0.1", 80, false},
+		{"::1", 80, false},
+		{"10.1.2.3", 80, false},
+		{"172.16.0.1", 80, false},
+		{"192.168.1.1", 80, false},
+		{"169.254.169.254", 80, false},
+		{"::ffff:169.254.169.254", 80, false},
+		{"fd00::1", 80, false},
+		{"0.0.0.0", 80, false},
+	} {
+		err := policy.Check(net.ParseIP(tt.ip), tt.port)
+		if tt.allowed {
+			require.NoError(t, err, tt.ip)
+		} else {
+			require.Error(t, err, tt.ip)
+		}
+	}
+
+	allowPrivate := bucketevents.NewAddressPolicy(bucketevents.Config{AllowPrivateAddresses: true}, ":10005")
+	require.NoError(t, allowPrivate.Check(net.ParseIP("127.0.0.1"), 80))
+	require.Error(t, allowPrivate.Check(net.ParseIP("127.0.0.1"), 10005))
+}
+
+func TestAddressPolicyCheckURL(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	policy := bucketevents.AddressPolicy{}
+	for _, rawurl := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"https://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+	} {
+		parsed, err := url.Parse(rawurl)
+		require.NoError(t, err)
+		require.Error(t, policy.CheckURL(ctx, parsed), rawurl)
+	}
+
+	parsed, err := url.Parse("http://8.8.8.8/hook")
+	require.NoError(t, err)
+	require.NoError(t, policy.CheckURL(ctx, parsed))
+}
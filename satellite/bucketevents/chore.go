@@ -0,0 +1,295 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/sync2"
+)
+
+const (
+	// SignatureHeader is the header containing the signature of the request body.
+	SignatureHeader = "X-Storj-Signature"
+	// DeliveryHeader is the header containing the id of the delivery, it's the
+	// same for every attempt of a delivery.
+	DeliveryHeader = "X-Storj-Delivery"
+)
+
+// Config contains configurable values for the bucket events delivery.
+type Config struct {
+	Interval              time.Duration `help:"how often to deliver the pending bucket events" releaseDefault:"30s" devDefault:"1s"`
+	BatchSize             int           `help:"how many bucket events to deliver in a single cycle" default:"100"`
+	Concurrency           int           `help:"how many bucket events to deliver concurrently" default:"10"`
+	EndpointConcurrency   int           `help:"how many bucket events to deliver concurrently to the same webhook host" default:"2"`
+	Timeout               time.Duration `help:"timeout for a single bucket event delivery" default:"10s"`
+	MaxAttempts           int           `help:"how many times to attempt a bucket event delivery before dropping it" default:"10"`
+	InitialBackoff        time.Duration `help:"how long to wait before retrying a failed bucket event delivery" releaseDefault:"1m" devDefault:"1s"`
+	MaxBackoff            time.Duration `help:"the maximum time to wait before retrying a failed bucket event delivery" default:"6h"`
+	AllowPrivateAddresses bool          `help:"allow delivering the bucket events to loopback, link-local and private network addresses, for testing only" releaseDefault:"false" devDefault:"true"`
+}
+
+// Chore delivers the pending bucket events to the subscribers as signed
+// HTTP POST requests.
+//
+// architecture: Chore
+type Chore struct {
+	log     *zap.Logger
+	db      DB
+	objects Objects
+	config  Config
+	client  *http.Client
+
+	Loop *sync2.Cycle
+}
+
+// NewChore creates a new bucket events delivery chore. The events are only
+// delivered to the addresses allowed by policy.
+func NewChore(log *zap.Logger, db DB, objects Objects, policy AddressPolicy, config Config) *Chore {
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		Control: policy.control,
+	}
+	transport := &http.Transport{
+		// a proxy would make the connections to the checked addresses
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		MaxIdleConnsPerHost:   config.EndpointConcurrency,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   config.Timeout,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &Chore{
+		log:     log,
+		db:      db,
+		objects: objects,
+		config:  config,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   config.Timeout,
+			// a redirect is a failed delivery, it could point anywhere
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+
+		Loop: sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run starts the bucket events delivery chore.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return chore.Loop.Run(ctx, func(ctx context.Context) error {
+		err := chore.DeliverPending(ctx)
+		if err != nil {
+			chore.log.Error("failed to deliver bucket events", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// DeliverPending delivers the events that are due.
+//
+// The events are delivered concurrently, with at most EndpointConcurrency
+// deliveries to the same webhook host, so that a slow subscriber doesn't
+// delay the deliveries to the others.
+func (chore *Chore) DeliverPending(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	deliveries, err := chore.db.ListPending(ctx, now, chore.config.BatchSize)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	var endpoints []string
+	byEndpoint := make(map[string][]Delivery)
+	for _, delivery := range deliveries {
+		endpoint := endpointOf(delivery.URL)
+		if _, ok := byEndpoint[endpoint]; !ok {
+			endpoints = append(endpoints, endpoint)
+		}
+		byEndpoint[endpoint] = append(byEndpoint[endpoint], delivery)
+	}
+
+	var mu sync.Mutex
+	var group errs.Group
+
+	limiter := sync2.NewLimiter(chore.config.Concurrency)
+	for _, endpoint := range endpoints {
+		queue := make(chan Delivery, len(byEndpoint[endpoint]))
+		for _, delivery := range byEndpoint[endpoint] {
+			queue <- delivery
+		}
+		close(queue)
+
+		workers := chore.config.EndpointConcurrency
+		if workers <= 0 || workers > len(queue) {
+			workers = len(queue)
+		}
+		for i := 0; i < workers; i++ {
+			limiter.Go(ctx, func() {
+				for delivery := range queue {
+					if ctx.Err() != nil {
+						return
+					}
+					if err := chore.deliver(ctx, delivery, now); err != nil {
+						mu.Lock()
+						group.Add(err)
+						mu.Unlock()
+					}
+				}
+			})
+		}
+	}
+	limiter.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return Error.Wrap(group.Err())
+}
+
+// deliver sends the delivery and deletes it when it's delivered or when
+// there are no attempts left, it's postponed otherwise.
+func (chore *Chore) deliver(ctx context.Context, delivery Delivery, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	attempts := delivery.Attempts + 1
+
+	payload, err := chore.payload(ctx, delivery)
+	if err != nil {
+		if !ErrNotCommitted.Has(err) {
+			return err
+		}
+		// the object may not be committed yet, or its commit failed
+		if attempts >= chore.config.MaxAttempts {
+			mon.Meter("bucket_event_not_committed").Mark(1)
+			return chore.db.Delete(ctx, delivery.ID)
+		}
+		return chore.db.Retry(ctx, delivery.ID, now.Add(chore.backoff(attempts)))
+	}
+
+	sendErr := chore.send(ctx, delivery, payload)
+	if sendErr == nil {
+		mon.Meter("bucket_event_delivered").Mark(1)
+		return chore.db.Delete(ctx, delivery.ID)
+	}
+
+	if attempts >= chore.config.MaxAttempts {
+		mon.Meter("bucket_event_dropped").Mark(1)
+		chore.log.Warn("dropping bucket event after too many failed deliveries",
+			zap.Int64("delivery", delivery.ID),
+			zap.String("url", delivery.URL),
+			zap.Int("attempts", attempts),
+			zap.Error(sendErr))
+		return chore.db.Delete(ctx, delivery.ID)
+	}
+
+	mon.Meter("bucket_event_retried").Mark(1)
+	chore.log.Debug("failed to deliver bucket event",
+		zap.Int64("delivery", delivery.ID),
+		zap.String("url", delivery.URL),
+		zap.Int("attempts", attempts),
+		zap.Error(sendErr))
+	return chore.db.Retry(ctx, delivery.ID, now.Add(chore.backoff(attempts)))
+}
+
+// payload returns the payload to send. The object created events get the
+// size of their object, once it's committed.
+func (chore *Chore) payload(ctx context.Context, delivery Delivery) (_ []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var event Event
+	if err := json.Unmarshal(delivery.Payload, &event); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if event.Type != ObjectCreated.String() {
+		return delivery.Payload, nil
+	}
+
+	var createdAt time.Time
+	if event.CreatedAt != nil {
+		createdAt = *event.CreatedAt
+	}
+	event.Size, err = chore.objects.Size(ctx, event.ProjectID, []byte(event.Bucket), event.EncryptedKey, createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(event)
+	return payload, Error.Wrap(err)
+}
+
+// send posts the payload of the delivery to the subscriber.
+func (chore *Chore) send(ctx context.Context, delivery Delivery, payload []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	request, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(payload))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, payload))
+	request.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+
+	response, err := chore.client.Do(request)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(response.Body, 1<<10))
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return Error.New("unexpected status %q", response.Status)
+	}
+	return nil
+}
+
+// endpointOf returns the host of the webhook URL, the deliveries to the same
+// host are limited.
+func endpointOf(webhookURL string) string {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Host == "" {
+		return webhookURL
+	}
+	return parsed.Host
+}
+
+// backoff returns how long to wait before the next attempt, it doubles
+// with every failed attempt up to MaxBackoff.
+func (chore *Chore) backoff(attempts int) time.Duration {
+	backoff := chore.config.InitialBackoff
+	for i := 1; i < attempts && backoff < chore.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > chore.config.MaxBackoff {
+		backoff = chore.config.MaxBackoff
+	}
+	return backoff
+}
+
+// Close stops the bucket events delivery chore.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
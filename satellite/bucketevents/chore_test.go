@@ -0,0 +1,197 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketevents_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/bucketevents"
+	"storj.io/storj/satellite/console"
+)
+
+// webhook records the events it receives, failing the first failures requests.
+type webhook struct {
+	mu       sync.Mutex
+	secret   []byte
+	failures int
+	events   []bucketevents.Event
+	invalid  int
+}
+
+func (hook *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get(bucketevents.SignatureHeader) != bucketevents.Sign(hook.secret, body) {
+		hook.invalid++
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	if hook.failures > 0 {
+		hook.failures--
+		http.Error(w, "try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	var event bucketevents.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hook.events = append(hook.events, event)
+}
+
+func (hook *webhook) received() []bucketevents.Event {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	return append([]bucketevents.Event{}, hook.events...)
+}
+
+func TestChore(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.BucketEvents.InitialBackoff = 0
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+		project := uplink.Projects[0]
+
+		chore := sat.Core.BucketEvents.Chore
+		chore.Loop.Pause()
+
+		hook := &webhook{secret: testrand.BytesInt(32), failures: 1}
+		server := httptest.NewServer(hook)
+		defer server.Close()
+
+		subscriptions := sat.DB.Console().BucketEventSubscriptions()
+		_, err := subscriptions.Create(ctx, console.BucketEventSubscription{
+			ID:         testrand.UUID(),
+			ProjectID:  project.ID,
+			BucketName: "watched",
+			URL:        server.URL,
+			Secret:     hook.secret,
+			EventTypes: bucketevents.AllEvents,
+		})
+		require.NoError(t, err)
+
+		_, err = subscriptions.Create(ctx, console.BucketEventSubscription{
+			ID:         testrand.UUID(),
+			ProjectID:  project.ID,
+			BucketName: "watched",
+			URL:        server.URL,
+			Secret:     hook.secret,
+			EventTypes: bucketevents.ObjectDeleted,
+		})
+		require.NoError(t, err)
+
+		data := testrand.Bytes(10 * memory.KiB)
+		require.NoError(t, uplink.Upload(ctx, sat, "watched", "object", data))
+		require.NoError(t, uplink.Upload(ctx, sat, "other", "object", data))
+
+		// the first delivery fails and it's retried in the next cycle
+		chore.Loop.TriggerWait()
+		require.Empty(t, hook.received())
+
+		pending, err := sat.DB.BucketEvents().ListPending(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, 1, pending[0].Attempts)
+
+		chore.Loop.TriggerWait()
+		events := hook.received()
+		require.Len(t, events, 1)
+		require.Equal(t, "object:created", events[0].Type)
+		require.Equal(t, project.ID, events[0].ProjectID)
+		require.Equal(t, "watched", events[0].Bucket)
+		require.NotEmpty(t, events[0].EncryptedKey)
+		require.NotEqual(t, []byte("object"), events[0].EncryptedKey)
+		require.True(t, events[0].Size > 0)
+		require.NotNil(t, events[0].CreatedAt)
+		require.Nil(t, events[0].ExpiresAt)
+
+		// both subscriptions receive the deletion
+		require.NoError(t, uplink.DeleteObject(ctx, sat, "watched", "object"))
+		chore.Loop.TriggerWait()
+
+		events = hook.received()
+		require.Len(t, events, 3)
+		for _, event := range events[1:] {
+			require.Equal(t, "object:deleted", event.Type)
+			require.Equal(t, events[0].EncryptedKey, event.EncryptedKey)
+		}
+		require.Zero(t, hook.invalid)
+
+		pending, err = sat.DB.BucketEvents().ListPending(ctx, time.Now().Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+}
+
+func TestChoreDropsAfterMaxAttempts(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.BucketEvents.InitialBackoff = 0
+				config.BucketEvents.MaxAttempts = 2
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+		project := uplink.Projects[0]
+
+		chore := sat.Core.BucketEvents.Chore
+		chore.Loop.Pause()
+
+		hook := &webhook{secret: testrand.BytesInt(32), failures: 10}
+		server := httptest.NewServer(hook)
+		defer server.Close()
+
+		_, err := sat.DB.Console().BucketEventSubscriptions().Create(ctx, console.BucketEventSubscription{
+			ID:         testrand.UUID(),
+			ProjectID:  project.ID,
+			BucketName: "watched",
+			URL:        server.URL,
+			Secret:     hook.secret,
+			EventTypes: bucketevents.ObjectCreated,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, uplink.Upload(ctx, sat, "watched", "object", testrand.Bytes(memory.KiB)))
+
+		chore.Loop.TriggerWait()
+		chore.Loop.TriggerWait()
+
+		pending, err := sat.DB.BucketEvents().ListPending(ctx, time.Now().Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Empty(t, pending)
+		require.Empty(t, hook.received())
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketevents
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+)
+
+var (
+	// Error is the default error class for bucket events.
+	Error = errs.Class("bucket events error")
+	// ErrNotCommitted is returned when the object of an object created event
+	// isn't committed.
+	ErrNotCommitted = errs.Class("object not committed")
+
+	mon = monkit.Package()
+)
+
+// EventType is a set of bucket event types.
+type EventType int
+
+const (
+	// ObjectCreated is published when an object is committed.
+	ObjectCreated EventType = 1 << iota
+	// ObjectDeleted is published when an object is deleted.
+	ObjectDeleted
+
+	// AllEvents contains every bucket event type.
+	AllEvents = ObjectCreated | ObjectDeleted
+)
+
+// eventTypeNames are the names of the event types, in the order they're listed.
+var eventTypeNames = []struct {
+	Type EventType
+	Name string
+}{
+	{ObjectCreated, "object:created"},
+	{ObjectDeleted, "object:deleted"},
+}
+
+// ParseEventType returns the event type with the given name.
+func ParseEventType(name string) (EventType, error) {
+	for _, eventType := range eventTypeNames {
+		if eventType.Name == name {
+			return eventType.Type, nil
+		}
+	}
+	return 0, Error.New("unknown event type %q", name)
+}
+
+// Names returns the names of the event types in the set.
+func (types EventType) Names() []string {
+	var names []string
+	for _, eventType := range eventTypeNames {
+		if types&eventType.Type != 0 {
+			names = append(names, eventType.Name)
+		}
+	}
+	return names
+}
+
+// String returns the names of the event types, separated by commas.
+func (types EventType) String() string {
+	return strings.Join(types.Names(), ",")
+}
+
+// Event is the payload delivered to the subscribers.
+//
+// It contains only the information the satellite has, the object key is
+// encrypted and the size is the encrypted size of the segments.
+type Event struct {
+	Type         string     `json:"type"`
+	ProjectID    uuid.UUID  `json:"projectId"`
+	Bucket       string     `json:"bucket"`
+	EncryptedKey []byte     `json:"encryptedKey"`
+	Size         int64      `json:"size,omitempty"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	OccurredAt   time.Time  `json:"occurredAt"`
+}
+
+// Delivery is a pending event with the subscription it should be sent to.
+type Delivery struct {
+	ID       int64
+	URL      string
+	Secret   []byte
+	Payload  []byte
+	Attempts int
+}
+
+// DB is the durable outbox of the bucket events.
+//
+// architecture: Database
+type DB interface {
+	// Subscribed returns whether the bucket has subscriptions for eventType.
+	Subscribed(ctx context.Context, projectID uuid.UUID, bucketName []byte, eventType EventType) (bool, error)
+	// Enqueue adds the event payload to the outbox once for every subscription
+	// of the bucket to eventType.
+	Enqueue(ctx context.Context, projectID uuid.UUID, bucketName []byte, eventType EventType, payload []byte) error
+	// ListPending returns up to limit deliveries due before now, the oldest first.
+	ListPending(ctx context.Context, now time.Time, limit int) ([]Delivery, error)
+	// Retry increments the attempts of the delivery and postpones it until nextAttemptAt.
+	Retry(ctx context.Context, id int64, nextAttemptAt time.Time) error
+	// Delete removes the delivery from the outbox.
+	Delete(ctx context.Context, id int64) error
+}
+
+// Objects looks up the objects of the object created events.
+//
+// The object created events are enqueued before their objects are committed,
+// so that no event is lost after a commit. The chore delivers them once it
+// finds the object, together with its size.
+type Objects interface {
+	// Size returns the size of the object committed at createdAt, or
+	// ErrNotCommitted when there isn't one.
+	Size(ctx context.Context, projectID uuid.UUID, bucket, encryptedKey []byte, createdAt time.Time) (int64, error)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body using the subscription secret.
+//
+// Subscribers should compare it against the SignatureHeader of the request.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
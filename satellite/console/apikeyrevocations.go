@@ -0,0 +1,25 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+
+	"storj.io/common/uuid"
+)
+
+// APIKeyRevocations is the store of the revoked macaroon tails of the projects.
+//
+// Revoking a tail rejects the API key with that tail and every API key
+// restricted from it, while the root API key and its other restricted keys
+// keep working.
+//
+// architecture: Database
+type APIKeyRevocations interface {
+	// Revoke adds the macaroon tail to the revocation list of the project.
+	// Revoking a tail more than once isn't an error.
+	Revoke(ctx context.Context, projectID uuid.UUID, tail []byte) error
+	// GetByProjectID returns the revoked macaroon tails of the project.
+	GetByProjectID(ctx context.Context, projectID uuid.UUID) ([][]byte, error)
+}
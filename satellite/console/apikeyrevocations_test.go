@@ -0,0 +1,56 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestAPIKeyRevocationsRepository(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		projects := db.Console().Projects()
+		revocations := db.Console().APIKeyRevocations()
+
+		project, err := projects.Insert(ctx, &console.Project{
+			Name:        "ProjectName",
+			Description: "projects description",
+		})
+		require.NoError(t, err)
+
+		other, err := projects.Insert(ctx, &console.Project{
+			Name:        "OtherProjectName",
+			Description: "projects description",
+		})
+		require.NoError(t, err)
+
+		tails, err := revocations.GetByProjectID(ctx, project.ID)
+		require.NoError(t, err)
+		require.Empty(t, tails)
+
+		first, second := testrand.BytesInt(32), testrand.BytesInt(32)
+		require.NoError(t, revocations.Revoke(ctx, project.ID, first))
+		require.NoError(t, revocations.Revoke(ctx, project.ID, second))
+		// revoking twice isn't an error
+		require.NoError(t, revocations.Revoke(ctx, project.ID, first))
+		require.NoError(t, revocations.Revoke(ctx, other.ID, testrand.BytesInt(32)))
+
+		tails, err = revocations.GetByProjectID(ctx, project.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, [][]byte{first, second}, tails)
+
+		// the revocations are deleted with the project
+		require.NoError(t, projects.Delete(ctx, project.ID))
+		tails, err = revocations.GetByProjectID(ctx, project.ID)
+		require.NoError(t, err)
+		require.Empty(t, tails)
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"time"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/bucketevents"
+)
+
+// BucketEventSubscriptions is the store of the webhooks which receive the
+// events of the buckets.
+//
+// architecture: Database
+type BucketEventSubscriptions interface {
+	// Create inserts a new subscription.
+	Create(ctx context.Context, subscription BucketEventSubscription) (*BucketEventSubscription, error)
+	// GetByBucket returns the subscriptions of the bucket.
+	GetByBucket(ctx context.Context, projectID uuid.UUID, bucketName []byte) ([]BucketEventSubscription, error)
+	// Delete deletes the subscription together with its pending events.
+	Delete(ctx context.Context, projectID uuid.UUID, id uuid.UUID) error
+}
+
+// BucketEventSubscription is a webhook which receives the events of a bucket.
+type BucketEventSubscription struct {
+	ID         uuid.UUID              `json:"id"`
+	ProjectID  uuid.UUID              `json:"projectId"`
+	BucketName string                 `json:"bucketName"`
+	URL        string                 `json:"url"`
+	Secret     []byte                 `json:"-"`
+	EventTypes bucketevents.EventType `json:"eventTypes"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/bucketevents"
+	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestBucketEventSubscriptionsRepository(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		projects := db.Console().Projects()
+		subscriptions := db.Console().BucketEventSubscriptions()
+
+		project, err := projects.Insert(ctx, &console.Project{
+			Name:        "ProjectName",
+			Description: "projects description",
+		})
+		require.NoError(t, err)
+
+		list, err := subscriptions.GetByBucket(ctx, project.ID, []byte("bucket"))
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		created, err := subscriptions.Create(ctx, console.BucketEventSubscription{
+			ID:         testrand.UUID(),
+			ProjectID:  project.ID,
+			BucketName: "bucket",
+			URL:        "https://example.test/hook",
+			Secret:     testrand.BytesInt(32),
+			EventTypes: bucketevents.ObjectCreated,
+		})
+		require.NoError(t, err)
+		require.False(t, created.CreatedAt.IsZero())
+
+		_, err = subscriptions.Create(ctx, console.BucketEventSubscription{
+			ID:         testrand.UUID(),
+			ProjectID:  project.ID,
+			BucketName: "other",
+			URL:        "https://example.test/other",
+			Secret:     testrand.BytesInt(32),
+			EventTypes: bucketevents.AllEvents,
+		})
+		require.NoError(t, err)
+
+		list, err = subscriptions.GetByBucket(ctx, project.ID, []byte("bucket"))
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, created.ID, list[0].ID)
+		require.Equal(t, created.URL, list[0].URL)
+		require.Equal(t, created.Secret, list[0].Secret)
+		require.Equal(t, bucketevents.ObjectCreated, list[0].EventTypes)
+
+		// the events are published only for the subscribed buckets and types
+		events := db.BucketEvents()
+		subscribed, err := events.Subscribed(ctx, project.ID, []byte("bucket"), bucketevents.ObjectCreated)
+		require.NoError(t, err)
+		require.True(t, subscribed)
+		subscribed, err = events.Subscribed(ctx, project.ID, []byte("bucket"), bucketevents.ObjectDeleted)
+		require.NoError(t, err)
+		require.False(t, subscribed)
+
+		require.NoError(t, events.Enqueue(ctx, project.ID, []byte("bucket"), bucketevents.ObjectDeleted, []byte("{}")))
+		require.NoError(t, events.Enqueue(ctx, project.ID, []byte("bucket"), bucketevents.ObjectCreated, []byte("{}")))
+
+		pending, err := events.ListPending(ctx, created.CreatedAt.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, created.URL, pending[0].URL)
+
+		// deleting the subscription drops its pending events
+		require.NoError(t, subscriptions.Delete(ctx, project.ID, created.ID))
+		list, err = subscriptions.GetByBucket(ctx, project.ID, []byte("bucket"))
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		pending, err = events.ListPending(ctx, created.CreatedAt.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Empty(t, pending)
+
+		// the subscriptions are deleted with the project
+		require.NoError(t, projects.Delete(ctx, project.ID))
+		list, err = subscriptions.GetByBucket(ctx, project.ID, []byte("other"))
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+}
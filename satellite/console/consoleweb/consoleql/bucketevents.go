@@ -0,0 +1,106 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleql
+
+import (
+	"encoding/hex"
+
+	"github.com/graphql-go/graphql"
+
+	"storj.io/storj/satellite/bucketevents"
+	"storj.io/storj/satellite/console"
+)
+
+const (
+	// BucketEventSubscriptionType is a graphql type name for bucket event subscription
+	BucketEventSubscriptionType = "bucketEventSubscription"
+	// CreateBucketEventSubscriptionType is a graphql type name for createBucketEventSubscription struct
+	// which incapsulates the signing secret and the subscription
+	CreateBucketEventSubscriptionType = "graphqlCreateBucketEventSubscription"
+	// FieldURL is a field name for webhook url
+	FieldURL = "url"
+	// FieldEventTypes is a field name for bucket event types
+	FieldEventTypes = "eventTypes"
+	// FieldSubscription is a field name for bucket event subscription
+	FieldSubscription = "subscription"
+	// FieldBucketEventSubscriptions is a field name for bucket event subscriptions
+	FieldBucketEventSubscriptions = "bucketEventSubscriptions"
+)
+
+// graphqlBucketEventSubscription creates console.BucketEventSubscription graphql object
+func graphqlBucketEventSubscription() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: BucketEventSubscriptionType,
+		Fields: graphql.Fields{
+			FieldID: &graphql.Field{
+				Type: graphql.String,
+			},
+			FieldProjectID: &graphql.Field{
+				Type: graphql.String,
+			},
+			FieldBucketName: &graphql.Field{
+				Type: graphql.String,
+			},
+			FieldURL: &graphql.Field{
+				Type: graphql.String,
+			},
+			FieldEventTypes: &graphql.Field{
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					switch subscription := p.Source.(type) {
+					case console.BucketEventSubscription:
+						return subscription.EventTypes.Names(), nil
+					case *console.BucketEventSubscription:
+						return subscription.EventTypes.Names(), nil
+					}
+					return nil, nil
+				},
+			},
+			FieldCreatedAt: &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	})
+}
+
+// graphqlCreateBucketEventSubscription creates createBucketEventSubscription graphql object
+func graphqlCreateBucketEventSubscription(types *TypeCreator) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: CreateBucketEventSubscriptionType,
+		Fields: graphql.Fields{
+			Secret: &graphql.Field{
+				Type: graphql.String,
+			},
+			FieldSubscription: &graphql.Field{
+				Type: types.bucketEventSubscription,
+			},
+		},
+	})
+}
+
+// createBucketEventSubscription holds the hex encoded signing secret and console.BucketEventSubscription
+type createBucketEventSubscription struct {
+	Secret       string
+	Subscription console.BucketEventSubscription
+}
+
+// newCreateBucketEventSubscription returns the created subscription with its signing secret.
+func newCreateBucketEventSubscription(subscription *console.BucketEventSubscription) createBucketEventSubscription {
+	return createBucketEventSubscription{
+		Secret:       hex.EncodeToString(subscription.Secret),
+		Subscription: *subscription,
+	}
+}
+
+// fromListEventTypes converts the list of event type names to bucketevents.EventType
+func fromListEventTypes(names []interface{}) (eventTypes bucketevents.EventType, err error) {
+	for _, name := range names {
+		eventType, err := bucketevents.ParseEventType(name.(string))
+		if err != nil {
+			return 0, err
+		}
+		eventTypes |= eventType
+	}
+	return eventTypes, nil
+}
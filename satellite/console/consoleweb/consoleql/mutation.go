@@ -7,8 +7,10 @@ import (
 	"github.com/graphql-go/graphql"
 	"go.uber.org/zap"
 
+	"storj.io/common/memory"
 	"storj.io/common/uuid"
 	"storj.io/storj/private/post"
+	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/mailservice"
 )
@@ -23,6 +25,8 @@ const (
 	DeleteProjectMutation = "deleteProject"
 	// UpdateProjectDescriptionMutation is a mutation name for project updating
 	UpdateProjectDescriptionMutation = "updateProjectDescription"
+	// UpdateBucketLimitsMutation is a mutation name for bucket limits updating
+	UpdateBucketLimitsMutation = "updateBucketLimits"
 
 	// AddProjectMembersMutation is a mutation name for adding new project members
 	AddProjectMembersMutation = "addProjectMembers"
@@ -33,6 +37,13 @@ const (
 	CreateAPIKeyMutation = "createAPIKey"
 	// DeleteAPIKeysMutation is a mutation name for api key deleting
 	DeleteAPIKeysMutation = "deleteAPIKeys"
+	// RevokeAPIKeysMutation is a mutation name for api key revoking
+	RevokeAPIKeysMutation = "revokeAPIKeys"
+
+	// CreateBucketEventSubscriptionMutation is a mutation name for bucket event subscription creation
+	CreateBucketEventSubscriptionMutation = "createBucketEventSubscription"
+	// DeleteBucketEventSubscriptionMutation is a mutation name for bucket event subscription deleting
+	DeleteBucketEventSubscriptionMutation = "deleteBucketEventSubscription"
 
 	// AddPaymentMethodMutation is mutation name for adding new payment method
 	AddPaymentMethodMutation = "addPaymentMethod"
@@ -132,6 +143,44 @@ func rootMutation(log *zap.Logger, service *console.Service, mailService *mailse
 					return project, nil
 				},
 			},
+			// updates the storage and bandwidth limits of a bucket,
+			// an omitted limit removes the limit
+			UpdateBucketLimitsMutation: &graphql.Field{
+				Type: types.bucketUsageLimits,
+				Args: graphql.FieldConfigArgument{
+					FieldProjectID: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldBucketName: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldStorageLimit: &graphql.ArgumentConfig{
+						Type: graphql.Float,
+					},
+					FieldBandwidthLimit: &graphql.ArgumentConfig{
+						Type: graphql.Float,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					projectID, err := uuid.FromString(p.Args[FieldProjectID].(string))
+					if err != nil {
+						return nil, err
+					}
+					bucketName := p.Args[FieldBucketName].(string)
+
+					var limits accounting.BucketLimits
+					if storageLimit, ok := p.Args[FieldStorageLimit].(float64); ok {
+						limit := memory.Size(storageLimit)
+						limits.Storage = &limit
+					}
+					if bandwidthLimit, ok := p.Args[FieldBandwidthLimit].(float64); ok {
+						limit := memory.Size(bandwidthLimit)
+						limits.Bandwidth = &limit
+					}
+
+					return service.UpdateBucketLimits(p.Context, projectID, bucketName, limits)
+				},
+			},
 			// add user as member of given project
 			AddProjectMembersMutation: &graphql.Field{
 				Type: types.project,
@@ -304,6 +353,102 @@ func rootMutation(log *zap.Logger, service *console.Service, mailService *mailse
 					return keys, nil
 				},
 			},
+			// revokes api keys restricted from the api keys of the project
+			RevokeAPIKeysMutation: &graphql.Field{
+				Type: graphql.NewList(types.apiKeyInfo),
+				Args: graphql.FieldConfigArgument{
+					FieldProjectID: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldAPIKeys: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.NewList(graphql.String)),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					projectIDField, _ := p.Args[FieldProjectID].(string)
+					paramKeys, _ := p.Args[FieldAPIKeys].([]interface{})
+
+					projectID, err := uuid.FromString(projectIDField)
+					if err != nil {
+						return nil, err
+					}
+
+					var serializedKeys []string
+					for _, key := range paramKeys {
+						serializedKeys = append(serializedKeys, key.(string))
+					}
+
+					return service.RevokeAPIKeys(p.Context, projectID, serializedKeys)
+				},
+			},
+			// subscribes a webhook to the events of a bucket,
+			// the signing secret is returned only here
+			CreateBucketEventSubscriptionMutation: &graphql.Field{
+				Type: types.createBucketEventSubscription,
+				Args: graphql.FieldConfigArgument{
+					FieldProjectID: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldBucketName: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldURL: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldEventTypes: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.NewList(graphql.String)),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					projectID, err := uuid.FromString(p.Args[FieldProjectID].(string))
+					if err != nil {
+						return nil, err
+					}
+					bucketName := p.Args[FieldBucketName].(string)
+					webhookURL := p.Args[FieldURL].(string)
+
+					paramEventTypes, _ := p.Args[FieldEventTypes].([]interface{})
+					eventTypes, err := fromListEventTypes(paramEventTypes)
+					if err != nil {
+						return nil, err
+					}
+
+					subscription, err := service.CreateBucketEventSubscription(p.Context, projectID, bucketName, webhookURL, eventTypes)
+					if err != nil {
+						return nil, err
+					}
+
+					return newCreateBucketEventSubscription(subscription), nil
+				},
+			},
+			DeleteBucketEventSubscriptionMutation: &graphql.Field{
+				Type: graphql.Boolean,
+				Args: graphql.FieldConfigArgument{
+					FieldProjectID: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldID: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					projectID, err := uuid.FromString(p.Args[FieldProjectID].(string))
+					if err != nil {
+						return nil, err
+					}
+					id, err := uuid.FromString(p.Args[FieldID].(string))
+					if err != nil {
+						return nil, err
+					}
+
+					err = service.DeleteBucketEventSubscription(p.Context, projectID, id)
+					if err != nil {
+						return false, err
+					}
+
+					return true, nil
+				},
+			},
 			AddPaymentMethodMutation: &graphql.Field{
 				Type: graphql.Boolean,
 				Args: graphql.FieldConfigArgument{},
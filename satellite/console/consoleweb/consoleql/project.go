@@ -26,6 +26,8 @@ const (
 	BucketUsageType = "bucketUsage"
 	// BucketUsagePageType is a field name for bucket usage page
 	BucketUsagePageType = "bucketUsagePage"
+	// BucketUsageLimitsType is a graphql type name for bucket usage limits
+	BucketUsageLimitsType = "bucketUsageLimits"
 	// ProjectMembersPageType is a field name for project members page
 	ProjectMembersPageType = "projectMembersPage"
 	// ProjectMembersCursorInputType is a graphql type name for project members
@@ -50,6 +52,16 @@ const (
 	FieldUsage = "usage"
 	// FieldBucketUsages is a field name for bucket usages
 	FieldBucketUsages = "bucketUsages"
+	// FieldBucketLimits is a field name for bucket usage limits
+	FieldBucketLimits = "bucketLimits"
+	// FieldStorageLimit is a field name for storage limit
+	FieldStorageLimit = "storageLimit"
+	// FieldBandwidthLimit is a field name for bandwidth limit
+	FieldBandwidthLimit = "bandwidthLimit"
+	// FieldStorageUsed is a field name for storage used
+	FieldStorageUsed = "storageUsed"
+	// FieldBandwidthUsed is a field name for bandwidth used
+	FieldBandwidthUsed = "bandwidthUsed"
 	// FieldStorage is a field name for storage total
 	FieldStorage = "storage"
 	// FieldEgress is a field name for egress total
@@ -236,6 +248,41 @@ func graphqlProject(service *console.Service, types *TypeCreator) *graphql.Objec
 					return page, nil
 				},
 			},
+			FieldBucketLimits: &graphql.Field{
+				Type: types.bucketUsageLimits,
+				Args: graphql.FieldConfigArgument{
+					FieldBucketName: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					project, _ := p.Source.(*console.Project)
+
+					bucketName := p.Args[FieldBucketName].(string)
+
+					limits, err := service.GetBucketUsageLimits(p.Context, project.ID, bucketName)
+					if err != nil {
+						return nil, err
+					}
+
+					return limits, nil
+				},
+			},
+			FieldBucketEventSubscriptions: &graphql.Field{
+				Type: graphql.NewList(types.bucketEventSubscription),
+				Args: graphql.FieldConfigArgument{
+					FieldBucketName: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					project, _ := p.Source.(*console.Project)
+
+					bucketName := p.Args[FieldBucketName].(string)
+
+					return service.GetBucketEventSubscriptions(p.Context, project.ID, bucketName)
+				},
+			},
 		},
 	})
 }
@@ -300,6 +347,30 @@ func graphqlBucketUsage() *graphql.Object {
 	})
 }
 
+// graphqlBucketUsageLimits creates bucket usage limits graphql type
+func graphqlBucketUsageLimits() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: BucketUsageLimitsType,
+		Fields: graphql.Fields{
+			FieldBucketName: &graphql.Field{
+				Type: graphql.String,
+			},
+			FieldStorageLimit: &graphql.Field{
+				Type: graphql.Float,
+			},
+			FieldBandwidthLimit: &graphql.Field{
+				Type: graphql.Float,
+			},
+			FieldStorageUsed: &graphql.Field{
+				Type: graphql.Float,
+			},
+			FieldBandwidthUsed: &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	})
+}
+
 // graphqlBucketUsagePage creates bucket usage page graphql object
 func graphqlBucketUsagePage(types *TypeCreator) *graphql.Object {
 	return graphql.NewObject(graphql.ObjectConfig{
@@ -18,6 +18,7 @@ import (
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/accounting/live"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleauth"
 	"storj.io/storj/satellite/console/consoleweb/consoleql"
@@ -60,6 +61,7 @@ func TestGraphqlQuery(t *testing.T) {
 			db.Rewards(),
 			partnersService,
 			mockpayments.Accounts(),
+			bucketevents.AddressPolicy{},
 			console.Config{PasswordCost: console.TestPasswordCost},
 			5000,
 		)
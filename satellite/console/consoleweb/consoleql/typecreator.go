@@ -23,12 +23,16 @@ type TypeCreator struct {
 	projectUsage      *graphql.Object
 	bucketUsage       *graphql.Object
 	bucketUsagePage   *graphql.Object
+	bucketUsageLimits *graphql.Object
 	projectMember     *graphql.Object
 	projectMemberPage *graphql.Object
 	apiKeyPage        *graphql.Object
 	apiKeyInfo        *graphql.Object
 	createAPIKey      *graphql.Object
 
+	bucketEventSubscription       *graphql.Object
+	createBucketEventSubscription *graphql.Object
+
 	userInput            *graphql.InputObject
 	projectInput         *graphql.InputObject
 	bucketUsageCursor    *graphql.InputObject
@@ -95,6 +99,11 @@ func (c *TypeCreator) Create(log *zap.Logger, service *console.Service, mailServ
 		return err
 	}
 
+	c.bucketUsageLimits = graphqlBucketUsageLimits()
+	if err := c.bucketUsageLimits.Error(); err != nil {
+		return err
+	}
+
 	c.apiKeyInfo = graphqlAPIKeyInfo()
 	if err := c.apiKeyInfo.Error(); err != nil {
 		return err
@@ -105,6 +114,16 @@ func (c *TypeCreator) Create(log *zap.Logger, service *console.Service, mailServ
 		return err
 	}
 
+	c.bucketEventSubscription = graphqlBucketEventSubscription()
+	if err := c.bucketEventSubscription.Error(); err != nil {
+		return err
+	}
+
+	c.createBucketEventSubscription = graphqlCreateBucketEventSubscription(c)
+	if err := c.createBucketEventSubscription.Error(); err != nil {
+		return err
+	}
+
 	c.projectMember = graphqlProjectMember(service, c)
 	if err := c.projectMember.Error(); err != nil {
 		return err
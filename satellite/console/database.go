@@ -19,6 +19,10 @@ type DB interface {
 	ProjectMembers() ProjectMembers
 	// APIKeys is a getter for APIKeys repository.
 	APIKeys() APIKeys
+	// APIKeyRevocations is a getter for APIKeyRevocations repository.
+	APIKeyRevocations() APIKeyRevocations
+	// BucketEventSubscriptions is a getter for BucketEventSubscriptions repository.
+	BucketEventSubscriptions() BucketEventSubscriptions
 	// RegistrationTokens is a getter for RegistrationTokens repository.
 	RegistrationTokens() RegistrationTokens
 	// ResetPasswordTokens is a getter for ResetPasswordTokens repository.
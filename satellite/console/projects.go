@@ -35,6 +35,8 @@ type Projects interface {
 
 	// UpdateRateLimit is a method for updating projects rate limit.
 	UpdateRateLimit(ctx context.Context, id uuid.UUID, newLimit int) error
+	// UpdateOwner is a method for transferring the ownership of a project to another user.
+	UpdateOwner(ctx context.Context, id uuid.UUID, ownerID uuid.UUID) error
 }
 
 // Project is a database object that describes Project entity
@@ -10,3 +10,14 @@ type ProjectUsageLimits struct {
 	StorageUsed    int64 `json:"storageUsed"`
 	BandwidthUsed  int64 `json:"bandwidthUsed"`
 }
+
+// BucketUsageLimits holds the storage and bandwidth limits of a bucket together
+// with its current usage. A nil limit means that the bucket is only limited by
+// the limits of its project.
+type BucketUsageLimits struct {
+	BucketName     string `json:"bucketName"`
+	StorageLimit   *int64 `json:"storageLimit"`
+	BandwidthLimit *int64 `json:"bandwidthLimit"`
+	StorageUsed    int64  `json:"storageUsed"`
+	BandwidthUsed  int64  `json:"bandwidthUsed"`
+}
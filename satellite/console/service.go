@@ -5,9 +5,11 @@ package console
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/subtle"
 	"database/sql"
 	"fmt"
+	"net/url"
 	"sort"
 	"time"
 
@@ -21,6 +23,7 @@ import (
 	"storj.io/common/uuid"
 	"storj.io/storj/pkg/auth"
 	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/console/consoleauth"
 	"storj.io/storj/satellite/payments"
 	"storj.io/storj/satellite/rewards"
@@ -48,6 +51,10 @@ const (
 	passwordIncorrectErrMsg              = "Your password needs at least %d characters long"
 	projectOwnerDeletionForbiddenErrMsg  = "%s is a project owner and can not be deleted"
 	apiKeyWithNameExistsErrMsg           = "An API Key with this name already exists in this project, please use a different name"
+	apiKeyNotInProjectErrMsg             = "The API Key doesn't belong to this project"
+	bucketNameEmptyErrMsg                = "The bucket name can't be empty"
+	invalidEventTypesErrMsg              = "Select at least one of the supported bucket event types"
+	invalidWebhookURLErrMsg              = "The webhook URL must be an absolute http or https URL"
 	teamMemberDoesNotExistErrMsg         = `There is no account on this Satellite for the user(s) you have entered.
 									     Please add team members with active accounts`
 
@@ -85,6 +92,7 @@ type Service struct {
 	rewards           rewards.DB
 	partners          *rewards.PartnersService
 	accounts          payments.Accounts
+	webhookPolicy     bucketevents.AddressPolicy
 
 	config Config
 
@@ -103,7 +111,7 @@ type PaymentsService struct {
 }
 
 // NewService returns new instance of Service.
-func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting accounting.ProjectAccounting, projectUsage *accounting.Service, rewards rewards.DB, partners *rewards.PartnersService, accounts payments.Accounts, config Config, minCoinPayment int64) (*Service, error) {
+func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting accounting.ProjectAccounting, projectUsage *accounting.Service, rewards rewards.DB, partners *rewards.PartnersService, accounts payments.Accounts, webhookPolicy bucketevents.AddressPolicy, config Config, minCoinPayment int64) (*Service, error) {
 	if signer == nil {
 		return nil, errs.New("signer can't be nil")
 	}
@@ -126,6 +134,7 @@ func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting acco
 		rewards:           rewards,
 		partners:          partners,
 		accounts:          accounts,
+		webhookPolicy:     webhookPolicy,
 		config:            config,
 		minCoinPayment:    minCoinPayment,
 	}, nil
@@ -1187,6 +1196,159 @@ func (s *Service) DeleteAPIKeys(ctx context.Context, ids []uuid.UUID) (err error
 	return Error.Wrap(err)
 }
 
+// RevokeAPIKeys adds the serialized API keys to the revocation list of the
+// project. It returns the infos of the API keys which they're restricted from.
+//
+// Unlike DeleteAPIKeys, only the revoked keys and the keys restricted from
+// them are rejected, the other keys restricted from the same API key keep
+// working.
+func (s *Service) RevokeAPIKeys(ctx context.Context, projectID uuid.UUID, serializedKeys []string) (_ []APIKeyInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	var infos []APIKeyInfo
+	var tails [][]byte
+	for _, serializedKey := range serializedKeys {
+		key, err := macaroon.ParseAPIKey(serializedKey)
+		if err != nil {
+			return nil, ErrValidation.Wrap(err)
+		}
+
+		info, err := s.store.APIKeys().GetByHead(ctx, key.Head())
+		if err != nil {
+			return nil, ErrValidation.New(apiKeyNotInProjectErrMsg)
+		}
+		if info.ProjectID != projectID {
+			return nil, ErrValidation.New(apiKeyNotInProjectErrMsg)
+		}
+
+		mac, err := macaroon.ParseMacaroon(key.SerializeRaw())
+		if err != nil {
+			return nil, ErrValidation.Wrap(err)
+		}
+		if !mac.Validate(info.Secret) {
+			return nil, ErrValidation.New(apiKeyNotInProjectErrMsg)
+		}
+
+		infos = append(infos, *info)
+		tails = append(tails, key.Tail())
+	}
+
+	for _, tail := range tails {
+		err = s.store.APIKeyRevocations().Revoke(ctx, projectID, tail)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	return infos, nil
+}
+
+// CreateBucketEventSubscription subscribes the webhook at url to the events
+// of the bucket. The returned subscription contains the secret used to sign
+// the deliveries, it isn't returned anywhere else.
+func (s *Service) CreateBucketEventSubscription(ctx context.Context, projectID uuid.UUID, bucketName, webhookURL string, eventTypes bucketevents.EventType) (_ *BucketEventSubscription, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	if bucketName == "" {
+		return nil, ErrValidation.New(bucketNameEmptyErrMsg)
+	}
+	if eventTypes == 0 || eventTypes&^bucketevents.AllEvents != 0 {
+		return nil, ErrValidation.New(invalidEventTypesErrMsg)
+	}
+
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, ErrValidation.New(invalidWebhookURLErrMsg)
+	}
+	if err := s.webhookPolicy.CheckURL(ctx, parsed); err != nil {
+		return nil, ErrValidation.New(invalidWebhookURLErrMsg)
+	}
+
+	id, err := uuid.New()
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	subscription, err := s.store.BucketEventSubscriptions().Create(ctx, BucketEventSubscription{
+		ID:         id,
+		ProjectID:  projectID,
+		BucketName: bucketName,
+		URL:        parsed.String(),
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return subscription, nil
+}
+
+// GetBucketEventSubscriptions returns the webhooks subscribed to the events of the bucket.
+func (s *Service) GetBucketEventSubscriptions(ctx context.Context, projectID uuid.UUID, bucketName string) (_ []BucketEventSubscription, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	subscriptions, err := s.store.BucketEventSubscriptions().GetByBucket(ctx, projectID, []byte(bucketName))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteBucketEventSubscription deletes the subscription and drops its
+// undelivered events.
+func (s *Service) DeleteBucketEventSubscription(ctx context.Context, projectID, id uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return ErrUnauthorized.Wrap(err)
+	}
+
+	return Error.Wrap(s.store.BucketEventSubscriptions().Delete(ctx, projectID, id))
+}
+
 // GetAPIKeys returns paged api key list for given Project
 func (s *Service) GetAPIKeys(ctx context.Context, projectID uuid.UUID, cursor APIKeyCursor) (page *APIKeyPage, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1314,6 +1476,87 @@ func (s *Service) GetProjectUsageLimits(ctx context.Context, projectID uuid.UUID
 	}, nil
 }
 
+// GetBucketUsageLimits returns the limits of a bucket and its current usage.
+func (s *Service) GetBucketUsageLimits(ctx context.Context, projectID uuid.UUID, bucketName string) (_ *BucketUsageLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	return s.getBucketUsageLimits(ctx, projectID, bucketName)
+}
+
+// UpdateBucketLimits sets the storage and bandwidth limits of a bucket. Only
+// the owner of the project can change them. A nil limit removes the limit.
+func (s *Service) UpdateBucketLimits(ctx context.Context, projectID uuid.UUID, bucketName string, limits accounting.BucketLimits) (_ *BucketUsageLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.isProjectOwner(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	if limits.Storage != nil && *limits.Storage < 0 {
+		return nil, Error.New("storage limit can't be negative")
+	}
+	if limits.Bandwidth != nil && *limits.Bandwidth < 0 {
+		return nil, Error.New("bandwidth limit can't be negative")
+	}
+
+	err = s.projectUsage.UpdateBucketLimits(ctx, projectID, []byte(bucketName), limits)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return s.getBucketUsageLimits(ctx, projectID, bucketName)
+}
+
+// getBucketUsageLimits returns the limits of a bucket and its current usage.
+func (s *Service) getBucketUsageLimits(ctx context.Context, projectID uuid.UUID, bucketName string) (_ *BucketUsageLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limits, err := s.projectUsage.GetBucketLimits(ctx, projectID, []byte(bucketName))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	storageUsed, err := s.projectUsage.GetBucketStorageTotals(ctx, projectID, []byte(bucketName))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	bandwidthUsed, err := s.projectUsage.GetBucketBandwidthTotals(ctx, projectID, []byte(bucketName))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	usageLimits := &BucketUsageLimits{
+		BucketName:    bucketName,
+		StorageUsed:   storageUsed,
+		BandwidthUsed: bandwidthUsed,
+	}
+	if limits.Storage != nil {
+		storageLimit := limits.Storage.Int64()
+		usageLimits.StorageLimit = &storageLimit
+	}
+	if limits.Bandwidth != nil {
+		bandwidthLimit := limits.Bandwidth.Int64()
+		usageLimits.BandwidthLimit = &bandwidthLimit
+	}
+	return usageLimits, nil
+}
+
 // Authorize validates token from context and returns authorized Authorization
 func (s *Service) Authorize(ctx context.Context) (a Authorization, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -85,6 +85,9 @@ const (
 	Active UserStatus = 1
 	// Deleted is a user status that he receives after deleting account
 	Deleted UserStatus = 2
+	// Frozen is a user status that he receives when an admin freezes the account,
+	// the API keys of the projects he owns are rejected until it's unfrozen
+	Frozen UserStatus = 3
 )
 
 // User is a database object that describes User entity.
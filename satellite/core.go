@@ -29,6 +29,7 @@ import (
 	"storj.io/storj/satellite/accounting/rollup"
 	"storj.io/storj/satellite/accounting/tally"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/contact"
 	"storj.io/storj/satellite/dbcleanup"
 	"storj.io/storj/satellite/downtime"
@@ -36,6 +37,7 @@ import (
 	"storj.io/storj/satellite/gracefulexit"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/metainfo/expireddeletion"
+	"storj.io/storj/satellite/metainfo/uncommitteddeletion"
 	"storj.io/storj/satellite/metrics"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
@@ -110,6 +112,10 @@ type Core struct {
 		Chore *expireddeletion.Chore
 	}
 
+	UncommittedDeletion struct {
+		Chore *uncommitteddeletion.Chore
+	}
+
 	DBCleanup struct {
 		Chore *dbcleanup.Chore
 	}
@@ -138,6 +144,10 @@ type Core struct {
 		Chore *metrics.Chore
 	}
 
+	BucketEvents struct {
+		Chore *bucketevents.Chore
+	}
+
 	DowntimeTracking struct {
 		DetectionChore  *downtime.DetectionChore
 		EstimationChore *downtime.EstimationChore
@@ -233,7 +243,10 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 
 	{ // setup overlay
 		peer.Overlay.DB = peer.DB.OverlayCache()
-		peer.Overlay.Service = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		peer.Overlay.Service, err = overlay.NewService(peer.Log.Named("overlay"), peer.Overlay.DB, config.Overlay)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Services.Add(lifecycle.Item{
 			Name:  "overlay",
 			Close: peer.Overlay.Service.Close,
@@ -291,7 +304,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 
 	{ // setup datarepair
 		// TODO: simplify argument list somehow
-		peer.Repair.Checker = checker.NewChecker(
+		peer.Repair.Checker, err = checker.NewChecker(
 			peer.Log.Named("repair:checker"),
 			peer.DB.RepairQueue(),
 			peer.DB.Irreparable(),
@@ -299,6 +312,9 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Metainfo.Loop,
 			peer.Overlay.Service,
 			config.Checker)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Services.Add(lifecycle.Item{
 			Name:  "repair:checker",
 			Run:   peer.Repair.Checker.Run,
@@ -398,6 +414,22 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			debug.Cycle("Expired Segments Chore", peer.ExpiredDeletion.Chore.Loop))
 	}
 
+	{ // setup uncommitted segment cleanup
+		peer.UncommittedDeletion.Chore = uncommitteddeletion.NewChore(
+			peer.Log.Named("core-uncommitted-deletion"),
+			config.UncommittedDeletion,
+			config.Metainfo.MaxObjectCommitInterval,
+			peer.Metainfo.Service,
+			peer.Metainfo.Loop,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name: "uncommitteddeletion:chore",
+			Run:  peer.UncommittedDeletion.Chore.Run,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Uncommitted Segments Chore", peer.UncommittedDeletion.Chore.Loop))
+	}
+
 	{ // setup db cleanup
 		peer.DBCleanup.Chore = dbcleanup.NewChore(peer.Log.Named("dbcleanup"), peer.DB.Orders(), config.DBCleanup)
 		peer.Services.Add(lifecycle.Item{
@@ -514,6 +546,23 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			debug.Cycle("Metrics", peer.Metrics.Chore.Loop))
 	}
 
+	{ // setup bucket events delivery
+		peer.BucketEvents.Chore = bucketevents.NewChore(
+			peer.Log.Named("bucketevents"),
+			peer.DB.BucketEvents(),
+			metainfo.NewBucketEventObjects(peer.Metainfo.Service),
+			bucketevents.NewAddressPolicy(config.BucketEvents, config.Admin.Address),
+			config.BucketEvents,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "bucketevents",
+			Run:   peer.BucketEvents.Chore.Run,
+			Close: peer.BucketEvents.Chore.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Bucket Events", peer.BucketEvents.Chore.Loop))
+	}
+
 	{ // setup downtime tracking
 		peer.DowntimeTracking.Service = downtime.NewService(peer.Log.Named("downtime"), peer.Overlay.Service, peer.Contact.Service)
 
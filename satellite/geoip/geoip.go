@@ -0,0 +1,101 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package geoip implements resolving IP addresses to countries.
+package geoip
+
+import (
+	"io"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/zeebo/errs"
+)
+
+// Error is the default error class for geoip.
+var Error = errs.Class("geoip error")
+
+// IPToCountry defines an abstraction for resolving the ISO country code given the string representation of an IP address.
+//
+// architecture: Service
+type IPToCountry interface {
+	io.Closer
+	// LookupISOCountryCode returns the two letter ISO 3166-1 country code of
+	// the address. An empty string is returned when the country is unknown.
+	LookupISOCountryCode(address string) (string, error)
+}
+
+// Config contains configuration for the IP to country resolution.
+type Config struct {
+	DB string `help:"path to a MaxMind DB (mmdb) file mapping IP addresses to ISO 3166-1 country codes; when empty no country is resolved" default:""`
+}
+
+// Open opens the IP to country database configured in config.
+//
+// When no database has been configured, a resolver that doesn't
+// know any country is returned.
+func Open(config Config) (IPToCountry, error) {
+	if config.DB == "" {
+		return Unknown{}, nil
+	}
+	return OpenMaxmindDB(config.DB)
+}
+
+// Unknown is an IPToCountry which doesn't know the country of any address.
+type Unknown struct{}
+
+// LookupISOCountryCode implements IPToCountry.
+func (Unknown) LookupISOCountryCode(address string) (string, error) { return "", nil }
+
+// Close implements io.Closer.
+func (Unknown) Close() error { return nil }
+
+// MaxmindDB resolves countries with a MaxMind DB file, such as GeoLite2 Country.
+type MaxmindDB struct {
+	db *maxminddb.Reader
+}
+
+// ipInfo is the part of a MaxMind DB record containing the country.
+type ipInfo struct {
+	Country struct {
+		IsoCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// OpenMaxmindDB opens the MaxMind DB file at path.
+func OpenMaxmindDB(path string) (*MaxmindDB, error) {
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &MaxmindDB{db: db}, nil
+}
+
+// LookupISOCountryCode implements IPToCountry.
+//
+// address can be either an IP address or an IP address with a port.
+func (m *MaxmindDB) LookupISOCountryCode(address string) (string, error) {
+	ip := parseIP(address)
+	if ip == nil {
+		return "", Error.New("invalid IP address %q", address)
+	}
+
+	var info ipInfo
+	if err := m.db.Lookup(ip, &info); err != nil {
+		return "", Error.Wrap(err)
+	}
+	return info.Country.IsoCode, nil
+}
+
+// Close implements io.Closer.
+func (m *MaxmindDB) Close() error {
+	return Error.Wrap(m.db.Close())
+}
+
+// parseIP parses address either with or without a port.
+func parseIP(address string) net.IP {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	return net.ParseIP(address)
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package geoip_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/satellite/geoip"
+)
+
+func TestMaxmindDB(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	path := ctx.File("country.mmdb")
+	require.NoError(t, ioutil.WriteFile(path, writeMMDB(t, map[string]string{
+		"10.0.0.0/8":   "DE",
+		"192.0.2.0/24": "US",
+	}), 0644))
+
+	db, err := geoip.Open(geoip.Config{DB: path})
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+
+	for _, tt := range []struct {
+		address string
+		country string
+	}{
+		{"10.1.2.3", "DE"},
+		{"10.255.255.255:7777", "DE"},
+		{"11.0.0.0", ""},
+		{"9.255.255.255", ""},
+		{"192.0.2.10:28967", "US"},
+		{"192.0.3.1", ""},
+	} {
+		country, err := db.LookupISOCountryCode(tt.address)
+		require.NoError(t, err, tt.address)
+		require.Equal(t, tt.country, country, tt.address)
+	}
+
+	_, err = db.LookupISOCountryCode("not-an-ip")
+	require.Error(t, err)
+}
+
+func TestOpenInvalid(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db, err := geoip.Open(geoip.Config{})
+	require.NoError(t, err)
+	country, err := db.LookupISOCountryCode("10.1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, "", country)
+
+	path := ctx.File("invalid.mmdb")
+	require.NoError(t, ioutil.WriteFile(path, []byte("10.0.0.0/8,DE\n"), 0644))
+	_, err = geoip.Open(geoip.Config{DB: path})
+	require.Error(t, err)
+}
+
+// writeMMDB writes an IPv4 MaxMind DB with 24 bit records mapping the
+// networks to their countries. The networks must not overlap.
+func writeMMDB(t *testing.T, countries map[string]string) []byte {
+	const (
+		empty = -1
+		// data records are stored as -(dataBase + index)
+		dataBase = 2
+	)
+
+	nodes := [][2]int{{empty, empty}}
+	var data bytes.Buffer
+	var offsets []int
+
+	for cidr, country := range countries {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		ip := network.IP.To4()
+		ones, _ := network.Mask.Size()
+
+		offsets = append(offsets, data.Len())
+		writeMap(&data, 1)
+		writeString(&data, "country")
+		writeMap(&data, 1)
+		writeString(&data, "iso_code")
+		writeString(&data, country)
+
+		node := 0
+		for i := 0; i < ones; i++ {
+			bit := int(ip[i/8]>>(7-uint(i%8))) & 1
+			if i == ones-1 {
+				nodes[node][bit] = -(dataBase + len(offsets) - 1)
+				break
+			}
+			if nodes[node][bit] == empty {
+				nodes = append(nodes, [2]int{empty, empty})
+				nodes[node][bit] = len(nodes) - 1
+			}
+			node = nodes[node][bit]
+		}
+	}
+
+	var db bytes.Buffer
+	nodeCount := len(nodes)
+	for _, node := range nodes {
+		for _, record := range node {
+			value := record
+			switch {
+			case record == empty:
+				value = nodeCount
+			case record < 0:
+				value = nodeCount + 16 + offsets[-record-dataBase]
+			}
+			db.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+		}
+	}
+	db.Write(make([]byte, 16))
+	db.Write(data.Bytes())
+
+	db.WriteString("\xAB\xCD\xEFMaxMind.com")
+	writeMap(&db, 6)
+	writeString(&db, "node_count")
+	writeUint(&db, 6, uint64(nodeCount))
+	writeString(&db, "record_size")
+	writeUint(&db, 5, 24)
+	writeString(&db, "ip_version")
+	writeUint(&db, 5, 4)
+	writeString(&db, "binary_format_major_version")
+	writeUint(&db, 5, 2)
+	writeString(&db, "binary_format_minor_version")
+	writeUint(&db, 5, 0)
+	writeString(&db, "database_type")
+	writeString(&db, "Test-Country")
+
+	return db.Bytes()
+}
+
+func writeMap(buf *bytes.Buffer, size int) {
+	buf.WriteByte(7<<5 | byte(size))
+}
+
+func writeString(buf *bytes.Buffer, value string) {
+	buf.WriteByte(2<<5 | byte(len(value)))
+	buf.WriteString(value)
+}
+
+func writeUint(buf *bytes.Buffer, typ byte, value uint64) {
+	var encoded [8]byte
+	binary.BigEndian.PutUint64(encoded[:], value)
+	trimmed := bytes.TrimLeft(encoded[:], "\x00")
+	buf.WriteByte(typ<<5 | byte(len(trimmed)))
+	buf.Write(trimmed)
+}
@@ -53,14 +53,22 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 			return nil
 		}
 
+		shrinks, err := chore.db.GetUnfinishedShrinks(ctx)
+		if err != nil {
+			chore.log.Error("error retrieving nodes that have not finished shrinking", zap.Error(err))
+			return nil
+		}
+
 		nodeCount := len(exitingNodes)
-		if nodeCount == 0 {
+		if nodeCount == 0 && len(shrinks) == 0 {
 			return nil
 		}
-		chore.log.Debug("found exiting nodes", zap.Int("exitingNodes", nodeCount))
+		chore.log.Debug("found exiting nodes", zap.Int("exitingNodes", nodeCount), zap.Int("shrinkingNodes", len(shrinks)))
 
+		exiting := make(map[storj.NodeID]bool, nodeCount)
 		exitingNodesLoopIncomplete := make(storj.NodeIDList, 0, nodeCount)
 		for _, node := range exitingNodes {
+			exiting[node.NodeID] = true
 			if node.ExitLoopCompletedAt == nil {
 				exitingNodesLoopIncomplete = append(exitingNodesLoopIncomplete, node.NodeID)
 				continue
@@ -99,8 +107,25 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 			}
 		}
 
+		// the pieces of exiting nodes are all transferred, their shrinks are
+		// picked up again if the exit is cancelled.
+		shrinksLoopIncomplete := make([]*Shrink, 0, len(shrinks))
+		for _, shrink := range shrinks {
+			if exiting[shrink.NodeID] {
+				continue
+			}
+			if shrink.LoopCompletedAt == nil {
+				shrinksLoopIncomplete = append(shrinksLoopIncomplete, shrink)
+				continue
+			}
+			chore.checkShrinkActivity(ctx, shrink)
+		}
+
 		// Populate transfer queue for nodes that have not completed the exit loop yet
 		pathCollector := NewPathCollector(chore.db, exitingNodesLoopIncomplete, chore.log, chore.config.ChoreBatchSize)
+		for _, shrink := range shrinksLoopIncomplete {
+			pathCollector.AddShrink(shrink.NodeID, shrink.TargetBytes)
+		}
 		err = chore.metainfoLoop.Join(ctx, pathCollector)
 		if err != nil {
 			chore.log.Error("error joining metainfo loop.", zap.Error(err))
@@ -115,22 +140,61 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 
 		now := time.Now().UTC()
 		for _, nodeID := range exitingNodesLoopIncomplete {
-			exitStatus := overlay.ExitStatusRequest{
-				NodeID:              nodeID,
-				ExitLoopCompletedAt: now,
-			}
-			_, err = chore.overlay.UpdateExitStatus(ctx, &exitStatus)
+			// the exit may have been cancelled while the loop was running, the
+			// loop is only completed when the exit is still initiated.
+			completed, err := chore.overlay.CompleteExitLoop(ctx, nodeID, now)
 			if err != nil {
-				chore.log.Error("error updating exit status.", zap.Error(err))
+				chore.log.Error("error updating exit status.", zap.Stringer("Node ID", nodeID), zap.Error(err))
+				continue
+			}
+			if !completed {
+				err = chore.db.DeleteTransferQueueItems(ctx, nodeID)
+				if err != nil {
+					chore.log.Error("error deleting cancelled node from transfer queue.", zap.Stringer("Node ID", nodeID), zap.Error(err))
+				}
+				continue
 			}
 
 			bytesToTransfer := pathCollector.nodeIDStorage[nodeID]
 			mon.IntVal("graceful_exit_init_bytes_stored").Observe(bytesToTransfer)
 		}
+
+		for _, shrink := range shrinksLoopIncomplete {
+			bytesToTransfer := pathCollector.nodeIDStorage[shrink.NodeID]
+			err = chore.db.CompleteShrinkLoop(ctx, shrink.NodeID, bytesToTransfer, now)
+			if err != nil {
+				chore.log.Error("error updating shrink status.", zap.Stringer("Node ID", shrink.NodeID), zap.Error(err))
+				continue
+			}
+			mon.IntVal("graceful_exit_shrink_bytes_queued").Observe(bytesToTransfer)
+		}
 		return nil
 	})
 }
 
+// checkShrinkActivity finishes the shrink of a node which hasn't transferred
+// any piece within the inactive time frame.
+func (chore *Chore) checkShrinkActivity(ctx context.Context, shrink *Shrink) {
+	progress, err := chore.db.GetProgress(ctx, shrink.NodeID)
+	if err != nil && !ErrNodeNotFound.Has(err) {
+		chore.log.Error("error retrieving progress for node", zap.Stringer("Node ID", shrink.NodeID), zap.Error(err))
+		return
+	}
+
+	lastActivityTime := *shrink.LoopCompletedAt
+	if progress != nil {
+		lastActivityTime = progress.UpdatedAt
+	}
+
+	if lastActivityTime.Add(chore.config.MaxInactiveTimeFrame).Before(time.Now().UTC()) {
+		mon.Meter("graceful_exit_shrink_inactive").Mark(1)
+		err = finishShrink(ctx, chore.db, shrink.NodeID)
+		if err != nil {
+			chore.log.Error("error finishing inactive shrink", zap.Stringer("Node ID", shrink.NodeID), zap.Error(err))
+		}
+	}
+}
+
 // Close closes chore.
 func (chore *Chore) Close() error {
 	chore.Loop.Close()
@@ -35,6 +35,18 @@ type TransferQueueItem struct {
 	OrderLimitSendCount int
 }
 
+// Shrink represents the persisted partial graceful exit of a node. Pieces of
+// the node are transferred until TargetBytes are moved, the node stays active.
+type Shrink struct {
+	NodeID           storj.NodeID
+	TargetBytes      int64
+	QueuedBytes      int64
+	BytesTransferred int64
+	CreatedAt        time.Time
+	LoopCompletedAt  *time.Time
+	FinishedAt       *time.Time
+}
+
 // DB implements CRUD operations for graceful exit service
 //
 // architecture: Database
@@ -43,6 +55,8 @@ type DB interface {
 	IncrementProgress(ctx context.Context, nodeID storj.NodeID, bytes int64, successfulTransfers int64, failedTransfers int64) error
 	// GetProgress gets a graceful exit progress entry.
 	GetProgress(ctx context.Context, nodeID storj.NodeID) (*Progress, error)
+	// DeleteProgress deletes the graceful exit progress entry of a node.
+	DeleteProgress(ctx context.Context, nodeID storj.NodeID) error
 
 	// Enqueue batch inserts graceful exit transfer queue entries it does not exist.
 	Enqueue(ctx context.Context, items []TransferQueueItem) error
@@ -64,4 +78,15 @@ type DB interface {
 	GetIncompleteFailed(ctx context.Context, nodeID storj.NodeID, maxFailures int, limit int, offset int64) ([]*TransferQueueItem, error)
 	// IncrementOrderLimitSendCount increments the number of times a node has been sent an order limit for transferring.
 	IncrementOrderLimitSendCount(ctx context.Context, nodeID storj.NodeID, path []byte, pieceNum int32) error
+
+	// InitiateShrink creates a shrink of the node, unless it has an unfinished one.
+	InitiateShrink(ctx context.Context, nodeID storj.NodeID, targetBytes int64) (initiated bool, err error)
+	// GetShrink gets the last shrink of the node.
+	GetShrink(ctx context.Context, nodeID storj.NodeID) (*Shrink, error)
+	// GetUnfinishedShrinks gets the shrinks which haven't finished.
+	GetUnfinishedShrinks(ctx context.Context) ([]*Shrink, error)
+	// CompleteShrinkLoop records the bytes queued for the shrink of the node.
+	CompleteShrinkLoop(ctx context.Context, nodeID storj.NodeID, queuedBytes int64, completedAt time.Time) error
+	// FinishShrink marks the shrink of the node finished with the bytes transferred so far, and deletes its transfer progress and queue items.
+	FinishShrink(ctx context.Context, nodeID storj.NodeID, finishedAt time.Time) error
 }
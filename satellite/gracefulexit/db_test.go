@@ -187,3 +187,60 @@ func TestTransferQueueItem(t *testing.T) {
 		require.Equal(t, 1, item.OrderLimitSendCount)
 	})
 }
+
+func TestShrink(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		geDB := db.GracefulExit()
+		nodeID := testrand.NodeID()
+
+		_, err := geDB.GetShrink(ctx, nodeID)
+		require.True(t, gracefulexit.ErrNodeNotFound.Has(err))
+
+		initiated, err := geDB.InitiateShrink(ctx, nodeID, 10*memory.MiB.Int64())
+		require.NoError(t, err)
+		require.True(t, initiated)
+
+		// only one shrink can be in progress.
+		initiated, err = geDB.InitiateShrink(ctx, nodeID, memory.MiB.Int64())
+		require.NoError(t, err)
+		require.False(t, initiated)
+
+		shrinks, err := geDB.GetUnfinishedShrinks(ctx)
+		require.NoError(t, err)
+		require.Len(t, shrinks, 1)
+		require.Equal(t, nodeID, shrinks[0].NodeID)
+		require.Equal(t, 10*memory.MiB.Int64(), shrinks[0].TargetBytes)
+		require.Nil(t, shrinks[0].LoopCompletedAt)
+
+		require.NoError(t, geDB.CompleteShrinkLoop(ctx, nodeID, 9*memory.MiB.Int64(), time.Now()))
+		require.NoError(t, geDB.IncrementProgress(ctx, nodeID, 8*memory.MiB.Int64(), 1, 0))
+		require.NoError(t, geDB.FinishShrink(ctx, nodeID, time.Now()))
+
+		// the progress of the shrink is removed.
+		_, err = geDB.GetProgress(ctx, nodeID)
+		require.True(t, gracefulexit.ErrNodeNotFound.Has(err))
+
+		shrink, err := geDB.GetShrink(ctx, nodeID)
+		require.NoError(t, err)
+		require.Equal(t, 9*memory.MiB.Int64(), shrink.QueuedBytes)
+		require.Equal(t, 8*memory.MiB.Int64(), shrink.BytesTransferred)
+		require.NotNil(t, shrink.LoopCompletedAt)
+		require.NotNil(t, shrink.FinishedAt)
+
+		shrinks, err = geDB.GetUnfinishedShrinks(ctx)
+		require.NoError(t, err)
+		require.Empty(t, shrinks)
+
+		// a finished shrink is replaced by a new one.
+		initiated, err = geDB.InitiateShrink(ctx, nodeID, memory.MiB.Int64())
+		require.NoError(t, err)
+		require.True(t, initiated)
+
+		shrink, err = geDB.GetShrink(ctx, nodeID)
+		require.NoError(t, err)
+		require.Equal(t, memory.MiB.Int64(), shrink.TargetBytes)
+		require.Zero(t, shrink.QueuedBytes)
+		require.Nil(t, shrink.LoopCompletedAt)
+		require.Nil(t, shrink.FinishedAt)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"storj.io/common/signing"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/pkg/gracefulexitpb"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
@@ -110,6 +111,22 @@ func (endpoint *Endpoint) Process(stream pb.DRPCSatelliteGracefulExit_ProcessStr
 	ctx := stream.Context()
 	defer mon.Task()(&ctx)(&err)
 
+	return endpoint.process(ctx, stream, false)
+}
+
+// ProcessShrink is called by shrinking storage nodes to receive the pieces
+// queued for the shrink to transfer to new nodes. Unlike Process, it never
+// initiates a graceful exit.
+func (endpoint *Endpoint) ProcessShrink(stream pb.DRPCSatelliteGracefulExit_ProcessStream) (err error) {
+	ctx := stream.Context()
+	defer mon.Task()(&ctx)(&err)
+
+	return endpoint.process(ctx, stream, true)
+}
+
+// process transfers the pieces of the graceful exit, or of the shrink when
+// shrinking is set.
+func (endpoint *Endpoint) process(ctx context.Context, stream pb.DRPCSatelliteGracefulExit_ProcessStream, shrinking bool) (err error) {
 	peer, err := identity.PeerIdentityFromContext(ctx)
 	if err != nil {
 		return rpcstatus.Error(rpcstatus.Unauthenticated, Error.Wrap(err).Error())
@@ -134,12 +151,25 @@ func (endpoint *Endpoint) Process(stream pb.DRPCSatelliteGracefulExit_ProcessStr
 		return rpcstatus.Error(rpcstatus.FailedPrecondition, "Disqualified nodes cannot graceful exit")
 	}
 
-	msg, err := endpoint.checkExitStatus(ctx, nodeID)
-	if err != nil {
-		if ErrIneligibleNodeAge.Has(err) {
-			return rpcstatus.Error(rpcstatus.FailedPrecondition, err.Error())
+	var msg *pb.SatelliteMessage
+	if shrinking {
+		// a shrinking node transfers its queued pieces without exiting
+		var inProgress bool
+		inProgress, msg, err = endpoint.checkShrinkStatus(ctx, nodeID)
+		if err != nil {
+			return rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+		if !inProgress {
+			return rpcstatus.Error(rpcstatus.FailedPrecondition, "No shrink in progress")
+		}
+	} else {
+		msg, err = endpoint.checkExitStatus(ctx, nodeID)
+		if err != nil {
+			if ErrIneligibleNodeAge.Has(err) {
+				return rpcstatus.Error(rpcstatus.FailedPrecondition, err.Error())
+			}
+			return rpcstatus.Error(rpcstatus.Internal, err.Error())
 		}
-		return rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
 	if msg != nil {
@@ -214,6 +244,15 @@ func (endpoint *Endpoint) Process(stream pb.DRPCSatelliteGracefulExit_ProcessStr
 		}
 
 		// if there is no more work to receive send complete
+		if finished && shrinking {
+			// the node stays active, closing the stream ends the shrink
+			err = finishShrink(ctx, endpoint.db, nodeID)
+			if err != nil {
+				return rpcstatus.Error(rpcstatus.Internal, err.Error())
+			}
+			mon.Meter("graceful_exit_shrink_success").Mark(1)
+			break
+		}
 		if finished {
 			isDisqualified, err := endpoint.handleDisqualifiedNode(ctx, nodeID)
 			if err != nil {
@@ -271,6 +310,16 @@ func (endpoint *Endpoint) Process(stream pb.DRPCSatelliteGracefulExit_ProcessStr
 
 					continue
 				}
+				if ErrInvalidArgument.Has(err) && shrinking {
+					// immediately stop the shrink of nodes that fail satellite validation
+					mon.Meter("graceful_exit_shrink_fail_validation").Mark(1)
+
+					err = finishShrink(ctx, endpoint.db, nodeID)
+					if err != nil {
+						return rpcstatus.Error(rpcstatus.Internal, err.Error())
+					}
+					return rpcstatus.Error(rpcstatus.FailedPrecondition, "Shrink stopped after a failed transfer verification")
+				}
 				if ErrInvalidArgument.Has(err) {
 					// immediately fail and complete graceful exit for nodes that fail satellite validation
 					err = endpoint.db.IncrementProgress(ctx, nodeID, 0, 0, 1)
@@ -307,6 +356,134 @@ func (endpoint *Endpoint) Process(stream pb.DRPCSatelliteGracefulExit_ProcessStr
 	return nil
 }
 
+// CancelGracefulExit is called by storage nodes to cancel their graceful exit.
+// The exit can only be cancelled until the exit loop has collected the pieces
+// of the node, and while the node isn't processing it.
+func (endpoint *Endpoint) CancelGracefulExit(ctx context.Context, req *gracefulexitpb.CancelGracefulExitRequest) (_ *gracefulexitpb.CancelGracefulExitResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, Error.Wrap(err).Error())
+	}
+
+	nodeID := peer.ID
+	endpoint.log.Debug("cancel graceful exit", zap.Stringer("Node ID", nodeID))
+
+	// the exit must not be re-initiated by a concurrent process call
+	if !endpoint.connections.tryAdd(nodeID) {
+		return nil, rpcstatus.Error(rpcstatus.Aborted, "Graceful exit can't be cancelled while it's being processed")
+	}
+	defer endpoint.connections.delete(nodeID)
+
+	exitStatus, err := endpoint.overlaydb.GetExitStatus(ctx, nodeID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if exitStatus.ExitInitiatedAt == nil {
+		// the node hasn't contacted the satellite since initiating the exit
+		return &gracefulexitpb.CancelGracefulExitResponse{}, nil
+	}
+
+	canceled, err := endpoint.overlaydb.CancelExit(ctx, nodeID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if !canceled {
+		return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, "Graceful exit can't be cancelled after its exit loop has completed")
+	}
+
+	err = endpoint.db.DeleteTransferQueueItems(ctx, nodeID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	err = endpoint.db.DeleteProgress(ctx, nodeID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	mon.Meter("graceful_exit_cancel").Mark(1)
+	return &gracefulexitpb.CancelGracefulExitResponse{}, nil
+}
+
+// InitiateShrink is called by storage nodes to transfer the target bytes of
+// their pieces to other nodes without exiting. The chore queues the pieces,
+// which the node transfers through Process.
+func (endpoint *Endpoint) InitiateShrink(ctx context.Context, req *gracefulexitpb.InitiateShrinkRequest) (_ *gracefulexitpb.InitiateShrinkResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, Error.Wrap(err).Error())
+	}
+
+	nodeID := peer.ID
+	endpoint.log.Debug("initiate shrink", zap.Stringer("Node ID", nodeID), zap.Int64("target bytes", req.TargetBytes))
+
+	if req.TargetBytes <= 0 {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "target bytes must be positive")
+	}
+
+	node, err := endpoint.overlaydb.Get(ctx, nodeID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if node.Disqualified != nil {
+		return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, "Disqualified nodes cannot shrink")
+	}
+	if node.ExitStatus.ExitInitiatedAt != nil {
+		return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, "Exiting nodes cannot shrink")
+	}
+
+	initiated, err := endpoint.db.InitiateShrink(ctx, nodeID, req.TargetBytes)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if !initiated {
+		return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, "Only one shrink can be in progress")
+	}
+
+	mon.Meter("graceful_exit_shrink_init").Mark(1)
+	return &gracefulexitpb.InitiateShrinkResponse{}, nil
+}
+
+// GetShrinkStatus is called by storage nodes to get the status of their last shrink.
+func (endpoint *Endpoint) GetShrinkStatus(ctx context.Context, req *gracefulexitpb.GetShrinkStatusRequest) (_ *gracefulexitpb.GetShrinkStatusResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, Error.Wrap(err).Error())
+	}
+
+	shrink, err := endpoint.db.GetShrink(ctx, peer.ID)
+	if err != nil {
+		if ErrNodeNotFound.Has(err) {
+			return nil, rpcstatus.Error(rpcstatus.NotFound, "No shrink was initiated")
+		}
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	bytesTransferred := shrink.BytesTransferred
+	if shrink.FinishedAt == nil {
+		progress, err := endpoint.db.GetProgress(ctx, peer.ID)
+		switch {
+		case err == nil:
+			bytesTransferred = progress.BytesTransferred
+		case !ErrNodeNotFound.Has(err):
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+	}
+
+	return &gracefulexitpb.GetShrinkStatusResponse{
+		TargetBytes:      shrink.TargetBytes,
+		QueuedBytes:      shrink.QueuedBytes,
+		BytesTransferred: bytesTransferred,
+		Queued:           shrink.LoopCompletedAt != nil,
+		Finished:         shrink.FinishedAt != nil,
+	}, nil
+}
+
 func (endpoint *Endpoint) processIncomplete(ctx context.Context, stream pb.DRPCSatelliteGracefulExit_ProcessStream, pending *PendingMap, incomplete *TransferQueueItem) error {
 	nodeID := incomplete.NodeID
 
@@ -368,10 +545,16 @@ func (endpoint *Endpoint) processIncomplete(ctx context.Context, stream pb.DRPCS
 		excludedIDs[i] = piece.NodeId
 	}
 
+	placement, err := endpoint.metainfo.GetPlacementForPath(ctx, string(incomplete.Path))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
 	// get replacement node
 	request := &overlay.FindStorageNodesRequest{
 		RequestedCount: 1,
 		ExcludedIDs:    excludedIDs,
+		Placement:      placement,
 	}
 
 	newNodes, err := endpoint.overlay.FindStorageNodesForGracefulExit(ctx, *request)
@@ -805,6 +988,35 @@ func (endpoint *Endpoint) checkExitStatus(ctx context.Context, nodeID storj.Node
 	return nil, nil
 }
 
+// checkShrinkStatus returns whether the node is shrinking, i.e. it has an
+// unfinished shrink and hasn't initiated a graceful exit. If the pieces of
+// the shrinking node aren't queued yet, it will return a not ready message.
+func (endpoint *Endpoint) checkShrinkStatus(ctx context.Context, nodeID storj.NodeID) (shrinking bool, _ *pb.SatelliteMessage, err error) {
+	shrink, err := endpoint.db.GetShrink(ctx, nodeID)
+	if err != nil {
+		if ErrNodeNotFound.Has(err) {
+			return false, nil, nil
+		}
+		return false, nil, Error.Wrap(err)
+	}
+	if shrink.FinishedAt != nil {
+		return false, nil, nil
+	}
+
+	exitStatus, err := endpoint.overlaydb.GetExitStatus(ctx, nodeID)
+	if err != nil {
+		return false, nil, Error.Wrap(err)
+	}
+	if exitStatus.ExitInitiatedAt != nil {
+		return false, nil, nil
+	}
+
+	if shrink.LoopCompletedAt == nil {
+		return true, &pb.SatelliteMessage{Message: &pb.SatelliteMessage_NotReady{NotReady: &pb.NotReady{}}}, nil
+	}
+	return true, nil, nil
+}
+
 func (endpoint *Endpoint) generateExitStatusRequest(ctx context.Context, nodeID storj.NodeID) (*overlay.ExitStatusRequest, pb.ExitFailed_Reason, error) {
 	var exitFailedReason pb.ExitFailed_Reason = -1
 	progress, err := endpoint.db.GetProgress(ctx, nodeID)
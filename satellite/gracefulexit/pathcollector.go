@@ -25,6 +25,7 @@ type PathCollector struct {
 	db            DB
 	nodeIDMutex   sync.Mutex
 	nodeIDStorage map[storj.NodeID]int64
+	shrinkTargets map[storj.NodeID]int64
 	buffer        []TransferQueueItem
 	log           *zap.Logger
 	batchSize     int
@@ -50,6 +51,19 @@ func NewPathCollector(db DB, nodeIDs storj.NodeIDList, log *zap.Logger, batchSiz
 	return collector
 }
 
+// AddShrink adds a shrinking node, whose pieces are only collected until
+// their size reaches targetBytes.
+func (collector *PathCollector) AddShrink(nodeID storj.NodeID, targetBytes int64) {
+	if collector.nodeIDStorage == nil {
+		collector.nodeIDStorage = make(map[storj.NodeID]int64)
+	}
+	if collector.shrinkTargets == nil {
+		collector.shrinkTargets = make(map[storj.NodeID]int64)
+	}
+	collector.nodeIDStorage[nodeID] = 0
+	collector.shrinkTargets[nodeID] = targetBytes
+}
+
 // Flush persists the current buffer items to the database.
 func (collector *PathCollector) Flush(ctx context.Context) (err error) {
 	return collector.flush(ctx, 1)
@@ -69,6 +83,9 @@ func (collector *PathCollector) RemoteSegment(ctx context.Context, path metainfo
 		if _, ok := collector.nodeIDStorage[piece.NodeId]; !ok {
 			continue
 		}
+		if target, ok := collector.shrinkTargets[piece.NodeId]; ok && collector.nodeIDStorage[piece.NodeId] >= target {
+			continue
+		}
 		redundancy, err := eestream.NewRedundancyStrategyFromProto(pointer.GetRemote().GetRedundancy())
 		if err != nil {
 			return err
@@ -0,0 +1,20 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+	"time"
+
+	"storj.io/common/storj"
+)
+
+// finishShrink marks the shrink of the node finished with the bytes
+// transferred so far, and removes its transfer progress and remaining queue
+// items, so that they don't count towards a later graceful exit.
+func finishShrink(ctx context.Context, db DB, nodeID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return Error.Wrap(db.FinishShrink(ctx, nodeID, time.Now().UTC()))
+}
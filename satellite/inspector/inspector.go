@@ -14,8 +14,10 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/storj/pkg/inspectorpb"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/repair/queue"
 )
 
 var (
@@ -30,17 +32,19 @@ const lastSegmentIndex = int64(-1)
 //
 // architecture: Endpoint
 type Endpoint struct {
-	log      *zap.Logger
-	overlay  *overlay.Service
-	metainfo *metainfo.Service
+	log         *zap.Logger
+	overlay     *overlay.Service
+	metainfo    *metainfo.Service
+	repairQueue queue.RepairQueue
 }
 
 // NewEndpoint will initialize an Endpoint struct
-func NewEndpoint(log *zap.Logger, cache *overlay.Service, metainfo *metainfo.Service) *Endpoint {
+func NewEndpoint(log *zap.Logger, cache *overlay.Service, metainfo *metainfo.Service, repairQueue queue.RepairQueue) *Endpoint {
 	return &Endpoint{
-		log:      log,
-		overlay:  cache,
-		metainfo: metainfo,
+		log:         log,
+		overlay:     cache,
+		metainfo:    metainfo,
+		repairQueue: repairQueue,
 	}
 }
 
@@ -185,3 +189,33 @@ func (endpoint *Endpoint) SegmentHealth(ctx context.Context, in *pb.SegmentHealt
 		Redundancy: pointer.GetRemote().GetRedundancy(),
 	}, nil
 }
+
+// SegmentRepairHistory returns the failed repair attempts of a segment which
+// is in the repair queue
+func (endpoint *Endpoint) SegmentRepairHistory(ctx context.Context, in *inspectorpb.SegmentRepairHistoryRequest) (resp *inspectorpb.SegmentRepairHistoryResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	projectID, err := uuid.FromString(string(in.ProjectId))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	path, err := metainfo.CreatePath(ctx, projectID, in.SegmentIndex, in.Bucket, in.EncryptedPath)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	attempts, err := endpoint.repairQueue.Attempts(ctx, []byte(path))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	resp = &inspectorpb.SegmentRepairHistoryResponse{}
+	for _, attempt := range attempts {
+		resp.Attempts = append(resp.Attempts, &inspectorpb.RepairAttempt{
+			AttemptedAt:   attempt.AttemptedAt,
+			FailureReason: attempt.FailureReason,
+		})
+	}
+	return resp, nil
+}
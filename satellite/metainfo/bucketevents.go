@@ -0,0 +1,131 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/bucketevents"
+)
+
+// enqueueObjectCreated adds an object created event, for the object created
+// at createdAt, to the outbox of the bucket subscriptions. It's called before
+// any pointer of the object is changed and its error fails the commit, so that
+// no event is lost after a commit and a failed enqueue leaves the upload as
+// it was. The event gets the size of the object when it's delivered, and is
+// dropped when the object wasn't committed, see bucketEventObjects.
+func (endpoint *Endpoint) enqueueObjectCreated(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, createdAt, expiresAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	event := bucketevents.Event{
+		Type:         bucketevents.ObjectCreated.String(),
+		ProjectID:    projectID,
+		Bucket:       string(bucket),
+		EncryptedKey: encryptedPath,
+		OccurredAt:   time.Now(),
+	}
+	if !createdAt.IsZero() {
+		event.CreatedAt = &createdAt
+	}
+	if !expiresAt.IsZero() {
+		event.ExpiresAt = &expiresAt
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return endpoint.bucketEvents.Enqueue(ctx, projectID, bucket, bucketevents.ObjectCreated, payload)
+}
+
+// publishObjectDeleted adds an object deleted event to the outbox of the
+// bucket subscriptions. Failing to publish doesn't fail the deletion.
+func (endpoint *Endpoint) publishObjectDeleted(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) {
+	defer mon.Task()(&ctx)(nil)
+
+	endpoint.publishBucketEvent(ctx, bucketevents.ObjectDeleted, bucketevents.Event{
+		Type:         bucketevents.ObjectDeleted.String(),
+		ProjectID:    projectID,
+		Bucket:       string(bucket),
+		EncryptedKey: encryptedPath,
+		OccurredAt:   time.Now(),
+	})
+}
+
+func (endpoint *Endpoint) publishBucketEvent(ctx context.Context, eventType bucketevents.EventType, event bucketevents.Event) {
+	defer mon.Task()(&ctx)(nil)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		endpoint.log.Error("unable to marshal bucket event", zap.Error(err))
+		return
+	}
+
+	err = endpoint.bucketEvents.Enqueue(ctx, event.ProjectID, []byte(event.Bucket), eventType, payload)
+	if err != nil {
+		endpoint.log.Error("unable to publish bucket event",
+			zap.Stringer("Project ID", event.ProjectID),
+			zap.Stringer("Event Type", eventType),
+			zap.Error(err))
+	}
+}
+
+// bucketEventObjects looks up the objects of the object created events.
+type bucketEventObjects struct {
+	metainfo *Service
+}
+
+// NewBucketEventObjects returns the lookup of the objects of the object
+// created events in the pointerdb.
+func NewBucketEventObjects(metainfo *Service) bucketevents.Objects {
+	return &bucketEventObjects{metainfo: metainfo}
+}
+
+// Size returns the size of the object committed at createdAt, or
+// bucketevents.ErrNotCommitted when there isn't one.
+func (objects *bucketEventObjects) Size(ctx context.Context, projectID uuid.UUID, bucket, encryptedKey []byte, createdAt time.Time) (size int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path, err := CreatePath(ctx, projectID, lastSegment, bucket, encryptedKey)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	pointer, err := objects.metainfo.Get(ctx, path)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return 0, bucketevents.ErrNotCommitted.New("%q", path)
+		}
+		return 0, err
+	}
+	// the object may have been replaced by another upload
+	if !createdAt.IsZero() && !pointer.CreationDate.Equal(createdAt) {
+		return 0, bucketevents.ErrNotCommitted.New("%q", path)
+	}
+
+	streamMeta := pb.StreamMeta{}
+	if err := pb.Unmarshal(pointer.Metadata, &streamMeta); err != nil {
+		return 0, Error.Wrap(err)
+	}
+
+	size = pointer.SegmentSize
+	for index := int64(0); index < streamMeta.NumberOfSegments-1; index++ {
+		path, err := CreatePath(ctx, projectID, index, bucket, encryptedKey)
+		if err != nil {
+			return 0, Error.Wrap(err)
+		}
+		segment, err := objects.metainfo.Get(ctx, path)
+		if err != nil {
+			return 0, err
+		}
+		size += segment.SegmentSize
+	}
+	return size, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/accounting"
+)
+
+// checkBucketStorageLimit returns a ResourceExhausted error when the bucket
+// has a storage limit and its storage usage is over it.
+func (endpoint *Endpoint) checkBucketStorageLimit(ctx context.Context, projectID uuid.UUID, bucket []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limits, err := endpoint.getBucketLimits(ctx, projectID, bucket)
+	if err != nil {
+		endpoint.log.Error("Retrieving bucket limits failed.", zap.Error(err))
+		return nil
+	}
+
+	exceeded, limit, err := endpoint.projectUsage.ExceedsBucketStorageUsage(ctx, projectID, bucket, limits)
+	if err != nil {
+		endpoint.log.Error("Retrieving bucket storage totals failed.", zap.Error(err))
+		return nil
+	}
+	if exceeded {
+		endpoint.log.Error("Bucket storage limit exceeded.",
+			zap.Stringer("Limit", limit),
+			zap.Stringer("Project ID", projectID),
+			zap.ByteString("Bucket", bucket),
+		)
+		return rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Bucket Usage Limit")
+	}
+	return nil
+}
+
+// checkBucketBandwidthLimit returns a ResourceExhausted error when the bucket
+// has a bandwidth limit and its bandwidth usage of the month is over it.
+func (endpoint *Endpoint) checkBucketBandwidthLimit(ctx context.Context, projectID uuid.UUID, bucket []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limits, err := endpoint.getBucketLimits(ctx, projectID, bucket)
+	if err != nil {
+		endpoint.log.Error("Retrieving bucket limits failed.", zap.Error(err))
+		return nil
+	}
+
+	exceeded, limit, err := endpoint.projectUsage.ExceedsBucketBandwidthUsage(ctx, projectID, bucket, limits)
+	if err != nil {
+		endpoint.log.Error("Retrieving bucket bandwidth total failed.", zap.Error(err))
+		return nil
+	}
+	if exceeded {
+		endpoint.log.Error("Monthly bucket bandwidth limit exceeded.",
+			zap.Stringer("Limit", limit),
+			zap.Stringer("Project ID", projectID),
+			zap.ByteString("Bucket", bucket),
+		)
+		return rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Bucket Usage Limit")
+	}
+	return nil
+}
+
+// getBucketLimits returns the limits of the bucket. They are cached, because
+// they are checked for every segment of the uploads and the downloads.
+func (endpoint *Endpoint) getBucketLimits(ctx context.Context, projectID uuid.UUID, bucket []byte) (_ accounting.BucketLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limits, err := endpoint.bucketLimitsCache.Get(projectID.String()+"/"+string(bucket), func() (interface{}, error) {
+		return endpoint.projectUsage.GetBucketLimits(ctx, projectID, bucket)
+	})
+	if err != nil {
+		return accounting.BucketLimits{}, err
+	}
+	return limits.(accounting.BucketLimits), nil
+}
+
+// addBucketStorageUsage lets the live accounting know that the bucket has
+// just added spaceUsed bytes of storage.
+func (endpoint *Endpoint) addBucketStorageUsage(ctx context.Context, projectID uuid.UUID, bucket []byte, spaceUsed int64) {
+	if err := endpoint.projectUsage.AddBucketStorageUsage(ctx, projectID, bucket, spaceUsed); err != nil {
+		endpoint.log.Error("Could not track new storage usage by bucket",
+			zap.Stringer("Project ID", projectID),
+			zap.ByteString("Bucket", bucket),
+			zap.Error(err),
+		)
+		// but continue. it's most likely our own fault that we couldn't track it, and the only thing
+		// that will be affected is our per-bucket storage limits.
+	}
+}
@@ -46,18 +46,43 @@ type RateLimiterConfig struct {
 	CacheExpiration time.Duration `help:"how long to cache the projects limiter." releaseDefault:"10m" devDefault:"10s"`
 }
 
+// OwnerStatusConfig is a configuration struct for the check of the status of
+// the project owners, which rejects the API keys of frozen users.
+type OwnerStatusConfig struct {
+	CacheCapacity   int           `help:"number of project owner statuses to cache." releaseDefault:"10000" devDefault:"10"`
+	CacheExpiration time.Duration `help:"how long to cache the project owner statuses, i.e. how long the API keys of a frozen user keep working." releaseDefault:"1m" devDefault:"10s"`
+}
+
+// RevocationsConfig is a configuration struct for the cache of the revoked
+// API keys of the projects.
+type RevocationsConfig struct {
+	CacheCapacity   int           `help:"number of project revocation lists to cache." releaseDefault:"10000" devDefault:"10"`
+	CacheExpiration time.Duration `help:"how long to cache the project revocation lists, i.e. how long a revoked API key keeps working." releaseDefault:"1m" devDefault:"10s"`
+}
+
+// BucketLimitsConfig is a configuration struct for the cache of the storage
+// and bandwidth limits of the buckets, which are checked for every segment.
+type BucketLimitsConfig struct {
+	CacheCapacity   int           `help:"number of bucket limits to cache." releaseDefault:"10000" devDefault:"10"`
+	CacheExpiration time.Duration `help:"how long to cache the bucket limits, i.e. how long a bucket keeps its previous limits after they are updated." releaseDefault:"1m" devDefault:"10s"`
+}
+
 // Config is a configuration struct that is everything you need to start a metainfo
 type Config struct {
-	DatabaseURL          string               `help:"the database connection string to use" default:"postgres://"`
-	MinRemoteSegmentSize memory.Size          `default:"1240" help:"minimum remote segment size"`
-	MaxInlineSegmentSize memory.Size          `default:"4KiB" help:"maximum inline segment size"`
-	MaxSegmentSize       memory.Size          `default:"64MiB" help:"maximum segment size"`
-	MaxCommitInterval    time.Duration        `default:"48h" help:"maximum time allowed to pass between creating and committing a segment"`
-	Overlay              bool                 `default:"true" help:"toggle flag if overlay is enabled"`
-	RS                   RSConfig             `help:"redundancy scheme configuration"`
-	Loop                 LoopConfig           `help:"loop configuration"`
-	RateLimiter          RateLimiterConfig    `help:"rate limiter configuration"`
-	PieceDeletion        piecedeletion.Config `help:"piece deletion configuration"`
+	DatabaseURL             string               `help:"the database connection string to use" default:"postgres://"`
+	MinRemoteSegmentSize    memory.Size          `default:"1240" help:"minimum remote segment size"`
+	MaxInlineSegmentSize    memory.Size          `default:"4KiB" help:"maximum inline segment size"`
+	MaxSegmentSize          memory.Size          `default:"64MiB" help:"maximum segment size"`
+	MaxCommitInterval       time.Duration        `default:"48h" help:"maximum time allowed to pass between creating and committing a segment"`
+	MaxObjectCommitInterval time.Duration        `default:"48h" help:"maximum time allowed to pass between beginning and committing an object, at most 48h, the segments of older uncommitted objects are deleted"`
+	Overlay                 bool                 `default:"true" help:"toggle flag if overlay is enabled"`
+	RS                      RSConfig             `help:"redundancy scheme configuration"`
+	Loop                    LoopConfig           `help:"loop configuration"`
+	RateLimiter             RateLimiterConfig    `help:"rate limiter configuration"`
+	OwnerStatus             OwnerStatusConfig    `help:"project owner status check configuration"`
+	Revocations             RevocationsConfig    `help:"api key revocations configuration"`
+	BucketLimits            BucketLimitsConfig   `help:"bucket limits configuration"`
+	PieceDeletion           piecedeletion.Config `help:"piece deletion configuration"`
 }
 
 // PointerDB stores pointers.
@@ -9,6 +9,7 @@ import (
 	"storj.io/common/macaroon"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/storj/satellite/overlay"
 )
 
 // BucketsDB is the interface for the database to interact with buckets
@@ -25,4 +26,27 @@ type BucketsDB interface {
 	DeleteBucket(ctx context.Context, bucketName []byte, projectID uuid.UUID) (err error)
 	// List returns all buckets for a project
 	ListBuckets(ctx context.Context, projectID uuid.UUID, listOpts storj.BucketListOptions, allowedBuckets macaroon.AllowedBuckets) (bucketList storj.BucketList, err error)
+	// GetBucketPlacement returns the placement constraint of a bucket
+	GetBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID) (placement overlay.PlacementConstraint, err error)
+	// UpdateBucketPlacement changes the placement constraint of a bucket
+	UpdateBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID, placement overlay.PlacementConstraint) (err error)
+	// GetBucketVersioning returns the versioning state of a bucket
+	GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (versioning Versioning, err error)
+	// UpdateBucketVersioning changes the versioning state of a bucket
+	UpdateBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, versioning Versioning) (err error)
+}
+
+// PieceReferencesDB counts the pointers which share the pieces of a root
+// piece ID, because the object they belong to has been copied.
+//
+// Pieces without references recorded are only referenced by a single
+// pointer.
+//
+// architecture: Database
+type PieceReferencesDB interface {
+	// Add records that one more pointer references the pieces of rootPieceID.
+	Add(ctx context.Context, rootPieceID storj.PieceID) (err error)
+	// Release records that a pointer referencing the pieces of rootPieceID has
+	// been deleted and returns whether other pointers still reference them.
+	Release(ctx context.Context, rootPieceID storj.PieceID) (referenced bool, err error)
 }
@@ -194,3 +194,31 @@ func TestListBucketsNotAllowed(t *testing.T) {
 		}
 	})
 }
+
+func TestPieceReferences(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		references := db.PieceReferences()
+
+		rootPieceID := testrand.PieceID()
+
+		// pieces which have never been copied aren't shared
+		referenced, err := references.Release(ctx, rootPieceID)
+		require.NoError(t, err)
+		require.False(t, referenced)
+
+		// the original pointer and two copies
+		require.NoError(t, references.Add(ctx, rootPieceID))
+		require.NoError(t, references.Add(ctx, rootPieceID))
+
+		for i := 0; i < 2; i++ {
+			referenced, err = references.Release(ctx, rootPieceID)
+			require.NoError(t, err)
+			require.True(t, referenced)
+		}
+
+		// the last pointer owns the pieces again
+		referenced, err = references.Release(ctx, rootPieceID)
+		require.NoError(t, err)
+		require.False(t, referenced)
+	})
+}
@@ -332,7 +332,11 @@ func iterateDatabase(ctx context.Context, db PointerDB, observers []*observerCon
 				continue nextSegment
 			}
 
-			isLastSegment := pathElements[1] == "l"
+			isLastSegment := IsLastSegment(pathElements[1])
+			if pathElements[1] == versionedLastSegment && IsDeleteMarker(pointer) {
+				// delete markers don't have any data
+				continue nextSegment
+			}
 
 			path := ScopedPath{
 				Raw:                 rawPath,
@@ -27,6 +27,7 @@ import (
 	"storj.io/storj/pkg/macaroon"
 	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/attribution"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/metainfo/piecedeletion"
 	"storj.io/storj/satellite/metainfo/pointerverification"
@@ -42,7 +43,15 @@ const (
 	lastSegment     = -1
 	listLimit       = 1000
 
+	// pendingSegmentsListLimit is the maximum number of segment indexes looked
+	// up when listing the segments of an uncommitted object.
+	pendingSegmentsListLimit = 100
+
 	deleteObjectPiecesSuccessThreshold = 0.75
+
+	// errObjectCommitIntervalExceeded is matched by the resumable uploads of
+	// the uplink, it must not change.
+	errObjectCommitIntervalExceeded = "object not committed before max object commit interval"
 )
 
 var (
@@ -64,6 +73,7 @@ type APIKeys interface {
 //
 // architecture: Database
 type Revocations interface {
+	Revoke(ctx context.Context, projectID uuid.UUID, tail []byte) error
 	GetByProjectID(ctx context.Context, projectID uuid.UUID) ([][]byte, error)
 }
 
@@ -77,14 +87,21 @@ type Endpoint struct {
 	orders               *orders.Service
 	overlay              *overlay.Service
 	attributions         attribution.DB
+	pieceReferences      PieceReferencesDB
 	partners             *rewards.PartnersService
 	pointerVerification  *pointerverification.Service
 	projectUsage         *accounting.Service
 	projects             console.Projects
+	users                console.Users
 	apiKeys              APIKeys
+	revocations          Revocations
+	bucketEvents         bucketevents.DB
 	createRequests       *createRequests
 	satellite            signing.Signer
 	limiterCache         *lrucache.ExpiringLRU
+	ownerStatusCache     *lrucache.ExpiringLRU
+	revocationsCache     *lrucache.ExpiringLRU
+	bucketLimitsCache    *lrucache.ExpiringLRU
 	encInlineSegmentSize int64 // max inline segment size + encryption overhead
 	config               Config
 }
@@ -92,11 +109,16 @@ type Endpoint struct {
 // NewEndpoint creates new metainfo endpoint instance.
 func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *piecedeletion.Service,
 	orders *orders.Service, cache *overlay.Service, attributions attribution.DB,
-	partners *rewards.PartnersService, peerIdentities overlay.PeerIdentities,
-	apiKeys APIKeys, projectUsage *accounting.Service, projects console.Projects,
+	pieceReferences PieceReferencesDB, partners *rewards.PartnersService, peerIdentities overlay.PeerIdentities,
+	apiKeys APIKeys, revocations Revocations, bucketEvents bucketevents.DB, projectUsage *accounting.Service, projects console.Projects, users console.Users,
 	satellite signing.Signer, config Config) (*Endpoint, error) {
 	// TODO do something with too many params
 
+	// the stream IDs expire earlier, so a longer interval never takes effect
+	if config.MaxObjectCommitInterval > satIDExpiration {
+		return nil, Error.New("max object commit interval %v exceeds the stream ID expiration %v", config.MaxObjectCommitInterval, satIDExpiration)
+	}
+
 	encInlineSegmentSize, err := encryption.CalcEncryptedSize(config.MaxInlineSegmentSize.Int64(), storj.EncryptionParameters{
 		CipherSuite: storj.EncAESGCM,
 		BlockSize:   128, // intentionally low block size to allow maximum possible encryption overhead
@@ -111,17 +133,33 @@ func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *piecedeletion
 		orders:              orders,
 		overlay:             cache,
 		attributions:        attributions,
+		pieceReferences:     pieceReferences,
 		partners:            partners,
 		pointerVerification: pointerverification.NewService(peerIdentities),
 		apiKeys:             apiKeys,
+		revocations:         revocations,
+		bucketEvents:        bucketEvents,
 		projectUsage:        projectUsage,
 		projects:            projects,
+		users:               users,
 		createRequests:      newCreateRequests(),
 		satellite:           satellite,
 		limiterCache: lrucache.New(lrucache.Options{
 			Capacity:   config.RateLimiter.CacheCapacity,
 			Expiration: config.RateLimiter.CacheExpiration,
 		}),
+		ownerStatusCache: lrucache.New(lrucache.Options{
+			Capacity:   config.OwnerStatus.CacheCapacity,
+			Expiration: config.OwnerStatus.CacheExpiration,
+		}),
+		revocationsCache: lrucache.New(lrucache.Options{
+			Capacity:   config.Revocations.CacheCapacity,
+			Expiration: config.Revocations.CacheExpiration,
+		}),
+		bucketLimitsCache: lrucache.New(lrucache.Options{
+			Capacity:   config.BucketLimits.CacheCapacity,
+			Expiration: config.BucketLimits.CacheExpiration,
+		}),
 		encInlineSegmentSize: encInlineSegmentSize,
 		config:               config,
 	}, nil
@@ -197,6 +235,10 @@ func (endpoint *Endpoint) CreateSegmentOld(ctx context.Context, req *pb.SegmentW
 		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
 	}
 
+	if err := endpoint.checkBucketStorageLimit(ctx, keyInfo.ProjectID, req.Bucket); err != nil {
+		return nil, err
+	}
+
 	redundancy, err := eestream.NewRedundancyStrategyFromProto(req.GetRedundancy())
 	if err != nil {
 		return nil, err
@@ -204,8 +246,14 @@ func (endpoint *Endpoint) CreateSegmentOld(ctx context.Context, req *pb.SegmentW
 
 	maxPieceSize := eestream.CalcPieceSize(req.GetMaxEncryptedSegmentSize(), redundancy)
 
+	placement, err := endpoint.getBucketPlacement(ctx, keyInfo.ProjectID, req.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
 	request := overlay.FindStorageNodesRequest{
 		RequestedCount: int(req.Redundancy.Total),
+		Placement:      placement,
 	}
 	nodes, err := endpoint.overlay.FindStorageNodesForUpload(ctx, request)
 	if err != nil {
@@ -319,6 +367,7 @@ func (endpoint *Endpoint) CommitSegmentOld(ctx context.Context, req *pb.SegmentC
 		// but continue. it's most likely our own fault that we couldn't track it, and the only thing
 		// that will be affected is our per-project bandwidth and storage limits.
 	}
+	endpoint.addBucketStorageUsage(ctx, keyInfo.ProjectID, req.Bucket, segmentSize)
 
 	err = endpoint.metainfo.UnsynchronizedPut(ctx, path, req.Pointer)
 	if err != nil {
@@ -378,6 +427,10 @@ func (endpoint *Endpoint) DownloadSegmentOld(ctx context.Context, req *pb.Segmen
 		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
 	}
 
+	if err := endpoint.checkBucketBandwidthLimit(ctx, keyInfo.ProjectID, req.Bucket); err != nil {
+		return nil, err
+	}
+
 	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, req.Segment, req.Bucket, req.Path)
 	if err != nil {
 		return nil, err
@@ -447,7 +500,7 @@ func (endpoint *Endpoint) DeleteSegmentOld(ctx context.Context, req *pb.SegmentD
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
-	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil {
+	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil && !endpoint.releasePieces(ctx, pointer) {
 		bucketID := createBucketID(keyInfo.ProjectID, req.Bucket)
 		limits, privateKey, err := endpoint.orders.CreateDeleteOrderLimits(ctx, bucketID, pointer)
 		if err != nil {
@@ -591,6 +644,22 @@ func (endpoint *Endpoint) filterValidPieces(ctx context.Context, pointer *pb.Poi
 	return nil
 }
 
+// getBucketPlacement returns the placement constraint nodes for new pieces
+// of the bucket have to satisfy.
+func (endpoint *Endpoint) getBucketPlacement(ctx context.Context, projectID uuid.UUID, bucket []byte) (_ overlay.PlacementConstraint, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	placement, err := endpoint.metainfo.GetBucketPlacement(ctx, bucket, projectID)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return overlay.EveryCountry, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		endpoint.log.Error("unable to get bucket placement", zap.Error(err))
+		return overlay.EveryCountry, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return placement, nil
+}
+
 // CreatePath creates a Segment path.
 func CreatePath(ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, path []byte) (_ storj.Path, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -947,14 +1016,13 @@ func (endpoint *Endpoint) BeginObject(ctx context.Context, req *pb.ObjectBeginRe
 	}
 
 	// TODO this needs to be optimized to avoid DB call on each request
-	_, err = endpoint.metainfo.GetBucket(ctx, req.Bucket, keyInfo.ProjectID)
+	versioning, err := endpoint.getBucketVersioning(ctx, keyInfo.ProjectID, req.Bucket)
 	if err != nil {
-		if storj.ErrBucketNotFound.Has(err) {
-			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
-		}
+		return nil, err
+	}
 
-		endpoint.log.Error("unable to check bucket", zap.Error(err))
-		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	if err := endpoint.checkBucketStorageLimit(ctx, keyInfo.ProjectID, req.Bucket); err != nil {
+		return nil, err
 	}
 
 	if err := endpoint.ensureAttribution(ctx, req.Header, req.Bucket); err != nil {
@@ -976,8 +1044,8 @@ func (endpoint *Endpoint) BeginObject(ctx context.Context, req *pb.ObjectBeginRe
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
-	err = endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
-	if err != nil && !errs2.IsRPC(err, rpcstatus.NotFound) {
+	err = endpoint.replaceObject(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, versioning)
+	if err != nil {
 		return nil, err
 	}
 
@@ -1019,6 +1087,10 @@ func (endpoint *Endpoint) commitObject(ctx context.Context, req *pb.ObjectCommit
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "stream ID expired")
 	}
 
+	if err := endpoint.validateObjectCommitInterval(streamID); err != nil {
+		return nil, err
+	}
+
 	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
 		Op:            macaroon.ActionWrite,
 		Bucket:        streamID.Bucket,
@@ -1035,6 +1107,13 @@ func (endpoint *Endpoint) commitObject(ctx context.Context, req *pb.ObjectCommit
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "invalid metadata structure")
 	}
 
+	// the segments have the creation date of the stream
+	err = endpoint.enqueueObjectCreated(ctx, keyInfo.ProjectID, streamID.Bucket, streamID.EncryptedPath, streamID.CreationDate, streamID.ExpirationDate)
+	if err != nil {
+		endpoint.log.Error("unable to publish bucket event", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
+	}
+
 	lastSegmentPointer := pointer
 	if pointer == nil {
 		lastSegmentIndex := streamMeta.NumberOfSegments - 1
@@ -1115,10 +1194,13 @@ func (endpoint *Endpoint) GetObject(ctx context.Context, req *pb.ObjectGetReques
 }
 
 func (endpoint *Endpoint) getObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (*pb.Object, error) {
-	pointer, _, err := endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+	pointer, _, err := endpoint.getVersionPointer(ctx, projectID, lastSegment, bucket, encryptedPath, version)
 	if err != nil {
 		return nil, err
 	}
+	if version > 0 && IsDeleteMarker(pointer) {
+		return nil, rpcstatus.Errorf(rpcstatus.NotFound, "object version %d is a delete marker", version)
+	}
 
 	streamMeta := &pb.StreamMeta{}
 	err = pb.Unmarshal(pointer.Metadata, streamMeta)
@@ -1165,7 +1247,7 @@ func (endpoint *Endpoint) getObject(ctx context.Context, projectID uuid.UUID, bu
 
 		index := int64(0)
 		for {
-			path, err := CreatePath(ctx, projectID, index, bucket, encryptedPath)
+			path, err := segmentPath(ctx, projectID, index, bucket, encryptedPath, version)
 			if err != nil {
 				endpoint.log.Error("unable to get pointer path", zap.Error(err))
 				return nil, rpcstatus.Error(rpcstatus.Internal, "unable to get object")
@@ -1188,6 +1270,10 @@ func (endpoint *Endpoint) getObject(ctx context.Context, projectID uuid.UUID, bu
 		}
 	}
 
+	if version > 0 {
+		object.Version = version
+	}
+
 	return object, nil
 }
 
@@ -1317,11 +1403,14 @@ func (endpoint *Endpoint) BeginDeleteObject(ctx context.Context, req *pb.ObjectB
 		// Info about deleted object is returned only if either Read, or List permission is granted
 		object, err = endpoint.getObject(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath, satStreamID.Version)
 		if err != nil {
-			return nil, err
+			// delete markers can be deleted, but they don't have any object info
+			if satStreamID.Version <= 0 || !errs2.IsRPC(err, rpcstatus.NotFound) {
+				return nil, err
+			}
 		}
 	}
 
-	err = endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath)
+	err = endpoint.deleteObject(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath, satStreamID.Version)
 	if err != nil {
 		if !canRead && !canList {
 			// No error info is returned if neither Read, nor List permission is granted
@@ -1330,6 +1419,8 @@ func (endpoint *Endpoint) BeginDeleteObject(ctx context.Context, req *pb.ObjectB
 		return nil, err
 	}
 
+	endpoint.publishObjectDeleted(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath)
+
 	endpoint.log.Info("Object Delete", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "delete"), zap.String("type", "object"))
 	mon.Meter("req_delete_object").Mark(1)
 
@@ -1368,7 +1459,8 @@ func (endpoint *Endpoint) FinishDeleteObject(ctx context.Context, req *pb.Object
 		return nil, err
 	}
 
-	// we don't need to do anything for shim implementation
+	// we don't need to do anything for shim implementation, the object is
+	// deleted and its bucket event is published in BeginDeleteObject
 
 	return &pb.ObjectFinishDeleteResponse{}, nil
 }
@@ -1382,6 +1474,10 @@ func (endpoint *Endpoint) BeginSegment(ctx context.Context, req *pb.SegmentBegin
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
 
+	if err := endpoint.validateObjectCommitInterval(streamID); err != nil {
+		return nil, err
+	}
+
 	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
 		Op:            macaroon.ActionWrite,
 		Bucket:        streamID.Bucket,
@@ -1410,6 +1506,10 @@ func (endpoint *Endpoint) BeginSegment(ctx context.Context, req *pb.SegmentBegin
 		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
 	}
 
+	if err := endpoint.checkBucketStorageLimit(ctx, keyInfo.ProjectID, streamID.Bucket); err != nil {
+		return nil, err
+	}
+
 	redundancy, err := eestream.NewRedundancyStrategyFromProto(streamID.Redundancy)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
@@ -1417,8 +1517,14 @@ func (endpoint *Endpoint) BeginSegment(ctx context.Context, req *pb.SegmentBegin
 
 	maxPieceSize := eestream.CalcPieceSize(req.MaxOrderLimit, redundancy)
 
+	placement, err := endpoint.getBucketPlacement(ctx, keyInfo.ProjectID, streamID.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
 	request := overlay.FindStorageNodesRequest{
 		RequestedCount: redundancy.TotalCount(),
+		Placement:      placement,
 	}
 	nodes, err := endpoint.overlay.FindStorageNodesForUpload(ctx, request)
 	if err != nil {
@@ -1467,6 +1573,10 @@ func (endpoint *Endpoint) commitSegment(ctx context.Context, req *pb.SegmentComm
 
 	streamID := segmentID.StreamId
 
+	if err := endpoint.validateObjectCommitInterval(streamID); err != nil {
+		return nil, nil, err
+	}
+
 	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
 		Op:            macaroon.ActionWrite,
 		Bucket:        streamID.Bucket,
@@ -1581,6 +1691,7 @@ func (endpoint *Endpoint) commitSegment(ctx context.Context, req *pb.SegmentComm
 		// but continue. it's most likely our own fault that we couldn't track it, and the only thing
 		// that will be affected is our per-project bandwidth and storage limits.
 	}
+	endpoint.addBucketStorageUsage(ctx, keyInfo.ProjectID, streamID.Bucket, segmentSize)
 
 	if savePointer {
 		path, err := CreatePath(ctx, keyInfo.ProjectID, int64(segmentID.Index), streamID.Bucket, streamID.EncryptedPath)
@@ -1588,7 +1699,7 @@ func (endpoint *Endpoint) commitSegment(ctx context.Context, req *pb.SegmentComm
 			return nil, nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 		}
 
-		err = endpoint.metainfo.UnsynchronizedPut(ctx, path, pointer)
+		err = endpoint.putSegment(ctx, path, pointer)
 		if err != nil {
 			return nil, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 		}
@@ -1616,6 +1727,10 @@ func (endpoint *Endpoint) makeInlineSegment(ctx context.Context, req *pb.Segment
 		return nil, nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
 
+	if err := endpoint.validateObjectCommitInterval(streamID); err != nil {
+		return nil, nil, err
+	}
+
 	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
 		Op:            macaroon.ActionWrite,
 		Bucket:        streamID.Bucket,
@@ -1652,6 +1767,7 @@ func (endpoint *Endpoint) makeInlineSegment(ctx context.Context, req *pb.Segment
 		// but continue. it's most likely our own fault that we couldn't track it, and the only thing
 		// that will be affected is our per-project bandwidth and storage limits.
 	}
+	endpoint.addBucketStorageUsage(ctx, keyInfo.ProjectID, streamID.Bucket, inlineUsed)
 
 	metadata, err := pb.Marshal(&pb.SegmentMeta{
 		EncryptedKey: req.EncryptedKey,
@@ -1673,7 +1789,7 @@ func (endpoint *Endpoint) makeInlineSegment(ctx context.Context, req *pb.Segment
 			return nil, nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 		}
 
-		err = endpoint.metainfo.UnsynchronizedPut(ctx, path, pointer)
+		err = endpoint.putSegment(ctx, path, pointer)
 		if err != nil {
 			return nil, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 		}
@@ -1714,9 +1830,16 @@ func (endpoint *Endpoint) BeginDeleteSegment(ctx context.Context, req *pb.Segmen
 		return nil, err
 	}
 
+	// moved from FinishDeleteSegment to avoid inconsistency if someone will not
+	// call FinishDeleteSegment on uplink side
+	err = endpoint.metainfo.UnsynchronizedDelete(ctx, path)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
 	var limits []*pb.AddressedOrderLimit
 	var privateKey storj.PiecePrivateKey
-	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil {
+	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil && !endpoint.releasePieces(ctx, pointer) {
 		bucketID := createBucketID(keyInfo.ProjectID, streamID.Bucket)
 		limits, privateKey, err = endpoint.orders.CreateDeleteOrderLimits(ctx, bucketID, pointer)
 		if err != nil {
@@ -1724,13 +1847,6 @@ func (endpoint *Endpoint) BeginDeleteSegment(ctx context.Context, req *pb.Segmen
 		}
 	}
 
-	// moved from FinishDeleteSegment to avoid inconsistency if someone will not
-	// call FinishDeleteSegment on uplink side
-	err = endpoint.metainfo.UnsynchronizedDelete(ctx, path)
-	if err != nil {
-		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
-	}
-
 	segmentID, err := endpoint.packSegmentID(ctx, &pb.SatSegmentID{
 		StreamId:            streamID,
 		OriginalOrderLimits: limits,
@@ -1798,10 +1914,12 @@ func (endpoint *Endpoint) ListSegments(ctx context.Context, req *pb.SegmentListR
 		limit = listLimit
 	}
 
-	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, lastSegment, streamID.Bucket, streamID.EncryptedPath)
+	pointer, _, err := endpoint.getVersionPointer(ctx, keyInfo.ProjectID, lastSegment, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		if rpcstatus.Code(err) == rpcstatus.NotFound {
-			return &pb.SegmentListResponse{}, nil
+			// the object isn't committed yet, list the segments committed so far
+			// to allow resuming the upload
+			return endpoint.listPendingSegments(ctx, keyInfo.ProjectID, streamID, req.CursorPosition.Index, limit)
 		}
 		return nil, err
 	}
@@ -1885,6 +2003,59 @@ func (endpoint *Endpoint) listSegmentsFromNumberOfSegments(ctx context.Context,
 	}, nil
 }
 
+// listPendingSegments lists the segments already committed for a stream whose
+// object isn't committed yet. Segments of such a stream may be committed in any
+// order, so the indexes from cursorIndex up to cursorIndex + limit are looked up
+// with a single query, limit being at most pendingSegmentsListLimit. More is set
+// when the segment following the checked indexes is committed; the client,
+// which knows the number of segments of its upload, lists every range anyway.
+func (endpoint *Endpoint) listPendingSegments(ctx context.Context, projectID uuid.UUID, streamID *pb.SatStreamID, cursorIndex, limit int32) (resp *pb.SegmentListResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if cursorIndex < 0 {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "cursor index cannot be negative")
+	}
+	if limit > pendingSegmentsListLimit {
+		limit = pendingSegmentsListLimit
+	}
+
+	// one more index is looked up to know whether there are more segments
+	paths := make([]string, 0, limit+1)
+	for index := int64(cursorIndex); index <= int64(cursorIndex)+int64(limit); index++ {
+		path, err := segmentPath(ctx, projectID, index, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+		}
+		paths = append(paths, path)
+	}
+
+	exists, err := endpoint.metainfo.Exists(ctx, paths)
+	if err != nil {
+		endpoint.log.Error("error getting the pointers from metainfo service", zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if len(exists) != len(paths) {
+		return nil, rpcstatus.Error(rpcstatus.Internal, "unexpected number of pointers")
+	}
+
+	segmentItems := make([]*pb.SegmentListItem, 0)
+	for i, exist := range exists[:limit] {
+		if !exist {
+			continue
+		}
+		segmentItems = append(segmentItems, &pb.SegmentListItem{
+			Position: &pb.SegmentPosition{
+				Index: cursorIndex + int32(i),
+			},
+		})
+	}
+
+	return &pb.SegmentListResponse{
+		Items: segmentItems,
+		More:  exists[limit],
+	}, nil
+}
+
 // listSegmentManually lists the segments that belongs to projectID and streamID
 // from the cursorIndex up to the limit. It stops before the limit when
 // cursorIndex + n returns a not found pointer.
@@ -1903,7 +2074,7 @@ func (endpoint *Endpoint) listSegmentsManually(ctx context.Context, projectID uu
 	more := false
 
 	for {
-		_, _, err := endpoint.getPointer(ctx, projectID, index, streamID.Bucket, streamID.EncryptedPath)
+		_, _, err := endpoint.getVersionPointer(ctx, projectID, index, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 		if err != nil {
 			if rpcstatus.Code(err) != rpcstatus.NotFound {
 				return nil, err
@@ -1974,7 +2145,11 @@ func (endpoint *Endpoint) DownloadSegment(ctx context.Context, req *pb.SegmentDo
 		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
 	}
 
-	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, int64(req.CursorPosition.Index), streamID.Bucket, streamID.EncryptedPath)
+	if err := endpoint.checkBucketBandwidthLimit(ctx, keyInfo.ProjectID, streamID.Bucket); err != nil {
+		return nil, err
+	}
+
+	pointer, _, err := endpoint.getVersionPointer(ctx, keyInfo.ProjectID, int64(req.CursorPosition.Index), streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -2070,8 +2245,17 @@ func (endpoint *Endpoint) DownloadSegment(ctx context.Context, req *pb.SegmentDo
 func (endpoint *Endpoint) getPointer(
 	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte,
 ) (_ *pb.Pointer, _ string, err error) {
-	defer mon.Task()(&ctx, projectID.String(), segmentIndex, bucket, encryptedPath)(&err)
-	path, err := CreatePath(ctx, projectID, segmentIndex, bucket, encryptedPath)
+	return endpoint.getVersionPointer(ctx, projectID, segmentIndex, bucket, encryptedPath, 0)
+}
+
+// getVersionPointer returns the pointer and the segment path of a segment of
+// the current object, when version is 0, or of an archived object version.
+// It returns an error with a specific RPC status.
+func (endpoint *Endpoint) getVersionPointer(
+	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte, version int32,
+) (_ *pb.Pointer, _ string, err error) {
+	defer mon.Task()(&ctx, projectID.String(), segmentIndex, bucket, encryptedPath, version)(&err)
+	path, err := segmentPath(ctx, projectID, segmentIndex, bucket, encryptedPath, version)
 	if err != nil {
 		return nil, "", rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
@@ -2089,13 +2273,13 @@ func (endpoint *Endpoint) getPointer(
 }
 
 // getObjectNumberOfSegments returns the number of segments of the indicated
-// object by projectID, bucket and encryptedPath.
+// object by projectID, bucket, encryptedPath and version.
 //
 // It returns 0 if the number is unknown.
-func (endpoint *Endpoint) getObjectNumberOfSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (_ int64, err error) {
-	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath)(&err)
+func (endpoint *Endpoint) getObjectNumberOfSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (_ int64, err error) {
+	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath, version)(&err)
 
-	pointer, _, err := endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+	pointer, _, err := endpoint.getVersionPointer(ctx, projectID, lastSegment, bucket, encryptedPath, version)
 	if err != nil {
 		return 0, err
 	}
@@ -2193,6 +2377,19 @@ func (endpoint *Endpoint) unmarshalSatStreamID(ctx context.Context, streamID sto
 	return satStreamID, nil
 }
 
+// validateObjectCommitInterval checks that the stream was begun less than
+// MaxObjectCommitInterval ago. Older streams are never committed, which allows
+// the uncommitted deletion chore to remove their segments.
+func (endpoint *Endpoint) validateObjectCommitInterval(streamID *pb.SatStreamID) error {
+	if endpoint.config.MaxObjectCommitInterval <= 0 {
+		return nil
+	}
+	if time.Since(streamID.CreationDate) > endpoint.config.MaxObjectCommitInterval {
+		return rpcstatus.Error(rpcstatus.FailedPrecondition, errObjectCommitIntervalExceeded)
+	}
+	return nil
+}
+
 func (endpoint *Endpoint) unmarshalSatSegmentID(ctx context.Context, segmentID storj.SegmentID) (_ *pb.SatSegmentID, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -2225,7 +2422,15 @@ func (endpoint *Endpoint) unmarshalSatSegmentID(ctx context.Context, segmentID s
 func (endpoint *Endpoint) DeleteObjectPieces(
 	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte,
 ) (err error) {
-	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath)(&err)
+	return endpoint.deleteObjectPieces(ctx, projectID, bucket, encryptedPath, 0)
+}
+
+// deleteObjectPieces deletes the pointers and the pieces of the current object,
+// when version is 0, or of an archived object version.
+func (endpoint *Endpoint) deleteObjectPieces(
+	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32,
+) (err error) {
+	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath, version)(&err)
 
 	// We should ignore client cancelling and always try to delete segments.
 	ctx = context2.WithoutCancellation(ctx)
@@ -2235,7 +2440,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 		prevLastSegmentIndex int64
 	)
 	{
-		numOfSegments, err := endpoint.getObjectNumberOfSegments(ctx, projectID, bucket, encryptedPath)
+		numOfSegments, err := endpoint.getObjectNumberOfSegments(ctx, projectID, bucket, encryptedPath, version)
 		if err != nil {
 			if !errs2.IsRPC(err, rpcstatus.NotFound) {
 				return err
@@ -2247,7 +2452,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			{
 				var err error
 				prevLastSegmentIndex, err = endpoint.findIndexPreviousLastSegmentWhenNotKnowingNumSegments(
-					ctx, projectID, bucket, encryptedPath,
+					ctx, projectID, bucket, encryptedPath, version,
 				)
 				if err != nil {
 					endpoint.log.Error("unexpected error while finding last segment index previous to the last segment",
@@ -2279,7 +2484,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 
 	if !lastSegmentNotFound {
 		// first delete the last segment
-		pointer, err := endpoint.deletePointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+		pointer, err := endpoint.deletePointer(ctx, projectID, lastSegment, bucket, encryptedPath, version)
 		if err != nil {
 			if storj.ErrObjectNotFound.Has(err) {
 				endpoint.log.Warn(
@@ -2300,7 +2505,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			}
 		}
 
-		if err == nil && pointer.Type == pb.Pointer_REMOTE {
+		if err == nil && pointer.Type == pb.Pointer_REMOTE && !endpoint.releasePieces(ctx, pointer) {
 			rootPieceID := pointer.GetRemote().RootPieceId
 			for _, piece := range pointer.GetRemote().GetRemotePieces() {
 				pieceID := rootPieceID.Derive(piece.NodeId, piece.PieceNum)
@@ -2317,7 +2522,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 	}
 
 	for segmentIdx := prevLastSegmentIndex; segmentIdx >= 0; segmentIdx-- {
-		pointer, err := endpoint.deletePointer(ctx, projectID, segmentIdx, bucket, encryptedPath)
+		pointer, err := endpoint.deletePointer(ctx, projectID, segmentIdx, bucket, encryptedPath, version)
 		if err != nil {
 			segment := "s" + strconv.FormatInt(segmentIdx, 10)
 			if storj.ErrObjectNotFound.Has(err) {
@@ -2344,7 +2549,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			continue
 		}
 
-		if pointer.Type != pb.Pointer_REMOTE {
+		if pointer.Type != pb.Pointer_REMOTE || endpoint.releasePieces(ctx, pointer) {
 			continue
 		}
 
@@ -2389,16 +2594,91 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 	return endpoint.deletePieces.Delete(ctx, requests, deleteObjectPiecesSuccessThreshold)
 }
 
+// putSegment saves the pointer of a segment at path. The pieces of the
+// segment it replaces, for example when a part of a multipart upload is
+// uploaded again, are deleted from the storage nodes.
+func (endpoint *Endpoint) putSegment(ctx context.Context, path string, pointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	replaced, err := endpoint.metainfo.Get(ctx, path)
+	if err != nil {
+		if !storj.ErrObjectNotFound.Has(err) {
+			return err
+		}
+		replaced = nil
+	}
+
+	err = endpoint.metainfo.UnsynchronizedPut(ctx, path, pointer)
+	if err != nil {
+		return err
+	}
+
+	if replaced == nil || replaced.Type != pb.Pointer_REMOTE || endpoint.releasePieces(ctx, replaced) {
+		return nil
+	}
+
+	// the replaced segment is already gone, so failing to delete its pieces
+	// doesn't fail the upload; the garbage collector deletes them
+	if err := endpoint.deleteSegmentPieces(ctx, replaced); err != nil {
+		endpoint.log.Warn("unable to delete the pieces of a replaced segment",
+			zap.String("pointer_path", path),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// deleteSegmentPieces deletes the pieces of the remote segment of pointer
+// from the storage nodes.
+func (endpoint *Endpoint) deleteSegmentPieces(ctx context.Context, pointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// We should ignore client cancelling and always try to delete pieces.
+	ctx = context2.WithoutCancellation(ctx)
+
+	var (
+		nodesPieces = make(map[storj.NodeID][]storj.PieceID)
+		nodeIDs     storj.NodeIDList
+	)
+
+	rootPieceID := pointer.GetRemote().RootPieceId
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		if _, ok := nodesPieces[piece.NodeId]; !ok {
+			nodeIDs = append(nodeIDs, piece.NodeId)
+		}
+		nodesPieces[piece.NodeId] = append(nodesPieces[piece.NodeId], rootPieceID.Derive(piece.NodeId, piece.PieceNum))
+	}
+
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+
+	nodes, err := endpoint.overlay.KnownReliable(ctx, nodeIDs)
+	if err != nil {
+		return err
+	}
+
+	var requests []piecedeletion.Request
+	for _, node := range nodes {
+		requests = append(requests, piecedeletion.Request{
+			Node:   node,
+			Pieces: nodesPieces[node.Id],
+		})
+	}
+
+	return endpoint.deletePieces.Delete(ctx, requests, deleteObjectPiecesSuccessThreshold)
+}
+
 // deletePointer deletes a pointer returning the deleted pointer.
 //
 // If the pointer isn't found when getting or deleting it, it returns
 // storj.ErrObjectNotFound error.
 func (endpoint *Endpoint) deletePointer(
-	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte,
+	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte, version int32,
 ) (_ *pb.Pointer, err error) {
-	defer mon.Task()(&ctx, projectID, segmentIndex, bucket, encryptedPath)(&err)
+	defer mon.Task()(&ctx, projectID, segmentIndex, bucket, encryptedPath, version)(&err)
 
-	pointer, path, err := endpoint.getPointer(ctx, projectID, segmentIndex, bucket, encryptedPath)
+	pointer, path, err := endpoint.getVersionPointer(ctx, projectID, segmentIndex, bucket, encryptedPath, version)
 	if err != nil {
 		if errs2.IsRPC(err, rpcstatus.NotFound) {
 			return nil, storj.ErrObjectNotFound.New("%s", err.Error())
@@ -2421,13 +2701,13 @@ func (endpoint *Endpoint) deletePointer(
 // It returns -1 index if none is found and error if there is some error getting
 // the segments' pointers.
 func (endpoint *Endpoint) findIndexPreviousLastSegmentWhenNotKnowingNumSegments(
-	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte,
+	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32,
 ) (index int64, err error) {
-	defer mon.Task()(&ctx, projectID, bucket, encryptedPath)(&err)
+	defer mon.Task()(&ctx, projectID, bucket, encryptedPath, version)(&err)
 
 	lastIdxFound := int64(lastSegment)
 	for {
-		_, _, err := endpoint.getPointer(ctx, projectID, lastIdxFound+1, bucket, encryptedPath)
+		_, _, err := endpoint.getVersionPointer(ctx, projectID, lastIdxFound+1, bucket, encryptedPath, version)
 		if err != nil {
 			if errs2.IsRPC(err, rpcstatus.NotFound) {
 				break
@@ -1407,3 +1407,115 @@ func TestInlineSegmentThreshold(t *testing.T) {
 		}
 	})
 }
+
+func TestObjectVersioning(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		apiKey := planet.Uplinks[0].APIKey[planet.Satellites[0].ID()]
+		projectID := planet.Uplinks[0].Projects[0].ID
+		satellite := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+
+		require.NoError(t, uplink.CreateBucket(ctx, satellite, "versioned"))
+		require.NoError(t, satellite.Metainfo.Service.UpdateBucketVersioning(ctx, []byte("versioned"), projectID, satMetainfo.VersioningEnabled))
+
+		first, second := testrand.Bytes(memory.KiB), testrand.Bytes(memory.KiB)
+		require.NoError(t, uplink.Upload(ctx, satellite, "versioned", "object", first))
+		require.NoError(t, uplink.Upload(ctx, satellite, "versioned", "object", second))
+
+		data, err := uplink.Download(ctx, satellite, "versioned", "object")
+		require.NoError(t, err)
+		require.Equal(t, second, data)
+
+		listVersions := func() []*pb.ObjectListItem {
+			response, err := satellite.Metainfo.Endpoint2.ListObjectVersions(ctx, &pb.ObjectListRequest{
+				Header:    &pb.RequestHeader{ApiKey: apiKey.SerializeRaw()},
+				Bucket:    []byte("versioned"),
+				Recursive: true,
+			})
+			require.NoError(t, err)
+			require.False(t, response.More)
+			return response.Items
+		}
+
+		items := listVersions()
+		require.Len(t, items, 2)
+		require.Equal(t, items[0].EncryptedPath, items[1].EncryptedPath)
+		require.EqualValues(t, 0, items[0].Version)
+		require.EqualValues(t, 1, items[1].Version)
+		encryptedPath := items[0].EncryptedPath
+
+		metainfoClient, err := uplink.DialMetainfo(ctx, satellite, apiKey)
+		require.NoError(t, err)
+		defer ctx.Check(metainfoClient.Close)
+
+		downloadInline := func(version int32) []byte {
+			object, err := metainfoClient.GetObject(ctx, metainfo.GetObjectParams{
+				Bucket:        []byte("versioned"),
+				EncryptedPath: encryptedPath,
+				Version:       version,
+			})
+			require.NoError(t, err)
+
+			info, _, err := metainfoClient.DownloadSegment(ctx, metainfo.DownloadSegmentParams{
+				StreamID: object.StreamID,
+				Position: storj.SegmentPosition{Index: -1},
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, info.EncryptedInlineData)
+			return info.EncryptedInlineData
+		}
+		current, archived := downloadInline(0), downloadInline(1)
+		require.NotEqual(t, current, archived)
+
+		// deleting leaves a delete marker on top of the archived versions
+		require.NoError(t, uplink.DeleteObject(ctx, satellite, "versioned", "object"))
+		_, err = uplink.Download(ctx, satellite, "versioned", "object")
+		require.Error(t, err)
+
+		items = listVersions()
+		require.Len(t, items, 3)
+		require.EqualValues(t, 3, items[0].Version)
+		require.Equal(t, pb.Object_DELETING, items[0].Status)
+		require.EqualValues(t, 2, items[1].Version)
+		require.Equal(t, pb.Object_COMMITTED, items[1].Status)
+		require.EqualValues(t, 1, items[2].Version)
+		require.Equal(t, archived, downloadInline(1))
+		require.Equal(t, current, downloadInline(2))
+
+		err = satellite.Metainfo.Service.DeleteBucket(ctx, []byte("versioned"), projectID)
+		require.True(t, satMetainfo.ErrBucketNotEmpty.Has(err))
+
+		// deleting the delete marker restores the object
+		_, _, err = metainfoClient.BeginDeleteObject(ctx, metainfo.BeginDeleteObjectParams{
+			Bucket:        []byte("versioned"),
+			EncryptedPath: encryptedPath,
+			Version:       3,
+		})
+		require.NoError(t, err)
+
+		data, err = uplink.Download(ctx, satellite, "versioned", "object")
+		require.NoError(t, err)
+		require.Equal(t, second, data)
+
+		// deleting a version removes it permanently
+		_, _, err = metainfoClient.BeginDeleteObject(ctx, metainfo.BeginDeleteObjectParams{
+			Bucket:        []byte("versioned"),
+			EncryptedPath: encryptedPath,
+			Version:       1,
+		})
+		require.NoError(t, err)
+
+		items = listVersions()
+		require.Len(t, items, 1)
+		require.EqualValues(t, 0, items[0].Version)
+
+		_, err = metainfoClient.GetObject(ctx, metainfo.GetObjectParams{
+			Bucket:        []byte("versioned"),
+			EncryptedPath: encryptedPath,
+			Version:       1,
+		})
+		require.True(t, errs2.IsRPC(err, rpcstatus.NotFound))
+	})
+}
@@ -0,0 +1,514 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/macaroon"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/pkg/metainfopb"
+	"storj.io/storj/storage"
+)
+
+// objectSegment is a segment of the current object, as read from the
+// pointerdb.
+type objectSegment struct {
+	index        int64
+	path         string
+	pointerBytes []byte
+	pointer      *pb.Pointer
+}
+
+// BeginCopyObject returns the segment keys of an object, which the client
+// has to re-encrypt for the new path of the object. Moving an object begins
+// with it too.
+func (endpoint *Endpoint) BeginCopyObject(ctx context.Context, req *metainfopb.ObjectBeginCopyRequest) (resp *metainfopb.ObjectBeginCopyResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionRead,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := endpoint.getObjectSegments(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	streamMeta := &pb.StreamMeta{}
+	err = pb.Unmarshal(segments[len(segments)-1].pointer.Metadata, streamMeta)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	keys := make([]*metainfopb.SegmentKey, 0, len(segments))
+	for _, segment := range segments {
+		key, err := getSegmentKey(segment)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, err.Error())
+		}
+		keys = append(keys, key)
+	}
+
+	return &metainfopb.ObjectBeginCopyResponse{
+		EncryptionParameters: &pb.EncryptionParameters{
+			CipherSuite: pb.CipherSuite(streamMeta.EncryptionType),
+			BlockSize:   int64(streamMeta.EncryptionBlockSize),
+		},
+		SegmentKeys: keys,
+	}, nil
+}
+
+// FinishCopyObject copies an object to a new path. The copy shares the pieces
+// of the remote segments with the original object.
+//
+// An object created event is published for the copy.
+func (endpoint *Endpoint) FinishCopyObject(ctx context.Context, req *metainfopb.ObjectFinishCopyRequest) (resp *metainfopb.ObjectFinishCopyResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	keyInfo, err := endpoint.validateAuthAll(ctx, req.Header,
+		macaroon.Action{
+			Op:            macaroon.ActionRead,
+			Bucket:        req.Bucket,
+			EncryptedPath: req.EncryptedPath,
+			Time:          now,
+		},
+		macaroon.Action{
+			Op:            macaroon.ActionWrite,
+			Bucket:        req.NewBucket,
+			EncryptedPath: req.NewEncryptedPath,
+			Time:          now,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	versioning, err := endpoint.validateObjectCopy(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, req.NewBucket, req.NewEncryptedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	exceeded, limit, err := endpoint.projectUsage.ExceedsStorageUsage(ctx, keyInfo.ProjectID)
+	if err != nil {
+		endpoint.log.Error("Retrieving project storage totals failed.", zap.Error(err))
+	}
+	if exceeded {
+		endpoint.log.Error("Monthly storage limit exceeded.",
+			zap.Stringer("Limit", limit),
+			zap.Stringer("Project ID", keyInfo.ProjectID),
+		)
+		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
+	}
+
+	if err := endpoint.checkBucketStorageLimit(ctx, keyInfo.ProjectID, req.NewBucket); err != nil {
+		return nil, err
+	}
+
+	segments, err := endpoint.getObjectSegments(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pointers, err := withSegmentKeys(segments, req.NewSegmentKeys)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	// the event is enqueued before the object at the new path is replaced;
+	// when the copy fails the object isn't committed and the event is dropped
+	// the event is enqueued before the object at the new path is replaced;
+	// when the move fails the object isn't committed and the event is dropped
+	lastPointer := pointers[len(pointers)-1]
+	err = endpoint.enqueueObjectCreated(ctx, keyInfo.ProjectID, req.NewBucket, req.NewEncryptedPath, lastPointer.CreationDate, lastPointer.ExpirationDate)
+	if err != nil {
+		endpoint.log.Error("unable to publish bucket event", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	err = endpoint.replaceObject(ctx, keyInfo.ProjectID, req.NewBucket, req.NewEncryptedPath, versioning)
+	if err != nil {
+		return nil, err
+	}
+
+	var copiedSize int64
+	for i, segment := range segments {
+		err = endpoint.copySegment(ctx, keyInfo.ProjectID, segment, req.NewBucket, req.NewEncryptedPath, pointers[i])
+		if err != nil {
+			// delete the partial copy, which releases the pieces it references
+			deleteErr := endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, req.NewBucket, req.NewEncryptedPath)
+			if deleteErr != nil && !errs2.IsRPC(deleteErr, rpcstatus.NotFound) {
+				endpoint.log.Warn("unable to delete partial object copy", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Error(deleteErr))
+			}
+			return nil, err
+		}
+
+		segmentSize, _ := calculateSpaceUsed(pointers[i])
+		copiedSize += segmentSize
+	}
+
+	if err := endpoint.projectUsage.AddProjectStorageUsage(ctx, keyInfo.ProjectID, copiedSize); err != nil {
+		endpoint.log.Error("Could not track new storage usage.", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Error(err))
+		// but continue. it's most likely our own fault that we couldn't track it, and the only thing
+		// that will be affected is our per-project bandwidth and storage limits.
+	}
+	endpoint.addBucketStorageUsage(ctx, keyInfo.ProjectID, req.NewBucket, copiedSize)
+
+	endpoint.log.Info("Object Copy", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "copy"), zap.String("type", "object"))
+	mon.Meter("req_copy_object").Mark(1)
+
+	return &metainfopb.ObjectFinishCopyResponse{}, nil
+}
+
+// FinishMoveObject moves an object to a new path.
+//
+// An object created event is published for the new path and an object
+// deleted event for the old one.
+//
+// Archived versions of the object are left under the old path.
+func (endpoint *Endpoint) FinishMoveObject(ctx context.Context, req *metainfopb.ObjectFinishMoveRequest) (resp *metainfopb.ObjectFinishMoveResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	keyInfo, err := endpoint.validateAuthAll(ctx, req.Header,
+		macaroon.Action{
+			Op:            macaroon.ActionRead,
+			Bucket:        req.Bucket,
+			EncryptedPath: req.EncryptedPath,
+			Time:          now,
+		},
+		macaroon.Action{
+			Op:            macaroon.ActionDelete,
+			Bucket:        req.Bucket,
+			EncryptedPath: req.EncryptedPath,
+			Time:          now,
+		},
+		macaroon.Action{
+			Op:            macaroon.ActionWrite,
+			Bucket:        req.NewBucket,
+			EncryptedPath: req.NewEncryptedPath,
+			Time:          now,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	versioning, err := endpoint.validateObjectCopy(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, req.NewBucket, req.NewEncryptedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	crossBucket := !bytes.Equal(req.Bucket, req.NewBucket)
+	if crossBucket {
+		if err := endpoint.checkBucketStorageLimit(ctx, keyInfo.ProjectID, req.NewBucket); err != nil {
+			return nil, err
+		}
+	}
+
+	segments, err := endpoint.getObjectSegments(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pointers, err := withSegmentKeys(segments, req.NewSegmentKeys)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	lastPointer := pointers[len(pointers)-1]
+	err = endpoint.enqueueObjectCreated(ctx, keyInfo.ProjectID, req.NewBucket, req.NewEncryptedPath, lastPointer.CreationDate, lastPointer.ExpirationDate)
+	if err != nil {
+		endpoint.log.Error("unable to publish bucket event", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	err = endpoint.replaceObject(ctx, keyInfo.ProjectID, req.NewBucket, req.NewEncryptedPath, versioning)
+	if err != nil {
+		return nil, err
+	}
+
+	// the last segment is moved at the end, so the object is only listed
+	// under the new path once all of its segments are there
+	var movedSize int64
+	for i, segment := range segments {
+		newPath, err := CreatePath(ctx, keyInfo.ProjectID, segment.index, req.NewBucket, req.NewEncryptedPath)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+		}
+
+		err = endpoint.metainfo.Move(ctx, segment.path, segment.pointerBytes, newPath, pointers[i])
+		if err != nil {
+			if storage.ErrValueChanged.Has(err) {
+				return nil, rpcstatus.Error(rpcstatus.Aborted, "object has been modified concurrently")
+			}
+			endpoint.log.Error("unable to move segment",
+				zap.Stringer("Project ID", keyInfo.ProjectID),
+				zap.Int64("segment", segment.index),
+				zap.Error(err),
+			)
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
+		if crossBucket {
+			segmentSize, _ := calculateSpaceUsed(pointers[i])
+			movedSize += segmentSize
+		}
+	}
+
+	if crossBucket {
+		endpoint.addBucketStorageUsage(ctx, keyInfo.ProjectID, req.Bucket, -movedSize)
+		endpoint.addBucketStorageUsage(ctx, keyInfo.ProjectID, req.NewBucket, movedSize)
+	}
+
+	endpoint.publishObjectDeleted(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+
+	endpoint.log.Info("Object Move", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "move"), zap.String("type", "object"))
+	mon.Meter("req_move_object").Mark(1)
+
+	return &metainfopb.ObjectFinishMoveResponse{}, nil
+}
+
+// validateObjectCopy checks that an object can be copied or moved to the new
+// path and returns the versioning state of the new bucket.
+func (endpoint *Endpoint) validateObjectCopy(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath, newBucket, newEncryptedPath []byte) (_ Versioning, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(bucket) == 0 || len(newBucket) == 0 {
+		return Unversioned, rpcstatus.Error(rpcstatus.InvalidArgument, storj.ErrNoBucket.New("").Error())
+	}
+	if len(encryptedPath) == 0 || len(newEncryptedPath) == 0 {
+		return Unversioned, rpcstatus.Error(rpcstatus.InvalidArgument, storj.ErrNoPath.New("").Error())
+	}
+
+	if bytes.Equal(bucket, newBucket) {
+		if bytes.Equal(encryptedPath, newEncryptedPath) {
+			return Unversioned, rpcstatus.Error(rpcstatus.InvalidArgument, "the new path is the path of the object")
+		}
+	} else {
+		// the pieces stay on the nodes they have been uploaded to
+		placement, err := endpoint.getBucketPlacement(ctx, projectID, bucket)
+		if err != nil {
+			return Unversioned, err
+		}
+		newPlacement, err := endpoint.getBucketPlacement(ctx, projectID, newBucket)
+		if err != nil {
+			return Unversioned, err
+		}
+		if placement != newPlacement {
+			return Unversioned, rpcstatus.Error(rpcstatus.FailedPrecondition, "the buckets have different placement constraints")
+		}
+	}
+
+	return endpoint.getBucketVersioning(ctx, projectID, newBucket)
+}
+
+// getObjectSegments returns the segments of the current object, with the
+// last segment at the end.
+func (endpoint *Endpoint) getObjectSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (segments []objectSegment, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	prevLastSegmentIndex, err := endpoint.previousLastSegmentIndex(ctx, projectID, bucket, encryptedPath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int64, 0, prevLastSegmentIndex+2)
+	for index := int64(0); index <= prevLastSegmentIndex; index++ {
+		indexes = append(indexes, index)
+	}
+	indexes = append(indexes, lastSegment)
+
+	for _, index := range indexes {
+		path, err := CreatePath(ctx, projectID, index, bucket, encryptedPath)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+		}
+
+		pointerBytes, pointer, err := endpoint.metainfo.GetWithBytes(ctx, path)
+		if err != nil {
+			if storj.ErrObjectNotFound.Has(err) {
+				return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+			}
+			endpoint.log.Error("error getting the pointer from metainfo service", zap.Error(err))
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
+		segments = append(segments, objectSegment{
+			index:        index,
+			path:         path,
+			pointerBytes: pointerBytes,
+			pointer:      pointer,
+		})
+	}
+	return segments, nil
+}
+
+// copySegment stores the copy of a segment under the new path of the object.
+//
+// The pieces of a remote segment are referenced by both the segment and its
+// copy, so they aren't deleted until both are.
+func (endpoint *Endpoint) copySegment(ctx context.Context, projectID uuid.UUID, segment objectSegment, newBucket, newEncryptedPath []byte, pointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	newPath, err := CreatePath(ctx, projectID, segment.index, newBucket, newEncryptedPath)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	remote := pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil
+	if remote {
+		err = endpoint.pieceReferences.Add(ctx, pointer.Remote.RootPieceId)
+		if err != nil {
+			endpoint.log.Error("unable to add piece reference", zap.Error(err))
+			return rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
+		// the segment may have been deleted, together with its pieces, before
+		// the reference has been added
+		pointerBytes, _, err := endpoint.metainfo.GetWithBytes(ctx, segment.path)
+		if err != nil || !bytes.Equal(pointerBytes, segment.pointerBytes) {
+			endpoint.releasePieces(ctx, pointer)
+			if err != nil && !storj.ErrObjectNotFound.Has(err) {
+				return rpcstatus.Error(rpcstatus.Internal, err.Error())
+			}
+			return rpcstatus.Error(rpcstatus.Aborted, "object has been modified concurrently")
+		}
+	}
+
+	err = endpoint.metainfo.Put(ctx, newPath, pointer)
+	if err != nil {
+		if remote {
+			endpoint.releasePieces(ctx, pointer)
+		}
+		if storage.ErrValueChanged.Has(err) {
+			return rpcstatus.Error(rpcstatus.Aborted, "object has been modified concurrently")
+		}
+		endpoint.log.Error("unable to put segment copy", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return nil
+}
+
+// releasePieces releases the reference of a deleted remote pointer to its
+// pieces and returns whether they are still referenced by copies of the
+// pointer, in which case they must not be deleted.
+func (endpoint *Endpoint) releasePieces(ctx context.Context, pointer *pb.Pointer) (referenced bool) {
+	referenced, err := endpoint.pieceReferences.Release(ctx, pointer.GetRemote().RootPieceId)
+	if err != nil {
+		// keep the pieces, the garbage collector deletes them when they
+		// aren't referenced anymore
+		endpoint.log.Error("unable to release piece reference", zap.Error(err))
+		return true
+	}
+	return referenced
+}
+
+// getSegmentKey returns the encrypted key of a segment.
+func getSegmentKey(segment objectSegment) (_ *metainfopb.SegmentKey, err error) {
+	segmentMeta, err := getSegmentMeta(segment)
+	if err != nil {
+		return nil, err
+	}
+	if len(segmentMeta.EncryptedKey) == 0 {
+		return nil, errs.New("segment %d doesn't have an encrypted key", segment.index)
+	}
+
+	return &metainfopb.SegmentKey{
+		Position:          &pb.SegmentPosition{Index: int32(segment.index)},
+		EncryptedKeyNonce: segmentMeta.KeyNonce,
+		EncryptedKey:      segmentMeta.EncryptedKey,
+	}, nil
+}
+
+// getSegmentMeta returns the segment metadata, which is part of the stream
+// metadata for the last segment.
+func getSegmentMeta(segment objectSegment) (_ *pb.SegmentMeta, err error) {
+	if segment.index == lastSegment {
+		streamMeta := &pb.StreamMeta{}
+		if err := pb.Unmarshal(segment.pointer.Metadata, streamMeta); err != nil {
+			return nil, errs.Wrap(err)
+		}
+		if streamMeta.LastSegmentMeta == nil {
+			return &pb.SegmentMeta{}, nil
+		}
+		return streamMeta.LastSegmentMeta, nil
+	}
+
+	segmentMeta := &pb.SegmentMeta{}
+	if err := pb.Unmarshal(segment.pointer.Metadata, segmentMeta); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return segmentMeta, nil
+}
+
+// withSegmentKeys returns copies of the segment pointers with the re-encrypted
+// segment keys.
+func withSegmentKeys(segments []objectSegment, keys []*metainfopb.SegmentKey) (pointers []*pb.Pointer, err error) {
+	if len(keys) != len(segments) {
+		return nil, errs.New("expected %d segment keys, got %d", len(segments), len(keys))
+	}
+
+	keysByIndex := make(map[int64]*metainfopb.SegmentKey, len(keys))
+	for _, key := range keys {
+		if key.Position == nil {
+			return nil, errs.New("missing segment position")
+		}
+		keysByIndex[int64(key.Position.Index)] = key
+	}
+
+	for _, segment := range segments {
+		key, ok := keysByIndex[segment.index]
+		if !ok || len(key.EncryptedKey) == 0 {
+			return nil, errs.New("missing key of segment %d", segment.index)
+		}
+		if len(key.EncryptedKeyNonce) != len(storj.Nonce{}) {
+			return nil, errs.New("invalid key nonce of segment %d", segment.index)
+		}
+
+		// decode the stored bytes to not modify the pointer of the segment
+		pointer := &pb.Pointer{}
+		if err := pb.Unmarshal(segment.pointerBytes, pointer); err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		segmentMeta := &pb.SegmentMeta{
+			EncryptedKey: key.EncryptedKey,
+			KeyNonce:     key.EncryptedKeyNonce,
+		}
+
+		if segment.index == lastSegment {
+			streamMeta := &pb.StreamMeta{}
+			if err := pb.Unmarshal(pointer.Metadata, streamMeta); err != nil {
+				return nil, errs.Wrap(err)
+			}
+			streamMeta.LastSegmentMeta = segmentMeta
+			pointer.Metadata, err = pb.Marshal(streamMeta)
+		} else {
+			pointer.Metadata, err = pb.Marshal(segmentMeta)
+		}
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		pointers = append(pointers, pointer)
+	}
+	return pointers, nil
+}
@@ -0,0 +1,447 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/macaroon"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/pkg/metainfopb"
+	"storj.io/storj/storage"
+	"storj.io/uplink/private/storage/meta"
+)
+
+// maxArchiveAttempts is the number of versions an object is tried to be
+// archived as, when the versions are taken by concurrent archives.
+const maxArchiveAttempts = 3
+
+// getBucketVersioning returns the versioning state of the bucket. It also
+// checks that the bucket exists.
+func (endpoint *Endpoint) getBucketVersioning(ctx context.Context, projectID uuid.UUID, bucket []byte) (_ Versioning, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	versioning, err := endpoint.metainfo.GetBucketVersioning(ctx, bucket, projectID)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return Unversioned, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		endpoint.log.Error("unable to check bucket", zap.Error(err))
+		return Unversioned, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return versioning, nil
+}
+
+// deleteObject deletes the current object or the archived object version.
+//
+// Deleting the current object of a bucket with versioning enabled archives
+// it and puts a delete marker on top of it.
+func (endpoint *Endpoint) deleteObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if version > 0 {
+		return endpoint.deleteObjectVersion(ctx, projectID, bucket, encryptedPath, version)
+	}
+
+	versioning, err := endpoint.getBucketVersioning(ctx, projectID, bucket)
+	if err != nil {
+		return err
+	}
+	if versioning != VersioningEnabled {
+		return endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+	}
+
+	archived, err := endpoint.archiveObject(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return err
+	}
+
+	markerPath, err := CreateVersionPath(ctx, projectID, lastSegment, bucket, encryptedPath, archived+1)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	err = endpoint.metainfo.Put(ctx, markerPath, &pb.Pointer{Type: pb.Pointer_INLINE})
+	if err != nil {
+		endpoint.log.Error("unable to put delete marker", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return nil
+}
+
+// deleteObjectVersion permanently deletes an archived object version or a
+// delete marker.
+//
+// When the newest delete marker is deleted, the version below it becomes the
+// current object again.
+func (endpoint *Endpoint) deleteObjectVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	latest, latestPointer, err := endpoint.latestObjectVersion(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return err
+	}
+
+	err = endpoint.deleteObjectPieces(ctx, projectID, bucket, encryptedPath, version)
+	if err != nil {
+		return err
+	}
+
+	if version != latest || !IsDeleteMarker(latestPointer) {
+		return nil
+	}
+
+	_, _, err = endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+	if !errs2.IsRPC(err, rpcstatus.NotFound) {
+		// there is a current object already, or we were unable to check it
+		return err
+	}
+
+	latest, latestPointer, err = endpoint.latestObjectVersion(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return err
+	}
+	if latest == 0 || IsDeleteMarker(latestPointer) {
+		return nil
+	}
+
+	return endpoint.moveObjectSegments(ctx, projectID, bucket, encryptedPath, latest, 0)
+}
+
+// replaceObject makes room for a new object under encryptedPath. The current
+// object is archived when versioning is enabled and deleted otherwise.
+func (endpoint *Endpoint) replaceObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, versioning Versioning) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if versioning == VersioningEnabled {
+		_, err = endpoint.archiveObject(ctx, projectID, bucket, encryptedPath)
+		if errs2.IsRPC(err, rpcstatus.NotFound) {
+			// there is no object to keep, but there may be zombie segments
+			err = endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+		}
+	} else {
+		err = endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+	}
+	if err != nil && !errs2.IsRPC(err, rpcstatus.NotFound) {
+		return err
+	}
+	return nil
+}
+
+// archiveObject moves the current object to a new archived version and
+// returns the version.
+//
+// The version is claimed by moving the last segment first, which fails when
+// the version has been taken concurrently, and it's retried with the next
+// version then. The other segments are moved once the version is claimed.
+//
+// It returns a NotFound error when there is no current object.
+func (endpoint *Endpoint) archiveObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (version int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	prevLastSegmentIndex, err := endpoint.previousLastSegmentIndex(ctx, projectID, bucket, encryptedPath, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		latest, _, err := endpoint.latestObjectVersion(ctx, projectID, bucket, encryptedPath)
+		if err != nil {
+			return 0, err
+		}
+
+		version = latest + 1
+		err = endpoint.moveSegment(ctx, projectID, lastSegment, bucket, encryptedPath, 0, version)
+		if err == nil {
+			break
+		}
+		if !errs2.IsRPC(err, rpcstatus.Aborted) || attempt >= maxArchiveAttempts {
+			return 0, err
+		}
+	}
+
+	for segmentIndex := int64(0); segmentIndex <= prevLastSegmentIndex; segmentIndex++ {
+		err := endpoint.moveSegment(ctx, projectID, segmentIndex, bucket, encryptedPath, 0, version)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return version, nil
+}
+
+// latestObjectVersion returns the newest archived version of the object,
+// including delete markers, together with its last segment pointer.
+//
+// It returns 0 when the object doesn't have any archived version.
+func (endpoint *Endpoint) latestObjectVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (version int32, pointer *pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	items, err := endpoint.metainfo.ListByPrefix(ctx, createObjectVersionsPrefix(projectID, bucket, encryptedPath), 1)
+	if err != nil {
+		endpoint.log.Error("unable to list object versions", zap.Error(err))
+		return 0, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if len(items) == 0 {
+		return 0, nil, nil
+	}
+
+	_, version, err = parseVersionedPath(items[0].Path)
+	if err != nil {
+		endpoint.log.Error("invalid object version path", zap.Error(err))
+		return 0, nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return version, items[0].Pointer, nil
+}
+
+// moveObjectSegments moves all the segments of an object from one version to
+// another one. Version 0 is the current object.
+func (endpoint *Endpoint) moveObjectSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, from, to int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	prevLastSegmentIndex, err := endpoint.previousLastSegmentIndex(ctx, projectID, bucket, encryptedPath, from)
+	if err != nil {
+		return err
+	}
+
+	// the last segment is moved at the end, so the object is only listed
+	// under the new version once all of its segments are there
+	for segmentIndex := int64(0); segmentIndex <= prevLastSegmentIndex; segmentIndex++ {
+		if err := endpoint.moveSegment(ctx, projectID, segmentIndex, bucket, encryptedPath, from, to); err != nil {
+			return err
+		}
+	}
+	return endpoint.moveSegment(ctx, projectID, lastSegment, bucket, encryptedPath, from, to)
+}
+
+// previousLastSegmentIndex returns the index of the segment before the last
+// segment of an object, or -1 when the object only has its last segment.
+func (endpoint *Endpoint) previousLastSegmentIndex(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	numberOfSegments, err := endpoint.getObjectNumberOfSegments(ctx, projectID, bucket, encryptedPath, version)
+	if err != nil {
+		return 0, err
+	}
+	if numberOfSegments == 0 {
+		return endpoint.findIndexPreviousLastSegmentWhenNotKnowingNumSegments(ctx, projectID, bucket, encryptedPath, version)
+	}
+	return numberOfSegments - 2, nil // because of the last segment and because it's an index
+}
+
+// moveSegment moves a single segment of an object from one version to another.
+func (endpoint *Endpoint) moveSegment(ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte, from, to int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	fromPath, err := segmentPath(ctx, projectID, segmentIndex, bucket, encryptedPath, from)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+	toPath, err := segmentPath(ctx, projectID, segmentIndex, bucket, encryptedPath, to)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	err = endpoint.metainfo.Rename(ctx, fromPath, toPath)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		if storage.ErrValueChanged.Has(err) {
+			return rpcstatus.Error(rpcstatus.Aborted, "object has been modified concurrently")
+		}
+		endpoint.log.Error("unable to move segment",
+			zap.Stringer("Project ID", projectID),
+			zap.Int64("segment", segmentIndex),
+			zap.Int32("from version", from),
+			zap.Int32("to version", to),
+			zap.Error(err),
+		)
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return nil
+}
+
+// ListObjectVersions lists the current objects together with their archived
+// versions and delete markers.
+//
+// The current object is listed with version 0, followed by its archived
+// versions from the newest to the oldest one. Delete markers are listed with
+// the DELETING status. The cursor to continue the listing is created with
+// VersionCursor from the last listed item.
+func (endpoint *Endpoint) ListObjectVersions(ctx context.Context, req *pb.ObjectListRequest) (resp *pb.ObjectListResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:            macaroon.ActionList,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPrefix,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	_, err = endpoint.getBucketVersioning(ctx, keyInfo.ProjectID, req.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	currentCursor, versionsCursor, err := parseVersionCursor(req.EncryptedCursor)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > listLimit {
+		limit = listLimit
+	}
+
+	currentPrefix, err := CreatePath(ctx, keyInfo.ProjectID, lastSegment, req.Bucket, req.EncryptedPrefix)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+	versionsPrefix := createBucketVersionsPrefix(keyInfo.ProjectID, req.Bucket)
+	if len(req.EncryptedPrefix) > 0 {
+		versionsPrefix = storj.JoinPaths(versionsPrefix, string(req.EncryptedPrefix))
+	}
+
+	current, currentMore, err := endpoint.metainfo.List(ctx, currentPrefix, currentCursor, req.Recursive, limit, meta.All)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	versions, versionsMore, err := endpoint.metainfo.List(ctx, versionsPrefix, versionsCursor, req.Recursive, limit, meta.All)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	// both lists are sorted and the current object sorts before its
+	// archived versions, because their paths have it as prefix
+	items := make([]*pb.ObjectListItem, 0, limit)
+	var lastPrefix string
+	for len(items) < int(limit) && (len(current) > 0 || len(versions) > 0) {
+		var item *pb.ListResponse_Item
+		if len(versions) == 0 || (len(current) > 0 && current[0].Path <= versions[0].Path) {
+			item, current = current[0], current[1:]
+		} else {
+			item, versions = versions[0], versions[1:]
+		}
+
+		if item.IsPrefix {
+			// both namespaces can contain the same prefix
+			if item.Path == lastPrefix {
+				continue
+			}
+			lastPrefix = item.Path
+			items = append(items, &pb.ObjectListItem{
+				EncryptedPath: []byte(item.Path),
+			})
+			continue
+		}
+
+		encryptedPath, version := item.Path, int32(0)
+		if versionedPath, versionedVersion, err := parseVersionedPath(item.Path); err == nil {
+			encryptedPath, version = versionedPath, versionedVersion
+		}
+
+		listItem := &pb.ObjectListItem{
+			EncryptedPath: []byte(encryptedPath),
+			Version:       version,
+			Status:        pb.Object_COMMITTED,
+		}
+		if item.Pointer != nil {
+			if version > 0 && len(item.Pointer.Metadata) == 0 {
+				listItem.Status = pb.Object_DELETING
+			}
+			listItem.EncryptedMetadata = item.Pointer.Metadata
+			listItem.CreatedAt = item.Pointer.CreationDate
+			listItem.ExpiresAt = item.Pointer.ExpirationDate
+		}
+		items = append(items, listItem)
+	}
+
+	endpoint.log.Info("Object Version List", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "list"), zap.String("type", "object"))
+	mon.Meter("req_list_object_versions").Mark(1)
+
+	return &pb.ObjectListResponse{
+		Items: items,
+		More:  currentMore || versionsMore || len(current) > 0 || len(versions) > 0,
+	}, nil
+}
+
+// RestoreObjectVersion makes an archived object version the current object
+// again.
+//
+// The current object is archived as the newest version when versioning is
+// enabled and deleted otherwise. The segments keep their encrypted keys,
+// because the encrypted path of the object doesn't change.
+func (endpoint *Endpoint) RestoreObjectVersion(ctx context.Context, req *metainfopb.ObjectRestoreVersionRequest) (resp *metainfopb.ObjectRestoreVersionResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	keyInfo, err := endpoint.validateAuthAll(ctx, req.Header,
+		macaroon.Action{
+			Op:            macaroon.ActionRead,
+			Bucket:        req.Bucket,
+			EncryptedPath: req.EncryptedPath,
+			Time:          now,
+		},
+		macaroon.Action{
+			Op:            macaroon.ActionWrite,
+			Bucket:        req.Bucket,
+			EncryptedPath: req.EncryptedPath,
+			Time:          now,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+	if req.Version <= 0 {
+		return nil, rpcstatus.Errorf(rpcstatus.InvalidArgument, "invalid version %d", req.Version)
+	}
+
+	versioning, err := endpoint.getBucketVersioning(ctx, keyInfo.ProjectID, req.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	pointer, _, err := endpoint.getVersionPointer(ctx, keyInfo.ProjectID, lastSegment, req.Bucket, req.EncryptedPath, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	if IsDeleteMarker(pointer) {
+		return nil, rpcstatus.Errorf(rpcstatus.FailedPrecondition, "object version %d is a delete marker", req.Version)
+	}
+
+	err = endpoint.replaceObject(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, versioning)
+	if err != nil {
+		return nil, err
+	}
+
+	err = endpoint.moveObjectSegments(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, req.Version, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.log.Info("Object Version Restore", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "restore"), zap.String("type", "object"))
+	mon.Meter("req_restore_object_version").Mark(1)
+
+	return &metainfopb.ObjectRestoreVersionResponse{}, nil
+}
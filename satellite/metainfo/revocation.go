@@ -0,0 +1,70 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/macaroon"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+// RevokeAPIKey adds the API key of the request to the revocation list of its
+// project. The API key must be the API key of the request header, or be
+// restricted from it, hence an access can only revoke the accesses which
+// were shared from it.
+func (endpoint *Endpoint) RevokeAPIKey(ctx context.Context, req *metainfopb.RevokeAPIKeyRequest) (resp *metainfopb.RevokeAPIKeyResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// reading the bucket list is allowed by every valid API key, the
+	// authorization to revoke comes from the relation between the keys.
+	keyInfo, err := endpoint.validateAuth(ctx, req.Header, macaroon.Action{
+		Op:   macaroon.ActionRead,
+		Time: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requestKey, err := getAPIKey(ctx, req.Header)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid API credentials")
+	}
+
+	key, err := macaroon.ParseRawAPIKey(req.ApiKey)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid API key to revoke")
+	}
+
+	if !bytes.Equal(key.Head(), requestKey.Head()) {
+		return nil, rpcstatus.Error(rpcstatus.PermissionDenied, "API key isn't restricted from the API credentials")
+	}
+
+	mac, err := macaroon.ParseMacaroon(key.SerializeRaw())
+	if err != nil || !mac.Validate(keyInfo.Secret) {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid API key to revoke")
+	}
+
+	restricted, err := isRevoked(key, keyInfo.Secret, [][]byte{requestKey.Tail()})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid API key to revoke")
+	}
+	if !restricted {
+		return nil, rpcstatus.Error(rpcstatus.PermissionDenied, "API key isn't restricted from the API credentials")
+	}
+
+	err = endpoint.revocations.Revoke(ctx, keyInfo.ProjectID, key.Tail())
+	if err != nil {
+		endpoint.log.Error("unable to revoke API key", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Error(err))
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	endpoint.revocationsCache.Delete(keyInfo.ProjectID.String())
+
+	return &metainfopb.RevokeAPIKeyResponse{}, nil
+}
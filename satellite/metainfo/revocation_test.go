@@ -0,0 +1,83 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/errs2"
+	"storj.io/common/macaroon"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/testcontext"
+	"storj.io/storj/lib/uplink"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink/private/metainfo"
+)
+
+func TestRevokeAPIKey(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client := planet.Uplinks[0]
+		rootKey := client.Projects[0].RawAPIKey
+
+		restrict := func(key *macaroon.APIKey) *macaroon.APIKey {
+			restricted, err := key.Restrict(macaroon.Caveat{DisallowDeletes: true})
+			require.NoError(t, err)
+			return restricted
+		}
+		listBuckets := func(key *macaroon.APIKey) error {
+			metainfoClient, err := client.DialMetainfo(ctx, satellite, key)
+			require.NoError(t, err)
+			defer ctx.Check(metainfoClient.Close)
+
+			_, err = metainfoClient.ListBuckets(ctx, metainfo.ListBucketsParams{})
+			return err
+		}
+		revoke := func(key, revoked *macaroon.APIKey) error {
+			up, err := client.NewLibuplink(ctx)
+			require.NoError(t, err)
+			defer ctx.Check(up.Close)
+
+			apiKey, err := uplink.ParseAPIKey(key.Serialize())
+			require.NoError(t, err)
+			revokedKey, err := uplink.ParseAPIKey(revoked.Serialize())
+			require.NoError(t, err)
+
+			project, err := up.OpenProject(ctx, satellite.Addr(), apiKey)
+			require.NoError(t, err)
+			defer ctx.Check(project.Close)
+
+			return project.RevokeAPIKey(ctx, revokedKey)
+		}
+
+		shared := restrict(rootKey)
+		sharedChild := restrict(shared)
+		other := restrict(rootKey)
+
+		// a key can't revoke the keys which aren't restricted from it
+		err := revoke(other, shared)
+		require.True(t, errs2.IsRPC(err, rpcstatus.PermissionDenied), err)
+
+		require.NoError(t, revoke(rootKey, shared))
+
+		err = listBuckets(shared)
+		require.True(t, errs2.IsRPC(err, rpcstatus.PermissionDenied), err)
+		err = listBuckets(sharedChild)
+		require.True(t, errs2.IsRPC(err, rpcstatus.PermissionDenied), err)
+
+		require.NoError(t, listBuckets(rootKey))
+		require.NoError(t, listBuckets(other))
+
+		// a key can revoke itself
+		require.NoError(t, revoke(other, other))
+		err = listBuckets(other)
+		require.True(t, errs2.IsRPC(err, rpcstatus.PermissionDenied), err)
+
+		require.NoError(t, listBuckets(rootKey))
+	})
+}
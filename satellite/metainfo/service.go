@@ -14,6 +14,7 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/storj/satellite/overlay"
 	"storj.io/storj/storage"
 	"storj.io/uplink/private/storage/meta"
 )
@@ -219,6 +220,28 @@ func (s *Service) GetWithBytes(ctx context.Context, path string) (pointerBytes [
 	return pointerBytes, pointer, nil
 }
 
+// Exists returns whether the pointers of paths exist in the DB, with a single
+// lookup. The number of paths is limited by the lookup limit of the DB.
+func (s *Service) Exists(ctx context.Context, paths []string) (_ []bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keys := make(storage.Keys, 0, len(paths))
+	for _, path := range paths {
+		keys = append(keys, storage.Key(path))
+	}
+
+	values, err := s.db.GetAll(ctx, keys)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	exists := make([]bool, len(values))
+	for i, value := range values {
+		exists[i] = value != nil
+	}
+	return exists, nil
+}
+
 // List returns all Path keys in the pointers bucket
 func (s *Service) List(ctx context.Context, prefix string, startAfter string, recursive bool, limit int32,
 	metaFlags uint32) (items []*pb.ListResponse_Item, more bool, err error) {
@@ -249,6 +272,37 @@ func (s *Service) List(ctx context.Context, prefix string, startAfter string, re
 	return items, more, nil
 }
 
+// ListByPrefix returns up to limit pointers whose path starts with prefix,
+// sorted by path. Unlike List, prefix doesn't have to end with a delimiter
+// and the returned items contain the full path and pointer.
+func (s *Service) ListByPrefix(ctx context.Context, prefix string, limit int) (items []*pb.ListResponse_Item, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = s.db.Iterate(ctx, storage.IterateOptions{
+		Prefix:  storage.Key(prefix),
+		Recurse: true,
+		Limit:   limit,
+	}, func(ctx context.Context, it storage.Iterator) error {
+		var item storage.ListItem
+		for len(items) < limit && it.Next(ctx, &item) {
+			pointer := &pb.Pointer{}
+			if err := pb.Unmarshal(item.Value, pointer); err != nil {
+				return err
+			}
+			items = append(items, &pb.ListResponse_Item{
+				Path:    item.Key.String(),
+				Pointer: pointer,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return items, nil
+}
+
 // createListItem creates a new list item with the given path. It also adds
 // the metadata according to the given metaFlags.
 func (s *Service) createListItem(ctx context.Context, rawItem storage.ListItem, metaFlags uint32) *pb.ListResponse_Item {
@@ -322,6 +376,50 @@ func (s *Service) UnsynchronizedDelete(ctx context.Context, path string) (err er
 	return Error.Wrap(err)
 }
 
+// Rename moves the pointer stored under oldPath to newPath without modifying
+// it. It fails when a pointer is already stored under newPath.
+func (s *Service) Rename(ctx context.Context, oldPath, newPath string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointerBytes, err := s.db.Get(ctx, []byte(oldPath))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			err = storj.ErrObjectNotFound.Wrap(err)
+		}
+		return Error.Wrap(err)
+	}
+
+	return s.move(ctx, oldPath, pointerBytes, newPath, pointerBytes)
+}
+
+// Move replaces the pointer stored under oldPath with newPointer stored under
+// newPath. It fails when the pointer under oldPath doesn't match
+// oldPointerBytes anymore or when a pointer is already stored under newPath.
+func (s *Service) Move(ctx context.Context, oldPath string, oldPointerBytes []byte, newPath string, newPointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	newPointerBytes, err := pb.Marshal(newPointer)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return s.move(ctx, oldPath, oldPointerBytes, newPath, newPointerBytes)
+}
+
+func (s *Service) move(ctx context.Context, oldPath string, oldPointerBytes []byte, newPath string, newPointerBytes []byte) (err error) {
+	err = s.db.CompareAndSwap(ctx, []byte(newPath), nil, newPointerBytes)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	err = s.db.CompareAndSwap(ctx, []byte(oldPath), oldPointerBytes, nil)
+	if err != nil {
+		// don't leave the pointer in both places
+		return Error.Wrap(errs.Combine(err, s.db.Delete(ctx, []byte(newPath))))
+	}
+	return nil
+}
+
 // CreateBucket creates a new bucket in the buckets db
 func (s *Service) CreateBucket(ctx context.Context, bucket storj.Bucket) (_ storj.Bucket, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -366,6 +464,15 @@ func (s *Service) IsBucketEmpty(ctx context.Context, projectID uuid.UUID, bucket
 	if err != nil {
 		return false, Error.Wrap(err)
 	}
+	if len(items) > 0 {
+		return false, nil
+	}
+
+	// archived object versions and delete markers are also part of the bucket
+	items, _, err = s.List(ctx, createBucketVersionsPrefix(projectID, bucketName), "", true, 1, 0)
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
 	return len(items) == 0, nil
 }
 
@@ -374,3 +481,51 @@ func (s *Service) ListBuckets(ctx context.Context, projectID uuid.UUID, listOpts
 	defer mon.Task()(&ctx)(&err)
 	return s.bucketsDB.ListBuckets(ctx, projectID, listOpts, allowedBuckets)
 }
+
+// GetBucketPlacement returns the placement constraint of a bucket
+func (s *Service) GetBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ overlay.PlacementConstraint, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketPlacement(ctx, bucketName, projectID)
+}
+
+// UpdateBucketPlacement changes the placement constraint of a bucket
+func (s *Service) UpdateBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID, placement overlay.PlacementConstraint) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.UpdateBucketPlacement(ctx, bucketName, projectID, placement)
+}
+
+// GetBucketVersioning returns the versioning state of a bucket
+func (s *Service) GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ Versioning, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketVersioning(ctx, bucketName, projectID)
+}
+
+// UpdateBucketVersioning changes the versioning state of a bucket
+func (s *Service) UpdateBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, versioning Versioning) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.UpdateBucketVersioning(ctx, bucketName, projectID, versioning)
+}
+
+// GetPlacementForPath returns the placement constraint of the bucket
+// containing the segment stored at path.
+//
+// When the bucket doesn't exist anymore, EveryCountry is returned.
+func (s *Service) GetPlacementForPath(ctx context.Context, path storj.Path) (_ overlay.PlacementConstraint, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	comps := storj.SplitPath(path)
+	if len(comps) < 3 {
+		return overlay.EveryCountry, Error.New("no bucket component in path: %s", path)
+	}
+
+	projectID, err := uuid.FromString(comps[0])
+	if err != nil {
+		return overlay.EveryCountry, Error.Wrap(err)
+	}
+
+	placement, err := s.bucketsDB.GetBucketPlacement(ctx, []byte(comps[2]), projectID)
+	if storj.ErrBucketNotFound.Has(err) {
+		return overlay.EveryCountry, nil
+	}
+	return placement, err
+}
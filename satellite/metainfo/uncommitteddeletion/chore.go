@@ -0,0 +1,91 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uncommitteddeletion
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/sync2"
+	"storj.io/storj/satellite/metainfo"
+)
+
+var (
+	// Error defines the uncommitteddeletion chore errors class
+	Error = errs.Class("uncommitteddeletion chore error")
+	mon   = monkit.Package()
+)
+
+// Config contains configurable values for uncommitted segment cleanup
+type Config struct {
+	Interval time.Duration `help:"the time between each attempt to go through the db and clean up segments of uncommitted objects" releaseDefault:"24h" devDefault:"10m"`
+	Enabled  bool          `help:"set if uncommitted segment cleanup is enabled or not" releaseDefault:"true" devDefault:"true"`
+}
+
+// Chore implements the uncommitted segment cleanup chore
+//
+// architecture: Chore
+type Chore struct {
+	log                     *zap.Logger
+	config                  Config
+	maxObjectCommitInterval time.Duration
+	Loop                    *sync2.Cycle
+
+	metainfo     *metainfo.Service
+	metainfoLoop *metainfo.Loop
+}
+
+// NewChore creates a new instance of the uncommitteddeletion chore. Segments
+// of objects which were not committed within maxObjectCommitInterval are deleted.
+func NewChore(log *zap.Logger, config Config, maxObjectCommitInterval time.Duration, meta *metainfo.Service, loop *metainfo.Loop) *Chore {
+	return &Chore{
+		log:                     log,
+		config:                  config,
+		maxObjectCommitInterval: maxObjectCommitInterval,
+		Loop:                    sync2.NewCycle(config.Interval),
+		metainfo:                meta,
+		metainfoLoop:            loop,
+	}
+}
+
+// Run starts the uncommitteddeletion loop service
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !chore.config.Enabled {
+		return nil
+	}
+
+	// without a positive max object commit interval the satellite doesn't
+	// reject late commits, so no segment can safely be considered abandoned
+	if chore.maxObjectCommitInterval <= 0 {
+		chore.log.Warn("uncommitted segment cleanup disabled, max object commit interval is not positive",
+			zap.Duration("max object commit interval", chore.maxObjectCommitInterval))
+		return nil
+	}
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) (err error) {
+		defer mon.Task()(&ctx)(&err)
+
+		deleter := &uncommittedDeleter{
+			log:      chore.log.Named("uncommitted deleter observer"),
+			metainfo: chore.metainfo,
+			before:   time.Now().Add(-chore.maxObjectCommitInterval),
+		}
+
+		// delete segments of uncommitted objects
+		err = chore.metainfoLoop.Join(ctx, deleter)
+		if err != nil {
+			chore.log.Error("error joining metainfoloop", zap.Error(err))
+			return nil
+		}
+
+		chore.log.Debug("uncommitted segments deleted", zap.Int64("count", deleter.deleted))
+		return nil
+	})
+}
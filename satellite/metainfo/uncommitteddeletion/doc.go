@@ -0,0 +1,21 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+/*
+Package uncommitteddeletion contains the functions needed to run uncommitted segment deletion
+
+Segments are stored in metainfo when they are committed, but the last segment,
+which represents the object, is only stored when the whole object is committed.
+Uploads which are interrupted and never resumed leave their segments behind.
+
+The uncommitteddeletion.uncommittedDeleter implements the metainfo loop Observer
+interface allowing us to subscribe to the loop to get information for every
+segment in the metainfo database.
+
+The uncommitteddeletion chore will subscribe the deleter to the metainfo loop
+and delete the segments of objects which were not committed within the
+metainfo max object commit interval. The satellite refuses to commit such objects,
+so their segments can't become part of an object anymore. The pieces of the
+deleted segments are removed from the storage nodes by garbage collection.
+*/
+package uncommitteddeletion
@@ -0,0 +1,98 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uncommitteddeletion
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+)
+
+var _ metainfo.Observer = (*uncommittedDeleter)(nil)
+
+// uncommittedDeleter implements the metainfo loop observer interface for
+// uncommitted segment cleanup.
+//
+// architecture: Observer
+type uncommittedDeleter struct {
+	log      *zap.Logger
+	metainfo *metainfo.Service
+
+	// before is the creation time before which a segment of an
+	// uncommitted object is deleted.
+	before time.Time
+
+	deleted int64
+}
+
+// RemoteSegment deletes the segment if it belongs to an abandoned upload
+func (ud *uncommittedDeleter) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx, path.Raw)(&err)
+
+	return ud.deleteSegmentIfUncommitted(ctx, path, pointer)
+}
+
+// InlineSegment deletes the segment if it belongs to an abandoned upload
+func (ud *uncommittedDeleter) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx, path.Raw)(&err)
+
+	return ud.deleteSegmentIfUncommitted(ctx, path, pointer)
+}
+
+// Object returns nil because the deleter only checks the segments
+func (ud *uncommittedDeleter) Object(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	return nil
+}
+
+func (ud *uncommittedDeleter) deleteSegmentIfUncommitted(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) error {
+	// only the numbered segments of the current objects can be uncommitted,
+	// archived versions are always committed
+	if !strings.HasPrefix(path.Segment, "s") {
+		return nil
+	}
+
+	if !pointer.CreationDate.Before(ud.before) {
+		return nil
+	}
+
+	// only the old segments are checked against their last segment, which
+	// exists when the object is committed
+	lastSegmentPath, err := metainfo.CreatePath(ctx, path.ProjectID, -1, []byte(path.BucketName), []byte(path.EncryptedObjectPath))
+	if err != nil {
+		return err
+	}
+	_, err = ud.metainfo.Get(ctx, lastSegmentPath)
+	if err == nil {
+		return nil
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		return err
+	}
+
+	pointerBytes, err := pb.Marshal(pointer)
+	if err != nil {
+		return err
+	}
+	err = ud.metainfo.Delete(ctx, path.Raw, pointerBytes)
+	if storj.ErrObjectNotFound.Has(err) {
+		// segment already deleted
+		return nil
+	} else if storage.ErrValueChanged.Has(err) {
+		// segment was replaced
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ud.deleted++
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uncommitteddeletion_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/storage"
+)
+
+// TestUncommittedDeletion does the following:
+// * Upload three objects with two segments into separate buckets
+// * Make the first segment of every object older than the max object commit interval
+// * Remove the last segment of two objects, as if they were never committed
+// * Make the first segment of one of them recent again
+// * Run the uncommitted segment chore
+// * Verify that only the old segment of the uncommitted object has been deleted
+func TestUncommittedDeletion(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.UncommittedDeletion.Interval = 500 * time.Millisecond
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		upl := planet.Uplinks[0]
+		chore := satellite.Core.UncommittedDeletion.Chore
+		maxCommitInterval := satellite.Config.Metainfo.MaxObjectCommitInterval
+
+		config := upl.GetConfig(satellite)
+		config.Client.SegmentSize = 10 * memory.KiB
+
+		buckets := []string{"committed", "abandoned", "recent"}
+		for _, bucket := range buckets {
+			err := upl.UploadWithClientConfig(ctx, satellite, config, bucket, "test/path", testrand.Bytes(15*memory.KiB))
+			require.NoError(t, err)
+		}
+
+		listItems := func() map[string]storage.ListItem {
+			items := make(map[string]storage.ListItem)
+			err := satellite.Metainfo.Database.Iterate(ctx, storage.IterateOptions{Recurse: true},
+				func(ctx context.Context, it storage.Iterator) error {
+					var item storage.ListItem
+					for it.Next(ctx, &item) {
+						// <project>/<segment>/<bucket>/<path>
+						comps := strings.SplitN(item.Key.String(), "/", 4)
+						items[comps[2]+"/"+comps[1]] = item
+					}
+					return nil
+				})
+			require.NoError(t, err)
+			return items
+		}
+
+		setCreationDate := func(item storage.ListItem, creationDate time.Time) {
+			pointer := &pb.Pointer{}
+			require.NoError(t, pb.Unmarshal(item.Value, pointer))
+			pointer.CreationDate = creationDate
+			newPointerBytes, err := pb.Marshal(pointer)
+			require.NoError(t, err)
+			err = satellite.Metainfo.Database.CompareAndSwap(ctx, item.Key, item.Value, newPointerBytes)
+			require.NoError(t, err)
+		}
+
+		items := listItems()
+		require.Len(t, items, 6)
+
+		old := time.Now().Add(-2 * maxCommitInterval)
+		for _, bucket := range buckets {
+			setCreationDate(items[bucket+"/s0"], old)
+		}
+		for _, bucket := range []string{"abandoned", "recent"} {
+			require.NoError(t, satellite.Metainfo.Database.Delete(ctx, items[bucket+"/l"].Key))
+		}
+
+		items = listItems()
+		setCreationDate(items["recent/s0"], time.Now())
+
+		chore.Loop.Restart()
+		chore.Loop.TriggerWait()
+
+		items = listItems()
+		require.Len(t, items, 3)
+		require.Contains(t, items, "committed/l")
+		require.Contains(t, items, "committed/s0")
+		require.Contains(t, items, "recent/s0")
+	})
+}
@@ -6,6 +6,8 @@ package metainfo
 import (
 	"bytes"
 	"context"
+	"database/sql"
+	"errors"
 	"regexp"
 	"sync"
 	"time"
@@ -155,13 +157,50 @@ func (endpoint *Endpoint) validateAuth(ctx context.Context, header *pb.RequestHe
 		return nil, err
 	}
 
-	// Revocations are currently handled by just deleting the key.
+	// The root keys are revoked by deleting them, the restricted keys through
+	// the revocation list.
 	err = key.Check(ctx, keyInfo.Secret, action, nil)
 	if err != nil {
 		endpoint.log.Debug("unauthorized request", zap.Error(err))
 		return nil, rpcstatus.Error(rpcstatus.PermissionDenied, "Unauthorized API credentials")
 	}
 
+	if err = endpoint.checkRevocations(ctx, keyInfo, key); err != nil {
+		endpoint.log.Debug("revocation check failed", zap.Error(err))
+		return nil, err
+	}
+
+	if err = endpoint.checkOwnerStatus(ctx, keyInfo.ProjectID); err != nil {
+		endpoint.log.Debug("owner status check failed", zap.Error(err))
+		return nil, err
+	}
+
+	return keyInfo, nil
+}
+
+// validateAuthAll validates that the request is authorized to perform all
+// the actions, e.g. to read the source and to write the destination of a copy.
+func (endpoint *Endpoint) validateAuthAll(ctx context.Context, header *pb.RequestHeader, actions ...macaroon.Action) (_ *console.APIKeyInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, header, actions[0])
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := getAPIKey(ctx, header)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid API credentials")
+	}
+
+	for _, action := range actions[1:] {
+		err = key.Check(ctx, keyInfo.Secret, action, nil)
+		if err != nil {
+			endpoint.log.Debug("unauthorized request", zap.Error(err))
+			return nil, rpcstatus.Error(rpcstatus.PermissionDenied, "Unauthorized API credentials")
+		}
+	}
+
 	return keyInfo, nil
 }
 
@@ -219,6 +258,82 @@ func (endpoint *Endpoint) checkRate(ctx context.Context, projectID uuid.UUID) (e
 	return nil
 }
 
+// checkOwnerStatus returns a PermissionDenied error when the owner of the
+// project is frozen.
+func (endpoint *Endpoint) checkOwnerStatus(ctx context.Context, projectID uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	status, err := endpoint.ownerStatusCache.Get(projectID.String(), func() (interface{}, error) {
+		project, err := endpoint.projects.Get(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		owner, err := endpoint.users.Get(ctx, project.OwnerID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// a project without owner can't be frozen
+			return console.Active, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return owner.Status, nil
+	})
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Unavailable, err.Error())
+	}
+
+	if status.(console.UserStatus) == console.Frozen {
+		return rpcstatus.Error(rpcstatus.PermissionDenied, "Account Frozen")
+	}
+	return nil
+}
+
+// checkRevocations returns a PermissionDenied error when the key, or any key
+// which it's restricted from, is in the revocation list of the project.
+func (endpoint *Endpoint) checkRevocations(ctx context.Context, keyInfo *console.APIKeyInfo, key *macaroon.APIKey) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	revoked, err := endpoint.revocationsCache.Get(keyInfo.ProjectID.String(), func() (interface{}, error) {
+		return endpoint.revocations.GetByProjectID(ctx, keyInfo.ProjectID)
+	})
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Unavailable, err.Error())
+	}
+
+	revokedTails := revoked.([][]byte)
+	if len(revokedTails) == 0 {
+		return nil
+	}
+
+	keyRevoked, err := isRevoked(key, keyInfo.Secret, revokedTails)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid API credentials")
+	}
+	if keyRevoked {
+		return rpcstatus.Error(rpcstatus.PermissionDenied, "API key revoked")
+	}
+	return nil
+}
+
+// isRevoked returns whether the tail of the key, or the tail of any key which
+// it's restricted from, is one of the revoked tails.
+func isRevoked(key *macaroon.APIKey, secret []byte, revokedTails [][]byte) (bool, error) {
+	mac, err := macaroon.ParseMacaroon(key.SerializeRaw())
+	if err != nil {
+		return false, err
+	}
+
+	for _, tail := range mac.Tails(secret) {
+		for _, revokedTail := range revokedTails {
+			if bytes.Equal(tail, revokedTail) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func (endpoint *Endpoint) validateCommitSegment(ctx context.Context, req *pb.SegmentCommitRequestOld) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
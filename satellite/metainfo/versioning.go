@@ -0,0 +1,190 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+)
+
+// Versioning is the versioning state of a bucket.
+type Versioning int
+
+const (
+	// Unversioned buckets overwrite and delete objects permanently.
+	Unversioned Versioning = 0
+
+	// VersioningEnabled buckets keep the previous versions of overwritten
+	// objects and leave a delete marker when an object is deleted.
+	VersioningEnabled Versioning = 1
+
+	// VersioningSuspended buckets behave as unversioned buckets for new
+	// writes, but keep the versions which have been archived so far.
+	VersioningSuspended Versioning = 2
+)
+
+// ParseVersioning parses the name of a versioning state.
+func ParseVersioning(name string) (Versioning, error) {
+	switch strings.ToLower(name) {
+	case "", "unversioned", "off":
+		return Unversioned, nil
+	case "enabled", "on":
+		return VersioningEnabled, nil
+	case "suspended":
+		return VersioningSuspended, nil
+	}
+	return Unversioned, Error.New("unknown versioning state %q", name)
+}
+
+// String returns the name of the versioning state.
+func (versioning Versioning) String() string {
+	switch versioning {
+	case Unversioned:
+		return "unversioned"
+	case VersioningEnabled:
+		return "enabled"
+	case VersioningSuspended:
+		return "suspended"
+	}
+	return "unknown"
+}
+
+const (
+	// versionedSegmentPrefix is prepended to the segment component of the
+	// path of the segments of archived object versions.
+	versionedSegmentPrefix = "v"
+	// versionedLastSegment is the segment component of the last segment of an
+	// archived object version.
+	versionedLastSegment = versionedSegmentPrefix + "l"
+	// versionSeparator separates the encrypted path of an object from its
+	// version. Encrypted paths never contain it.
+	versionSeparator = "\x00"
+)
+
+// CreateVersionPath creates the path of a segment of an archived version of
+// an object.
+//
+// Archived versions are stored in their own namespace, which is the segment
+// component prefixed with "v", and the encrypted path is suffixed with the
+// version in a way that sorts the newest version first.
+func CreateVersionPath(ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, path []byte, version int32) (_ storj.Path, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if version <= 0 {
+		return "", Error.New("invalid version %d", version)
+	}
+	if len(bucket) == 0 || len(path) == 0 {
+		return "", Error.New("bucket and path are required for a versioned path")
+	}
+
+	segmentPath, err := CreatePath(ctx, projectID, segmentIndex, bucket, path)
+	if err != nil {
+		return "", err
+	}
+
+	// insert the version prefix in the segment component
+	comps := strings.SplitN(segmentPath, "/", 2)
+	return comps[0] + "/" + versionedSegmentPrefix + comps[1] + versionSeparator + versionKey(version), nil
+}
+
+// createObjectVersionsPrefix creates the prefix of the paths of the last
+// segments of all the archived versions of an object.
+func createObjectVersionsPrefix(projectID uuid.UUID, bucket, path []byte) storj.Path {
+	return storj.JoinPaths(projectID.String(), versionedLastSegment, string(bucket), string(path)) + versionSeparator
+}
+
+// createBucketVersionsPrefix creates the prefix of the paths of the last
+// segments of all the archived object versions of a bucket.
+func createBucketVersionsPrefix(projectID uuid.UUID, bucket []byte) storj.Path {
+	return storj.JoinPaths(projectID.String(), versionedLastSegment, string(bucket))
+}
+
+// versionKey encodes the version so that newer versions sort first.
+func versionKey(version int32) string {
+	return fmt.Sprintf("%010d", math.MaxInt32-int64(version))
+}
+
+// parseVersionedPath splits the encrypted path of an archived object version
+// into the encrypted path of the object and its version.
+func parseVersionedPath(versionedPath string) (encryptedPath string, version int32, err error) {
+	i := strings.LastIndex(versionedPath, versionSeparator)
+	if i < 0 {
+		return "", 0, Error.New("path has no version")
+	}
+
+	inverted, err := strconv.ParseInt(versionedPath[i+len(versionSeparator):], 10, 64)
+	if err != nil {
+		return "", 0, Error.Wrap(err)
+	}
+	if inverted < 0 || inverted >= math.MaxInt32 {
+		return "", 0, Error.New("invalid version key %d", inverted)
+	}
+
+	return versionedPath[:i], int32(math.MaxInt32 - inverted), nil
+}
+
+// IsLastSegment returns whether the segment component of a path refers to the
+// last segment of an object or of an archived object version.
+func IsLastSegment(segment string) bool {
+	return segment == "l" || segment == versionedLastSegment
+}
+
+// IsVersionedSegment returns whether the segment component of a path refers to
+// a segment of an archived object version.
+func IsVersionedSegment(segment string) bool {
+	return strings.HasPrefix(segment, versionedSegmentPrefix)
+}
+
+// IsDeleteMarker returns whether the pointer stored as the last segment of an
+// archived object version is a delete marker.
+func IsDeleteMarker(pointer *pb.Pointer) bool {
+	return pointer.GetType() == pb.Pointer_INLINE &&
+		len(pointer.GetInlineSegment()) == 0 &&
+		len(pointer.GetMetadata()) == 0
+}
+
+// VersionCursor returns the cursor to continue listing object versions
+// after the version of the object with encryptedPath.
+func VersionCursor(encryptedPath []byte, version int32) []byte {
+	if version <= 0 {
+		return encryptedPath
+	}
+	cursor := append([]byte{}, encryptedPath...)
+	cursor = append(cursor, versionSeparator...)
+	return strconv.AppendInt(cursor, int64(version), 10)
+}
+
+// parseVersionCursor converts a cursor created by VersionCursor into the
+// cursors of the current object and of the archived object versions
+// namespaces.
+func parseVersionCursor(cursor []byte) (current, versions string, err error) {
+	i := bytes.LastIndex(cursor, []byte(versionSeparator))
+	if i < 0 {
+		return string(cursor), string(cursor), nil
+	}
+
+	version, err := strconv.ParseInt(string(cursor[i+len(versionSeparator):]), 10, 32)
+	if err != nil || version <= 0 {
+		return "", "", Error.New("invalid cursor version")
+	}
+
+	path := string(cursor[:i])
+	return path, path + versionSeparator + versionKey(int32(version)), nil
+}
+
+// segmentPath creates the path of a segment of the current object, when
+// version is 0, or of an archived object version.
+func segmentPath(ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, path []byte, version int32) (storj.Path, error) {
+	if version <= 0 {
+		return CreatePath(ctx, projectID, segmentIndex, bucket, path)
+	}
+	return CreateVersionPath(ctx, projectID, segmentIndex, bucket, path, version)
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestParseVersioning(t *testing.T) {
+	for _, versioning := range []metainfo.Versioning{
+		metainfo.Unversioned,
+		metainfo.VersioningEnabled,
+		metainfo.VersioningSuspended,
+	} {
+		parsed, err := metainfo.ParseVersioning(versioning.String())
+		require.NoError(t, err)
+		require.Equal(t, versioning, parsed)
+	}
+
+	_, err := metainfo.ParseVersioning("sometimes")
+	require.Error(t, err)
+}
+
+func TestCreateVersionPath(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	projectID := testrand.UUID()
+
+	_, err := metainfo.CreateVersionPath(ctx, projectID, -1, []byte("bucket"), []byte("a/b"), 0)
+	require.Error(t, err)
+
+	var paths []string
+	for _, version := range []int32{1, 2, 10, 11} {
+		path, err := metainfo.CreateVersionPath(ctx, projectID, -1, []byte("bucket"), []byte("a/b"), version)
+		require.NoError(t, err)
+		paths = append(paths, path)
+
+		comps := storj.SplitPath(path)
+		require.Equal(t, projectID.String(), comps[0])
+		require.Equal(t, "vl", comps[1])
+		require.True(t, metainfo.IsLastSegment(comps[1]))
+		require.True(t, metainfo.IsVersionedSegment(comps[1]))
+		require.Equal(t, "bucket", comps[2])
+	}
+
+	// newer versions sort first
+	for i := 1; i < len(paths); i++ {
+		require.True(t, paths[i] < paths[i-1])
+	}
+
+	path, err := metainfo.CreateVersionPath(ctx, projectID, 3, []byte("bucket"), []byte("a/b"), 1)
+	require.NoError(t, err)
+	segment := storj.SplitPath(path)[1]
+	require.Equal(t, "vs3", segment)
+	require.False(t, metainfo.IsLastSegment(segment))
+	require.True(t, metainfo.IsVersionedSegment(segment))
+
+	require.True(t, metainfo.IsLastSegment("l"))
+	require.False(t, metainfo.IsVersionedSegment("l"))
+	require.False(t, metainfo.IsVersionedSegment("s0"))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/zeebo/errs"
 
 	"storj.io/common/memory"
+	"storj.io/storj/satellite/geoip"
 )
 
 var (
@@ -22,6 +23,7 @@ var (
 type Config struct {
 	Node                 NodeSelectionConfig
 	NodeSelectionCache   CacheConfig
+	GeoIP                geoip.Config
 	UpdateStatsBatchSize int `help:"number of update requests to process per transaction" default:"100"`
 }
 
@@ -145,6 +145,10 @@ nextNewNode:
 			}
 		}
 
+		if !req.Placement.AllowedCountry(currNode.CountryCode) {
+			continue nextNewNode
+		}
+
 		if distinct {
 			// don't select a node if we've already selected another node from the same network
 			if _, ok := distinctNetworks[currNode.LastNet]; ok {
@@ -171,6 +175,11 @@ nextReputableNode:
 			}
 		}
 
+		// don't select a node outside of the allowed countries
+		if !req.Placement.AllowedCountry(currNode.CountryCode) {
+			continue nextReputableNode
+		}
+
 		if distinct {
 			// don't select a node if we've already selected another node from the same network
 			if _, ok := distinctNetworks[currNode.LastNet]; ok {
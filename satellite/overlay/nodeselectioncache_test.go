@@ -420,6 +420,84 @@ func TestGetNodesDistinct(t *testing.T) {
 	}
 }
 
+func TestGetNodesPlacement(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	reputableNodes := []*overlay.SelectedNode{{
+		ID:          testrand.NodeID(),
+		Address:     &pb.NodeAddress{Address: "127.0.0.9"},
+		LastNet:     "127.0.0",
+		LastIPPort:  "127.0.0.9:8000",
+		CountryCode: "DE",
+	}, {
+		ID:          testrand.NodeID(),
+		Address:     &pb.NodeAddress{Address: "127.0.1.7"},
+		LastNet:     "127.0.1",
+		LastIPPort:  "127.0.1.7:8000",
+		CountryCode: "US",
+	}, {
+		ID:         testrand.NodeID(),
+		Address:    &pb.NodeAddress{Address: "127.0.2.7"},
+		LastNet:    "127.0.2",
+		LastIPPort: "127.0.2.7:8000",
+	}}
+
+	newNodes := []*overlay.SelectedNode{{
+		ID:          testrand.NodeID(),
+		Address:     &pb.NodeAddress{Address: "127.0.3.8"},
+		LastNet:     "127.0.3",
+		LastIPPort:  "127.0.3.8:8000",
+		CountryCode: "NO",
+	}}
+
+	mockDB := mockdb{
+		reputable: reputableNodes,
+		new:       newNodes,
+	}
+
+	config := nodeSelectionConfig
+	config.NewNodeFraction = 0
+	cache := overlay.NewNodeSelectionCache(zap.NewNop(),
+		&mockDB,
+		highStaleness,
+		config,
+	)
+
+	// every node is allowed without a placement constraint
+	nodes, err := cache.GetNodes(ctx, overlay.FindStorageNodesRequest{
+		RequestedCount: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+
+	// only the german node is in the EU
+	nodes, err = cache.GetNodes(ctx, overlay.FindStorageNodesRequest{
+		RequestedCount: 1,
+		Placement:      overlay.EU,
+	})
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "DE", nodes[0].CountryCode)
+
+	// nodes with an unknown country are never selected with a constraint
+	_, err = cache.GetNodes(ctx, overlay.FindStorageNodesRequest{
+		RequestedCount: 2,
+		Placement:      overlay.EU,
+	})
+	require.Error(t, err)
+
+	// norway is part of the EEA, but not of the EU
+	nodes, err = cache.GetNodes(ctx, overlay.FindStorageNodesRequest{
+		RequestedCount: 2,
+		Placement:      overlay.EEA,
+	})
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	countries := []string{nodes[0].CountryCode, nodes[1].CountryCode}
+	require.ElementsMatch(t, []string{"DE", "NO"}, countries)
+}
+
 func TestGetNodesError(t *testing.T) {
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
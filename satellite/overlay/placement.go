@@ -0,0 +1,119 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"strings"
+)
+
+// PlacementConstraint is the ID of the policy restricting in which
+// countries the pieces of a bucket may be stored.
+type PlacementConstraint uint16
+
+const (
+	// EveryCountry includes all countries and nodes with an unknown country.
+	EveryCountry PlacementConstraint = 0
+
+	// EU includes only the member states of the European Union.
+	EU PlacementConstraint = 1
+
+	// EEA includes the European Economic Area: the European Union, Iceland, Liechtenstein and Norway.
+	EEA PlacementConstraint = 2
+
+	// US includes only the United States.
+	US PlacementConstraint = 3
+
+	// DE includes only Germany.
+	DE PlacementConstraint = 4
+)
+
+// eu contains the ISO 3166-1 codes of the member states of the European Union.
+var eu = []string{
+	"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI",
+	"FR", "GR", "HR", "HU", "IE", "IT", "LT", "LU", "LV", "MT",
+	"NL", "PL", "PT", "RO", "SE", "SI", "SK",
+}
+
+// eea contains the ISO 3166-1 codes of the non-EU members of the European Economic Area.
+var eea = []string{"IS", "LI", "NO"}
+
+// ParsePlacementConstraint parses the name of a placement constraint.
+func ParsePlacementConstraint(name string) (PlacementConstraint, error) {
+	switch strings.ToUpper(name) {
+	case "", "ANY", "EVERYCOUNTRY":
+		return EveryCountry, nil
+	case "EU":
+		return EU, nil
+	case "EEA":
+		return EEA, nil
+	case "US":
+		return US, nil
+	case "DE":
+		return DE, nil
+	}
+	return EveryCountry, Error.New("unknown placement constraint %q", name)
+}
+
+// String returns the name of the placement constraint.
+func (p PlacementConstraint) String() string {
+	switch p {
+	case EveryCountry:
+		return "any"
+	case EU:
+		return "EU"
+	case EEA:
+		return "EEA"
+	case US:
+		return "US"
+	case DE:
+		return "DE"
+	}
+	return "unknown"
+}
+
+// Countries returns the ISO 3166-1 country codes allowed by the constraint.
+// It returns nil for EveryCountry, because every country, including an
+// unknown one, is allowed.
+func (p PlacementConstraint) Countries() []string {
+	switch p {
+	case EveryCountry:
+		return nil
+	case EU:
+		return append([]string{}, eu...)
+	case EEA:
+		return append(append([]string{}, eu...), eea...)
+	case US:
+		return []string{"US"}
+	case DE:
+		return []string{"DE"}
+	}
+	// unknown constraints don't allow any country
+	return []string{}
+}
+
+// AllowedCountry checks whether a node in the country is allowed to store pieces.
+func (p PlacementConstraint) AllowedCountry(isoCountryCode string) bool {
+	switch p {
+	case EveryCountry:
+		return true
+	case EU:
+		return contains(eu, isoCountryCode)
+	case EEA:
+		return contains(eu, isoCountryCode) || contains(eea, isoCountryCode)
+	case US:
+		return isoCountryCode == "US"
+	case DE:
+		return isoCountryCode == "DE"
+	}
+	return false
+}
+
+func contains(countries []string, country string) bool {
+	for _, c := range countries {
+		if c == country {
+			return true
+		}
+	}
+	return false
+}
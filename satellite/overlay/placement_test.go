@@ -0,0 +1,47 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/overlay"
+)
+
+func TestPlacementConstraint(t *testing.T) {
+	for _, name := range []string{"", "any", "EU", "eu", "EEA", "US", "DE"} {
+		placement, err := overlay.ParsePlacementConstraint(name)
+		require.NoError(t, err, name)
+
+		roundtrip, err := overlay.ParsePlacementConstraint(placement.String())
+		require.NoError(t, err, name)
+		require.Equal(t, placement, roundtrip, name)
+	}
+
+	_, err := overlay.ParsePlacementConstraint("mars")
+	require.Error(t, err)
+
+	require.True(t, overlay.EveryCountry.AllowedCountry(""))
+	require.True(t, overlay.EveryCountry.AllowedCountry("US"))
+	require.Nil(t, overlay.EveryCountry.Countries())
+
+	require.False(t, overlay.EU.AllowedCountry(""))
+	require.True(t, overlay.EU.AllowedCountry("DE"))
+	require.False(t, overlay.EU.AllowedCountry("NO"))
+	require.True(t, overlay.EEA.AllowedCountry("NO"))
+	require.True(t, overlay.EEA.AllowedCountry("FR"))
+	require.False(t, overlay.US.AllowedCountry("DE"))
+	require.False(t, overlay.DE.AllowedCountry("FR"))
+
+	for _, placement := range []overlay.PlacementConstraint{overlay.EU, overlay.EEA, overlay.US, overlay.DE} {
+		for _, country := range placement.Countries() {
+			require.True(t, placement.AllowedCountry(country), placement.String()+" "+country)
+		}
+	}
+
+	require.False(t, overlay.PlacementConstraint(1000).AllowedCountry("DE"))
+	require.Empty(t, overlay.PlacementConstraint(1000).Countries())
+}
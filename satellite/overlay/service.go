@@ -14,6 +14,7 @@ import (
 
 	"storj.io/common/pb"
 	"storj.io/common/storj"
+	"storj.io/storj/satellite/geoip"
 	"storj.io/storj/storage"
 )
 
@@ -79,6 +80,10 @@ type DB interface {
 	GetGracefulExitIncompleteByTimeFrame(ctx context.Context, begin, end time.Time) (exitingNodes storj.NodeIDList, err error)
 	// GetExitStatus returns a node's graceful exit status.
 	GetExitStatus(ctx context.Context, nodeID storj.NodeID) (exitStatus *ExitStatus, err error)
+	// CancelExit clears the exit initiation of a node which hasn't completed the exit loop yet, and returns whether it was cleared.
+	CancelExit(ctx context.Context, nodeID storj.NodeID) (canceled bool, err error)
+	// CompleteExitLoop sets the exit loop completion of a node whose exit is still initiated, and returns whether it was set.
+	CompleteExitLoop(ctx context.Context, nodeID storj.NodeID, completedAt time.Time) (completed bool, err error)
 
 	// GetNodesNetwork returns the /24 subnet for each storage node, order is not guaranteed.
 	GetNodesNetwork(ctx context.Context, nodeIDs []storj.NodeID) (nodeNets []string, err error)
@@ -90,23 +95,32 @@ type DB interface {
 
 	// DisqualifyNode disqualifies a storage node.
 	DisqualifyNode(ctx context.Context, nodeID storj.NodeID) (err error)
+	// ReinstateNode removes the disqualification of a storage node and resets
+	// its audit reputation.
+	ReinstateNode(ctx context.Context, nodeID storj.NodeID) (err error)
 
 	// SuspendNode suspends a storage node.
 	SuspendNode(ctx context.Context, nodeID storj.NodeID, suspendedAt time.Time) (err error)
 	// UnsuspendNode unsuspends a storage node.
 	UnsuspendNode(ctx context.Context, nodeID storj.NodeID) (err error)
+
+	// SearchNodes returns up to limit nodes ordered by ID, starting after cursor.
+	// A non-empty search restricts the nodes to the one with that ID and the ones
+	// whose address, email or wallet contains it.
+	SearchNodes(ctx context.Context, search string, cursor storj.NodeID, limit int) (nodes []NodeSearchResult, err error)
 }
 
 // NodeCheckInInfo contains all the info that will be updated when a node checkins
 type NodeCheckInInfo struct {
-	NodeID     storj.NodeID
-	Address    *pb.NodeAddress
-	LastNet    string
-	LastIPPort string
-	IsUp       bool
-	Operator   *pb.NodeOperator
-	Capacity   *pb.NodeCapacity
-	Version    *pb.NodeVersion
+	NodeID      storj.NodeID
+	Address     *pb.NodeAddress
+	LastNet     string
+	LastIPPort  string
+	CountryCode string
+	IsUp        bool
+	Operator    *pb.NodeOperator
+	Capacity    *pb.NodeCapacity
+	Version     *pb.NodeVersion
 }
 
 // FindStorageNodesRequest defines easy request parameters.
@@ -114,6 +128,7 @@ type FindStorageNodesRequest struct {
 	RequestedCount int
 	ExcludedIDs    []storj.NodeID
 	MinimumVersion string // semver or empty
+	Placement      PlacementConstraint
 }
 
 // NodeCriteria are the requirements for selecting nodes
@@ -126,6 +141,7 @@ type NodeCriteria struct {
 	MinimumVersion   string   // semver or empty
 	OnlineWindow     time.Duration
 	DistinctIP       bool
+	Placement        PlacementConstraint
 }
 
 // AuditType is an enum representing the outcome of a particular audit reported to the overlay.
@@ -189,6 +205,7 @@ type NodeDossier struct {
 	CreatedAt    time.Time
 	LastNet      string
 	LastIPPort   string
+	CountryCode  string
 }
 
 // NodeStats contains statistics about a node.
@@ -217,12 +234,26 @@ type NodeLastContact struct {
 	LastContactFailure time.Time
 }
 
+// NodeSearchResult contains the status of a node found by SearchNodes.
+type NodeSearchResult struct {
+	ID                 storj.NodeID
+	Address            string
+	Email              string
+	Wallet             string
+	LastContactSuccess time.Time
+	Disqualified       *time.Time
+	Suspended          *time.Time
+	ExitInitiatedAt    *time.Time
+	ExitFinishedAt     *time.Time
+}
+
 // SelectedNode is used as a result for creating orders limits.
 type SelectedNode struct {
-	ID         storj.NodeID
-	Address    *pb.NodeAddress
-	LastNet    string
-	LastIPPort string
+	ID          storj.NodeID
+	Address     *pb.NodeAddress
+	LastNet     string
+	LastIPPort  string
+	CountryCode string
 }
 
 // Clone returns a deep clone of the selected node.
@@ -233,8 +264,9 @@ func (node *SelectedNode) Clone() *SelectedNode {
 			Transport: node.Address.Transport,
 			Address:   node.Address.Address,
 		},
-		LastNet:    node.LastNet,
-		LastIPPort: node.LastIPPort,
+		LastNet:     node.LastNet,
+		LastIPPort:  node.LastIPPort,
+		CountryCode: node.CountryCode,
 	}
 }
 
@@ -245,23 +277,30 @@ type Service struct {
 	log            *zap.Logger
 	db             DB
 	config         Config
+	geoIP          geoip.IPToCountry
 	SelectionCache *NodeSelectionCache
 }
 
 // NewService returns a new Service
-func NewService(log *zap.Logger, db DB, config Config) *Service {
+func NewService(log *zap.Logger, db DB, config Config) (*Service, error) {
+	geoIP, err := geoip.Open(config.GeoIP)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
 	return &Service{
 		log:    log,
 		db:     db,
 		config: config,
+		geoIP:  geoIP,
 		SelectionCache: NewNodeSelectionCache(log, db,
 			config.NodeSelectionCache.Staleness, config.Node,
 		),
-	}
+	}, nil
 }
 
 // Close closes resources
-func (service *Service) Close() error { return nil }
+func (service *Service) Close() error { return service.geoIP.Close() }
 
 // Inspect lists limited number of items in the cache
 func (service *Service) Inspect(ctx context.Context) (_ storage.Keys, err error) {
@@ -364,6 +403,7 @@ func (service *Service) FindStorageNodesWithPreferences(ctx context.Context, req
 		MinimumVersion:   preferences.MinimumVersion,
 		OnlineWindow:     preferences.OnlineWindow,
 		DistinctIP:       preferences.DistinctIP,
+		Placement:        req.Placement,
 	}
 	nodes, err = service.db.SelectStorageNodes(ctx, totalNeededNodes, newNodeCount, &criteria)
 	if err != nil {
@@ -450,8 +490,23 @@ func (service *Service) UpdateUptime(ctx context.Context, nodeID storj.NodeID, i
 }
 
 // UpdateCheckIn updates a single storagenode's check-in info.
+//
+// When the country of the node isn't known, it's resolved from the node's
+// last IP address.
 func (service *Service) UpdateCheckIn(ctx context.Context, node NodeCheckInInfo, timestamp time.Time) (err error) {
 	defer mon.Task()(&ctx)(&err)
+
+	if node.CountryCode == "" && node.LastIPPort != "" {
+		node.CountryCode, err = service.geoIP.LookupISOCountryCode(node.LastIPPort)
+		if err != nil {
+			service.log.Debug("failed to resolve country of node",
+				zap.Stringer("Node ID", node.NodeID),
+				zap.String("address", node.LastIPPort),
+				zap.Error(err))
+			node.CountryCode = ""
+		}
+	}
+
 	return service.db.UpdateCheckIn(ctx, node, timestamp, service.config.Node)
 }
 
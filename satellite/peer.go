@@ -20,6 +20,7 @@ import (
 	"storj.io/storj/satellite/admin"
 	"storj.io/storj/satellite/attribution"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/compensation"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleweb"
@@ -33,6 +34,7 @@ import (
 	"storj.io/storj/satellite/marketingweb"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/metainfo/expireddeletion"
+	"storj.io/storj/satellite/metainfo/uncommitteddeletion"
 	"storj.io/storj/satellite/metrics"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
@@ -86,6 +88,8 @@ type DB interface {
 	Containment() audit.Containment
 	// Buckets returns the database to interact with buckets
 	Buckets() metainfo.BucketsDB
+	// PieceReferences returns the database to count the pointers sharing pieces
+	PieceReferences() metainfo.PieceReferencesDB
 	// GracefulExit returns database for graceful exit
 	GracefulExit() gracefulexit.DB
 	// StripeCoinPayments returns stripecoinpayments database.
@@ -96,6 +100,10 @@ type DB interface {
 	HeldAmount() heldamount.DB
 	// Compoensation tracks storage node compensation
 	Compensation() compensation.DB
+	// AdminAuditLog returns the log of the actions taken through the admin API
+	AdminAuditLog() admin.AuditLog
+	// BucketEvents returns the outbox of the bucket events
+	BucketEvents() bucketevents.DB
 }
 
 // Config is the global config satellite
@@ -118,7 +126,8 @@ type Config struct {
 
 	GarbageCollection gc.Config
 
-	ExpiredDeletion expireddeletion.Config
+	ExpiredDeletion     expireddeletion.Config
+	UncommittedDeletion uncommitteddeletion.Config
 
 	DBCleanup dbcleanup.Config
 
@@ -141,6 +150,8 @@ type Config struct {
 
 	GracefulExit gracefulexit.Config
 
+	BucketEvents bucketevents.Config
+
 	Metrics metrics.Config
 
 	Downtime downtime.Config
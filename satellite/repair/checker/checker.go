@@ -35,6 +35,8 @@ type Config struct {
 
 	ReliabilityCacheStaleness time.Duration `help:"how stale reliable node cache can be" releaseDefault:"5m" devDefault:"5m"`
 	RepairOverride            int           `help:"override value for repair threshold" default:"0"`
+
+	Priority PriorityConfig
 }
 
 // durabilityStats remote segment information
@@ -62,10 +64,18 @@ type Checker struct {
 	repairOverride  int32
 	Loop            *sync2.Cycle
 	IrreparableLoop *sync2.Cycle
+
+	priority         PriorityConfig
+	placementWeights map[overlay.PlacementConstraint]float64
 }
 
 // NewChecker creates a new instance of checker
-func NewChecker(logger *zap.Logger, repairQueue queue.RepairQueue, irrdb irreparable.DB, metainfo *metainfo.Service, metaLoop *metainfo.Loop, overlay *overlay.Service, config Config) *Checker {
+func NewChecker(logger *zap.Logger, repairQueue queue.RepairQueue, irrdb irreparable.DB, metainfo *metainfo.Service, metaLoop *metainfo.Loop, overlay *overlay.Service, config Config) (*Checker, error) {
+	placementWeights, err := ParsePlacementWeights(config.Priority.PlacementWeights)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Checker{
 		logger: logger,
 
@@ -78,7 +88,10 @@ func NewChecker(logger *zap.Logger, repairQueue queue.RepairQueue, irrdb irrepar
 
 		Loop:            sync2.NewCycle(config.Interval),
 		IrreparableLoop: sync2.NewCycle(config.IrreparableInterval),
-	}
+
+		priority:         config.Priority,
+		placementWeights: placementWeights,
+	}, nil
 }
 
 // Run the checker loop
@@ -119,6 +132,7 @@ func (checker *Checker) IdentifyInjuredSegments(ctx context.Context) (err error)
 		nodestate:      checker.nodestate,
 		monStats:       durabilityStats{},
 		overrideRepair: checker.repairOverride,
+		prioritizer:    checker.newPrioritizer(),
 		log:            checker.logger,
 	}
 	err = checker.metaLoop.Join(ctx, observer)
@@ -159,7 +173,7 @@ func contains(a []string, x string) bool {
 	return false
 }
 
-func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, pointer *pb.Pointer, path string) (err error) {
+func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, prioritizer *prioritizer, pointer *pb.Pointer, path string) (err error) {
 	// TODO figure out how to reduce duplicate code between here and checkerObs.RemoteSegment
 	defer mon.Task()(&ctx)(&err)
 	remote := pointer.GetRemote()
@@ -190,11 +204,12 @@ func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, poin
 	// minimum required pieces in redundancy
 	// except for the case when the repair and success thresholds are the same (a case usually seen during testing)
 	if numHealthy >= redundancy.MinReq && numHealthy <= repairThreshold && numHealthy < redundancy.SuccessThreshold {
+		priority := prioritizer.Priority(ctx, path, pointer, numHealthy)
 		err = checker.repairQueue.Insert(ctx, &pb.InjuredSegment{
 			Path:         []byte(path),
 			LostPieces:   missingPieces,
 			InsertedTime: time.Now().UTC(),
-		}, int(numHealthy))
+		}, int(numHealthy), priority)
 		if err != nil {
 			return errs.Combine(Error.New("error adding injured segment to queue"), err)
 		}
@@ -235,6 +250,7 @@ type checkerObserver struct {
 	nodestate      *ReliabilityCache
 	monStats       durabilityStats
 	overrideRepair int32
+	prioritizer    *prioritizer
 	log            *zap.Logger
 }
 
@@ -280,11 +296,12 @@ func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.Sco
 	// except for the case when the repair and success thresholds are the same (a case usually seen during testing)
 	if numHealthy >= redundancy.MinReq && numHealthy <= repairThreshold && numHealthy < redundancy.SuccessThreshold {
 		obs.monStats.remoteSegmentsNeedingRepair++
+		priority := obs.prioritizer.Priority(ctx, path.Raw, pointer, numHealthy)
 		err = obs.repairQueue.Insert(ctx, &pb.InjuredSegment{
 			Path:         []byte(path.Raw),
 			LostPieces:   missingPieces,
 			InsertedTime: time.Now().UTC(),
-		}, int(numHealthy))
+		}, int(numHealthy), priority)
 		if err != nil {
 			obs.log.Error("error adding injured segment to queue", zap.Error(err))
 			return nil
@@ -370,6 +387,7 @@ func (checker *Checker) IrreparableProcess(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	const limit = 1000
 	lastSeenSegmentPath := []byte{}
+	prioritizer := checker.newPrioritizer()
 
 	for {
 		segments, err := checker.irrdb.GetLimited(ctx, limit, lastSeenSegmentPath)
@@ -385,7 +403,7 @@ func (checker *Checker) IrreparableProcess(ctx context.Context) (err error) {
 		lastSeenSegmentPath = segments[len(segments)-1].Path
 
 		for _, segment := range segments {
-			err = checker.updateIrreparableSegmentStatus(ctx, segment.GetSegmentDetail(), string(segment.GetPath()))
+			err = checker.updateIrreparableSegmentStatus(ctx, prioritizer, segment.GetSegmentDetail(), string(segment.GetPath()))
 			if err != nil {
 				checker.logger.Error("irrepair segment checker failed: ", zap.Error(err))
 			}
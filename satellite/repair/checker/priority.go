@@ -0,0 +1,127 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
+)
+
+// PriorityConfig contains the weights of the repair priority of injured
+// segments. The segment with the highest priority is repaired first.
+type PriorityConfig struct {
+	ThresholdWeight  float64 `help:"repair priority of a segment with as many healthy pieces as the minimum threshold, divided by one more for every additional healthy piece" default:"100"`
+	AgeWeight        float64 `help:"repair priority added for every day since the segment was uploaded" default:"0.01"`
+	PlacementWeights string  `help:"repair priority added to the segments of buckets with a placement constraint, e.g. EU:10,DE:20" default:""`
+}
+
+// Priority returns the repair priority of a segment with numHealthy healthy
+// pieces out of the minThreshold pieces required to restore it. importance is
+// added as it is.
+func (config PriorityConfig) Priority(numHealthy, minThreshold int32, age time.Duration, importance float64) float64 {
+	margin := numHealthy - minThreshold
+	if margin < 0 {
+		margin = 0
+	}
+	return config.ThresholdWeight/float64(margin+1) + config.AgeWeight*age.Hours()/24 + importance
+}
+
+// ParsePlacementWeights parses placement weights in the format
+// "placement:weight,placement:weight".
+func ParsePlacementWeights(s string) (map[overlay.PlacementConstraint]float64, error) {
+	weights := make(map[overlay.PlacementConstraint]float64)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return nil, Error.New("invalid placement weight %q", entry)
+		}
+
+		placement, err := overlay.ParsePlacementConstraint(parts[0])
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		weight, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, Error.New("invalid placement weight %q: %v", entry, err)
+		}
+		weights[placement] = weight
+	}
+	return weights, nil
+}
+
+// prioritizer computes the repair priority of the injured segments found
+// during a single checker run. It isn't safe for concurrent use.
+type prioritizer struct {
+	log        *zap.Logger
+	config     PriorityConfig
+	placements map[overlay.PlacementConstraint]float64
+	metainfo   *metainfo.Service
+
+	// importance caches the importance of the buckets
+	importance map[string]float64
+}
+
+func (checker *Checker) newPrioritizer() *prioritizer {
+	return &prioritizer{
+		log:        checker.logger,
+		config:     checker.priority,
+		placements: checker.placementWeights,
+		metainfo:   checker.metainfo,
+		importance: make(map[string]float64),
+	}
+}
+
+// Priority returns the repair priority of the segment at path.
+func (p *prioritizer) Priority(ctx context.Context, path storj.Path, pointer *pb.Pointer, numHealthy int32) float64 {
+	minThreshold := pointer.GetRemote().GetRedundancy().GetMinReq()
+	age := time.Since(pointer.CreationDate)
+	return p.config.Priority(numHealthy, minThreshold, age, p.bucketImportance(ctx, path))
+}
+
+// bucketImportance returns the importance of the bucket of the segment at
+// path, which depends on the placement constraint of the bucket.
+func (p *prioritizer) bucketImportance(ctx context.Context, path storj.Path) float64 {
+	if len(p.placements) == 0 {
+		return 0
+	}
+
+	// the path starts with the project ID, the segment index and the bucket name
+	elements := storj.SplitPath(path)
+	if len(elements) < 3 {
+		return 0
+	}
+	key := storj.JoinPaths(elements[0], elements[2])
+	if importance, ok := p.importance[key]; ok {
+		return importance
+	}
+
+	projectID, err := uuid.FromString(elements[0])
+	if err != nil {
+		return 0
+	}
+	placement, err := p.metainfo.GetBucketPlacement(ctx, []byte(elements[2]), projectID)
+	if err != nil {
+		p.log.Debug("failed to get bucket placement", zap.Error(err))
+		return 0
+	}
+
+	importance := p.placements[placement]
+	p.importance[key] = importance
+	return importance
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/repair/checker"
+)
+
+func TestPriority(t *testing.T) {
+	config := checker.PriorityConfig{
+		ThresholdWeight: 100,
+		AgeWeight:       1,
+	}
+
+	// the fewer healthy pieces above the minimum threshold, the higher the priority
+	assert.Equal(t, 100.0, config.Priority(29, 29, 0, 0))
+	assert.Equal(t, 100.0, config.Priority(28, 29, 0, 0))
+	assert.Equal(t, 50.0, config.Priority(30, 29, 0, 0))
+	assert.Equal(t, 25.0, config.Priority(32, 29, 0, 0))
+
+	// older and more important segments come first
+	assert.Equal(t, 52.0, config.Priority(30, 29, 48*time.Hour, 0))
+	assert.Equal(t, 60.0, config.Priority(30, 29, 0, 10))
+}
+
+func TestParsePlacementWeights(t *testing.T) {
+	weights, err := checker.ParsePlacementWeights("")
+	require.NoError(t, err)
+	require.Empty(t, weights)
+
+	weights, err = checker.ParsePlacementWeights("EU:10, de:2.5")
+	require.NoError(t, err)
+	require.Equal(t, map[overlay.PlacementConstraint]float64{
+		overlay.EU: 10,
+		overlay.DE: 2.5,
+	}, weights)
+
+	_, err = checker.ParsePlacementWeights("EU")
+	require.Error(t, err)
+	_, err = checker.ParsePlacementWeights("XX:1")
+	require.Error(t, err)
+	_, err = checker.ParsePlacementWeights("EU:lots")
+	require.Error(t, err)
+}
+
+func TestNewCheckerInvalidPlacementWeights(t *testing.T) {
+	_, err := checker.NewChecker(zaptest.NewLogger(t), nil, nil, nil, nil, nil, checker.Config{
+		Priority: checker.PriorityConfig{PlacementWeights: "XX:1"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package queue
+
+import (
+	"time"
+)
+
+// Attempt is a failed repair attempt of an injured segment.
+type Attempt struct {
+	Path          []byte
+	AttemptedAt   time.Time
+	FailureReason string
+}
+
+// Backoff configures how long the repair of a segment is postponed after it
+// failed. The delay doubles with every consecutive failure.
+type Backoff struct {
+	Initial time.Duration `help:"how long the repair of a segment is postponed after its first failure" default:"30m"`
+	Max     time.Duration `help:"maximum time the repair of a segment is postponed after consecutive failures" default:"48h"`
+}
+
+// Delay returns how long the repair is postponed after the given number of
+// consecutive failures.
+func (backoff Backoff) Delay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	delay := backoff.Initial
+	for i := 1; i < failures && delay < backoff.Max; i++ {
+		delay *= 2
+	}
+	if delay > backoff.Max {
+		delay = backoff.Max
+	}
+	return delay
+}
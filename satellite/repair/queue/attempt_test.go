@@ -0,0 +1,34 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/satellite/repair/queue"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := queue.Backoff{
+		Initial: 30 * time.Minute,
+		Max:     3 * time.Hour,
+	}
+
+	for _, tt := range []struct {
+		failures int
+		delay    time.Duration
+	}{
+		{0, 0},
+		{1, 30 * time.Minute},
+		{2, time.Hour},
+		{3, 2 * time.Hour},
+		{4, 3 * time.Hour},
+		{100, 3 * time.Hour},
+	} {
+		assert.Equal(t, tt.delay, backoff.Delay(tt.failures), "failures %d", tt.failures)
+	}
+}
@@ -14,12 +14,20 @@ import (
 //
 // architecture: Database
 type RepairQueue interface {
-	// Insert adds an injured segment.
-	Insert(ctx context.Context, s *pb.InjuredSegment, numHealthy int) error
-	// Select gets an injured segment.
+	// Insert adds an injured segment, or updates the healthy count and the
+	// priority of an already queued one.
+	Insert(ctx context.Context, s *pb.InjuredSegment, numHealthy int, priority float64) error
+	// Select gets the injured segment with the highest priority, skipping the
+	// segments whose repair is postponed.
 	Select(ctx context.Context) (*pb.InjuredSegment, error)
-	// Delete removes an injured segment.
+	// Delete removes an injured segment and its repair attempt history.
 	Delete(ctx context.Context, s *pb.InjuredSegment) error
+	// RecordFailure adds a failed repair attempt to the history of the segment
+	// and postpones its next repair according to backoff.
+	RecordFailure(ctx context.Context, attempt Attempt, backoff Backoff) error
+	// Attempts returns the failed repair attempts of a segment, the most
+	// recent first.
+	Attempts(ctx context.Context, path []byte) ([]Attempt, error)
 	// SelectN lists limit amount of injured segments.
 	SelectN(ctx context.Context, limit int) ([]pb.InjuredSegment, error)
 	// Count counts the number of segments in the repair queue.
@@ -15,6 +15,7 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/testcontext"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/repair/queue"
 	"storj.io/storj/satellite/satellitedb/dbx"
 	"storj.io/storj/satellite/satellitedb/satellitedbtest"
 	"storj.io/storj/storage"
@@ -29,7 +30,7 @@ func TestUntilEmpty(t *testing.T) {
 		for i := 0; i < 100; i++ {
 			path := "/path/" + string(i)
 			injuredSeg := &pb.InjuredSegment{Path: []byte(path)}
-			err := repairQueue.Insert(ctx, injuredSeg, 10)
+			err := repairQueue.Insert(ctx, injuredSeg, 10, 0)
 			require.NoError(t, err)
 			pathsMap[path] = 0
 		}
@@ -61,7 +62,7 @@ func TestOrder(t *testing.T) {
 
 		for _, path := range [][]byte{oldRepairPath, recentRepairPath, nullPath, olderRepairPath} {
 			injuredSeg := &pb.InjuredSegment{Path: path}
-			err := repairQueue.Insert(ctx, injuredSeg, 10)
+			err := repairQueue.Insert(ctx, injuredSeg, 10, 0)
 			require.NoError(t, err)
 		}
 
@@ -155,7 +156,7 @@ func TestOrderHealthyPieces(t *testing.T) {
 		for _, item := range injuredSegList {
 			// first, insert the injured segment
 			injuredSeg := &pb.InjuredSegment{Path: item.path}
-			err := repairQueue.Insert(ctx, injuredSeg, item.health)
+			err := repairQueue.Insert(ctx, injuredSeg, item.health, 0)
 			require.NoError(t, err)
 
 			// next, if applicable, update the "attempted at" timestamp
@@ -218,7 +219,7 @@ func TestOrderOverwrite(t *testing.T) {
 		}
 		for _, item := range injuredSegList {
 			injuredSeg := &pb.InjuredSegment{Path: item.path}
-			err := repairQueue.Insert(ctx, injuredSeg, item.health)
+			err := repairQueue.Insert(ctx, injuredSeg, item.health, 0)
 			require.NoError(t, err)
 		}
 
@@ -248,7 +249,7 @@ func TestCount(t *testing.T) {
 		for i := 0; i < numSegments; i++ {
 			path := "/path/" + string(i)
 			injuredSeg := &pb.InjuredSegment{Path: []byte(path)}
-			err := repairQueue.Insert(ctx, injuredSeg, 10)
+			err := repairQueue.Insert(ctx, injuredSeg, 10, 0)
 			require.NoError(t, err)
 			pathsMap[path] = 0
 		}
@@ -259,3 +260,107 @@ func TestCount(t *testing.T) {
 	})
 
 }
+
+// TestOrderPriority ensures that the priority precedes the segment health.
+func TestOrderPriority(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		repairQueue := db.RepairQueue()
+
+		injuredSegList := []struct {
+			path     []byte
+			health   int
+			priority float64
+		}{
+			{[]byte("path/a"), 6, 1},
+			{[]byte("path/b"), 10, 5},
+			{[]byte("path/c"), 8, 1},
+		}
+		for _, item := range injuredSegList {
+			injuredSeg := &pb.InjuredSegment{Path: item.path}
+			err := repairQueue.Insert(ctx, injuredSeg, item.health, item.priority)
+			require.NoError(t, err)
+		}
+
+		for _, nextPath := range []string{
+			"path/b",
+			"path/a",
+			"path/c",
+		} {
+			injuredSeg, err := repairQueue.Select(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, nextPath, string(injuredSeg.Path))
+		}
+	})
+}
+
+func TestRecordFailure(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		repairQueue := db.RepairQueue()
+
+		backoff := queue.Backoff{Initial: time.Hour, Max: 4 * time.Hour}
+		seg := &pb.InjuredSegment{Path: []byte("path/a")}
+		require.NoError(t, repairQueue.Insert(ctx, seg, 10, 0))
+
+		// TODO: remove dependency on *dbx.DB
+		dbAccess := db.(interface{ TestDBAccess() *dbx.DB }).TestDBAccess()
+
+		attemptedAt := time.Now().Add(-30 * time.Minute).UTC().Truncate(time.Millisecond)
+		err := repairQueue.RecordFailure(ctx, queue.Attempt{
+			Path:          seg.Path,
+			AttemptedAt:   attemptedAt,
+			FailureReason: "not enough nodes",
+		}, backoff)
+		require.NoError(t, err)
+
+		// the repair is postponed, even after the attempt isn't recent anymore
+		_, err = dbAccess.ExecContext(ctx, dbAccess.Rebind(`UPDATE injuredsegments SET attempted = ? WHERE path = ?`), time.Now().Add(-7*time.Hour), seg.Path)
+		require.NoError(t, err)
+		_, err = repairQueue.Select(ctx)
+		require.True(t, storage.ErrEmptyQueue.Has(err))
+
+		err = repairQueue.RecordFailure(ctx, queue.Attempt{
+			Path:          seg.Path,
+			AttemptedAt:   attemptedAt.Add(time.Minute),
+			FailureReason: "upload failed",
+		}, backoff)
+		require.NoError(t, err)
+
+		var failures int
+		var retryAfter time.Time
+		err = dbAccess.QueryRowContext(ctx, dbAccess.Rebind(`SELECT failures, retry_after FROM injuredsegments WHERE path = ?`), seg.Path).Scan(&failures, &retryAfter)
+		require.NoError(t, err)
+		require.Equal(t, 2, failures)
+		require.WithinDuration(t, attemptedAt.Add(time.Minute+2*time.Hour), retryAfter, time.Second)
+
+		attempts, err := repairQueue.Attempts(ctx, seg.Path)
+		require.NoError(t, err)
+		require.Len(t, attempts, 2)
+		require.Equal(t, "upload failed", attempts[0].FailureReason)
+		require.Equal(t, "not enough nodes", attempts[1].FailureReason)
+		require.WithinDuration(t, attemptedAt, attempts[1].AttemptedAt, time.Second)
+
+		// the segment can be repaired again after the backoff
+		_, err = dbAccess.ExecContext(ctx, dbAccess.Rebind(`UPDATE injuredsegments SET retry_after = ? WHERE path = ?`), time.Now().Add(-time.Minute), seg.Path)
+		require.NoError(t, err)
+		injuredSeg, err := repairQueue.Select(ctx)
+		require.NoError(t, err)
+		require.Equal(t, seg.Path, injuredSeg.Path)
+
+		// deleting the segment deletes its history
+		require.NoError(t, repairQueue.Delete(ctx, seg))
+		attempts, err = repairQueue.Attempts(ctx, seg.Path)
+		require.NoError(t, err)
+		require.Empty(t, attempts)
+
+		// failures of segments which aren't queued anymore are ignored
+		err = repairQueue.RecordFailure(ctx, queue.Attempt{
+			Path:          seg.Path,
+			AttemptedAt:   time.Now(),
+			FailureReason: "upload failed",
+		}, backoff)
+		require.NoError(t, err)
+		attempts, err = repairQueue.Attempts(ctx, seg.Path)
+		require.NoError(t, err)
+		require.Empty(t, attempts)
+	})
+}
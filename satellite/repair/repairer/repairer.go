@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/sync/semaphore"
 
+	"storj.io/common/context2"
 	"storj.io/common/memory"
 	"storj.io/common/pb"
 	"storj.io/common/sync2"
@@ -36,6 +37,7 @@ type Config struct {
 	MaxBufferMem                  memory.Size   `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"4M"`
 	MaxExcessRateOptimalThreshold float64       `help:"ratio applied to the optimal threshold to calculate the excess of the maximum number of repaired pieces to upload" default:"0.05"`
 	InMemoryRepair                bool          `help:"whether to download pieces for repair in memory (true) or download to disk (false)" default:"false"`
+	Backoff                       queue.Backoff
 }
 
 // Service contains the information needed to run the repair service
@@ -184,6 +186,18 @@ func (service *Service) worker(ctx context.Context, seg *pb.InjuredSegment) (err
 				err = errs.Combine(err, Error.New("failed to remove segment from queue: %v", delErr))
 			}
 		}
+	} else if err != nil {
+		// the segment stays in the queue, postpone its next repair attempt
+		// to not keep failing on it. The repair may have failed because ctx
+		// timed out, hence the failure is recorded without it.
+		recordErr := service.queue.RecordFailure(context2.WithoutCancellation(ctx), queue.Attempt{
+			Path:          seg.GetPath(),
+			AttemptedAt:   workerStartTime,
+			FailureReason: err.Error(),
+		}, service.config.Backoff)
+		if recordErr != nil {
+			err = errs.Combine(err, Error.New("failed to record repair failure: %v", recordErr))
+		}
 	}
 	if err != nil {
 		return Error.Wrap(err)
@@ -199,10 +199,16 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, path storj.Path) (s
 		requestCount = int(totalNeeded) - len(healthyPieces)
 	}
 
+	placement, err := repairer.metainfo.GetPlacementForPath(ctx, path)
+	if err != nil {
+		return false, metainfoGetError.Wrap(err)
+	}
+
 	// Request Overlay for n-h new storage nodes
 	request := overlay.FindStorageNodesRequest{
 		RequestedCount: requestCount,
 		ExcludedIDs:    excludeNodeIDs,
+		Placement:      placement,
 	}
 	newNodes, err := repairer.overlay.FindStorageNodesForRepair(ctx, request)
 	if err != nil {
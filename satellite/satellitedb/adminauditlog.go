@@ -0,0 +1,118 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/admin"
+	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/satellitedb/dbx"
+)
+
+// ensures that adminAuditLog implements admin.AuditLog.
+var _ admin.AuditLog = (*adminAuditLog)(nil)
+
+type adminAuditLog struct {
+	db *satelliteDB
+}
+
+// WithTx runs fn in a transaction, so that the changes of an action are
+// committed together with its record.
+func (log *adminAuditLog) WithTx(ctx context.Context, fn func(ctx context.Context, tx admin.AuditLogTx) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return log.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		return fn(ctx, &adminAuditLogTx{db: log.db, tx: tx})
+	})
+}
+
+// List returns up to limit actions taken on target, the most recent first.
+// An empty target returns the actions taken on any target.
+func (log *adminAuditLog) List(ctx context.Context, target string, limit int) (entries []admin.AuditLogEntry, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := log.db.QueryContext(ctx, log.db.Rebind(`
+		SELECT id, operator, action, target, reason, created_at
+		FROM admin_audit_logs
+		WHERE ? = '' OR target = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`), target, target, limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var entry admin.AuditLogEntry
+		err = rows.Scan(&entry.ID, &entry.Operator, &entry.Action, &entry.Target, &entry.Reason, &entry.CreatedAt)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, Error.Wrap(rows.Err())
+}
+
+// adminAuditLogTx is a transaction of the audit log.
+type adminAuditLogTx struct {
+	db *satelliteDB
+	tx *dbx.Tx
+}
+
+// Insert records an action.
+func (logTx *adminAuditLogTx) Insert(ctx context.Context, entry admin.AuditLogEntry) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = logTx.tx.Tx.ExecContext(ctx, logTx.db.Rebind(`
+		INSERT INTO admin_audit_logs (id, operator, action, target, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`), entry.ID, entry.Operator, entry.Action, entry.Target, entry.Reason, entry.CreatedAt.UTC())
+	return Error.Wrap(err)
+}
+
+// Console returns the console database in the transaction.
+func (logTx *adminAuditLogTx) Console() console.DB {
+	return logTx.db.Console().(*ConsoleDB).withTx(logTx.tx)
+}
+
+// Nodes returns the node changes of the transaction.
+func (logTx *adminAuditLogTx) Nodes() admin.NodeChanges {
+	return &adminNodeChanges{tx: logTx.tx}
+}
+
+// adminNodeChanges changes the status of the nodes in a transaction.
+type adminNodeChanges struct {
+	tx *dbx.Tx
+}
+
+// DisqualifyNode disqualifies a storage node.
+func (changes *adminNodeChanges) DisqualifyNode(ctx context.Context, nodeID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return disqualifyNode(ctx, changes.tx, nodeID)
+}
+
+// ReinstateNode removes the disqualification of a storage node and resets its
+// audit reputation.
+func (changes *adminNodeChanges) ReinstateNode(ctx context.Context, nodeID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return reinstateNode(ctx, changes.tx, nodeID)
+}
+
+// UnsuspendNode unsuspends a storage node.
+func (changes *adminNodeChanges) UnsuspendNode(ctx context.Context, nodeID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return unsuspendNode(ctx, changes.tx, nodeID)
+}
+
+// UpdateExitStatus updates the graceful exit status of a storage node.
+func (changes *adminNodeChanges) UpdateExitStatus(ctx context.Context, request *overlay.ExitStatusRequest) (_ *overlay.NodeDossier, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return updateExitStatus(ctx, changes.tx, request)
+}
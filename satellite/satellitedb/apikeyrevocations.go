@@ -0,0 +1,54 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/console"
+)
+
+// ensures that apiKeyRevocations implements console.APIKeyRevocations.
+var _ console.APIKeyRevocations = (*apiKeyRevocations)(nil)
+
+type apiKeyRevocations struct {
+	db *satelliteDB
+}
+
+// Revoke adds the macaroon tail to the revocation list of the project.
+func (revocations *apiKeyRevocations) Revoke(ctx context.Context, projectID uuid.UUID, tail []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = revocations.db.ExecContext(ctx, revocations.db.Rebind(`
+		INSERT INTO api_key_revocations (project_id, tail, created_at)
+		VALUES (?, ?, now())
+		ON CONFLICT (project_id, tail) DO NOTHING
+	`), projectID[:], tail)
+	return Error.Wrap(err)
+}
+
+// GetByProjectID returns the revoked macaroon tails of the project.
+func (revocations *apiKeyRevocations) GetByProjectID(ctx context.Context, projectID uuid.UUID) (tails [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := revocations.db.QueryContext(ctx, revocations.db.Rebind(`
+		SELECT tail FROM api_key_revocations WHERE project_id = ?
+	`), projectID[:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var tail []byte
+		if err := rows.Scan(&tail); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		tails = append(tails, tail)
+	}
+	return tails, Error.Wrap(rows.Err())
+}
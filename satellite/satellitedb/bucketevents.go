@@ -0,0 +1,97 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/bucketevents"
+)
+
+// ensures that bucketEvents implements bucketevents.DB.
+var _ bucketevents.DB = (*bucketEvents)(nil)
+
+type bucketEvents struct {
+	db *satelliteDB
+}
+
+// Subscribed returns whether the bucket has subscriptions for eventType.
+func (events *bucketEvents) Subscribed(ctx context.Context, projectID uuid.UUID, bucketName []byte, eventType bucketevents.EventType) (subscribed bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = events.db.QueryRowContext(ctx, events.db.Rebind(`
+		SELECT EXISTS (
+			SELECT 1 FROM bucket_event_subscriptions
+			WHERE project_id = ? AND bucket_name = ? AND event_types & ? <> 0
+		)
+	`), projectID[:], bucketName, int(eventType)).Scan(&subscribed)
+	return subscribed, Error.Wrap(err)
+}
+
+// Enqueue adds the event payload to the outbox once for every subscription
+// of the bucket to eventType.
+func (events *bucketEvents) Enqueue(ctx context.Context, projectID uuid.UUID, bucketName []byte, eventType bucketevents.EventType, payload []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = events.db.ExecContext(ctx, events.db.Rebind(`
+		INSERT INTO bucket_events (subscription_id, payload, attempts, next_attempt_at, created_at)
+		SELECT id, ?, 0, now(), now() FROM bucket_event_subscriptions
+		WHERE project_id = ? AND bucket_name = ? AND event_types & ? <> 0
+	`), payload, projectID[:], bucketName, int(eventType))
+	return Error.Wrap(err)
+}
+
+// ListPending returns up to limit deliveries due before now, the oldest first.
+func (events *bucketEvents) ListPending(ctx context.Context, now time.Time, limit int) (deliveries []bucketevents.Delivery, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := events.db.QueryContext(ctx, events.db.Rebind(`
+		SELECT bucket_events.id, bucket_event_subscriptions.url, bucket_event_subscriptions.secret,
+			bucket_events.payload, bucket_events.attempts
+		FROM bucket_events
+		JOIN bucket_event_subscriptions ON bucket_event_subscriptions.id = bucket_events.subscription_id
+		WHERE bucket_events.next_attempt_at <= ?
+		ORDER BY bucket_events.id
+		LIMIT ?
+	`), now, limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var delivery bucketevents.Delivery
+		err := rows.Scan(&delivery.ID, &delivery.URL, &delivery.Secret, &delivery.Payload, &delivery.Attempts)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, Error.Wrap(rows.Err())
+}
+
+// Retry increments the attempts of the delivery and postpones it until nextAttemptAt.
+func (events *bucketEvents) Retry(ctx context.Context, id int64, nextAttemptAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = events.db.ExecContext(ctx, events.db.Rebind(`
+		UPDATE bucket_events SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id = ?
+	`), nextAttemptAt, id)
+	return Error.Wrap(err)
+}
+
+// Delete removes the delivery from the outbox.
+func (events *bucketEvents) Delete(ctx context.Context, id int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = events.db.ExecContext(ctx, events.db.Rebind(`
+		DELETE FROM bucket_events WHERE id = ?
+	`), id)
+	return Error.Wrap(err)
+}
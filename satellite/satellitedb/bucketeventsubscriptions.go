@@ -0,0 +1,87 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/bucketevents"
+	"storj.io/storj/satellite/console"
+)
+
+// ensures that bucketEventSubscriptions implements console.BucketEventSubscriptions.
+var _ console.BucketEventSubscriptions = (*bucketEventSubscriptions)(nil)
+
+type bucketEventSubscriptions struct {
+	db *satelliteDB
+}
+
+// Create inserts a new subscription.
+func (subscriptions *bucketEventSubscriptions) Create(ctx context.Context, subscription console.BucketEventSubscription) (_ *console.BucketEventSubscription, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = subscriptions.db.QueryRowContext(ctx, subscriptions.db.Rebind(`
+		INSERT INTO bucket_event_subscriptions (id, project_id, bucket_name, url, secret, event_types, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, now())
+		RETURNING created_at
+	`), subscription.ID[:], subscription.ProjectID[:], []byte(subscription.BucketName),
+		subscription.URL, subscription.Secret, int(subscription.EventTypes),
+	).Scan(&subscription.CreatedAt)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &subscription, nil
+}
+
+// GetByBucket returns the subscriptions of the bucket.
+func (subscriptions *bucketEventSubscriptions) GetByBucket(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ []console.BucketEventSubscription, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := subscriptions.db.QueryContext(ctx, subscriptions.db.Rebind(`
+		SELECT id, url, secret, event_types, created_at FROM bucket_event_subscriptions
+		WHERE project_id = ? AND bucket_name = ?
+		ORDER BY created_at
+	`), projectID[:], bucketName)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var result []console.BucketEventSubscription
+	for rows.Next() {
+		subscription := console.BucketEventSubscription{
+			ProjectID:  projectID,
+			BucketName: string(bucketName),
+		}
+
+		var id []byte
+		var eventTypes int
+		err := rows.Scan(&id, &subscription.URL, &subscription.Secret, &eventTypes, &subscription.CreatedAt)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		subscription.ID, err = uuid.FromBytes(id)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		subscription.EventTypes = bucketevents.EventType(eventTypes)
+
+		result = append(result, subscription)
+	}
+	return result, Error.Wrap(rows.Err())
+}
+
+// Delete deletes the subscription together with its pending events.
+func (subscriptions *bucketEventSubscriptions) Delete(ctx context.Context, projectID uuid.UUID, id uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = subscriptions.db.ExecContext(ctx, subscriptions.db.Rebind(`
+		DELETE FROM bucket_event_subscriptions WHERE project_id = ? AND id = ?
+	`), projectID[:], id[:])
+	return Error.Wrap(err)
+}
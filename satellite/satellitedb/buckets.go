@@ -12,6 +12,7 @@ import (
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
 	"storj.io/storj/satellite/satellitedb/dbx"
 )
 
@@ -189,6 +190,78 @@ func (db *bucketsDB) ListBuckets(ctx context.Context, projectID uuid.UUID, listO
 	return bucketList, nil
 }
 
+// GetBucketPlacement returns the placement constraint of a bucket
+func (db *bucketsDB) GetBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ overlay.PlacementConstraint, err error) {
+	defer mon.Task()(&ctx)(&err)
+	dbxBucket, err := db.db.Get_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return overlay.EveryCountry, storj.ErrBucketNotFound.New("%s", bucketName)
+		}
+		return overlay.EveryCountry, storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket.Placement == nil {
+		return overlay.EveryCountry, nil
+	}
+	return overlay.PlacementConstraint(*dbxBucket.Placement), nil
+}
+
+// UpdateBucketPlacement changes the placement constraint of a bucket
+func (db *bucketsDB) UpdateBucketPlacement(ctx context.Context, bucketName []byte, projectID uuid.UUID, placement overlay.PlacementConstraint) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var updateFields dbx.BucketMetainfo_Update_Fields
+	updateFields.Placement = dbx.BucketMetainfo_Placement(int(placement))
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx, dbx.BucketMetainfo_ProjectId(projectID[:]), dbx.BucketMetainfo_Name(bucketName), updateFields)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// GetBucketVersioning returns the versioning state of a bucket
+func (db *bucketsDB) GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ metainfo.Versioning, err error) {
+	defer mon.Task()(&ctx)(&err)
+	dbxBucket, err := db.db.Get_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return metainfo.Unversioned, storj.ErrBucketNotFound.New("%s", bucketName)
+		}
+		return metainfo.Unversioned, storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket.Versioning == nil {
+		return metainfo.Unversioned, nil
+	}
+	return metainfo.Versioning(*dbxBucket.Versioning), nil
+}
+
+// UpdateBucketVersioning changes the versioning state of a bucket
+func (db *bucketsDB) UpdateBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, versioning metainfo.Versioning) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var updateFields dbx.BucketMetainfo_Update_Fields
+	updateFields.Versioning = dbx.BucketMetainfo_Versioning(int(versioning))
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx, dbx.BucketMetainfo_ProjectId(projectID[:]), dbx.BucketMetainfo_Name(bucketName), updateFields)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
 func convertDBXtoBucket(dbxBucket *dbx.BucketMetainfo) (bucket storj.Bucket, err error) {
 	id, err := uuid.FromBytes(dbxBucket.Id)
 	if err != nil {
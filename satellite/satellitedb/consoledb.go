@@ -58,6 +58,16 @@ func (db *ConsoleDB) APIKeys() console.APIKeys {
 	return db.apikeys
 }
 
+// APIKeyRevocations is a getter for APIKeyRevocations repository.
+func (db *ConsoleDB) APIKeyRevocations() console.APIKeyRevocations {
+	return &apiKeyRevocations{db.db}
+}
+
+// BucketEventSubscriptions is a getter for BucketEventSubscriptions repository.
+func (db *ConsoleDB) BucketEventSubscriptions() console.BucketEventSubscriptions {
+	return &bucketEventSubscriptions{db.db}
+}
+
 // RegistrationTokens is a getter for RegistrationTokens repository.
 func (db *ConsoleDB) RegistrationTokens() console.RegistrationTokens {
 	return &registrationTokens{db.methods}
@@ -80,23 +90,25 @@ func (db *ConsoleDB) WithTx(ctx context.Context, fn func(context.Context, consol
 	}
 
 	return db.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
-		dbTx := &DBTx{
-			ConsoleDB: &ConsoleDB{
-				apikeysLRUOptions: db.apikeysLRUOptions,
-
-				// Need to expose dbx.DB for when database methods need access to check database driver type
-				db:      db.db,
-				tx:      tx,
-				methods: tx,
-
-				apikeysOnce: db.apikeysOnce,
-				apikeys:     db.apikeys,
-			},
-		}
-		return fn(ctx, dbTx)
+		return fn(ctx, &DBTx{ConsoleDB: db.withTx(tx)})
 	})
 }
 
+// withTx returns the console database in the transaction tx.
+func (db *ConsoleDB) withTx(tx *dbx.Tx) *ConsoleDB {
+	return &ConsoleDB{
+		apikeysLRUOptions: db.apikeysLRUOptions,
+
+		// Need to expose dbx.DB for when database methods need access to check database driver type
+		db:      db.db,
+		tx:      tx,
+		methods: tx,
+
+		apikeysOnce: db.apikeysOnce,
+		apikeys:     db.apikeys,
+	}
+}
+
 // DBTx extends Database with transaction scope.
 type DBTx struct {
 	*ConsoleDB
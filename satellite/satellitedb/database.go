@@ -14,8 +14,10 @@ import (
 	"storj.io/storj/private/dbutil/pgutil"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/admin"
 	"storj.io/storj/satellite/attribution"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/bucketevents"
 	"storj.io/storj/satellite/compensation"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/downtime"
@@ -184,6 +186,16 @@ func (db *satelliteDB) HeldAmount() heldamount.DB {
 	return &paymentStubs{db: db}
 }
 
+// AdminAuditLog returns the log of the actions taken through the admin API.
+func (db *satelliteDB) AdminAuditLog() admin.AuditLog {
+	return &adminAuditLog{db: db}
+}
+
+// BucketEvents returns the outbox of the bucket events.
+func (db *satelliteDB) BucketEvents() bucketevents.DB {
+	return &bucketEvents{db: db}
+}
+
 // Compenstation returns database for storage node compensation
 func (db *satelliteDB) Compensation() compensation.DB {
 	return &compensationDB{db: db}
@@ -289,6 +289,15 @@ CREATE TABLE accounting_timestamps (
 	value timestamp with time zone NOT NULL,
 	PRIMARY KEY ( name )
 );
+CREATE TABLE admin_audit_logs (
+	id bytea NOT NULL,
+	operator text NOT NULL,
+	action text NOT NULL,
+	target text NOT NULL,
+	reason text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_bandwidth_rollups (
 	bucket_name bytea NOT NULL,
 	project_id bytea NOT NULL,
@@ -373,6 +382,16 @@ CREATE TABLE graceful_exit_progress (
 	updated_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( node_id )
 );
+CREATE TABLE graceful_exit_shrinks (
+	node_id bytea NOT NULL,
+	target_bytes bigint NOT NULL,
+	queued_bytes bigint NOT NULL DEFAULT 0,
+	bytes_transferred bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	loop_completed_at timestamp with time zone,
+	finished_at timestamp with time zone,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE graceful_exit_transfer_queue (
 	node_id bytea NOT NULL,
 	path bytea NOT NULL,
@@ -393,6 +412,9 @@ CREATE TABLE injuredsegments (
 	data bytea NOT NULL,
 	attempted timestamp with time zone,
 	num_healthy_pieces integer NOT NULL DEFAULT 52,
+	priority double precision NOT NULL DEFAULT 0,
+	failures integer NOT NULL DEFAULT 0,
+	retry_after timestamp with time zone,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -403,6 +425,12 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE live_accounting_totals (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	total bigint NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL DEFAULT '',
@@ -443,6 +471,7 @@ CREATE TABLE nodes (
 	exit_loop_completed_at timestamp with time zone,
 	exit_finished_at timestamp with time zone,
 	exit_success boolean NOT NULL DEFAULT false,
+	country_code text,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE nodes_offline_times (
@@ -492,6 +521,11 @@ CREATE TABLE pending_serial_queue (
 	expires_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( storage_node_id, bucket_id, serial_number )
 );
+CREATE TABLE piece_references (
+	root_piece_id bytea NOT NULL,
+	reference_count integer NOT NULL,
+	PRIMARY KEY ( root_piece_id )
+);
 CREATE TABLE projects (
 	id bytea NOT NULL,
 	name text NOT NULL,
@@ -517,6 +551,12 @@ CREATE TABLE registration_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE repair_attempts (
+	path bytea NOT NULL,
+	attempted_at timestamp with time zone NOT NULL,
+	failure_reason text NOT NULL,
+	PRIMARY KEY ( path, attempted_at )
+);
 CREATE TABLE reported_serials (
 	expires_at timestamp with time zone NOT NULL,
 	storage_node_id bytea NOT NULL,
@@ -635,6 +675,12 @@ CREATE TABLE value_attributions (
 	last_updated timestamp with time zone NOT NULL,
 	PRIMARY KEY ( project_id, bucket_name )
 );
+CREATE TABLE api_key_revocations (
+	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+	tail bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( project_id, tail )
+);
 CREATE TABLE api_keys (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
@@ -647,6 +693,16 @@ CREATE TABLE api_keys (
 	UNIQUE ( head ),
 	UNIQUE ( name, project_id )
 );
+CREATE TABLE bucket_event_subscriptions (
+	id bytea NOT NULL,
+	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+	bucket_name bytea NOT NULL,
+	url text NOT NULL,
+	secret bytea NOT NULL,
+	event_types integer NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_metainfos (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ),
@@ -663,6 +719,10 @@ CREATE TABLE bucket_metainfos (
 	default_redundancy_repair_shares integer NOT NULL,
 	default_redundancy_optimal_shares integer NOT NULL,
 	default_redundancy_total_shares integer NOT NULL,
+	placement integer,
+	versioning integer,
+	storage_limit bigint,
+	bandwidth_limit bigint,
 	PRIMARY KEY ( id ),
 	UNIQUE ( name, project_id )
 );
@@ -705,7 +765,17 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE TABLE bucket_events (
+	id bigserial NOT NULL,
+	subscription_id bytea NOT NULL REFERENCES bucket_event_subscriptions( id ) ON DELETE CASCADE,
+	payload bytea NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamp with time zone NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE INDEX accounting_rollups_start_time_index ON accounting_rollups ( start_time );
+CREATE INDEX admin_audit_logs_target_index ON admin_audit_logs ( target );
 CREATE INDEX bucket_bandwidth_rollups_project_id_action_interval_index ON bucket_bandwidth_rollups ( project_id, action, interval_start );
 CREATE INDEX bucket_bandwidth_rollups_action_interval_project_id_index ON bucket_bandwidth_rollups ( action, interval_start, project_id );
 CREATE INDEX consumed_serials_expires_at_index ON consumed_serials ( expires_at );
@@ -718,7 +788,9 @@ CREATE INDEX serial_numbers_expires_at_index ON serial_numbers ( expires_at );
 CREATE INDEX storagenode_payments_node_id_period_index ON storagenode_payments ( node_id, period );
 CREATE INDEX storagenode_paystubs_node_id_index ON storagenode_paystubs ( node_id );
 CREATE INDEX storagenode_storage_tallies_node_id_index ON storagenode_storage_tallies ( node_id );
-CREATE UNIQUE INDEX credits_earned_user_id_offer_id ON user_credits ( id, offer_id );`
+CREATE INDEX bucket_event_subscriptions_project_id_bucket_name_index ON bucket_event_subscriptions ( project_id, bucket_name );
+CREATE UNIQUE INDEX credits_earned_user_id_offer_id ON user_credits ( id, offer_id );
+CREATE INDEX bucket_events_next_attempt_at_index ON bucket_events ( next_attempt_at );`
 }
 
 func (obj *postgresDB) wrapTx(tx tagsql.Tx) txMethods {
@@ -799,6 +871,15 @@ CREATE TABLE accounting_timestamps (
 	value timestamp with time zone NOT NULL,
 	PRIMARY KEY ( name )
 );
+CREATE TABLE admin_audit_logs (
+	id bytea NOT NULL,
+	operator text NOT NULL,
+	action text NOT NULL,
+	target text NOT NULL,
+	reason text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_bandwidth_rollups (
 	bucket_name bytea NOT NULL,
 	project_id bytea NOT NULL,
@@ -883,6 +964,16 @@ CREATE TABLE graceful_exit_progress (
 	updated_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( node_id )
 );
+CREATE TABLE graceful_exit_shrinks (
+	node_id bytea NOT NULL,
+	target_bytes bigint NOT NULL,
+	queued_bytes bigint NOT NULL DEFAULT 0,
+	bytes_transferred bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	loop_completed_at timestamp with time zone,
+	finished_at timestamp with time zone,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE graceful_exit_transfer_queue (
 	node_id bytea NOT NULL,
 	path bytea NOT NULL,
@@ -903,6 +994,9 @@ CREATE TABLE injuredsegments (
 	data bytea NOT NULL,
 	attempted timestamp with time zone,
 	num_healthy_pieces integer NOT NULL DEFAULT 52,
+	priority double precision NOT NULL DEFAULT 0,
+	failures integer NOT NULL DEFAULT 0,
+	retry_after timestamp with time zone,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -913,6 +1007,12 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE live_accounting_totals (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	total bigint NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL DEFAULT '',
@@ -953,6 +1053,7 @@ CREATE TABLE nodes (
 	exit_loop_completed_at timestamp with time zone,
 	exit_finished_at timestamp with time zone,
 	exit_success boolean NOT NULL DEFAULT false,
+	country_code text,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE nodes_offline_times (
@@ -1002,6 +1103,11 @@ CREATE TABLE pending_serial_queue (
 	expires_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( storage_node_id, bucket_id, serial_number )
 );
+CREATE TABLE piece_references (
+	root_piece_id bytea NOT NULL,
+	reference_count integer NOT NULL,
+	PRIMARY KEY ( root_piece_id )
+);
 CREATE TABLE projects (
 	id bytea NOT NULL,
 	name text NOT NULL,
@@ -1027,6 +1133,12 @@ CREATE TABLE registration_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE repair_attempts (
+	path bytea NOT NULL,
+	attempted_at timestamp with time zone NOT NULL,
+	failure_reason text NOT NULL,
+	PRIMARY KEY ( path, attempted_at )
+);
 CREATE TABLE reported_serials (
 	expires_at timestamp with time zone NOT NULL,
 	storage_node_id bytea NOT NULL,
@@ -1145,6 +1257,12 @@ CREATE TABLE value_attributions (
 	last_updated timestamp with time zone NOT NULL,
 	PRIMARY KEY ( project_id, bucket_name )
 );
+CREATE TABLE api_key_revocations (
+	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+	tail bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( project_id, tail )
+);
 CREATE TABLE api_keys (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
@@ -1157,6 +1275,16 @@ CREATE TABLE api_keys (
 	UNIQUE ( head ),
 	UNIQUE ( name, project_id )
 );
+CREATE TABLE bucket_event_subscriptions (
+	id bytea NOT NULL,
+	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+	bucket_name bytea NOT NULL,
+	url text NOT NULL,
+	secret bytea NOT NULL,
+	event_types integer NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_metainfos (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ),
@@ -1173,6 +1301,10 @@ CREATE TABLE bucket_metainfos (
 	default_redundancy_repair_shares integer NOT NULL,
 	default_redundancy_optimal_shares integer NOT NULL,
 	default_redundancy_total_shares integer NOT NULL,
+	placement integer,
+	versioning integer,
+	storage_limit bigint,
+	bandwidth_limit bigint,
 	PRIMARY KEY ( id ),
 	UNIQUE ( name, project_id )
 );
@@ -1215,7 +1347,17 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE TABLE bucket_events (
+	id bigserial NOT NULL,
+	subscription_id bytea NOT NULL REFERENCES bucket_event_subscriptions( id ) ON DELETE CASCADE,
+	payload bytea NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamp with time zone NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE INDEX accounting_rollups_start_time_index ON accounting_rollups ( start_time );
+CREATE INDEX admin_audit_logs_target_index ON admin_audit_logs ( target );
 CREATE INDEX bucket_bandwidth_rollups_project_id_action_interval_index ON bucket_bandwidth_rollups ( project_id, action, interval_start );
 CREATE INDEX bucket_bandwidth_rollups_action_interval_project_id_index ON bucket_bandwidth_rollups ( action, interval_start, project_id );
 CREATE INDEX consumed_serials_expires_at_index ON consumed_serials ( expires_at );
@@ -1228,7 +1370,9 @@ CREATE INDEX serial_numbers_expires_at_index ON serial_numbers ( expires_at );
 CREATE INDEX storagenode_payments_node_id_period_index ON storagenode_payments ( node_id, period );
 CREATE INDEX storagenode_paystubs_node_id_index ON storagenode_paystubs ( node_id );
 CREATE INDEX storagenode_storage_tallies_node_id_index ON storagenode_storage_tallies ( node_id );
-CREATE UNIQUE INDEX credits_earned_user_id_offer_id ON user_credits ( id, offer_id );`
+CREATE INDEX bucket_event_subscriptions_project_id_bucket_name_index ON bucket_event_subscriptions ( project_id, bucket_name );
+CREATE UNIQUE INDEX credits_earned_user_id_offer_id ON user_credits ( id, offer_id );
+CREATE INDEX bucket_events_next_attempt_at_index ON bucket_events ( next_attempt_at );`
 }
 
 func (obj *cockroachDB) wrapTx(tx tagsql.Tx) txMethods {
@@ -1528,6 +1672,134 @@ func (f AccountingTimestamps_Value_Field) value() interface{} {
 
 func (AccountingTimestamps_Value_Field) _Column() string { return "value" }
 
+type AdminAuditLog struct {
+	Id        []byte
+	Operator  string
+	Action    string
+	Target    string
+	Reason    string
+	CreatedAt time.Time
+}
+
+func (AdminAuditLog) _Table() string { return "admin_audit_logs" }
+
+type AdminAuditLog_Update_Fields struct {
+}
+
+type AdminAuditLog_Id_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func AdminAuditLog_Id(v []byte) AdminAuditLog_Id_Field {
+	return AdminAuditLog_Id_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditLog_Id_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditLog_Id_Field) _Column() string { return "id" }
+
+type AdminAuditLog_Operator_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditLog_Operator(v string) AdminAuditLog_Operator_Field {
+	return AdminAuditLog_Operator_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditLog_Operator_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditLog_Operator_Field) _Column() string { return "operator" }
+
+type AdminAuditLog_Action_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditLog_Action(v string) AdminAuditLog_Action_Field {
+	return AdminAuditLog_Action_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditLog_Action_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditLog_Action_Field) _Column() string { return "action" }
+
+type AdminAuditLog_Target_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditLog_Target(v string) AdminAuditLog_Target_Field {
+	return AdminAuditLog_Target_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditLog_Target_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditLog_Target_Field) _Column() string { return "target" }
+
+type AdminAuditLog_Reason_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditLog_Reason(v string) AdminAuditLog_Reason_Field {
+	return AdminAuditLog_Reason_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditLog_Reason_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditLog_Reason_Field) _Column() string { return "reason" }
+
+type AdminAuditLog_CreatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func AdminAuditLog_CreatedAt(v time.Time) AdminAuditLog_CreatedAt_Field {
+	return AdminAuditLog_CreatedAt_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditLog_CreatedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditLog_CreatedAt_Field) _Column() string { return "created_at" }
+
 type BucketBandwidthRollup struct {
 	BucketName      []byte
 	ProjectId       []byte
@@ -2751,207 +3023,396 @@ func (f GracefulExitProgress_UpdatedAt_Field) value() interface{} {
 
 func (GracefulExitProgress_UpdatedAt_Field) _Column() string { return "updated_at" }
 
-type GracefulExitTransferQueue struct {
-	NodeId              []byte
-	Path                []byte
-	PieceNum            int
-	RootPieceId         []byte
-	DurabilityRatio     float64
-	QueuedAt            time.Time
-	RequestedAt         *time.Time
-	LastFailedAt        *time.Time
-	LastFailedCode      *int
-	FailedCount         *int
-	FinishedAt          *time.Time
-	OrderLimitSendCount int
+type GracefulExitShrink struct {
+	NodeId           []byte
+	TargetBytes      int64
+	QueuedBytes      int64
+	BytesTransferred int64
+	CreatedAt        time.Time
+	LoopCompletedAt  *time.Time
+	FinishedAt       *time.Time
 }
 
-func (GracefulExitTransferQueue) _Table() string { return "graceful_exit_transfer_queue" }
+func (GracefulExitShrink) _Table() string { return "graceful_exit_shrinks" }
 
-type GracefulExitTransferQueue_Create_Fields struct {
-	RootPieceId         GracefulExitTransferQueue_RootPieceId_Field
-	RequestedAt         GracefulExitTransferQueue_RequestedAt_Field
-	LastFailedAt        GracefulExitTransferQueue_LastFailedAt_Field
-	LastFailedCode      GracefulExitTransferQueue_LastFailedCode_Field
-	FailedCount         GracefulExitTransferQueue_FailedCount_Field
-	FinishedAt          GracefulExitTransferQueue_FinishedAt_Field
-	OrderLimitSendCount GracefulExitTransferQueue_OrderLimitSendCount_Field
+type GracefulExitShrink_Create_Fields struct {
+	QueuedBytes      GracefulExitShrink_QueuedBytes_Field
+	BytesTransferred GracefulExitShrink_BytesTransferred_Field
+	LoopCompletedAt  GracefulExitShrink_LoopCompletedAt_Field
+	FinishedAt       GracefulExitShrink_FinishedAt_Field
 }
 
-type GracefulExitTransferQueue_Update_Fields struct {
-	DurabilityRatio     GracefulExitTransferQueue_DurabilityRatio_Field
-	RequestedAt         GracefulExitTransferQueue_RequestedAt_Field
-	LastFailedAt        GracefulExitTransferQueue_LastFailedAt_Field
-	LastFailedCode      GracefulExitTransferQueue_LastFailedCode_Field
-	FailedCount         GracefulExitTransferQueue_FailedCount_Field
-	FinishedAt          GracefulExitTransferQueue_FinishedAt_Field
-	OrderLimitSendCount GracefulExitTransferQueue_OrderLimitSendCount_Field
+type GracefulExitShrink_Update_Fields struct {
+	QueuedBytes      GracefulExitShrink_QueuedBytes_Field
+	BytesTransferred GracefulExitShrink_BytesTransferred_Field
+	LoopCompletedAt  GracefulExitShrink_LoopCompletedAt_Field
+	FinishedAt       GracefulExitShrink_FinishedAt_Field
 }
 
-type GracefulExitTransferQueue_NodeId_Field struct {
+type GracefulExitShrink_NodeId_Field struct {
 	_set   bool
 	_null  bool
 	_value []byte
 }
 
-func GracefulExitTransferQueue_NodeId(v []byte) GracefulExitTransferQueue_NodeId_Field {
-	return GracefulExitTransferQueue_NodeId_Field{_set: true, _value: v}
+func GracefulExitShrink_NodeId(v []byte) GracefulExitShrink_NodeId_Field {
+	return GracefulExitShrink_NodeId_Field{_set: true, _value: v}
 }
 
-func (f GracefulExitTransferQueue_NodeId_Field) value() interface{} {
+func (f GracefulExitShrink_NodeId_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (GracefulExitTransferQueue_NodeId_Field) _Column() string { return "node_id" }
+func (GracefulExitShrink_NodeId_Field) _Column() string { return "node_id" }
 
-type GracefulExitTransferQueue_Path_Field struct {
+type GracefulExitShrink_TargetBytes_Field struct {
 	_set   bool
 	_null  bool
-	_value []byte
+	_value int64
 }
 
-func GracefulExitTransferQueue_Path(v []byte) GracefulExitTransferQueue_Path_Field {
-	return GracefulExitTransferQueue_Path_Field{_set: true, _value: v}
+func GracefulExitShrink_TargetBytes(v int64) GracefulExitShrink_TargetBytes_Field {
+	return GracefulExitShrink_TargetBytes_Field{_set: true, _value: v}
 }
 
-func (f GracefulExitTransferQueue_Path_Field) value() interface{} {
+func (f GracefulExitShrink_TargetBytes_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (GracefulExitTransferQueue_Path_Field) _Column() string { return "path" }
+func (GracefulExitShrink_TargetBytes_Field) _Column() string { return "target_bytes" }
 
-type GracefulExitTransferQueue_PieceNum_Field struct {
+type GracefulExitShrink_QueuedBytes_Field struct {
 	_set   bool
 	_null  bool
-	_value int
+	_value int64
 }
 
-func GracefulExitTransferQueue_PieceNum(v int) GracefulExitTransferQueue_PieceNum_Field {
-	return GracefulExitTransferQueue_PieceNum_Field{_set: true, _value: v}
+func GracefulExitShrink_QueuedBytes(v int64) GracefulExitShrink_QueuedBytes_Field {
+	return GracefulExitShrink_QueuedBytes_Field{_set: true, _value: v}
 }
 
-func (f GracefulExitTransferQueue_PieceNum_Field) value() interface{} {
+func (f GracefulExitShrink_QueuedBytes_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (GracefulExitTransferQueue_PieceNum_Field) _Column() string { return "piece_num" }
+func (GracefulExitShrink_QueuedBytes_Field) _Column() string { return "queued_bytes" }
 
-type GracefulExitTransferQueue_RootPieceId_Field struct {
+type GracefulExitShrink_BytesTransferred_Field struct {
 	_set   bool
 	_null  bool
-	_value []byte
+	_value int64
 }
 
-func GracefulExitTransferQueue_RootPieceId(v []byte) GracefulExitTransferQueue_RootPieceId_Field {
-	return GracefulExitTransferQueue_RootPieceId_Field{_set: true, _value: v}
+func GracefulExitShrink_BytesTransferred(v int64) GracefulExitShrink_BytesTransferred_Field {
+	return GracefulExitShrink_BytesTransferred_Field{_set: true, _value: v}
 }
 
-func GracefulExitTransferQueue_RootPieceId_Raw(v []byte) GracefulExitTransferQueue_RootPieceId_Field {
-	if v == nil {
-		return GracefulExitTransferQueue_RootPieceId_Null()
+func (f GracefulExitShrink_BytesTransferred_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
 	}
-	return GracefulExitTransferQueue_RootPieceId(v)
+	return f._value
 }
 
-func GracefulExitTransferQueue_RootPieceId_Null() GracefulExitTransferQueue_RootPieceId_Field {
-	return GracefulExitTransferQueue_RootPieceId_Field{_set: true, _null: true}
+func (GracefulExitShrink_BytesTransferred_Field) _Column() string { return "bytes_transferred" }
+
+type GracefulExitShrink_CreatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
 }
 
-func (f GracefulExitTransferQueue_RootPieceId_Field) isnull() bool {
-	return !f._set || f._null || f._value == nil
+func GracefulExitShrink_CreatedAt(v time.Time) GracefulExitShrink_CreatedAt_Field {
+	return GracefulExitShrink_CreatedAt_Field{_set: true, _value: v}
 }
 
-func (f GracefulExitTransferQueue_RootPieceId_Field) value() interface{} {
+func (f GracefulExitShrink_CreatedAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (GracefulExitTransferQueue_RootPieceId_Field) _Column() string { return "root_piece_id" }
+func (GracefulExitShrink_CreatedAt_Field) _Column() string { return "created_at" }
 
-type GracefulExitTransferQueue_DurabilityRatio_Field struct {
+type GracefulExitShrink_LoopCompletedAt_Field struct {
 	_set   bool
 	_null  bool
-	_value float64
+	_value *time.Time
 }
 
-func GracefulExitTransferQueue_DurabilityRatio(v float64) GracefulExitTransferQueue_DurabilityRatio_Field {
-	return GracefulExitTransferQueue_DurabilityRatio_Field{_set: true, _value: v}
+func GracefulExitShrink_LoopCompletedAt(v time.Time) GracefulExitShrink_LoopCompletedAt_Field {
+	return GracefulExitShrink_LoopCompletedAt_Field{_set: true, _value: &v}
 }
 
-func (f GracefulExitTransferQueue_DurabilityRatio_Field) value() interface{} {
-	if !f._set || f._null {
-		return nil
+func GracefulExitShrink_LoopCompletedAt_Raw(v *time.Time) GracefulExitShrink_LoopCompletedAt_Field {
+	if v == nil {
+		return GracefulExitShrink_LoopCompletedAt_Null()
 	}
-	return f._value
+	return GracefulExitShrink_LoopCompletedAt(*v)
 }
 
-func (GracefulExitTransferQueue_DurabilityRatio_Field) _Column() string { return "durability_ratio" }
-
-type GracefulExitTransferQueue_QueuedAt_Field struct {
-	_set   bool
-	_null  bool
-	_value time.Time
+func GracefulExitShrink_LoopCompletedAt_Null() GracefulExitShrink_LoopCompletedAt_Field {
+	return GracefulExitShrink_LoopCompletedAt_Field{_set: true, _null: true}
 }
 
-func GracefulExitTransferQueue_QueuedAt(v time.Time) GracefulExitTransferQueue_QueuedAt_Field {
-	return GracefulExitTransferQueue_QueuedAt_Field{_set: true, _value: v}
+func (f GracefulExitShrink_LoopCompletedAt_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
 }
 
-func (f GracefulExitTransferQueue_QueuedAt_Field) value() interface{} {
+func (f GracefulExitShrink_LoopCompletedAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (GracefulExitTransferQueue_QueuedAt_Field) _Column() string { return "queued_at" }
+func (GracefulExitShrink_LoopCompletedAt_Field) _Column() string { return "loop_completed_at" }
 
-type GracefulExitTransferQueue_RequestedAt_Field struct {
+type GracefulExitShrink_FinishedAt_Field struct {
 	_set   bool
 	_null  bool
 	_value *time.Time
 }
 
-func GracefulExitTransferQueue_RequestedAt(v time.Time) GracefulExitTransferQueue_RequestedAt_Field {
-	return GracefulExitTransferQueue_RequestedAt_Field{_set: true, _value: &v}
+func GracefulExitShrink_FinishedAt(v time.Time) GracefulExitShrink_FinishedAt_Field {
+	return GracefulExitShrink_FinishedAt_Field{_set: true, _value: &v}
 }
 
-func GracefulExitTransferQueue_RequestedAt_Raw(v *time.Time) GracefulExitTransferQueue_RequestedAt_Field {
+func GracefulExitShrink_FinishedAt_Raw(v *time.Time) GracefulExitShrink_FinishedAt_Field {
 	if v == nil {
-		return GracefulExitTransferQueue_RequestedAt_Null()
+		return GracefulExitShrink_FinishedAt_Null()
 	}
-	return GracefulExitTransferQueue_RequestedAt(*v)
+	return GracefulExitShrink_FinishedAt(*v)
 }
 
-func GracefulExitTransferQueue_RequestedAt_Null() GracefulExitTransferQueue_RequestedAt_Field {
-	return GracefulExitTransferQueue_RequestedAt_Field{_set: true, _null: true}
+func GracefulExitShrink_FinishedAt_Null() GracefulExitShrink_FinishedAt_Field {
+	return GracefulExitShrink_FinishedAt_Field{_set: true, _null: true}
 }
 
-func (f GracefulExitTransferQueue_RequestedAt_Field) isnull() bool {
+func (f GracefulExitShrink_FinishedAt_Field) isnull() bool {
 	return !f._set || f._null || f._value == nil
 }
 
-func (f GracefulExitTransferQueue_RequestedAt_Field) value() interface{} {
+func (f GracefulExitShrink_FinishedAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (GracefulExitTransferQueue_RequestedAt_Field) _Column() string { return "requested_at" }
+func (GracefulExitShrink_FinishedAt_Field) _Column() string { return "finished_at" }
 
-type GracefulExitTransferQueue_LastFailedAt_Field struct {
+type GracefulExitTransferQueue struct {
+	NodeId              []byte
+	Path                []byte
+	PieceNum            int
+	RootPieceId         []byte
+	DurabilityRatio     float64
+	QueuedAt            time.Time
+	RequestedAt         *time.Time
+	LastFailedAt        *time.Time
+	LastFailedCode      *int
+	FailedCount         *int
+	FinishedAt          *time.Time
+	OrderLimitSendCount int
+}
+
+func (GracefulExitTransferQueue) _Table() string { return "graceful_exit_transfer_queue" }
+
+type GracefulExitTransferQueue_Create_Fields struct {
+	RootPieceId         GracefulExitTransferQueue_RootPieceId_Field
+	RequestedAt         GracefulExitTransferQueue_RequestedAt_Field
+	LastFailedAt        GracefulExitTransferQueue_LastFailedAt_Field
+	LastFailedCode      GracefulExitTransferQueue_LastFailedCode_Field
+	FailedCount         GracefulExitTransferQueue_FailedCount_Field
+	FinishedAt          GracefulExitTransferQueue_FinishedAt_Field
+	OrderLimitSendCount GracefulExitTransferQueue_OrderLimitSendCount_Field
+}
+
+type GracefulExitTransferQueue_Update_Fields struct {
+	DurabilityRatio     GracefulExitTransferQueue_DurabilityRatio_Field
+	RequestedAt         GracefulExitTransferQueue_RequestedAt_Field
+	LastFailedAt        GracefulExitTransferQueue_LastFailedAt_Field
+	LastFailedCode      GracefulExitTransferQueue_LastFailedCode_Field
+	FailedCount         GracefulExitTransferQueue_FailedCount_Field
+	FinishedAt          GracefulExitTransferQueue_FinishedAt_Field
+	OrderLimitSendCount GracefulExitTransferQueue_OrderLimitSendCount_Field
+}
+
+type GracefulExitTransferQueue_NodeId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func GracefulExitTransferQueue_NodeId(v []byte) GracefulExitTransferQueue_NodeId_Field {
+	return GracefulExitTransferQueue_NodeId_Field{_set: true, _value: v}
+}
+
+func (f GracefulExitTransferQueue_NodeId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (GracefulExitTransferQueue_NodeId_Field) _Column() string { return "node_id" }
+
+type GracefulExitTransferQueue_Path_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func GracefulExitTransferQueue_Path(v []byte) GracefulExitTransferQueue_Path_Field {
+	return GracefulExitTransferQueue_Path_Field{_set: true, _value: v}
+}
+
+func (f GracefulExitTransferQueue_Path_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (GracefulExitTransferQueue_Path_Field) _Column() string { return "path" }
+
+type GracefulExitTransferQueue_PieceNum_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func GracefulExitTransferQueue_PieceNum(v int) GracefulExitTransferQueue_PieceNum_Field {
+	return GracefulExitTransferQueue_PieceNum_Field{_set: true, _value: v}
+}
+
+func (f GracefulExitTransferQueue_PieceNum_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (GracefulExitTransferQueue_PieceNum_Field) _Column() string { return "piece_num" }
+
+type GracefulExitTransferQueue_RootPieceId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func GracefulExitTransferQueue_RootPieceId(v []byte) GracefulExitTransferQueue_RootPieceId_Field {
+	return GracefulExitTransferQueue_RootPieceId_Field{_set: true, _value: v}
+}
+
+func GracefulExitTransferQueue_RootPieceId_Raw(v []byte) GracefulExitTransferQueue_RootPieceId_Field {
+	if v == nil {
+		return GracefulExitTransferQueue_RootPieceId_Null()
+	}
+	return GracefulExitTransferQueue_RootPieceId(v)
+}
+
+func GracefulExitTransferQueue_RootPieceId_Null() GracefulExitTransferQueue_RootPieceId_Field {
+	return GracefulExitTransferQueue_RootPieceId_Field{_set: true, _null: true}
+}
+
+func (f GracefulExitTransferQueue_RootPieceId_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f GracefulExitTransferQueue_RootPieceId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (GracefulExitTransferQueue_RootPieceId_Field) _Column() string { return "root_piece_id" }
+
+type GracefulExitTransferQueue_DurabilityRatio_Field struct {
+	_set   bool
+	_null  bool
+	_value float64
+}
+
+func GracefulExitTransferQueue_DurabilityRatio(v float64) GracefulExitTransferQueue_DurabilityRatio_Field {
+	return GracefulExitTransferQueue_DurabilityRatio_Field{_set: true, _value: v}
+}
+
+func (f GracefulExitTransferQueue_DurabilityRatio_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (GracefulExitTransferQueue_DurabilityRatio_Field) _Column() string { return "durability_ratio" }
+
+type GracefulExitTransferQueue_QueuedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func GracefulExitTransferQueue_QueuedAt(v time.Time) GracefulExitTransferQueue_QueuedAt_Field {
+	return GracefulExitTransferQueue_QueuedAt_Field{_set: true, _value: v}
+}
+
+func (f GracefulExitTransferQueue_QueuedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (GracefulExitTransferQueue_QueuedAt_Field) _Column() string { return "queued_at" }
+
+type GracefulExitTransferQueue_RequestedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func GracefulExitTransferQueue_RequestedAt(v time.Time) GracefulExitTransferQueue_RequestedAt_Field {
+	return GracefulExitTransferQueue_RequestedAt_Field{_set: true, _value: &v}
+}
+
+func GracefulExitTransferQueue_RequestedAt_Raw(v *time.Time) GracefulExitTransferQueue_RequestedAt_Field {
+	if v == nil {
+		return GracefulExitTransferQueue_RequestedAt_Null()
+	}
+	return GracefulExitTransferQueue_RequestedAt(*v)
+}
+
+func GracefulExitTransferQueue_RequestedAt_Null() GracefulExitTransferQueue_RequestedAt_Field {
+	return GracefulExitTransferQueue_RequestedAt_Field{_set: true, _null: true}
+}
+
+func (f GracefulExitTransferQueue_RequestedAt_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f GracefulExitTransferQueue_RequestedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (GracefulExitTransferQueue_RequestedAt_Field) _Column() string { return "requested_at" }
+
+type GracefulExitTransferQueue_LastFailedAt_Field struct {
 	_set   bool
 	_null  bool
 	_value *time.Time
@@ -3113,6 +3574,9 @@ type Injuredsegment struct {
 	Data             []byte
 	Attempted        *time.Time
 	NumHealthyPieces int
+	Priority         float64
+	Failures         int
+	RetryAfter       *time.Time
 }
 
 func (Injuredsegment) _Table() string { return "injuredsegments" }
@@ -3120,10 +3584,16 @@ func (Injuredsegment) _Table() string { return "injuredsegments" }
 type Injuredsegment_Create_Fields struct {
 	Attempted        Injuredsegment_Attempted_Field
 	NumHealthyPieces Injuredsegment_NumHealthyPieces_Field
+	Priority         Injuredsegment_Priority_Field
+	Failures         Injuredsegment_Failures_Field
+	RetryAfter       Injuredsegment_RetryAfter_Field
 }
 
 type Injuredsegment_Update_Fields struct {
-	Attempted Injuredsegment_Attempted_Field
+	Attempted  Injuredsegment_Attempted_Field
+	Priority   Injuredsegment_Priority_Field
+	Failures   Injuredsegment_Failures_Field
+	RetryAfter Injuredsegment_RetryAfter_Field
 }
 
 type Injuredsegment_Path_Field struct {
@@ -3215,6 +3685,76 @@ func (f Injuredsegment_NumHealthyPieces_Field) value() interface{} {
 
 func (Injuredsegment_NumHealthyPieces_Field) _Column() string { return "num_healthy_pieces" }
 
+type Injuredsegment_Priority_Field struct {
+	_set   bool
+	_null  bool
+	_value float64
+}
+
+func Injuredsegment_Priority(v float64) Injuredsegment_Priority_Field {
+	return Injuredsegment_Priority_Field{_set: true, _value: v}
+}
+
+func (f Injuredsegment_Priority_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Injuredsegment_Priority_Field) _Column() string { return "priority" }
+
+type Injuredsegment_Failures_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func Injuredsegment_Failures(v int) Injuredsegment_Failures_Field {
+	return Injuredsegment_Failures_Field{_set: true, _value: v}
+}
+
+func (f Injuredsegment_Failures_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Injuredsegment_Failures_Field) _Column() string { return "failures" }
+
+type Injuredsegment_RetryAfter_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func Injuredsegment_RetryAfter(v time.Time) Injuredsegment_RetryAfter_Field {
+	return Injuredsegment_RetryAfter_Field{_set: true, _value: &v}
+}
+
+func Injuredsegment_RetryAfter_Raw(v *time.Time) Injuredsegment_RetryAfter_Field {
+	if v == nil {
+		return Injuredsegment_RetryAfter_Null()
+	}
+	return Injuredsegment_RetryAfter(*v)
+}
+
+func Injuredsegment_RetryAfter_Null() Injuredsegment_RetryAfter_Field {
+	return Injuredsegment_RetryAfter_Field{_set: true, _null: true}
+}
+
+func (f Injuredsegment_RetryAfter_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f Injuredsegment_RetryAfter_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Injuredsegment_RetryAfter_Field) _Column() string { return "retry_after" }
+
 type Irreparabledb struct {
 	Segmentpath        []byte
 	Segmentdetail      []byte
@@ -3327,6 +3867,75 @@ func (f Irreparabledb_RepairAttemptCount_Field) value() interface{} {
 
 func (Irreparabledb_RepairAttemptCount_Field) _Column() string { return "repair_attempt_count" }
 
+type LiveAccountingTotal struct {
+	ProjectId  []byte
+	BucketName []byte
+	Total      int64
+}
+
+func (LiveAccountingTotal) _Table() string { return "live_accounting_totals" }
+
+type LiveAccountingTotal_Update_Fields struct {
+	Total LiveAccountingTotal_Total_Field
+}
+
+type LiveAccountingTotal_ProjectId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func LiveAccountingTotal_ProjectId(v []byte) LiveAccountingTotal_ProjectId_Field {
+	return LiveAccountingTotal_ProjectId_Field{_set: true, _value: v}
+}
+
+func (f LiveAccountingTotal_ProjectId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (LiveAccountingTotal_ProjectId_Field) _Column() string { return "project_id" }
+
+type LiveAccountingTotal_BucketName_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func LiveAccountingTotal_BucketName(v []byte) LiveAccountingTotal_BucketName_Field {
+	return LiveAccountingTotal_BucketName_Field{_set: true, _value: v}
+}
+
+func (f LiveAccountingTotal_BucketName_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (LiveAccountingTotal_BucketName_Field) _Column() string { return "bucket_name" }
+
+type LiveAccountingTotal_Total_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func LiveAccountingTotal_Total(v int64) LiveAccountingTotal_Total_Field {
+	return LiveAccountingTotal_Total_Field{_set: true, _value: v}
+}
+
+func (f LiveAccountingTotal_Total_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (LiveAccountingTotal_Total_Field) _Column() string { return "total" }
+
 type Node struct {
 	Id                          []byte
 	Address                     string
@@ -3367,6 +3976,7 @@ type Node struct {
 	ExitLoopCompletedAt         *time.Time
 	ExitFinishedAt              *time.Time
 	ExitSuccess                 bool
+	CountryCode                 *string
 }
 
 func (Node) _Table() string { return "nodes" }
@@ -3402,6 +4012,7 @@ type Node_Create_Fields struct {
 	ExitLoopCompletedAt         Node_ExitLoopCompletedAt_Field
 	ExitFinishedAt              Node_ExitFinishedAt_Field
 	ExitSuccess                 Node_ExitSuccess_Field
+	CountryCode                 Node_CountryCode_Field
 }
 
 type Node_Update_Fields struct {
@@ -3441,6 +4052,7 @@ type Node_Update_Fields struct {
 	ExitLoopCompletedAt         Node_ExitLoopCompletedAt_Field
 	ExitFinishedAt              Node_ExitFinishedAt_Field
 	ExitSuccess                 Node_ExitSuccess_Field
+	CountryCode                 Node_CountryCode_Field
 }
 
 type Node_Id_Field struct {
@@ -4277,6 +4889,38 @@ func (f Node_ExitSuccess_Field) value() interface{} {
 
 func (Node_ExitSuccess_Field) _Column() string { return "exit_success" }
 
+type Node_CountryCode_Field struct {
+	_set   bool
+	_null  bool
+	_value *string
+}
+
+func Node_CountryCode(v string) Node_CountryCode_Field {
+	return Node_CountryCode_Field{_set: true, _value: &v}
+}
+
+func Node_CountryCode_Raw(v *string) Node_CountryCode_Field {
+	if v == nil {
+		return Node_CountryCode_Null()
+	}
+	return Node_CountryCode(*v)
+}
+
+func Node_CountryCode_Null() Node_CountryCode_Field {
+	return Node_CountryCode_Field{_set: true, _null: true}
+}
+
+func (f Node_CountryCode_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f Node_CountryCode_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Node_CountryCode_Field) _Column() string { return "country_code" }
+
 type NodesOfflineTime struct {
 	NodeId    []byte
 	TrackedAt time.Time
@@ -4981,45 +5625,94 @@ func (f PendingSerialQueue_Action_Field) value() interface{} {
 	return f._value
 }
 
-func (PendingSerialQueue_Action_Field) _Column() string { return "action" }
+func (PendingSerialQueue_Action_Field) _Column() string { return "action" }
+
+type PendingSerialQueue_Settled_Field struct {
+	_set   bool
+	_null  bool
+	_value uint64
+}
+
+func PendingSerialQueue_Settled(v uint64) PendingSerialQueue_Settled_Field {
+	return PendingSerialQueue_Settled_Field{_set: true, _value: v}
+}
+
+func (f PendingSerialQueue_Settled_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (PendingSerialQueue_Settled_Field) _Column() string { return "settled" }
+
+type PendingSerialQueue_ExpiresAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func PendingSerialQueue_ExpiresAt(v time.Time) PendingSerialQueue_ExpiresAt_Field {
+	return PendingSerialQueue_ExpiresAt_Field{_set: true, _value: v}
+}
+
+func (f PendingSerialQueue_ExpiresAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (PendingSerialQueue_ExpiresAt_Field) _Column() string { return "expires_at" }
+
+type PieceReference struct {
+	RootPieceId    []byte
+	ReferenceCount int
+}
+
+func (PieceReference) _Table() string { return "piece_references" }
+
+type PieceReference_Update_Fields struct {
+	ReferenceCount PieceReference_ReferenceCount_Field
+}
 
-type PendingSerialQueue_Settled_Field struct {
+type PieceReference_RootPieceId_Field struct {
 	_set   bool
 	_null  bool
-	_value uint64
+	_value []byte
 }
 
-func PendingSerialQueue_Settled(v uint64) PendingSerialQueue_Settled_Field {
-	return PendingSerialQueue_Settled_Field{_set: true, _value: v}
+func PieceReference_RootPieceId(v []byte) PieceReference_RootPieceId_Field {
+	return PieceReference_RootPieceId_Field{_set: true, _value: v}
 }
 
-func (f PendingSerialQueue_Settled_Field) value() interface{} {
+func (f PieceReference_RootPieceId_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (PendingSerialQueue_Settled_Field) _Column() string { return "settled" }
+func (PieceReference_RootPieceId_Field) _Column() string { return "root_piece_id" }
 
-type PendingSerialQueue_ExpiresAt_Field struct {
+type PieceReference_ReferenceCount_Field struct {
 	_set   bool
 	_null  bool
-	_value time.Time
+	_value int
 }
 
-func PendingSerialQueue_ExpiresAt(v time.Time) PendingSerialQueue_ExpiresAt_Field {
-	return PendingSerialQueue_ExpiresAt_Field{_set: true, _value: v}
+func PieceReference_ReferenceCount(v int) PieceReference_ReferenceCount_Field {
+	return PieceReference_ReferenceCount_Field{_set: true, _value: v}
 }
 
-func (f PendingSerialQueue_ExpiresAt_Field) value() interface{} {
+func (f PieceReference_ReferenceCount_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (PendingSerialQueue_ExpiresAt_Field) _Column() string { return "expires_at" }
+func (PieceReference_ReferenceCount_Field) _Column() string { return "reference_count" }
 
 type Project struct {
 	Id          []byte
@@ -5044,6 +5737,7 @@ type Project_Update_Fields struct {
 	Description Project_Description_Field
 	UsageLimit  Project_UsageLimit_Field
 	RateLimit   Project_RateLimit_Field
+	OwnerId     Project_OwnerId_Field
 }
 
 type Project_Id_Field struct {
@@ -5400,6 +6094,74 @@ func (f RegistrationToken_CreatedAt_Field) value() interface{} {
 
 func (RegistrationToken_CreatedAt_Field) _Column() string { return "created_at" }
 
+type RepairAttempt struct {
+	Path          []byte
+	AttemptedAt   time.Time
+	FailureReason string
+}
+
+func (RepairAttempt) _Table() string { return "repair_attempts" }
+
+type RepairAttempt_Update_Fields struct {
+}
+
+type RepairAttempt_Path_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func RepairAttempt_Path(v []byte) RepairAttempt_Path_Field {
+	return RepairAttempt_Path_Field{_set: true, _value: v}
+}
+
+func (f RepairAttempt_Path_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RepairAttempt_Path_Field) _Column() string { return "path" }
+
+type RepairAttempt_AttemptedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func RepairAttempt_AttemptedAt(v time.Time) RepairAttempt_AttemptedAt_Field {
+	return RepairAttempt_AttemptedAt_Field{_set: true, _value: v}
+}
+
+func (f RepairAttempt_AttemptedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RepairAttempt_AttemptedAt_Field) _Column() string { return "attempted_at" }
+
+type RepairAttempt_FailureReason_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func RepairAttempt_FailureReason(v string) RepairAttempt_FailureReason_Field {
+	return RepairAttempt_FailureReason_Field{_set: true, _value: v}
+}
+
+func (f RepairAttempt_FailureReason_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RepairAttempt_FailureReason_Field) _Column() string { return "failure_reason" }
+
 type ReportedSerial struct {
 	ExpiresAt     time.Time
 	StorageNodeId []byte
@@ -7173,6 +7935,74 @@ func (f ValueAttribution_LastUpdated_Field) value() interface{} {
 
 func (ValueAttribution_LastUpdated_Field) _Column() string { return "last_updated" }
 
+type ApiKeyRevocation struct {
+	ProjectId []byte
+	Tail      []byte
+	CreatedAt time.Time
+}
+
+func (ApiKeyRevocation) _Table() string { return "api_key_revocations" }
+
+type ApiKeyRevocation_Update_Fields struct {
+}
+
+type ApiKeyRevocation_ProjectId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func ApiKeyRevocation_ProjectId(v []byte) ApiKeyRevocation_ProjectId_Field {
+	return ApiKeyRevocation_ProjectId_Field{_set: true, _value: v}
+}
+
+func (f ApiKeyRevocation_ProjectId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKeyRevocation_ProjectId_Field) _Column() string { return "project_id" }
+
+type ApiKeyRevocation_Tail_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func ApiKeyRevocation_Tail(v []byte) ApiKeyRevocation_Tail_Field {
+	return ApiKeyRevocation_Tail_Field{_set: true, _value: v}
+}
+
+func (f ApiKeyRevocation_Tail_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKeyRevocation_Tail_Field) _Column() string { return "tail" }
+
+type ApiKeyRevocation_CreatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func ApiKeyRevocation_CreatedAt(v time.Time) ApiKeyRevocation_CreatedAt_Field {
+	return ApiKeyRevocation_CreatedAt_Field{_set: true, _value: v}
+}
+
+func (f ApiKeyRevocation_CreatedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKeyRevocation_CreatedAt_Field) _Column() string { return "created_at" }
+
 type ApiKey struct {
 	Id        []byte
 	ProjectId []byte
@@ -7222,122 +8052,270 @@ func ApiKey_ProjectId(v []byte) ApiKey_ProjectId_Field {
 	return ApiKey_ProjectId_Field{_set: true, _value: v}
 }
 
-func (f ApiKey_ProjectId_Field) value() interface{} {
+func (f ApiKey_ProjectId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKey_ProjectId_Field) _Column() string { return "project_id" }
+
+type ApiKey_Head_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func ApiKey_Head(v []byte) ApiKey_Head_Field {
+	return ApiKey_Head_Field{_set: true, _value: v}
+}
+
+func (f ApiKey_Head_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKey_Head_Field) _Column() string { return "head" }
+
+type ApiKey_Name_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func ApiKey_Name(v string) ApiKey_Name_Field {
+	return ApiKey_Name_Field{_set: true, _value: v}
+}
+
+func (f ApiKey_Name_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKey_Name_Field) _Column() string { return "name" }
+
+type ApiKey_Secret_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func ApiKey_Secret(v []byte) ApiKey_Secret_Field {
+	return ApiKey_Secret_Field{_set: true, _value: v}
+}
+
+func (f ApiKey_Secret_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKey_Secret_Field) _Column() string { return "secret" }
+
+type ApiKey_PartnerId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func ApiKey_PartnerId(v []byte) ApiKey_PartnerId_Field {
+	return ApiKey_PartnerId_Field{_set: true, _value: v}
+}
+
+func ApiKey_PartnerId_Raw(v []byte) ApiKey_PartnerId_Field {
+	if v == nil {
+		return ApiKey_PartnerId_Null()
+	}
+	return ApiKey_PartnerId(v)
+}
+
+func ApiKey_PartnerId_Null() ApiKey_PartnerId_Field {
+	return ApiKey_PartnerId_Field{_set: true, _null: true}
+}
+
+func (f ApiKey_PartnerId_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f ApiKey_PartnerId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKey_PartnerId_Field) _Column() string { return "partner_id" }
+
+type ApiKey_CreatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func ApiKey_CreatedAt(v time.Time) ApiKey_CreatedAt_Field {
+	return ApiKey_CreatedAt_Field{_set: true, _value: v}
+}
+
+func (f ApiKey_CreatedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ApiKey_CreatedAt_Field) _Column() string { return "created_at" }
+
+type BucketEventSubscription struct {
+	Id         []byte
+	ProjectId  []byte
+	BucketName []byte
+	Url        string
+	Secret     []byte
+	EventTypes int
+	CreatedAt  time.Time
+}
+
+func (BucketEventSubscription) _Table() string { return "bucket_event_subscriptions" }
+
+type BucketEventSubscription_Update_Fields struct {
+}
+
+type BucketEventSubscription_Id_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketEventSubscription_Id(v []byte) BucketEventSubscription_Id_Field {
+	return BucketEventSubscription_Id_Field{_set: true, _value: v}
+}
+
+func (f BucketEventSubscription_Id_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketEventSubscription_Id_Field) _Column() string { return "id" }
+
+type BucketEventSubscription_ProjectId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketEventSubscription_ProjectId(v []byte) BucketEventSubscription_ProjectId_Field {
+	return BucketEventSubscription_ProjectId_Field{_set: true, _value: v}
+}
+
+func (f BucketEventSubscription_ProjectId_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (ApiKey_ProjectId_Field) _Column() string { return "project_id" }
+func (BucketEventSubscription_ProjectId_Field) _Column() string { return "project_id" }
 
-type ApiKey_Head_Field struct {
+type BucketEventSubscription_BucketName_Field struct {
 	_set   bool
 	_null  bool
 	_value []byte
 }
 
-func ApiKey_Head(v []byte) ApiKey_Head_Field {
-	return ApiKey_Head_Field{_set: true, _value: v}
+func BucketEventSubscription_BucketName(v []byte) BucketEventSubscription_BucketName_Field {
+	return BucketEventSubscription_BucketName_Field{_set: true, _value: v}
 }
 
-func (f ApiKey_Head_Field) value() interface{} {
+func (f BucketEventSubscription_BucketName_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (ApiKey_Head_Field) _Column() string { return "head" }
+func (BucketEventSubscription_BucketName_Field) _Column() string { return "bucket_name" }
 
-type ApiKey_Name_Field struct {
+type BucketEventSubscription_Url_Field struct {
 	_set   bool
 	_null  bool
 	_value string
 }
 
-func ApiKey_Name(v string) ApiKey_Name_Field {
-	return ApiKey_Name_Field{_set: true, _value: v}
+func BucketEventSubscription_Url(v string) BucketEventSubscription_Url_Field {
+	return BucketEventSubscription_Url_Field{_set: true, _value: v}
 }
 
-func (f ApiKey_Name_Field) value() interface{} {
+func (f BucketEventSubscription_Url_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (ApiKey_Name_Field) _Column() string { return "name" }
+func (BucketEventSubscription_Url_Field) _Column() string { return "url" }
 
-type ApiKey_Secret_Field struct {
+type BucketEventSubscription_Secret_Field struct {
 	_set   bool
 	_null  bool
 	_value []byte
 }
 
-func ApiKey_Secret(v []byte) ApiKey_Secret_Field {
-	return ApiKey_Secret_Field{_set: true, _value: v}
+func BucketEventSubscription_Secret(v []byte) BucketEventSubscription_Secret_Field {
+	return BucketEventSubscription_Secret_Field{_set: true, _value: v}
 }
 
-func (f ApiKey_Secret_Field) value() interface{} {
+func (f BucketEventSubscription_Secret_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (ApiKey_Secret_Field) _Column() string { return "secret" }
+func (BucketEventSubscription_Secret_Field) _Column() string { return "secret" }
 
-type ApiKey_PartnerId_Field struct {
+type BucketEventSubscription_EventTypes_Field struct {
 	_set   bool
 	_null  bool
-	_value []byte
-}
-
-func ApiKey_PartnerId(v []byte) ApiKey_PartnerId_Field {
-	return ApiKey_PartnerId_Field{_set: true, _value: v}
-}
-
-func ApiKey_PartnerId_Raw(v []byte) ApiKey_PartnerId_Field {
-	if v == nil {
-		return ApiKey_PartnerId_Null()
-	}
-	return ApiKey_PartnerId(v)
+	_value int
 }
 
-func ApiKey_PartnerId_Null() ApiKey_PartnerId_Field {
-	return ApiKey_PartnerId_Field{_set: true, _null: true}
+func BucketEventSubscription_EventTypes(v int) BucketEventSubscription_EventTypes_Field {
+	return BucketEventSubscription_EventTypes_Field{_set: true, _value: v}
 }
 
-func (f ApiKey_PartnerId_Field) isnull() bool { return !f._set || f._null || f._value == nil }
-
-func (f ApiKey_PartnerId_Field) value() interface{} {
+func (f BucketEventSubscription_EventTypes_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (ApiKey_PartnerId_Field) _Column() string { return "partner_id" }
+func (BucketEventSubscription_EventTypes_Field) _Column() string { return "event_types" }
 
-type ApiKey_CreatedAt_Field struct {
+type BucketEventSubscription_CreatedAt_Field struct {
 	_set   bool
 	_null  bool
 	_value time.Time
 }
 
-func ApiKey_CreatedAt(v time.Time) ApiKey_CreatedAt_Field {
-	return ApiKey_CreatedAt_Field{_set: true, _value: v}
+func BucketEventSubscription_CreatedAt(v time.Time) BucketEventSubscription_CreatedAt_Field {
+	return BucketEventSubscription_CreatedAt_Field{_set: true, _value: v}
 }
 
-func (f ApiKey_CreatedAt_Field) value() interface{} {
+func (f BucketEventSubscription_CreatedAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (ApiKey_CreatedAt_Field) _Column() string { return "created_at" }
+func (BucketEventSubscription_CreatedAt_Field) _Column() string { return "created_at" }
 
 type BucketMetainfo struct {
 	Id                              []byte
@@ -7355,12 +8333,20 @@ type BucketMetainfo struct {
 	DefaultRedundancyRepairShares   int
 	DefaultRedundancyOptimalShares  int
 	DefaultRedundancyTotalShares    int
+	Placement                       *int
+	Versioning                      *int
+	StorageLimit                    *int64
+	BandwidthLimit                  *int64
 }
 
 func (BucketMetainfo) _Table() string { return "bucket_metainfos" }
 
 type BucketMetainfo_Create_Fields struct {
-	PartnerId BucketMetainfo_PartnerId_Field
+	PartnerId      BucketMetainfo_PartnerId_Field
+	Placement      BucketMetainfo_Placement_Field
+	Versioning     BucketMetainfo_Versioning_Field
+	StorageLimit   BucketMetainfo_StorageLimit_Field
+	BandwidthLimit BucketMetainfo_BandwidthLimit_Field
 }
 
 type BucketMetainfo_Update_Fields struct {
@@ -7374,6 +8360,10 @@ type BucketMetainfo_Update_Fields struct {
 	DefaultRedundancyRepairShares   BucketMetainfo_DefaultRedundancyRepairShares_Field
 	DefaultRedundancyOptimalShares  BucketMetainfo_DefaultRedundancyOptimalShares_Field
 	DefaultRedundancyTotalShares    BucketMetainfo_DefaultRedundancyTotalShares_Field
+	Placement                       BucketMetainfo_Placement_Field
+	Versioning                      BucketMetainfo_Versioning_Field
+	StorageLimit                    BucketMetainfo_StorageLimit_Field
+	BandwidthLimit                  BucketMetainfo_BandwidthLimit_Field
 }
 
 type BucketMetainfo_Id_Field struct {
@@ -7690,6 +8680,138 @@ func (BucketMetainfo_DefaultRedundancyTotalShares_Field) _Column() string {
 	return "default_redundancy_total_shares"
 }
 
+type BucketMetainfo_Placement_Field struct {
+	_set   bool
+	_null  bool
+	_value *int
+}
+
+func BucketMetainfo_Placement(v int) BucketMetainfo_Placement_Field {
+	return BucketMetainfo_Placement_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_Placement_Raw(v *int) BucketMetainfo_Placement_Field {
+	if v == nil {
+		return BucketMetainfo_Placement_Null()
+	}
+	return BucketMetainfo_Placement(*v)
+}
+
+func BucketMetainfo_Placement_Null() BucketMetainfo_Placement_Field {
+	return BucketMetainfo_Placement_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_Placement_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f BucketMetainfo_Placement_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_Placement_Field) _Column() string { return "placement" }
+
+type BucketMetainfo_Versioning_Field struct {
+	_set   bool
+	_null  bool
+	_value *int
+}
+
+func BucketMetainfo_Versioning(v int) BucketMetainfo_Versioning_Field {
+	return BucketMetainfo_Versioning_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_Versioning_Raw(v *int) BucketMetainfo_Versioning_Field {
+	if v == nil {
+		return BucketMetainfo_Versioning_Null()
+	}
+	return BucketMetainfo_Versioning(*v)
+}
+
+func BucketMetainfo_Versioning_Null() BucketMetainfo_Versioning_Field {
+	return BucketMetainfo_Versioning_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_Versioning_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f BucketMetainfo_Versioning_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_Versioning_Field) _Column() string { return "versioning" }
+
+type BucketMetainfo_StorageLimit_Field struct {
+	_set   bool
+	_null  bool
+	_value *int64
+}
+
+func BucketMetainfo_StorageLimit(v int64) BucketMetainfo_StorageLimit_Field {
+	return BucketMetainfo_StorageLimit_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_StorageLimit_Raw(v *int64) BucketMetainfo_StorageLimit_Field {
+	if v == nil {
+		return BucketMetainfo_StorageLimit_Null()
+	}
+	return BucketMetainfo_StorageLimit(*v)
+}
+
+func BucketMetainfo_StorageLimit_Null() BucketMetainfo_StorageLimit_Field {
+	return BucketMetainfo_StorageLimit_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_StorageLimit_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f BucketMetainfo_StorageLimit_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_StorageLimit_Field) _Column() string { return "storage_limit" }
+
+type BucketMetainfo_BandwidthLimit_Field struct {
+	_set   bool
+	_null  bool
+	_value *int64
+}
+
+func BucketMetainfo_BandwidthLimit(v int64) BucketMetainfo_BandwidthLimit_Field {
+	return BucketMetainfo_BandwidthLimit_Field{_set: true, _value: &v}
+}
+
+func BucketMetainfo_BandwidthLimit_Raw(v *int64) BucketMetainfo_BandwidthLimit_Field {
+	if v == nil {
+		return BucketMetainfo_BandwidthLimit_Null()
+	}
+	return BucketMetainfo_BandwidthLimit(*v)
+}
+
+func BucketMetainfo_BandwidthLimit_Null() BucketMetainfo_BandwidthLimit_Field {
+	return BucketMetainfo_BandwidthLimit_Field{_set: true, _null: true}
+}
+
+func (f BucketMetainfo_BandwidthLimit_Field) isnull() bool {
+	return !f._set || f._null || f._value == nil
+}
+
+func (f BucketMetainfo_BandwidthLimit_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketMetainfo_BandwidthLimit_Field) _Column() string { return "bandwidth_limit" }
+
 type ProjectInvoiceStamp struct {
 	ProjectId []byte
 	InvoiceId []byte
@@ -8025,172 +9147,306 @@ func (f UserCredit_Id_Field) value() interface{} {
 	return f._value
 }
 
-func (UserCredit_Id_Field) _Column() string { return "id" }
+func (UserCredit_Id_Field) _Column() string { return "id" }
+
+type UserCredit_UserId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func UserCredit_UserId(v []byte) UserCredit_UserId_Field {
+	return UserCredit_UserId_Field{_set: true, _value: v}
+}
+
+func (f UserCredit_UserId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (UserCredit_UserId_Field) _Column() string { return "user_id" }
+
+type UserCredit_OfferId_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func UserCredit_OfferId(v int) UserCredit_OfferId_Field {
+	return UserCredit_OfferId_Field{_set: true, _value: v}
+}
+
+func (f UserCredit_OfferId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (UserCredit_OfferId_Field) _Column() string { return "offer_id" }
+
+type UserCredit_ReferredBy_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func UserCredit_ReferredBy(v []byte) UserCredit_ReferredBy_Field {
+	return UserCredit_ReferredBy_Field{_set: true, _value: v}
+}
+
+func UserCredit_ReferredBy_Raw(v []byte) UserCredit_ReferredBy_Field {
+	if v == nil {
+		return UserCredit_ReferredBy_Null()
+	}
+	return UserCredit_ReferredBy(v)
+}
+
+func UserCredit_ReferredBy_Null() UserCredit_ReferredBy_Field {
+	return UserCredit_ReferredBy_Field{_set: true, _null: true}
+}
+
+func (f UserCredit_ReferredBy_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f UserCredit_ReferredBy_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (UserCredit_ReferredBy_Field) _Column() string { return "referred_by" }
+
+type UserCredit_Type_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func UserCredit_Type(v string) UserCredit_Type_Field {
+	return UserCredit_Type_Field{_set: true, _value: v}
+}
+
+func (f UserCredit_Type_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (UserCredit_Type_Field) _Column() string { return "type" }
+
+type UserCredit_CreditsEarnedInCents_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func UserCredit_CreditsEarnedInCents(v int) UserCredit_CreditsEarnedInCents_Field {
+	return UserCredit_CreditsEarnedInCents_Field{_set: true, _value: v}
+}
+
+func (f UserCredit_CreditsEarnedInCents_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (UserCredit_CreditsEarnedInCents_Field) _Column() string { return "credits_earned_in_cents" }
+
+type UserCredit_CreditsUsedInCents_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func UserCredit_CreditsUsedInCents(v int) UserCredit_CreditsUsedInCents_Field {
+	return UserCredit_CreditsUsedInCents_Field{_set: true, _value: v}
+}
+
+func (f UserCredit_CreditsUsedInCents_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (UserCredit_CreditsUsedInCents_Field) _Column() string { return "credits_used_in_cents" }
 
-type UserCredit_UserId_Field struct {
+type UserCredit_ExpiresAt_Field struct {
 	_set   bool
 	_null  bool
-	_value []byte
+	_value time.Time
 }
 
-func UserCredit_UserId(v []byte) UserCredit_UserId_Field {
-	return UserCredit_UserId_Field{_set: true, _value: v}
+func UserCredit_ExpiresAt(v time.Time) UserCredit_ExpiresAt_Field {
+	return UserCredit_ExpiresAt_Field{_set: true, _value: v}
 }
 
-func (f UserCredit_UserId_Field) value() interface{} {
+func (f UserCredit_ExpiresAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_UserId_Field) _Column() string { return "user_id" }
+func (UserCredit_ExpiresAt_Field) _Column() string { return "expires_at" }
 
-type UserCredit_OfferId_Field struct {
+type UserCredit_CreatedAt_Field struct {
 	_set   bool
 	_null  bool
-	_value int
+	_value time.Time
 }
 
-func UserCredit_OfferId(v int) UserCredit_OfferId_Field {
-	return UserCredit_OfferId_Field{_set: true, _value: v}
+func UserCredit_CreatedAt(v time.Time) UserCredit_CreatedAt_Field {
+	return UserCredit_CreatedAt_Field{_set: true, _value: v}
 }
 
-func (f UserCredit_OfferId_Field) value() interface{} {
+func (f UserCredit_CreatedAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_OfferId_Field) _Column() string { return "offer_id" }
+func (UserCredit_CreatedAt_Field) _Column() string { return "created_at" }
 
-type UserCredit_ReferredBy_Field struct {
-	_set   bool
-	_null  bool
-	_value []byte
+type BucketEvent struct {
+	Id             int64
+	SubscriptionId []byte
+	Payload        []byte
+	Attempts       int
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
 }
 
-func UserCredit_ReferredBy(v []byte) UserCredit_ReferredBy_Field {
-	return UserCredit_ReferredBy_Field{_set: true, _value: v}
+func (BucketEvent) _Table() string { return "bucket_events" }
+
+type BucketEvent_Create_Fields struct {
+	Attempts BucketEvent_Attempts_Field
 }
 
-func UserCredit_ReferredBy_Raw(v []byte) UserCredit_ReferredBy_Field {
-	if v == nil {
-		return UserCredit_ReferredBy_Null()
-	}
-	return UserCredit_ReferredBy(v)
+type BucketEvent_Update_Fields struct {
+	Attempts      BucketEvent_Attempts_Field
+	NextAttemptAt BucketEvent_NextAttemptAt_Field
 }
 
-func UserCredit_ReferredBy_Null() UserCredit_ReferredBy_Field {
-	return UserCredit_ReferredBy_Field{_set: true, _null: true}
+type BucketEvent_Id_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
 }
 
-func (f UserCredit_ReferredBy_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+func BucketEvent_Id(v int64) BucketEvent_Id_Field {
+	return BucketEvent_Id_Field{_set: true, _value: v}
+}
 
-func (f UserCredit_ReferredBy_Field) value() interface{} {
+func (f BucketEvent_Id_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_ReferredBy_Field) _Column() string { return "referred_by" }
+func (BucketEvent_Id_Field) _Column() string { return "id" }
 
-type UserCredit_Type_Field struct {
+type BucketEvent_SubscriptionId_Field struct {
 	_set   bool
 	_null  bool
-	_value string
+	_value []byte
 }
 
-func UserCredit_Type(v string) UserCredit_Type_Field {
-	return UserCredit_Type_Field{_set: true, _value: v}
+func BucketEvent_SubscriptionId(v []byte) BucketEvent_SubscriptionId_Field {
+	return BucketEvent_SubscriptionId_Field{_set: true, _value: v}
 }
 
-func (f UserCredit_Type_Field) value() interface{} {
+func (f BucketEvent_SubscriptionId_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_Type_Field) _Column() string { return "type" }
+func (BucketEvent_SubscriptionId_Field) _Column() string { return "subscription_id" }
 
-type UserCredit_CreditsEarnedInCents_Field struct {
+type BucketEvent_Payload_Field struct {
 	_set   bool
 	_null  bool
-	_value int
+	_value []byte
 }
 
-func UserCredit_CreditsEarnedInCents(v int) UserCredit_CreditsEarnedInCents_Field {
-	return UserCredit_CreditsEarnedInCents_Field{_set: true, _value: v}
+func BucketEvent_Payload(v []byte) BucketEvent_Payload_Field {
+	return BucketEvent_Payload_Field{_set: true, _value: v}
 }
 
-func (f UserCredit_CreditsEarnedInCents_Field) value() interface{} {
+func (f BucketEvent_Payload_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_CreditsEarnedInCents_Field) _Column() string { return "credits_earned_in_cents" }
+func (BucketEvent_Payload_Field) _Column() string { return "payload" }
 
-type UserCredit_CreditsUsedInCents_Field struct {
+type BucketEvent_Attempts_Field struct {
 	_set   bool
 	_null  bool
 	_value int
 }
 
-func UserCredit_CreditsUsedInCents(v int) UserCredit_CreditsUsedInCents_Field {
-	return UserCredit_CreditsUsedInCents_Field{_set: true, _value: v}
+func BucketEvent_Attempts(v int) BucketEvent_Attempts_Field {
+	return BucketEvent_Attempts_Field{_set: true, _value: v}
 }
 
-func (f UserCredit_CreditsUsedInCents_Field) value() interface{} {
+func (f BucketEvent_Attempts_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_CreditsUsedInCents_Field) _Column() string { return "credits_used_in_cents" }
+func (BucketEvent_Attempts_Field) _Column() string { return "attempts" }
 
-type UserCredit_ExpiresAt_Field struct {
+type BucketEvent_NextAttemptAt_Field struct {
 	_set   bool
 	_null  bool
 	_value time.Time
 }
 
-func UserCredit_ExpiresAt(v time.Time) UserCredit_ExpiresAt_Field {
-	return UserCredit_ExpiresAt_Field{_set: true, _value: v}
+func BucketEvent_NextAttemptAt(v time.Time) BucketEvent_NextAttemptAt_Field {
+	return BucketEvent_NextAttemptAt_Field{_set: true, _value: v}
 }
 
-func (f UserCredit_ExpiresAt_Field) value() interface{} {
+func (f BucketEvent_NextAttemptAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_ExpiresAt_Field) _Column() string { return "expires_at" }
+func (BucketEvent_NextAttemptAt_Field) _Column() string { return "next_attempt_at" }
 
-type UserCredit_CreatedAt_Field struct {
+type BucketEvent_CreatedAt_Field struct {
 	_set   bool
 	_null  bool
 	_value time.Time
 }
 
-func UserCredit_CreatedAt(v time.Time) UserCredit_CreatedAt_Field {
-	return UserCredit_CreatedAt_Field{_set: true, _value: v}
+func BucketEvent_CreatedAt(v time.Time) BucketEvent_CreatedAt_Field {
+	return BucketEvent_CreatedAt_Field{_set: true, _value: v}
 }
 
-func (f UserCredit_CreatedAt_Field) value() interface{} {
+func (f BucketEvent_CreatedAt_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (UserCredit_CreatedAt_Field) _Column() string { return "created_at" }
+func (BucketEvent_CreatedAt_Field) _Column() string { return "created_at" }
 
 func toUTC(t time.Time) time.Time {
 	return t.UTC()
@@ -8718,15 +9974,16 @@ func (obj *postgresImpl) CreateNoReturn_Node(ctx context.Context,
 	__exit_initiated_at_val := optional.ExitInitiatedAt.value()
 	__exit_loop_completed_at_val := optional.ExitLoopCompletedAt.value()
 	__exit_finished_at_val := optional.ExitFinishedAt.value()
+	__country_code_val := optional.CountryCode.value()
 
-	var __columns = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("id, last_net, last_ip_port, email, wallet, vetted_at, uptime_success_count, total_uptime_count, disqualified, suspended, exit_initiated_at, exit_loop_completed_at, exit_finished_at")}
-	var __placeholders = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?")}
+	var __columns = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("id, last_net, last_ip_port, email, wallet, vetted_at, uptime_success_count, total_uptime_count, disqualified, suspended, exit_initiated_at, exit_loop_completed_at, exit_finished_at, country_code")}
+	var __placeholders = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?")}
 	var __clause = &__sqlbundle_Hole{SQL: __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("("), __columns, __sqlbundle_Literal(") VALUES ("), __placeholders, __sqlbundle_Literal(")")}}}
 
 	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("INSERT INTO nodes "), __clause}}
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __last_net_val, __last_ip_port_val, __email_val, __wallet_val, __vetted_at_val, __uptime_success_count_val, __total_uptime_count_val, __disqualified_val, __suspended_val, __exit_initiated_at_val, __exit_loop_completed_at_val, __exit_finished_at_val)
+	__values = append(__values, __id_val, __last_net_val, __last_ip_port_val, __email_val, __wallet_val, __vetted_at_val, __uptime_success_count_val, __total_uptime_count_val, __disqualified_val, __suspended_val, __exit_initiated_at_val, __exit_loop_completed_at_val, __exit_finished_at_val, __country_code_val)
 
 	__optional_columns := __sqlbundle_Literals{Join: ", "}
 	__optional_placeholders := __sqlbundle_Literals{Join: ", "}
@@ -9541,17 +10798,21 @@ func (obj *postgresImpl) Create_BucketMetainfo(ctx context.Context,
 	__default_redundancy_repair_shares_val := bucket_metainfo_default_redundancy_repair_shares.value()
 	__default_redundancy_optimal_shares_val := bucket_metainfo_default_redundancy_optimal_shares.value()
 	__default_redundancy_total_shares_val := bucket_metainfo_default_redundancy_total_shares.value()
+	__placement_val := optional.Placement.value()
+	__versioning_val := optional.Versioning.value()
+	__storage_limit_val := optional.StorageLimit.value()
+	__bandwidth_limit_val := optional.BandwidthLimit.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares, placement, versioning, storage_limit, bandwidth_limit ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val)
+	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val, __placement_val, __versioning_val, __storage_limit_val, __bandwidth_limit_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -10163,7 +11424,7 @@ func (obj *postgresImpl) Get_Node_By_Id(ctx context.Context,
 	node *Node, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success FROM nodes WHERE nodes.id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success, nodes.country_code FROM nodes WHERE nodes.id = ?")
 
 	var __values []interface{}
 	__values = append(__values, node_id.value())
@@ -10172,7 +11433,7 @@ func (obj *postgresImpl) Get_Node_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	node = &Node{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess, &node.CountryCode)
 	if err != nil {
 		return (*Node)(nil), obj.makeErr(err)
 	}
@@ -10218,7 +11479,7 @@ func (obj *postgresImpl) Limited_Node_By_Id_GreaterOrEqual_OrderBy_Asc_Id(ctx co
 	rows []*Node, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success FROM nodes WHERE nodes.id >= ? ORDER BY nodes.id LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success, nodes.country_code FROM nodes WHERE nodes.id >= ? ORDER BY nodes.id LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, node_id_greater_or_equal.value())
@@ -10236,7 +11497,7 @@ func (obj *postgresImpl) Limited_Node_By_Id_GreaterOrEqual_OrderBy_Asc_Id(ctx co
 
 	for __rows.Next() {
 		node := &Node{}
-		err = __rows.Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess)
+		err = __rows.Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess, &node.CountryCode)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -11540,7 +12801,7 @@ func (obj *postgresImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.Co
 	bucket_metainfo *BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name.value())
@@ -11549,7 +12810,7 @@ func (obj *postgresImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.Co
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 	if err != nil {
 		return (*BucketMetainfo)(nil), obj.makeErr(err)
 	}
@@ -11564,7 +12825,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOrE
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater_or_equal.value())
@@ -11582,7 +12843,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOrE
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -11602,7 +12863,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_Or
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater.value())
@@ -11620,7 +12881,7 @@ func (obj *postgresImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_Or
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -12467,7 +13728,7 @@ func (obj *postgresImpl) Update_Node_By_Id(ctx context.Context,
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE nodes SET "), __sets, __sqlbundle_Literal(" WHERE nodes.id = ? RETURNING nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE nodes SET "), __sets, __sqlbundle_Literal(" WHERE nodes.id = ? RETURNING nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success, nodes.country_code")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -12653,6 +13914,11 @@ func (obj *postgresImpl) Update_Node_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("exit_success = ?"))
 	}
 
+	if update.CountryCode._set {
+		__values = append(__values, update.CountryCode.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("country_code = ?"))
+	}
+
 	__now := obj.db.Hooks.Now().UTC()
 
 	__values = append(__values, __now)
@@ -12667,7 +13933,7 @@ func (obj *postgresImpl) Update_Node_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	node = &Node{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess, &node.CountryCode)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -12870,6 +14136,11 @@ func (obj *postgresImpl) UpdateNoReturn_Node_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("exit_success = ?"))
 	}
 
+	if update.CountryCode._set {
+		__values = append(__values, update.CountryCode.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("country_code = ?"))
+	}
+
 	__now := obj.db.Hooks.Now().UTC()
 
 	__values = append(__values, __now)
@@ -12984,6 +14255,11 @@ func (obj *postgresImpl) Update_Project_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("rate_limit = ?"))
 	}
 
+	if update.OwnerId._set {
+		__values = append(__values, update.OwnerId.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("owner_id = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -13218,7 +14494,7 @@ func (obj *postgresImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx context
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -13274,6 +14550,26 @@ func (obj *postgresImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx context
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("default_redundancy_total_shares = ?"))
 	}
 
+	if update.Placement._set {
+		__values = append(__values, update.Placement.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("placement = ?"))
+	}
+
+	if update.Versioning._set {
+		__values = append(__values, update.Versioning.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("versioning = ?"))
+	}
+
+	if update.StorageLimit._set {
+		__values = append(__values, update.StorageLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("storage_limit = ?"))
+	}
+
+	if update.BandwidthLimit._set {
+		__values = append(__values, update.BandwidthLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("bandwidth_limit = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -13287,7 +14583,7 @@ func (obj *postgresImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx context
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -14225,6 +15521,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 	defer mon.Task()(&ctx)(&err)
 	var __res sql.Result
 	var __count int64
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_events;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
 	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM user_credits;")
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14280,6 +15586,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_event_subscriptions;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14290,6 +15606,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM api_key_revocations;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14410,6 +15736,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM repair_attempts;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14440,6 +15776,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM piece_references;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14500,6 +15846,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM live_accounting_totals;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14530,6 +15886,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM graceful_exit_shrinks;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14620,6 +15986,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM admin_audit_logs;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -14828,15 +16204,16 @@ func (obj *cockroachImpl) CreateNoReturn_Node(ctx context.Context,
 	__exit_initiated_at_val := optional.ExitInitiatedAt.value()
 	__exit_loop_completed_at_val := optional.ExitLoopCompletedAt.value()
 	__exit_finished_at_val := optional.ExitFinishedAt.value()
+	__country_code_val := optional.CountryCode.value()
 
-	var __columns = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("id, last_net, last_ip_port, email, wallet, vetted_at, uptime_success_count, total_uptime_count, disqualified, suspended, exit_initiated_at, exit_loop_completed_at, exit_finished_at")}
-	var __placeholders = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?")}
+	var __columns = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("id, last_net, last_ip_port, email, wallet, vetted_at, uptime_success_count, total_uptime_count, disqualified, suspended, exit_initiated_at, exit_loop_completed_at, exit_finished_at, country_code")}
+	var __placeholders = &__sqlbundle_Hole{SQL: __sqlbundle_Literal("?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?")}
 	var __clause = &__sqlbundle_Hole{SQL: __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("("), __columns, __sqlbundle_Literal(") VALUES ("), __placeholders, __sqlbundle_Literal(")")}}}
 
 	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("INSERT INTO nodes "), __clause}}
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __last_net_val, __last_ip_port_val, __email_val, __wallet_val, __vetted_at_val, __uptime_success_count_val, __total_uptime_count_val, __disqualified_val, __suspended_val, __exit_initiated_at_val, __exit_loop_completed_at_val, __exit_finished_at_val)
+	__values = append(__values, __id_val, __last_net_val, __last_ip_port_val, __email_val, __wallet_val, __vetted_at_val, __uptime_success_count_val, __total_uptime_count_val, __disqualified_val, __suspended_val, __exit_initiated_at_val, __exit_loop_completed_at_val, __exit_finished_at_val, __country_code_val)
 
 	__optional_columns := __sqlbundle_Literals{Join: ", "}
 	__optional_placeholders := __sqlbundle_Literals{Join: ", "}
@@ -15651,17 +17028,21 @@ func (obj *cockroachImpl) Create_BucketMetainfo(ctx context.Context,
 	__default_redundancy_repair_shares_val := bucket_metainfo_default_redundancy_repair_shares.value()
 	__default_redundancy_optimal_shares_val := bucket_metainfo_default_redundancy_optimal_shares.value()
 	__default_redundancy_total_shares_val := bucket_metainfo_default_redundancy_total_shares.value()
+	__placement_val := optional.Placement.value()
+	__versioning_val := optional.Versioning.value()
+	__storage_limit_val := optional.StorageLimit.value()
+	__bandwidth_limit_val := optional.BandwidthLimit.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO bucket_metainfos ( id, project_id, name, partner_id, path_cipher, created_at, default_segment_size, default_encryption_cipher_suite, default_encryption_block_size, default_redundancy_algorithm, default_redundancy_share_size, default_redundancy_required_shares, default_redundancy_repair_shares, default_redundancy_optimal_shares, default_redundancy_total_shares, placement, versioning, storage_limit, bandwidth_limit ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val)
+	__values = append(__values, __id_val, __project_id_val, __name_val, __partner_id_val, __path_cipher_val, __created_at_val, __default_segment_size_val, __default_encryption_cipher_suite_val, __default_encryption_block_size_val, __default_redundancy_algorithm_val, __default_redundancy_share_size_val, __default_redundancy_required_shares_val, __default_redundancy_repair_shares_val, __default_redundancy_optimal_shares_val, __default_redundancy_total_shares_val, __placement_val, __versioning_val, __storage_limit_val, __bandwidth_limit_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -16273,7 +17654,7 @@ func (obj *cockroachImpl) Get_Node_By_Id(ctx context.Context,
 	node *Node, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success FROM nodes WHERE nodes.id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success, nodes.country_code FROM nodes WHERE nodes.id = ?")
 
 	var __values []interface{}
 	__values = append(__values, node_id.value())
@@ -16282,7 +17663,7 @@ func (obj *cockroachImpl) Get_Node_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	node = &Node{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess, &node.CountryCode)
 	if err != nil {
 		return (*Node)(nil), obj.makeErr(err)
 	}
@@ -16328,7 +17709,7 @@ func (obj *cockroachImpl) Limited_Node_By_Id_GreaterOrEqual_OrderBy_Asc_Id(ctx c
 	rows []*Node, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success FROM nodes WHERE nodes.id >= ? ORDER BY nodes.id LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success, nodes.country_code FROM nodes WHERE nodes.id >= ? ORDER BY nodes.id LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, node_id_greater_or_equal.value())
@@ -16346,7 +17727,7 @@ func (obj *cockroachImpl) Limited_Node_By_Id_GreaterOrEqual_OrderBy_Asc_Id(ctx c
 
 	for __rows.Next() {
 		node := &Node{}
-		err = __rows.Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess)
+		err = __rows.Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess, &node.CountryCode)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -17650,7 +19031,7 @@ func (obj *cockroachImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.C
 	bucket_metainfo *BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name.value())
@@ -17659,7 +19040,7 @@ func (obj *cockroachImpl) Get_BucketMetainfo_By_ProjectId_And_Name(ctx context.C
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 	if err != nil {
 		return (*BucketMetainfo)(nil), obj.makeErr(err)
 	}
@@ -17674,7 +19055,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOr
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name >= ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater_or_equal.value())
@@ -17692,7 +19073,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_GreaterOr
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -17712,7 +19093,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_O
 	rows []*BucketMetainfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit FROM bucket_metainfos WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name > ? ORDER BY bucket_metainfos.name LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, bucket_metainfo_project_id.value(), bucket_metainfo_name_greater.value())
@@ -17730,7 +19111,7 @@ func (obj *cockroachImpl) Limited_BucketMetainfo_By_ProjectId_And_Name_Greater_O
 
 	for __rows.Next() {
 		bucket_metainfo := &BucketMetainfo{}
-		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+		err = __rows.Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -18577,7 +19958,7 @@ func (obj *cockroachImpl) Update_Node_By_Id(ctx context.Context,
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE nodes SET "), __sets, __sqlbundle_Literal(" WHERE nodes.id = ? RETURNING nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE nodes SET "), __sets, __sqlbundle_Literal(" WHERE nodes.id = ? RETURNING nodes.id, nodes.address, nodes.last_net, nodes.last_ip_port, nodes.protocol, nodes.type, nodes.email, nodes.wallet, nodes.free_disk, nodes.piece_count, nodes.major, nodes.minor, nodes.patch, nodes.hash, nodes.timestamp, nodes.release, nodes.latency_90, nodes.audit_success_count, nodes.total_audit_count, nodes.vetted_at, nodes.uptime_success_count, nodes.total_uptime_count, nodes.created_at, nodes.updated_at, nodes.last_contact_success, nodes.last_contact_failure, nodes.contained, nodes.disqualified, nodes.suspended, nodes.audit_reputation_alpha, nodes.audit_reputation_beta, nodes.unknown_audit_reputation_alpha, nodes.unknown_audit_reputation_beta, nodes.uptime_reputation_alpha, nodes.uptime_reputation_beta, nodes.exit_initiated_at, nodes.exit_loop_completed_at, nodes.exit_finished_at, nodes.exit_success, nodes.country_code")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -18763,6 +20144,11 @@ func (obj *cockroachImpl) Update_Node_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("exit_success = ?"))
 	}
 
+	if update.CountryCode._set {
+		__values = append(__values, update.CountryCode.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("country_code = ?"))
+	}
+
 	__now := obj.db.Hooks.Now().UTC()
 
 	__values = append(__values, __now)
@@ -18777,7 +20163,7 @@ func (obj *cockroachImpl) Update_Node_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	node = &Node{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&node.Id, &node.Address, &node.LastNet, &node.LastIpPort, &node.Protocol, &node.Type, &node.Email, &node.Wallet, &node.FreeDisk, &node.PieceCount, &node.Major, &node.Minor, &node.Patch, &node.Hash, &node.Timestamp, &node.Release, &node.Latency90, &node.AuditSuccessCount, &node.TotalAuditCount, &node.VettedAt, &node.UptimeSuccessCount, &node.TotalUptimeCount, &node.CreatedAt, &node.UpdatedAt, &node.LastContactSuccess, &node.LastContactFailure, &node.Contained, &node.Disqualified, &node.Suspended, &node.AuditReputationAlpha, &node.AuditReputationBeta, &node.UnknownAuditReputationAlpha, &node.UnknownAuditReputationBeta, &node.UptimeReputationAlpha, &node.UptimeReputationBeta, &node.ExitInitiatedAt, &node.ExitLoopCompletedAt, &node.ExitFinishedAt, &node.ExitSuccess, &node.CountryCode)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -18980,6 +20366,11 @@ func (obj *cockroachImpl) UpdateNoReturn_Node_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("exit_success = ?"))
 	}
 
+	if update.CountryCode._set {
+		__values = append(__values, update.CountryCode.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("country_code = ?"))
+	}
+
 	__now := obj.db.Hooks.Now().UTC()
 
 	__values = append(__values, __now)
@@ -19094,6 +20485,11 @@ func (obj *cockroachImpl) Update_Project_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("rate_limit = ?"))
 	}
 
+	if update.OwnerId._set {
+		__values = append(__values, update.OwnerId.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("owner_id = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -19328,7 +20724,7 @@ func (obj *cockroachImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx contex
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE bucket_metainfos SET "), __sets, __sqlbundle_Literal(" WHERE bucket_metainfos.project_id = ? AND bucket_metainfos.name = ? RETURNING bucket_metainfos.id, bucket_metainfos.project_id, bucket_metainfos.name, bucket_metainfos.partner_id, bucket_metainfos.path_cipher, bucket_metainfos.created_at, bucket_metainfos.default_segment_size, bucket_metainfos.default_encryption_cipher_suite, bucket_metainfos.default_encryption_block_size, bucket_metainfos.default_redundancy_algorithm, bucket_metainfos.default_redundancy_share_size, bucket_metainfos.default_redundancy_required_shares, bucket_metainfos.default_redundancy_repair_shares, bucket_metainfos.default_redundancy_optimal_shares, bucket_metainfos.default_redundancy_total_shares, bucket_metainfos.placement, bucket_metainfos.versioning, bucket_metainfos.storage_limit, bucket_metainfos.bandwidth_limit")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -19384,6 +20780,26 @@ func (obj *cockroachImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx contex
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("default_redundancy_total_shares = ?"))
 	}
 
+	if update.Placement._set {
+		__values = append(__values, update.Placement.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("placement = ?"))
+	}
+
+	if update.Versioning._set {
+		__values = append(__values, update.Versioning.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("versioning = ?"))
+	}
+
+	if update.StorageLimit._set {
+		__values = append(__values, update.StorageLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("storage_limit = ?"))
+	}
+
+	if update.BandwidthLimit._set {
+		__values = append(__values, update.BandwidthLimit.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("bandwidth_limit = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -19397,7 +20813,7 @@ func (obj *cockroachImpl) Update_BucketMetainfo_By_ProjectId_And_Name(ctx contex
 	obj.logStmt(__stmt, __values...)
 
 	bucket_metainfo = &BucketMetainfo{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&bucket_metainfo.Id, &bucket_metainfo.ProjectId, &bucket_metainfo.Name, &bucket_metainfo.PartnerId, &bucket_metainfo.PathCipher, &bucket_metainfo.CreatedAt, &bucket_metainfo.DefaultSegmentSize, &bucket_metainfo.DefaultEncryptionCipherSuite, &bucket_metainfo.DefaultEncryptionBlockSize, &bucket_metainfo.DefaultRedundancyAlgorithm, &bucket_metainfo.DefaultRedundancyShareSize, &bucket_metainfo.DefaultRedundancyRequiredShares, &bucket_metainfo.DefaultRedundancyRepairShares, &bucket_metainfo.DefaultRedundancyOptimalShares, &bucket_metainfo.DefaultRedundancyTotalShares, &bucket_metainfo.Placement, &bucket_metainfo.Versioning, &bucket_metainfo.StorageLimit, &bucket_metainfo.BandwidthLimit)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -20335,6 +21751,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 	defer mon.Task()(&ctx)(&err)
 	var __res sql.Result
 	var __count int64
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_events;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
 	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM user_credits;")
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20390,6 +21816,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_event_subscriptions;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20400,6 +21836,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM api_key_revocations;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20520,6 +21966,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM repair_attempts;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20550,6 +22006,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM piece_references;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20610,6 +22076,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM live_accounting_totals;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20640,6 +22116,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM graceful_exit_shrinks;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -20730,6 +22216,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM admin_audit_logs;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
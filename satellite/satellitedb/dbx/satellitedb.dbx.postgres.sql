@@ -17,6 +17,15 @@ CREATE TABLE accounting_timestamps (
 	value timestamp with time zone NOT NULL,
 	PRIMARY KEY ( name )
 );
+CREATE TABLE admin_audit_logs (
+	id bytea NOT NULL,
+	operator text NOT NULL,
+	action text NOT NULL,
+	target text NOT NULL,
+	reason text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_bandwidth_rollups (
 	bucket_name bytea NOT NULL,
 	project_id bytea NOT NULL,
@@ -101,6 +110,16 @@ CREATE TABLE graceful_exit_progress (
 	updated_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( node_id )
 );
+CREATE TABLE graceful_exit_shrinks (
+	node_id bytea NOT NULL,
+	target_bytes bigint NOT NULL,
+	queued_bytes bigint NOT NULL DEFAULT 0,
+	bytes_transferred bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	loop_completed_at timestamp with time zone,
+	finished_at timestamp with time zone,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE graceful_exit_transfer_queue (
 	node_id bytea NOT NULL,
 	path bytea NOT NULL,
@@ -121,6 +140,9 @@ CREATE TABLE injuredsegments (
 	data bytea NOT NULL,
 	attempted timestamp with time zone,
 	num_healthy_pieces integer NOT NULL DEFAULT 52,
+	priority double precision NOT NULL DEFAULT 0,
+	failures integer NOT NULL DEFAULT 0,
+	retry_after timestamp with time zone,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -131,6 +153,12 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE live_accounting_totals (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	total bigint NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL DEFAULT '',
@@ -171,6 +199,7 @@ CREATE TABLE nodes (
 	exit_loop_completed_at timestamp with time zone,
 	exit_finished_at timestamp with time zone,
 	exit_success boolean NOT NULL DEFAULT false,
+	country_code text,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE nodes_offline_times (
@@ -220,6 +249,11 @@ CREATE TABLE pending_serial_queue (
 	expires_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( storage_node_id, bucket_id, serial_number )
 );
+CREATE TABLE piece_references (
+	root_piece_id bytea NOT NULL,
+	reference_count integer NOT NULL,
+	PRIMARY KEY ( root_piece_id )
+);
 CREATE TABLE projects (
 	id bytea NOT NULL,
 	name text NOT NULL,
@@ -245,6 +279,12 @@ CREATE TABLE registration_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE repair_attempts (
+	path bytea NOT NULL,
+	attempted_at timestamp with time zone NOT NULL,
+	failure_reason text NOT NULL,
+	PRIMARY KEY ( path, attempted_at )
+);
 CREATE TABLE reported_serials (
 	expires_at timestamp with time zone NOT NULL,
 	storage_node_id bytea NOT NULL,
@@ -363,6 +403,12 @@ CREATE TABLE value_attributions (
 	last_updated timestamp with time zone NOT NULL,
 	PRIMARY KEY ( project_id, bucket_name )
 );
+CREATE TABLE api_key_revocations (
+	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+	tail bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( project_id, tail )
+);
 CREATE TABLE api_keys (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
@@ -375,6 +421,16 @@ CREATE TABLE api_keys (
 	UNIQUE ( head ),
 	UNIQUE ( name, project_id )
 );
+CREATE TABLE bucket_event_subscriptions (
+	id bytea NOT NULL,
+	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+	bucket_name bytea NOT NULL,
+	url text NOT NULL,
+	secret bytea NOT NULL,
+	event_types integer NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_metainfos (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ),
@@ -391,6 +447,10 @@ CREATE TABLE bucket_metainfos (
 	default_redundancy_repair_shares integer NOT NULL,
 	default_redundancy_optimal_shares integer NOT NULL,
 	default_redundancy_total_shares integer NOT NULL,
+	placement integer,
+	versioning integer,
+	storage_limit bigint,
+	bandwidth_limit bigint,
 	PRIMARY KEY ( id ),
 	UNIQUE ( name, project_id )
 );
@@ -433,7 +493,17 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE TABLE bucket_events (
+	id bigserial NOT NULL,
+	subscription_id bytea NOT NULL REFERENCES bucket_event_subscriptions( id ) ON DELETE CASCADE,
+	payload bytea NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamp with time zone NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE INDEX accounting_rollups_start_time_index ON accounting_rollups ( start_time );
+CREATE INDEX admin_audit_logs_target_index ON admin_audit_logs ( target );
 CREATE INDEX bucket_bandwidth_rollups_project_id_action_interval_index ON bucket_bandwidth_rollups ( project_id, action, interval_start );
 CREATE INDEX bucket_bandwidth_rollups_action_interval_project_id_index ON bucket_bandwidth_rollups ( action, interval_start, project_id );
 CREATE INDEX consumed_serials_expires_at_index ON consumed_serials ( expires_at );
@@ -446,4 +516,6 @@ CREATE INDEX serial_numbers_expires_at_index ON serial_numbers ( expires_at );
 CREATE INDEX storagenode_payments_node_id_period_index ON storagenode_payments ( node_id, period );
 CREATE INDEX storagenode_paystubs_node_id_index ON storagenode_paystubs ( node_id );
 CREATE INDEX storagenode_storage_tallies_node_id_index ON storagenode_storage_tallies ( node_id );
+CREATE INDEX bucket_event_subscriptions_project_id_bucket_name_index ON bucket_event_subscriptions ( project_id, bucket_name );
 CREATE UNIQUE INDEX credits_earned_user_id_offer_id ON user_credits ( id, offer_id );
+CREATE INDEX bucket_events_next_attempt_at_index ON bucket_events ( next_attempt_at );
@@ -69,6 +69,13 @@ func (db *gracefulexitDB) GetProgress(ctx context.Context, nodeID storj.NodeID)
 	return progress, Error.Wrap(err)
 }
 
+// DeleteProgress deletes the graceful exit progress entry of a node.
+func (db *gracefulexitDB) DeleteProgress(ctx context.Context, nodeID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	_, err = db.db.Delete_GracefulExitProgress_By_NodeId(ctx, dbx.GracefulExitProgress_NodeId(nodeID.Bytes()))
+	return Error.Wrap(err)
+}
+
 // Enqueue batch inserts graceful exit transfer queue entries it does not exist.
 func (db *gracefulexitDB) Enqueue(ctx context.Context, items []gracefulexit.TransferQueueItem) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -253,6 +260,125 @@ func (db *gracefulexitDB) IncrementOrderLimitSendCount(ctx context.Context, node
 	return Error.Wrap(err)
 }
 
+// InitiateShrink creates a shrink of the node, unless it has an unfinished one.
+func (db *gracefulexitDB) InitiateShrink(ctx context.Context, nodeID storj.NodeID, targetBytes int64) (initiated bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := db.db.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO graceful_exit_shrinks (node_id, target_bytes, queued_bytes, bytes_transferred, created_at)
+		VALUES (?, ?, 0, 0, ?)
+		ON CONFLICT (node_id)
+		DO UPDATE SET target_bytes = excluded.target_bytes,
+			queued_bytes = 0,
+			bytes_transferred = 0,
+			created_at = excluded.created_at,
+			loop_completed_at = NULL,
+			finished_at = NULL
+		WHERE graceful_exit_shrinks.finished_at IS NOT NULL
+	`), nodeID, targetBytes, time.Now().UTC())
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return affected > 0, nil
+}
+
+// GetShrink gets the last shrink of the node.
+func (db *gracefulexitDB) GetShrink(ctx context.Context, nodeID storj.NodeID) (_ *gracefulexit.Shrink, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	row := db.db.QueryRowContext(ctx, db.db.Rebind(`
+		SELECT node_id, target_bytes, queued_bytes, bytes_transferred, created_at, loop_completed_at, finished_at
+		FROM graceful_exit_shrinks
+		WHERE node_id = ?
+	`), nodeID)
+
+	shrink := &gracefulexit.Shrink{}
+	err = row.Scan(&shrink.NodeID, &shrink.TargetBytes, &shrink.QueuedBytes, &shrink.BytesTransferred,
+		&shrink.CreatedAt, &shrink.LoopCompletedAt, &shrink.FinishedAt)
+	if errs.Is(err, sql.ErrNoRows) {
+		return nil, gracefulexit.ErrNodeNotFound.Wrap(err)
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return shrink, nil
+}
+
+// GetUnfinishedShrinks gets the shrinks which haven't finished.
+func (db *gracefulexitDB) GetUnfinishedShrinks(ctx context.Context) (shrinks []*gracefulexit.Shrink, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, `
+		SELECT node_id, target_bytes, queued_bytes, bytes_transferred, created_at, loop_completed_at, finished_at
+		FROM graceful_exit_shrinks
+		WHERE finished_at IS NULL
+	`)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		shrink := &gracefulexit.Shrink{}
+		err = rows.Scan(&shrink.NodeID, &shrink.TargetBytes, &shrink.QueuedBytes, &shrink.BytesTransferred,
+			&shrink.CreatedAt, &shrink.LoopCompletedAt, &shrink.FinishedAt)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		shrinks = append(shrinks, shrink)
+	}
+	return shrinks, Error.Wrap(rows.Err())
+}
+
+// CompleteShrinkLoop records the bytes queued for the shrink of the node.
+func (db *gracefulexitDB) CompleteShrinkLoop(ctx context.Context, nodeID storj.NodeID, queuedBytes int64, completedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		UPDATE graceful_exit_shrinks SET queued_bytes = ?, loop_completed_at = ?
+		WHERE node_id = ? AND finished_at IS NULL
+	`), queuedBytes, completedAt.UTC(), nodeID)
+	return Error.Wrap(err)
+}
+
+// FinishShrink marks the shrink of the node finished with the bytes
+// transferred so far, and deletes its transfer progress and remaining queue
+// items in one transaction.
+func (db *gracefulexitDB) FinishShrink(ctx context.Context, nodeID storj.NodeID, finishedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return Error.Wrap(db.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		var bytesTransferred int64
+		progress, err := tx.Get_GracefulExitProgress_By_NodeId(ctx, dbx.GracefulExitProgress_NodeId(nodeID.Bytes()))
+		switch {
+		case err == nil:
+			bytesTransferred = progress.BytesTransferred
+		case err != sql.ErrNoRows:
+			return err
+		}
+
+		_, err = tx.Tx.ExecContext(ctx, db.db.Rebind(`
+			UPDATE graceful_exit_shrinks SET bytes_transferred = ?, finished_at = ?
+			WHERE node_id = ? AND finished_at IS NULL
+		`), bytesTransferred, finishedAt.UTC(), nodeID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Delete_GracefulExitProgress_By_NodeId(ctx, dbx.GracefulExitProgress_NodeId(nodeID.Bytes()))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Delete_GracefulExitTransferQueue_By_NodeId(ctx, dbx.GracefulExitTransferQueue_NodeId(nodeID.Bytes()))
+		return err
+	}))
+}
+
 func scanRows(rows *sql.Rows) (transferQueueItemRows []*gracefulexit.TransferQueueItem, err error) {
 	for rows.Next() {
 		transferQueueItem := &gracefulexit.TransferQueueItem{}
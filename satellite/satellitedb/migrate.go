@@ -1067,6 +1067,148 @@ func (db *satelliteDB) PostgresMigration() *migrate.Migration {
 					ON CONFLICT(project_id, interval_month) DO UPDATE SET egress_allocated = EXCLUDED.egress_allocated::bigint;`,
 				},
 			},
+			{
+				DB:          db.DB,
+				Description: "Add country_code to nodes and placement to bucket_metainfos",
+				Version:     107,
+				Action: migrate.SQL{
+					`ALTER TABLE nodes ADD COLUMN country_code text;`,
+					`ALTER TABLE bucket_metainfos ADD COLUMN placement integer;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add versioning to bucket_metainfos",
+				Version:     108,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN versioning integer;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add piece_references table",
+				Version:     109,
+				Action: migrate.SQL{
+					`CREATE TABLE piece_references (
+						root_piece_id bytea NOT NULL,
+						reference_count integer NOT NULL,
+						PRIMARY KEY ( root_piece_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add storage and bandwidth limits to bucket_metainfos",
+				Version:     110,
+				Action: migrate.SQL{
+					`ALTER TABLE bucket_metainfos ADD COLUMN storage_limit bigint;`,
+					`ALTER TABLE bucket_metainfos ADD COLUMN bandwidth_limit bigint;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add repair priority, backoff and attempt history",
+				Version:     111,
+				Action: migrate.SQL{
+					`ALTER TABLE injuredsegments ADD COLUMN priority double precision NOT NULL DEFAULT 0;`,
+					`ALTER TABLE injuredsegments ADD COLUMN failures integer NOT NULL DEFAULT 0;`,
+					`ALTER TABLE injuredsegments ADD COLUMN retry_after timestamp with time zone;`,
+					`CREATE TABLE repair_attempts (
+						path bytea NOT NULL,
+						attempted_at timestamp with time zone NOT NULL,
+						failure_reason text NOT NULL,
+						PRIMARY KEY ( path, attempted_at )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add admin audit log",
+				Version:     112,
+				Action: migrate.SQL{
+					`CREATE TABLE admin_audit_logs (
+						id bytea NOT NULL,
+						operator text NOT NULL,
+						action text NOT NULL,
+						target text NOT NULL,
+						reason text NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE INDEX admin_audit_logs_target_index ON admin_audit_logs ( target );`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add API key revocations",
+				Version:     113,
+				Action: migrate.SQL{
+					`CREATE TABLE api_key_revocations (
+						project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+						tail bytea NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( project_id, tail )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add bucket event subscriptions and outbox",
+				Version:     114,
+				Action: migrate.SQL{
+					`CREATE TABLE bucket_event_subscriptions (
+						id bytea NOT NULL,
+						project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
+						bucket_name bytea NOT NULL,
+						url text NOT NULL,
+						secret bytea NOT NULL,
+						event_types integer NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE TABLE bucket_events (
+						id bigserial NOT NULL,
+						subscription_id bytea NOT NULL REFERENCES bucket_event_subscriptions( id ) ON DELETE CASCADE,
+						payload bytea NOT NULL,
+						attempts integer NOT NULL DEFAULT 0,
+						next_attempt_at timestamp with time zone NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE INDEX bucket_event_subscriptions_project_id_bucket_name_index ON bucket_event_subscriptions ( project_id, bucket_name );`,
+					`CREATE INDEX bucket_events_next_attempt_at_index ON bucket_events ( next_attempt_at );`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add graceful exit shrinks",
+				Version:     115,
+				Action: migrate.SQL{
+					`CREATE TABLE graceful_exit_shrinks (
+						node_id bytea NOT NULL,
+						target_bytes bigint NOT NULL,
+						queued_bytes bigint NOT NULL DEFAULT 0,
+						bytes_transferred bigint NOT NULL DEFAULT 0,
+						created_at timestamp with time zone NOT NULL,
+						loop_completed_at timestamp with time zone,
+						finished_at timestamp with time zone,
+						PRIMARY KEY ( node_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add live accounting totals",
+				Version:     116,
+				Action: migrate.SQL{
+					`CREATE TABLE live_accounting_totals (
+						project_id bytea NOT NULL,
+						bucket_name bytea NOT NULL,
+						total bigint NOT NULL,
+						PRIMARY KEY ( project_id, bucket_name )
+					);`,
+				},
+			},
 		},
 	}
 }
@@ -108,25 +108,25 @@ func (cache *overlaycache) selectStorageNodesOnce(ctx context.Context, reputable
 	// Later, the flag allows us to distinguish if a node is new when scanning the db rows.
 	if !criteria.DistinctIP {
 		reputableNodeQuery = partialQuery{
-			selection: `SELECT last_net, id, address, last_ip_port, false FROM nodes`,
+			selection: `SELECT last_net, id, address, last_ip_port, country_code, false FROM nodes`,
 			condition: reputableNodesCondition,
 			limit:     reputableNodeCount,
 		}
 		newNodeQuery = partialQuery{
-			selection: `SELECT last_net, id, address, last_ip_port, true FROM nodes`,
+			selection: `SELECT last_net, id, address, last_ip_port, country_code, true FROM nodes`,
 			condition: newNodesCondition,
 			limit:     newNodeCount,
 		}
 	} else {
 		reputableNodeQuery = partialQuery{
-			selection: `SELECT DISTINCT ON (last_net) last_net, id, address, last_ip_port, false FROM nodes`,
+			selection: `SELECT DISTINCT ON (last_net) last_net, id, address, last_ip_port, country_code, false FROM nodes`,
 			condition: reputableNodesCondition,
 			distinct:  true,
 			limit:     reputableNodeCount,
 			orderBy:   "last_net",
 		}
 		newNodeQuery = partialQuery{
-			selection: `SELECT DISTINCT ON (last_net) last_net, id, address, last_ip_port, true FROM nodes`,
+			selection: `SELECT DISTINCT ON (last_net) last_net, id, address, last_ip_port, country_code, true FROM nodes`,
 			condition: newNodesCondition,
 			distinct:  true,
 			limit:     newNodeCount,
@@ -145,10 +145,10 @@ func (cache *overlaycache) selectStorageNodesOnce(ctx context.Context, reputable
 	for rows.Next() {
 		var node overlay.SelectedNode
 		node.Address = &pb.NodeAddress{Transport: pb.NodeTransport_TCP_TLS_GRPC}
-		var lastIPPort sql.NullString
+		var lastIPPort, countryCode sql.NullString
 		var isNew bool
 
-		err = rows.Scan(&node.LastNet, &node.ID, &node.Address.Address, &node.LastIPPort, &isNew)
+		err = rows.Scan(&node.LastNet, &node.ID, &node.Address.Address, &node.LastIPPort, &countryCode, &isNew)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -156,6 +156,9 @@ func (cache *overlaycache) selectStorageNodesOnce(ctx context.Context, reputable
 		if lastIPPort.Valid {
 			node.LastIPPort = lastIPPort.String
 		}
+		if countryCode.Valid {
+			node.CountryCode = countryCode.String
+		}
 
 		if isNew {
 			newNodes = append(newNodes, &node)
@@ -210,6 +213,12 @@ func nodeSelectionCondition(ctx context.Context, criteria *overlay.NodeCriteria,
 			postgresNodeIDList(excludedIDs),
 		)
 	}
+	if criteria.Placement != overlay.EveryCountry {
+		conds.add(
+			`country_code = any(?::text[])`,
+			pq.Array(criteria.Placement.Countries()),
+		)
+	}
 	if criteria.DistinctIP {
 		if len(excludedNetworks) > 0 {
 			conds.add(
@@ -9,6 +9,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -38,7 +39,7 @@ func (cache *overlaycache) SelectAllStorageNodesUpload(ctx context.Context, sele
 	defer mon.Task()(&ctx)(&err)
 
 	query := `
-		SELECT id, address, last_net, last_ip_port, (total_audit_count < $1 OR total_uptime_count < $2) as isnew
+		SELECT id, address, last_net, last_ip_port, country_code, (total_audit_count < $1 OR total_uptime_count < $2) as isnew
 			FROM nodes
 			WHERE disqualified IS NULL
 			AND suspended IS NULL
@@ -80,15 +81,18 @@ func (cache *overlaycache) SelectAllStorageNodesUpload(ctx context.Context, sele
 	for rows.Next() {
 		var node overlay.SelectedNode
 		node.Address = &pb.NodeAddress{}
-		var lastIPPort sql.NullString
+		var lastIPPort, countryCode sql.NullString
 		var isnew bool
-		err = rows.Scan(&node.ID, &node.Address.Address, &node.LastNet, &lastIPPort, &isnew)
+		err = rows.Scan(&node.ID, &node.Address.Address, &node.LastNet, &lastIPPort, &countryCode, &isnew)
 		if err != nil {
 			return nil, nil, err
 		}
 		if lastIPPort.Valid {
 			node.LastIPPort = lastIPPort.String
 		}
+		if countryCode.Valid {
+			node.CountryCode = countryCode.String
+		}
 
 		if isnew {
 			newNodes = append(newNodes, &node)
@@ -565,10 +569,43 @@ func (cache *overlaycache) UpdateUptime(ctx context.Context, nodeID storj.NodeID
 // DisqualifyNode disqualifies a storage node.
 func (cache *overlaycache) DisqualifyNode(ctx context.Context, nodeID storj.NodeID) (err error) {
 	defer mon.Task()(&ctx)(&err)
+	return disqualifyNode(ctx, cache.db, nodeID)
+}
+
+// ReinstateNode removes the disqualification of a storage node and resets its
+// audit reputation.
+func (cache *overlaycache) ReinstateNode(ctx context.Context, nodeID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return reinstateNode(ctx, cache.db, nodeID)
+}
+
+// disqualifyNode disqualifies a storage node with methods, which are either
+// the database or a transaction.
+func disqualifyNode(ctx context.Context, methods dbx.Methods, nodeID storj.NodeID) error {
 	updateFields := dbx.Node_Update_Fields{}
 	updateFields.Disqualified = dbx.Node_Disqualified(time.Now().UTC())
 
-	dbNode, err := cache.db.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
+	dbNode, err := methods.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
+	if err != nil {
+		return err
+	}
+	if dbNode == nil {
+		return errs.New("unable to get node by ID: %v", nodeID)
+	}
+	return nil
+}
+
+// reinstateNode removes the disqualification of a storage node with methods,
+// which are either the database or a transaction. The audit reputation is
+// reset to the one of a new node, otherwise the next failed audit would
+// disqualify the node again.
+func reinstateNode(ctx context.Context, methods dbx.Methods, nodeID storj.NodeID) error {
+	updateFields := dbx.Node_Update_Fields{}
+	updateFields.Disqualified = dbx.Node_Disqualified_Null()
+	updateFields.AuditReputationAlpha = dbx.Node_AuditReputationAlpha(1)
+	updateFields.AuditReputationBeta = dbx.Node_AuditReputationBeta(0)
+
+	dbNode, err := methods.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
 	if err != nil {
 		return err
 	}
@@ -597,10 +634,16 @@ func (cache *overlaycache) SuspendNode(ctx context.Context, nodeID storj.NodeID,
 // UnsuspendNode unsuspends a storage node.
 func (cache *overlaycache) UnsuspendNode(ctx context.Context, nodeID storj.NodeID) (err error) {
 	defer mon.Task()(&ctx)(&err)
+	return unsuspendNode(ctx, cache.db, nodeID)
+}
+
+// unsuspendNode unsuspends a storage node with methods, which are either the
+// database or a transaction.
+func unsuspendNode(ctx context.Context, methods dbx.Methods, nodeID storj.NodeID) error {
 	updateFields := dbx.Node_Update_Fields{}
 	updateFields.Suspended = dbx.Node_Suspended_Null()
 
-	dbNode, err := cache.db.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
+	dbNode, err := methods.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
 	if err != nil {
 		return err
 	}
@@ -610,6 +653,56 @@ func (cache *overlaycache) UnsuspendNode(ctx context.Context, nodeID storj.NodeI
 	return nil
 }
 
+// SearchNodes returns up to limit nodes ordered by ID, starting after cursor.
+// A non-empty search restricts the nodes to the one with that ID and the ones
+// whose address, email or wallet contains it.
+func (cache *overlaycache) SearchNodes(ctx context.Context, search string, cursor storj.NodeID, limit int) (nodes []overlay.NodeSearchResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// the search matches the node ID only when it's a valid node ID
+	var searchID []byte
+	if id, err := storj.NodeIDFromString(search); err == nil {
+		searchID = id.Bytes()
+	}
+
+	rows, err := cache.db.Query(ctx, cache.db.Rebind(`
+		SELECT id, address, email, wallet, last_contact_success,
+			disqualified, suspended, exit_initiated_at, exit_finished_at
+		FROM nodes
+		WHERE id > ?
+			AND (
+				? = ''
+				OR id = ?
+				OR address ILIKE ?
+				OR email ILIKE ?
+				OR wallet ILIKE ?
+			)
+		ORDER BY id
+		LIMIT ?
+	`), cursor, search, searchID, likePattern(search), likePattern(search), likePattern(search), limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var node overlay.NodeSearchResult
+		err = rows.Scan(&node.ID, &node.Address, &node.Email, &node.Wallet, &node.LastContactSuccess,
+			&node.Disqualified, &node.Suspended, &node.ExitInitiatedAt, &node.ExitFinishedAt)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, Error.Wrap(rows.Err())
+}
+
+// likePattern returns a LIKE pattern matching the strings which contain s.
+func likePattern(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + s + "%"
+}
+
 // AllPieceCounts returns a map of node IDs to piece counts from the db.
 // NB: a valid, partial piece map can be returned even if node ID parsing error(s) are returned.
 func (cache *overlaycache) AllPieceCounts(ctx context.Context) (_ map[storj.NodeID]int, err error) {
@@ -791,12 +884,17 @@ func (cache *overlaycache) GetGracefulExitIncompleteByTimeFrame(ctx context.Cont
 // UpdateExitStatus is used to update a node's graceful exit status.
 func (cache *overlaycache) UpdateExitStatus(ctx context.Context, request *overlay.ExitStatusRequest) (_ *overlay.NodeDossier, err error) {
 	defer mon.Task()(&ctx)(&err)
+	return updateExitStatus(ctx, cache.db, request)
+}
 
+// updateExitStatus updates the graceful exit status of a node with methods,
+// which are either the database or a transaction.
+func updateExitStatus(ctx context.Context, methods dbx.Methods, request *overlay.ExitStatusRequest) (_ *overlay.NodeDossier, err error) {
 	nodeID := request.NodeID
 
 	updateFields := populateExitStatusFields(request)
 
-	dbNode, err := cache.db.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
+	dbNode, err := methods.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
@@ -841,6 +939,52 @@ func (cache *overlaycache) GetSuccesfulNodesNotCheckedInSince(ctx context.Contex
 	return nodeLastContacts, nil
 }
 
+// CancelExit clears the exit initiation of a node which hasn't completed the exit loop yet, and returns whether it was cleared.
+func (cache *overlaycache) CancelExit(ctx context.Context, nodeID storj.NodeID) (canceled bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := cache.db.ExecContext(ctx, cache.db.Rebind(`
+		UPDATE nodes
+		SET exit_initiated_at = NULL
+		WHERE id = ?
+			AND exit_initiated_at IS NOT NULL
+			AND exit_loop_completed_at IS NULL
+			AND exit_finished_at IS NULL
+	`), nodeID)
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return affected > 0, nil
+}
+
+// CompleteExitLoop sets the exit loop completion of a node whose exit is still initiated, and returns whether it was set.
+func (cache *overlaycache) CompleteExitLoop(ctx context.Context, nodeID storj.NodeID, completedAt time.Time) (completed bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := cache.db.ExecContext(ctx, cache.db.Rebind(`
+		UPDATE nodes
+		SET exit_loop_completed_at = ?
+		WHERE id = ?
+			AND exit_initiated_at IS NOT NULL
+			AND exit_loop_completed_at IS NULL
+			AND exit_finished_at IS NULL
+	`), completedAt, nodeID)
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return affected > 0, nil
+}
+
 func populateExitStatusFields(req *overlay.ExitStatusRequest) dbx.Node_Update_Fields {
 	dbxUpdateFields := dbx.Node_Update_Fields{}
 
@@ -944,6 +1088,9 @@ func convertDBNode(ctx context.Context, info *dbx.Node) (_ *overlay.NodeDossier,
 	if info.LastIpPort != nil {
 		node.LastIPPort = *info.LastIpPort
 	}
+	if info.CountryCode != nil {
+		node.CountryCode = *info.CountryCode
+	}
 
 	return node, nil
 }
@@ -1338,7 +1485,7 @@ func (cache *overlaycache) UpdateCheckIn(ctx context.Context, node overlay.NodeC
 				audit_reputation_alpha, audit_reputation_beta,
 				unknown_audit_reputation_alpha, unknown_audit_reputation_beta,
 				major, minor, patch, hash, timestamp, release,
-				last_ip_port
+				last_ip_port, country_code
 			)
 			VALUES (
 				$1, $2, $3, $4, $5,
@@ -1353,7 +1500,7 @@ func (cache *overlaycache) UpdateCheckIn(ctx context.Context, node overlay.NodeC
 				$10, $11,
 				$10, $11,
 				$12, $13, $14, $15, $16, $17,
-				$19
+				$19, $20
 			)
 			ON CONFLICT (id)
 			DO UPDATE
@@ -1375,7 +1522,8 @@ func (cache *overlaycache) UpdateCheckIn(ctx context.Context, node overlay.NodeC
 					THEN $18::timestamptz
 					ELSE nodes.last_contact_failure
 				END,
-				last_ip_port=$19;
+				last_ip_port=$19,
+				country_code=$20;
 			`
 	_, err = cache.db.ExecContext(ctx, query,
 		// args $1 - $5
@@ -1392,6 +1540,8 @@ func (cache *overlaycache) UpdateCheckIn(ctx context.Context, node overlay.NodeC
 		timestamp,
 		// args $19
 		node.LastIPPort,
+		// args $20
+		sql.NullString{String: node.CountryCode, Valid: node.CountryCode != ""},
 	)
 	if err != nil {
 		return Error.Wrap(err)
@@ -0,0 +1,67 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/metainfo"
+)
+
+type pieceReferencesDB struct {
+	db *satelliteDB
+}
+
+// PieceReferences returns the database to count the pointers sharing pieces.
+func (db *satelliteDB) PieceReferences() metainfo.PieceReferencesDB {
+	return &pieceReferencesDB{db: db}
+}
+
+// Add records that one more pointer references the pieces of rootPieceID.
+//
+// The first copy of a pointer creates the row with two references, the
+// original pointer and the copy.
+func (db *pieceReferencesDB) Add(ctx context.Context, rootPieceID storj.PieceID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO piece_references (root_piece_id, reference_count) VALUES (?, 2)
+		ON CONFLICT(root_piece_id)
+		DO UPDATE SET reference_count = piece_references.reference_count + 1
+	`), rootPieceID.Bytes())
+	return Error.Wrap(err)
+}
+
+// Release records that a pointer referencing the pieces of rootPieceID has
+// been deleted and returns whether other pointers still reference them.
+func (db *pieceReferencesDB) Release(ctx context.Context, rootPieceID storj.PieceID) (referenced bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var count int
+	err = db.db.QueryRowContext(ctx, db.db.Rebind(`
+		UPDATE piece_references SET reference_count = reference_count - 1
+		WHERE root_piece_id = ?
+		RETURNING reference_count
+	`), rootPieceID.Bytes()).Scan(&count)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+
+	if count <= 1 {
+		// a single pointer is left, which doesn't need to be counted anymore
+		_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+			DELETE FROM piece_references WHERE root_piece_id = ? AND reference_count <= 1
+		`), rootPieceID.Bytes())
+		if err != nil {
+			return true, Error.Wrap(err)
+		}
+	}
+	return true, nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"storj.io/common/memory"
 	"storj.io/common/pb"
+	"storj.io/common/storj"
 	"storj.io/common/uuid"
 	"storj.io/storj/private/dbutil"
 	"storj.io/storj/satellite/accounting"
@@ -146,6 +147,19 @@ func (db *ProjectAccounting) GetProjectAllocatedBandwidth(ctx context.Context, p
 	return *egress, err
 }
 
+// GetBucketAllocatedBandwidth returns the sum of GET bandwidth usage allocated for a bucket since from.
+func (db *ProjectAccounting) GetBucketAllocatedBandwidth(ctx context.Context, projectID uuid.UUID, bucketName []byte, from time.Time) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	var sum *int64
+	query := `SELECT SUM(allocated) FROM bucket_bandwidth_rollups WHERE project_id = ? AND bucket_name = ? AND action = ? AND interval_start >= ?;`
+	err = db.db.QueryRow(ctx, db.db.Rebind(query), projectID[:], bucketName, pb.PieceAction_GET, from.UTC()).Scan(&sum)
+	if err == sql.ErrNoRows || sum == nil {
+		return 0, nil
+	}
+
+	return *sum, err
+}
+
 // GetStorageTotals returns the current inline and remote storage usage for a projectID
 func (db *ProjectAccounting) GetStorageTotals(ctx context.Context, projectID uuid.UUID) (inline int64, remote int64, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -208,6 +222,63 @@ func (db *ProjectAccounting) getProjectUsageLimit(ctx context.Context, projectID
 	return memory.Size(row.UsageLimit), nil
 }
 
+// GetBucketLimits returns the storage and bandwidth limits of a bucket.
+func (db *ProjectAccounting) GetBucketLimits(ctx context.Context, projectID uuid.UUID, bucketName []byte) (_ accounting.BucketLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dbxBucket, err := db.db.Get_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.BucketLimits{}, storj.ErrBucketNotFound.New("%s", bucketName)
+		}
+		return accounting.BucketLimits{}, Error.Wrap(err)
+	}
+
+	var limits accounting.BucketLimits
+	if dbxBucket.StorageLimit != nil {
+		limit := memory.Size(*dbxBucket.StorageLimit)
+		limits.Storage = &limit
+	}
+	if dbxBucket.BandwidthLimit != nil {
+		limit := memory.Size(*dbxBucket.BandwidthLimit)
+		limits.Bandwidth = &limit
+	}
+	return limits, nil
+}
+
+// UpdateBucketLimits updates the storage and bandwidth limits of a bucket.
+// A nil limit removes the limit.
+func (db *ProjectAccounting) UpdateBucketLimits(ctx context.Context, projectID uuid.UUID, bucketName []byte, limits accounting.BucketLimits) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	updateFields := dbx.BucketMetainfo_Update_Fields{
+		StorageLimit:   dbx.BucketMetainfo_StorageLimit_Null(),
+		BandwidthLimit: dbx.BucketMetainfo_BandwidthLimit_Null(),
+	}
+	if limits.Storage != nil {
+		updateFields.StorageLimit = dbx.BucketMetainfo_StorageLimit(limits.Storage.Int64())
+	}
+	if limits.Bandwidth != nil {
+		updateFields.BandwidthLimit = dbx.BucketMetainfo_BandwidthLimit(limits.Bandwidth.Int64())
+	}
+
+	dbxBucket, err := db.db.Update_BucketMetainfo_By_ProjectId_And_Name(ctx,
+		dbx.BucketMetainfo_ProjectId(projectID[:]),
+		dbx.BucketMetainfo_Name(bucketName),
+		updateFields,
+	)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if dbxBucket == nil {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
 // GetProjectTotal retrieves project usage for a given period.
 func (db *ProjectAccounting) GetProjectTotal(ctx context.Context, projectID uuid.UUID, since, before time.Time) (usage *accounting.ProjectUsage, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -156,6 +156,19 @@ func (projects *projects) UpdateRateLimit(ctx context.Context, id uuid.UUID, new
 	return err
 }
 
+// UpdateOwner is a method for transferring the ownership of a project to another user.
+func (projects *projects) UpdateOwner(ctx context.Context, id uuid.UUID, ownerID uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = projects.db.Update_Project_By_Id(ctx,
+		dbx.Project_Id(id[:]),
+		dbx.Project_Update_Fields{
+			OwnerId: dbx.Project_OwnerId(ownerID[:]),
+		})
+
+	return err
+}
+
 // List returns paginated projects, created before provided timestamp.
 func (projects *projects) List(ctx context.Context, offset int64, limit int, before time.Time) (_ console.ProjectsPage, err error) {
 	defer mon.Task()(&ctx)(&err)
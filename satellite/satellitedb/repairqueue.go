@@ -11,6 +11,8 @@ import (
 
 	"storj.io/common/pb"
 	"storj.io/storj/private/dbutil"
+	"storj.io/storj/satellite/repair/queue"
+	"storj.io/storj/satellite/satellitedb/dbx"
 	"storj.io/storj/storage"
 )
 
@@ -21,23 +23,24 @@ type repairQueue struct {
 	db *satelliteDB
 }
 
-func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment, numHealthy int) (err error) {
+func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment, numHealthy int, priority float64) (err error) {
 	defer mon.Task()(&ctx)(&err)
-	// insert if not exists, or update healthy count if does exist
+	// insert if not exists, or update healthy count and priority if does exist
 	query := `
 		INSERT INTO injuredsegments
 		(
-			path, data, num_healthy_pieces
+			path, data, num_healthy_pieces, priority
 		)
 		VALUES (
-			$1, $2, $3
+			$1, $2, $3, $4
 		)
 		ON CONFLICT (path)
 		DO UPDATE
 		SET
-			num_healthy_pieces=$3
+			num_healthy_pieces=$3,
+			priority=$4
 		`
-	_, err = r.db.ExecContext(ctx, query, seg.Path, seg, numHealthy)
+	_, err = r.db.ExecContext(ctx, query, seg.Path, seg, numHealthy, priority)
 	return err
 }
 
@@ -48,15 +51,17 @@ func (r *repairQueue) Select(ctx context.Context) (seg *pb.InjuredSegment, err e
 		err = r.db.QueryRowContext(ctx, `
 				UPDATE injuredsegments SET attempted = now() WHERE path = (
 					SELECT path FROM injuredsegments
-					WHERE attempted IS NULL OR attempted < now() - interval '6 hours'
-					ORDER BY num_healthy_pieces ASC, attempted LIMIT 1
+					WHERE (attempted IS NULL OR attempted < now() - interval '6 hours')
+						AND (retry_after IS NULL OR retry_after < now())
+					ORDER BY priority DESC, num_healthy_pieces ASC, attempted LIMIT 1
 				) RETURNING data`).Scan(&seg)
 	case dbutil.Postgres:
 		err = r.db.QueryRowContext(ctx, `
 				UPDATE injuredsegments SET attempted = now() WHERE path = (
 					SELECT path FROM injuredsegments
-					WHERE attempted IS NULL OR attempted < now() - interval '6 hours'
-					ORDER BY num_healthy_pieces ASC, attempted NULLS FIRST FOR UPDATE SKIP LOCKED LIMIT 1
+					WHERE (attempted IS NULL OR attempted < now() - interval '6 hours')
+						AND (retry_after IS NULL OR retry_after < now())
+					ORDER BY priority DESC, num_healthy_pieces ASC, attempted NULLS FIRST FOR UPDATE SKIP LOCKED LIMIT 1
 				) RETURNING data`).Scan(&seg)
 	default:
 		return seg, errs.New("invalid dbType: %v", r.db.implementation)
@@ -69,8 +74,71 @@ func (r *repairQueue) Select(ctx context.Context) (seg *pb.InjuredSegment, err e
 
 func (r *repairQueue) Delete(ctx context.Context, seg *pb.InjuredSegment) (err error) {
 	defer mon.Task()(&ctx)(&err)
-	_, err = r.db.ExecContext(ctx, r.db.Rebind(`DELETE FROM injuredsegments WHERE path = ?`), seg.Path)
-	return Error.Wrap(err)
+	return Error.Wrap(r.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		_, err := tx.Tx.ExecContext(ctx, r.db.Rebind(`DELETE FROM injuredsegments WHERE path = ?`), seg.Path)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Tx.ExecContext(ctx, r.db.Rebind(`DELETE FROM repair_attempts WHERE path = ?`), seg.Path)
+		return err
+	}))
+}
+
+func (r *repairQueue) RecordFailure(ctx context.Context, attempt queue.Attempt, backoff queue.Backoff) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return Error.Wrap(r.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		var failures int
+		err := tx.Tx.QueryRowContext(ctx, r.db.Rebind(`
+			UPDATE injuredsegments SET failures = failures + 1
+			WHERE path = ?
+			RETURNING failures
+		`), attempt.Path).Scan(&failures)
+		if err == sql.ErrNoRows {
+			// the segment isn't queued anymore, e.g. because it was deleted
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		retryAfter := attempt.AttemptedAt.Add(backoff.Delay(failures))
+		_, err = tx.Tx.ExecContext(ctx, r.db.Rebind(`UPDATE injuredsegments SET retry_after = ? WHERE path = ?`), retryAfter, attempt.Path)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Tx.ExecContext(ctx, r.db.Rebind(`
+			INSERT INTO repair_attempts ( path, attempted_at, failure_reason )
+			VALUES ( ?, ?, ? )
+			ON CONFLICT DO NOTHING
+		`), attempt.Path, attempt.AttemptedAt, attempt.FailureReason)
+		return err
+	}))
+}
+
+func (r *repairQueue) Attempts(ctx context.Context, path []byte) (attempts []queue.Attempt, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(`
+		SELECT attempted_at, failure_reason FROM repair_attempts
+		WHERE path = ?
+		ORDER BY attempted_at DESC
+	`), path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		attempt := queue.Attempt{Path: path}
+		err = rows.Scan(&attempt.AttemptedAt, &attempt.FailureReason)
+		if err != nil {
+			return attempts, Error.Wrap(err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, Error.Wrap(rows.Err())
 }
 
 func (r *repairQueue) SelectN(ctx context.Context, limit int) (segs []pb.InjuredSegment, err error) {
@@ -753,6 +753,15 @@ func (dir *Dir) Info() (DiskInfo, error) {
 	return diskInfoFromPath(path)
 }
 
+// DiskInfoFromPath returns information about the disk containing the path.
+func DiskInfoFromPath(path string) (DiskInfo, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return DiskInfo{}, err
+	}
+	return diskInfoFromPath(path)
+}
+
 type blobInfo struct {
 	ref           storage.BlobRef
 	path          string
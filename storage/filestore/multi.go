@@ -0,0 +1,511 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package filestore
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+)
+
+var _ storage.Blobs = (*multiStore)(nil)
+
+// ErrPartial is returned by the operations of a store spread over several
+// directories which change blobs when some, but not all, of the directories
+// failed. The reads which add up the results of the directories return the
+// results of the directories which didn't fail without an error instead, so
+// that a failed disk doesn't stop the services of the node.
+var ErrPartial = errs.Class("storage directories failed")
+
+// WeightedPath is a storage directory with the relative share of new blobs
+// it should receive.
+type WeightedPath struct {
+	Path   string
+	Weight float64
+}
+
+// WeightedPaths is a list of weighted storage directories, which can be
+// configured as a comma-separated list of "path" or "path=weight".
+type WeightedPaths []WeightedPath
+
+// String returns the comma-separated representation of the paths.
+func (paths WeightedPaths) String() string {
+	var strs []string
+	for _, path := range paths {
+		// the weight is required when the path itself contains "="
+		if path.Weight == 1 && !strings.Contains(path.Path, "=") {
+			strs = append(strs, path.Path)
+			continue
+		}
+		strs = append(strs, path.Path+"="+strconv.FormatFloat(path.Weight, 'f', -1, 64))
+	}
+	return strings.Join(strs, ",")
+}
+
+// Set parses a comma-separated list of "path" or "path=weight".
+func (paths *WeightedPaths) Set(value string) error {
+	var parsed WeightedPaths
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		path := WeightedPath{Path: entry, Weight: 1}
+		if i := strings.LastIndexByte(entry, '='); i >= 0 {
+			weight, err := strconv.ParseFloat(entry[i+1:], 64)
+			if err != nil {
+				return Error.New("invalid weight for %q: %v", entry, err)
+			}
+			if weight <= 0 {
+				return Error.New("weight for %q must be positive", entry)
+			}
+			path = WeightedPath{Path: entry[:i], Weight: weight}
+		}
+		parsed = append(parsed, path)
+	}
+	*paths = parsed
+	return nil
+}
+
+// Type implements pflag.Value.
+func (WeightedPaths) Type() string { return "filestore.WeightedPaths" }
+
+// weightedStore is a single directory of a multiStore.
+type weightedStore struct {
+	path   string
+	weight float64
+	store  *blobStore
+}
+
+// multiStore implements a blob store spread over several directories.
+//
+// New blobs are placed in one of the directories, chosen at random
+// proportionally to its free space and weight. Existing blobs are looked up in
+// every directory. A directory which fails is logged and skipped, so losing a
+// disk only loses the blobs stored on it. The operations on every directory
+// return an ErrPartial error when some of them fail.
+type multiStore struct {
+	log    *zap.Logger
+	stores []*weightedStore
+}
+
+// NewMulti creates a new disk blob store spread over the specified directories.
+//
+// Directories which cannot be opened are logged and skipped, it fails only
+// when none of them can be opened.
+func NewMulti(log *zap.Logger, paths WeightedPaths, config Config) (storage.Blobs, error) {
+	store := &multiStore{log: log}
+
+	var group errs.Group
+	for _, path := range paths {
+		dir, err := NewDir(path.Path)
+		if err != nil {
+			log.Error("unable to open storage directory", zap.String("Path", path.Path), zap.Error(err))
+			group.Add(err)
+			continue
+		}
+		store.stores = append(store.stores, &weightedStore{
+			path:   path.Path,
+			weight: path.Weight,
+			store:  &blobStore{dir: dir, log: log, config: config},
+		})
+	}
+
+	if len(store.stores) == 0 {
+		if err := group.Err(); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		return nil, Error.New("no storage directories")
+	}
+	return store, nil
+}
+
+// Close closes the store.
+func (store *multiStore) Close() error { return nil }
+
+// failed logs a failure of a single directory, which is skipped.
+func (store *multiStore) failed(dir *weightedStore, msg string, err error) {
+	store.log.Error(msg, zap.String("Path", dir.path), zap.Error(err))
+}
+
+// Open loads blob with the specified hash from the directory which contains it.
+func (store *multiStore) Open(ctx context.Context, ref storage.BlobRef) (_ storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+	var group errs.Group
+	for _, dir := range store.stores {
+		reader, err := dir.store.Open(ctx, ref)
+		if err == nil {
+			return reader, nil
+		}
+		if !os.IsNotExist(err) {
+			store.failed(dir, "unable to open blob", err)
+			group.Add(err)
+		}
+	}
+	if err := group.Err(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return nil, os.ErrNotExist
+}
+
+// OpenWithStorageFormat loads the already-located blob, avoiding the potential need to check multiple
+// storage formats to find the blob.
+func (store *multiStore) OpenWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (_ storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+	var group errs.Group
+	for _, dir := range store.stores {
+		reader, err := dir.store.OpenWithStorageFormat(ctx, ref, formatVer)
+		if err == nil {
+			return reader, nil
+		}
+		if !os.IsNotExist(err) {
+			store.failed(dir, "unable to open blob", err)
+			group.Add(err)
+		}
+	}
+	if err := group.Err(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return nil, os.ErrNotExist
+}
+
+// Stat looks up disk metadata on the blob file in the directory which contains it.
+func (store *multiStore) Stat(ctx context.Context, ref storage.BlobRef) (_ storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	_, info, err := store.locate(ctx, func(dir *weightedStore) (storage.BlobInfo, error) {
+		return dir.store.Stat(ctx, ref)
+	})
+	return info, err
+}
+
+// StatWithStorageFormat looks up disk metadata on the blob file with the given storage format version
+func (store *multiStore) StatWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (_ storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	_, info, err := store.locate(ctx, func(dir *weightedStore) (storage.BlobInfo, error) {
+		return dir.store.StatWithStorageFormat(ctx, ref, formatVer)
+	})
+	return info, err
+}
+
+// locate finds the directory for which stat succeeds. It returns a not exist
+// error when the blob isn't in any of the directories.
+func (store *multiStore) locate(ctx context.Context, stat func(dir *weightedStore) (storage.BlobInfo, error)) (_ *weightedStore, _ storage.BlobInfo, err error) {
+	var group errs.Group
+	for _, dir := range store.stores {
+		info, err := stat(dir)
+		if err == nil {
+			return dir, info, nil
+		}
+		if !errs.Is(err, os.ErrNotExist) {
+			store.failed(dir, "unable to stat blob", err)
+			group.Add(err)
+		}
+	}
+	if err := group.Err(); err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+	return nil, nil, Error.Wrap(os.ErrNotExist)
+}
+
+// Delete deletes blobs with the specified ref from every directory.
+//
+// It doesn't return an error if the blob isn't found for any reason or it cannot
+// be deleted at this moment and it's delayed.
+func (store *multiStore) Delete(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return store.each(func(dir *weightedStore) error {
+		return dir.store.Delete(ctx, ref)
+	})
+}
+
+// DeleteWithStorageFormat deletes blobs with the specified ref and storage format version from every directory.
+func (store *multiStore) DeleteWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return store.each(func(dir *weightedStore) error {
+		return dir.store.DeleteWithStorageFormat(ctx, ref, formatVer)
+	})
+}
+
+// Trash moves the ref to the trash directory of the directory which contains it.
+func (store *multiStore) Trash(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	dir, _, err := store.locate(ctx, func(dir *weightedStore) (storage.BlobInfo, error) {
+		return dir.store.Stat(ctx, ref)
+	})
+	if err != nil {
+		if errs.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return dir.store.Trash(ctx, ref)
+}
+
+// RestoreTrash moves every piece in the trash of every directory back into the regular location
+func (store *multiStore) RestoreTrash(ctx context.Context, namespace []byte) (keysRestored [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = store.each(func(dir *weightedStore) error {
+		keys, err := dir.store.RestoreTrash(ctx, namespace)
+		keysRestored = append(keysRestored, keys...)
+		return err
+	})
+	return keysRestored, err
+}
+
+// EmptyTrash removes all files in trash of every directory that have been there longer than trashExpiryDur
+func (store *multiStore) EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = store.each(func(dir *weightedStore) error {
+		emptied, emptiedKeys, err := dir.store.EmptyTrash(ctx, namespace, trashedBefore)
+		bytesEmptied += emptied
+		keys = append(keys, emptiedKeys...)
+		return err
+	})
+	return bytesEmptied, keys, err
+}
+
+// GarbageCollect tries to delete any files that haven't yet been deleted in every directory
+func (store *multiStore) GarbageCollect(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return store.each(func(dir *weightedStore) error {
+		return dir.store.GarbageCollect(ctx)
+	})
+}
+
+// each calls fn for every directory. The failing directories are logged and
+// skipped, it returns their combined errors, wrapped with ErrPartial when only
+// some of them fail.
+func (store *multiStore) each(fn func(dir *weightedStore) error) error {
+	var group errs.Group
+	for _, dir := range store.stores {
+		if err := fn(dir); err != nil {
+			store.failed(dir, "storage directory failed", err)
+			group.Add(err)
+		}
+	}
+	return store.combine(group)
+}
+
+// combine returns the combined errors of the failed directories.
+func (store *multiStore) combine(group errs.Group) error {
+	switch {
+	case len(group) == 0:
+		return nil
+	case len(group) < len(store.stores):
+		mon.Meter("storage_directory_partial_failure").Mark(1)
+		return ErrPartial.Wrap(group.Err())
+	default:
+		return Error.Wrap(group.Err())
+	}
+}
+
+// ignorePartial drops the error of a read which failed only for some of the
+// directories. The failures are already logged and metered.
+func ignorePartial(err error) error {
+	if ErrPartial.Has(err) {
+		return nil
+	}
+	return err
+}
+
+// Create creates a new blob that can be written in one of the directories
+// optionally takes a size argument for performance improvements, -1 is unknown size
+func (store *multiStore) Create(ctx context.Context, ref storage.BlobRef, size int64) (_ storage.BlobWriter, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return store.create(size, func(dir *weightedStore) (storage.BlobWriter, error) {
+		return dir.store.Create(ctx, ref, size)
+	})
+}
+
+// create creates a blob in one of the directories, trying the next one when
+// it fails.
+func (store *multiStore) create(size int64, create func(dir *weightedStore) (storage.BlobWriter, error)) (storage.BlobWriter, error) {
+	failed := map[*weightedStore]bool{}
+	for {
+		dir, err := store.choose(size, failed)
+		if err != nil {
+			return nil, err
+		}
+		writer, err := create(dir)
+		if err == nil {
+			return writer, nil
+		}
+		store.failed(dir, "unable to create blob", err)
+		failed[dir] = true
+	}
+}
+
+// choose picks a directory for a new blob, at random proportionally to its
+// free space and weight. Directories without enough space for the blob are
+// used only when none of them has enough space.
+func (store *multiStore) choose(size int64, skip map[*weightedStore]bool) (*weightedStore, error) {
+	type candidate struct {
+		dir   *weightedStore
+		score float64
+	}
+
+	var group errs.Group
+	var fitting, all []candidate
+	for _, dir := range store.stores {
+		if skip[dir] {
+			continue
+		}
+		free, err := dir.store.FreeSpace()
+		if err != nil {
+			store.failed(dir, "unable to get free space", err)
+			group.Add(err)
+			continue
+		}
+		if free < 0 {
+			free = 0
+		}
+		c := candidate{dir: dir, score: float64(free) * dir.weight}
+		all = append(all, c)
+		if size < 0 || free > size {
+			fitting = append(fitting, c)
+		}
+	}
+
+	candidates := fitting
+	if len(candidates) == 0 {
+		candidates = all
+	}
+	if len(candidates) == 0 {
+		return nil, Error.New("no storage directory available")
+	}
+
+	var total float64
+	for _, c := range candidates {
+		total += c.score
+	}
+	if total <= 0 {
+		return candidates[rand.Intn(len(candidates))].dir, nil
+	}
+
+	pick := rand.Float64() * total
+	for _, c := range candidates {
+		pick -= c.score
+		if pick < 0 {
+			return c.dir, nil
+		}
+	}
+	return candidates[len(candidates)-1].dir, nil
+}
+
+// SpaceUsedForBlobs adds up the space used in all namespaces and directories for blob storage
+func (store *multiStore) SpaceUsedForBlobs(ctx context.Context) (space int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = store.each(func(dir *weightedStore) error {
+		used, err := dir.store.SpaceUsedForBlobs(ctx)
+		space += used
+		return err
+	})
+	return space, ignorePartial(err)
+}
+
+// SpaceUsedForBlobsInNamespace adds up how much is used in the given namespace of every directory for blob storage
+func (store *multiStore) SpaceUsedForBlobsInNamespace(ctx context.Context, namespace []byte) (space int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = store.each(func(dir *weightedStore) error {
+		used, err := dir.store.SpaceUsedForBlobsInNamespace(ctx, namespace)
+		space += used
+		return err
+	})
+	return space, ignorePartial(err)
+}
+
+// SpaceUsedForTrash returns the total space used by the trash of every directory
+func (store *multiStore) SpaceUsedForTrash(ctx context.Context) (total int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = store.each(func(dir *weightedStore) error {
+		used, err := dir.store.SpaceUsedForTrash(ctx)
+		total += used
+		return err
+	})
+	return total, ignorePartial(err)
+}
+
+// FreeSpace returns how much space is left in all of the directories. Directories
+// sharing the same disk are counted only once.
+func (store *multiStore) FreeSpace() (int64, error) {
+	var total int64
+	disks := map[string]struct{}{}
+	err := store.each(func(dir *weightedStore) error {
+		info, err := dir.store.dir.Info()
+		if err != nil {
+			return err
+		}
+		if _, counted := disks[info.ID]; counted && info.ID != "" {
+			return nil
+		}
+		disks[info.ID] = struct{}{}
+		total += info.AvailableSpace
+		return nil
+	})
+	return total, ignorePartial(err)
+}
+
+// ListNamespaces finds all known namespace IDs in use in any of the directories. They are not
+// guaranteed to contain any blobs.
+func (store *multiStore) ListNamespaces(ctx context.Context) (ids [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	seen := map[string]struct{}{}
+	err = store.each(func(dir *weightedStore) error {
+		namespaces, err := dir.store.ListNamespaces(ctx)
+		for _, namespace := range namespaces {
+			if _, ok := seen[string(namespace)]; ok {
+				continue
+			}
+			seen[string(namespace)] = struct{}{}
+			ids = append(ids, namespace)
+		}
+		return err
+	})
+	return ids, ignorePartial(err)
+}
+
+// WalkNamespace executes walkFunc for each locally stored blob in the given namespace of every
+// directory. If walkFunc returns a non-nil error, WalkNamespace will stop iterating and return
+// the error immediately. The ctx parameter is intended specifically to allow canceling iteration
+// early. The directories which fail are skipped, their errors are only returned when all of them
+// fail.
+func (store *multiStore) WalkNamespace(ctx context.Context, namespace []byte, walkFunc func(storage.BlobInfo) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	var group errs.Group
+	for _, dir := range store.stores {
+		var walkErr error
+		err := dir.store.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+			walkErr = walkFunc(info)
+			return walkErr
+		})
+		if walkErr != nil {
+			return walkErr
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			store.failed(dir, "unable to walk namespace", err)
+			group.Add(err)
+		}
+	}
+	return ignorePartial(store.combine(group))
+}
+
+// TestCreateV0 creates a new V0 blob that can be written. This is ONLY appropriate in test situations.
+func (store *multiStore) TestCreateV0(ctx context.Context, ref storage.BlobRef) (_ storage.BlobWriter, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return store.create(-1, func(dir *weightedStore) (storage.BlobWriter, error) {
+		return dir.store.TestCreateV0(ctx, ref)
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package filestore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+)
+
+func TestWeightedPaths(t *testing.T) {
+	var paths filestore.WeightedPaths
+	require.NoError(t, paths.Set(""))
+	require.Empty(t, paths)
+
+	require.NoError(t, paths.Set("/mnt/a, /mnt/b=2.5,/mnt/c=d=1"))
+	require.Equal(t, filestore.WeightedPaths{
+		{Path: "/mnt/a", Weight: 1},
+		{Path: "/mnt/b", Weight: 2.5},
+		{Path: "/mnt/c=d", Weight: 1},
+	}, paths)
+	require.Equal(t, "/mnt/a,/mnt/b=2.5,/mnt/c=d=1", paths.String())
+
+	require.Error(t, paths.Set("/mnt/a=x"))
+	require.Error(t, paths.Set("/mnt/a=0"))
+	require.Error(t, paths.Set("/mnt/a=-1"))
+}
+
+func TestMultiStore(t *testing.T) {
+	const blobCount = 32
+	blobSize := memory.KiB
+
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dirs := []string{ctx.Dir("a"), ctx.Dir("b")}
+	store, err := filestore.NewMulti(zaptest.NewLogger(t), filestore.WeightedPaths{
+		{Path: dirs[0], Weight: 1},
+		{Path: dirs[1], Weight: 1},
+	}, filestore.DefaultConfig)
+	require.NoError(t, err)
+	ctx.Check(store.Close)
+
+	namespace := testrand.Bytes(namespaceSize)
+	data := testrand.BytesInt(blobSize.Int())
+
+	var refs []storage.BlobRef
+	for i := 0; i < blobCount; i++ {
+		ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(keySize)}
+		refs = append(refs, ref)
+
+		writer, err := store.Create(ctx, ref, int64(len(data)))
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+	}
+
+	// the blobs are spread across both directories
+	perDir := make([]int, len(dirs))
+	for i, dir := range dirs {
+		single, err := filestore.NewAt(zaptest.NewLogger(t), dir, filestore.DefaultConfig)
+		require.NoError(t, err)
+		require.NoError(t, single.WalkNamespace(ctx, namespace, func(storage.BlobInfo) error {
+			perDir[i]++
+			return nil
+		}))
+	}
+	require.NotZero(t, perDir[0])
+	require.NotZero(t, perDir[1])
+	require.Equal(t, blobCount, perDir[0]+perDir[1])
+
+	// the blobs are found regardless of the directory
+	for _, ref := range refs {
+		reader, err := store.Open(ctx, ref)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+
+		info, err := store.Stat(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, ref, info.BlobRef())
+	}
+
+	_, err = store.Open(ctx, storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(keySize)})
+	require.True(t, os.IsNotExist(err))
+
+	namespaces, err := store.ListNamespaces(ctx)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{namespace}, namespaces)
+
+	walked := 0
+	require.NoError(t, store.WalkNamespace(ctx, namespace, func(storage.BlobInfo) error {
+		walked++
+		return nil
+	}))
+	require.Equal(t, blobCount, walked)
+
+	// errors returned by walkFunc stop the walk
+	walkErr := filestore.Error.New("stop")
+	walked = 0
+	err = store.WalkNamespace(ctx, namespace, func(storage.BlobInfo) error {
+		walked++
+		return walkErr
+	})
+	require.Equal(t, walkErr, err)
+	require.Equal(t, 1, walked)
+
+	used, err := store.SpaceUsedForBlobs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(blobCount*len(data)), used)
+
+	free, err := store.FreeSpace()
+	require.NoError(t, err)
+	require.True(t, free > 0)
+
+	// trash and restore all the blobs
+	for _, ref := range refs {
+		require.NoError(t, store.Trash(ctx, ref))
+	}
+	walked = 0
+	require.NoError(t, store.WalkNamespace(ctx, namespace, func(storage.BlobInfo) error {
+		walked++
+		return nil
+	}))
+	require.Zero(t, walked)
+
+	trashUsed, err := store.SpaceUsedForTrash(ctx)
+	require.NoError(t, err)
+	require.True(t, trashUsed >= int64(blobCount*len(data)))
+
+	restored, err := store.RestoreTrash(ctx, namespace)
+	require.NoError(t, err)
+	require.Len(t, restored, blobCount)
+
+	// delete all the blobs
+	for _, ref := range refs {
+		require.NoError(t, store.Delete(ctx, ref))
+		_, err := store.Stat(ctx, ref)
+		require.Error(t, err)
+	}
+
+	// trashing and emptying the trash works across directories
+	ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(keySize)}
+	writer, err := store.Create(ctx, ref, -1)
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit(ctx))
+	require.NoError(t, store.Trash(ctx, ref))
+
+	emptied, keys, err := store.EmptyTrash(ctx, namespace, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, emptied >= int64(len(data)))
+	require.Equal(t, [][]byte{ref.Key}, keys)
+}
+
+func TestMultiStoreLostDirectory(t *testing.T) {
+	const blobCount = 32
+
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dirs := []string{ctx.Dir("a"), ctx.Dir("b")}
+	store, err := filestore.NewMulti(zaptest.NewLogger(t), filestore.WeightedPaths{
+		{Path: dirs[0], Weight: 1},
+		{Path: dirs[1], Weight: 1},
+	}, filestore.DefaultConfig)
+	require.NoError(t, err)
+	ctx.Check(store.Close)
+
+	namespace := testrand.Bytes(namespaceSize)
+	data := testrand.Bytes(memory.KiB)
+
+	var refs []storage.BlobRef
+	for i := 0; i < blobCount; i++ {
+		ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(keySize)}
+		refs = append(refs, ref)
+
+		writer, err := store.Create(ctx, ref, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+	}
+
+	// lose the second directory and replace it with a file, so that accessing
+	// anything in it fails
+	require.NoError(t, os.RemoveAll(dirs[1]))
+	require.NoError(t, ioutil.WriteFile(dirs[1], nil, 0644))
+
+	found := 0
+	for _, ref := range refs {
+		reader, err := store.Open(ctx, ref)
+		if err != nil {
+			continue
+		}
+		require.NoError(t, reader.Close())
+		found++
+	}
+	require.NotZero(t, found)
+	require.NotEqual(t, blobCount, found)
+
+	// the reads return the results of the other directory
+	walked := 0
+	err = store.WalkNamespace(ctx, namespace, func(storage.BlobInfo) error {
+		walked++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, found, walked)
+
+	used, err := store.SpaceUsedForBlobs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(found*len(data)), used)
+
+	// the failure of the directory is reported by the changes
+	err = store.Delete(ctx, refs[0])
+	require.True(t, filestore.ErrPartial.Has(err), err)
+
+	_, err = store.FreeSpace()
+	require.NoError(t, err)
+
+	// new blobs go to the remaining directory
+	for i := 0; i < blobCount; i++ {
+		ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(keySize)}
+		writer, err := store.Create(ctx, ref, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+	}
+}
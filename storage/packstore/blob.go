@@ -0,0 +1,183 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storage"
+)
+
+// blobReader reads a blob from its section of a pack file.
+type blobReader struct {
+	section       *io.SectionReader
+	file          *os.File
+	formatVersion storage.FormatVersion
+}
+
+func newBlobReader(file *os.File, entry Entry) *blobReader {
+	return &blobReader{
+		section:       io.NewSectionReader(file, entry.Offset, entry.Size),
+		file:          file,
+		formatVersion: entry.FormatVersion,
+	}
+}
+
+// Read reads from the blob.
+func (blob *blobReader) Read(p []byte) (int, error) { return blob.section.Read(p) }
+
+// ReadAt reads from the blob at the offset.
+func (blob *blobReader) ReadAt(p []byte, off int64) (int, error) { return blob.section.ReadAt(p, off) }
+
+// Seek seeks within the blob.
+func (blob *blobReader) Seek(offset int64, whence int) (int64, error) {
+	return blob.section.Seek(offset, whence)
+}
+
+// Close closes the pack file.
+func (blob *blobReader) Close() error { return blob.file.Close() }
+
+// Size returns how large is the blob.
+func (blob *blobReader) Size() (int64, error) { return blob.section.Size(), nil }
+
+// StorageFormatVersion gets the storage format version being used by the blob.
+func (blob *blobReader) StorageFormatVersion() storage.FormatVersion {
+	return blob.formatVersion
+}
+
+// blobWriter writes the blob to a temporary file, which is appended to a pack
+// file on commit.
+type blobWriter struct {
+	ref           storage.BlobRef
+	store         *Store
+	closed        bool
+	formatVersion storage.FormatVersion
+	buffer        *bufio.Writer
+	fh            *os.File
+}
+
+func newBlobWriter(ref storage.BlobRef, store *Store, formatVersion storage.FormatVersion, file *os.File) *blobWriter {
+	return &blobWriter{
+		ref:           ref,
+		store:         store,
+		formatVersion: formatVersion,
+		buffer:        bufio.NewWriterSize(file, writeBufferSize.Int()),
+		fh:            file,
+	}
+}
+
+// Write adds data to the blob.
+func (blob *blobWriter) Write(p []byte) (int, error) {
+	return blob.buffer.Write(p)
+}
+
+// Cancel discards the blob.
+func (blob *blobWriter) Cancel(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if blob.closed {
+		return nil
+	}
+	blob.closed = true
+
+	err = blob.fh.Close()
+	removeErr := os.Remove(blob.fh.Name())
+	return Error.Wrap(errs.Combine(err, removeErr))
+}
+
+// Commit appends the blob to a pack file.
+func (blob *blobWriter) Commit(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return blob.commit(ctx, time.Now())
+}
+
+func (blob *blobWriter) commit(ctx context.Context, modTime time.Time) (err error) {
+	if blob.closed {
+		return Error.New("already closed")
+	}
+	blob.closed = true
+
+	defer func() {
+		err = errs.Combine(err, blob.fh.Close(), os.Remove(blob.fh.Name()))
+	}()
+
+	if err := blob.buffer.Flush(); err != nil {
+		return Error.Wrap(err)
+	}
+
+	return Error.Wrap(blob.store.commit(ctx, blob.ref, blob.formatVersion, blob.fh, modTime))
+}
+
+// Seek flushes any buffer and seeks the underlying file.
+func (blob *blobWriter) Seek(offset int64, whence int) (int64, error) {
+	if err := blob.buffer.Flush(); err != nil {
+		return 0, err
+	}
+
+	return blob.fh.Seek(offset, whence)
+}
+
+// Size returns how much has been written so far.
+func (blob *blobWriter) Size() (int64, error) {
+	pos, err := blob.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+
+	return pos, err
+}
+
+// StorageFormatVersion indicates what storage format version the blob is using.
+func (blob *blobWriter) StorageFormatVersion() storage.FormatVersion {
+	return blob.formatVersion
+}
+
+// blobInfo is the metadata of a blob in a pack file.
+type blobInfo struct {
+	entry Entry
+	path  string
+}
+
+func newBlobInfo(entry Entry, path string) storage.BlobInfo {
+	return &blobInfo{entry: entry, path: path}
+}
+
+// BlobRef returns the relevant BlobRef for the blob.
+func (info *blobInfo) BlobRef() storage.BlobRef {
+	return info.entry.Ref
+}
+
+// StorageFormatVersion indicates the storage format version used to store the blob.
+func (info *blobInfo) StorageFormatVersion() storage.FormatVersion {
+	return info.entry.FormatVersion
+}
+
+// FullPath returns the path of the pack file containing the blob.
+func (info *blobInfo) FullPath(ctx context.Context) (string, error) {
+	return info.path, nil
+}
+
+// Stat returns the size and the modification time of the blob.
+func (info *blobInfo) Stat(ctx context.Context) (os.FileInfo, error) {
+	return fileInfo{info.entry}, nil
+}
+
+// fileInfo implements os.FileInfo for a blob in a pack file.
+type fileInfo struct {
+	entry Entry
+}
+
+func (info fileInfo) Name() string       { return hex.EncodeToString(info.entry.Ref.Key) }
+func (info fileInfo) Size() int64        { return info.entry.Size }
+func (info fileInfo) Mode() os.FileMode  { return 0600 }
+func (info fileInfo) ModTime() time.Time { return info.entry.ModTime }
+func (info fileInfo) IsDir() bool        { return false }
+func (info fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,205 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/sync2"
+)
+
+// compactBatch is how many entries are moved at once during compaction.
+const compactBatch = 1000
+
+// CompactStats contains the results of a single compaction.
+type CompactStats struct {
+	Packs     int64
+	Moved     int64
+	Reclaimed int64
+}
+
+// Compact rewrites the live blobs, including the trashed ones, of the pack
+// files which have at least the configured fraction of deleted data into the
+// active pack files, and removes the pack files which are no longer used.
+func (store *Store) Compact(ctx context.Context) (stats CompactStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// pack files created after this point are never compacted in this run,
+	// and pack IDs are never reused, so only the active packs have to be skipped.
+	store.mu.Lock()
+	limitID := store.nextID
+	active := make(map[int64]struct{}, len(store.activeID))
+	for packID := range store.activeID {
+		active[packID] = struct{}{}
+	}
+	store.mu.Unlock()
+
+	packIDs, err := store.listPacks()
+	if err != nil {
+		return stats, Error.Wrap(err)
+	}
+	used, err := store.index.SpaceUsedByPack(ctx)
+	if err != nil {
+		return stats, Error.Wrap(err)
+	}
+
+	for _, packID := range packIDs {
+		if _, ok := active[packID]; ok || packID >= limitID {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		info, err := os.Stat(store.packPath(packID))
+		if err != nil {
+			return stats, Error.Wrap(err)
+		}
+
+		live := used[packID]
+		if info.Size() > 0 && live > 0 {
+			dead := float64(info.Size()-live) / float64(info.Size())
+			if dead < store.config.CompactionThreshold {
+				continue
+			}
+		}
+
+		moved, err := store.compactPack(ctx, packID)
+		if err != nil {
+			return stats, err
+		}
+		stats.Moved += moved
+
+		removed, err := store.removePack(ctx, packID)
+		if err != nil {
+			return stats, err
+		}
+		if removed {
+			stats.Packs++
+			stats.Reclaimed += info.Size() - live
+		}
+	}
+
+	return stats, nil
+}
+
+// compactPack moves all the entries of the pack file to the active pack files.
+func (store *Store) compactPack(ctx context.Context, packID int64) (moved int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	file, err := os.Open(store.packPath(packID))
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(file.Close())) }()
+
+	for {
+		entries, err := store.index.ListPack(ctx, packID, compactBatch)
+		if err != nil {
+			return moved, Error.Wrap(err)
+		}
+		if len(entries) == 0 {
+			return moved, nil
+		}
+
+		for _, entry := range entries {
+			if err := ctx.Err(); err != nil {
+				return moved, err
+			}
+
+			data := io.NewSectionReader(file, entry.Offset, entry.Size)
+			err := store.appendBlob(ctx, data, func(newPackID, newOffset int64) error {
+				ok, err := store.index.Move(ctx, entry, newPackID, newOffset)
+				if ok {
+					moved++
+				}
+				return err
+			})
+			if err != nil {
+				return moved, Error.Wrap(err)
+			}
+		}
+	}
+}
+
+// removePack removes the pack file when the index no longer has entries in it.
+func (store *Store) removePack(ctx context.Context, packID int64) (removed bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entries, err := store.index.ListPack(ctx, packID, 1)
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	if len(entries) > 0 {
+		return false, nil
+	}
+
+	err = os.Remove(store.packPath(packID))
+	if err != nil {
+		// the pack file may still be open by a reader on some platforms,
+		// it will be removed by the next compaction.
+		store.log.Warn("unable to remove pack file", zap.Int64("Pack ID", packID), zap.Error(err))
+		return false, nil
+	}
+	return true, nil
+}
+
+// Chore compacts the pack files of a pack store periodically.
+//
+// architecture: Chore
+type Chore struct {
+	log      *zap.Logger
+	store    *Store
+	interval time.Duration
+
+	Loop *sync2.Cycle
+}
+
+// NewChore creates a new pack compaction chore.
+func NewChore(log *zap.Logger, store *Store, interval time.Duration) *Chore {
+	return &Chore{
+		log:      log,
+		store:    store,
+		interval: interval,
+		Loop:     sync2.NewCycle(interval),
+	}
+}
+
+// Run compacts the pack files on every interval.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if chore.interval <= 0 {
+		return nil
+	}
+	return chore.Loop.Run(ctx, func(ctx context.Context) (err error) {
+		defer mon.Task()(&ctx)(&err)
+
+		stats, err := chore.store.Compact(ctx)
+		if err != nil {
+			if errs2.IsCanceled(err) {
+				return err
+			}
+			chore.log.Error("compacting pack files failed", zap.Error(err))
+			return nil
+		}
+		chore.log.Info("compacted pack files",
+			zap.Int64("Packs", stats.Packs),
+			zap.Int64("Moved", stats.Moved),
+			zap.Int64("Reclaimed", stats.Reclaimed))
+		return nil
+	})
+}
+
+// Close stops the chore.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/context2"
+	"storj.io/storj/storage"
+)
+
+// ConvertStats contains the results of a conversion.
+type ConvertStats struct {
+	Namespaces int64
+	Blobs      int64
+	Bytes      int64
+	Trashed    int64
+}
+
+// Convert moves all the blobs of another blob store, such as a filestore, into
+// the pack store, preserving their storage format versions and modification
+// times. Every blob is deleted from the source once it has been copied, so an
+// interrupted conversion can be resumed by running it again.
+//
+// The trashed blobs are restored in the source and converted first, each of
+// them is trashed in the pack store as soon as it's copied, which restarts its
+// time in the trash. When the conversion fails, the restored blobs which
+// aren't converted yet are trashed again in the source.
+func Convert(ctx context.Context, log *zap.Logger, from storage.Blobs, to *Store) (stats ConvertStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	namespaces, err := from.ListNamespaces(ctx)
+	if err != nil {
+		return stats, Error.Wrap(err)
+	}
+
+	for _, namespace := range namespaces {
+		if err := convertNamespace(ctx, from, to, namespace, &stats); err != nil {
+			return stats, err
+		}
+
+		stats.Namespaces++
+		log.Info("converted namespace",
+			zap.Binary("Namespace", namespace),
+			zap.Int64("Blobs", stats.Blobs),
+			zap.Int64("Trashed", stats.Trashed))
+	}
+
+	return stats, nil
+}
+
+// convertNamespace moves the trashed and then the other blobs of the
+// namespace into the pack store.
+func convertNamespace(ctx context.Context, from storage.Blobs, to *Store, namespace []byte, stats *ConvertStats) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	restored, err := from.RestoreTrash(ctx, namespace)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	converted := 0
+	defer func() {
+		if err == nil {
+			return
+		}
+		// the conversion may have been canceled
+		ctx := context2.WithoutCancellation(ctx)
+		for _, key := range restored[converted:] {
+			trashErr := from.Trash(ctx, storage.BlobRef{Namespace: namespace, Key: key})
+			if trashErr != nil && !errs.Is(trashErr, os.ErrNotExist) {
+				err = errs.Combine(err, Error.Wrap(trashErr))
+			}
+		}
+	}()
+
+	for _, key := range restored {
+		ref := storage.BlobRef{Namespace: namespace, Key: key}
+		info, err := from.Stat(ctx, ref)
+		if err != nil {
+			if errs.Is(err, os.ErrNotExist) {
+				converted++
+				continue
+			}
+			return Error.Wrap(err)
+		}
+
+		size, err := convertBlob(ctx, from, to, info)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		// the blob is no longer in the source, it must not be trashed there
+		converted++
+		if err := to.Trash(ctx, ref); err != nil {
+			return err
+		}
+		stats.Blobs++
+		stats.Bytes += size
+		stats.Trashed++
+	}
+
+	err = from.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+		size, err := convertBlob(ctx, from, to, info)
+		if err != nil {
+			return err
+		}
+		stats.Blobs++
+		stats.Bytes += size
+		return nil
+	})
+	return Error.Wrap(err)
+}
+
+// convertBlob copies a single blob into the pack store and deletes it from the source.
+func convertBlob(ctx context.Context, from storage.Blobs, to *Store, info storage.BlobInfo) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stat, err := info.Stat(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	size, err := copyBlob(ctx, from, to, info, stat.ModTime())
+	if err != nil {
+		return 0, err
+	}
+	return size, from.DeleteWithStorageFormat(ctx, info.BlobRef(), info.StorageFormatVersion())
+}
+
+// copyBlob copies a single blob into the pack store.
+func copyBlob(ctx context.Context, from storage.Blobs, to *Store, info storage.BlobInfo, modTime time.Time) (_ int64, err error) {
+	reader, err := from.OpenWithStorageFormat(ctx, info.BlobRef(), info.StorageFormatVersion())
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	writer, err := to.create(ctx, info.BlobRef(), info.StorageFormatVersion())
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := io.Copy(writer, reader)
+	if err != nil {
+		return 0, errs.Combine(err, writer.Cancel(ctx))
+	}
+	return size, writer.commit(ctx, modTime)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/storage"
+)
+
+// Entry is the location of a blob in a pack file.
+type Entry struct {
+	Ref           storage.BlobRef
+	FormatVersion storage.FormatVersion
+
+	PackID int64
+	Offset int64
+	Size   int64
+
+	ModTime   time.Time
+	TrashedAt *time.Time
+}
+
+// ListCursor is the position after which the entries are listed.
+type ListCursor struct {
+	Key           []byte
+	FormatVersion storage.FormatVersion
+}
+
+// Index stores the locations of the blobs in the pack files.
+//
+// architecture: Database
+type Index interface {
+	// Put adds the entry, replacing the entry of the same blob and format version.
+	Put(ctx context.Context, entry Entry) error
+	// Get returns the entry of the blob stored with the format version.
+	Get(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (_ Entry, found bool, err error)
+	// Delete removes the entry of the blob stored with the format version.
+	Delete(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) error
+	// Move updates the location of the entry, unless the entry was changed since it was read.
+	Move(ctx context.Context, entry Entry, packID, offset int64) (moved bool, err error)
+
+	// Trash marks all the format versions of the blob as trashed.
+	Trash(ctx context.Context, ref storage.BlobRef, trashedAt time.Time) error
+	// RestoreTrash unmarks the trashed entries in the namespace and returns their keys.
+	RestoreTrash(ctx context.Context, namespace []byte) (keys [][]byte, err error)
+	// EmptyTrash removes the entries in the namespace trashed before trashedBefore and returns them.
+	EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) ([]Entry, error)
+
+	// List returns the entries which aren't trashed in the namespace after the cursor, ordered by key and format version.
+	List(ctx context.Context, namespace []byte, cursor ListCursor, limit int) ([]Entry, error)
+	// ListPack returns the entries stored in the pack file.
+	ListPack(ctx context.Context, packID int64, limit int) ([]Entry, error)
+	// ListNamespaces returns all the namespaces which have entries.
+	ListNamespaces(ctx context.Context) ([][]byte, error)
+
+	// SpaceUsed returns the size of the entries which aren't trashed in the namespace, or in all namespaces when it's nil.
+	SpaceUsed(ctx context.Context, namespace []byte) (int64, error)
+	// SpaceUsedForTrash returns the size of the trashed entries.
+	SpaceUsedForTrash(ctx context.Context) (int64, error)
+	// SpaceUsedByPack returns the size of the entries, including the trashed ones, in each pack file.
+	SpaceUsedByPack(ctx context.Context) (map[int64]int64, error)
+}
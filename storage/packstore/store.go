@@ -0,0 +1,519 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+)
+
+var (
+	// Error is the default packstore error class.
+	Error = errs.Class("packstore error")
+
+	mon = monkit.Package()
+
+	_ storage.Blobs = (*Store)(nil)
+)
+
+const (
+	packsDir  = "packs"
+	tempDir   = "packs-temp"
+	packExt   = ".pack"
+	walkBatch = 1000
+
+	writeBufferSize = 128 * memory.KiB
+)
+
+// Config is the configuration for the pack store.
+type Config struct {
+	MaxPackSize         memory.Size   `help:"size after which a new pack file is started" default:"1GiB"`
+	ActivePacks         int           `help:"how many pack files the blobs are appended to concurrently" default:"4"`
+	CompactionInterval  time.Duration `help:"how often to compact the pack files, 0 disables it" default:"24h0m0s"`
+	CompactionThreshold float64       `help:"fraction of a pack file which must be deleted before the pack is compacted" default:"0.25"`
+}
+
+// DefaultConfig is the default value for Config.
+var DefaultConfig = Config{
+	MaxPackSize:         memory.GiB,
+	ActivePacks:         4,
+	CompactionInterval:  24 * time.Hour,
+	CompactionThreshold: 0.25,
+}
+
+// Store is a blob store which appends the blobs into large pack files instead
+// of storing every blob in its own file. The location of every blob is kept
+// in the Index.
+//
+// The blobs are appended concurrently to several active pack files, each of
+// which is written by a single blob at a time.
+//
+// architecture: Database
+type Store struct {
+	log    *zap.Logger
+	dir    string
+	index  Index
+	config Config
+
+	// packs are the active pack files which aren't being appended to.
+	packs chan *activePack
+
+	mu       sync.Mutex
+	nextID   int64
+	activeID map[int64]struct{}
+}
+
+// activePack is a pack file which the blobs are appended to.
+type activePack struct {
+	file *os.File
+	id   int64
+	size int64
+}
+
+// New creates a pack store in the specified directory.
+func New(log *zap.Logger, dir string, index Index, config Config) (*Store, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if config.ActivePacks <= 0 {
+		config.ActivePacks = 1
+	}
+
+	store := &Store{
+		log:      log,
+		dir:      dir,
+		index:    index,
+		config:   config,
+		packs:    make(chan *activePack, config.ActivePacks),
+		activeID: make(map[int64]struct{}),
+	}
+	for i := 0; i < config.ActivePacks; i++ {
+		store.packs <- &activePack{id: -1}
+	}
+
+	for _, path := range []string{store.packsDir(), store.tempDir()} {
+		if err := os.MkdirAll(path, 0700); err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	// remove the uploads which were interrupted
+	temps, err := ioutil.ReadDir(store.tempDir())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for _, temp := range temps {
+		if err := os.Remove(filepath.Join(store.tempDir(), temp.Name())); err != nil {
+			log.Warn("unable to remove temporary file", zap.String("Name", temp.Name()), zap.Error(err))
+		}
+	}
+
+	packIDs, err := store.listPacks()
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for _, packID := range packIDs {
+		if packID >= store.nextID {
+			store.nextID = packID + 1
+		}
+	}
+
+	return store, nil
+}
+
+func (store *Store) packsDir() string { return filepath.Join(store.dir, packsDir) }
+func (store *Store) tempDir() string  { return filepath.Join(store.dir, tempDir) }
+
+func (store *Store) packPath(packID int64) string {
+	return filepath.Join(store.packsDir(), fmt.Sprintf("%016x%s", packID, packExt))
+}
+
+// listPacks returns the IDs of all the pack files on disk.
+func (store *Store) listPacks() ([]int64, error) {
+	infos, err := ioutil.ReadDir(store.packsDir())
+	if err != nil {
+		return nil, err
+	}
+	var packIDs []int64
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, packExt) {
+			continue
+		}
+		packID, err := strconv.ParseInt(strings.TrimSuffix(name, packExt), 16, 64)
+		if err != nil {
+			store.log.Warn("unexpected file in packs directory", zap.String("Name", name))
+			continue
+		}
+		packIDs = append(packIDs, packID)
+	}
+	return packIDs, nil
+}
+
+// Close closes the active pack files, once the blobs which are being appended
+// to them are committed.
+func (store *Store) Close() error {
+	var group errs.Group
+	packs := make([]*activePack, 0, cap(store.packs))
+	for len(packs) < cap(store.packs) {
+		pack := <-store.packs
+		group.Add(store.closePack(pack))
+		packs = append(packs, pack)
+	}
+	for _, pack := range packs {
+		store.packs <- pack
+	}
+	return Error.Wrap(group.Err())
+}
+
+// appendBlob appends the data to one of the active pack files and calls commit
+// with its location, while no other blob can be appended to the pack file.
+// Holding the pack file while committing ensures that it's never compacted
+// while a blob, which was appended to it, is not yet in the index.
+func (store *Store) appendBlob(ctx context.Context, data io.Reader, commit func(packID, offset int64) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var pack *activePack
+	select {
+	case pack = <-store.packs:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { store.packs <- pack }()
+
+	if err := store.rotate(pack); err != nil {
+		return err
+	}
+
+	offset := pack.size
+	n, err := io.Copy(pack.file, data)
+	pack.size += n
+	if err != nil {
+		return err
+	}
+	if err := pack.file.Sync(); err != nil {
+		return err
+	}
+
+	return commit(pack.id, offset)
+}
+
+// rotate starts a new pack file when the pack doesn't have one or when it is full.
+func (store *Store) rotate(pack *activePack) error {
+	if pack.file != nil && pack.size < store.config.MaxPackSize.Int64() {
+		return nil
+	}
+
+	if err := store.closePack(pack); err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	packID := store.nextID
+	store.nextID++
+	store.activeID[packID] = struct{}{}
+	store.mu.Unlock()
+
+	file, err := os.OpenFile(store.packPath(packID), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		store.mu.Lock()
+		delete(store.activeID, packID)
+		store.mu.Unlock()
+		return err
+	}
+
+	pack.file = file
+	pack.id = packID
+	pack.size = 0
+	return nil
+}
+
+// closePack closes the file of the pack, which can be compacted afterwards.
+func (store *Store) closePack(pack *activePack) error {
+	if pack.file == nil {
+		return nil
+	}
+	err := pack.file.Close()
+
+	store.mu.Lock()
+	delete(store.activeID, pack.id)
+	store.mu.Unlock()
+
+	pack.file = nil
+	pack.id = -1
+	pack.size = 0
+	return err
+}
+
+// Create creates a new blob that can be written.
+func (store *Store) Create(ctx context.Context, ref storage.BlobRef, size int64) (_ storage.BlobWriter, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return store.create(ctx, ref, filestore.MaxFormatVersionSupported)
+}
+
+// TestCreateV0 creates a new V0 blob that can be written. This is ONLY appropriate in test situations.
+func (store *Store) TestCreateV0(ctx context.Context, ref storage.BlobRef) (_ storage.BlobWriter, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return store.create(ctx, ref, filestore.FormatV0)
+}
+
+func (store *Store) create(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (_ *blobWriter, err error) {
+	if !ref.IsValid() {
+		return nil, storage.ErrInvalidBlobRef.New("")
+	}
+	file, err := ioutil.TempFile(store.tempDir(), "blob-*.partial")
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return newBlobWriter(ref, store, formatVersion, file), nil
+}
+
+// commit appends the content of the temporary file to a pack file and adds it to the index.
+func (store *Store) commit(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion, file *os.File, modTime time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return store.appendBlob(ctx, io.LimitReader(file, size), func(packID, offset int64) error {
+		return store.index.Put(ctx, Entry{
+			Ref:           ref,
+			FormatVersion: formatVersion,
+			PackID:        packID,
+			Offset:        offset,
+			Size:          size,
+			ModTime:       modTime.UTC(),
+		})
+	})
+}
+
+// lookup returns the entry of the blob, which isn't trashed, stored with the format version.
+func (store *Store) lookup(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (_ Entry, err error) {
+	if !ref.IsValid() {
+		return Entry{}, storage.ErrInvalidBlobRef.New("")
+	}
+	entry, found, err := store.index.Get(ctx, ref, formatVersion)
+	if err != nil {
+		return Entry{}, Error.Wrap(err)
+	}
+	if !found || entry.TrashedAt != nil {
+		return Entry{}, os.ErrNotExist
+	}
+	return entry, nil
+}
+
+// Open opens a reader with the specified namespace and key.
+func (store *Store) Open(ctx context.Context, ref storage.BlobRef) (_ storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for formatVersion := filestore.MaxFormatVersionSupported; formatVersion >= filestore.MinFormatVersionSupported; formatVersion-- {
+		reader, err := store.OpenWithStorageFormat(ctx, ref, formatVersion)
+		if os.IsNotExist(err) {
+			continue
+		}
+		return reader, err
+	}
+	return nil, os.ErrNotExist
+}
+
+// OpenWithStorageFormat opens a reader for the already-located blob, avoiding the potential need
+// to check multiple storage formats to find the blob.
+func (store *Store) OpenWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (_ storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entry, err := store.lookup(ctx, ref, formatVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(store.packPath(entry.PackID))
+	if os.IsNotExist(err) {
+		// the pack may have been compacted after the lookup
+		entry, err = store.lookup(ctx, ref, formatVersion)
+		if err != nil {
+			return nil, err
+		}
+		file, err = os.Open(store.packPath(entry.PackID))
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			store.log.Error("pack file of a blob is missing", zap.Int64("Pack ID", entry.PackID))
+		}
+		return nil, Error.Wrap(err)
+	}
+	return newBlobReader(file, entry), nil
+}
+
+// Stat looks up the metadata of the blob.
+func (store *Store) Stat(ctx context.Context, ref storage.BlobRef) (_ storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for formatVersion := filestore.MaxFormatVersionSupported; formatVersion >= filestore.MinFormatVersionSupported; formatVersion-- {
+		info, err := store.StatWithStorageFormat(ctx, ref, formatVersion)
+		if os.IsNotExist(err) {
+			continue
+		}
+		return info, err
+	}
+	return nil, os.ErrNotExist
+}
+
+// StatWithStorageFormat looks up the metadata of the blob stored with the given storage format version.
+func (store *Store) StatWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (_ storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entry, err := store.lookup(ctx, ref, formatVersion)
+	if err != nil {
+		return nil, err
+	}
+	return newBlobInfo(entry, store.packPath(entry.PackID)), nil
+}
+
+// Delete deletes the blob with the namespace and key in all the storage formats.
+func (store *Store) Delete(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var group errs.Group
+	for formatVersion := filestore.MinFormatVersionSupported; formatVersion <= filestore.MaxFormatVersionSupported; formatVersion++ {
+		group.Add(store.DeleteWithStorageFormat(ctx, ref, formatVersion))
+	}
+	return group.Err()
+}
+
+// DeleteWithStorageFormat deletes the blob stored with the storage format. The
+// space is reclaimed once the pack file is compacted.
+func (store *Store) DeleteWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if !ref.IsValid() {
+		return storage.ErrInvalidBlobRef.New("")
+	}
+	return Error.Wrap(store.index.Delete(ctx, ref, formatVersion))
+}
+
+// Trash marks the blob for pending deletion.
+func (store *Store) Trash(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if !ref.IsValid() {
+		return storage.ErrInvalidBlobRef.New("")
+	}
+	return Error.Wrap(store.index.Trash(ctx, ref, time.Now().UTC()))
+}
+
+// RestoreTrash restores all the trashed blobs in the namespace and returns the keys restored.
+func (store *Store) RestoreTrash(ctx context.Context, namespace []byte) (_ [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	keys, err := store.index.RestoreTrash(ctx, namespace)
+	return keys, Error.Wrap(err)
+}
+
+// EmptyTrash removes all the blobs in the namespace which were trashed before
+// trashedBefore and returns the total bytes emptied and the keys deleted.
+func (store *Store) EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	entries, err := store.index.EmptyTrash(ctx, namespace, trashedBefore.UTC())
+	if err != nil {
+		return 0, nil, Error.Wrap(err)
+	}
+	for _, entry := range entries {
+		bytesEmptied += entry.Size
+		keys = append(keys, entry.Ref.Key)
+	}
+	return bytesEmptied, keys, nil
+}
+
+// FreeSpace returns how much space is left on the disk of the pack store.
+func (store *Store) FreeSpace() (int64, error) {
+	info, err := filestore.DiskInfoFromPath(store.dir)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	return info.AvailableSpace, nil
+}
+
+// SpaceUsedForTrash returns the total size of the trashed blobs.
+func (store *Store) SpaceUsedForTrash(ctx context.Context) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	total, err := store.index.SpaceUsedForTrash(ctx)
+	return total, Error.Wrap(err)
+}
+
+// SpaceUsedForBlobs adds up the size of the blobs in all namespaces.
+func (store *Store) SpaceUsedForBlobs(ctx context.Context) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	total, err := store.index.SpaceUsed(ctx, nil)
+	return total, Error.Wrap(err)
+}
+
+// SpaceUsedForBlobsInNamespace adds up the size of the blobs in the namespace.
+func (store *Store) SpaceUsedForBlobsInNamespace(ctx context.Context, namespace []byte) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	total, err := store.index.SpaceUsed(ctx, namespace)
+	return total, Error.Wrap(err)
+}
+
+// ListNamespaces returns all the namespaces which have blobs.
+func (store *Store) ListNamespaces(ctx context.Context) (_ [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	namespaces, err := store.index.ListNamespaces(ctx)
+	return namespaces, Error.Wrap(err)
+}
+
+// WalkNamespace executes walkFunc for each blob, which isn't trashed, in the
+// namespace. If walkFunc returns a non-nil error, WalkNamespace will stop
+// iterating and return the error immediately. The ctx parameter is intended
+// specifically to allow canceling iteration early.
+func (store *Store) WalkNamespace(ctx context.Context, namespace []byte, walkFunc func(storage.BlobInfo) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var cursor ListCursor
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entries, err := store.index.List(ctx, namespace, cursor, walkBatch)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		for _, entry := range entries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := walkFunc(newBlobInfo(entry, store.packPath(entry.PackID))); err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < walkBatch {
+			return nil
+		}
+		last := entries[len(entries)-1]
+		cursor = ListCursor{Key: last.Ref.Key, FormatVersion: last.FormatVersion}
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/packstore"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func writeBlob(ctx *testcontext.Context, t *testing.T, blobs storage.Blobs, ref storage.BlobRef, data []byte) {
+	writer, err := blobs.Create(ctx, ref, int64(len(data)))
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit(ctx))
+}
+
+func readBlob(ctx *testcontext.Context, t *testing.T, blobs storage.Blobs, ref storage.BlobRef) []byte {
+	reader, err := blobs.Open(ctx, ref)
+	require.NoError(t, err)
+	defer ctx.Check(reader.Close)
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	return data
+}
+
+func TestStore(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		store, err := packstore.New(zaptest.NewLogger(t), ctx.Dir("packs"), db.PackIndex(), packstore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(store.Close)
+
+		namespace := testrand.Bytes(32)
+		refs := make([]storage.BlobRef, 5)
+		contents := make([][]byte, len(refs))
+		for i := range refs {
+			refs[i] = storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+			contents[i] = testrand.BytesInt(testrand.Intn(10*memory.KiB.Int()) + 1)
+			writeBlob(ctx, t, store, refs[i], contents[i])
+		}
+
+		// cancelled blobs are not stored
+		writer, err := store.Create(ctx, storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(testrand.Bytes(memory.KiB))
+		require.NoError(t, err)
+		require.NoError(t, writer.Cancel(ctx))
+
+		var total int64
+		for i, ref := range refs {
+			require.Equal(t, contents[i], readBlob(ctx, t, store, ref))
+
+			info, err := store.Stat(ctx, ref)
+			require.NoError(t, err)
+			require.Equal(t, filestore.FormatV1, info.StorageFormatVersion())
+			stat, err := info.Stat(ctx)
+			require.NoError(t, err)
+			require.EqualValues(t, len(contents[i]), stat.Size())
+			total += stat.Size()
+
+			// reading at an offset stays within the blob
+			reader, err := store.Open(ctx, ref)
+			require.NoError(t, err)
+			size, err := reader.Size()
+			require.NoError(t, err)
+			require.EqualValues(t, len(contents[i]), size)
+			buf := make([]byte, 2)
+			_, err = reader.ReadAt(buf, size-1)
+			require.Error(t, err)
+			require.NoError(t, reader.Close())
+		}
+
+		used, err := store.SpaceUsedForBlobs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, total, used)
+		used, err = store.SpaceUsedForBlobsInNamespace(ctx, namespace)
+		require.NoError(t, err)
+		require.Equal(t, total, used)
+
+		namespaces, err := store.ListNamespaces(ctx)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{namespace}, namespaces)
+
+		var walked [][]byte
+		require.NoError(t, store.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+			walked = append(walked, info.BlobRef().Key)
+			return nil
+		}))
+		require.Len(t, walked, len(refs))
+
+		// rewriting a blob replaces it
+		contents[0] = testrand.Bytes(memory.KiB)
+		writeBlob(ctx, t, store, refs[0], contents[0])
+		require.Equal(t, contents[0], readBlob(ctx, t, store, refs[0]))
+
+		require.NoError(t, store.Delete(ctx, refs[1]))
+		_, err = store.Open(ctx, refs[1])
+		require.True(t, os.IsNotExist(err))
+		_, err = store.Stat(ctx, refs[1])
+		require.True(t, os.IsNotExist(err))
+
+		// deleting a missing blob isn't an error
+		require.NoError(t, store.Delete(ctx, refs[1]))
+	})
+}
+
+func TestStoreFormatVersions(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		store, err := packstore.New(zaptest.NewLogger(t), ctx.Dir("packs"), db.PackIndex(), packstore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(store.Close)
+
+		ref := storage.BlobRef{Namespace: testrand.Bytes(32), Key: testrand.Bytes(32)}
+		v0Data := testrand.Bytes(memory.KiB)
+
+		writer, err := store.TestCreateV0(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, filestore.FormatV0, writer.StorageFormatVersion())
+		_, err = writer.Write(v0Data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+
+		reader, err := store.Open(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, filestore.FormatV0, reader.StorageFormatVersion())
+		require.NoError(t, reader.Close())
+
+		_, err = store.OpenWithStorageFormat(ctx, ref, filestore.FormatV1)
+		require.True(t, os.IsNotExist(err))
+
+		// the newest format version is preferred
+		v1Data := testrand.Bytes(memory.KiB)
+		writeBlob(ctx, t, store, ref, v1Data)
+		require.Equal(t, v1Data, readBlob(ctx, t, store, ref))
+
+		info, err := store.StatWithStorageFormat(ctx, ref, filestore.FormatV0)
+		require.NoError(t, err)
+		require.Equal(t, filestore.FormatV0, info.StorageFormatVersion())
+
+		require.NoError(t, store.DeleteWithStorageFormat(ctx, ref, filestore.FormatV1))
+		require.Equal(t, v0Data, readBlob(ctx, t, store, ref))
+
+		require.NoError(t, store.Delete(ctx, ref))
+		_, err = store.Open(ctx, ref)
+		require.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestStoreTrash(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		store, err := packstore.New(zaptest.NewLogger(t), ctx.Dir("packs"), db.PackIndex(), packstore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(store.Close)
+
+		namespace := testrand.Bytes(32)
+		keep := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		trash := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		data := testrand.Bytes(memory.KiB)
+		writeBlob(ctx, t, store, keep, data)
+		writeBlob(ctx, t, store, trash, data)
+
+		require.NoError(t, store.Trash(ctx, trash))
+		_, err = store.Open(ctx, trash)
+		require.True(t, os.IsNotExist(err))
+
+		used, err := store.SpaceUsedForTrash(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, len(data), used)
+		used, err = store.SpaceUsedForBlobs(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, len(data), used)
+
+		// trashed blobs are not walked
+		count := 0
+		require.NoError(t, store.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+			count++
+			assert.Equal(t, keep.Key, info.BlobRef().Key)
+			return nil
+		}))
+		require.Equal(t, 1, count)
+
+		restored, err := store.RestoreTrash(ctx, namespace)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{trash.Key}, restored)
+		require.Equal(t, data, readBlob(ctx, t, store, trash))
+
+		require.NoError(t, store.Trash(ctx, trash))
+
+		// blobs trashed after trashedBefore are kept
+		emptied, keys, err := store.EmptyTrash(ctx, namespace, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, emptied)
+		require.Empty(t, keys)
+
+		emptied, keys, err = store.EmptyTrash(ctx, namespace, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.EqualValues(t, len(data), emptied)
+		require.Equal(t, [][]byte{trash.Key}, keys)
+
+		restored, err = store.RestoreTrash(ctx, namespace)
+		require.NoError(t, err)
+		require.Empty(t, restored)
+		_, err = store.Open(ctx, trash)
+		require.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestStoreCompact(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		config := packstore.Config{
+			MaxPackSize:         4 * memory.KiB,
+			CompactionThreshold: 0.25,
+		}
+		dir := ctx.Dir("packs")
+		store, err := packstore.New(zaptest.NewLogger(t), dir, db.PackIndex(), config)
+		require.NoError(t, err)
+		defer ctx.Check(store.Close)
+
+		namespace := testrand.Bytes(32)
+		refs := make([]storage.BlobRef, 12)
+		contents := make(map[string][]byte)
+		for i := range refs {
+			refs[i] = storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+			data := testrand.Bytes(memory.KiB)
+			contents[string(refs[i].Key)] = data
+			writeBlob(ctx, t, store, refs[i], data)
+		}
+
+		packs, err := ioutil.ReadDir(dir + "/packs")
+		require.NoError(t, err)
+		require.Len(t, packs, 3)
+
+		// delete half of the blobs and trash one, which must survive the compaction
+		for i := 0; i < len(refs); i += 2 {
+			require.NoError(t, store.Delete(ctx, refs[i]))
+			delete(contents, string(refs[i].Key))
+		}
+		require.NoError(t, store.Trash(ctx, refs[1]))
+
+		used, err := store.SpaceUsedForBlobs(ctx)
+		require.NoError(t, err)
+
+		stats, err := store.Compact(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, stats.Packs)
+		require.EqualValues(t, 4, stats.Moved)
+		require.EqualValues(t, 4*memory.KiB, stats.Reclaimed)
+
+		packs, err = ioutil.ReadDir(dir + "/packs")
+		require.NoError(t, err)
+		require.Len(t, packs, 2)
+
+		usedAfter, err := store.SpaceUsedForBlobs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, used, usedAfter)
+
+		_, err = store.RestoreTrash(ctx, namespace)
+		require.NoError(t, err)
+		for key, data := range contents {
+			require.Equal(t, data, readBlob(ctx, t, store, storage.BlobRef{Namespace: namespace, Key: []byte(key)}))
+		}
+
+		// the store continues with new packs after reopening
+		require.NoError(t, store.Close())
+		store, err = packstore.New(zaptest.NewLogger(t), dir, db.PackIndex(), config)
+		require.NoError(t, err)
+		ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		data := testrand.Bytes(memory.KiB)
+		writeBlob(ctx, t, store, ref, data)
+		require.Equal(t, data, readBlob(ctx, t, store, ref))
+		require.NoError(t, store.Close())
+	})
+}
+
+func TestConvert(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		log := zaptest.NewLogger(t)
+
+		files, err := filestore.NewAt(log, ctx.Dir("store"), filestore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(files.Close)
+
+		namespace := testrand.Bytes(32)
+		live := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		trashed := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		v0 := storage.BlobRef{Namespace: testrand.Bytes(32), Key: testrand.Bytes(32)}
+		data := testrand.Bytes(memory.KiB)
+
+		writeBlob(ctx, t, files, live, data)
+		writeBlob(ctx, t, files, trashed, data)
+		require.NoError(t, files.Trash(ctx, trashed))
+
+		writer, err := files.(interface {
+			TestCreateV0(ctx context.Context, ref storage.BlobRef) (storage.BlobWriter, error)
+		}).TestCreateV0(ctx, v0)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+
+		info, err := files.Stat(ctx, live)
+		require.NoError(t, err)
+		stat, err := info.Stat(ctx)
+		require.NoError(t, err)
+		modTime := stat.ModTime()
+
+		packs, err := packstore.New(log, ctx.Dir("store"), db.PackIndex(), packstore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(packs.Close)
+
+		stats, err := packstore.Convert(ctx, log, files, packs)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, stats.Namespaces)
+		require.EqualValues(t, 3, stats.Blobs)
+		require.EqualValues(t, 3*len(data), stats.Bytes)
+		require.EqualValues(t, 1, stats.Trashed)
+
+		// the source is empty
+		for _, ref := range []storage.BlobRef{live, trashed, v0} {
+			_, err := files.Stat(ctx, ref)
+			require.True(t, errs.Is(err, os.ErrNotExist))
+		}
+
+		require.Equal(t, data, readBlob(ctx, t, packs, live))
+		info, err = packs.Stat(ctx, live)
+		require.NoError(t, err)
+		stat, err = info.Stat(ctx)
+		require.NoError(t, err)
+		require.True(t, modTime.Equal(stat.ModTime()))
+
+		info, err = packs.Stat(ctx, v0)
+		require.NoError(t, err)
+		require.Equal(t, filestore.FormatV0, info.StorageFormatVersion())
+
+		_, err = packs.Open(ctx, trashed)
+		require.True(t, os.IsNotExist(err))
+		restored, err := packs.RestoreTrash(ctx, namespace)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{trashed.Key}, restored)
+
+		// converting again does nothing
+		stats, err = packstore.Convert(ctx, log, files, packs)
+		require.NoError(t, err)
+		require.Zero(t, stats.Blobs)
+	})
+}
+
+func TestStoreConcurrentWrites(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		dir := ctx.Dir("packs")
+		store, err := packstore.New(zaptest.NewLogger(t), dir, db.PackIndex(), packstore.Config{
+			MaxPackSize: 16 * memory.KiB,
+			ActivePacks: 4,
+		})
+		require.NoError(t, err)
+		defer ctx.Check(store.Close)
+
+		namespace := testrand.Bytes(32)
+		refs := make([]storage.BlobRef, 32)
+		contents := make([][]byte, len(refs))
+		for i := range refs {
+			refs[i] = storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+			contents[i] = testrand.Bytes(memory.KiB)
+		}
+
+		var group errgroup.Group
+		for i := range refs {
+			i := i
+			group.Go(func() error {
+				writer, err := store.Create(ctx, refs[i], int64(len(contents[i])))
+				if err != nil {
+					return err
+				}
+				if _, err := writer.Write(contents[i]); err != nil {
+					return errs.Combine(err, writer.Cancel(ctx))
+				}
+				return writer.Commit(ctx)
+			})
+		}
+		require.NoError(t, group.Wait())
+
+		for i, ref := range refs {
+			require.Equal(t, contents[i], readBlob(ctx, t, store, ref))
+		}
+
+		packs, err := ioutil.ReadDir(dir + "/packs")
+		require.NoError(t, err)
+		require.True(t, len(packs) >= 2)
+	})
+}
+
+func TestConvertFailureTrashesAgain(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		log := zaptest.NewLogger(t)
+
+		files, err := filestore.NewAt(log, ctx.Dir("store"), filestore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(files.Close)
+
+		namespace := testrand.Bytes(32)
+		trashed := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		writeBlob(ctx, t, files, trashed, testrand.Bytes(memory.KiB))
+		require.NoError(t, files.Trash(ctx, trashed))
+
+		dir := ctx.Dir("packs")
+		packs, err := packstore.New(log, dir, db.PackIndex(), packstore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(packs.Close)
+
+		// no pack file can be created
+		require.NoError(t, os.RemoveAll(dir+"/packs"))
+		require.NoError(t, ioutil.WriteFile(dir+"/packs", nil, 0644))
+
+		_, err = packstore.Convert(ctx, log, files, packs)
+		require.Error(t, err)
+
+		// the blob is still trashed in the source
+		_, err = files.Stat(ctx, trashed)
+		require.True(t, errs.Is(err, os.ErrNotExist))
+		restored, err := files.RestoreTrash(ctx, namespace)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{trashed.Key}, restored)
+	})
+}
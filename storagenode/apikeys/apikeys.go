@@ -0,0 +1,92 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package apikeys
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+)
+
+var (
+	// Error is the default error class for the console api keys.
+	Error = errs.Class("api keys error")
+
+	// ErrUnauthorized is returned when the api key is unknown or its scope isn't sufficient.
+	ErrUnauthorized = errs.Class("unauthorized")
+)
+
+// DB stores the hashes of the console api keys.
+//
+// architecture: Database
+type DB interface {
+	// Store stores the api key.
+	Store(ctx context.Context, key APIKey) error
+	// GetByHash returns the api key with the hash.
+	GetByHash(ctx context.Context, hash []byte) (APIKey, error)
+	// List returns all the api keys, oldest first.
+	List(ctx context.Context) ([]APIKey, error)
+	// Revoke removes the api key.
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+// APIKey is an issued console api key. Only the hash of the secret is kept.
+type APIKey struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Scope     Scope     `json:"scope"`
+	Hash      []byte    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Scope defines what an api key is allowed to do.
+type Scope int
+
+const (
+	// ReadOnly allows reading the dashboard data.
+	ReadOnly Scope = 1
+	// Admin additionally allows the endpoints which change the node state.
+	Admin Scope = 2
+)
+
+// Allows returns whether the scope includes the required scope.
+func (scope Scope) Allows(required Scope) bool {
+	return scope >= required
+}
+
+// String implements pflag.Value.
+func (scope Scope) String() string {
+	switch scope {
+	case ReadOnly:
+		return "read-only"
+	case Admin:
+		return "admin"
+	default:
+		return "invalid"
+	}
+}
+
+// Set implements pflag.Value.
+func (scope *Scope) Set(s string) error {
+	switch s {
+	case "read-only":
+		*scope = ReadOnly
+	case "admin":
+		*scope = Admin
+	default:
+		return Error.New("invalid scope %q, expected read-only or admin", s)
+	}
+	return nil
+}
+
+// Type implements pflag.Value.
+func (Scope) Type() string { return "apikeys.Scope" }
+
+// MarshalText implements encoding.TextMarshaler.
+func (scope Scope) MarshalText() ([]byte, error) {
+	return []byte(scope.String()), nil
+}
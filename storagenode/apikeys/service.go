@@ -0,0 +1,115 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"go.uber.org/zap"
+
+	"storj.io/common/uuid"
+)
+
+var mon = monkit.Package()
+
+// secretSize is the number of random bytes of an api key secret.
+const secretSize = 32
+
+// Service issues and checks the console api keys.
+//
+// architecture: Service
+type Service struct {
+	log *zap.Logger
+	db  DB
+}
+
+// NewService creates a new api keys service.
+func NewService(log *zap.Logger, db DB) *Service {
+	return &Service{
+		log: log,
+		db:  db,
+	}
+}
+
+// Issue creates a new api key with the scope and returns its secret, which
+// can't be retrieved later.
+func (service *Service) Issue(ctx context.Context, name string, scope Scope) (_ APIKey, secret string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if scope != ReadOnly && scope != Admin {
+		return APIKey{}, "", Error.New("invalid scope %d", scope)
+	}
+
+	id, err := uuid.New()
+	if err != nil {
+		return APIKey{}, "", Error.Wrap(err)
+	}
+
+	var random [secretSize]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return APIKey{}, "", Error.Wrap(err)
+	}
+	secret = hex.EncodeToString(random[:])
+
+	key := APIKey{
+		ID:        id,
+		Name:      name,
+		Scope:     scope,
+		Hash:      hash(secret),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := service.db.Store(ctx, key); err != nil {
+		return APIKey{}, "", Error.Wrap(err)
+	}
+	return key, secret, nil
+}
+
+// Check returns the api key of the secret, when its scope includes the required scope.
+func (service *Service) Check(ctx context.Context, secret string, required Scope) (_ APIKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if secret == "" {
+		return APIKey{}, ErrUnauthorized.New("missing api key")
+	}
+
+	key, err := service.db.GetByHash(ctx, hash(secret))
+	if err != nil {
+		if ErrUnauthorized.Has(err) {
+			return APIKey{}, err
+		}
+		return APIKey{}, Error.Wrap(err)
+	}
+
+	if !key.Scope.Allows(required) {
+		return APIKey{}, ErrUnauthorized.New("api key %q has the %s scope, %s is required", key.Name, key.Scope, required)
+	}
+	return key, nil
+}
+
+// List returns all the api keys.
+func (service *Service) List(ctx context.Context) (_ []APIKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keys, err := service.db.List(ctx)
+	return keys, Error.Wrap(err)
+}
+
+// Revoke removes the api key.
+func (service *Service) Revoke(ctx context.Context, id uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return Error.Wrap(service.db.Revoke(ctx, id))
+}
+
+// hash returns the hash of the secret which is stored. The secrets are
+// random, so they don't need a slow password hash.
+func hash(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package apikeys_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/apikeys"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestService(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		service := apikeys.NewService(zaptest.NewLogger(t), db.APIKeys())
+
+		_, _, err := service.Issue(ctx, "invalid", apikeys.Scope(0))
+		require.Error(t, err)
+
+		readOnly, readOnlySecret, err := service.Issue(ctx, "aggregator", apikeys.ReadOnly)
+		require.NoError(t, err)
+		require.Equal(t, apikeys.ReadOnly, readOnly.Scope)
+		require.NotEmpty(t, readOnlySecret)
+		require.NotContains(t, string(readOnly.Hash), readOnlySecret)
+
+		admin, adminSecret, err := service.Issue(ctx, "admin", apikeys.Admin)
+		require.NoError(t, err)
+		require.NotEqual(t, readOnlySecret, adminSecret)
+
+		key, err := service.Check(ctx, readOnlySecret, apikeys.ReadOnly)
+		require.NoError(t, err)
+		require.Equal(t, readOnly.ID, key.ID)
+		require.Equal(t, "aggregator", key.Name)
+
+		_, err = service.Check(ctx, readOnlySecret, apikeys.Admin)
+		require.True(t, apikeys.ErrUnauthorized.Has(err))
+
+		key, err = service.Check(ctx, adminSecret, apikeys.ReadOnly)
+		require.NoError(t, err)
+		require.Equal(t, admin.ID, key.ID)
+		_, err = service.Check(ctx, adminSecret, apikeys.Admin)
+		require.NoError(t, err)
+
+		_, err = service.Check(ctx, "", apikeys.ReadOnly)
+		require.True(t, apikeys.ErrUnauthorized.Has(err))
+		_, err = service.Check(ctx, "unknown", apikeys.ReadOnly)
+		require.True(t, apikeys.ErrUnauthorized.Has(err))
+
+		keys, err := service.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		require.NoError(t, service.Revoke(ctx, admin.ID))
+		require.Error(t, service.Revoke(ctx, admin.ID))
+		_, err = service.Check(ctx, adminSecret, apikeys.ReadOnly)
+		require.True(t, apikeys.ErrUnauthorized.Has(err))
+
+		keys, err = service.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, readOnly.ID, keys[0].ID)
+	})
+}
+
+func TestScope(t *testing.T) {
+	var scope apikeys.Scope
+	require.NoError(t, scope.Set("admin"))
+	require.Equal(t, apikeys.Admin, scope)
+	require.Equal(t, "admin", scope.String())
+	require.NoError(t, scope.Set("read-only"))
+	require.Equal(t, apikeys.ReadOnly, scope)
+	require.Error(t, scope.Set("root"))
+
+	require.True(t, apikeys.Admin.Allows(apikeys.ReadOnly))
+	require.True(t, apikeys.ReadOnly.Allows(apikeys.ReadOnly))
+	require.False(t, apikeys.ReadOnly.Allows(apikeys.Admin))
+}
@@ -0,0 +1,275 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bandwidth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/memory"
+	"storj.io/storj/private/date"
+)
+
+// ErrLimits is the default error class for bandwidth limits.
+var ErrLimits = errs.Class("bandwidth limits")
+
+// minimumBurst is the smallest burst of the token buckets, so that small rates
+// don't split every chunk into tiny waits.
+const minimumBurst = 32 * memory.KiB
+
+// ScheduleEntry limits the transfer rate between two times of the day.
+type ScheduleEntry struct {
+	// Start and End are the offsets from midnight, End may be before Start
+	// when the entry spans midnight.
+	Start time.Duration
+	End   time.Duration
+	Rate  memory.Size
+}
+
+// Contains returns whether the time of the day is within the entry.
+func (entry ScheduleEntry) Contains(t time.Time) bool {
+	hour, minute, second := t.Clock()
+	offset := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+	if entry.Start <= entry.End {
+		return entry.Start <= offset && offset < entry.End
+	}
+	return entry.Start <= offset || offset < entry.End
+}
+
+// Schedule is a list of time of the day rate limits, using the local time of the node.
+type Schedule []ScheduleEntry
+
+// Rate returns the rate limit at the time, 0 means unlimited. The first
+// matching entry is used.
+func (schedule Schedule) Rate(t time.Time) memory.Size {
+	for _, entry := range schedule {
+		if entry.Contains(t) {
+			return entry.Rate
+		}
+	}
+	return 0
+}
+
+// String returns the schedule as a comma-separated list of HH:MM-HH:MM=rate.
+func (schedule Schedule) String() string {
+	var entries []string
+	for _, entry := range schedule {
+		entries = append(entries, fmt.Sprintf("%s-%s=%s",
+			formatTimeOfDay(entry.Start), formatTimeOfDay(entry.End), entry.Rate.String()))
+	}
+	return strings.Join(entries, ",")
+}
+
+// Set parses a comma-separated list of HH:MM-HH:MM=rate.
+func (schedule *Schedule) Set(value string) error {
+	var parsed Schedule
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		eq := strings.LastIndex(item, "=")
+		if eq < 0 {
+			return ErrLimits.New("invalid schedule entry %q, expected HH:MM-HH:MM=rate", item)
+		}
+		times := strings.SplitN(item[:eq], "-", 2)
+		if len(times) != 2 {
+			return ErrLimits.New("invalid schedule entry %q, expected HH:MM-HH:MM=rate", item)
+		}
+
+		var entry ScheduleEntry
+		var err error
+		if entry.Start, err = parseTimeOfDay(times[0]); err != nil {
+			return err
+		}
+		if entry.End, err = parseTimeOfDay(times[1]); err != nil {
+			return err
+		}
+		if err := entry.Rate.Set(item[eq+1:]); err != nil {
+			return ErrLimits.Wrap(err)
+		}
+		if entry.Rate <= 0 {
+			return ErrLimits.New("invalid schedule entry %q, rate must be positive", item)
+		}
+		parsed = append(parsed, entry)
+	}
+	*schedule = parsed
+	return nil
+}
+
+// Type implements pflag.Value.
+func (Schedule) Type() string { return "bandwidth.Schedule" }
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		// 24:00 is allowed as the end of the day
+		if strings.TrimSpace(value) == "24:00" {
+			return 24 * time.Hour, nil
+		}
+		return 0, ErrLimits.New("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func formatTimeOfDay(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
+
+// LimitsConfig defines the bandwidth limits of a storage node.
+type LimitsConfig struct {
+	IngressSchedule Schedule      `help:"ingress rate limits by time of the day, as a comma-separated list of HH:MM-HH:MM=rate per second, unlimited outside of them" default:""`
+	EgressSchedule  Schedule      `help:"egress rate limits by time of the day, as a comma-separated list of HH:MM-HH:MM=rate per second, unlimited outside of them" default:""`
+	MonthlyIngress  memory.Size   `help:"how much ingress is allowed in a calendar month before uploads are rejected, 0 is unlimited" default:"0B"`
+	MonthlyEgress   memory.Size   `help:"how much egress the node advertises for a calendar month, 0 is unlimited" default:"0B"`
+	UsageCacheTTL   time.Duration `help:"how long the bandwidth used this month is cached when checking the budget" default:"1m0s" hidden:"true"`
+}
+
+// Budget is the monthly bandwidth budget of the node. The limits are 0 when
+// they are unlimited.
+type Budget struct {
+	IngressLimit     int64 `json:"ingressLimit"`
+	IngressUsed      int64 `json:"ingressUsed"`
+	IngressRemaining int64 `json:"ingressRemaining"`
+	EgressLimit      int64 `json:"egressLimit"`
+	EgressUsed       int64 `json:"egressUsed"`
+	EgressRemaining  int64 `json:"egressRemaining"`
+}
+
+// IngressExhausted returns whether the ingress budget has been used up.
+func (budget Budget) IngressExhausted() bool {
+	return budget.IngressLimit > 0 && budget.IngressRemaining <= 0
+}
+
+// EgressExhausted returns whether the egress budget has been used up.
+func (budget Budget) EgressExhausted() bool {
+	return budget.EgressLimit > 0 && budget.EgressRemaining <= 0
+}
+
+// Limits enforces the bandwidth schedules and budgets of the node. The
+// transfers are shaped by a token bucket per direction, shared by all the
+// streams.
+//
+// architecture: Service
+type Limits struct {
+	db     DB
+	config LimitsConfig
+	now    func() time.Time
+
+	ingress *rate.Limiter
+	egress  *rate.Limiter
+
+	mu        sync.Mutex
+	budget    Budget
+	fetchedAt time.Time
+}
+
+// NewLimits creates the bandwidth limits.
+func NewLimits(db DB, config LimitsConfig) *Limits {
+	return &Limits{
+		db:      db,
+		config:  config,
+		now:     time.Now,
+		ingress: rate.NewLimiter(rate.Inf, minimumBurst.Int()),
+		egress:  rate.NewLimiter(rate.Inf, minimumBurst.Int()),
+	}
+}
+
+// Budget returns the bandwidth budget of the current month. The used
+// bandwidth is cached for a short while.
+func (limits *Limits) Budget(ctx context.Context) (_ Budget, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	now := limits.now()
+	from, to := date.MonthBoundary(now.UTC())
+	if !limits.fetchedAt.IsZero() && now.Sub(limits.fetchedAt) < limits.config.UsageCacheTTL && !limits.fetchedAt.Before(from) {
+		return limits.budget, nil
+	}
+
+	ingress, err := limits.db.IngressSummary(ctx, from, to)
+	if err != nil {
+		return Budget{}, ErrLimits.Wrap(err)
+	}
+	egress, err := limits.db.EgressSummary(ctx, from, to)
+	if err != nil {
+		return Budget{}, ErrLimits.Wrap(err)
+	}
+
+	budget := Budget{
+		IngressLimit: limits.config.MonthlyIngress.Int64(),
+		IngressUsed:  ingress.Put + ingress.PutRepair,
+		EgressLimit:  limits.config.MonthlyEgress.Int64(),
+		EgressUsed:   egress.Get + egress.GetAudit + egress.GetRepair,
+	}
+	if budget.IngressLimit > 0 {
+		budget.IngressRemaining = maxInt64(budget.IngressLimit-budget.IngressUsed, 0)
+	}
+	if budget.EgressLimit > 0 {
+		budget.EgressRemaining = maxInt64(budget.EgressLimit-budget.EgressUsed, 0)
+	}
+
+	limits.budget, limits.fetchedAt = budget, now
+	return budget, nil
+}
+
+// WaitIngress waits until n bytes may be received according to the ingress schedule.
+func (limits *Limits) WaitIngress(ctx context.Context, n int) error {
+	return limits.wait(ctx, limits.ingress, limits.config.IngressSchedule, n)
+}
+
+// WaitEgress waits until n bytes may be sent according to the egress schedule.
+func (limits *Limits) WaitEgress(ctx context.Context, n int) error {
+	return limits.wait(ctx, limits.egress, limits.config.EgressSchedule, n)
+}
+
+func (limits *Limits) wait(ctx context.Context, limiter *rate.Limiter, schedule Schedule, n int) error {
+	if len(schedule) == 0 {
+		return nil
+	}
+
+	now := limits.now()
+	limit, burst := rate.Inf, minimumBurst.Int()
+	if scheduled := schedule.Rate(now); scheduled > 0 {
+		limit = rate.Limit(scheduled)
+		if scheduled > minimumBurst {
+			burst = scheduled.Int()
+		}
+	}
+	if limiter.Limit() != limit {
+		limiter.SetLimitAt(now, limit)
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurstAt(now, burst)
+	}
+
+	// a single wait can't be larger than the burst
+	for n > 0 {
+		chunk := n
+		if chunk > burst {
+			chunk = burst
+		}
+		if err := limiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
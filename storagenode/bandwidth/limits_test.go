@@ -0,0 +1,111 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bandwidth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestSchedule(t *testing.T) {
+	var schedule bandwidth.Schedule
+	require.NoError(t, schedule.Set("08:00-18:00=2MiB, 22:00-06:30=512KiB"))
+	require.Equal(t, bandwidth.Schedule{
+		{Start: 8 * time.Hour, End: 18 * time.Hour, Rate: 2 * memory.MiB},
+		{Start: 22 * time.Hour, End: 6*time.Hour + 30*time.Minute, Rate: 512 * memory.KiB},
+	}, schedule)
+
+	var parsed bandwidth.Schedule
+	require.NoError(t, parsed.Set(schedule.String()))
+	require.Equal(t, schedule, parsed)
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2020, 5, 20, hour, minute, 0, 0, time.Local)
+	}
+	require.Equal(t, 2*memory.MiB, schedule.Rate(at(8, 0)))
+	require.Equal(t, 2*memory.MiB, schedule.Rate(at(17, 59)))
+	require.Equal(t, memory.Size(0), schedule.Rate(at(18, 0)))
+	require.Equal(t, 512*memory.KiB, schedule.Rate(at(23, 0)))
+	require.Equal(t, 512*memory.KiB, schedule.Rate(at(3, 0)))
+	require.Equal(t, memory.Size(0), schedule.Rate(at(6, 30)))
+
+	require.NoError(t, parsed.Set(""))
+	require.Empty(t, parsed)
+
+	for _, invalid := range []string{"08:00=1MB", "8-18=1MB", "08:00-18:00", "08:00-18:00=0B", "25:00-26:00=1MB"} {
+		require.Error(t, parsed.Set(invalid), invalid)
+	}
+}
+
+func TestLimitsBudget(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		satelliteID := testrand.NodeID()
+
+		unlimited := bandwidth.NewLimits(db.Bandwidth(), bandwidth.LimitsConfig{})
+		limits := bandwidth.NewLimits(db.Bandwidth(), bandwidth.LimitsConfig{
+			MonthlyIngress: 10 * memory.KiB,
+			MonthlyEgress:  20 * memory.KiB,
+		})
+
+		budget, err := limits.Budget(ctx)
+		require.NoError(t, err)
+		require.Equal(t, bandwidth.Budget{
+			IngressLimit:     10 * memory.KiB.Int64(),
+			IngressRemaining: 10 * memory.KiB.Int64(),
+			EgressLimit:      20 * memory.KiB.Int64(),
+			EgressRemaining:  20 * memory.KiB.Int64(),
+		}, budget)
+
+		now := time.Now()
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_PUT, 6*memory.KiB.Int64(), now))
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_PUT_REPAIR, 6*memory.KiB.Int64(), now))
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET, 5*memory.KiB.Int64(), now))
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET_AUDIT, memory.KiB.Int64(), now))
+
+		budget, err = limits.Budget(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 12*memory.KiB, budget.IngressUsed)
+		require.Zero(t, budget.IngressRemaining)
+		require.True(t, budget.IngressExhausted())
+		require.EqualValues(t, 6*memory.KiB, budget.EgressUsed)
+		require.EqualValues(t, 14*memory.KiB, budget.EgressRemaining)
+		require.False(t, budget.EgressExhausted())
+
+		budget, err = unlimited.Budget(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 12*memory.KiB, budget.IngressUsed)
+		require.False(t, budget.IngressExhausted())
+		require.False(t, budget.EgressExhausted())
+	})
+}
+
+func TestLimitsWait(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// without a schedule nothing waits
+	limits := bandwidth.NewLimits(nil, bandwidth.LimitsConfig{})
+	require.NoError(t, limits.WaitIngress(ctx, 100*memory.MiB.Int()))
+
+	limits = bandwidth.NewLimits(nil, bandwidth.LimitsConfig{
+		EgressSchedule: bandwidth.Schedule{{Start: 0, End: 24 * time.Hour, Rate: memory.MiB}},
+	})
+	require.NoError(t, limits.WaitIngress(ctx, 100*memory.MiB.Int()))
+
+	// the first second is the burst, the rest is limited
+	start := time.Now()
+	require.NoError(t, limits.WaitEgress(ctx, memory.MiB.Int()))
+	require.NoError(t, limits.WaitEgress(ctx, memory.MiB.Int()/2))
+	require.True(t, time.Since(start) >= 400*time.Millisecond, time.Since(start))
+}
@@ -0,0 +1,246 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleapi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+	"storj.io/storj/storagenode/gracefulexit"
+)
+
+// ErrGracefulExitAPI - console graceful exit api error type.
+var ErrGracefulExitAPI = errs.Class("graceful exit console web error")
+
+// GracefulExit is an api controller that exposes the graceful exit api.
+type GracefulExit struct {
+	service *gracefulexit.Service
+
+	log *zap.Logger
+}
+
+// ExitReceipt is the completion receipt of a finished graceful exit.
+type ExitReceipt struct {
+	SatelliteID storj.NodeID `json:"satelliteId"`
+	Successful  bool         `json:"successful"`
+	FinishedAt  time.Time    `json:"finishedAt"`
+	Receipt     string       `json:"receipt"`
+}
+
+// NewGracefulExit is a constructor for graceful exit controller.
+func NewGracefulExit(log *zap.Logger, service *gracefulexit.Service) *GracefulExit {
+	return &GracefulExit{
+		log:     log,
+		service: service,
+	}
+}
+
+// Satellites returns the satellites which the node can gracefully exit.
+func (exit *GracefulExit) Satellites(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	satellites, err := exit.service.ListSatellites(ctx)
+	if err != nil {
+		exit.serveJSONError(w, http.StatusInternalServerError, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	exit.serveJSON(w, satellites)
+}
+
+// Progress returns the progress of the graceful exits of the node.
+func (exit *GracefulExit) Progress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	progress, err := exit.service.ListExits(ctx)
+	if err != nil {
+		exit.serveJSONError(w, http.StatusInternalServerError, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	exit.serveJSON(w, progress)
+}
+
+// Initiate starts the graceful exit from a satellite.
+func (exit *GracefulExit) Initiate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	satelliteID, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		exit.serveJSONError(w, http.StatusBadRequest, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	progress, err := exit.service.InitiateExit(ctx, satelliteID)
+	switch {
+	case gracefulexit.ErrUntrusted.Has(err):
+		exit.serveJSONError(w, http.StatusBadRequest, ErrGracefulExitAPI.Wrap(err))
+		return
+	case gracefulexit.ErrExitRejected.Has(err):
+		exit.serveJSONError(w, http.StatusConflict, ErrGracefulExitAPI.Wrap(err))
+		return
+	case err != nil:
+		exit.serveJSONError(w, http.StatusInternalServerError, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	exit.serveJSON(w, progress)
+}
+
+// Cancel cancels the graceful exit from a satellite.
+func (exit *GracefulExit) Cancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	satelliteID, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		exit.serveJSONError(w, http.StatusBadRequest, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	err = exit.service.CancelExit(ctx, satelliteID)
+	switch {
+	case gracefulexit.ErrNotExiting.Has(err):
+		exit.serveJSONError(w, http.StatusNotFound, ErrGracefulExitAPI.Wrap(err))
+	case gracefulexit.ErrCancelRejected.Has(err):
+		exit.serveJSONError(w, http.StatusConflict, ErrGracefulExitAPI.Wrap(err))
+	case err != nil:
+		exit.serveJSONError(w, http.StatusInternalServerError, ErrGracefulExitAPI.Wrap(err))
+	}
+}
+
+// Shrinks returns the progress of the shrinks of the node.
+func (exit *GracefulExit) Shrinks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	progress, err := exit.service.ListShrinks(ctx)
+	if err != nil {
+		exit.serveJSONError(w, http.StatusInternalServerError, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	exit.serveJSON(w, progress)
+}
+
+// Shrink starts moving the requested number of bytes of the pieces from a
+// satellite to other nodes, without exiting the satellite.
+func (exit *GracefulExit) Shrink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	satelliteID, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		exit.serveJSONError(w, http.StatusBadRequest, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	var request struct {
+		TargetBytes int64 `json:"targetBytes"`
+	}
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		exit.serveJSONError(w, http.StatusBadRequest, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	progress, err := exit.service.InitiateShrink(ctx, satelliteID, request.TargetBytes)
+	if err != nil {
+		if gracefulexit.ErrShrinkRejected.Has(err) {
+			exit.serveJSONError(w, http.StatusConflict, ErrGracefulExitAPI.Wrap(err))
+			return
+		}
+		exit.serveJSONError(w, http.StatusInternalServerError, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	exit.serveJSON(w, progress)
+}
+
+// Receipt returns the completion receipt of a finished graceful exit.
+func (exit *GracefulExit) Receipt(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	satelliteID, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		exit.serveJSONError(w, http.StatusBadRequest, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+
+	progress, err := exit.service.GetExit(ctx, satelliteID)
+	if err != nil {
+		if gracefulexit.ErrNotExiting.Has(err) {
+			exit.serveJSONError(w, http.StatusNotFound, ErrGracefulExitAPI.Wrap(err))
+			return
+		}
+		exit.serveJSONError(w, http.StatusInternalServerError, ErrGracefulExitAPI.Wrap(err))
+		return
+	}
+	if progress.FinishedAt == nil {
+		exit.serveJSONError(w, http.StatusNotFound, ErrGracefulExitAPI.New("graceful exit from %s hasn't finished", satelliteID))
+		return
+	}
+
+	exit.serveJSON(w, ExitReceipt{
+		SatelliteID: progress.SatelliteID,
+		Successful:  progress.Successful,
+		FinishedAt:  *progress.FinishedAt,
+		Receipt:     hex.EncodeToString(progress.CompletionReceipt),
+	})
+}
+
+// serveJSON writes the value as JSON to response output stream.
+func (exit *GracefulExit) serveJSON(w http.ResponseWriter, value interface{}) {
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		exit.log.Error("failed to encode json graceful exit response", zap.Error(ErrGracefulExitAPI.Wrap(err)))
+	}
+}
+
+// serveJSONError writes JSON error to response output stream.
+func (exit *GracefulExit) serveJSONError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+
+	var response struct {
+		Error string `json:"error"`
+	}
+
+	response.Error = err.Error()
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		exit.log.Error("failed to write json error response", zap.Error(ErrGracefulExitAPI.Wrap(err)))
+		return
+	}
+}
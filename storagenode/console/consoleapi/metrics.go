@@ -0,0 +1,51 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleapi
+
+import (
+	"net/http"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storagenode/console/consolemetrics"
+)
+
+// ErrMetricsAPI - console metrics api error type.
+var ErrMetricsAPI = errs.Class("storagenode metrics console web error")
+
+// Metrics is an api controller that exposes the node metrics for Prometheus.
+type Metrics struct {
+	service *consolemetrics.Service
+
+	log *zap.Logger
+}
+
+// NewMetrics is a constructor for metrics controller.
+func NewMetrics(log *zap.Logger, service *consolemetrics.Service) *Metrics {
+	return &Metrics{
+		log:     log,
+		service: service,
+	}
+}
+
+// Metrics handles the metrics scrape requests.
+func (metrics *Metrics) Metrics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	families, err := metrics.service.Collect(ctx)
+	if err != nil {
+		metrics.log.Error("failed to collect metrics", zap.Error(ErrMetricsAPI.Wrap(err)))
+		http.Error(w, ErrMetricsAPI.Wrap(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentType, consolemetrics.ContentType)
+	if err := consolemetrics.Write(w, families); err != nil {
+		metrics.log.Error("failed to write metrics", zap.Error(ErrMetricsAPI.Wrap(err)))
+		return
+	}
+}
@@ -111,6 +111,26 @@ func (dashboard *StorageNode) Satellite(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// CorruptPieces handles corrupt pieces API request.
+func (dashboard *StorageNode) CorruptPieces(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set(contentType, applicationJSON)
+
+	data, err := dashboard.service.GetCorruptPieces(ctx)
+	if err != nil {
+		dashboard.serveJSONError(w, http.StatusInternalServerError, ErrStorageNodeAPI.Wrap(err))
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		dashboard.log.Error("failed to encode json response", zap.Error(ErrStorageNodeAPI.Wrap(err)))
+		return
+	}
+}
+
 // serveJSONError writes JSON error to response output stream.
 func (dashboard *StorageNode) serveJSONError(w http.ResponseWriter, status int, err error) {
 	w.WriteHeader(status)
@@ -0,0 +1,86 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consolemetrics
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Type is the type of a metric family.
+type Type string
+
+const (
+	// Gauge is a value which can go up and down.
+	Gauge Type = "gauge"
+	// Counter is a value which only goes up, except when it's reset.
+	Counter Type = "counter"
+)
+
+// Label is a name and value pair which identifies a sample.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric family.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Family is a group of samples with the same name, help and type.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// Add appends a sample with the labels given as name and value pairs.
+func (family *Family) Add(value float64, labels ...string) {
+	sample := Sample{Value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		sample.Labels = append(sample.Labels, Label{Name: labels[i], Value: labels[i+1]})
+	}
+	family.Samples = append(family.Samples, sample)
+}
+
+// Write writes the families in the Prometheus text exposition format, see
+// https://prometheus.io/docs/instrumenting/exposition_formats/.
+func Write(w io.Writer, families []*Family) error {
+	buf := bufio.NewWriter(w)
+	for _, family := range families {
+		_, _ = buf.WriteString("# HELP " + family.Name + " " + helpEscaper.Replace(family.Help) + "\n")
+		_, _ = buf.WriteString("# TYPE " + family.Name + " " + string(family.Type) + "\n")
+
+		for _, sample := range family.Samples {
+			_, _ = buf.WriteString(family.Name)
+			if len(sample.Labels) > 0 {
+				_ = buf.WriteByte('{')
+				for i, label := range sample.Labels {
+					if i > 0 {
+						_ = buf.WriteByte(',')
+					}
+					_, _ = buf.WriteString(label.Name + `="` + labelEscaper.Replace(label.Value) + `"`)
+				}
+				_ = buf.WriteByte('}')
+			}
+			_ = buf.WriteByte(' ')
+			_, _ = buf.WriteString(strconv.FormatFloat(sample.Value, 'g', -1, 64))
+			_ = buf.WriteByte('\n')
+		}
+	}
+	return buf.Flush()
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
@@ -0,0 +1,267 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consolemetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/storj/private/date"
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/retain"
+)
+
+var (
+	// Error is the default error class for the storage node metrics.
+	Error = errs.Class("storagenode metrics error")
+
+	mon = monkit.Package()
+)
+
+// microDollars is the number of units of the held amounts in a dollar.
+const microDollars = 1e6
+
+// Databases contains the databases the metrics are read from.
+type Databases struct {
+	Bandwidth  bandwidth.DB
+	Reputation reputation.DB
+	HeldAmount heldamount.DB
+	Orders     orders.DB
+}
+
+// Service collects the storage node dashboard data as metrics.
+//
+// architecture: Service
+type Service struct {
+	log *zap.Logger
+	db  Databases
+
+	store              *pieces.Store
+	allocatedDiskSpace memory.Size
+	retain             *retain.Service
+	deleter            *pieces.Deleter
+}
+
+// NewService creates a new metrics service.
+func NewService(log *zap.Logger, db Databases, store *pieces.Store, allocatedDiskSpace memory.Size, retain *retain.Service, deleter *pieces.Deleter) *Service {
+	return &Service{
+		log:                log,
+		db:                 db,
+		store:              store,
+		allocatedDiskSpace: allocatedDiskSpace,
+		retain:             retain,
+		deleter:            deleter,
+	}
+}
+
+// Collect returns the current values of all the metrics.
+func (service *Service) Collect(ctx context.Context) (_ []*Family, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var families []*Family
+	for _, collect := range []func(context.Context) ([]*Family, error){
+		service.collectDisk,
+		service.collectBandwidth,
+		service.collectReputation,
+		service.collectHeldAmount,
+		service.collectOrders,
+		service.collectGarbageCollection,
+	} {
+		collected, err := collect(ctx)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		families = append(families, collected...)
+	}
+	return families, nil
+}
+
+func (service *Service) collectDisk(ctx context.Context) (_ []*Family, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	used, _, err := service.store.SpaceUsedForPieces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trash, err := service.store.SpaceUsedForTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status, err := service.store.StorageStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allocated := service.allocatedDiskSpace.Int64()
+	free := allocated - used - trash
+	if free < 0 {
+		free = 0
+	}
+
+	return []*Family{
+		single("storagenode_disk_used_bytes", "Space used by the pieces, including their headers.", used),
+		single("storagenode_disk_trash_bytes", "Space used by the pieces in the trash.", trash),
+		single("storagenode_disk_allocated_bytes", "Space allocated to the node.", allocated),
+		single("storagenode_disk_free_bytes", "Allocated space which isn't used by pieces or the trash.", free),
+		single("storagenode_disk_filesystem_free_bytes", "Free space of the file system storing the pieces.", status.DiskFree),
+	}, nil
+}
+
+func (service *Service) collectBandwidth(ctx context.Context) (_ []*Family, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	from, to := date.MonthBoundary(time.Now().UTC())
+	usages, err := service.db.Bandwidth.SummaryBySatellite(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	family := &Family{
+		Name: "storagenode_bandwidth_month_bytes",
+		Help: "Bandwidth used in the current calendar month by satellite and action.",
+		Type: Gauge,
+	}
+	for satelliteID, usage := range usages {
+		satellite := satelliteID.String()
+		family.Add(float64(usage.Put), "satellite", satellite, "action", "put")
+		family.Add(float64(usage.Get), "satellite", satellite, "action", "get")
+		family.Add(float64(usage.GetAudit), "satellite", satellite, "action", "get_audit")
+		family.Add(float64(usage.GetRepair), "satellite", satellite, "action", "get_repair")
+		family.Add(float64(usage.PutRepair), "satellite", satellite, "action", "put_repair")
+		family.Add(float64(usage.Delete), "satellite", satellite, "action", "delete")
+	}
+	return []*Family{family}, nil
+}
+
+func (service *Service) collectReputation(ctx context.Context) (_ []*Family, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stats, err := service.db.Reputation.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auditScore := &Family{Name: "storagenode_audit_score", Help: "Audit score reported by the satellite.", Type: Gauge}
+	audits := &Family{Name: "storagenode_audits_total", Help: "Number of audits reported by the satellite.", Type: Counter}
+	auditsSuccess := &Family{Name: "storagenode_audits_success_total", Help: "Number of successful audits reported by the satellite.", Type: Counter}
+	uptimeScore := &Family{Name: "storagenode_uptime_score", Help: "Uptime score reported by the satellite.", Type: Gauge}
+	uptimeChecks := &Family{Name: "storagenode_uptime_checks_total", Help: "Number of uptime checks reported by the satellite.", Type: Counter}
+	uptimeChecksSuccess := &Family{Name: "storagenode_uptime_checks_success_total", Help: "Number of successful uptime checks reported by the satellite.", Type: Counter}
+	disqualified := &Family{Name: "storagenode_disqualified", Help: "Whether the node is disqualified by the satellite.", Type: Gauge}
+	suspended := &Family{Name: "storagenode_suspended", Help: "Whether the node is suspended by the satellite.", Type: Gauge}
+
+	for _, stat := range stats {
+		satellite := stat.SatelliteID.String()
+		auditScore.Add(stat.Audit.Score, "satellite", satellite)
+		audits.Add(float64(stat.Audit.TotalCount), "satellite", satellite)
+		auditsSuccess.Add(float64(stat.Audit.SuccessCount), "satellite", satellite)
+		uptimeScore.Add(stat.Uptime.Score, "satellite", satellite)
+		uptimeChecks.Add(float64(stat.Uptime.TotalCount), "satellite", satellite)
+		uptimeChecksSuccess.Add(float64(stat.Uptime.SuccessCount), "satellite", satellite)
+		disqualified.Add(boolValue(stat.Disqualified != nil), "satellite", satellite)
+		suspended.Add(boolValue(stat.Suspended != nil), "satellite", satellite)
+	}
+
+	return []*Family{auditScore, audits, auditsSuccess, uptimeScore, uptimeChecks, uptimeChecksSuccess, disqualified, suspended}, nil
+}
+
+func (service *Service) collectHeldAmount(ctx context.Context) (_ []*Family, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stats, err := service.db.Reputation.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	family := &Family{
+		Name: "storagenode_held_amount_dollars",
+		Help: "Total amount held back by the satellite and not yet disposed, according to the paystubs.",
+		Type: Gauge,
+	}
+	for _, stat := range stats {
+		history, err := service.db.HeldAmount.SatellitesHeldbackHistory(ctx, stat.SatelliteID)
+		if err != nil {
+			return nil, err
+		}
+
+		var held int64
+		for _, period := range history {
+			held += period.Held - period.Disposed
+		}
+		family.Add(float64(held)/microDollars, "satellite", stat.SatelliteID.String())
+	}
+	return []*Family{family}, nil
+}
+
+func (service *Service) collectOrders(ctx context.Context) (_ []*Family, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	counts, err := service.db.Orders.CountUnsentBySatellite(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	family := &Family{
+		Name: "storagenode_orders_unsent",
+		Help: "Number of orders which haven't been sent to the satellite yet.",
+		Type: Gauge,
+	}
+	for satelliteID, count := range counts {
+		family.Add(float64(count), "satellite", satelliteID.String())
+	}
+	return []*Family{family}, nil
+}
+
+func (service *Service) collectGarbageCollection(ctx context.Context) (_ []*Family, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	status := &Family{
+		Name: "storagenode_retain_status",
+		Help: "Whether garbage collection requests from the satellites are disabled, only logged, or enabled.",
+		Type: Gauge,
+	}
+	current := service.retain.Status()
+	for _, value := range []retain.Status{retain.Disabled, retain.Debug, retain.Enabled} {
+		status.Add(boolValue(current == value), "status", value.String())
+	}
+
+	queued, working := service.retain.Pending()
+	requests := &Family{
+		Name: "storagenode_retain_requests",
+		Help: "Number of garbage collection requests which are queued or being processed.",
+		Type: Gauge,
+	}
+	requests.Add(float64(queued), "state", "queued")
+	requests.Add(float64(working), "state", "working")
+
+	return []*Family{
+		status,
+		requests,
+		single("storagenode_piece_deleter_queue_length", "Number of piece deletes waiting in the queue.", int64(service.deleter.QueueLength())),
+		single("storagenode_piece_deleter_queue_capacity", "Number of piece deletes the queue can hold before they are left to garbage collection.", int64(service.deleter.QueueCapacity())),
+	}, nil
+}
+
+// single returns a gauge family with a single sample without labels.
+func single(name, help string, value int64) *Family {
+	family := &Family{Name: name, Help: help, Type: Gauge}
+	family.Add(float64(value))
+	return family
+}
+
+func boolValue(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
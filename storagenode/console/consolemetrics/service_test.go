@@ -0,0 +1,126 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consolemetrics_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/console/consolemetrics"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/retain"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestWrite(t *testing.T) {
+	families := []*consolemetrics.Family{
+		{Name: "single", Help: "A single\nvalue.", Type: consolemetrics.Gauge},
+		{Name: "labeled_total", Help: `Values with \ labels.`, Type: consolemetrics.Counter},
+	}
+	families[0].Add(1.5)
+	families[1].Add(3, "satellite", "a", "action", "get")
+	families[1].Add(1e12, "satellite", "b\"\n\\", "action", "put")
+
+	var buf bytes.Buffer
+	require.NoError(t, consolemetrics.Write(&buf, families))
+	require.Equal(t, ""+
+		"# HELP single A single\\nvalue.\n"+
+		"# TYPE single gauge\n"+
+		"single 1.5\n"+
+		"# HELP labeled_total Values with \\\\ labels.\n"+
+		"# TYPE labeled_total counter\n"+
+		"labeled_total{satellite=\"a\",action=\"get\"} 3\n"+
+		"labeled_total{satellite=\"b\\\"\\n\\\\\",action=\"put\"} 1e+12\n",
+		buf.String())
+}
+
+func TestCollect(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		log := zaptest.NewLogger(t)
+		satelliteID := testrand.NodeID()
+		now := time.Now()
+
+		store := pieces.NewStore(log, db.Pieces(), db.V0PieceInfo(), db.PieceExpirationDB(), db.PieceSpaceUsedDB(), pieces.DefaultConfig)
+		retainService := retain.NewService(log, store, retain.Config{Status: retain.Debug, Concurrency: 1})
+		deleter := pieces.NewDeleter(log, store, 1, 10)
+
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET, 100, now))
+		require.NoError(t, db.Reputation().Store(ctx, reputation.Stats{
+			SatelliteID: satelliteID,
+			Audit:       reputation.Metric{TotalCount: 10, SuccessCount: 9, Score: 0.95},
+			Uptime:      reputation.Metric{TotalCount: 20, SuccessCount: 20, Score: 1},
+			UpdatedAt:   now,
+			JoinedAt:    now,
+		}))
+		require.NoError(t, db.HeldAmount().StorePayStub(ctx, heldamount.PayStub{SatelliteID: satelliteID, Period: "2020-04", Held: 1500000}))
+		require.NoError(t, db.HeldAmount().StorePayStub(ctx, heldamount.PayStub{SatelliteID: satelliteID, Period: "2020-05", Held: 500000}))
+		require.NoError(t, db.HeldAmount().StorePayStub(ctx, heldamount.PayStub{SatelliteID: satelliteID, Period: "2020-06", Held: 250000, Disposed: 750000}))
+		require.NoError(t, db.Orders().Enqueue(ctx, &orders.Info{
+			Limit: &pb.OrderLimit{
+				SatelliteId:     satelliteID,
+				SerialNumber:    testrand.SerialNumber(),
+				OrderExpiration: now.Add(time.Hour),
+			},
+			Order: &pb.Order{},
+		}))
+		require.Equal(t, 0, deleter.Enqueue(ctx, satelliteID, []storj.PieceID{testrand.PieceID()}))
+
+		service := consolemetrics.NewService(log, consolemetrics.Databases{
+			Bandwidth:  db.Bandwidth(),
+			Reputation: db.Reputation(),
+			HeldAmount: db.HeldAmount(),
+			Orders:     db.Orders(),
+		}, store, memory.TB, retainService, deleter)
+
+		families, err := service.Collect(ctx)
+		require.NoError(t, err)
+
+		values := map[string]float64{}
+		for _, family := range families {
+			for _, sample := range family.Samples {
+				key := family.Name
+				for _, label := range sample.Labels {
+					if label.Name != "satellite" {
+						key += "," + label.Name + "=" + label.Value
+					}
+				}
+				values[key] = sample.Value
+			}
+		}
+
+		require.EqualValues(t, memory.TB, values["storagenode_disk_allocated_bytes"])
+		require.EqualValues(t, memory.TB, values["storagenode_disk_free_bytes"])
+		require.EqualValues(t, 100, values["storagenode_bandwidth_month_bytes,action=get"])
+		require.EqualValues(t, 0, values["storagenode_bandwidth_month_bytes,action=put"])
+		require.EqualValues(t, 0.95, values["storagenode_audit_score"])
+		require.EqualValues(t, 9, values["storagenode_audits_success_total"])
+		require.EqualValues(t, 1, values["storagenode_uptime_score"])
+		require.EqualValues(t, 0, values["storagenode_disqualified"])
+		// the disposed amount is paid out and no longer held
+		require.EqualValues(t, 1.5, values["storagenode_held_amount_dollars"])
+		require.EqualValues(t, 1, values["storagenode_orders_unsent"])
+		require.EqualValues(t, 1, values["storagenode_retain_status,status=debug"])
+		require.EqualValues(t, 0, values["storagenode_retain_status,status=enabled"])
+		require.EqualValues(t, 0, values["storagenode_retain_requests,state=queued"])
+		require.EqualValues(t, 1, values["storagenode_piece_deleter_queue_length"])
+		require.EqualValues(t, 10, values["storagenode_piece_deleter_queue_capacity"])
+
+		var buf bytes.Buffer
+		require.NoError(t, consolemetrics.Write(&buf, families))
+		require.Contains(t, buf.String(), `storagenode_audit_score{satellite="`+satelliteID.String()+`"} 0.95`)
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleserver
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/uuid"
+	"storj.io/storj/storagenode/apikeys"
+)
+
+// apiKeysDB is an in-memory apikeys.DB.
+type apiKeysDB struct {
+	keys []apikeys.APIKey
+}
+
+func (db *apiKeysDB) Store(ctx context.Context, key apikeys.APIKey) error {
+	db.keys = append(db.keys, key)
+	return nil
+}
+
+func (db *apiKeysDB) GetByHash(ctx context.Context, hash []byte) (apikeys.APIKey, error) {
+	for _, key := range db.keys {
+		if bytes.Equal(key.Hash, hash) {
+			return key, nil
+		}
+	}
+	return apikeys.APIKey{}, apikeys.ErrUnauthorized.New("unknown api key")
+}
+
+func (db *apiKeysDB) List(ctx context.Context) ([]apikeys.APIKey, error) {
+	return db.keys, nil
+}
+
+func (db *apiKeysDB) Revoke(ctx context.Context, id uuid.UUID) error {
+	for i, key := range db.keys {
+		if key.ID == id {
+			db.keys = append(db.keys[:i], db.keys[i+1:]...)
+			return nil
+		}
+	}
+	return apikeys.Error.New("not found")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(server *Server, required apikeys.Scope, remoteAddr, secret string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/sno", nil)
+		req.RemoteAddr = remoteAddr
+		if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		rec := httptest.NewRecorder()
+		server.authMiddleware(required)(ok).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	service := apikeys.NewService(zaptest.NewLogger(t), &apiKeysDB{})
+	_, readOnlySecret, err := service.Issue(ctx, "aggregator", apikeys.ReadOnly)
+	require.NoError(t, err)
+	adminKey, adminSecret, err := service.Issue(ctx, "admin", apikeys.Admin)
+	require.NoError(t, err)
+
+	open := &Server{log: zaptest.NewLogger(t), apiKeys: service}
+	require.Equal(t, http.StatusOK, serve(open, apikeys.Admin, "192.0.2.1:1234", ""))
+
+	protected := &Server{log: zaptest.NewLogger(t), apiKeys: service, config: Config{RequireAPIKey: true}}
+	require.Equal(t, http.StatusUnauthorized, serve(protected, apikeys.ReadOnly, "192.0.2.1:1234", ""))
+	require.Equal(t, http.StatusUnauthorized, serve(protected, apikeys.ReadOnly, "192.0.2.1:1234", "wrong"))
+	require.Equal(t, http.StatusOK, serve(protected, apikeys.ReadOnly, "192.0.2.1:1234", readOnlySecret))
+	require.Equal(t, http.StatusUnauthorized, serve(protected, apikeys.Admin, "192.0.2.1:1234", readOnlySecret))
+	require.Equal(t, http.StatusOK, serve(protected, apikeys.ReadOnly, "192.0.2.1:1234", adminSecret))
+	require.Equal(t, http.StatusOK, serve(protected, apikeys.Admin, "192.0.2.1:1234", adminSecret))
+	// the requests from the loopback address, such as a reverse proxy, need an api key too
+	require.Equal(t, http.StatusUnauthorized, serve(protected, apikeys.ReadOnly, "127.0.0.1:1234", ""))
+	require.Equal(t, http.StatusUnauthorized, serve(protected, apikeys.Admin, "[::1]:1234", ""))
+	require.Equal(t, http.StatusOK, serve(protected, apikeys.Admin, "127.0.0.1:1234", adminSecret))
+
+	require.NoError(t, service.Revoke(ctx, adminKey.ID))
+	require.Equal(t, http.StatusUnauthorized, serve(protected, apikeys.Admin, "192.0.2.1:1234", adminSecret))
+}
+
+func TestAuthMiddlewareContentType(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &Server{log: zaptest.NewLogger(t)}
+	serve := func(method, contentType string) int {
+		req := httptest.NewRequest(method, "/api/gracefulexit/id/initiate", nil)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		server.authMiddleware(apikeys.Admin)(ok).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// the requests which a cross-site form can send are rejected, even
+	// without required api keys
+	require.Equal(t, http.StatusUnsupportedMediaType, serve(http.MethodPost, ""))
+	require.Equal(t, http.StatusUnsupportedMediaType, serve(http.MethodPost, "application/x-www-form-urlencoded"))
+	require.Equal(t, http.StatusUnsupportedMediaType, serve(http.MethodPost, "text/plain"))
+	require.Equal(t, http.StatusOK, serve(http.MethodPost, "application/json"))
+	require.Equal(t, http.StatusOK, serve(http.MethodPost, "application/json; charset=utf-8"))
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, ""))
+}
+
+func TestNewServerRequiresTLSCertAndKey(t *testing.T) {
+	log := zaptest.NewLogger(t)
+
+	_, err := NewServer(log, Config{TLSCertPath: "cert.pem"}, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+
+	_, err = NewServer(log, Config{TLSKeyPath: "key.pem"}, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+
+	_, err = NewServer(log, Config{TLSCertPath: "cert.pem", TLSKeyPath: "key.pem"}, nil, nil, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+}
@@ -5,10 +5,13 @@ package consoleserver
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"mime"
 	"net"
 	"net/http"
 	"path/filepath"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/spacemonkeygo/monkit/v3"
@@ -17,8 +20,11 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"storj.io/common/errs2"
+	"storj.io/storj/storagenode/apikeys"
 	"storj.io/storj/storagenode/console"
 	"storj.io/storj/storagenode/console/consoleapi"
+	"storj.io/storj/storagenode/console/consolemetrics"
+	"storj.io/storj/storagenode/gracefulexit"
 	"storj.io/storj/storagenode/heldamount"
 	"storj.io/storj/storagenode/notifications"
 )
@@ -33,56 +39,101 @@ var (
 type Config struct {
 	Address   string `help:"server address of the api gateway and frontend app" default:"127.0.0.1:14002"`
 	StaticDir string `help:"path to static resources" default:""`
+	Metrics   bool   `help:"expose the dashboard data as Prometheus metrics at /metrics" default:"false"`
+
+	RequireAPIKey bool   `help:"require the api requests to send an api key issued with the issue-apikey command as a bearer token in the Authorization header, including the requests from the loopback address, which may come from a reverse proxy" default:"false"`
+	TLSCertPath   string `help:"path to the TLS certificate, the server uses TLS when it's set, together with the key" default:""`
+	TLSKeyPath    string `help:"path to the TLS private key, required with the TLS certificate" default:""`
 }
 
 // Server represents storagenode console web server.
 //
 // architecture: Endpoint
 type Server struct {
-	log *zap.Logger
+	log    *zap.Logger
+	config Config
 
 	service       *console.Service
 	notifications *notifications.Service
 	heldAmount    *heldamount.Service
+	gracefulExit  *gracefulexit.Service
+	metrics       *consolemetrics.Service
+	apiKeys       *apikeys.Service
 	listener      net.Listener
 
 	server http.Server
 }
 
 // NewServer creates new instance of storagenode console web server.
-func NewServer(logger *zap.Logger, assets http.FileSystem, notifications *notifications.Service, service *console.Service, heldAmount *heldamount.Service, listener net.Listener) *Server {
+// The metrics endpoint is only served when metrics isn't nil.
+//
+// It fails when only one of the TLS certificate and key is set, rather than
+// serving plaintext.
+func NewServer(logger *zap.Logger, config Config, assets http.FileSystem, notifications *notifications.Service, service *console.Service, heldAmount *heldamount.Service, gracefulExit *gracefulexit.Service, metrics *consolemetrics.Service, apiKeys *apikeys.Service, listener net.Listener) (*Server, error) {
+	if (config.TLSCertPath == "") != (config.TLSKeyPath == "") {
+		return nil, Error.New("both the TLS certificate and key must be set to use TLS")
+	}
+
 	server := Server{
 		log:           logger,
+		config:        config,
 		service:       service,
 		listener:      listener,
 		notifications: notifications,
 		heldAmount:    heldAmount,
+		gracefulExit:  gracefulExit,
+		metrics:       metrics,
+		apiKeys:       apiKeys,
 	}
 
 	router := mux.NewRouter()
 
+	// reading the dashboard data requires the read-only scope, changing the
+	// node state requires the admin scope.
+	readOnly := server.authMiddleware(apikeys.ReadOnly)
+	admin := server.authMiddleware(apikeys.Admin)
+
 	// handle api endpoints
 	storageNodeController := consoleapi.NewStorageNode(server.log, server.service)
 	storageNodeRouter := router.PathPrefix("/api/sno").Subrouter()
 	storageNodeRouter.StrictSlash(true)
+	storageNodeRouter.Use(readOnly)
 	storageNodeRouter.HandleFunc("/", storageNodeController.StorageNode).Methods(http.MethodGet)
 	storageNodeRouter.HandleFunc("/satellites", storageNodeController.Satellites).Methods(http.MethodGet)
 	storageNodeRouter.HandleFunc("/satellite/{id}", storageNodeController.Satellite).Methods(http.MethodGet)
+	storageNodeRouter.HandleFunc("/corrupt-pieces", storageNodeController.CorruptPieces).Methods(http.MethodGet)
 
 	notificationController := consoleapi.NewNotifications(server.log, server.notifications)
 	notificationRouter := router.PathPrefix("/api/notifications").Subrouter()
 	notificationRouter.StrictSlash(true)
-	notificationRouter.HandleFunc("/list", notificationController.ListNotifications).Methods(http.MethodGet)
-	notificationRouter.HandleFunc("/{id}/read", notificationController.ReadNotification).Methods(http.MethodPost)
-	notificationRouter.HandleFunc("/readall", notificationController.ReadAllNotifications).Methods(http.MethodPost)
+	notificationRouter.Handle("/list", readOnly(http.HandlerFunc(notificationController.ListNotifications))).Methods(http.MethodGet)
+	notificationRouter.Handle("/{id}/read", admin(http.HandlerFunc(notificationController.ReadNotification))).Methods(http.MethodPost)
+	notificationRouter.Handle("/readall", admin(http.HandlerFunc(notificationController.ReadAllNotifications))).Methods(http.MethodPost)
 
 	heldAmountController := consoleapi.NewHeldAmount(server.log, server.heldAmount)
 	heldAmountRouter := router.PathPrefix("/api/heldamount").Subrouter()
 	heldAmountRouter.StrictSlash(true)
+	heldAmountRouter.Use(readOnly)
 	heldAmountRouter.HandleFunc("/paystubs/{period}", heldAmountController.PayStubMonthly).Methods(http.MethodGet)
 	heldAmountRouter.HandleFunc("/paystubs/{start}/{end}", heldAmountController.PayStubPeriod).Methods(http.MethodGet)
 	heldAmountRouter.HandleFunc("/heldback/{id}", heldAmountController.HeldbackHistory).Methods(http.MethodGet)
 
+	gracefulExitController := consoleapi.NewGracefulExit(server.log, server.gracefulExit)
+	gracefulExitRouter := router.PathPrefix("/api/gracefulexit").Subrouter()
+	gracefulExitRouter.StrictSlash(true)
+	gracefulExitRouter.Handle("/satellites", readOnly(http.HandlerFunc(gracefulExitController.Satellites))).Methods(http.MethodGet)
+	gracefulExitRouter.Handle("/progress", readOnly(http.HandlerFunc(gracefulExitController.Progress))).Methods(http.MethodGet)
+	gracefulExitRouter.Handle("/shrinks", readOnly(http.HandlerFunc(gracefulExitController.Shrinks))).Methods(http.MethodGet)
+	gracefulExitRouter.Handle("/{id}/receipt", readOnly(http.HandlerFunc(gracefulExitController.Receipt))).Methods(http.MethodGet)
+	gracefulExitRouter.Handle("/{id}/initiate", admin(http.HandlerFunc(gracefulExitController.Initiate))).Methods(http.MethodPost)
+	gracefulExitRouter.Handle("/{id}/cancel", admin(http.HandlerFunc(gracefulExitController.Cancel))).Methods(http.MethodPost)
+	gracefulExitRouter.Handle("/{id}/shrink", admin(http.HandlerFunc(gracefulExitController.Shrink))).Methods(http.MethodPost)
+
+	if server.metrics != nil {
+		metricsController := consoleapi.NewMetrics(server.log, server.metrics)
+		router.Handle("/metrics", readOnly(http.HandlerFunc(metricsController.Metrics))).Methods(http.MethodGet)
+	}
+
 	if assets != nil {
 		fs := http.FileServer(assets)
 		router.PathPrefix("/static/").Handler(server.cacheMiddleware(http.StripPrefix("/static", fs)))
@@ -97,7 +148,7 @@ func NewServer(logger *zap.Logger, assets http.FileSystem, notifications *notifi
 		Handler: router,
 	}
 
-	return &server
+	return &server, nil
 }
 
 // Run starts the server that host webapp and api endpoints.
@@ -112,7 +163,12 @@ func (server *Server) Run(ctx context.Context) (err error) {
 	})
 	group.Go(func() error {
 		defer cancel()
-		err := server.server.Serve(server.listener)
+		var err error
+		if server.config.TLSCertPath != "" {
+			err = server.server.ServeTLS(server.listener, server.config.TLSCertPath, server.config.TLSKeyPath)
+		} else {
+			err = server.server.Serve(server.listener)
+		}
 		if errs2.IsCanceled(err) || errors.Is(err, http.ErrServerClosed) {
 			err = nil
 		}
@@ -146,3 +202,61 @@ func (server *Server) cacheMiddleware(fn http.Handler) http.Handler {
 		fn.ServeHTTP(w, r)
 	})
 }
+
+// authMiddleware returns a middleware which checks that the request has an
+// api key with the required scope, when api keys are required.
+//
+// The requests which change the node state must have the JSON content type,
+// which a browser only sends to another origin after a CORS preflight, so
+// that other websites can't send them with a cross-site form.
+func (server *Server) authMiddleware(required apikeys.Scope) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+				if err != nil || mediaType != "application/json" {
+					server.serveJSONError(w, http.StatusUnsupportedMediaType, Error.New("content type must be application/json"))
+					return
+				}
+			}
+
+			if !server.config.RequireAPIKey {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			secret := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			_, err := server.apiKeys.Check(ctx, secret, required)
+			if err != nil {
+				if apikeys.ErrUnauthorized.Has(err) {
+					server.serveJSONError(w, http.StatusUnauthorized, err)
+					return
+				}
+				server.log.Error("failed to check api key", zap.Error(err))
+				server.serveJSONError(w, http.StatusInternalServerError, Error.New("failed to check api key"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// serveJSONError writes JSON error to response output stream.
+func (server *Server) serveJSONError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	var response struct {
+		Error string `json:"error"`
+	}
+
+	response.Error = err.Error()
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		server.log.Error("failed to write json error response", zap.Error(Error.Wrap(err)))
+		return
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"storj.io/common/testcontext"
 	"storj.io/storj/private/testplanet"
+	"storj.io/storj/storagenode/console/consolemetrics"
 )
 
 func TestConsole(t *testing.T) {
@@ -44,6 +45,31 @@ func TestConsole(t *testing.T) {
 				require.NotNil(t, req)
 				_ = req.Body.Close()
 				require.Equal(t, http.StatusOK, req.StatusCode)
+
+				req, err = http.Get(fmt.Sprintf("http://%s/metrics", addr))
+				require.NoError(t, err)
+				require.NotNil(t, req)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+				require.Equal(t, consolemetrics.ContentType, req.Header.Get("Content-Type"))
+
+				req, err = http.Get(fmt.Sprintf("http://%s/api/gracefulexit/satellites", addr))
+				require.NoError(t, err)
+				require.NotNil(t, req)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+
+				req, err = http.Get(fmt.Sprintf("http://%s/api/gracefulexit/shrinks", addr))
+				require.NoError(t, err)
+				require.NotNil(t, req)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+
+				req, err = http.Get(fmt.Sprintf("http://%s/api/gracefulexit/%s/receipt", addr, satellite.ID()))
+				require.NoError(t, err)
+				require.NotNil(t, req)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusNotFound, req.StatusCode)
 			})
 		},
 	)
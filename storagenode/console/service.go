@@ -40,11 +40,13 @@ type Service struct {
 	log            *zap.Logger
 	trust          *trust.Pool
 	bandwidthDB    bandwidth.DB
+	limits         *bandwidth.Limits
 	reputationDB   reputation.DB
 	storageUsageDB storageusage.DB
 	pricingDB      pricing.DB
 	satelliteDB    satellites.DB
 	pieceStore     *pieces.Store
+	corruptPieces  pieces.CorruptPiecesDB
 	contact        *contact.Service
 
 	version   *checker.Service
@@ -58,9 +60,9 @@ type Service struct {
 }
 
 // NewService returns new instance of Service.
-func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Store, version *checker.Service,
+func NewService(log *zap.Logger, bandwidth bandwidth.DB, limits *bandwidth.Limits, pieceStore *pieces.Store, version *checker.Service,
 	allocatedDiskSpace memory.Size, walletAddress string, versionInfo version.Info, trust *trust.Pool,
-	reputationDB reputation.DB, storageUsageDB storageusage.DB, pricingDB pricing.DB, satelliteDB satellites.DB, pingStats *contact.PingStats, contact *contact.Service) (*Service, error) {
+	reputationDB reputation.DB, storageUsageDB storageusage.DB, pricingDB pricing.DB, satelliteDB satellites.DB, corruptPieces pieces.CorruptPiecesDB, pingStats *contact.PingStats, contact *contact.Service) (*Service, error) {
 	if log == nil {
 		return nil, errs.New("log can't be nil")
 	}
@@ -69,6 +71,10 @@ func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Stor
 		return nil, errs.New("bandwidth can't be nil")
 	}
 
+	if limits == nil {
+		return nil, errs.New("limits can't be nil")
+	}
+
 	if pieceStore == nil {
 		return nil, errs.New("pieceStore can't be nil")
 	}
@@ -89,11 +95,13 @@ func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Stor
 		log:                log,
 		trust:              trust,
 		bandwidthDB:        bandwidth,
+		limits:             limits,
 		reputationDB:       reputationDB,
 		storageUsageDB:     storageUsageDB,
 		pricingDB:          pricingDB,
 		satelliteDB:        satelliteDB,
 		pieceStore:         pieceStore,
+		corruptPieces:      corruptPieces,
 		version:            version,
 		pingStats:          pingStats,
 		allocatedDiskSpace: allocatedDiskSpace,
@@ -119,8 +127,9 @@ type Dashboard struct {
 
 	Satellites []SatelliteInfo `json:"satellites"`
 
-	DiskSpace DiskSpaceInfo `json:"diskSpace"`
-	Bandwidth BandwidthInfo `json:"bandwidth"`
+	DiskSpace       DiskSpaceInfo    `json:"diskSpace"`
+	Bandwidth       BandwidthInfo    `json:"bandwidth"`
+	BandwidthBudget bandwidth.Budget `json:"bandwidthBudget"`
 
 	LastPinged time.Time `json:"lastPinged"`
 
@@ -190,6 +199,11 @@ func (s *Service) GetDashboardData(ctx context.Context) (_ *Dashboard, err error
 		Used: bandwidthUsage,
 	}
 
+	data.BandwidthBudget, err = s.limits.Budget(ctx)
+	if err != nil {
+		return nil, SNOServiceErr.Wrap(err)
+	}
+
 	return data, nil
 }
 
@@ -365,3 +379,17 @@ func (s *Service) VerifySatelliteID(ctx context.Context, satelliteID storj.NodeI
 
 	return nil
 }
+
+// GetCorruptPieces returns the pieces which the scrubber found corrupt.
+func (s *Service) GetCorruptPieces(ctx context.Context) (_ []pieces.CorruptPiece, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	corrupt, err := s.corruptPieces.List(ctx)
+	if err != nil {
+		return nil, SNOServiceErr.Wrap(err)
+	}
+	if corrupt == nil {
+		corrupt = []pieces.CorruptPiece{}
+	}
+	return corrupt, nil
+}
@@ -10,7 +10,9 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/common/rpc"
+	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/storagenode/notifications"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/satellites"
 	"storj.io/storj/storagenode/trust"
@@ -20,11 +22,12 @@ import (
 //
 // architecture: Chore
 type Chore struct {
-	log         *zap.Logger
-	store       *pieces.Store
-	satelliteDB satellites.DB
-	trust       *trust.Pool
-	dialer      rpc.Dialer
+	log           *zap.Logger
+	store         *pieces.Store
+	satelliteDB   satellites.DB
+	notifications *notifications.Service
+	trust         *trust.Pool
+	dialer        rpc.Dialer
 
 	config Config
 
@@ -34,16 +37,17 @@ type Chore struct {
 }
 
 // NewChore instantiates Chore.
-func NewChore(log *zap.Logger, config Config, store *pieces.Store, trust *trust.Pool, dialer rpc.Dialer, satelliteDB satellites.DB) *Chore {
+func NewChore(log *zap.Logger, config Config, store *pieces.Store, trust *trust.Pool, dialer rpc.Dialer, satelliteDB satellites.DB, notifications *notifications.Service) *Chore {
 	return &Chore{
-		log:         log,
-		store:       store,
-		satelliteDB: satelliteDB,
-		trust:       trust,
-		dialer:      dialer,
-		config:      config,
-		Loop:        sync2.NewCycle(config.ChoreInterval),
-		limiter:     sync2.NewLimiter(config.NumWorkers),
+		log:           log,
+		store:         store,
+		satelliteDB:   satelliteDB,
+		notifications: notifications,
+		trust:         trust,
+		dialer:        dialer,
+		config:        config,
+		Loop:          sync2.NewCycle(config.ChoreInterval),
+		limiter:       sync2.NewLimiter(config.NumWorkers),
 	}
 }
 
@@ -60,13 +64,21 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 			return nil
 		}
 
-		if len(satellites) == 0 {
+		shrinks, err := chore.satelliteDB.ListShrinks(ctx)
+		if err != nil {
+			chore.log.Error("error retrieving shrinks.", zap.Error(err))
+			return nil
+		}
+
+		if len(satellites) == 0 && len(shrinks) == 0 {
 			return nil
 		}
-		chore.log.Debug("exiting", zap.Int("satellites", len(satellites)))
+		chore.log.Debug("exiting", zap.Int("satellites", len(satellites)), zap.Int("shrinks", len(shrinks)))
 
+		exiting := make(map[storj.NodeID]bool, len(satellites))
 		for _, satellite := range satellites {
 			mon.Meter("satellite_gracefulexit_request").Mark(1) //locked
+			exiting[satellite.SatelliteID] = true
 			if satellite.FinishedAt != nil {
 				continue
 			}
@@ -77,27 +89,22 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 				continue
 			}
 
-			worker := NewWorker(chore.log, chore.store, chore.satelliteDB, chore.dialer, satelliteID, addr, chore.config)
-			if _, ok := chore.exitingMap.LoadOrStore(satelliteID, worker); ok {
-				// already running a worker for this satellite
-				chore.log.Debug("skipping for satellite, worker already exists.", zap.Stringer("Satellite ID", satelliteID))
+			chore.startWorker(ctx, NewWorker(chore.log, chore.store, chore.satelliteDB, chore.notifications, chore.dialer, satelliteID, addr, chore.config))
+		}
+
+		for _, shrink := range shrinks {
+			// the pieces of exiting satellites are all transferred
+			if shrink.FinishedAt != nil || exiting[shrink.SatelliteID] {
+				continue
+			}
+			satelliteID := shrink.SatelliteID
+			addr, err := chore.trust.GetAddress(ctx, satelliteID)
+			if err != nil {
+				chore.log.Error("failed to get satellite address.", zap.Error(err))
 				continue
 			}
 
-			chore.limiter.Go(ctx, func() {
-				err := worker.Run(ctx, func() {
-					chore.log.Debug("finished for satellite.", zap.Stringer("Satellite ID", satelliteID))
-					chore.exitingMap.Delete(satelliteID)
-				})
-
-				if err != nil {
-					chore.log.Error("worker failed", zap.Error(err))
-				}
-
-				if err := worker.Close(); err != nil {
-					chore.log.Error("closing worker failed", zap.Error(err))
-				}
-			})
+			chore.startWorker(ctx, NewShrinkWorker(chore.log, chore.store, chore.satelliteDB, chore.notifications, chore.dialer, satelliteID, addr, chore.config))
 		}
 		chore.limiter.Wait()
 
@@ -107,6 +114,31 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 	return err
 }
 
+// startWorker runs the worker, unless a worker is already running for its satellite.
+func (chore *Chore) startWorker(ctx context.Context, worker *Worker) {
+	satelliteID := worker.satelliteID
+	if _, ok := chore.exitingMap.LoadOrStore(satelliteID, worker); ok {
+		// already running a worker for this satellite
+		chore.log.Debug("skipping for satellite, worker already exists.", zap.Stringer("Satellite ID", satelliteID))
+		return
+	}
+
+	chore.limiter.Go(ctx, func() {
+		err := worker.Run(ctx, func() {
+			chore.log.Debug("finished for satellite.", zap.Stringer("Satellite ID", satelliteID))
+			chore.exitingMap.Delete(satelliteID)
+		})
+
+		if err != nil {
+			chore.log.Error("worker failed", zap.Error(err))
+		}
+
+		if err := worker.Close(); err != nil {
+			chore.log.Error("closing worker failed", zap.Error(err))
+		}
+	})
+}
+
 // Close closes chore.
 func (chore *Chore) Close() error {
 	chore.Loop.Close()
@@ -18,6 +18,7 @@ import (
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite/overlay"
 	"storj.io/storj/storage"
+	"storj.io/storj/storagenode/notifications"
 )
 
 func TestChore(t *testing.T) {
@@ -122,6 +123,17 @@ func exitSatellite(ctx context.Context, t *testing.T, planet *testplanet.Planet,
 		}
 	}
 
+	// the operator is notified about the completed exit.
+	page, err := exitingNode.Notifications.Service.List(ctx, notifications.Cursor{Limit: 10, Page: 1})
+	require.NoError(t, err)
+	var notified bool
+	for _, notification := range page.Notifications {
+		if notification.Type == notifications.TypeGracefulExitCompleted && notification.SenderID == satellite1.ID() {
+			notified = true
+		}
+	}
+	require.True(t, notified)
+
 	// make sure there are no more pieces on the node.
 	namespaces, err := exitingNode.DB.Pieces().ListNamespaces(ctx)
 	require.NoError(t, err)
@@ -71,3 +71,91 @@ func TestDB(t *testing.T) {
 		}
 	})
 }
+
+func TestDBCancel(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		nodeID := testrand.NodeID()
+
+		require.NoError(t, db.Satellites().InitiateGracefulExit(ctx, nodeID, time.Now(), 5000))
+		satellite, err := db.Satellites().GetSatellite(ctx, nodeID)
+		require.NoError(t, err)
+		require.EqualValues(t, satellites.Exiting, satellite.Status)
+
+		require.NoError(t, db.Satellites().CancelGracefulExit(ctx, nodeID))
+		exits, err := db.Satellites().ListGracefulExits(ctx)
+		require.NoError(t, err)
+		require.Empty(t, exits)
+
+		satellite, err = db.Satellites().GetSatellite(ctx, nodeID)
+		require.NoError(t, err)
+		require.EqualValues(t, satellites.Normal, satellite.Status)
+
+		// the exit can be initiated again
+		require.NoError(t, db.Satellites().InitiateGracefulExit(ctx, nodeID, time.Now(), 6000))
+		exits, err = db.Satellites().ListGracefulExits(ctx)
+		require.NoError(t, err)
+		require.Len(t, exits, 1)
+		require.EqualValues(t, 6000, exits[0].StartingDiskUsage)
+
+		// a cancelled exit is restored with its progress
+		require.NoError(t, db.Satellites().UpdateGracefulExit(ctx, nodeID, 1000))
+		exits, err = db.Satellites().ListGracefulExits(ctx)
+		require.NoError(t, err)
+		require.Len(t, exits, 1)
+		exit := exits[0]
+
+		require.NoError(t, db.Satellites().CancelGracefulExit(ctx, nodeID))
+		require.NoError(t, db.Satellites().RestoreGracefulExit(ctx, exit))
+		exits, err = db.Satellites().ListGracefulExits(ctx)
+		require.NoError(t, err)
+		require.Len(t, exits, 1)
+		require.EqualValues(t, 1000, exits[0].BytesDeleted)
+		require.EqualValues(t, 6000, exits[0].StartingDiskUsage)
+		require.True(t, exits[0].InitiatedAt.Equal(*exit.InitiatedAt))
+
+		satellite, err = db.Satellites().GetSatellite(ctx, nodeID)
+		require.NoError(t, err)
+		require.EqualValues(t, satellites.Exiting, satellite.Status)
+	})
+}
+
+func TestDBShrink(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		nodeID := testrand.NodeID()
+		start := time.Now()
+
+		require.NoError(t, db.Satellites().InitiateShrink(ctx, nodeID, start, 5000))
+		require.NoError(t, db.Satellites().UpdateShrink(ctx, nodeID, 1000))
+		require.NoError(t, db.Satellites().UpdateShrink(ctx, nodeID, 1000))
+
+		shrinks, err := db.Satellites().ListShrinks(ctx)
+		require.NoError(t, err)
+		require.Len(t, shrinks, 1)
+		require.Equal(t, nodeID, shrinks[0].SatelliteID)
+		require.Equal(t, int64(5000), shrinks[0].TargetBytes)
+		require.Equal(t, int64(2000), shrinks[0].BytesDeleted)
+		require.True(t, shrinks[0].InitiatedAt.Equal(start))
+		require.Nil(t, shrinks[0].FinishedAt)
+
+		stop := time.Now()
+		require.NoError(t, db.Satellites().CompleteShrink(ctx, nodeID, stop))
+		shrinks, err = db.Satellites().ListShrinks(ctx)
+		require.NoError(t, err)
+		require.Len(t, shrinks, 1)
+		require.True(t, shrinks[0].FinishedAt.Equal(stop))
+
+		// a new shrink replaces the finished one.
+		require.NoError(t, db.Satellites().InitiateShrink(ctx, nodeID, stop, 3000))
+		shrinks, err = db.Satellites().ListShrinks(ctx)
+		require.NoError(t, err)
+		require.Len(t, shrinks, 1)
+		require.Equal(t, int64(3000), shrinks[0].TargetBytes)
+		require.Equal(t, int64(0), shrinks[0].BytesDeleted)
+		require.Nil(t, shrinks[0].FinishedAt)
+
+		// the shrink isn't a graceful exit.
+		exits, err := db.Satellites().ListGracefulExits(ctx)
+		require.NoError(t, err)
+		require.Empty(t, exits)
+	})
+}
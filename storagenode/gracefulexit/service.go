@@ -0,0 +1,419 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/rpc"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/gracefulexitpb"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/satellites"
+	"storj.io/storj/storagenode/trust"
+)
+
+var (
+	// ErrNotExiting is returned when the node isn't exiting the satellite.
+	ErrNotExiting = errs.Class("not exiting the satellite")
+	// ErrExitRejected is returned when the exit is already initiated or conflicts with a shrink.
+	ErrExitRejected = errs.Class("graceful exit rejected")
+	// ErrUntrusted is returned when the satellite isn't trusted.
+	ErrUntrusted = errs.Class("satellite untrusted")
+	// ErrCancelRejected is returned when the satellite doesn't allow cancelling the exit.
+	ErrCancelRejected = errs.Class("graceful exit can't be cancelled")
+	// ErrShrinkRejected is returned when the node or the satellite doesn't allow the shrink.
+	ErrShrinkRejected = errs.Class("shrink rejected")
+)
+
+// Satellite is a satellite which the node can gracefully exit.
+type Satellite struct {
+	ID         storj.NodeID `json:"id"`
+	DomainName string       `json:"domainName"`
+	SpaceUsed  int64        `json:"spaceUsed"`
+}
+
+// ExitProgress is the progress of a graceful exit from a satellite.
+type ExitProgress struct {
+	SatelliteID       storj.NodeID `json:"satelliteId"`
+	DomainName        string       `json:"domainName"`
+	InitiatedAt       *time.Time   `json:"initiatedAt"`
+	FinishedAt        *time.Time   `json:"finishedAt"`
+	StartingDiskUsage int64        `json:"startingDiskUsage"`
+	BytesDeleted      int64        `json:"bytesDeleted"`
+	PercentComplete   float32      `json:"percentComplete"`
+	Successful        bool         `json:"successful"`
+	CompletionReceipt []byte       `json:"-"`
+}
+
+// ShrinkProgress is the progress of a partial graceful exit from a satellite,
+// which moves the target bytes of its pieces to other nodes.
+type ShrinkProgress struct {
+	SatelliteID     storj.NodeID `json:"satelliteId"`
+	DomainName      string       `json:"domainName"`
+	TargetBytes     int64        `json:"targetBytes"`
+	InitiatedAt     time.Time    `json:"initiatedAt"`
+	FinishedAt      *time.Time   `json:"finishedAt"`
+	BytesDeleted    int64        `json:"bytesDeleted"`
+	PercentComplete float32      `json:"percentComplete"`
+}
+
+// Service initiates, reports and cancels the graceful exits of the node.
+// It also initiates and reports shrinks, which are partial graceful exits.
+//
+// architecture: Service
+type Service struct {
+	log         *zap.Logger
+	trust       *trust.Pool
+	satelliteDB satellites.DB
+	usageCache  *pieces.BlobsUsageCache
+	dialer      rpc.Dialer
+}
+
+// NewService creates a new graceful exit service.
+func NewService(log *zap.Logger, trust *trust.Pool, satelliteDB satellites.DB, usageCache *pieces.BlobsUsageCache, dialer rpc.Dialer) *Service {
+	return &Service{
+		log:         log,
+		trust:       trust,
+		satelliteDB: satelliteDB,
+		usageCache:  usageCache,
+		dialer:      dialer,
+	}
+}
+
+// ListSatellites returns the trusted satellites which the node hasn't started exiting.
+func (service *Service) ListSatellites(ctx context.Context) (_ []Satellite, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	trustedSatellites := service.trust.GetSatellites(ctx)
+
+	exitingSatellites, err := service.satelliteDB.ListGracefulExits(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	exiting := make(map[storj.NodeID]bool, len(exitingSatellites))
+	for _, exit := range exitingSatellites {
+		exiting[exit.SatelliteID] = true
+	}
+
+	available := make([]Satellite, 0, len(trustedSatellites))
+	for _, trusted := range trustedSatellites {
+		if exiting[trusted] {
+			continue
+		}
+
+		domain, err := service.trust.GetAddress(ctx, trusted)
+		if err != nil {
+			service.log.Debug("graceful exit: get satellite domain name", zap.Stringer("Satellite ID", trusted), zap.Error(err))
+			continue
+		}
+		_, piecesContentSize, err := service.usageCache.SpaceUsedBySatellite(ctx, trusted)
+		if err != nil {
+			service.log.Debug("graceful exit: get space used by satellite", zap.Stringer("Satellite ID", trusted), zap.Error(err))
+			continue
+		}
+		available = append(available, Satellite{
+			ID:         trusted,
+			DomainName: domain,
+			SpaceUsed:  piecesContentSize,
+		})
+	}
+	return available, nil
+}
+
+// InitiateExit marks the satellite as exiting, the chore starts the exit on its next run.
+func (service *Service) InitiateExit(ctx context.Context, satelliteID storj.NodeID) (_ ExitProgress, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	domain, err := service.trust.GetAddress(ctx, satelliteID)
+	if err != nil {
+		return ExitProgress{}, ErrUntrusted.Wrap(err)
+	}
+
+	exits, err := service.satelliteDB.ListGracefulExits(ctx)
+	if err != nil {
+		return ExitProgress{}, Error.Wrap(err)
+	}
+	for _, exit := range exits {
+		if exit.SatelliteID == satelliteID {
+			return ExitProgress{}, ErrExitRejected.New("graceful exit from %s is already initiated", satelliteID)
+		}
+	}
+
+	// the satellite keeps serving the shrink until it's finished
+	shrinks, err := service.satelliteDB.ListShrinks(ctx)
+	if err != nil {
+		return ExitProgress{}, Error.Wrap(err)
+	}
+	for _, shrink := range shrinks {
+		if shrink.SatelliteID == satelliteID && shrink.FinishedAt == nil {
+			return ExitProgress{}, ErrExitRejected.New("shrink from %s is in progress", satelliteID)
+		}
+	}
+
+	_, piecesContentSize, err := service.usageCache.SpaceUsedBySatellite(ctx, satelliteID)
+	if err != nil {
+		return ExitProgress{}, Error.Wrap(err)
+	}
+
+	now := time.Now().UTC()
+	err = service.satelliteDB.InitiateGracefulExit(ctx, satelliteID, now, piecesContentSize)
+	if err != nil {
+		return ExitProgress{}, Error.Wrap(err)
+	}
+
+	return ExitProgress{
+		SatelliteID:       satelliteID,
+		DomainName:        domain,
+		InitiatedAt:       &now,
+		StartingDiskUsage: piecesContentSize,
+	}, nil
+}
+
+// ListExits returns the progress of the graceful exits of the node.
+func (service *Service) ListExits(ctx context.Context) (_ []ExitProgress, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	exits, err := service.satelliteDB.ListGracefulExits(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	progress := make([]ExitProgress, 0, len(exits))
+	for _, exit := range exits {
+		domain, err := service.trust.GetAddress(ctx, exit.SatelliteID)
+		if err != nil {
+			service.log.Debug("graceful exit: get satellite domain name", zap.Stringer("Satellite ID", exit.SatelliteID), zap.Error(err))
+			continue
+		}
+		progress = append(progress, newExitProgress(exit, domain))
+	}
+	return progress, nil
+}
+
+// GetExit returns the progress of the graceful exit from the satellite.
+func (service *Service) GetExit(ctx context.Context, satelliteID storj.NodeID) (_ ExitProgress, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	exit, err := service.findExit(ctx, satelliteID)
+	if err != nil {
+		return ExitProgress{}, err
+	}
+
+	// the satellite may not be trusted anymore, the progress is still useful
+	domain, err := service.trust.GetAddress(ctx, satelliteID)
+	if err != nil {
+		service.log.Debug("graceful exit: get satellite domain name", zap.Stringer("Satellite ID", satelliteID), zap.Error(err))
+	}
+	return newExitProgress(exit, domain), nil
+}
+
+// CancelExit cancels the graceful exit from the satellite. The satellite
+// rejects the cancellation once it has collected the pieces of the node.
+func (service *Service) CancelExit(ctx context.Context, satelliteID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	exit, err := service.findExit(ctx, satelliteID)
+	if err != nil {
+		return err
+	}
+	if exit.FinishedAt != nil {
+		return ErrCancelRejected.New("graceful exit from %s has already finished", satelliteID)
+	}
+
+	addr, err := service.trust.GetAddress(ctx, satelliteID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	// remove the exit first, so that the chore doesn't start a worker which
+	// would initiate the exit again on the satellite.
+	err = service.satelliteDB.CancelGracefulExit(ctx, satelliteID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	err = service.cancelOnSatellite(ctx, satelliteID, addr)
+	if err != nil {
+		// restore the previous record, so that the progress isn't lost
+		restoreErr := service.satelliteDB.RestoreGracefulExit(ctx, exit)
+		if restoreErr != nil {
+			service.log.Error("graceful exit: failed to restore the exit after a failed cancel", zap.Stringer("Satellite ID", satelliteID), zap.Error(restoreErr))
+		}
+		return errs.Combine(err, Error.Wrap(restoreErr))
+	}
+
+	service.log.Info("graceful exit cancelled.", zap.Stringer("Satellite ID", satelliteID))
+	return nil
+}
+
+// cancelOnSatellite calls the cancel RPC of the satellite.
+func (service *Service) cancelOnSatellite(ctx context.Context, satelliteID storj.NodeID, addr string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	conn, err := service.dialer.DialAddressID(ctx, addr, satelliteID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(conn.Close())) }()
+
+	_, err = gracefulexitpb.NewDRPCGracefulExitCancelClient(conn).CancelGracefulExit(ctx, &gracefulexitpb.CancelGracefulExitRequest{})
+	if errs2.IsRPC(err, rpcstatus.FailedPrecondition) || errs2.IsRPC(err, rpcstatus.Aborted) {
+		return ErrCancelRejected.Wrap(err)
+	}
+	return Error.Wrap(err)
+}
+
+// InitiateShrink asks the satellite to move targetBytes of the pieces of the
+// node to other nodes. The node stays active, the chore transfers the pieces
+// once the satellite has queued them.
+func (service *Service) InitiateShrink(ctx context.Context, satelliteID storj.NodeID, targetBytes int64) (_ ShrinkProgress, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if targetBytes <= 0 {
+		return ShrinkProgress{}, ErrShrinkRejected.New("target bytes must be positive")
+	}
+
+	_, err = service.findExit(ctx, satelliteID)
+	if err == nil {
+		return ShrinkProgress{}, ErrShrinkRejected.New("node is exiting %s", satelliteID)
+	}
+	if !ErrNotExiting.Has(err) {
+		return ShrinkProgress{}, err
+	}
+
+	shrinks, err := service.satelliteDB.ListShrinks(ctx)
+	if err != nil {
+		return ShrinkProgress{}, Error.Wrap(err)
+	}
+	for _, shrink := range shrinks {
+		if shrink.SatelliteID == satelliteID && shrink.FinishedAt == nil {
+			return ShrinkProgress{}, ErrShrinkRejected.New("shrink from %s is already in progress", satelliteID)
+		}
+	}
+
+	domain, err := service.trust.GetAddress(ctx, satelliteID)
+	if err != nil {
+		return ShrinkProgress{}, Error.Wrap(err)
+	}
+
+	err = service.shrinkOnSatellite(ctx, satelliteID, domain, targetBytes)
+	if err != nil {
+		return ShrinkProgress{}, err
+	}
+
+	now := time.Now().UTC()
+	err = service.satelliteDB.InitiateShrink(ctx, satelliteID, now, targetBytes)
+	if err != nil {
+		return ShrinkProgress{}, Error.Wrap(err)
+	}
+
+	service.log.Info("shrink initiated.", zap.Stringer("Satellite ID", satelliteID), zap.Int64("target bytes", targetBytes))
+	return ShrinkProgress{
+		SatelliteID: satelliteID,
+		DomainName:  domain,
+		TargetBytes: targetBytes,
+		InitiatedAt: now,
+	}, nil
+}
+
+// ListShrinks returns the progress of the last shrink from every satellite.
+func (service *Service) ListShrinks(ctx context.Context) (_ []ShrinkProgress, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	shrinks, err := service.satelliteDB.ListShrinks(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	progress := make([]ShrinkProgress, 0, len(shrinks))
+	for _, shrink := range shrinks {
+		domain, err := service.trust.GetAddress(ctx, shrink.SatelliteID)
+		if err != nil {
+			service.log.Debug("graceful exit: get satellite domain name", zap.Stringer("Satellite ID", shrink.SatelliteID), zap.Error(err))
+			continue
+		}
+		progress = append(progress, newShrinkProgress(shrink, domain))
+	}
+	return progress, nil
+}
+
+// shrinkOnSatellite calls the shrink RPC of the satellite.
+func (service *Service) shrinkOnSatellite(ctx context.Context, satelliteID storj.NodeID, addr string, targetBytes int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	conn, err := service.dialer.DialAddressID(ctx, addr, satelliteID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(conn.Close())) }()
+
+	_, err = gracefulexitpb.NewDRPCGracefulExitShrinkClient(conn).InitiateShrink(ctx, &gracefulexitpb.InitiateShrinkRequest{
+		TargetBytes: targetBytes,
+	})
+	if errs2.IsRPC(err, rpcstatus.FailedPrecondition) || errs2.IsRPC(err, rpcstatus.InvalidArgument) {
+		return ErrShrinkRejected.Wrap(err)
+	}
+	return Error.Wrap(err)
+}
+
+// findExit returns the graceful exit record of the satellite.
+func (service *Service) findExit(ctx context.Context, satelliteID storj.NodeID) (_ satellites.ExitProgress, err error) {
+	exits, err := service.satelliteDB.ListGracefulExits(ctx)
+	if err != nil {
+		return satellites.ExitProgress{}, Error.Wrap(err)
+	}
+	for _, exit := range exits {
+		if exit.SatelliteID == satelliteID {
+			return exit, nil
+		}
+	}
+	return satellites.ExitProgress{}, ErrNotExiting.New("%s", satelliteID)
+}
+
+// newExitProgress converts the graceful exit record.
+func newExitProgress(exit satellites.ExitProgress, domain string) ExitProgress {
+	progress := ExitProgress{
+		SatelliteID:       exit.SatelliteID,
+		DomainName:        domain,
+		InitiatedAt:       exit.InitiatedAt,
+		FinishedAt:        exit.FinishedAt,
+		StartingDiskUsage: exit.StartingDiskUsage,
+		BytesDeleted:      exit.BytesDeleted,
+		CompletionReceipt: exit.CompletionReceipt,
+	}
+	if exit.StartingDiskUsage != 0 {
+		progress.PercentComplete = (float32(exit.BytesDeleted) / float32(exit.StartingDiskUsage)) * 100
+	}
+	if exit.Status == satellites.ExitSucceeded {
+		progress.Successful = true
+		progress.PercentComplete = float32(100)
+	}
+	return progress
+}
+
+// newShrinkProgress converts the shrink record.
+func newShrinkProgress(shrink satellites.ShrinkProgress, domain string) ShrinkProgress {
+	progress := ShrinkProgress{
+		SatelliteID:  shrink.SatelliteID,
+		DomainName:   domain,
+		TargetBytes:  shrink.TargetBytes,
+		InitiatedAt:  shrink.InitiatedAt,
+		FinishedAt:   shrink.FinishedAt,
+		BytesDeleted: shrink.BytesDeleted,
+	}
+	if shrink.TargetBytes != 0 {
+		progress.PercentComplete = (float32(shrink.BytesDeleted) / float32(shrink.TargetBytes)) * 100
+	}
+	if progress.PercentComplete > 100 || shrink.FinishedAt != nil {
+		progress.PercentComplete = float32(100)
+	}
+	return progress
+}
@@ -0,0 +1,181 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/storagenode/gracefulexit"
+)
+
+func TestServiceCancel(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 3, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		satellite.GracefulExit.Chore.Loop.Pause()
+
+		err := planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test/path", testrand.Bytes(5*memory.KiB))
+		require.NoError(t, err)
+
+		exitingNode := planet.StorageNodes[0]
+		exitingNode.GracefulExit.Chore.Loop.Pause()
+		service := exitingNode.GracefulExit.Service
+
+		satellites, err := service.ListSatellites(ctx)
+		require.NoError(t, err)
+		require.Len(t, satellites, 1)
+		require.Equal(t, satellite.ID(), satellites[0].ID)
+
+		err = service.CancelExit(ctx, satellite.ID())
+		require.True(t, gracefulexit.ErrNotExiting.Has(err))
+
+		// cancel before the satellite knows about the exit.
+		progress, err := service.InitiateExit(ctx, satellite.ID())
+		require.NoError(t, err)
+		require.Equal(t, satellite.ID(), progress.SatelliteID)
+
+		_, err = service.InitiateExit(ctx, satellite.ID())
+		require.True(t, gracefulexit.ErrExitRejected.Has(err))
+
+		_, err = service.InitiateExit(ctx, testrand.NodeID())
+		require.True(t, gracefulexit.ErrUntrusted.Has(err))
+
+		satellites, err = service.ListSatellites(ctx)
+		require.NoError(t, err)
+		require.Empty(t, satellites)
+
+		require.NoError(t, service.CancelExit(ctx, satellite.ID()))
+
+		exits, err := service.ListExits(ctx)
+		require.NoError(t, err)
+		require.Empty(t, exits)
+
+		// cancel after the satellite has initiated the exit.
+		_, err = service.InitiateExit(ctx, satellite.ID())
+		require.NoError(t, err)
+		exitingNode.GracefulExit.Chore.Loop.TriggerWait()
+
+		status, err := satellite.Overlay.DB.GetExitStatus(ctx, exitingNode.ID())
+		require.NoError(t, err)
+		require.NotNil(t, status.ExitInitiatedAt)
+
+		require.NoError(t, service.CancelExit(ctx, satellite.ID()))
+
+		status, err = satellite.Overlay.DB.GetExitStatus(ctx, exitingNode.ID())
+		require.NoError(t, err)
+		require.Nil(t, status.ExitInitiatedAt)
+
+		// the cancel is rejected once the exit loop has completed.
+		_, err = service.InitiateExit(ctx, satellite.ID())
+		require.NoError(t, err)
+		exitingNode.GracefulExit.Chore.Loop.TriggerWait()
+		satellite.GracefulExit.Chore.Loop.TriggerWait()
+
+		status, err = satellite.Overlay.DB.GetExitStatus(ctx, exitingNode.ID())
+		require.NoError(t, err)
+		require.NotNil(t, status.ExitLoopCompletedAt)
+
+		err = service.CancelExit(ctx, satellite.ID())
+		require.True(t, gracefulexit.ErrCancelRejected.Has(err))
+
+		// the exit is kept on the node.
+		exit, err := service.GetExit(ctx, satellite.ID())
+		require.NoError(t, err)
+		require.Nil(t, exit.FinishedAt)
+	})
+}
+
+func TestServiceShrink(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 5,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 3, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		satellite.GracefulExit.Chore.Loop.Pause()
+
+		for i := 0; i < 4; i++ {
+			err := planet.Uplinks[0].Upload(ctx, satellite, "testbucket", fmt.Sprintf("test/path%d", i), testrand.Bytes(5*memory.KiB))
+			require.NoError(t, err)
+		}
+
+		// shrink a node which stores pieces.
+		var shrinkingNode *testplanet.StorageNode
+		for _, node := range planet.StorageNodes {
+			_, used, err := node.Storage2.BlobsCache.SpaceUsedBySatellite(ctx, satellite.ID())
+			require.NoError(t, err)
+			if used > 0 {
+				shrinkingNode = node
+				break
+			}
+		}
+		require.NotNil(t, shrinkingNode)
+		shrinkingNode.GracefulExit.Chore.Loop.Pause()
+		service := shrinkingNode.GracefulExit.Service
+
+		// a single byte queues a single piece.
+		progress, err := service.InitiateShrink(ctx, satellite.ID(), 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), progress.TargetBytes)
+
+		_, err = service.InitiateShrink(ctx, satellite.ID(), 1)
+		require.True(t, gracefulexit.ErrShrinkRejected.Has(err))
+
+		_, err = service.InitiateExit(ctx, satellite.ID())
+		require.True(t, gracefulexit.ErrExitRejected.Has(err))
+
+		// the pieces aren't queued yet.
+		shrinkingNode.GracefulExit.Chore.Loop.TriggerWait()
+		satellite.GracefulExit.Chore.Loop.TriggerWait()
+
+		shrink, err := satellite.DB.GracefulExit().GetShrink(ctx, shrinkingNode.ID())
+		require.NoError(t, err)
+		require.NotNil(t, shrink.LoopCompletedAt)
+		require.True(t, shrink.QueuedBytes > 0)
+
+		incomplete, err := satellite.DB.GracefulExit().GetIncomplete(ctx, shrinkingNode.ID(), 10, 0)
+		require.NoError(t, err)
+		require.Len(t, incomplete, 1)
+
+		// transfer the piece, then record the finished shrink.
+		shrinkingNode.GracefulExit.Chore.Loop.TriggerWait()
+		shrinkingNode.GracefulExit.Chore.Loop.TriggerWait()
+
+		shrink, err = satellite.DB.GracefulExit().GetShrink(ctx, shrinkingNode.ID())
+		require.NoError(t, err)
+		require.NotNil(t, shrink.FinishedAt)
+		require.Equal(t, shrink.QueuedBytes, shrink.BytesTransferred)
+
+		shrinks, err := service.ListShrinks(ctx)
+		require.NoError(t, err)
+		require.Len(t, shrinks, 1)
+		require.NotNil(t, shrinks[0].FinishedAt)
+		require.True(t, shrinks[0].BytesDeleted > 0)
+
+		// the node stays active.
+		status, err := satellite.Overlay.DB.GetExitStatus(ctx, shrinkingNode.ID())
+		require.NoError(t, err)
+		require.Nil(t, status.ExitInitiatedAt)
+
+		exits, err := service.ListExits(ctx)
+		require.NoError(t, err)
+		require.Empty(t, exits)
+	})
+}
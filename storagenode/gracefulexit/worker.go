@@ -6,6 +6,7 @@ package gracefulexit
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"time"
@@ -21,6 +22,8 @@ import (
 	"storj.io/common/signing"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/pkg/gracefulexitpb"
+	"storj.io/storj/storagenode/notifications"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/piecestore"
 	"storj.io/storj/storagenode/satellites"
@@ -28,10 +31,12 @@ import (
 )
 
 // Worker is responsible for completing the graceful exit for a given satellite.
+// A shrinking worker only transfers the pieces queued for the shrink.
 type Worker struct {
 	log                *zap.Logger
 	store              *pieces.Store
 	satelliteDB        satellites.DB
+	notifications      *notifications.Service
 	dialer             rpc.Dialer
 	limiter            *sync2.Limiter
 	satelliteID        storj.NodeID
@@ -39,14 +44,16 @@ type Worker struct {
 	ecclient           ecclient.Client
 	minBytesPerSecond  memory.Size
 	minDownloadTimeout time.Duration
+	shrinking          bool
 }
 
 // NewWorker instantiates Worker.
-func NewWorker(log *zap.Logger, store *pieces.Store, satelliteDB satellites.DB, dialer rpc.Dialer, satelliteID storj.NodeID, satelliteAddr string, config Config) *Worker {
+func NewWorker(log *zap.Logger, store *pieces.Store, satelliteDB satellites.DB, notifications *notifications.Service, dialer rpc.Dialer, satelliteID storj.NodeID, satelliteAddr string, config Config) *Worker {
 	return &Worker{
 		log:                log,
 		store:              store,
 		satelliteDB:        satelliteDB,
+		notifications:      notifications,
 		dialer:             dialer,
 		limiter:            sync2.NewLimiter(config.NumConcurrentTransfers),
 		satelliteID:        satelliteID,
@@ -57,6 +64,13 @@ func NewWorker(log *zap.Logger, store *pieces.Store, satelliteDB satellites.DB,
 	}
 }
 
+// NewShrinkWorker instantiates a Worker which transfers the pieces queued for the shrink from the satellite.
+func NewShrinkWorker(log *zap.Logger, store *pieces.Store, satelliteDB satellites.DB, notifications *notifications.Service, dialer rpc.Dialer, satelliteID storj.NodeID, satelliteAddr string, config Config) *Worker {
+	worker := NewWorker(log, store, satelliteDB, notifications, dialer, satelliteID, satelliteAddr, config)
+	worker.shrinking = true
+	return worker
+}
+
 // Run calls the satellite endpoint, transfers pieces, validates, and responds with success or failure.
 // It also marks the satellite finished once all the pieces have been transferred
 func (worker *Worker) Run(ctx context.Context, done func()) (err error) {
@@ -73,9 +87,20 @@ func (worker *Worker) Run(ctx context.Context, done func()) (err error) {
 		err = errs.Combine(err, conn.Close())
 	}()
 
-	client := pb.NewDRPCSatelliteGracefulExitClient(conn)
+	if worker.shrinking {
+		queued, err := worker.checkShrink(ctx, conn)
+		if err != nil || !queued {
+			return errs.Wrap(err)
+		}
+	}
 
-	c, err := client.Process(ctx)
+	// the shrink has its own stream, so that it never initiates an exit
+	var c pb.DRPCSatelliteGracefulExit_ProcessClient
+	if worker.shrinking {
+		c, err = gracefulexitpb.NewDRPCGracefulExitShrinkClient(conn).ProcessShrink(ctx)
+	} else {
+		c, err = pb.NewDRPCSatelliteGracefulExitClient(conn).Process(ctx)
+	}
 	if err != nil {
 		return errs.Wrap(err)
 	}
@@ -86,6 +111,9 @@ func (worker *Worker) Run(ctx context.Context, done func()) (err error) {
 			// Done
 			return nil
 		}
+		if errs2.IsRPC(err, rpcstatus.FailedPrecondition) && worker.shrinking {
+			return errs.Wrap(err)
+		}
 		if errs2.IsRPC(err, rpcstatus.FailedPrecondition) {
 			// delete the entry from satellite table and inform graceful exit has failed to start
 			deleteErr := worker.satelliteDB.CancelGracefulExit(ctx, worker.satelliteID)
@@ -138,7 +166,12 @@ func (worker *Worker) Run(ctx context.Context, done func()) (err error) {
 				worker.log.Error("failed to marshal exit failed message.")
 			}
 			err = worker.satelliteDB.CompleteGracefulExit(ctx, worker.satelliteID, time.Now(), satellites.ExitFailed, exitFailedBytes)
-			return errs.Wrap(err)
+			if err != nil {
+				return errs.Wrap(err)
+			}
+			worker.notify(ctx, notifications.TypeGracefulExitFailed, "Graceful exit failed",
+				fmt.Sprintf("The graceful exit from satellite %s failed: %s.", worker.satelliteAddr, msg.ExitFailed.Reason))
+			return nil
 
 		case *pb.SatelliteMessage_ExitCompleted:
 			worker.log.Info("graceful exit completed.", zap.Stringer("Satellite ID", worker.satelliteID))
@@ -152,6 +185,9 @@ func (worker *Worker) Run(ctx context.Context, done func()) (err error) {
 			if err != nil {
 				return errs.Wrap(err)
 			}
+			worker.notify(ctx, notifications.TypeGracefulExitCompleted, "Graceful exit completed",
+				fmt.Sprintf("The graceful exit from satellite %s completed, its completion receipt is available in the dashboard.", worker.satelliteAddr))
+
 			// delete all remaining pieces
 			err = worker.deleteAllPieces(ctx)
 			return errs.Wrap(err)
@@ -163,6 +199,44 @@ func (worker *Worker) Run(ctx context.Context, done func()) (err error) {
 	}
 }
 
+// checkShrink returns whether the satellite has queued the pieces of the
+// shrink, and marks the shrink finished once the satellite finished it, so
+// that the worker doesn't open the shrink stream for a finished shrink.
+func (worker *Worker) checkShrink(ctx context.Context, conn *rpc.Conn) (queued bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	status, err := gracefulexitpb.NewDRPCGracefulExitShrinkClient(conn).GetShrinkStatus(ctx, &gracefulexitpb.GetShrinkStatusRequest{})
+	if errs2.IsRPC(err, rpcstatus.NotFound) {
+		// the satellite doesn't know about the shrink anymore
+		status, err = &gracefulexitpb.GetShrinkStatusResponse{Finished: true}, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if status.Finished {
+		worker.log.Info("shrink finished.",
+			zap.Stringer("Satellite ID", worker.satelliteID),
+			zap.Int64("bytes transferred", status.BytesTransferred))
+		return false, worker.satelliteDB.CompleteShrink(ctx, worker.satelliteID, time.Now())
+	}
+	return status.Queued, nil
+}
+
+// notify adds a notification about the end of the exit. Failing to add it
+// doesn't fail the exit.
+func (worker *Worker) notify(ctx context.Context, notificationType notifications.Type, title, message string) {
+	_, err := worker.notifications.Receive(ctx, notifications.NewNotification{
+		SenderID: worker.satelliteID,
+		Type:     notificationType,
+		Title:    title,
+		Message:  message,
+	})
+	if err != nil {
+		worker.log.Error("failed to add graceful exit notification.", zap.Stringer("Satellite ID", worker.satelliteID), zap.Error(err))
+	}
+}
+
 type gracefulExitStream interface {
 	Context() context.Context
 	Send(*pb.StorageNodeMessage) error
@@ -291,6 +365,9 @@ func (worker *Worker) deleteOnePiece(ctx context.Context, pieceID storj.PieceID)
 	}
 	// update graceful exit progress
 	size := piece.Size()
+	if worker.shrinking {
+		return worker.satelliteDB.UpdateShrink(ctx, worker.satelliteID, size)
+	}
 	return worker.satelliteDB.UpdateGracefulExit(ctx, worker.satelliteID, size)
 }
 
@@ -56,6 +56,7 @@ type PayStub struct {
 
 // Heldback is node's heldback amount for period.
 type Heldback struct {
-	Period string `json:"period"`
-	Held   int64  `json:"held"`
+	Period   string `json:"period"`
+	Held     int64  `json:"held"`
+	Disposed int64  `json:"disposed"`
 }
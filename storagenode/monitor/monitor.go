@@ -43,6 +43,7 @@ type Service struct {
 	store              *pieces.Store
 	contact            *contact.Service
 	usageDB            bandwidth.DB
+	limits             *bandwidth.Limits
 	allocatedDiskSpace int64
 	cooldown           *sync2.Cooldown
 	Loop               *sync2.Cycle
@@ -50,12 +51,13 @@ type Service struct {
 }
 
 // NewService creates a new storage node monitoring service.
-func NewService(log *zap.Logger, store *pieces.Store, contact *contact.Service, usageDB bandwidth.DB, allocatedDiskSpace int64, interval time.Duration, reportCapacity func(context.Context), config Config) *Service {
+func NewService(log *zap.Logger, store *pieces.Store, contact *contact.Service, usageDB bandwidth.DB, limits *bandwidth.Limits, allocatedDiskSpace int64, interval time.Duration, reportCapacity func(context.Context), config Config) *Service {
 	return &Service{
 		log:                log,
 		store:              store,
 		contact:            contact,
 		usageDB:            usageDB,
+		limits:             limits,
 		allocatedDiskSpace: allocatedDiskSpace,
 		cooldown:           sync2.NewCooldown(config.NotifyLowDiskCooldown),
 		Loop:               sync2.NewCycle(interval),
@@ -153,8 +155,31 @@ func (service *Service) updateNodeInformation(ctx context.Context) (err error) {
 		return Error.Wrap(err)
 	}
 
+	budget, err := service.limits.Budget(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	// without a budget the free bandwidth isn't advertised
+	var freeBandwidth int64
+	switch {
+	case budget.IngressLimit > 0 && budget.EgressLimit > 0:
+		freeBandwidth = min(budget.IngressRemaining, budget.EgressRemaining)
+	case budget.IngressLimit > 0:
+		freeBandwidth = budget.IngressRemaining
+	case budget.EgressLimit > 0:
+		freeBandwidth = budget.EgressRemaining
+	}
+
+	freeDisk := service.allocatedDiskSpace - usedSpace
+	if budget.IngressExhausted() {
+		// uploads are rejected until the next month
+		freeDisk = 0
+	}
+
 	service.contact.UpdateSelf(&pb.NodeCapacity{
-		FreeDisk: service.allocatedDiskSpace - usedSpace,
+		FreeBandwidth: freeBandwidth,
+		FreeDisk:      freeDisk,
 	})
 
 	return nil
@@ -184,3 +209,10 @@ func (service *Service) AvailableSpace(ctx context.Context) (_ int64, err error)
 
 	return allocatedSpace - usedSpace, nil
 }
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -4,16 +4,28 @@
 package monitor_test
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 
 	"storj.io/common/memory"
 	"storj.io/common/pb"
+	"storj.io/common/rpc"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/contact"
+	"storj.io/storj/storagenode/monitor"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
 )
 
 func TestMonitor(t *testing.T) {
@@ -41,3 +53,51 @@ func TestMonitor(t *testing.T) {
 		assert.NotZero(t, nodeAssertions, "No storage node were verifed")
 	})
 }
+
+func TestMonitorLostDirectory(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		log := zaptest.NewLogger(t)
+
+		dirs := []string{ctx.Dir("a"), ctx.Dir("b")}
+		blobstore, err := filestore.NewMulti(log, filestore.WeightedPaths{
+			{Path: dirs[0], Weight: 1},
+			{Path: dirs[1], Weight: 1},
+		}, filestore.DefaultConfig)
+		require.NoError(t, err)
+
+		// lose the second directory, so that reading it fails
+		require.NoError(t, os.RemoveAll(dirs[1]))
+
+		contactService := contact.NewService(log, rpc.Dialer{}, &overlay.NodeDossier{}, nil)
+		service := monitor.NewService(log,
+			pieces.NewStore(log, blobstore, nil, nil, db.PieceSpaceUsedDB(), pieces.DefaultConfig),
+			contactService,
+			db.Bandwidth(),
+			bandwidth.NewLimits(db.Bandwidth(), bandwidth.LimitsConfig{}),
+			memory.GB.Int64(),
+			time.Hour,
+			nil,
+			monitor.Config{NotifyLowDiskCooldown: time.Hour},
+		)
+
+		stopped := make(chan error, 1)
+		go func() { stopped <- service.Run(ctx) }()
+
+		updated := make(chan struct{})
+		go func() {
+			service.Loop.TriggerWait()
+			close(updated)
+		}()
+
+		// the monitor keeps running with the remaining directory
+		select {
+		case err := <-stopped:
+			t.Fatalf("monitor stopped: %v", err)
+		case <-updated:
+		}
+		assert.NotZero(t, contactService.Local().Capacity.FreeDisk)
+
+		require.NoError(t, service.Close())
+		require.NoError(t, <-stopped)
+	})
+}
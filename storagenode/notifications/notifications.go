@@ -37,6 +37,10 @@ const (
 	TypeDisqualification Type = 3
 	// TypeSuspension is a notification type which describes node's suspension status.
 	TypeSuspension Type = 4
+	// TypeGracefulExitCompleted is a notification type which describes a completed graceful exit.
+	TypeGracefulExitCompleted Type = 5
+	// TypeGracefulExitFailed is a notification type which describes a failed graceful exit.
+	TypeGracefulExitFailed Type = 6
 )
 
 // NewNotification holds notification entity info which is being received from satellite or local client.
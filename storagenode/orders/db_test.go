@@ -109,6 +109,11 @@ func TestDB(t *testing.T) {
 		}
 		require.Empty(t, cmp.Diff(expectedGrouped, unsentGrouped, cmp.Comparer(pb.Equal)))
 
+		// count by group
+		unsentCounts, err := ordersdb.CountUnsentBySatellite(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[storj.NodeID]int64{satellite0.ID: 2}, unsentCounts)
+
 		// test archival
 		archivedAt := time.Now().UTC()
 		err = ordersdb.Archive(ctx, archivedAt, orders.ArchiveRequest{
@@ -219,6 +224,12 @@ func TestDB_Trivial(t *testing.T) {
 			require.Len(t, infos[satelliteID], 1)
 		}
 
+		{ // Ensure CountUnsentBySatellite works at all
+			counts, err := db.Orders().CountUnsentBySatellite(ctx)
+			require.NoError(t, err)
+			require.Equal(t, map[storj.NodeID]int64{satelliteID: 1}, counts)
+		}
+
 		{ // Ensure Archive works at all
 			err := db.Orders().Archive(ctx, before.UTC(), orders.ArchiveRequest{satelliteID, serial, orders.StatusAccepted})
 			require.NoError(t, err)
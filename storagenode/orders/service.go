@@ -72,6 +72,8 @@ type DB interface {
 	ListUnsent(ctx context.Context, limit int) ([]*Info, error)
 	// ListUnsentBySatellite returns orders that haven't been sent yet grouped by satellite.
 	ListUnsentBySatellite(ctx context.Context) (map[storj.NodeID][]*Info, error)
+	// CountUnsentBySatellite returns the number of orders that haven't been sent yet by satellite.
+	CountUnsentBySatellite(ctx context.Context) (map[storj.NodeID]int64, error)
 
 	// Archive marks order as being handled.
 	Archive(ctx context.Context, archivedAt time.Time, requests ...ArchiveRequest) error
@@ -32,10 +32,13 @@ import (
 	"storj.io/storj/satellite/overlay"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/packstore"
+	"storj.io/storj/storagenode/apikeys"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/collector"
 	"storj.io/storj/storagenode/console"
 	"storj.io/storj/storagenode/console/consoleassets"
+	"storj.io/storj/storagenode/console/consolemetrics"
 	"storj.io/storj/storagenode/console/consoleserver"
 	"storj.io/storj/storagenode/contact"
 	"storj.io/storj/storagenode/gracefulexit"
@@ -85,6 +88,9 @@ type DB interface {
 	Notifications() notifications.DB
 	HeldAmount() heldamount.DB
 	Pricing() pricing.DB
+	CorruptPieces() pieces.CorruptPiecesDB
+	PackIndex() packstore.Index
+	APIKeys() apikeys.DB
 
 	Preflight(ctx context.Context) error
 }
@@ -106,8 +112,10 @@ type Config struct {
 	Collector collector.Config
 
 	Filestore filestore.Config
+	Packstore packstore.Config
 
-	Pieces pieces.Config
+	Pieces   pieces.Config
+	Scrubber pieces.ScrubberConfig
 
 	Retain retain.Config
 
@@ -129,11 +137,14 @@ func (config *Config) DatabaseConfig() storagenodedb.Config {
 		dbdir = config.Storage.Path
 	}
 	return storagenodedb.Config{
-		Storage:   config.Storage.Path,
-		Info:      filepath.Join(dbdir, "piecestore.db"),
-		Info2:     filepath.Join(dbdir, "info.db"),
-		Pieces:    config.Storage.Path,
-		Filestore: config.Filestore,
+		Storage:     config.Storage.Path,
+		Info:        filepath.Join(dbdir, "piecestore.db"),
+		Info2:       filepath.Join(dbdir, "info.db"),
+		Pieces:      config.Storage.Path,
+		ExtraPieces: config.Storage.ExtraPaths,
+		Filestore:   config.Filestore,
+		Backend:     config.Storage.Backend,
+		Packstore:   config.Packstore,
 	}
 }
 
@@ -224,6 +235,9 @@ type Peer struct {
 		TrashChore    *pieces.TrashChore
 		BlobsCache    *pieces.BlobsUsageCache
 		CacheService  *pieces.CacheService
+		Scrubber      *pieces.Scrubber
+		PackChore     *packstore.Chore
+		Limits        *bandwidth.Limits
 		RetainService *retain.Service
 		PieceDeleter  *pieces.Deleter
 		Endpoint      *piecestore.Endpoint
@@ -243,10 +257,13 @@ type Peer struct {
 	Console struct {
 		Listener net.Listener
 		Service  *console.Service
+		Metrics  *consolemetrics.Service
+		APIKeys  *apikeys.Service
 		Endpoint *consoleserver.Server
 	}
 
 	GracefulExit struct {
+		Service  *gracefulexit.Service
 		Endpoint *gracefulexit.Endpoint
 		Chore    *gracefulexit.Chore
 	}
@@ -448,11 +465,43 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		peer.Debug.Server.Panel.Add(
 			debug.Cycle("Piecestore Cache", peer.Storage2.CacheService.Loop))
 
+		peer.Storage2.Scrubber = pieces.NewScrubber(
+			log.Named("pieces:scrubber"),
+			peer.Storage2.Store,
+			peer.DB.CorruptPieces(),
+			config.Scrubber,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "pieces:scrubber",
+			Run:   peer.Storage2.Scrubber.Run,
+			Close: peer.Storage2.Scrubber.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Pieces Scrubber", peer.Storage2.Scrubber.Loop))
+
+		if packs, ok := peer.DB.Pieces().(*packstore.Store); ok {
+			peer.Storage2.PackChore = packstore.NewChore(
+				log.Named("packstore:compaction"),
+				packs,
+				config.Packstore.CompactionInterval,
+			)
+			peer.Services.Add(lifecycle.Item{
+				Name:  "packstore:compaction",
+				Run:   peer.Storage2.PackChore.Run,
+				Close: peer.Storage2.PackChore.Close,
+			})
+			peer.Debug.Server.Panel.Add(
+				debug.Cycle("Packstore Compaction", peer.Storage2.PackChore.Loop))
+		}
+
+		peer.Storage2.Limits = bandwidth.NewLimits(peer.DB.Bandwidth(), config.Storage2.Bandwidth)
+
 		peer.Storage2.Monitor = monitor.NewService(
 			log.Named("piecestore:monitor"),
 			peer.Storage2.Store,
 			peer.Contact.Service,
 			peer.DB.Bandwidth(),
+			peer.Storage2.Limits,
 			config.Storage.AllocatedDiskSpace.Int64(),
 			//TODO use config.Storage.Monitor.Interval, but for some reason is not set
 			config.Storage.KBucketRefreshInterval,
@@ -489,6 +538,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			peer.Storage2.PieceDeleter,
 			peer.DB.Orders(),
 			peer.DB.Bandwidth(),
+			peer.Storage2.Limits,
 			peer.DB.UsedSerials(),
 			config.Storage2,
 		)
@@ -570,10 +620,46 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			debug.Cycle("Node Stats Cache Storage", peer.NodeStats.Cache.Storage))
 	}
 
+	{ // setup graceful exit service
+		peer.GracefulExit.Service = gracefulexit.NewService(
+			peer.Log.Named("gracefulexit:service"),
+			peer.Storage2.Trust,
+			peer.DB.Satellites(),
+			peer.Storage2.BlobsCache,
+			peer.Dialer,
+		)
+
+		peer.GracefulExit.Endpoint = gracefulexit.NewEndpoint(
+			peer.Log.Named("gracefulexit:endpoint"),
+			peer.GracefulExit.Service,
+		)
+		if err := pb.DRPCRegisterNodeGracefulExit(peer.Server.PrivateDRPC(), peer.GracefulExit.Endpoint); err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
+		peer.GracefulExit.Chore = gracefulexit.NewChore(
+			peer.Log.Named("gracefulexit:chore"),
+			config.GracefulExit,
+			peer.Storage2.Store,
+			peer.Storage2.Trust,
+			peer.Dialer,
+			peer.DB.Satellites(),
+			peer.Notifications.Service,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "gracefulexit:chore",
+			Run:   peer.GracefulExit.Chore.Run,
+			Close: peer.GracefulExit.Chore.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Graceful Exit", peer.GracefulExit.Chore.Loop))
+	}
+
 	{ // setup storage node operator dashboard
 		peer.Console.Service, err = console.NewService(
 			peer.Log.Named("console:service"),
 			peer.DB.Bandwidth(),
+			peer.Storage2.Limits,
 			peer.Storage2.Store,
 			peer.Version.Service,
 			config.Storage.AllocatedDiskSpace,
@@ -584,6 +670,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			peer.DB.StorageUsage(),
 			peer.DB.Pricing(),
 			peer.DB.Satellites(),
+			peer.DB.CorruptPieces(),
 			peer.Contact.PingStats,
 			peer.Contact.Service,
 		)
@@ -602,14 +689,39 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			assets = http.Dir(config.Console.StaticDir)
 		}
 
-		peer.Console.Endpoint = consoleserver.NewServer(
+		if config.Console.Metrics {
+			peer.Console.Metrics = consolemetrics.NewService(
+				peer.Log.Named("console:metrics"),
+				consolemetrics.Databases{
+					Bandwidth:  peer.DB.Bandwidth(),
+					Reputation: peer.DB.Reputation(),
+					HeldAmount: peer.DB.HeldAmount(),
+					Orders:     peer.DB.Orders(),
+				},
+				peer.Storage2.Store,
+				config.Storage.AllocatedDiskSpace,
+				peer.Storage2.RetainService,
+				peer.Storage2.PieceDeleter,
+			)
+		}
+
+		peer.Console.APIKeys = apikeys.NewService(peer.Log.Named("console:apikeys"), peer.DB.APIKeys())
+
+		peer.Console.Endpoint, err = consoleserver.NewServer(
 			peer.Log.Named("console:endpoint"),
+			config.Console,
 			assets,
 			peer.Notifications.Service,
 			peer.Console.Service,
 			peer.Heldamount.Service,
+			peer.GracefulExit.Service,
+			peer.Console.Metrics,
+			peer.Console.APIKeys,
 			peer.Console.Listener,
 		)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
 		peer.Services.Add(lifecycle.Item{
 			Name:  "console:endpoint",
 			Run:   peer.Console.Endpoint.Run,
@@ -633,34 +745,6 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		}
 	}
 
-	{ // setup graceful exit service
-		peer.GracefulExit.Endpoint = gracefulexit.NewEndpoint(
-			peer.Log.Named("gracefulexit:endpoint"),
-			peer.Storage2.Trust,
-			peer.DB.Satellites(),
-			peer.Storage2.BlobsCache,
-		)
-		if err := pb.DRPCRegisterNodeGracefulExit(peer.Server.PrivateDRPC(), peer.GracefulExit.Endpoint); err != nil {
-			return nil, errs.Combine(err, peer.Close())
-		}
-
-		peer.GracefulExit.Chore = gracefulexit.NewChore(
-			peer.Log.Named("gracefulexit:chore"),
-			config.GracefulExit,
-			peer.Storage2.Store,
-			peer.Storage2.Trust,
-			peer.Dialer,
-			peer.DB.Satellites(),
-		)
-		peer.Services.Add(lifecycle.Item{
-			Name:  "gracefulexit:chore",
-			Run:   peer.GracefulExit.Chore.Run,
-			Close: peer.GracefulExit.Chore.Close,
-		})
-		peer.Debug.Server.Panel.Add(
-			debug.Cycle("Graceful Exit", peer.GracefulExit.Chore.Loop))
-	}
-
 	peer.Collector = collector.NewService(peer.Log.Named("collector"), peer.Storage2.Store, peer.DB.UsedSerials(), config.Collector)
 	peer.Services.Add(lifecycle.Item{
 		Name:  "collector",
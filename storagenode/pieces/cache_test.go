@@ -4,6 +4,8 @@
 package pieces_test
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
@@ -264,6 +266,107 @@ func TestCachServiceRun(t *testing.T) {
 	})
 }
 
+func TestCacheServiceRunMultipleDirectories(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		blobstore, err := filestore.NewMulti(log, filestore.WeightedPaths{
+			{Path: ctx.Dir("a"), Weight: 1},
+			{Path: ctx.Dir("b"), Weight: 1},
+		}, filestore.DefaultConfig)
+		require.NoError(t, err)
+
+		// write pieces which end up spread across both directories
+		const pieceCount = 10
+		blobSize := memory.KB
+		for i := 0; i < pieceCount; i++ {
+			w, err := blobstore.Create(ctx, storage.BlobRef{
+				Namespace: testrand.NodeID().Bytes(),
+				Key:       testrand.PieceID().Bytes(),
+			}, -1)
+			require.NoError(t, err)
+			_, err = w.Write(testrand.Bytes(blobSize))
+			require.NoError(t, err)
+			require.NoError(t, w.Commit(ctx))
+		}
+
+		cache := pieces.NewBlobsUsageCache(log, blobstore)
+		cacheService := pieces.NewService(log,
+			cache,
+			pieces.NewStore(log, cache, nil, nil, db.PieceSpaceUsedDB(), pieces.DefaultConfig),
+			1*time.Hour,
+		)
+		require.NoError(t, cacheService.Init(ctx))
+
+		var eg errgroup.Group
+		eg.Go(func() error {
+			return cacheService.Run(ctx)
+		})
+		cacheService.InitFence.Wait(ctx)
+
+		// the cache reports the combined usage of the directories
+		piecesTotal, _, err := cache.SpaceUsedForPieces(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(pieceCount*blobSize), piecesTotal)
+
+		require.NoError(t, cacheService.Close())
+		require.NoError(t, eg.Wait())
+	})
+}
+
+func TestCacheServiceRunLostDirectory(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		dirs := []string{ctx.Dir("a"), ctx.Dir("b")}
+		blobstore, err := filestore.NewMulti(log, filestore.WeightedPaths{
+			{Path: dirs[0], Weight: 1},
+			{Path: dirs[1], Weight: 1},
+		}, filestore.DefaultConfig)
+		require.NoError(t, err)
+
+		const pieceCount = 10
+		blobSize := memory.KB
+		namespace := testrand.NodeID().Bytes()
+		for i := 0; i < pieceCount; i++ {
+			w, err := blobstore.Create(ctx, storage.BlobRef{
+				Namespace: namespace,
+				Key:       testrand.PieceID().Bytes(),
+			}, -1)
+			require.NoError(t, err)
+			_, err = w.Write(testrand.Bytes(blobSize))
+			require.NoError(t, err)
+			require.NoError(t, w.Commit(ctx))
+		}
+
+		// lose the second directory and replace it with a file, so that
+		// reading it fails
+		require.NoError(t, os.RemoveAll(dirs[1]))
+		require.NoError(t, ioutil.WriteFile(dirs[1], nil, 0644))
+
+		cache := pieces.NewBlobsUsageCache(log, blobstore)
+		cacheService := pieces.NewService(log,
+			cache,
+			pieces.NewStore(log, cache, nil, nil, db.PieceSpaceUsedDB(), pieces.DefaultConfig),
+			1*time.Hour,
+		)
+		require.NoError(t, cacheService.Init(ctx))
+
+		var eg errgroup.Group
+		eg.Go(func() error {
+			return cacheService.Run(ctx)
+		})
+		cacheService.InitFence.Wait(ctx)
+
+		// the cache is recalculated with the usage of the remaining directory
+		// and the service keeps running until it's closed
+		piecesTotal, _, err := cache.SpaceUsedForPieces(ctx)
+		require.NoError(t, err)
+		assert.True(t, piecesTotal > 0 && piecesTotal < int64(pieceCount*blobSize), piecesTotal)
+
+		require.NoError(t, cacheService.Close())
+		require.NoError(t, eg.Wait())
+	})
+}
+
 func TestPersistCacheTotals(t *testing.T) {
 	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
 		log := zaptest.NewLogger(t)
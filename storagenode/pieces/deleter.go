@@ -180,6 +180,16 @@ func (d *Deleter) Close() error {
 	return nil
 }
 
+// QueueLength returns the number of deletes waiting in the queue.
+func (d *Deleter) QueueLength() int {
+	return len(d.ch)
+}
+
+// QueueCapacity returns the number of deletes the queue can hold.
+func (d *Deleter) QueueCapacity() int {
+	return cap(d.ch)
+}
+
 // Wait blocks until the queue is empty and each enqueued delete has been
 // successfully processed.
 func (d *Deleter) Wait(ctx context.Context) {
@@ -0,0 +1,244 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/errs2"
+	"storj.io/common/memory"
+	"storj.io/common/pkcrypto"
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/storj/storage/filestore"
+)
+
+// scrubChunkSize is how much of a piece the scrubber reads at once.
+const scrubChunkSize = 32 * memory.KiB
+
+// CorruptPiece is a stored piece which failed the integrity check.
+type CorruptPiece struct {
+	SatelliteID storj.NodeID  `json:"satelliteId"`
+	PieceID     storj.PieceID `json:"pieceId"`
+	Reason      string        `json:"reason"`
+	DetectedAt  time.Time     `json:"detectedAt"`
+}
+
+// CorruptPiecesDB stores the pieces found corrupt by the scrubber.
+//
+// architecture: Database
+type CorruptPiecesDB interface {
+	// Add adds or updates a corrupt piece.
+	Add(ctx context.Context, piece CorruptPiece) error
+	// Delete removes the piece from the corrupt pieces.
+	Delete(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) error
+	// List returns all the corrupt pieces, the most recently detected first.
+	List(ctx context.Context) ([]CorruptPiece, error)
+}
+
+// ScrubberConfig defines parameters for the piece scrubber.
+type ScrubberConfig struct {
+	Interval time.Duration `help:"how often to verify the hashes of all the stored pieces, 0 disables it" releaseDefault:"168h0m0s" devDefault:"1h0m0s"`
+	Rate     memory.Size   `help:"how many bytes per second the piece scrubber reads from disk, 0 is unlimited" default:"4MiB"`
+}
+
+// ScrubStats contains the results of a single scrub.
+type ScrubStats struct {
+	Checked int64
+	Skipped int64
+	Corrupt int64
+	Bytes   int64
+}
+
+// Scrubber walks all the stored pieces and verifies that their content still
+// matches the hash signed by the uplink, recording the corrupt pieces.
+//
+// architecture: Chore
+type Scrubber struct {
+	log     *zap.Logger
+	store   *Store
+	corrupt CorruptPiecesDB
+	config  ScrubberConfig
+	limiter *rate.Limiter
+
+	Loop *sync2.Cycle
+}
+
+// NewScrubber creates a new piece scrubber.
+func NewScrubber(log *zap.Logger, store *Store, corrupt CorruptPiecesDB, config ScrubberConfig) *Scrubber {
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if config.Rate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(config.Rate), scrubChunkSize.Int())
+	}
+	return &Scrubber{
+		log:     log,
+		store:   store,
+		corrupt: corrupt,
+		config:  config,
+		limiter: limiter,
+		Loop:    sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run scrubs the stored pieces on every interval.
+func (scrubber *Scrubber) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if scrubber.config.Interval <= 0 {
+		return nil
+	}
+	return scrubber.Loop.Run(ctx, func(ctx context.Context) (err error) {
+		defer mon.Task()(&ctx)(&err)
+
+		stats, err := scrubber.Scrub(ctx)
+		if err != nil {
+			if errs2.IsCanceled(err) {
+				return err
+			}
+			scrubber.log.Error("scrubbing pieces failed", zap.Error(err))
+			return nil
+		}
+		scrubber.log.Info("scrubbed pieces",
+			zap.Int64("Checked", stats.Checked),
+			zap.Int64("Skipped", stats.Skipped),
+			zap.Int64("Corrupt", stats.Corrupt))
+		return nil
+	})
+}
+
+type scrubbedPiece struct {
+	satelliteID storj.NodeID
+	pieceID     storj.PieceID
+}
+
+// Scrub verifies all the stored pieces once. The pieces which are corrupt are
+// recorded and the previously recorded pieces which are no longer corrupt or
+// no longer stored are removed.
+//
+// V0 pieces are skipped, since their hashes are not stored with the piece.
+func (scrubber *Scrubber) Scrub(ctx context.Context) (stats ScrubStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	known, err := scrubber.corrupt.List(ctx)
+	if err != nil {
+		return stats, Error.Wrap(err)
+	}
+	// the known corrupt pieces which aren't found corrupt again are removed
+	resolved := make(map[scrubbedPiece]struct{}, len(known))
+	for _, piece := range known {
+		resolved[scrubbedPiece{piece.SatelliteID, piece.PieceID}] = struct{}{}
+	}
+
+	satellites, err := scrubber.store.getAllStoringSatellites(ctx)
+	if err != nil {
+		return stats, Error.Wrap(err)
+	}
+
+	for _, satelliteID := range satellites {
+		err := scrubber.store.WalkSatellitePieces(ctx, satelliteID, func(access StoredPieceAccess) error {
+			if access.StorageFormatVersion() < filestore.FormatV1 {
+				stats.Skipped++
+				return nil
+			}
+
+			key := scrubbedPiece{satelliteID, access.PieceID()}
+			read, reason, err := scrubber.verify(ctx, satelliteID, access.PieceID())
+			stats.Bytes += read
+			if err != nil {
+				if os.IsNotExist(err) {
+					// the piece was deleted while scrubbing
+					return nil
+				}
+				return err
+			}
+			stats.Checked++
+
+			if reason == "" {
+				return nil
+			}
+
+			delete(resolved, key)
+			stats.Corrupt++
+			scrubber.log.Warn("corrupt piece",
+				zap.Stringer("Satellite ID", satelliteID),
+				zap.Stringer("Piece ID", access.PieceID()),
+				zap.String("Reason", reason))
+			return scrubber.corrupt.Add(ctx, CorruptPiece{
+				SatelliteID: satelliteID,
+				PieceID:     access.PieceID(),
+				Reason:      reason,
+				DetectedAt:  time.Now().UTC(),
+			})
+		})
+		if err != nil {
+			return stats, Error.Wrap(err)
+		}
+	}
+
+	var group errs.Group
+	for piece := range resolved {
+		group.Add(scrubber.corrupt.Delete(ctx, piece.satelliteID, piece.pieceID))
+	}
+	return stats, Error.Wrap(group.Err())
+}
+
+// verify re-hashes the content of the piece and compares it with the hash in
+// the piece header. It returns the reason why the piece is corrupt, or an
+// empty reason when the piece is intact.
+func (scrubber *Scrubber) verify(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) (read int64, reason string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	reader, err := scrubber.store.ReaderWithStorageFormat(ctx, satelliteID, pieceID, filestore.FormatV1)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, "", err
+		}
+		return 0, "unable to open piece: " + err.Error(), nil
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	header, err := reader.GetPieceHeader()
+	if err != nil {
+		return 0, "unable to read piece header: " + err.Error(), nil
+	}
+
+	hash := pkcrypto.NewHash()
+	buffer := make([]byte, scrubChunkSize.Int())
+	for {
+		if err := scrubber.limiter.WaitN(ctx, len(buffer)); err != nil {
+			return read, "", err
+		}
+
+		n, err := reader.Read(buffer)
+		read += int64(n)
+		_, _ = hash.Write(buffer[:n])
+		if errs.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return read, "", ctxErr
+			}
+			return read, "unable to read piece: " + err.Error(), nil
+		}
+	}
+
+	if !bytes.Equal(hash.Sum(nil), header.GetHash()) {
+		return read, "hash doesn't match the uplink piece hash", nil
+	}
+	return read, "", nil
+}
+
+// Close stops the scrubber.
+func (scrubber *Scrubber) Close() error {
+	scrubber.Loop.Close()
+	return nil
+}
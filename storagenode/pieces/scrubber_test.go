@@ -0,0 +1,98 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestScrubber(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		log := zaptest.NewLogger(t)
+
+		blobs, err := filestore.NewAt(log, ctx.Dir("pieces"), filestore.DefaultConfig)
+		require.NoError(t, err)
+		defer ctx.Check(blobs.Close)
+
+		store := pieces.NewStore(log, blobs, nil, nil, db.PieceSpaceUsedDB(), pieces.DefaultConfig)
+		scrubber := pieces.NewScrubber(log, store, db.CorruptPieces(), pieces.ScrubberConfig{})
+
+		satelliteID := testrand.NodeID()
+		pieceIDs := []storj.PieceID{testrand.PieceID(), testrand.PieceID(), testrand.PieceID()}
+		for _, pieceID := range pieceIDs {
+			writer, err := store.Writer(ctx, satelliteID, pieceID)
+			require.NoError(t, err)
+			_, err = writer.Write(testrand.Bytes(10 * memory.KiB))
+			require.NoError(t, err)
+			require.NoError(t, writer.Commit(ctx, &pb.PieceHeader{
+				Hash: writer.Hash(),
+			}))
+		}
+
+		stats, err := scrubber.Scrub(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, len(pieceIDs), stats.Checked)
+		require.Zero(t, stats.Corrupt)
+
+		corrupt, err := db.CorruptPieces().List(ctx)
+		require.NoError(t, err)
+		require.Empty(t, corrupt)
+
+		// flip a byte of the content of a piece
+		corruptID := pieceIDs[1]
+		info, err := blobs.Stat(ctx, storage.BlobRef{Namespace: satelliteID.Bytes(), Key: corruptID.Bytes()})
+		require.NoError(t, err)
+		path, err := info.FullPath(ctx)
+		require.NoError(t, err)
+		file, err := os.OpenFile(path, os.O_RDWR, 0)
+		require.NoError(t, err)
+		data := make([]byte, 1)
+		offset := int64(pieces.V1PieceHeaderReservedArea + 100)
+		_, err = file.ReadAt(data, offset)
+		require.NoError(t, err)
+		data[0] ^= 0xFF
+		_, err = file.WriteAt(data, offset)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		stats, err = scrubber.Scrub(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, len(pieceIDs), stats.Checked)
+		require.EqualValues(t, 1, stats.Corrupt)
+
+		corrupt, err = db.CorruptPieces().List(ctx)
+		require.NoError(t, err)
+		require.Len(t, corrupt, 1)
+		require.Equal(t, satelliteID, corrupt[0].SatelliteID)
+		require.Equal(t, corruptID, corrupt[0].PieceID)
+		require.NotEmpty(t, corrupt[0].Reason)
+
+		// the record is removed once the piece is gone
+		require.NoError(t, store.Delete(ctx, satelliteID, corruptID))
+
+		stats, err = scrubber.Scrub(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, len(pieceIDs)-1, stats.Checked)
+		require.Zero(t, stats.Corrupt)
+
+		corrupt, err = db.CorruptPieces().List(ctx)
+		require.NoError(t, err)
+		require.Empty(t, corrupt)
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"storj.io/common/signing"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/storage/filestore"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/monitor"
 	"storj.io/storj/storagenode/orders"
@@ -41,11 +42,13 @@ var (
 
 // OldConfig contains everything necessary for a server
 type OldConfig struct {
-	Path                   string         `help:"path to store data in" default:"$CONFDIR/storage"`
-	WhitelistedSatellites  storj.NodeURLs `help:"a comma-separated list of approved satellite node urls (unused)" devDefault:"" releaseDefault:""`
-	AllocatedDiskSpace     memory.Size    `user:"true" help:"total allocated disk space in bytes" default:"1TB"`
-	AllocatedBandwidth     memory.Size    `user:"true" help:"total allocated bandwidth in bytes (deprecated)" default:"0B"`
-	KBucketRefreshInterval time.Duration  `help:"how frequently Kademlia bucket should be refreshed with node stats" default:"1h0m0s"`
+	Path                   string                  `help:"path to store data in" default:"$CONFDIR/storage"`
+	ExtraPaths             filestore.WeightedPaths `help:"a comma-separated list of additional directories to store pieces in, as path or path=weight" default:""`
+	Backend                string                  `help:"how to store the pieces, filestore stores every piece in its own file and packstore appends them into pack files" default:"filestore"`
+	WhitelistedSatellites  storj.NodeURLs          `help:"a comma-separated list of approved satellite node urls (unused)" devDefault:"" releaseDefault:""`
+	AllocatedDiskSpace     memory.Size             `user:"true" help:"total allocated disk space in bytes" default:"1TB"`
+	AllocatedBandwidth     memory.Size             `user:"true" help:"total allocated bandwidth in bytes (deprecated)" default:"0B"`
+	KBucketRefreshInterval time.Duration           `help:"how frequently Kademlia bucket should be refreshed with node stats" default:"1h0m0s"`
 }
 
 // Config defines parameters for piecestore endpoint.
@@ -63,8 +66,9 @@ type Config struct {
 
 	Trust trust.Config
 
-	Monitor monitor.Config
-	Orders  orders.Config
+	Monitor   monitor.Config
+	Orders    orders.Config
+	Bandwidth bandwidth.LimitsConfig
 }
 
 type pingStatsSource interface {
@@ -87,6 +91,7 @@ type Endpoint struct {
 	store        *pieces.Store
 	orders       orders.DB
 	usage        bandwidth.DB
+	limits       *bandwidth.Limits
 	usedSerials  UsedSerials
 	pieceDeleter *pieces.Deleter
 
@@ -94,7 +99,7 @@ type Endpoint struct {
 }
 
 // NewEndpoint creates a new piecestore endpoint.
-func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, monitor *monitor.Service, retain *retain.Service, pingStats pingStatsSource, store *pieces.Store, pieceDeleter *pieces.Deleter, orders orders.DB, usage bandwidth.DB, usedSerials UsedSerials, config Config) (*Endpoint, error) {
+func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, monitor *monitor.Service, retain *retain.Service, pingStats pingStatsSource, store *pieces.Store, pieceDeleter *pieces.Deleter, orders orders.DB, usage bandwidth.DB, limits *bandwidth.Limits, usedSerials UsedSerials, config Config) (*Endpoint, error) {
 	return &Endpoint{
 		log:    log,
 		config: config,
@@ -108,6 +113,7 @@ func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, moni
 		store:        store,
 		orders:       orders,
 		usage:        usage,
+		limits:       limits,
 		usedSerials:  usedSerials,
 		pieceDeleter: pieceDeleter,
 
@@ -227,6 +233,21 @@ func (endpoint *Endpoint) Upload(stream pb.DRPCPiecestore_UploadStream) (err err
 		return err
 	}
 
+	if endpoint.config.Bandwidth.MonthlyIngress > 0 {
+		budget, err := endpoint.limits.Budget(ctx)
+		if err != nil {
+			return rpcstatus.Wrap(rpcstatus.Internal, err)
+		}
+		if budget.IngressExhausted() {
+			endpoint.log.Warn("upload rejected, monthly ingress budget exhausted",
+				zap.Stringer("Piece ID", limit.PieceId),
+				zap.Stringer("Satellite ID", limit.SatelliteId),
+				zap.Int64("Ingress Used", budget.IngressUsed),
+				zap.Int64("Ingress Limit", budget.IngressLimit))
+			return rpcstatus.Error(rpcstatus.ResourceExhausted, "storage node monthly ingress budget exhausted")
+		}
+	}
+
 	availableSpace, err := endpoint.monitor.AvailableSpace(ctx)
 	if err != nil {
 		return rpcstatus.Wrap(rpcstatus.Internal, err)
@@ -351,6 +372,9 @@ func (endpoint *Endpoint) Upload(stream pb.DRPCPiecestore_UploadStream) (err err
 			if availableSpace < 0 {
 				return rpcstatus.Error(rpcstatus.Internal, "out of space")
 			}
+			if err := endpoint.limits.WaitIngress(ctx, len(message.Chunk.Data)); err != nil {
+				return rpcstatus.Wrap(rpcstatus.Internal, err)
+			}
 			if _, err := pieceWriter.Write(message.Chunk.Data); err != nil {
 				return rpcstatus.Wrap(rpcstatus.Internal, err)
 			}
@@ -558,6 +582,13 @@ func (endpoint *Endpoint) Download(stream pb.DRPCPiecestore_DownloadStream) (err
 				return nil
 			}
 
+			// audits are never delayed, so that they don't time out
+			if limit.Action != pb.PieceAction_GET_AUDIT {
+				if err := endpoint.limits.WaitEgress(ctx, int(chunkSize)); err != nil {
+					return rpcstatus.Wrap(rpcstatus.Internal, err)
+				}
+			}
+
 			chunkData := make([]byte, chunkSize)
 			_, err = pieceReader.Seek(currentOffset, io.SeekStart)
 			if err != nil {
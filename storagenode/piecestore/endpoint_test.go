@@ -162,6 +162,77 @@ func TestUpload(t *testing.T) {
 	})
 }
 
+func TestUploadIngressBudget(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Storage2.Bandwidth.MonthlyIngress = 10 * memory.KiB
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		storageNode := planet.StorageNodes[0]
+		satellite := planet.Satellites[0]
+
+		// uploads are accepted while there is budget left
+		expectedData, _, _ := uploadPiece(t, ctx, storj.PieceID{1}, storageNode, planet.Uplinks[0], satellite)
+
+		err := storageNode.DB.Bandwidth().Add(ctx, satellite.ID(), pb.PieceAction_PUT, 10*memory.KiB.Int64(), time.Now())
+		require.NoError(t, err)
+
+		client, err := planet.Uplinks[0].DialPiecestore(ctx, storageNode)
+		require.NoError(t, err)
+		defer ctx.Check(client.Close)
+
+		orderLimit, piecePrivateKey := GenerateOrderLimit(
+			t,
+			satellite.ID(),
+			storageNode.ID(),
+			storj.PieceID{2},
+			pb.PieceAction_PUT,
+			testrand.SerialNumber(),
+			24*time.Hour,
+			24*time.Hour,
+			memory.KiB.Int64(),
+		)
+		signer := signing.SignerFromFullIdentity(satellite.Identity)
+		orderLimit, err = signing.SignOrderLimit(ctx, signer, orderLimit)
+		require.NoError(t, err)
+
+		uploader, err := client.Upload(ctx, orderLimit, piecePrivateKey)
+		require.NoError(t, err)
+		_, err = uploader.Write(testrand.Bytes(memory.KiB))
+		if err == nil {
+			_, err = uploader.Commit(ctx)
+		}
+		require.Error(t, err)
+		require.True(t, errs2.IsRPC(err, rpcstatus.ResourceExhausted), err)
+
+		// downloads are still served
+		orderLimit, piecePrivateKey = GenerateOrderLimit(
+			t,
+			satellite.ID(),
+			storageNode.ID(),
+			storj.PieceID{1},
+			pb.PieceAction_GET,
+			testrand.SerialNumber(),
+			24*time.Hour,
+			24*time.Hour,
+			int64(len(expectedData)),
+		)
+		orderLimit, err = signing.SignOrderLimit(ctx, signer, orderLimit)
+		require.NoError(t, err)
+
+		downloader, err := client.Download(ctx, orderLimit, piecePrivateKey, 0, int64(len(expectedData)))
+		require.NoError(t, err)
+		buffer := make([]byte, len(expectedData))
+		_, err = io.ReadFull(downloader, buffer)
+		require.NoError(t, err)
+		require.Equal(t, expectedData, buffer)
+		require.NoError(t, downloader.Close())
+	})
+}
+
 func TestDownload(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
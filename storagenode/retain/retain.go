@@ -295,6 +295,14 @@ func (s *Service) Status() Status {
 	return s.config.Status
 }
 
+// Pending returns the number of retain requests which are queued or being processed.
+func (s *Service) Pending() (queued, working int) {
+	s.cond.L.Lock()
+	defer s.cond.L.Unlock()
+
+	return len(s.queued), len(s.working)
+}
+
 // ------------------------------------------------------------------------------------------------
 // On the correctness of using access.ModTime() in place of the more precise access.CreationTime()
 // in retainPieces():
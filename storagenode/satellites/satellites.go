@@ -37,6 +37,16 @@ type ExitProgress struct {
 	Status            int32
 }
 
+// ShrinkProgress contains the status of a partial graceful exit, which moves
+// TargetBytes of the pieces of the satellite to other nodes.
+type ShrinkProgress struct {
+	SatelliteID  storj.NodeID
+	TargetBytes  int64
+	InitiatedAt  time.Time
+	BytesDeleted int64
+	FinishedAt   *time.Time
+}
+
 // Satellite contains the satellite and status
 type Satellite struct {
 	SatelliteID storj.NodeID
@@ -54,10 +64,20 @@ type DB interface {
 	InitiateGracefulExit(ctx context.Context, satelliteID storj.NodeID, intitiatedAt time.Time, startingDiskUsage int64) error
 	// CancelGracefulExit removes that satellite by ID
 	CancelGracefulExit(ctx context.Context, satelliteID storj.NodeID) error
+	// RestoreGracefulExit restores a cancelled graceful exit record with its progress
+	RestoreGracefulExit(ctx context.Context, exit ExitProgress) error
 	// UpdateGracefulExit increments the total bytes deleted during a graceful exit
 	UpdateGracefulExit(ctx context.Context, satelliteID storj.NodeID, bytesDeleted int64) error
 	// CompleteGracefulExit updates the database when a graceful exit is completed or failed
 	CompleteGracefulExit(ctx context.Context, satelliteID storj.NodeID, finishedAt time.Time, exitStatus Status, completionReceipt []byte) error
 	// ListGracefulExits lists all graceful exit records
 	ListGracefulExits(ctx context.Context) ([]ExitProgress, error)
+	// InitiateShrink updates the database to reflect the beginning of a shrink, replacing the previous shrink
+	InitiateShrink(ctx context.Context, satelliteID storj.NodeID, initiatedAt time.Time, targetBytes int64) error
+	// UpdateShrink increments the total bytes deleted during a shrink
+	UpdateShrink(ctx context.Context, satelliteID storj.NodeID, bytesDeleted int64) error
+	// CompleteShrink updates the database when a shrink is finished
+	CompleteShrink(ctx context.Context, satelliteID storj.NodeID, finishedAt time.Time) error
+	// ListShrinks lists the last shrink of every satellite
+	ListShrinks(ctx context.Context) ([]ShrinkProgress, error)
 }
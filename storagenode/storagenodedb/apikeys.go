@@ -0,0 +1,117 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/storagenode/apikeys"
+)
+
+// ensures that apiKeysDB implements apikeys.DB interface.
+var _ apikeys.DB = (*apiKeysDB)(nil)
+
+// ErrAPIKeys represents errors from the api keys database.
+var ErrAPIKeys = errs.Class("api keys db error")
+
+// APIKeysDBName represents the database name.
+const APIKeysDBName = "api_keys"
+
+// apiKeysDB stores the hashes of the console api keys.
+//
+// architecture: Database
+type apiKeysDB struct {
+	dbContainerImpl
+}
+
+// Store stores the api key.
+func (db *apiKeysDB) Store(ctx context.Context, key apikeys.APIKey) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO api_keys(id, name, scope, hash, created_at)
+			VALUES (?,?,?,?,?)
+	`, key.ID[:], key.Name, int(key.Scope), key.Hash, key.CreatedAt.UTC())
+	return ErrAPIKeys.Wrap(err)
+}
+
+// GetByHash returns the api key with the hash.
+func (db *apiKeysDB) GetByHash(ctx context.Context, hash []byte) (_ apikeys.APIKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	row := db.QueryRowContext(ctx, `
+		SELECT id, name, scope, hash, created_at
+			FROM api_keys
+			WHERE hash = ?
+	`, hash)
+
+	key, err := scanAPIKey(row.Scan)
+	if err != nil {
+		if errs.Is(err, sql.ErrNoRows) {
+			return apikeys.APIKey{}, apikeys.ErrUnauthorized.New("unknown api key")
+		}
+		return apikeys.APIKey{}, ErrAPIKeys.Wrap(err)
+	}
+	return key, nil
+}
+
+// List returns all the api keys, oldest first.
+func (db *apiKeysDB) List(ctx context.Context) (_ []apikeys.APIKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, name, scope, hash, created_at
+			FROM api_keys
+			ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, ErrAPIKeys.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var keys []apikeys.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows.Scan)
+		if err != nil {
+			return nil, ErrAPIKeys.Wrap(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, ErrAPIKeys.Wrap(rows.Err())
+}
+
+// Revoke removes the api key.
+func (db *apiKeysDB) Revoke(ctx context.Context, id uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := db.ExecContext(ctx, `
+		DELETE FROM api_keys
+			WHERE id = ?
+	`, id[:])
+	if err != nil {
+		return ErrAPIKeys.Wrap(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return ErrAPIKeys.Wrap(err)
+	}
+	if rowsAffected != 1 {
+		return ErrAPIKeys.Wrap(ErrNoRows)
+	}
+	return nil
+}
+
+// scanAPIKey scans an api key using the scan function of a row.
+func scanAPIKey(scan func(dest ...interface{}) error) (apikeys.APIKey, error) {
+	var key apikeys.APIKey
+	var scope int
+	err := scan(&key.ID, &key.Name, &scope, &key.Hash, &key.CreatedAt)
+	key.Scope = apikeys.Scope(scope)
+	return key, err
+}
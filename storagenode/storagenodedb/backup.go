@@ -0,0 +1,101 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/private/dbutil/sqliteutil"
+	"storj.io/storj/private/tagsql"
+)
+
+// ErrBackup represents errors from the backup and the restore of the databases.
+var ErrBackup = errs.Class("storagenodedb backup")
+
+// Backup writes a consistent snapshot of every database into dir, using the
+// same file names as the databases in the storage directory. The databases
+// may be in use while the backup is taken.
+func (db *DB) Backup(ctx context.Context, dir string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return ErrBackup.Wrap(err)
+	}
+
+	driver := db.config.Driver
+	if driver == "" {
+		driver = "sqlite3"
+	}
+
+	for dbName, dbContainer := range db.SQLDBs {
+		path := filepath.Join(dir, db.filenameFromDBName(dbName))
+		if err := backupDatabase(ctx, dbContainer.GetDB(), driver, path); err != nil {
+			return ErrBackup.New("%s: %v", dbName, err)
+		}
+	}
+	return nil
+}
+
+// backupDatabase copies src into a new database file at path.
+func backupDatabase(ctx context.Context, src tagsql.DB, driver, path string) (err error) {
+	dest, err := tagsql.Open(driver, "file:"+path)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, dest.Close()) }()
+
+	return sqliteutil.BackupDatabase(ctx, src, dest)
+}
+
+// Versions returns the current migration version of every database.
+func (db *DB) Versions(ctx context.Context) (_ map[string]int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	migration := db.Migration(ctx)
+
+	versions := make(map[string]int, len(db.SQLDBs))
+	for dbName, dbContainer := range db.SQLDBs {
+		version, err := migration.CurrentVersion(ctx, db.log, dbContainer.GetDB())
+		if err != nil {
+			return nil, ErrBackup.New("%s: %v", dbName, err)
+		}
+		versions[dbName] = version
+	}
+	return versions, nil
+}
+
+// CheckVersions checks that no database has a newer migration version than
+// the migration of this release knows about, which happens when the databases
+// are restored from a backup of a newer release.
+func (db *DB) CheckVersions(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	versions, err := db.Versions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the version table of each database only records the steps which ran on it
+	steps := db.Migration(ctx).Steps
+	latest := make(map[string]int, len(db.SQLDBs))
+	for dbName, dbContainer := range db.SQLDBs {
+		latest[dbName] = -1
+		for _, step := range steps {
+			if interface{}(step.DB) == interface{}(dbContainer) {
+				latest[dbName] = step.Version
+			}
+		}
+	}
+
+	for dbName, version := range versions {
+		if supported := latest[dbName]; version > supported {
+			return ErrBackup.New("%s: database version %d is newer than the latest supported version %d", dbName, version, supported)
+		}
+	}
+	return nil
+}
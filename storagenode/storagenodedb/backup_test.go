@@ -0,0 +1,104 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func TestBackup(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+
+	newConfig := func(storageDir string) storagenodedb.Config {
+		return storagenodedb.Config{
+			Pieces:    storageDir,
+			Storage:   storageDir,
+			Info:      filepath.Join(storageDir, "piecestore.db"),
+			Info2:     filepath.Join(storageDir, "info.db"),
+			Filestore: filestore.DefaultConfig,
+		}
+	}
+
+	db, err := storagenodedb.New(log, newConfig(ctx.Dir("storage")))
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+
+	require.NoError(t, db.MigrateToLatest(ctx))
+
+	satelliteID := testrand.NodeID()
+	initiatedAt := time.Now().UTC()
+	require.NoError(t, db.Satellites().InitiateShrink(ctx, satelliteID, initiatedAt, 1<<30))
+
+	versions, err := db.Versions(ctx)
+	require.NoError(t, err)
+
+	backupDir := ctx.Dir("backup")
+	require.NoError(t, db.Backup(ctx, backupDir))
+
+	// the databases are written while the backup is open
+	require.NoError(t, db.Satellites().UpdateShrink(ctx, satelliteID, 1<<20))
+
+	restored, err := storagenodedb.New(log, newConfig(backupDir))
+	require.NoError(t, err)
+	defer ctx.Check(restored.Close)
+
+	restoredVersions, err := restored.Versions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, versions, restoredVersions)
+
+	require.NoError(t, restored.CheckVersions(ctx))
+	require.NoError(t, restored.Preflight(ctx))
+
+	shrinks, err := restored.Satellites().ListShrinks(ctx)
+	require.NoError(t, err)
+	require.Len(t, shrinks, 1)
+	require.Equal(t, satelliteID, shrinks[0].SatelliteID)
+	require.EqualValues(t, 1<<30, shrinks[0].TargetBytes)
+	require.Zero(t, shrinks[0].BytesDeleted)
+}
+
+func TestCheckVersions(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	storageDir := ctx.Dir("storage")
+	db, err := storagenodedb.New(zaptest.NewLogger(t), storagenodedb.Config{
+		Pieces:    storageDir,
+		Storage:   storageDir,
+		Info:      filepath.Join(storageDir, "piecestore.db"),
+		Info2:     filepath.Join(storageDir, "info.db"),
+		Filestore: filestore.DefaultConfig,
+	})
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+
+	// an empty storage directory is migrated by the node on start
+	require.NoError(t, db.CheckVersions(ctx))
+
+	require.NoError(t, db.MigrateToLatest(ctx))
+	require.NoError(t, db.CheckVersions(ctx))
+
+	// a database of a newer release can't be used
+	migration := db.Migration(ctx)
+	last := migration.Steps[len(migration.Steps)-1]
+	_, err = last.DB.ExecContext(ctx, `INSERT INTO versions (version, commited_at) VALUES (?, ?)`, last.Version+1, time.Now().String()) //nolint:misspell
+	require.NoError(t, err)
+
+	err = db.CheckVersions(ctx)
+	require.Error(t, err)
+	require.True(t, storagenodedb.ErrBackup.Has(err))
+}
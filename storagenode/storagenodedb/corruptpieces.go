@@ -0,0 +1,77 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/storj/storagenode/pieces"
+)
+
+// ensures that corruptPiecesDB implements pieces.CorruptPiecesDB interface.
+var _ pieces.CorruptPiecesDB = (*corruptPiecesDB)(nil)
+
+// ErrCorruptPieces represents errors from the corrupt pieces database.
+var ErrCorruptPieces = errs.Class("corrupt pieces error")
+
+// CorruptPiecesDBName represents the database name.
+const CorruptPiecesDBName = "corrupt_pieces"
+
+// corruptPiecesDB stores the pieces which failed the integrity check.
+//
+// architecture: Database
+type corruptPiecesDB struct {
+	dbContainerImpl
+}
+
+// Add adds or updates a corrupt piece.
+func (db *corruptPiecesDB) Add(ctx context.Context, piece pieces.CorruptPiece) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO corrupt_pieces(satellite_id, piece_id, reason, detected_at)
+			VALUES (?,?,?,?)
+	`, piece.SatelliteID, piece.PieceID, piece.Reason, piece.DetectedAt.UTC())
+	return ErrCorruptPieces.Wrap(err)
+}
+
+// Delete removes the piece from the corrupt pieces.
+func (db *corruptPiecesDB) Delete(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx, `
+		DELETE FROM corrupt_pieces
+			WHERE satellite_id = ? AND piece_id = ?
+	`, satelliteID, pieceID)
+	return ErrCorruptPieces.Wrap(err)
+}
+
+// List returns all the corrupt pieces, the most recently detected first.
+func (db *corruptPiecesDB) List(ctx context.Context) (_ []pieces.CorruptPiece, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT satellite_id, piece_id, reason, detected_at
+			FROM corrupt_pieces
+			ORDER BY detected_at DESC
+	`)
+	if err != nil {
+		return nil, ErrCorruptPieces.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var corrupt []pieces.CorruptPiece
+	for rows.Next() {
+		var piece pieces.CorruptPiece
+		err = rows.Scan(&piece.SatelliteID, &piece.PieceID, &piece.Reason, &piece.DetectedAt)
+		if err != nil {
+			return nil, ErrCorruptPieces.Wrap(err)
+		}
+		corrupt = append(corrupt, piece)
+	}
+	return corrupt, ErrCorruptPieces.Wrap(rows.Err())
+}
@@ -23,6 +23,8 @@ import (
 	"storj.io/storj/private/tagsql"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/packstore"
+	"storj.io/storj/storagenode/apikeys"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/heldamount"
 	"storj.io/storj/storagenode/notifications"
@@ -75,12 +77,15 @@ func withTx(ctx context.Context, db tagsql.DB, cb func(tx tagsql.Tx) error) erro
 // Config configures storage node database
 type Config struct {
 	// TODO: figure out better names
-	Storage   string
-	Info      string
-	Info2     string
-	Driver    string // if unset, uses sqlite3
-	Pieces    string
-	Filestore filestore.Config
+	Storage     string
+	Info        string
+	Info2       string
+	Driver      string // if unset, uses sqlite3
+	Pieces      string
+	ExtraPieces filestore.WeightedPaths
+	Filestore   filestore.Config
+	Backend     string
+	Packstore   packstore.Config
 }
 
 // DB contains access to different database tables
@@ -105,17 +110,44 @@ type DB struct {
 	notificationsDB   *notificationDB
 	heldamountDB      *heldamountDB
 	pricingDB         *pricingDB
+	corruptPiecesDB   *corruptPiecesDB
+	packIndexDB       *packIndexDB
+	apiKeysDB         *apiKeysDB
 
 	SQLDBs map[string]DBContainer
 }
 
 // New creates a new master database for storage node
 func New(log *zap.Logger, config Config) (*DB, error) {
-	piecesDir, err := filestore.NewDir(config.Pieces)
-	if err != nil {
-		return nil, err
+	packIndexDB := &packIndexDB{}
+
+	var pieces storage.Blobs
+	switch {
+	case config.Backend == "packstore":
+		if len(config.ExtraPieces) > 0 {
+			return nil, ErrDatabase.New("the packstore backend doesn't support extra piece directories")
+		}
+		var err error
+		pieces, err = packstore.New(log.Named("packstore"), config.Pieces, packIndexDB, config.Packstore)
+		if err != nil {
+			return nil, err
+		}
+	case config.Backend != "" && config.Backend != "filestore":
+		return nil, ErrDatabase.New("unknown storage backend %q", config.Backend)
+	case len(config.ExtraPieces) == 0:
+		piecesDir, err := filestore.NewDir(config.Pieces)
+		if err != nil {
+			return nil, err
+		}
+		pieces = filestore.New(log, piecesDir, config.Filestore)
+	default:
+		var err error
+		paths := append(filestore.WeightedPaths{{Path: config.Pieces, Weight: 1}}, config.ExtraPieces...)
+		pieces, err = filestore.NewMulti(log.Named("pieces"), paths, config.Filestore)
+		if err != nil {
+			return nil, err
+		}
 	}
-	pieces := filestore.New(log, piecesDir, config.Filestore)
 
 	deprecatedInfoDB := &deprecatedInfoDB{}
 	v0PieceInfoDB := &v0PieceInfoDB{}
@@ -130,6 +162,8 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 	notificationsDB := &notificationDB{}
 	heldamountDB := &heldamountDB{}
 	pricingDB := &pricingDB{}
+	corruptPiecesDB := &corruptPiecesDB{}
+	apiKeysDB := &apiKeysDB{}
 
 	db := &DB{
 		log:    log,
@@ -152,6 +186,9 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 		notificationsDB:   notificationsDB,
 		heldamountDB:      heldamountDB,
 		pricingDB:         pricingDB,
+		corruptPiecesDB:   corruptPiecesDB,
+		packIndexDB:       packIndexDB,
+		apiKeysDB:         apiKeysDB,
 
 		SQLDBs: map[string]DBContainer{
 			DeprecatedInfoDBName:  deprecatedInfoDB,
@@ -167,10 +204,13 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 			NotificationsDBName:   notificationsDB,
 			HeldAmountDBName:      heldamountDB,
 			PricingDBName:         pricingDB,
+			CorruptPiecesDBName:   corruptPiecesDB,
+			PackIndexDBName:       packIndexDB,
+			APIKeysDBName:         apiKeysDB,
 		},
 	}
 
-	err = db.openDatabases()
+	err := db.openDatabases()
 	if err != nil {
 		return nil, err
 	}
@@ -247,6 +287,21 @@ func (db *DB) openDatabases() error {
 	if err != nil {
 		return errs.Combine(err, db.closeDatabases())
 	}
+
+	err = db.openDatabase(CorruptPiecesDBName)
+	if err != nil {
+		return errs.Combine(err, db.closeDatabases())
+	}
+
+	err = db.openDatabase(PackIndexDBName)
+	if err != nil {
+		return errs.Combine(err, db.closeDatabases())
+	}
+
+	err = db.openDatabase(APIKeysDBName)
+	if err != nil {
+		return errs.Combine(err, db.closeDatabases())
+	}
 	return nil
 }
 
@@ -374,7 +429,7 @@ func (db *DB) Preflight(ctx context.Context) (err error) {
 
 // Close closes any resources.
 func (db *DB) Close() error {
-	return db.closeDatabases()
+	return errs.Combine(db.pieces.Close(), db.closeDatabases())
 }
 
 // closeDatabases closes all the SQLite database connections and removes them from the associated maps.
@@ -461,6 +516,21 @@ func (db *DB) Pricing() pricing.DB {
 	return db.pricingDB
 }
 
+// CorruptPieces returns instance of the CorruptPieces database.
+func (db *DB) CorruptPieces() pieces.CorruptPiecesDB {
+	return db.corruptPiecesDB
+}
+
+// PackIndex returns instance of the PackIndex database.
+func (db *DB) PackIndex() packstore.Index {
+	return db.packIndexDB
+}
+
+// APIKeys returns instance of the APIKeys database.
+func (db *DB) APIKeys() apikeys.DB {
+	return db.apiKeysDB
+}
+
 // RawDatabases are required for testing purposes
 func (db *DB) RawDatabases() map[string]DBContainer {
 	return db.SQLDBs
@@ -1333,6 +1403,70 @@ func (db *DB) Migration(ctx context.Context) *migrate.Migration {
 					return nil
 				}),
 			},
+			{
+				DB:          db.corruptPiecesDB,
+				Description: "Create corrupt_pieces table",
+				Version:     40,
+				Action: migrate.SQL{
+					`CREATE TABLE corrupt_pieces (
+						satellite_id BLOB NOT NULL,
+						piece_id BLOB NOT NULL,
+						reason TEXT NOT NULL,
+						detected_at TIMESTAMP NOT NULL,
+						PRIMARY KEY ( satellite_id, piece_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.packIndexDB,
+				Description: "Create pack_entries table",
+				Version:     41,
+				Action: migrate.SQL{
+					`CREATE TABLE pack_entries (
+						namespace BLOB NOT NULL,
+						key BLOB NOT NULL,
+						format_version INTEGER NOT NULL,
+						pack_id INTEGER NOT NULL,
+						pack_offset INTEGER NOT NULL,
+						size INTEGER NOT NULL,
+						mod_time TIMESTAMP NOT NULL,
+						trashed_at TIMESTAMP,
+						PRIMARY KEY ( namespace, key, format_version )
+					);`,
+					`CREATE INDEX idx_pack_entries_pack_id ON pack_entries(pack_id);`,
+				},
+			},
+			{
+				DB:          db.apiKeysDB,
+				Description: "Create api_keys table",
+				Version:     42,
+				Action: migrate.SQL{
+					`CREATE TABLE api_keys (
+						id BLOB NOT NULL,
+						name TEXT NOT NULL,
+						scope INTEGER NOT NULL,
+						hash BLOB NOT NULL,
+						created_at TIMESTAMP NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE UNIQUE INDEX idx_api_keys_hash ON api_keys(hash);`,
+				},
+			},
+			{
+				DB:          db.satellitesDB,
+				Description: "Create satellite_shrinks table",
+				Version:     43,
+				Action: migrate.SQL{
+					`CREATE TABLE satellite_shrinks (
+						satellite_id BLOB NOT NULL,
+						target_bytes INTEGER NOT NULL,
+						initiated_at TIMESTAMP NOT NULL,
+						bytes_deleted INTEGER NOT NULL DEFAULT 0,
+						finished_at TIMESTAMP,
+						PRIMARY KEY ( satellite_id )
+					);`,
+				},
+			},
 		},
 	}
 }
@@ -225,7 +225,8 @@ func (db *heldamountDB) SatellitesHeldbackHistory(ctx context.Context, id storj.
 
 	query := `SELECT 
 				period,
-				held
+				held,
+				disposed
 			  FROM paystubs WHERE satellite_id = ? ORDER BY period ASC`
 
 	rows, err := db.QueryContext(ctx, query, id)
@@ -239,7 +240,7 @@ func (db *heldamountDB) SatellitesHeldbackHistory(ctx context.Context, id storj.
 	for rows.Next() {
 		var held heldamount.Heldback
 
-		err := rows.Scan(&held.Period, &held.Held)
+		err := rows.Scan(&held.Period, &held.Held, &held.Disposed)
 		if err != nil {
 			return nil, ErrHeldAmount.Wrap(err)
 		}
@@ -169,6 +169,34 @@ func (db *ordersDB) ListUnsentBySatellite(ctx context.Context) (_ map[storj.Node
 	return infos, ErrOrders.Wrap(rows.Err())
 }
 
+// CountUnsentBySatellite returns the number of orders that haven't been sent
+// yet by satellite.
+func (db *ordersDB) CountUnsentBySatellite(ctx context.Context) (_ map[storj.NodeID]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT satellite_id, COUNT(*)
+		FROM unsent_order
+		GROUP BY satellite_id
+	`)
+	if err != nil {
+		return nil, ErrOrders.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	counts := map[storj.NodeID]int64{}
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var count int64
+		if err := rows.Scan(&satelliteID, &count); err != nil {
+			return nil, ErrOrders.Wrap(err)
+		}
+		counts[satelliteID] = count
+	}
+
+	return counts, ErrOrders.Wrap(rows.Err())
+}
+
 // Archive marks order as being handled.
 //
 // If any of the request contains an order which doesn't exist the method will
@@ -0,0 +1,332 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/private/tagsql"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/packstore"
+)
+
+// ensures that packIndexDB implements packstore.Index interface.
+var _ packstore.Index = (*packIndexDB)(nil)
+
+// ErrPackIndex represents errors from the pack index database.
+var ErrPackIndex = errs.Class("pack index error")
+
+// PackIndexDBName represents the database name.
+const PackIndexDBName = "pack_index"
+
+// packIndexDB stores the locations of the blobs in the pack files.
+//
+// architecture: Database
+type packIndexDB struct {
+	dbContainerImpl
+}
+
+const packEntryColumns = `namespace, key, format_version, pack_id, pack_offset, size, mod_time, trashed_at`
+
+// Put adds the entry, replacing the entry of the same blob and format version.
+func (db *packIndexDB) Put(ctx context.Context, entry packstore.Entry) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO pack_entries(`+packEntryColumns+`)
+			VALUES (?,?,?,?,?,?,?,NULL)
+	`, entry.Ref.Namespace, entry.Ref.Key, int(entry.FormatVersion),
+		entry.PackID, entry.Offset, entry.Size, entry.ModTime.UTC())
+	return ErrPackIndex.Wrap(err)
+}
+
+// Get returns the entry of the blob stored with the format version.
+func (db *packIndexDB) Get(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (_ packstore.Entry, found bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	row := db.QueryRowContext(ctx, `
+		SELECT `+packEntryColumns+`
+			FROM pack_entries
+			WHERE namespace = ? AND key = ? AND format_version = ?
+	`, ref.Namespace, ref.Key, int(formatVersion))
+
+	entry, err := scanPackEntry(row)
+	if errs.Is(err, sql.ErrNoRows) {
+		return packstore.Entry{}, false, nil
+	}
+	if err != nil {
+		return packstore.Entry{}, false, ErrPackIndex.Wrap(err)
+	}
+	return entry, true, nil
+}
+
+// Delete removes the entry of the blob stored with the format version.
+func (db *packIndexDB) Delete(ctx context.Context, ref storage.BlobRef, formatVersion storage.FormatVersion) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx, `
+		DELETE FROM pack_entries
+			WHERE namespace = ? AND key = ? AND format_version = ?
+	`, ref.Namespace, ref.Key, int(formatVersion))
+	return ErrPackIndex.Wrap(err)
+}
+
+// Move updates the location of the entry, unless the entry was changed since it was read.
+func (db *packIndexDB) Move(ctx context.Context, entry packstore.Entry, packID, offset int64) (moved bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE pack_entries
+			SET pack_id = ?, pack_offset = ?
+			WHERE namespace = ? AND key = ? AND format_version = ?
+				AND pack_id = ? AND pack_offset = ?
+	`, packID, offset,
+		entry.Ref.Namespace, entry.Ref.Key, int(entry.FormatVersion),
+		entry.PackID, entry.Offset)
+	if err != nil {
+		return false, ErrPackIndex.Wrap(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, ErrPackIndex.Wrap(err)
+	}
+	return affected > 0, nil
+}
+
+// Trash marks all the format versions of the blob as trashed.
+func (db *packIndexDB) Trash(ctx context.Context, ref storage.BlobRef, trashedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx, `
+		UPDATE pack_entries
+			SET trashed_at = ?
+			WHERE namespace = ? AND key = ? AND trashed_at IS NULL
+	`, trashedAt.UTC(), ref.Namespace, ref.Key)
+	return ErrPackIndex.Wrap(err)
+}
+
+// RestoreTrash unmarks the trashed entries in the namespace and returns their keys.
+func (db *packIndexDB) RestoreTrash(ctx context.Context, namespace []byte) (keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = withTx(ctx, db.GetDB(), func(tx tagsql.Tx) (err error) {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT DISTINCT key
+				FROM pack_entries
+				WHERE namespace = ? AND trashed_at IS NOT NULL
+		`, namespace)
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, rows.Close()) }()
+
+		for rows.Next() {
+			var key []byte
+			if err := rows.Scan(&key); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE pack_entries
+				SET trashed_at = NULL
+				WHERE namespace = ? AND trashed_at IS NOT NULL
+		`, namespace)
+		return err
+	})
+	if err != nil {
+		return nil, ErrPackIndex.Wrap(err)
+	}
+	return keys, nil
+}
+
+// EmptyTrash removes the entries in the namespace trashed before trashedBefore and returns them.
+func (db *packIndexDB) EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) (entries []packstore.Entry, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = withTx(ctx, db.GetDB(), func(tx tagsql.Tx) (err error) {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT `+packEntryColumns+`
+				FROM pack_entries
+				WHERE namespace = ? AND trashed_at < ?
+		`, namespace, trashedBefore.UTC())
+		if err != nil {
+			return err
+		}
+		entries, err = scanPackEntries(rows)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM pack_entries
+				WHERE namespace = ? AND trashed_at < ?
+		`, namespace, trashedBefore.UTC())
+		return err
+	})
+	if err != nil {
+		return nil, ErrPackIndex.Wrap(err)
+	}
+	return entries, nil
+}
+
+// List returns the entries which aren't trashed in the namespace after the cursor, ordered by key and format version.
+func (db *packIndexDB) List(ctx context.Context, namespace []byte, cursor packstore.ListCursor, limit int) (_ []packstore.Entry, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// a NULL key would never compare as smaller
+	key := cursor.Key
+	if key == nil {
+		key = []byte{}
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+packEntryColumns+`
+			FROM pack_entries
+			WHERE namespace = ? AND trashed_at IS NULL
+				AND (key > ? OR (key = ? AND format_version > ?))
+			ORDER BY key, format_version
+			LIMIT ?
+	`, namespace, key, key, int(cursor.FormatVersion), limit)
+	if err != nil {
+		return nil, ErrPackIndex.Wrap(err)
+	}
+	entries, err := scanPackEntries(rows)
+	return entries, ErrPackIndex.Wrap(err)
+}
+
+// ListPack returns the entries stored in the pack file.
+func (db *packIndexDB) ListPack(ctx context.Context, packID int64, limit int) (_ []packstore.Entry, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+packEntryColumns+`
+			FROM pack_entries
+			WHERE pack_id = ?
+			ORDER BY pack_offset
+			LIMIT ?
+	`, packID, limit)
+	if err != nil {
+		return nil, ErrPackIndex.Wrap(err)
+	}
+	entries, err := scanPackEntries(rows)
+	return entries, ErrPackIndex.Wrap(err)
+}
+
+// ListNamespaces returns all the namespaces which have entries.
+func (db *packIndexDB) ListNamespaces(ctx context.Context) (namespaces [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `SELECT DISTINCT namespace FROM pack_entries`)
+	if err != nil {
+		return nil, ErrPackIndex.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var namespace []byte
+		if err := rows.Scan(&namespace); err != nil {
+			return nil, ErrPackIndex.Wrap(err)
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, ErrPackIndex.Wrap(rows.Err())
+}
+
+// SpaceUsed returns the size of the entries which aren't trashed in the namespace, or in all namespaces when it's nil.
+func (db *packIndexDB) SpaceUsed(ctx context.Context, namespace []byte) (total int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if namespace == nil {
+		err = db.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(size), 0)
+				FROM pack_entries
+				WHERE trashed_at IS NULL
+		`).Scan(&total)
+	} else {
+		err = db.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(size), 0)
+				FROM pack_entries
+				WHERE namespace = ? AND trashed_at IS NULL
+		`, namespace).Scan(&total)
+	}
+	return total, ErrPackIndex.Wrap(err)
+}
+
+// SpaceUsedForTrash returns the size of the trashed entries.
+func (db *packIndexDB) SpaceUsedForTrash(ctx context.Context) (total int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(size), 0)
+			FROM pack_entries
+			WHERE trashed_at IS NOT NULL
+	`).Scan(&total)
+	return total, ErrPackIndex.Wrap(err)
+}
+
+// SpaceUsedByPack returns the size of the entries, including the trashed ones, in each pack file.
+func (db *packIndexDB) SpaceUsedByPack(ctx context.Context) (_ map[int64]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT pack_id, SUM(size)
+			FROM pack_entries
+			GROUP BY pack_id
+	`)
+	if err != nil {
+		return nil, ErrPackIndex.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	used := make(map[int64]int64)
+	for rows.Next() {
+		var packID, size int64
+		if err := rows.Scan(&packID, &size); err != nil {
+			return nil, ErrPackIndex.Wrap(err)
+		}
+		used[packID] = size
+	}
+	return used, ErrPackIndex.Wrap(rows.Err())
+}
+
+type packEntryScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPackEntry(row packEntryScanner) (entry packstore.Entry, err error) {
+	var formatVersion int
+	var trashedAt sql.NullTime
+	err = row.Scan(&entry.Ref.Namespace, &entry.Ref.Key, &formatVersion,
+		&entry.PackID, &entry.Offset, &entry.Size, &entry.ModTime, &trashedAt)
+	if err != nil {
+		return packstore.Entry{}, err
+	}
+	entry.FormatVersion = storage.FormatVersion(formatVersion)
+	if trashedAt.Valid {
+		entry.TrashedAt = &trashedAt.Time
+	}
+	return entry, nil
+}
+
+func scanPackEntries(rows *sql.Rows) (entries []packstore.Entry, err error) {
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		entry, err := scanPackEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
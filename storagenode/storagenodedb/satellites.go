@@ -59,12 +59,31 @@ func (db *satellitesDB) InitiateGracefulExit(ctx context.Context, satelliteID st
 	}))
 }
 
-// CancelGracefulExit delete an entry by satellite ID
+// CancelGracefulExit delete an entry by satellite ID and resets the satellite status
 func (db *satellitesDB) CancelGracefulExit(ctx context.Context, satelliteID storj.NodeID) (err error) {
 	defer mon.Task()(&ctx)(&err)
+	return ErrSatellitesDB.Wrap(withTx(ctx, db.GetDB(), func(tx tagsql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM satellite_exit_progress WHERE satellite_id = ?", satelliteID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE satellites SET status = ? WHERE node_id = ? AND status = ?", satellites.Normal, satelliteID, satellites.Exiting)
+		return err
+	}))
+}
 
-	_, err = db.ExecContext(ctx, "DELETE FROM satellite_exit_progress WHERE satellite_id = ?", satelliteID)
-	return ErrSatellitesDB.Wrap(err)
+// RestoreGracefulExit restores a cancelled graceful exit record with its progress
+func (db *satellitesDB) RestoreGracefulExit(ctx context.Context, exit satellites.ExitProgress) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return ErrSatellitesDB.Wrap(withTx(ctx, db.GetDB(), func(tx tagsql.Tx) error {
+		_, err := tx.ExecContext(ctx, "UPDATE satellites SET status = ? WHERE node_id = ?", exit.Status, exit.SatelliteID)
+		if err != nil {
+			return err
+		}
+		query := `INSERT OR REPLACE INTO satellite_exit_progress (satellite_id, initiated_at, finished_at, starting_disk_usage, bytes_deleted, completion_receipt) VALUES (?,?,?,?,?,?)`
+		_, err = tx.ExecContext(ctx, query, exit.SatelliteID, exit.InitiatedAt, exit.FinishedAt, exit.StartingDiskUsage, exit.BytesDeleted, exit.CompletionReceipt)
+		return err
+	}))
 }
 
 // UpdateGracefulExit increments the total bytes deleted during a graceful exit
@@ -114,3 +133,52 @@ func (db *satellitesDB) ListGracefulExits(ctx context.Context) (exitList []satel
 
 	return exitList, rows.Err()
 }
+
+// InitiateShrink updates the database to reflect the beginning of a shrink, replacing the previous shrink
+func (db *satellitesDB) InitiateShrink(ctx context.Context, satelliteID storj.NodeID, initiatedAt time.Time, targetBytes int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	query := `INSERT OR REPLACE INTO satellite_shrinks (satellite_id, target_bytes, initiated_at, bytes_deleted, finished_at) VALUES (?,?,?,0,NULL)`
+	_, err = db.ExecContext(ctx, query, satelliteID, targetBytes, initiatedAt.UTC())
+	return ErrSatellitesDB.Wrap(err)
+}
+
+// UpdateShrink increments the total bytes deleted during a shrink
+func (db *satellitesDB) UpdateShrink(ctx context.Context, satelliteID storj.NodeID, addToBytesDeleted int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	query := `UPDATE satellite_shrinks SET bytes_deleted = bytes_deleted + ? WHERE satellite_id = ?`
+	_, err = db.ExecContext(ctx, query, addToBytesDeleted, satelliteID)
+	return ErrSatellitesDB.Wrap(err)
+}
+
+// CompleteShrink updates the database when a shrink is finished
+func (db *satellitesDB) CompleteShrink(ctx context.Context, satelliteID storj.NodeID, finishedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	query := `UPDATE satellite_shrinks SET finished_at = ? WHERE satellite_id = ? AND finished_at IS NULL`
+	_, err = db.ExecContext(ctx, query, finishedAt.UTC(), satelliteID)
+	return ErrSatellitesDB.Wrap(err)
+}
+
+// ListShrinks lists the last shrink of every satellite
+func (db *satellitesDB) ListShrinks(ctx context.Context) (shrinkList []satellites.ShrinkProgress, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	query := `SELECT satellite_id, target_bytes, initiated_at, bytes_deleted, finished_at FROM satellite_shrinks`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, ErrSatellitesDB.Wrap(err)
+	}
+	defer func() {
+		err = ErrSatellitesDB.Wrap(errs.Combine(err, rows.Close()))
+	}()
+
+	for rows.Next() {
+		var shrink satellites.ShrinkProgress
+		err := rows.Scan(&shrink.SatelliteID, &shrink.TargetBytes, &shrink.InitiatedAt, &shrink.BytesDeleted, &shrink.FinishedAt)
+		if err != nil {
+			return nil, err
+		}
+		shrinkList = append(shrinkList, shrink)
+	}
+
+	return shrinkList, rows.Err()
+}
@@ -8,6 +8,44 @@ import "storj.io/storj/private/dbutil/dbschema"
 
 func Schema() map[string]*dbschema.Schema {
 	return map[string]*dbschema.Schema{
+		"api_keys": &dbschema.Schema{
+			Tables: []*dbschema.Table{
+				&dbschema.Table{
+					Name:       "api_keys",
+					PrimaryKey: []string{"id"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "created_at",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "hash",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "name",
+							Type:       "TEXT",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "scope",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+					},
+				},
+			},
+			Indexes: []*dbschema.Index{
+				&dbschema.Index{Name: "idx_api_keys_hash", Table: "api_keys", Columns: []string{"hash"}, Unique: false, Partial: ""},
+			},
+		},
 		"bandwidth": &dbschema.Schema{
 			Tables: []*dbschema.Table{
 				&dbschema.Table{
@@ -67,6 +105,36 @@ func Schema() map[string]*dbschema.Schema {
 				&dbschema.Index{Name: "idx_bandwidth_usage_satellite", Table: "bandwidth_usage", Columns: []string{"satellite_id"}, Unique: false, Partial: ""},
 			},
 		},
+		"corrupt_pieces": &dbschema.Schema{
+			Tables: []*dbschema.Table{
+				&dbschema.Table{
+					Name:       "corrupt_pieces",
+					PrimaryKey: []string{"piece_id", "satellite_id"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "detected_at",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "piece_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "reason",
+							Type:       "TEXT",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "satellite_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+					},
+				},
+			},
+		},
 		"heldamount": &dbschema.Schema{
 			Tables: []*dbschema.Table{
 				&dbschema.Table{
@@ -313,6 +381,59 @@ func Schema() map[string]*dbschema.Schema {
 				&dbschema.Index{Name: "idx_orders", Table: "unsent_order", Columns: []string{"satellite_id", "serial_number"}, Unique: false, Partial: ""},
 			},
 		},
+		"pack_index": &dbschema.Schema{
+			Tables: []*dbschema.Table{
+				&dbschema.Table{
+					Name:       "pack_entries",
+					PrimaryKey: []string{"format_version", "key", "namespace"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "format_version",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "key",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "mod_time",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "namespace",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "pack_id",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "pack_offset",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "size",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "trashed_at",
+							Type:       "TIMESTAMP",
+							IsNullable: true,
+						},
+					},
+				},
+			},
+			Indexes: []*dbschema.Index{
+				&dbschema.Index{Name: "idx_pack_entries_pack_id", Table: "pack_entries", Columns: []string{"pack_id"}, Unique: false, Partial: ""},
+			},
+		},
 		"piece_expiration": &dbschema.Schema{
 			Tables: []*dbschema.Table{
 				&dbschema.Table{
@@ -607,6 +728,37 @@ func Schema() map[string]*dbschema.Schema {
 						},
 					},
 				},
+				&dbschema.Table{
+					Name:       "satellite_shrinks",
+					PrimaryKey: []string{"satellite_id"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "bytes_deleted",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "finished_at",
+							Type:       "TIMESTAMP",
+							IsNullable: true,
+						},
+						&dbschema.Column{
+							Name:       "initiated_at",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "satellite_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "target_bytes",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+					},
+				},
 				&dbschema.Table{
 					Name:       "satellites",
 					PrimaryKey: []string{"node_id"},
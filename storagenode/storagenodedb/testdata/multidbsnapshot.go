@@ -54,6 +54,10 @@ var States = MultiDBStates{
 		&v37,
 		&v38,
 		&v39,
+		&v40,
+		&v41,
+		&v42,
+		&v43,
 	},
 }
 
@@ -0,0 +1,39 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testdata
+
+import "storj.io/storj/storagenode/storagenodedb"
+
+var v40 = MultiDBState{
+	Version: 40,
+	DBStates: DBStates{
+		storagenodedb.UsedSerialsDBName:     v28.DBStates[storagenodedb.UsedSerialsDBName],
+		storagenodedb.StorageUsageDBName:    v28.DBStates[storagenodedb.StorageUsageDBName],
+		storagenodedb.ReputationDBName:      v39.DBStates[storagenodedb.ReputationDBName],
+		storagenodedb.PieceSpaceUsedDBName:  v31.DBStates[storagenodedb.PieceSpaceUsedDBName],
+		storagenodedb.PieceInfoDBName:       v28.DBStates[storagenodedb.PieceInfoDBName],
+		storagenodedb.PieceExpirationDBName: v28.DBStates[storagenodedb.PieceExpirationDBName],
+		storagenodedb.OrdersDBName:          v28.DBStates[storagenodedb.OrdersDBName],
+		storagenodedb.BandwidthDBName:       v28.DBStates[storagenodedb.BandwidthDBName],
+		storagenodedb.SatellitesDBName:      v28.DBStates[storagenodedb.SatellitesDBName],
+		storagenodedb.DeprecatedInfoDBName:  v28.DBStates[storagenodedb.DeprecatedInfoDBName],
+		storagenodedb.NotificationsDBName:   v28.DBStates[storagenodedb.NotificationsDBName],
+		storagenodedb.HeldAmountDBName:      v37.DBStates[storagenodedb.HeldAmountDBName],
+		storagenodedb.PricingDBName:         v35.DBStates[storagenodedb.PricingDBName],
+		storagenodedb.CorruptPiecesDBName: &DBState{
+			SQL: `
+				-- table to hold the pieces which failed the integrity check
+				CREATE TABLE corrupt_pieces (
+					satellite_id BLOB NOT NULL,
+					piece_id BLOB NOT NULL,
+					reason TEXT NOT NULL,
+					detected_at TIMESTAMP NOT NULL,
+					PRIMARY KEY ( satellite_id, piece_id )
+				);`,
+			NewData: `
+				INSERT INTO corrupt_pieces VALUES(X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3000',X'd5e757fd8d207d1c46583fb58330f803dc961b71147308ff75ff1e72a0df6b0b','hash doesn''t match the uplink piece hash','2020-05-20 10:00:00+00:00');
+			`,
+		},
+	},
+}
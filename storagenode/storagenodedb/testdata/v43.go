@@ -0,0 +1,75 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testdata
+
+import "storj.io/storj/storagenode/storagenodedb"
+
+var v43 = MultiDBState{
+	Version: 43,
+	DBStates: DBStates{
+		storagenodedb.UsedSerialsDBName:     v28.DBStates[storagenodedb.UsedSerialsDBName],
+		storagenodedb.StorageUsageDBName:    v28.DBStates[storagenodedb.StorageUsageDBName],
+		storagenodedb.ReputationDBName:      v39.DBStates[storagenodedb.ReputationDBName],
+		storagenodedb.PieceSpaceUsedDBName:  v31.DBStates[storagenodedb.PieceSpaceUsedDBName],
+		storagenodedb.PieceInfoDBName:       v28.DBStates[storagenodedb.PieceInfoDBName],
+		storagenodedb.PieceExpirationDBName: v28.DBStates[storagenodedb.PieceExpirationDBName],
+		storagenodedb.OrdersDBName:          v28.DBStates[storagenodedb.OrdersDBName],
+		storagenodedb.BandwidthDBName:       v28.DBStates[storagenodedb.BandwidthDBName],
+		storagenodedb.SatellitesDBName: &DBState{
+			SQL: `
+				CREATE TABLE satellites (
+					node_id BLOB NOT NULL,
+					added_at TIMESTAMP NOT NULL,
+					status INTEGER NOT NULL,
+					PRIMARY KEY (node_id)
+				);
+
+				CREATE TABLE satellite_exit_progress (
+					satellite_id BLOB NOT NULL,
+					initiated_at TIMESTAMP,
+					finished_at TIMESTAMP,
+					starting_disk_usage INTEGER NOT NULL,
+					bytes_deleted INTEGER NOT NULL,
+					completion_receipt BLOB,
+					PRIMARY KEY (satellite_id)
+				);
+
+				-- table to hold the partial graceful exits of the node
+				CREATE TABLE satellite_shrinks (
+					satellite_id BLOB NOT NULL,
+					target_bytes INTEGER NOT NULL,
+					initiated_at TIMESTAMP NOT NULL,
+					bytes_deleted INTEGER NOT NULL DEFAULT 0,
+					finished_at TIMESTAMP,
+					PRIMARY KEY ( satellite_id )
+				);
+
+				INSERT INTO satellites VALUES(X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3000','2019-09-10 20:00:00+00:00', 0);
+				INSERT INTO satellite_exit_progress VALUES(X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3000','2019-09-10 20:00:00+00:00', null, 100, 0, null);`,
+			NewData: `
+				INSERT INTO satellite_shrinks VALUES(X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3000',1000000,'2020-06-10 10:00:00+00:00',2048,NULL);
+			`,
+		},
+		storagenodedb.DeprecatedInfoDBName: v28.DBStates[storagenodedb.DeprecatedInfoDBName],
+		storagenodedb.NotificationsDBName:  v28.DBStates[storagenodedb.NotificationsDBName],
+		storagenodedb.HeldAmountDBName:     v37.DBStates[storagenodedb.HeldAmountDBName],
+		storagenodedb.PricingDBName:        v35.DBStates[storagenodedb.PricingDBName],
+		storagenodedb.CorruptPiecesDBName:  v41.DBStates[storagenodedb.CorruptPiecesDBName],
+		storagenodedb.PackIndexDBName:      v42.DBStates[storagenodedb.PackIndexDBName],
+		storagenodedb.APIKeysDBName: &DBState{
+			SQL: `
+				-- table to hold the hashes of the console api keys
+				CREATE TABLE api_keys (
+					id BLOB NOT NULL,
+					name TEXT NOT NULL,
+					scope INTEGER NOT NULL,
+					hash BLOB NOT NULL,
+					created_at TIMESTAMP NOT NULL,
+					PRIMARY KEY ( id )
+				);
+				CREATE UNIQUE INDEX idx_api_keys_hash ON api_keys(hash);
+				INSERT INTO api_keys VALUES(X'0ed28abb2813e184a1e98b0f6605c491','multinode',1,X'd5e757fd8d207d1c46583fb58330f803dc961b71147308ff75ff1e72a0df6b0b','2020-06-01 10:00:00+00:00');`,
+		},
+	},
+}
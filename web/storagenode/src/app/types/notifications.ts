@@ -65,6 +65,9 @@ export class Notification {
             case NotificationTypes.Suspension:
                 this.icon = NotificationIcon.SUSPENDED;
                 break;
+            case NotificationTypes.GracefulExitFailed:
+                this.icon = NotificationIcon.FAIL;
+                break;
             default:
                 this.icon = NotificationIcon.INFO;
         }
@@ -80,6 +83,8 @@ export enum NotificationTypes {
     UptimeCheckFailure = 2,
     Disqualification = 3,
     Suspension = 4,
+    GracefulExitCompleted = 5,
+    GracefulExitFailed = 6,
 }
 
 /**